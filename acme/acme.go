@@ -0,0 +1,597 @@
+// Package acme issues certificates from an ACME certificate authority such as Let's Encrypt
+package acme
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// LetsEncrypt is the directory of the Let's Encrypt production certificate authority
+const LetsEncrypt = "https://acme-v02.api.letsencrypt.org/directory"
+
+// LetsEncryptStaging is the directory of the Let's Encrypt staging certificate authority, which
+// issues untrusted certificates with much higher rate limits
+const LetsEncryptStaging = "https://acme-staging-v02.api.letsencrypt.org/directory"
+
+// Solver proves control of a domain for one type of challenge
+type Solver interface {
+	// Type is the challenge type the solver answers, http-01 or dns-01
+	Type() string
+
+	// Present makes the key authorization of a challenge available for the authority to check
+	Present(domain, token, keyAuth string) error
+
+	// CleanUp removes what Present made available
+	CleanUp(domain, token, keyAuth string) error
+}
+
+// Certificate is an issued certificate along with its intermediate chain and private key, all PEM encoded
+type Certificate struct {
+	Certificate string
+	Chain       string
+	PrivateKey  string
+	Expiration  time.Time
+}
+
+// Client talks to an ACME certificate authority on behalf of one account
+type Client struct {
+	Directory string
+	Key       *ecdsa.PrivateKey
+
+	// PollInterval and PollTimeout control how authorizations and orders are waited on
+	PollInterval time.Duration
+	PollTimeout  time.Duration
+
+	HTTP *http.Client
+
+	account string
+	nonce   string
+	urls    directory
+}
+
+type directory struct {
+	NewNonce   string `json:"newNonce"`
+	NewAccount string `json:"newAccount"`
+	NewOrder   string `json:"newOrder"`
+}
+
+type problem struct {
+	Type   string `json:"type"`
+	Detail string `json:"detail"`
+}
+
+type authorization struct {
+	Status     string `json:"status"`
+	Identifier struct {
+		Value string `json:"value"`
+	} `json:"identifier"`
+	Challenges []challenge `json:"challenges"`
+}
+
+type challenge struct {
+	Type   string   `json:"type"`
+	URL    string   `json:"url"`
+	Token  string   `json:"token"`
+	Status string   `json:"status"`
+	Error  *problem `json:"error,omitempty"`
+}
+
+type order struct {
+	Status         string   `json:"status"`
+	Authorizations []string `json:"authorizations"`
+	Finalize       string   `json:"finalize"`
+	Certificate    string   `json:"certificate"`
+	Error          *problem `json:"error,omitempty"`
+}
+
+// NewClient returns a client for the authority at directory that signs its requests with key
+func NewClient(directory string, key *ecdsa.PrivateKey) *Client {
+	return &Client{
+		Directory:    directory,
+		Key:          key,
+		PollInterval: 3 * time.Second,
+		PollTimeout:  5 * time.Minute,
+		HTTP:         http.DefaultClient,
+	}
+}
+
+// GenerateKey creates a new account key
+func GenerateKey() (*ecdsa.PrivateKey, error) {
+	return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+}
+
+// EncodeKey returns an account key PEM encoded
+func EncodeKey(key *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+}
+
+// DecodeKey reads a PEM encoded account key
+func DecodeKey(data []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("invalid account key")
+	}
+
+	return x509.ParseECPrivateKey(block.Bytes)
+}
+
+// DNSRecord returns the value of the TXT record that answers a dns-01 challenge. The record is
+// named _acme-challenge.<domain>.
+func DNSRecord(keyAuth string) string {
+	sum := sha256.Sum256([]byte(keyAuth))
+	return encode(sum[:])
+}
+
+// Register creates the account of the client key or finds the existing one. The terms of
+// service of the authority are agreed to.
+func (c *Client) Register(email string) error {
+	if err := c.discover(); err != nil {
+		return err
+	}
+
+	req := map[string]interface{}{
+		"termsOfServiceAgreed": true,
+	}
+
+	if email != "" {
+		req["contact"] = []string{fmt.Sprintf("mailto:%s", email)}
+	}
+
+	res, err := c.post(c.urls.NewAccount, req, nil)
+	if err != nil {
+		return err
+	}
+
+	res.Body.Close()
+
+	c.account = res.Header.Get("Location")
+
+	if c.account == "" {
+		return fmt.Errorf("acme: no account url returned")
+	}
+
+	return nil
+}
+
+// Obtain orders a certificate for domains, proving control of each of them with solver. The
+// first domain is the common name of the certificate.
+func (c *Client) Obtain(domains []string, solver Solver) (*Certificate, error) {
+	return c.ObtainWith(domains, func(domain string) Solver { return solver })
+}
+
+// ObtainWith orders a certificate for domains, proving control of each of them with the solver
+// that solvers returns for it. The first domain is the common name of the certificate.
+func (c *Client) ObtainWith(domains []string, solvers func(domain string) Solver) (*Certificate, error) {
+	if len(domains) < 1 {
+		return nil, fmt.Errorf("must specify at least one domain")
+	}
+
+	if c.account == "" {
+		if err := c.Register(""); err != nil {
+			return nil, err
+		}
+	}
+
+	identifiers := []map[string]string{}
+
+	for _, d := range domains {
+		identifiers = append(identifiers, map[string]string{"type": "dns", "value": d})
+	}
+
+	var o order
+
+	res, err := c.post(c.urls.NewOrder, map[string]interface{}{"identifiers": identifiers}, &o)
+	if err != nil {
+		return nil, err
+	}
+
+	orderURL := res.Header.Get("Location")
+
+	for _, authz := range o.Authorizations {
+		if err := c.authorize(authz, solvers); err != nil {
+			return nil, err
+		}
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: domains[0]},
+		DNSNames: domains,
+	}, key)
+	if err != nil {
+		return nil, err
+	}
+
+	// the authority only accepts the csr once it has moved the order to ready
+	if err := c.waitOrder(orderURL, &o, "ready"); err != nil {
+		return nil, err
+	}
+
+	if o.Status == "ready" {
+		if _, err := c.post(o.Finalize, map[string]string{"csr": encode(csr)}, &o); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.waitOrder(orderURL, &o, "valid"); err != nil {
+		return nil, err
+	}
+
+	res, err = c.post(o.Certificate, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := splitChain(data)
+	if err != nil {
+		return nil, err
+	}
+
+	cert.PrivateKey = string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+
+	return cert, nil
+}
+
+// authorize answers the challenge of an authorization with the solver for its domain and waits
+// for the authority to check it
+func (c *Client) authorize(url string, solvers func(domain string) Solver) error {
+	var a authorization
+
+	if _, err := c.post(url, nil, &a); err != nil {
+		return err
+	}
+
+	if a.Status == "valid" {
+		return nil
+	}
+
+	solver := solvers(a.Identifier.Value)
+
+	var ch *challenge
+
+	for i := range a.Challenges {
+		if a.Challenges[i].Type == solver.Type() {
+			ch = &a.Challenges[i]
+			break
+		}
+	}
+
+	if ch == nil {
+		return fmt.Errorf("acme: no %s challenge offered for %s", solver.Type(), a.Identifier.Value)
+	}
+
+	keyAuth := c.keyAuthorization(ch.Token)
+
+	if err := solver.Present(a.Identifier.Value, ch.Token, keyAuth); err != nil {
+		return err
+	}
+
+	defer solver.CleanUp(a.Identifier.Value, ch.Token, keyAuth)
+
+	var answered challenge
+
+	if _, err := c.post(ch.URL, struct{}{}, &answered); err != nil {
+		return err
+	}
+
+	return c.poll(url, &a, func() (bool, error) {
+		switch a.Status {
+		case "valid":
+			return true, nil
+		case "invalid":
+			for _, ch := range a.Challenges {
+				if ch.Type == solver.Type() && ch.Error != nil {
+					return false, problemError(ch.Error, "")
+				}
+			}
+			return false, fmt.Errorf("acme: authorization of %s is invalid", a.Identifier.Value)
+		}
+		return false, nil
+	})
+}
+
+// waitOrder polls an order until it reaches status or is already valid
+func (c *Client) waitOrder(url string, o *order, status string) error {
+	return c.poll(url, o, func() (bool, error) {
+		switch o.Status {
+		case status, "valid":
+			return true, nil
+		case "invalid":
+			return false, problemError(o.Error, "order is invalid")
+		}
+		return false, nil
+	})
+}
+
+// poll fetches url into out until done returns true or an error
+func (c *Client) poll(url string, out interface{}, done func() (bool, error)) error {
+	deadline := time.Now().Add(c.PollTimeout)
+
+	for {
+		if ok, err := done(); ok || err != nil {
+			return err
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("acme: timeout waiting for %s", url)
+		}
+
+		time.Sleep(c.PollInterval)
+
+		if _, err := c.post(url, nil, out); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *Client) discover() error {
+	if c.urls.NewNonce != "" {
+		return nil
+	}
+
+	res, err := c.HTTP.Get(c.Directory)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode >= 400 {
+		return responseError(res)
+	}
+
+	return json.NewDecoder(res.Body).Decode(&c.urls)
+}
+
+func (c *Client) fetchNonce() (string, error) {
+	if c.nonce != "" {
+		nonce := c.nonce
+		c.nonce = ""
+		return nonce, nil
+	}
+
+	res, err := c.HTTP.Head(c.urls.NewNonce)
+	if err != nil {
+		return "", err
+	}
+
+	res.Body.Close()
+
+	nonce := res.Header.Get("Replay-Nonce")
+
+	if nonce == "" {
+		return "", fmt.Errorf("acme: no nonce returned")
+	}
+
+	return nonce, nil
+}
+
+// post sends a signed request to url and decodes the response into out when it is not nil. A
+// nil payload is a POST-as-GET request. A response that is not decoded must be closed.
+func (c *Client) post(url string, payload interface{}, out interface{}) (*http.Response, error) {
+	if err := c.discover(); err != nil {
+		return nil, err
+	}
+
+	// a nonce can be rejected as stale, in which case the authority sends a fresh one
+	for attempt := 0; ; attempt++ {
+		body, err := c.sign(url, payload)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := c.HTTP.Post(url, "application/jose+json", bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+
+		c.nonce = res.Header.Get("Replay-Nonce")
+
+		if res.StatusCode >= 400 {
+			err := responseError(res)
+
+			if p, ok := err.(*problemErr); ok && p.Type == "urn:ietf:params:acme:error:badNonce" && attempt < 2 {
+				continue
+			}
+
+			return nil, err
+		}
+
+		if out == nil {
+			return res, nil
+		}
+
+		defer res.Body.Close()
+
+		if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+			return nil, err
+		}
+
+		return res, nil
+	}
+}
+
+// sign wraps payload in a flattened JWS signed with the account key
+func (c *Client) sign(url string, payload interface{}) ([]byte, error) {
+	nonce, err := c.fetchNonce()
+	if err != nil {
+		return nil, err
+	}
+
+	protected := map[string]interface{}{
+		"alg":   "ES256",
+		"nonce": nonce,
+		"url":   url,
+	}
+
+	if c.account != "" {
+		protected["kid"] = c.account
+	} else {
+		protected["jwk"] = c.jwk()
+	}
+
+	ph, err := json.Marshal(protected)
+	if err != nil {
+		return nil, err
+	}
+
+	pl := []byte{}
+
+	if payload != nil {
+		pl, err = json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	input := fmt.Sprintf("%s.%s", encode(ph), encode(pl))
+	digest := sha256.Sum256([]byte(input))
+
+	r, s, err := ecdsa.Sign(rand.Reader, c.Key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	sig := append(pad(r, 32), pad(s, 32)...)
+
+	return json.Marshal(map[string]string{
+		"protected": encode(ph),
+		"payload":   encode(pl),
+		"signature": encode(sig),
+	})
+}
+
+func (c *Client) jwk() map[string]string {
+	return map[string]string{
+		"crv": "P-256",
+		"kty": "EC",
+		"x":   encode(pad(c.Key.X, 32)),
+		"y":   encode(pad(c.Key.Y, 32)),
+	}
+}
+
+// keyAuthorization joins a challenge token with the thumbprint of the account key
+func (c *Client) keyAuthorization(token string) string {
+	jwk := c.jwk()
+
+	// the thumbprint is taken over the required members in lexicographic order
+	data := fmt.Sprintf(`{"crv":%q,"kty":%q,"x":%q,"y":%q}`, jwk["crv"], jwk["kty"], jwk["x"], jwk["y"])
+
+	thumb := sha256.Sum256([]byte(data))
+
+	return fmt.Sprintf("%s.%s", token, encode(thumb[:]))
+}
+
+// splitChain separates the leaf certificate from its intermediates
+func splitChain(data []byte) (*Certificate, error) {
+	cert := &Certificate{}
+	chain := []string{}
+
+	for {
+		var block *pem.Block
+
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		if cert.Certificate == "" {
+			c, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+
+			cert.Certificate = string(pem.EncodeToMemory(block))
+			cert.Expiration = c.NotAfter
+			continue
+		}
+
+		chain = append(chain, string(pem.EncodeToMemory(block)))
+	}
+
+	if cert.Certificate == "" {
+		return nil, fmt.Errorf("acme: no certificate returned")
+	}
+
+	cert.Chain = strings.Join(chain, "")
+
+	return cert, nil
+}
+
+type problemErr struct {
+	problem
+}
+
+func (p *problemErr) Error() string {
+	return fmt.Sprintf("acme: %s", p.Detail)
+}
+
+func problemError(p *problem, def string) error {
+	if p == nil {
+		return fmt.Errorf("acme: %s", def)
+	}
+
+	return &problemErr{*p}
+}
+
+func responseError(res *http.Response) error {
+	defer res.Body.Close()
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	var p problem
+
+	if err := json.Unmarshal(data, &p); err != nil || p.Detail == "" {
+		return fmt.Errorf("acme: response status %d", res.StatusCode)
+	}
+
+	return &problemErr{p}
+}
+
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func pad(n *big.Int, size int) []byte {
+	b := n.Bytes()
+
+	if len(b) >= size {
+		return b
+	}
+
+	return append(make([]byte, size-len(b)), b...)
+}
@@ -0,0 +1,472 @@
+package acme_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/convox/rack/acme"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAuthority is a minimal ACME server that checks request signatures and key authorizations
+type fakeAuthority struct {
+	t      *testing.T
+	server *httptest.Server
+	solver *fakeSolver
+	dns    *fakeSolver
+
+	lock      sync.Mutex
+	key       *ecdsa.PublicKey
+	nonces    int
+	badNonce  bool
+	validated map[string]bool
+	domains   []string
+	issued    []byte
+	caKey     *rsa.PrivateKey
+	ca        *x509.Certificate
+	caPEM     []byte
+
+	// pending is how many polls of a fully authorized order still report it pending
+	pending int
+	invalid bool
+}
+
+type fakeSolver struct {
+	typ       string
+	presented map[string]string
+	cleaned   []string
+}
+
+func (s *fakeSolver) Type() string { return s.typ }
+
+func (s *fakeSolver) Present(domain, token, keyAuth string) error {
+	s.presented[token] = keyAuth
+	return nil
+}
+
+func (s *fakeSolver) CleanUp(domain, token, keyAuth string) error {
+	s.cleaned = append(s.cleaned, domain)
+	return nil
+}
+
+func newFakeAuthority(t *testing.T) *fakeAuthority {
+	caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "fake ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &caKey.PublicKey, caKey)
+	require.Nil(t, err)
+
+	ca, err := x509.ParseCertificate(der)
+	require.Nil(t, err)
+
+	fa := &fakeAuthority{
+		t:         t,
+		solver:    &fakeSolver{typ: "http-01", presented: map[string]string{}},
+		dns:       &fakeSolver{typ: "dns-01", presented: map[string]string{}},
+		badNonce:  true,
+		validated: map[string]bool{},
+		caKey:     caKey,
+		ca:        ca,
+		caPEM:     pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+
+	fa.server = httptest.NewTLSServer(http.HandlerFunc(fa.handle))
+
+	return fa
+}
+
+func (fa *fakeAuthority) url(path string) string {
+	return fa.server.URL + path
+}
+
+func (fa *fakeAuthority) handle(w http.ResponseWriter, r *http.Request) {
+	fa.lock.Lock()
+	defer fa.lock.Unlock()
+
+	fa.nonces++
+	w.Header().Set("Replay-Nonce", fmt.Sprintf("nonce-%d", fa.nonces))
+
+	if r.Method == "GET" && r.URL.Path == "/directory" {
+		json.NewEncoder(w).Encode(map[string]string{
+			"newNonce":   fa.url("/nonce"),
+			"newAccount": fa.url("/account"),
+			"newOrder":   fa.url("/order"),
+		})
+		return
+	}
+
+	if r.Method == "HEAD" {
+		return
+	}
+
+	payload, err := fa.verify(r)
+	if err != nil {
+		fa.t.Errorf("invalid request to %s: %s", r.URL.Path, err)
+		w.WriteHeader(400)
+		return
+	}
+
+	// the first signed request is rejected so that the client has to retry with a fresh nonce
+	if fa.badNonce {
+		fa.badNonce = false
+		w.WriteHeader(400)
+		fmt.Fprintf(w, `{"type":"urn:ietf:params:acme:error:badNonce","detail":"bad nonce"}`)
+		return
+	}
+
+	switch {
+	case r.URL.Path == "/account":
+		w.Header().Set("Location", fa.url("/accounts/1"))
+		w.WriteHeader(201)
+		fmt.Fprintf(w, `{"status":"valid"}`)
+	case r.URL.Path == "/order":
+		var req struct {
+			Identifiers []struct {
+				Value string `json:"value"`
+			} `json:"identifiers"`
+		}
+		require.Nil(fa.t, json.Unmarshal(payload, &req))
+
+		authz := []string{}
+
+		for _, id := range req.Identifiers {
+			authz = append(authz, fa.url("/authz/"+id.Value))
+			fa.domains = append(fa.domains, id.Value)
+		}
+
+		w.Header().Set("Location", fa.url("/orders/1"))
+		w.WriteHeader(201)
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "pending", "authorizations": authz, "finalize": fa.url("/finalize")})
+	case strings.HasPrefix(r.URL.Path, "/authz/"):
+		domain := strings.TrimPrefix(r.URL.Path, "/authz/")
+
+		status := "pending"
+
+		if fa.validated[domain] {
+			status = "valid"
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":     status,
+			"identifier": map[string]string{"type": "dns", "value": domain},
+			"challenges": []map[string]string{
+				{"type": "dns-01", "url": fa.url("/challenge/dns/" + domain), "token": "dns-" + domain},
+				{"type": "http-01", "url": fa.url("/challenge/http/" + domain), "token": "http-" + domain},
+			},
+		})
+	case strings.HasPrefix(r.URL.Path, "/challenge/dns/"):
+		domain := strings.TrimPrefix(r.URL.Path, "/challenge/dns/")
+
+		token := "dns-" + domain
+
+		assert.Equal(fa.t, token+"."+fa.thumbprint(), fa.dns.presented[token])
+
+		fa.validated[domain] = true
+
+		json.NewEncoder(w).Encode(map[string]string{"type": "dns-01", "status": "processing"})
+	case strings.HasPrefix(r.URL.Path, "/challenge/http/"):
+		domain := strings.TrimPrefix(r.URL.Path, "/challenge/http/")
+
+		token := "http-" + domain
+
+		assert.Equal(fa.t, token+"."+fa.thumbprint(), fa.solver.presented[token])
+
+		fa.validated[domain] = true
+
+		json.NewEncoder(w).Encode(map[string]string{"type": "http-01", "status": "processing"})
+	case r.URL.Path == "/finalize":
+		if fa.orderStatus() != "ready" {
+			w.WriteHeader(403)
+			fmt.Fprintf(w, `{"type":"urn:ietf:params:acme:error:orderNotReady","detail":"order is not ready"}`)
+			return
+		}
+
+		var req struct {
+			CSR string `json:"csr"`
+		}
+		require.Nil(fa.t, json.Unmarshal(payload, &req))
+
+		der, err := base64.RawURLEncoding.DecodeString(req.CSR)
+		require.Nil(fa.t, err)
+
+		csr, err := x509.ParseCertificateRequest(der)
+		require.Nil(fa.t, err)
+
+		tmpl := &x509.Certificate{
+			SerialNumber: big.NewInt(2),
+			Subject:      csr.Subject,
+			DNSNames:     csr.DNSNames,
+			NotBefore:    time.Now(),
+			NotAfter:     time.Now().Add(90 * 24 * time.Hour),
+		}
+
+		fa.issued, err = x509.CreateCertificate(rand.Reader, tmpl, fa.ca, csr.PublicKey, fa.caKey)
+		require.Nil(fa.t, err)
+
+		json.NewEncoder(w).Encode(map[string]string{"status": "processing"})
+	case r.URL.Path == "/orders/1":
+		status := fa.orderStatus()
+
+		if status == "pending" && fa.authorized() {
+			fa.pending--
+		}
+
+		o := map[string]interface{}{"status": status, "finalize": fa.url("/finalize")}
+
+		switch status {
+		case "valid":
+			o["certificate"] = fa.url("/certificate")
+		case "invalid":
+			o["error"] = map[string]string{"type": "urn:ietf:params:acme:error:rejectedIdentifier", "detail": "domain is blocked"}
+		}
+
+		json.NewEncoder(w).Encode(o)
+	case r.URL.Path == "/certificate":
+		w.Header().Set("Content-Type", "application/pem-certificate-chain")
+		w.Write(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: fa.issued}))
+		w.Write(fa.caPEM)
+	default:
+		fa.t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(404)
+	}
+}
+
+func (fa *fakeAuthority) authorized() bool {
+	for _, d := range fa.domains {
+		if !fa.validated[d] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (fa *fakeAuthority) orderStatus() string {
+	switch {
+	case fa.invalid:
+		return "invalid"
+	case fa.issued != nil:
+		return "valid"
+	case fa.authorized() && fa.pending <= 0:
+		return "ready"
+	}
+
+	return "pending"
+}
+
+// verify checks the signature of a request and returns its payload
+func (fa *fakeAuthority) verify(r *http.Request) ([]byte, error) {
+	var jws struct {
+		Protected string `json:"protected"`
+		Payload   string `json:"payload"`
+		Signature string `json:"signature"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&jws); err != nil {
+		return nil, err
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(jws.Protected)
+	if err != nil {
+		return nil, err
+	}
+
+	var protected struct {
+		Alg   string            `json:"alg"`
+		Nonce string            `json:"nonce"`
+		URL   string            `json:"url"`
+		Kid   string            `json:"kid"`
+		JWK   map[string]string `json:"jwk"`
+	}
+
+	if err := json.Unmarshal(data, &protected); err != nil {
+		return nil, err
+	}
+
+	if protected.Nonce == "" || protected.URL != fa.url(r.URL.Path) {
+		return nil, fmt.Errorf("invalid nonce or url")
+	}
+
+	if protected.JWK != nil {
+		x, _ := base64.RawURLEncoding.DecodeString(protected.JWK["x"])
+		y, _ := base64.RawURLEncoding.DecodeString(protected.JWK["y"])
+
+		fa.key = &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+	} else if protected.Kid != fa.url("/accounts/1") {
+		return nil, fmt.Errorf("unknown account: %s", protected.Kid)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(jws.Signature)
+	if err != nil || len(sig) != 64 {
+		return nil, fmt.Errorf("invalid signature")
+	}
+
+	digest := sha256.Sum256([]byte(jws.Protected + "." + jws.Payload))
+
+	if !ecdsa.Verify(fa.key, digest[:], new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])) {
+		return nil, fmt.Errorf("signature does not verify")
+	}
+
+	return base64.RawURLEncoding.DecodeString(jws.Payload)
+}
+
+func (fa *fakeAuthority) thumbprint() string {
+	data := fmt.Sprintf(`{"crv":"P-256","kty":"EC","x":%q,"y":%q}`,
+		base64.RawURLEncoding.EncodeToString(padded(fa.key.X)),
+		base64.RawURLEncoding.EncodeToString(padded(fa.key.Y)))
+
+	sum := sha256.Sum256([]byte(data))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func padded(n *big.Int) []byte {
+	b := n.Bytes()
+	return append(make([]byte, 32-len(b)), b...)
+}
+
+func TestObtain(t *testing.T) {
+	fa := newFakeAuthority(t)
+	defer fa.server.Close()
+
+	key, err := acme.GenerateKey()
+	require.Nil(t, err)
+
+	c := acme.NewClient(fa.url("/directory"), key)
+	c.PollInterval = 10 * time.Millisecond
+	c.HTTP = &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+
+	cert, err := c.Obtain([]string{"example.org", "www.example.org"}, fa.solver)
+	require.Nil(t, err)
+
+	block, _ := pem.Decode([]byte(cert.Certificate))
+	require.NotNil(t, block)
+
+	leaf, err := x509.ParseCertificate(block.Bytes)
+	require.Nil(t, err)
+
+	assert.Equal(t, "example.org", leaf.Subject.CommonName)
+	assert.Equal(t, []string{"example.org", "www.example.org"}, leaf.DNSNames)
+	assert.Equal(t, leaf.NotAfter, cert.Expiration)
+	assert.Equal(t, string(fa.caPEM), cert.Chain)
+	assert.Equal(t, []string{"example.org", "www.example.org"}, fa.solver.cleaned)
+
+	keyBlock, _ := pem.Decode([]byte(cert.PrivateKey))
+	require.NotNil(t, keyBlock)
+
+	pk, err := x509.ParsePKCS1PrivateKey(keyBlock.Bytes)
+	require.Nil(t, err)
+	assert.Equal(t, 0, pk.PublicKey.N.Cmp(leaf.PublicKey.(*rsa.PublicKey).N))
+}
+
+func TestObtainWaitsForReady(t *testing.T) {
+	fa := newFakeAuthority(t)
+	defer fa.server.Close()
+
+	fa.pending = 2
+
+	key, err := acme.GenerateKey()
+	require.Nil(t, err)
+
+	c := acme.NewClient(fa.url("/directory"), key)
+	c.PollInterval = 10 * time.Millisecond
+	c.HTTP = &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+
+	cert, err := c.Obtain([]string{"example.org"}, fa.solver)
+	require.Nil(t, err)
+
+	assert.Equal(t, 0, fa.pending)
+	assert.NotEmpty(t, cert.Certificate)
+}
+
+func TestObtainInvalidOrder(t *testing.T) {
+	fa := newFakeAuthority(t)
+	defer fa.server.Close()
+
+	fa.invalid = true
+
+	key, err := acme.GenerateKey()
+	require.Nil(t, err)
+
+	c := acme.NewClient(fa.url("/directory"), key)
+	c.PollInterval = 10 * time.Millisecond
+	c.HTTP = &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+
+	_, err = c.Obtain([]string{"example.org"}, fa.solver)
+	assert.EqualError(t, err, "acme: domain is blocked")
+	assert.Nil(t, fa.issued)
+}
+
+func TestObtainWith(t *testing.T) {
+	fa := newFakeAuthority(t)
+	defer fa.server.Close()
+
+	key, err := acme.GenerateKey()
+	require.Nil(t, err)
+
+	c := acme.NewClient(fa.url("/directory"), key)
+	c.PollInterval = 10 * time.Millisecond
+	c.HTTP = &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+
+	solvers := func(domain string) acme.Solver {
+		if domain == "www.example.org" {
+			return fa.dns
+		}
+
+		return fa.solver
+	}
+
+	_, err = c.ObtainWith([]string{"example.org", "www.example.org"}, solvers)
+	require.Nil(t, err)
+
+	assert.Equal(t, []string{"example.org"}, fa.solver.cleaned)
+	assert.Equal(t, []string{"www.example.org"}, fa.dns.cleaned)
+}
+
+func TestKeyEncoding(t *testing.T) {
+	key, err := acme.GenerateKey()
+	require.Nil(t, err)
+
+	data, err := acme.EncodeKey(key)
+	require.Nil(t, err)
+
+	decoded, err := acme.DecodeKey(data)
+	require.Nil(t, err)
+	assert.Equal(t, 0, key.D.Cmp(decoded.D))
+
+	_, err = acme.DecodeKey([]byte("bogus"))
+	assert.EqualError(t, err, "invalid account key")
+}
+
+func TestDNSRecord(t *testing.T) {
+	sum := sha256.Sum256([]byte("token.thumbprint"))
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(sum[:]), acme.DNSRecord("token.thumbprint"))
+}
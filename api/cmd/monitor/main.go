@@ -1,16 +1,36 @@
 package main
 
 import (
+	"os"
 	"time"
 
 	"github.com/convox/rack/api/workers"
 )
 
 func main() {
+	// in high availability mode several monitors run and only the leader does any work
+	if os.Getenv("HIGH_AVAILABILITY") == "true" {
+		workers.WaitForLeadership()
+	}
+
+	go workers.StartAlarms()
 	go workers.StartAutoscale()
+	go workers.StartBreakGlass()
+	go workers.StartBuildRetention()
+	go workers.StartBuildSchedules()
+	go workers.StartCertificates()
 	go workers.StartCluster()
+	go workers.StartCrashes()
+	go workers.StartDiskCleanup()
+	go workers.StartDrains()
+	go workers.StartEventRetention()
 	go workers.StartHeartbeat()
+	go workers.StartMonitors()
 	go workers.StartServicesCapacity()
+	go workers.StartSnapshots()
+	go workers.StartStackFailures()
+	go workers.StartStatusChecks()
+	go workers.StartTimers()
 
 	for {
 		time.Sleep(1 * time.Hour)
@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/convox/rack/api/httperr"
+	"github.com/convox/rack/api/models"
+	"github.com/gorilla/mux"
+)
+
+// AlarmList returns whether each alarm metric is enabled for an app and the state of its alarms
+func AlarmList(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	alarms, err := models.ListAlarms(app)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, alarms)
+}
+
+func AlarmEnable(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	return setAlarm(rw, r, true)
+}
+
+func AlarmDisable(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	return setAlarm(rw, r, false)
+}
+
+func setAlarm(rw http.ResponseWriter, r *http.Request, enabled bool) *httperr.Error {
+	vars := mux.Vars(r)
+	app := vars["app"]
+	metric := vars["metric"]
+
+	err := models.SetAlarm(app, metric, enabled)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil && (strings.HasPrefix(err.Error(), "metric must be") || strings.HasPrefix(err.Error(), "promote a new release")) {
+		return httperr.Errorf(403, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderSuccess(rw)
+}
+
+func CustomAlarmList(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	alarms, err := models.ListCustomAlarms(app)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, alarms)
+}
+
+func CustomAlarmCreate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	if r.FormValue("metric") == "" {
+		return httperr.Errorf(403, "metric is required")
+	}
+
+	if r.FormValue("threshold") == "" {
+		return httperr.Errorf(403, "threshold is required")
+	}
+
+	return saveCustomAlarm(rw, r, models.NewCustomAlarm(app))
+}
+
+// CustomAlarmUpdate changes the fields of a custom alarm given in the request and leaves the rest alone
+func CustomAlarmUpdate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	vars := mux.Vars(r)
+	app := vars["app"]
+	alarm := vars["alarm"]
+
+	c, err := models.GetCustomAlarm(app, alarm)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil && strings.HasPrefix(err.Error(), "no such alarm") {
+		return httperr.Errorf(404, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	// a new metric is aggregated the way that metric usually is unless a statistic is given
+	if r.FormValue("metric") != "" && r.FormValue("metric") != c.Metric {
+		c.Statistic = ""
+	}
+
+	return saveCustomAlarm(rw, r, c)
+}
+
+func CustomAlarmDelete(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	vars := mux.Vars(r)
+	app := vars["app"]
+	alarm := vars["alarm"]
+
+	c, err := models.GetCustomAlarm(app, alarm)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil && strings.HasPrefix(err.Error(), "no such alarm") {
+		return httperr.Errorf(404, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	if err := c.Delete(); err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderSuccess(rw)
+}
+
+func saveCustomAlarm(rw http.ResponseWriter, r *http.Request, c *models.CustomAlarm) *httperr.Error {
+	for field, value := range map[string]*string{"action": &c.Action, "comparison": &c.Comparison, "metric": &c.Metric, "process": &c.Process, "statistic": &c.Statistic} {
+		if v := r.FormValue(field); v != "" {
+			*value = v
+		}
+	}
+
+	for field, value := range map[string]*int{"evaluations": &c.Evaluations, "period": &c.Period} {
+		if v := r.FormValue(field); v != "" {
+			i, err := strconv.Atoi(v)
+			if err != nil {
+				return httperr.Invalid(field, "%s must be numeric", field)
+			}
+			*value = i
+		}
+	}
+
+	if v := r.FormValue("threshold"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return httperr.Invalid("threshold", "threshold must be numeric")
+		}
+		c.Threshold = f
+	}
+
+	err := c.Save()
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", c.App)
+	}
+	if err != nil && customAlarmInvalid(err) {
+		return httperr.Errorf(403, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, c)
+}
+
+// customAlarmInvalid returns true for the errors of a definition that can not be alarmed on as given
+func customAlarmInvalid(err error) bool {
+	for _, prefix := range []string{"action ", "app has not been promoted", "comparison ", "evaluations ", "metric ", "no load balancer", "no service", "no such process", "no such webhook", "period ", "process is required", "statistic "} {
+		if strings.HasPrefix(err.Error(), prefix) {
+			return true
+		}
+	}
+
+	return strings.HasSuffix(err.Error(), "is not behind a load balancer")
+}
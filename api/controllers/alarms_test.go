@@ -0,0 +1,24 @@
+package controllers_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/convox/rack/api/controllers"
+	"github.com/convox/rack/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomAlarmCreateRequired(t *testing.T) {
+	hf := test.NewHandlerFunc(controllers.HandlerFunc)
+
+	if assert.Nil(t, hf.Request("POST", "/apps/myapp/alarms/custom", url.Values{"threshold": {"100"}})) {
+		hf.AssertCode(t, 403)
+		hf.AssertError(t, "metric is required")
+	}
+
+	if assert.Nil(t, hf.Request("POST", "/apps/myapp/alarms/custom", url.Values{"metric": {"RequestCount"}})) {
+		hf.AssertCode(t, 403)
+		hf.AssertError(t, "threshold is required")
+	}
+}
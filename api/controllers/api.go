@@ -10,6 +10,8 @@ import (
 
 	"github.com/convox/logger"
 	"github.com/convox/rack/api/httperr"
+	"github.com/convox/rack/api/models"
+	"github.com/gorilla/mux"
 	"golang.org/x/net/websocket"
 )
 
@@ -22,6 +24,8 @@ func api(at string, handler ApiHandlerFunc) http.HandlerFunc {
 	return func(rw http.ResponseWriter, r *http.Request) {
 		log := logger.New("ns=api.controllers").At(at).Start()
 
+		rw.Header().Set("Auth-Schemes", AuthSchemes(r))
+
 		if !passwordCheck(r) {
 			log.Errorf("invalid authorization")
 			rw.Header().Set("WWW-Authenticate", `Basic realm="Convox System"`)
@@ -39,8 +43,15 @@ func api(at string, handler ApiHandlerFunc) http.HandlerFunc {
 
 		err := handler(rw, r)
 
+		audit(at, r, err)
+
 		if err != nil {
 			log.Error(err)
+
+			if err.Server() {
+				recordError(at, r, err)
+			}
+
 			rw.WriteHeader(err.Code())
 			RenderError(rw, err)
 			return
@@ -50,17 +61,157 @@ func api(at string, handler ApiHandlerFunc) http.HandlerFunc {
 	}
 }
 
+// auditSkip are POST and PUT actions that only stage files for a build or preview a change
+var auditSkip = []string{"index.diff", "index.update", "upload.create", "upload.chunk", "parameters.preview", "release.promote.preview"}
+
+// audit records a call that changes the rack once its handler has parsed the request
+func audit(at string, r *http.Request, err *httperr.Error) {
+	if r.Method == "GET" || r.Method == "HEAD" {
+		return
+	}
+
+	for _, skip := range auditSkip {
+		if at == skip {
+			return
+		}
+	}
+
+	recordAudit(at, r, err, "")
+}
+
+// recordAudit saves an audit event for a call in the background, summarizing its form unless a
+// summary is given
+func recordAudit(at string, r *http.Request, err *httperr.Error, summary string) {
+	e := models.NewAuditEvent(at, r.Method, r.URL.Path)
+
+	e.App = mux.Vars(r)["app"]
+	e.Status = 200
+	e.Summary = summary
+
+	if e.Summary == "" {
+		e.Summary = models.AuditSummary(r.Form, r.ContentLength)
+	}
+
+	e.User = requestUser(r)
+	e.Authenticated = requestIdentity(r) != ""
+
+	if err != nil {
+		e.Status = err.Code()
+	}
+
+	// denied calls without a form, like websockets, are summarized by their error
+	if e.Summary == "" && err != nil && err.Code() == 403 {
+		e.Summary = err.Error()
+	}
+
+	go func() {
+		if err := e.Save(); err != nil {
+			logger.New("ns=api.controllers").At("audit").Error(err)
+		}
+	}()
+}
+
+// immutable denies an action on an app whose policy only allows changes through releases unless
+// the user authenticated with their key has an active break glass grant. Denials and every use of a grant are logged and
+// recorded in the audit log, including for websocket actions that are not audited otherwise.
+func immutable(at string, r *http.Request, app, action string) *httperr.Error {
+	log := logger.New("ns=api.controllers").At(at)
+	user := requestUser(r)
+
+	// grants only apply to the user whose key authenticated the request
+	grant, err := models.CheckImmutable(app, action, requestIdentity(r))
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil && strings.HasSuffix(err.Error(), "is not allowed") {
+		log.Logf("app=%s user=%q denied=immutable", app, user)
+
+		herr := httperr.Errorf(403, "%s", err)
+
+		if r.Method == "GET" {
+			recordAudit(at, r, herr, "")
+		}
+
+		return herr
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	if grant != nil {
+		log.Logf("app=%s user=%q allowed=breakglass breakglass=%s", app, user, grant.Id)
+
+		recordAudit("breakglass.use", r, nil, fmt.Sprintf("%s %s: %s", grant.Id, action, grant.Reason))
+	}
+
+	return nil
+}
+
+// recordError keeps failed calls as rack events so they can be found after the logs have rotated
+func recordError(at string, r *http.Request, err *httperr.Error) {
+	data := map[string]string{
+		"action":  at,
+		"method":  r.Method,
+		"path":    r.URL.Path,
+		"message": err.Error(),
+	}
+
+	if app := mux.Vars(r)["app"]; app != "" {
+		data["app"] = app
+	}
+
+	go func() {
+		if err := models.RecordRackEvent("api:error", "error", data); err != nil {
+			logger.New("ns=api.controllers").At("recordError").Error(err)
+		}
+	}()
+}
+
+// authenticatedUserHeader carries the user whose key authenticated a request to its handler.
+// It is removed from every incoming request so a client can not set it.
+const authenticatedUserHeader = "Convox-Authenticated-User"
+
+// passwordCheck authenticates a request with the rack password or the key of a user
 func passwordCheck(r *http.Request) bool {
+	r.Header.Del(authenticatedUserHeader)
+
+	auth := r.Header.Get("Authorization")
+
+	if strings.HasPrefix(auth, signatureScheme+" ") {
+		if user := r.Header.Get("Key-User"); user != "" {
+			key, err := models.UserKeySigningKey(user)
+			if err != nil || !signatureCheck(r, key) {
+				return false
+			}
+
+			r.Header.Set(authenticatedUserHeader, user)
+			return true
+		}
+	}
+
+	if strings.HasPrefix(auth, "Basic ") {
+		if c, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(auth, "Basic ")); err == nil {
+			parts := strings.SplitN(string(c), ":", 2)
+
+			if len(parts) == 2 && parts[0] != "" && parts[0] != "convox" && models.AuthenticateUserKey(parts[0], parts[1]) {
+				r.Header.Set(authenticatedUserHeader, parts[0])
+				return true
+			}
+		}
+	}
+
 	if os.Getenv("PASSWORD") == "" {
 		return true
 	}
 
-	auth := r.Header.Get("Authorization")
-
 	if auth == "" {
 		return false
 	}
 
+	if strings.HasPrefix(auth, signatureScheme+" ") {
+		return signatureCheck(r, os.Getenv("PASSWORD"))
+	}
+
 	if !strings.HasPrefix(auth, "Basic ") {
 		return false
 	}
@@ -1,15 +1,29 @@
 package controllers_test
 
 import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/convox/rack/api/awsutil"
 	"github.com/convox/rack/api/controllers"
+	"github.com/convox/rack/api/crypt"
 	"github.com/convox/rack/api/models"
 	"github.com/convox/rack/test"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/nacl/secretbox"
 )
 
 // Note: these tests don't use the api helpers to ensure a naked
@@ -53,3 +67,268 @@ func TestBasicAuth(t *testing.T) {
 
 	assert.Equal(200, w.Code)
 }
+
+func signedRequest(method, uri, body, password string, at time.Time) *http.Request {
+	req, _ := http.NewRequest(method, "http://convox"+uri, strings.NewReader(body))
+
+	sum := sha256.Sum256([]byte(body))
+	hash := hex.EncodeToString(sum[:])
+	ts := strconv.FormatInt(at.Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(password))
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%s", method, uri, ts, hash)
+
+	req.Header.Set("Authorization", "Convox-HMAC-SHA256 Signature="+hex.EncodeToString(mac.Sum(nil)))
+	req.Header.Set("Content-Sha256", hash)
+	req.Header.Set("Timestamp", ts)
+
+	return req
+}
+
+// signatureCycle stubs recording a request signature, failing if it was already recorded
+func signatureCycle(seen bool) awsutil.Cycle {
+	c := awsutil.Cycle{
+		Request: awsutil.Request{
+			RequestURI: "/",
+			Operation:  "DynamoDB_20120810.PutItem",
+			Body:       `/"ConditionExpression":"attribute_not_exists\(signature\)".*"TableName":"convox-signatures"/`,
+		},
+		Response: awsutil.Response{
+			StatusCode: 200,
+			Body:       `{}`,
+		},
+	}
+
+	if seen {
+		c.Response = awsutil.Response{
+			StatusCode: 400,
+			Body:       `{"__type":"com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException","message":"The conditional request failed"}`,
+		}
+	}
+
+	return c
+}
+
+func TestSignedRequest(t *testing.T) {
+	models.TestProvider.On("SystemGet").Return(nil, nil)
+
+	aws := test.StubAws(
+		signatureCycle(false),
+		test.DescribeConvoxStackCycle("convox-test"),
+		signatureCycle(true),
+	)
+	defer aws.Close()
+	defer os.Setenv("PASSWORD", os.Getenv("PASSWORD"))
+	defer os.Setenv("RACK", os.Getenv("RACK"))
+	defer os.Setenv("DYNAMO_SIGNATURES", os.Getenv("DYNAMO_SIGNATURES"))
+
+	os.Setenv("PASSWORD", "keymaster")
+	os.Setenv("RACK", "convox-test")
+	os.Setenv("DYNAMO_SIGNATURES", "convox-signatures")
+
+	req := signedRequest("GET", "/system", "", "keymaster", time.Now())
+
+	w := httptest.NewRecorder()
+	controllers.HandlerFunc(w, req)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "basic, signature", w.Header().Get("Auth-Schemes"))
+
+	// the same signature can not be used twice, on this or any other rack host
+	w = httptest.NewRecorder()
+	controllers.HandlerFunc(w, req)
+	assert.Equal(t, 401, w.Code)
+
+	w = httptest.NewRecorder()
+	controllers.HandlerFunc(w, signedRequest("GET", "/system", "", "wrong", time.Now()))
+	assert.Equal(t, 401, w.Code)
+
+	w = httptest.NewRecorder()
+	controllers.HandlerFunc(w, signedRequest("GET", "/system", "", "keymaster", time.Now().Add(-10*time.Minute)))
+	assert.Equal(t, 401, w.Code)
+
+	tampered := signedRequest("GET", "/system", "", "keymaster", time.Now())
+	tampered.Body = ioutil.NopCloser(strings.NewReader("count=100"))
+
+	w = httptest.NewRecorder()
+	controllers.HandlerFunc(w, tampered)
+	assert.Equal(t, 401, w.Code)
+
+	// only streamed uploads and builds may leave their body unsigned
+	unsigned := signedRequest("POST", "/apps/foo/formation/web", "count=100", "keymaster", time.Now())
+	unsigned.Header.Set("Content-Sha256", "UNSIGNED-PAYLOAD")
+
+	w = httptest.NewRecorder()
+	controllers.HandlerFunc(w, unsigned)
+	assert.Equal(t, 401, w.Code)
+}
+
+func TestSignedRequestWithoutTable(t *testing.T) {
+	aws := test.StubAws()
+	defer aws.Close()
+	defer os.Setenv("PASSWORD", os.Getenv("PASSWORD"))
+
+	os.Setenv("PASSWORD", "keymaster")
+
+	w := httptest.NewRecorder()
+	controllers.HandlerFunc(w, signedRequest("GET", "/system", "", "keymaster", time.Now()))
+	assert.Equal(t, 401, w.Code)
+	assert.Equal(t, "basic", w.Header().Get("Auth-Schemes"))
+}
+
+// userKeyCycle stubs fetching the stored key of user from the rack settings bucket
+func userKeyCycle(user, key string) awsutil.Cycle {
+	return awsutil.Cycle{
+		Request: awsutil.Request{
+			RequestURI: fmt.Sprintf("/convox-settings/users/%s.json", user),
+		},
+		Response: awsutil.Response{
+			StatusCode: 200,
+			Body:       fmt.Sprintf(`{"user":%q,"hash":%q}`, user, models.UserKeyHash(key)),
+		},
+	}
+}
+
+func TestUserKeyAuth(t *testing.T) {
+	models.TestProvider.On("SystemGet").Return(nil, nil)
+
+	aws := test.StubAws(
+		userKeyCycle("alice", "secret"),
+		userKeyCycle("alice", "secret"),
+		userKeyCycle("alice", "secret"),
+	)
+	defer aws.Close()
+	defer os.Setenv("PASSWORD", os.Getenv("PASSWORD"))
+	defer os.Setenv("RACK", os.Getenv("RACK"))
+	defer os.Setenv("SETTINGS_BUCKET", os.Getenv("SETTINGS_BUCKET"))
+
+	os.Setenv("PASSWORD", "keymaster")
+	os.Setenv("RACK", "convox-test")
+	os.Setenv("SETTINGS_BUCKET", "convox-settings")
+
+	req, _ := http.NewRequest("GET", "http://convox/system", nil)
+	req.SetBasicAuth("alice", "secret")
+
+	w := httptest.NewRecorder()
+	controllers.HandlerFunc(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	req, _ = http.NewRequest("GET", "http://convox/system", nil)
+	req.SetBasicAuth("alice", "wrong")
+
+	w = httptest.NewRecorder()
+	controllers.HandlerFunc(w, req)
+	assert.Equal(t, 401, w.Code)
+
+	// user keys can not make keys for other users
+	req, _ = http.NewRequest("POST", "http://convox/users", strings.NewReader("user=bob"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Version", "dev")
+	req.SetBasicAuth("alice", "secret")
+
+	w = httptest.NewRecorder()
+	controllers.HandlerFunc(w, req)
+	assert.Equal(t, 403, w.Code)
+	assert.Equal(t, `{"error":"creating user keys requires the rack password, alice authenticated with a user key"}`, w.Body.String())
+}
+
+// userKeyDataKey stands in for the data key kms returns for the signing secrets of user keys
+var userKeyDataKey = bytes.Repeat([]byte{1}, crypt.KeyLength)
+
+// userKeySecretCycle stubs fetching the stored key of user along with its encrypted signing secret
+func userKeySecretCycle(user, key string) awsutil.Cycle {
+	var dk [crypt.KeyLength]byte
+	var nonce [crypt.NonceLength]byte
+
+	copy(dk[:], userKeyDataKey)
+
+	env, _ := json.Marshal(crypt.Envelope{
+		Ciphertext:   secretbox.Seal(nil, []byte(models.UserKeySigningSecret(key)), &nonce, &dk),
+		EncryptedKey: []byte("encrypted"),
+		Nonce:        nonce[:],
+	})
+
+	data, _ := json.Marshal(models.UserKey{User: user, Hash: models.UserKeyHash(key), Secret: env})
+
+	return awsutil.Cycle{
+		Request: awsutil.Request{
+			RequestURI: fmt.Sprintf("/convox-settings/users/%s.json", user),
+		},
+		Response: awsutil.Response{
+			StatusCode: 200,
+			Body:       string(data),
+		},
+	}
+}
+
+// kmsDecryptCycle stubs kms returning the data key of a signing secret
+func kmsDecryptCycle() awsutil.Cycle {
+	return awsutil.Cycle{
+		Request: awsutil.Request{
+			RequestURI: "/",
+			Operation:  "TrentService.Decrypt",
+			Body:       `/"CiphertextBlob":"ZW5jcnlwdGVk"/`,
+		},
+		Response: awsutil.Response{
+			StatusCode: 200,
+			Body:       fmt.Sprintf(`{"Plaintext":%q}`, base64.StdEncoding.EncodeToString(userKeyDataKey)),
+		},
+	}
+}
+
+func TestUserKeySignedRequest(t *testing.T) {
+	models.TestProvider.On("SystemGet").Return(nil, nil)
+
+	aws := test.StubAws(
+		userKeySecretCycle("alice", "secret"),
+		kmsDecryptCycle(),
+		signatureCycle(false),
+		userKeySecretCycle("alice", "secret"),
+		kmsDecryptCycle(),
+		userKeyCycle("bob", "secret"),
+		userKeyCycle("bob", "secret"),
+	)
+	defer aws.Close()
+	defer os.Setenv("PASSWORD", os.Getenv("PASSWORD"))
+	defer os.Setenv("RACK", os.Getenv("RACK"))
+	defer os.Setenv("SETTINGS_BUCKET", os.Getenv("SETTINGS_BUCKET"))
+	defer os.Setenv("DYNAMO_SIGNATURES", os.Getenv("DYNAMO_SIGNATURES"))
+
+	os.Setenv("PASSWORD", "keymaster")
+	os.Setenv("RACK", "convox-test")
+	os.Setenv("SETTINGS_BUCKET", "convox-settings")
+	os.Setenv("DYNAMO_SIGNATURES", "convox-signatures")
+
+	// the client derives the same secret
+	assert.Equal(t, "6d5bfcc228b3e444646e7d62062fa5b2c532e8b011a7d07588d5915bf6ad4291", models.UserKeySigningSecret("secret"))
+
+	req := signedRequest("GET", "/system", "", models.UserKeySigningSecret("secret"), time.Now())
+	req.Header.Set("Key-User", "alice")
+
+	w := httptest.NewRecorder()
+	controllers.HandlerFunc(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	// the hash is readable in the settings bucket so it must not sign requests
+	req = signedRequest("GET", "/system", "", models.UserKeyHash("secret"), time.Now())
+	req.Header.Set("Key-User", "alice")
+
+	w = httptest.NewRecorder()
+	controllers.HandlerFunc(w, req)
+	assert.Equal(t, 401, w.Code)
+
+	// keys stored without a signing secret can only use basic auth
+	req = signedRequest("GET", "/system", "", models.UserKeyHash("secret"), time.Now())
+	req.Header.Set("Key-User", "bob")
+
+	w = httptest.NewRecorder()
+	controllers.HandlerFunc(w, req)
+	assert.Equal(t, 401, w.Code)
+
+	req, _ = http.NewRequest("GET", "http://convox/auth", nil)
+	req.Header.Set("Key-User", "bob")
+
+	w = httptest.NewRecorder()
+	controllers.HandlerFunc(w, req)
+	assert.Equal(t, 401, w.Code)
+	assert.Equal(t, "basic", w.Header().Get("Auth-Schemes"))
+}
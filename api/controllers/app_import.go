@@ -0,0 +1,28 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/convox/rack/api/httperr"
+	"github.com/convox/rack/api/models"
+)
+
+func AppImportECS(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	cluster := r.FormValue("cluster")
+	service := r.FormValue("service")
+
+	if cluster == "" || service == "" {
+		return httperr.Errorf(403, "cluster and service are required")
+	}
+
+	imp, err := models.Provider().AppImportECS(cluster, service)
+	if err != nil && strings.HasPrefix(err.Error(), "no such ecs service") {
+		return httperr.Errorf(404, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, imp)
+}
@@ -107,6 +107,28 @@ func AppDelete(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 	return RenderSuccess(rw)
 }
 
+func AppCancel(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	name := mux.Vars(r)["app"]
+
+	app, err := models.GetApp(name)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", name)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	if app.Status != "updating" {
+		return httperr.Errorf(403, "app is not updating: %s", name)
+	}
+
+	if err := app.CancelUpdate(); err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderSuccess(rw)
+}
+
 func AppLogs(ws *websocket.Conn) *httperr.Error {
 	app := mux.Vars(ws.Request())["app"]
 	header := ws.Request().Header
@@ -127,9 +149,10 @@ func AppLogs(ws *websocket.Conn) *httperr.Error {
 	}
 
 	err = models.Provider().LogStream(app, ws, structs.LogStreamOptions{
-		Filter: header.Get("Filter"),
-		Follow: follow,
-		Since:  since,
+		Filter:  header.Get("Filter"),
+		Follow:  follow,
+		Process: header.Get("Process"),
+		Since:   since,
 	})
 	if err != nil {
 		if strings.HasSuffix(err.Error(), "write: broken pipe") {
@@ -139,3 +162,25 @@ func AppLogs(ws *websocket.Conn) *httperr.Error {
 	}
 	return nil
 }
+
+// AppEvents streams the stack events of an app update until the update has finished
+func AppEvents(ws *websocket.Conn) *httperr.Error {
+	app := mux.Vars(ws.Request())["app"]
+
+	a, err := models.GetApp(app)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	if err := a.StreamEvents(ws); err != nil {
+		if strings.HasSuffix(err.Error(), "write: broken pipe") {
+			return nil
+		}
+		return httperr.Server(err)
+	}
+
+	return nil
+}
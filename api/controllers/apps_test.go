@@ -175,6 +175,32 @@ func TestAppDeleteWithAppNotFound(t *testing.T) {
 	test.AssertStatus(t, 404, "DELETE", "http://convox/apps/bar", nil)
 }
 
+func TestAppCancel(t *testing.T) {
+	aws := test.StubAws(
+		test.DescribeAppStatusStackCycle("convox-test-bar", "UPDATE_IN_PROGRESS"),
+		test.CancelUpdateStackCycle("convox-test-bar"),
+	)
+	defer aws.Close()
+
+	body := test.HTTPBody("POST", "http://convox/apps/bar/cancel", nil)
+
+	var resp map[string]bool
+	err := json.Unmarshal([]byte(body), &resp)
+
+	if assert.Nil(t, err) {
+		assert.Equal(t, true, resp["success"])
+	}
+}
+
+func TestAppCancelNotUpdating(t *testing.T) {
+	aws := test.StubAws(
+		test.DescribeAppStatusStackCycle("convox-test-bar", "UPDATE_COMPLETE"),
+	)
+	defer aws.Close()
+
+	test.AssertStatus(t, 403, "POST", "http://convox/apps/bar/cancel", nil)
+}
+
 func TestAppLogs(t *testing.T) {
 
 }
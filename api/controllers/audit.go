@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/convox/rack/api/httperr"
+	"github.com/convox/rack/api/models"
+)
+
+func AuditList(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	filter := models.AuditFilter{
+		Action: r.URL.Query().Get("action"),
+		App:    r.URL.Query().Get("app"),
+		User:   r.URL.Query().Get("user"),
+		Limit:  100,
+	}
+
+	if since := r.URL.Query().Get("since"); since != "" {
+		d, err := time.ParseDuration(since)
+		if err != nil {
+			return httperr.Errorf(403, "since must be a duration like 24h: %s", since)
+		}
+
+		filter.Since = time.Now().Add(-d)
+	}
+
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil || l < 1 {
+			return httperr.Errorf(403, "limit must be a positive number: %s", limit)
+		}
+
+		filter.Limit = l
+	}
+
+	events, err := models.ListAudit(filter)
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, events)
+}
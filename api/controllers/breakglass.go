@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/convox/rack/api/httperr"
+	"github.com/convox/rack/api/models"
+	"github.com/gorilla/mux"
+)
+
+func BreakGlassList(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	grants, err := models.ListBreakGlass(app)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, grants)
+}
+
+func BreakGlassRequest(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	duration, err := time.ParseDuration(GetForm(r, "duration"))
+	if err != nil {
+		return httperr.Errorf(403, "invalid duration: %s", GetForm(r, "duration"))
+	}
+
+	b, err := models.RequestBreakGlass(app, requestIdentity(r), GetForm(r, "reason"), duration)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil {
+		for _, prefix := range []string{"must specify", "duration must", "app is not immutable", "break glass request"} {
+			if strings.HasPrefix(err.Error(), prefix) {
+				return httperr.Errorf(403, "%s", err)
+			}
+		}
+
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, b)
+}
+
+// BreakGlassApprove starts a grant on behalf of the user whose key authenticated the request
+func BreakGlassApprove(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	return reviewBreakGlass(rw, r, (*models.BreakGlass).Approve, requestIdentity(r))
+}
+
+// BreakGlassDeny and BreakGlassRevoke only take access away so the rack password can use them
+func BreakGlassDeny(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	return reviewBreakGlass(rw, r, (*models.BreakGlass).Deny, requestUser(r))
+}
+
+func BreakGlassRevoke(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	return reviewBreakGlass(rw, r, (*models.BreakGlass).Revoke, requestUser(r))
+}
+
+// reviewBreakGlass changes the status of a break glass request on behalf of user
+func reviewBreakGlass(rw http.ResponseWriter, r *http.Request, review func(*models.BreakGlass, string) error, user string) *httperr.Error {
+	vars := mux.Vars(r)
+	app := vars["app"]
+	id := vars["id"]
+
+	b, err := models.GetBreakGlass(app, id)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil && strings.HasPrefix(err.Error(), "no such break glass") {
+		return httperr.Errorf(404, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	if err := review(b, user); err != nil {
+		if strings.HasPrefix(err.Error(), "break glass request") {
+			return httperr.Errorf(403, "%s", err)
+		}
+
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, b)
+}
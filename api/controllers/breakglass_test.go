@@ -0,0 +1,98 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/convox/rack/api/awsutil"
+	"github.com/convox/rack/api/controllers"
+	"github.com/convox/rack/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreakGlassRequestInvalidDuration(t *testing.T) {
+	aws := test.StubAws()
+	defer aws.Close()
+
+	body := test.AssertStatus(t, 403, "POST", "http://convox/apps/bar/breakglass", url.Values{"duration": {"forever"}, "reason": {"incident 1234"}})
+	assert.Equal(t, `{"error":"invalid duration: forever"}`, body)
+}
+
+func TestBreakGlassApproveMissing(t *testing.T) {
+	aws := test.StubAws(
+		test.DescribeAppStackCycle("convox-test-bar"),
+		awsutil.Cycle{
+			Request: awsutil.Request{
+				RequestURI: "/apache-app-settings-2gkjc9lf123nm/breakglass/G1234567890.json",
+			},
+			Response: awsutil.Response{
+				StatusCode: 404,
+				Body:       `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`,
+			},
+		},
+	)
+	defer aws.Close()
+
+	body := test.AssertStatus(t, 404, "POST", "http://convox/apps/bar/breakglass/G1234567890/approve", nil)
+	assert.Equal(t, `{"error":"no such break glass request: G1234567890"}`, body)
+}
+
+func TestBreakGlassApproveWithoutUserKey(t *testing.T) {
+	aws := test.StubAws(
+		test.DescribeAppStackCycle("convox-test-bar"),
+		awsutil.Cycle{
+			Request: awsutil.Request{
+				RequestURI: "/apache-app-settings-2gkjc9lf123nm/breakglass/G1234567890.json",
+			},
+			Response: awsutil.Response{
+				StatusCode: 200,
+				Body:       `{"id":"G1234567890","app":"bar","user":"alice","reason":"incident 1234","duration":3600000000000,"status":"pending"}`,
+			},
+		},
+	)
+	defer aws.Close()
+
+	// the user header is asserted by the client and does not count as an approver
+	req, _ := http.NewRequest("POST", "http://convox/apps/bar/breakglass/G1234567890/approve", nil)
+	req.Header.Set("Version", "dev")
+	req.Header.Set("User", "bob")
+
+	w := httptest.NewRecorder()
+	controllers.HandlerFunc(w, req)
+
+	assert.Equal(t, 403, w.Code)
+	assert.Equal(t, `{"error":"break glass request must be approved with a user key"}`, w.Body.String())
+}
+
+func TestBreakGlassApproveOwnRequest(t *testing.T) {
+	aws := test.StubAws(
+		userKeyCycle("alice", "secret"),
+		test.DescribeAppStackCycle("convox-test-bar"),
+		awsutil.Cycle{
+			Request: awsutil.Request{
+				RequestURI: "/apache-app-settings-2gkjc9lf123nm/breakglass/G1234567890.json",
+			},
+			Response: awsutil.Response{
+				StatusCode: 200,
+				Body:       `{"id":"G1234567890","app":"bar","user":"alice","reason":"incident 1234","duration":3600000000000,"status":"pending"}`,
+			},
+		},
+	)
+	defer aws.Close()
+	defer os.Setenv("SETTINGS_BUCKET", os.Getenv("SETTINGS_BUCKET"))
+
+	os.Setenv("SETTINGS_BUCKET", "convox-settings")
+
+	req, _ := http.NewRequest("POST", "http://convox/apps/bar/breakglass/G1234567890/approve", nil)
+	req.Header.Set("Version", "dev")
+	req.SetBasicAuth("alice", "secret")
+
+	w := httptest.NewRecorder()
+	controllers.HandlerFunc(w, req)
+
+	assert.Equal(t, 403, w.Code)
+	assert.Equal(t, `{"error":"break glass request must be approved by another user"}`, w.Body.String())
+}
@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/convox/rack/api/httperr"
+	"github.com/convox/rack/api/models"
+	"github.com/gorilla/mux"
+)
+
+func BuildScheduleList(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	schedules, err := models.ListBuildSchedules(app)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, schedules)
+}
+
+func BuildScheduleCreate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	_, err := models.GetApp(app)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	s := models.NewBuildSchedule(app, r.FormValue("schedule"), r.FormValue("url"), r.FormValue("manifest"))
+
+	if err := s.Validate(); err != nil {
+		return httperr.Errorf(403, "%s", err)
+	}
+
+	if err := s.Save(); err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, s)
+}
+
+func BuildScheduleDelete(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	vars := mux.Vars(r)
+	app := vars["app"]
+	schedule := vars["schedule"]
+
+	s, err := models.GetBuildSchedule(app, schedule)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil && strings.HasPrefix(err.Error(), "no such build schedule") {
+		return httperr.Errorf(404, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	if err := s.Delete(); err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderSuccess(rw)
+}
@@ -3,6 +3,7 @@ package controllers
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"strconv"
@@ -13,6 +14,7 @@ import (
 	"github.com/convox/rack/api/httperr"
 	"github.com/convox/rack/api/models"
 	"github.com/convox/rack/api/structs"
+	"github.com/convox/rack/manifest"
 	docker "github.com/fsouza/go-dockerclient"
 	"github.com/gorilla/mux"
 	"golang.org/x/net/websocket"
@@ -76,9 +78,55 @@ func BuildCreate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 	vars := mux.Vars(r)
 	app := vars["app"]
 
-	cache := !(r.FormValue("cache") == "false")
-	manifest := r.FormValue("manifest")
-	description := r.FormValue("description")
+	strict := r.FormValue("strict")
+
+	if _, err := manifest.ParseLintRules(strict); err != nil {
+		return httperr.Invalid("strict", "%s", err)
+	}
+
+	buildArgs := []string{}
+
+	for _, arg := range strings.Split(r.FormValue("build-args"), "\n") {
+		if arg != "" {
+			buildArgs = append(buildArgs, arg)
+		}
+	}
+
+	if _, err := manifest.ParseBuildArgs(buildArgs); err != nil {
+		return httperr.Invalid("build-args", "%s", err)
+	}
+
+	architectures, err := structs.ParseBuildMatrix(r.FormValue("matrix"))
+	if err != nil {
+		return httperr.Invalid("matrix", "%s", err)
+	}
+
+	opts := structs.BuildOptions{
+		Architectures: architectures,
+		BuildArgs:     buildArgs,
+		Cache:         !(r.FormValue("cache") == "false"),
+		Description:   r.FormValue("description"),
+		Manifest:      r.FormValue("manifest"),
+		Priority:      r.FormValue("priority"),
+		Strict:        strict,
+	}
+
+	if opts.Priority == "" {
+		opts.Priority = "normal"
+	}
+
+	if err := structs.ValidBuildPriority(opts.Priority); err != nil {
+		return httperr.Errorf(403, "%s", err)
+	}
+
+	if c := r.FormValue("concurrency"); c != "" {
+		n, err := strconv.Atoi(c)
+		if err != nil || n < 1 {
+			return httperr.Invalid("concurrency", "concurrency must be a positive number")
+		}
+
+		opts.Concurrency = n
+	}
 
 	repo := r.FormValue("repo")
 	index := r.FormValue("index")
@@ -89,6 +137,11 @@ func BuildCreate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 		return httperr.Server(err)
 	}
 
+	upload, herr := uploadSource(r)
+	if herr != nil {
+		return herr
+	}
+
 	// Log into private registries that we might pull from
 	// TODO: move to prodiver BuildCreate
 	err = models.LoginPrivateRegistries()
@@ -109,11 +162,31 @@ func BuildCreate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 
 	var b *structs.Build
 
-	// if source file was posted, build from tar
-	if source != nil {
-		b, err = models.Provider().BuildCreateTar(app, source, r.FormValue("manifest"), r.FormValue("description"), cache)
+	// if source file was posted or uploaded in chunks, build from tar
+	if source != nil || upload != nil {
+		var src io.Reader = upload
+
+		if source != nil {
+			src = source
+		}
+
+		// large files the client left out of the tarball because the rack index has them
+		if blobs := r.FormValue("blobs"); blobs != "" {
+			var i structs.Index
+
+			if err := json.Unmarshal([]byte(blobs), &i); err != nil {
+				return httperr.Errorf(403, "invalid blobs: %s", err)
+			}
+
+			src, err = models.SourceWithBlobs(src, i)
+			if err != nil {
+				return httperr.Server(err)
+			}
+		}
+
+		b, err = models.Provider().BuildCreateTar(app, src, opts)
 	} else if repo != "" {
-		b, err = models.Provider().BuildCreateRepo(app, repo, r.FormValue("manifest"), r.FormValue("description"), cache)
+		b, err = models.Provider().BuildCreateRepo(app, repo, opts)
 	} else if index != "" {
 		var i structs.Index
 		err := json.Unmarshal([]byte(index), &i)
@@ -121,7 +194,7 @@ func BuildCreate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 			return httperr.Server(err)
 		}
 
-		b, err = models.Provider().BuildCreateIndex(app, i, manifest, description, cache)
+		b, err = models.Provider().BuildCreateIndex(app, i, opts)
 	} else {
 		return httperr.Errorf(403, "no source, repo or index")
 	}
@@ -160,6 +233,62 @@ func BuildDelete(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 	return RenderJson(rw, build)
 }
 
+// BuildCancel stops a build that is still in progress
+func BuildCancel(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	vars := mux.Vars(r)
+	app := vars["app"]
+	build := vars["build"]
+
+	b, err := models.Provider().BuildGet(app, build)
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	if !b.Ended.IsZero() {
+		return httperr.Errorf(400, "build has already finished: %s", build)
+	}
+
+	b, err = models.Provider().BuildCancel(app, build)
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, b)
+}
+
+// BuildRelease finds the release created for a completed build, creating it if it no longer exists
+func BuildRelease(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	vars := mux.Vars(r)
+	app := vars["app"]
+	build := vars["build"]
+
+	b, err := models.Provider().BuildGet(app, build)
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	if b.Status != "complete" {
+		return httperr.Errorf(403, "build is not complete: %s", build)
+	}
+
+	if b.Release != "" {
+		rr, err := models.Provider().ReleaseGet(app, b.Release)
+		if err == nil {
+			return RenderJson(rw, rr)
+		}
+		if !strings.HasPrefix(err.Error(), "no such release") {
+			return httperr.Server(err)
+		}
+	}
+
+	rr, err := models.Provider().BuildRelease(b)
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, rr)
+}
+
 func BuildUpdate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 	vars := mux.Vars(r)
 	app := vars["app"]
@@ -171,6 +300,11 @@ func BuildUpdate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 		return httperr.Server(err)
 	}
 
+	// a build container that finishes while being cancelled must not complete the build or create a release
+	if b.Status == "cancelled" {
+		return httperr.Errorf(403, "build has been cancelled: %s", build)
+	}
+
 	if d := r.FormValue("description"); d != "" {
 		b.Description = d
 	}
@@ -179,6 +313,22 @@ func BuildUpdate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 		b.Manifest = m
 	}
 
+	if g := r.FormValue("git-sha"); g != "" {
+		b.GitSha = g
+	}
+
+	if i := r.FormValue("images"); i != "" {
+		if err := json.Unmarshal([]byte(i), &b.Images); err != nil {
+			return httperr.Errorf(403, "invalid images: %s", err)
+		}
+	}
+
+	if t := r.FormValue("timings"); t != "" {
+		if err := json.Unmarshal([]byte(t), &b.Timings); err != nil {
+			return httperr.Errorf(403, "invalid timings: %s", err)
+		}
+	}
+
 	if r := r.FormValue("reason"); r != "" {
 		b.Reason = r
 	}
@@ -266,16 +416,109 @@ func BuildCopy(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 	return RenderJson(rw, b)
 }
 
+// BuildExport streams an archive of the manifest and images of a completed build
+func BuildExport(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	vars := mux.Vars(r)
+	app := vars["app"]
+	build := vars["build"]
+
+	a, err := models.GetApp(app)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	b, err := models.Provider().BuildGet(app, build)
+	if err != nil && strings.HasPrefix(err.Error(), "no such build") {
+		return httperr.Errorf(404, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	if b.Status != "complete" {
+		return httperr.Errorf(403, "build is not complete: %s", build)
+	}
+
+	// Log into the registry that we will pull from
+	if _, err := models.AppDockerLogin(*a); err != nil {
+		return httperr.Server(err)
+	}
+
+	rw.Header().Set("Content-Type", "application/gzip")
+	rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.tgz", b.Id))
+
+	if err := models.Provider().BuildExport(app, build, rw); err != nil {
+		return httperr.Server(err)
+	}
+
+	return nil
+}
+
+// BuildImport creates a build and release from an archive made by BuildExport, possibly on another rack
+func BuildImport(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	a, err := models.GetApp(app)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	upload, herr := uploadSource(r)
+	if herr != nil {
+		return herr
+	}
+
+	var source io.Reader = upload
+
+	if upload == nil {
+		file, _, err := r.FormFile("source")
+		if err == http.ErrMissingFile || err == http.ErrNotMultipart {
+			return httperr.Errorf(403, "no build archive")
+		}
+		if err != nil {
+			return httperr.Server(err)
+		}
+
+		defer file.Close()
+
+		source = file
+	}
+
+	// Log into the registry that we will push to
+	if _, err := models.AppDockerLogin(*a); err != nil {
+		return httperr.Server(err)
+	}
+
+	b, err := models.Provider().BuildImport(app, source)
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, b)
+}
+
 func BuildLogs(ws *websocket.Conn) *httperr.Error {
 	vars := mux.Vars(ws.Request())
 
 	app := vars["app"]
 	build := vars["build"]
 
-	_, err := models.Provider().BuildGet(app, build)
+	b, err := models.Provider().BuildGet(app, build)
 	if err != nil {
 		return httperr.Server(err)
 	}
+
+	// finished builds have no container left to follow
+	if !b.Ended.IsZero() {
+		return savedBuildLogs(ws, b)
+	}
+
 	// default to local docker socket
 	host := "unix:///var/run/docker.sock"
 
@@ -284,7 +527,8 @@ func BuildLogs(ws *websocket.Conn) *httperr.Error {
 	if os.Getenv("DEVELOPMENT") != "true" {
 		h, err := findBuildHost(build)
 		if err != nil {
-			return httperr.Server(err)
+			// the container may be gone if the rack restarted during the build
+			return savedBuildLogs(ws, b)
 		}
 
 		host = h
@@ -298,6 +542,19 @@ func BuildLogs(ws *websocket.Conn) *httperr.Error {
 		return httperr.Server(err)
 	}
 
+	// redact the live output the same way as the output that is saved
+	rules, err := models.Provider().LogScrubList(app)
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	scrubber, err := structs.NewLogScrubber(rules)
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	out := scrubber.Writer(ws)
+
 	quit := make(chan bool)
 	logErr := make(chan error)
 
@@ -310,8 +567,8 @@ func BuildLogs(ws *websocket.Conn) *httperr.Error {
 			Stderr:       true,
 			Tail:         "all",
 			RawTerminal:  false,
-			OutputStream: ws,
-			ErrorStream:  ws,
+			OutputStream: out,
+			ErrorStream:  out,
 		})
 
 		logErr <- e
@@ -330,18 +587,8 @@ ForLoop:
 				break ForLoop
 			}
 
-			switch b.Status {
-			case "complete":
-				err = nil
-				break ForLoop
-			case "error":
-				err = fmt.Errorf("%s build failed", app)
-				break ForLoop
-			case "failed":
-				err = fmt.Errorf("%s build failed", app)
-				break ForLoop
-			case "timeout":
-				err = fmt.Errorf("%s build timeout", app)
+			if done, berr := buildFinished(b); done {
+				err = berr
 				break ForLoop
 			}
 		}
@@ -354,6 +601,40 @@ ForLoop:
 	return httperr.Server(err)
 }
 
+// savedBuildLogs writes the output saved for a build so far and reports how it finished
+func savedBuildLogs(ws *websocket.Conn, b *structs.Build) *httperr.Error {
+	logs, err := models.Provider().BuildLogs(b.App, b.Id)
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	if _, err := ws.Write([]byte(logs)); err != nil {
+		return httperr.Server(err)
+	}
+
+	if _, err := buildFinished(b); err != nil {
+		return httperr.Server(err)
+	}
+
+	return nil
+}
+
+// buildFinished returns true if the build has stopped along with an error if it did not succeed
+func buildFinished(b *structs.Build) (bool, error) {
+	switch b.Status {
+	case "complete":
+		return true, nil
+	case "error", "failed":
+		return true, fmt.Errorf("%s build failed", b.App)
+	case "timeout":
+		return true, fmt.Errorf("%s build timeout", b.App)
+	case "cancelled":
+		return true, fmt.Errorf("%s build cancelled", b.App)
+	}
+
+	return false, nil
+}
+
 // try to find the docker host that's running a build
 // try a few times with a sleep
 func findBuildHost(build string) (string, error) {
@@ -3,14 +3,21 @@ package controllers
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/convox/rack/api/models"
 	"github.com/convox/rack/api/structs"
 	"github.com/convox/rack/provider"
 	"github.com/convox/rack/test"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
 )
 
 func init() {
@@ -86,3 +93,342 @@ func TestBuildDeleteActive(t *testing.T) {
 		assert.Equal(t, "cannot delete build contained in active release", resp["error"])
 	}
 }
+
+func TestBuildCancel(t *testing.T) {
+	models.TestProvider = &provider.TestProvider{
+		Build: structs.Build{
+			Id:     "build-id",
+			Status: "cancelled",
+		},
+	}
+
+	models.TestProvider.On("BuildGet", "app-name", "build-id").Return(&models.TestProvider.Build, nil)
+	models.TestProvider.On("BuildCancel", "app-name", "build-id").Return(&models.TestProvider.Build, nil)
+
+	body := test.HTTPBody("POST", "http://convox/apps/app-name/builds/build-id/cancel", nil)
+
+	models.TestProvider.AssertExpectations(t)
+
+	resp := new(structs.Build)
+	err := json.Unmarshal([]byte(body), resp)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "build-id", resp.Id)
+		assert.Equal(t, "cancelled", resp.Status)
+	}
+}
+
+func TestBuildCancelFinished(t *testing.T) {
+	models.TestProvider = &provider.TestProvider{
+		Build: structs.Build{
+			Id:     "build-id",
+			Status: "complete",
+			Ended:  time.Now(),
+		},
+	}
+
+	models.TestProvider.On("BuildGet", "app-name", "build-id").Return(&models.TestProvider.Build, nil)
+
+	body := test.HTTPBody("POST", "http://convox/apps/app-name/builds/build-id/cancel", nil)
+
+	models.TestProvider.AssertExpectations(t)
+
+	resp := make(map[string]string)
+	err := json.Unmarshal([]byte(body), &resp)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "build has already finished: build-id", resp["error"])
+	}
+}
+
+func TestBuildUpdateCancelled(t *testing.T) {
+	models.TestProvider = &provider.TestProvider{
+		Build: structs.Build{
+			Id:     "build-id",
+			Status: "cancelled",
+		},
+	}
+
+	models.TestProvider.On("BuildGet", "app-name", "build-id").Return(&models.TestProvider.Build, nil)
+
+	v := url.Values{}
+	v.Add("status", "complete")
+	v.Add("manifest", "web:\n  image: httpd\n")
+
+	body := test.HTTPBody("PUT", "http://convox/apps/app-name/builds/build-id", v)
+
+	models.TestProvider.AssertExpectations(t)
+
+	resp := make(map[string]string)
+	err := json.Unmarshal([]byte(body), &resp)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "build has been cancelled: build-id", resp["error"])
+	}
+}
+
+func TestBuildRelease(t *testing.T) {
+	models.TestProvider = &provider.TestProvider{
+		Build: structs.Build{
+			Id:      "build-id",
+			Release: "release-id",
+			Status:  "complete",
+		},
+		Release: structs.Release{
+			Id:    "release-id",
+			Build: "build-id",
+		},
+	}
+
+	models.TestProvider.On("BuildGet", "app-name", "build-id").Return(&models.TestProvider.Build, nil)
+	models.TestProvider.On("ReleaseGet", "app-name", "release-id").Return(&models.TestProvider.Release, nil)
+
+	body := test.HTTPBody("POST", "http://convox/apps/app-name/builds/build-id/release", nil)
+
+	models.TestProvider.AssertExpectations(t)
+
+	resp := new(structs.Release)
+	err := json.Unmarshal([]byte(body), resp)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "release-id", resp.Id)
+		assert.Equal(t, "build-id", resp.Build)
+	}
+}
+
+func TestBuildReleaseIncomplete(t *testing.T) {
+	models.TestProvider = &provider.TestProvider{
+		Build: structs.Build{
+			Id:     "build-id",
+			Status: "running",
+		},
+	}
+
+	models.TestProvider.On("BuildGet", "app-name", "build-id").Return(&models.TestProvider.Build, nil)
+
+	body := test.HTTPBody("POST", "http://convox/apps/app-name/builds/build-id/release", nil)
+
+	models.TestProvider.AssertExpectations(t)
+
+	resp := make(map[string]string)
+	err := json.Unmarshal([]byte(body), &resp)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "build is not complete: build-id", resp["error"])
+	}
+}
+
+func TestBuildCreateInvalidPriority(t *testing.T) {
+	models.TestProvider = &provider.TestProvider{}
+
+	v := url.Values{}
+	v.Add("repo", "https://example.org/app.git")
+	v.Add("priority", "urgent")
+
+	body := test.HTTPBody("POST", "http://convox/apps/app-name/builds", v)
+
+	models.TestProvider.AssertExpectations(t)
+
+	resp := make(map[string]string)
+	err := json.Unmarshal([]byte(body), &resp)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "priority must be one of: high, normal, low", resp["error"])
+	}
+}
+
+func TestBuildCreateInvalidConcurrency(t *testing.T) {
+	models.TestProvider = &provider.TestProvider{}
+
+	v := url.Values{}
+	v.Add("repo", "https://example.org/app.git")
+	v.Add("concurrency", "0")
+
+	body := test.HTTPBody("POST", "http://convox/apps/app-name/builds", v)
+
+	models.TestProvider.AssertExpectations(t)
+
+	resp := make(map[string]string)
+	err := json.Unmarshal([]byte(body), &resp)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "concurrency must be a positive number", resp["error"])
+		assert.Equal(t, "concurrency", resp["field"])
+	}
+}
+
+func TestBuildCreateInvalidStrict(t *testing.T) {
+	models.TestProvider = &provider.TestProvider{}
+
+	v := url.Values{}
+	v.Add("repo", "https://example.org/app.git")
+	v.Add("strict", "apt-cache,latest")
+
+	body := test.HTTPBody("POST", "http://convox/apps/app-name/builds", v)
+
+	models.TestProvider.AssertExpectations(t)
+
+	resp := make(map[string]string)
+	err := json.Unmarshal([]byte(body), &resp)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "unknown lint rule: latest", resp["error"])
+		assert.Equal(t, "strict", resp["field"])
+	}
+}
+
+func TestBuildCreateInvalidBuildArgs(t *testing.T) {
+	models.TestProvider = &provider.TestProvider{}
+
+	v := url.Values{}
+	v.Add("repo", "https://example.org/app.git")
+	v.Add("build-args", "VERSION=1.2\nTOKEN")
+
+	body := test.HTTPBody("POST", "http://convox/apps/app-name/builds", v)
+
+	models.TestProvider.AssertExpectations(t)
+
+	resp := make(map[string]string)
+	err := json.Unmarshal([]byte(body), &resp)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "build arg must be KEY=VALUE: TOKEN", resp["error"])
+		assert.Equal(t, "build-args", resp["field"])
+	}
+}
+
+func TestBuildCreateInvalidMatrix(t *testing.T) {
+	models.TestProvider = &provider.TestProvider{}
+
+	v := url.Values{}
+	v.Add("repo", "https://example.org/app.git")
+	v.Add("matrix", "arch=amd64,ppc64le")
+
+	body := test.HTTPBody("POST", "http://convox/apps/app-name/builds", v)
+
+	models.TestProvider.AssertExpectations(t)
+
+	resp := make(map[string]string)
+	err := json.Unmarshal([]byte(body), &resp)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "architecture must be one of: amd64, arm64", resp["error"])
+		assert.Equal(t, "matrix", resp["field"])
+	}
+}
+
+func TestBuildExportIncomplete(t *testing.T) {
+	aws := test.StubAws(
+		test.DescribeAppStackCycle("convox-test-bar"),
+	)
+	defer aws.Close()
+
+	models.TestProvider = &provider.TestProvider{
+		Build: structs.Build{
+			Id:     "build-id",
+			Status: "running",
+		},
+	}
+
+	models.TestProvider.On("BuildGet", "bar", "build-id").Return(&models.TestProvider.Build, nil)
+
+	body := test.HTTPBody("GET", "http://convox/apps/bar/builds/build-id/export", nil)
+
+	models.TestProvider.AssertExpectations(t)
+
+	assert.Equal(t, `{"error":"build is not complete: build-id"}`, body)
+}
+
+func TestBuildImportMissingArchive(t *testing.T) {
+	aws := test.StubAws(
+		test.DescribeAppStackCycle("convox-test-bar"),
+	)
+	defer aws.Close()
+
+	models.TestProvider = &provider.TestProvider{}
+
+	body := test.HTTPBody("POST", "http://convox/apps/bar/builds/import", url.Values{})
+
+	models.TestProvider.AssertExpectations(t)
+
+	assert.Equal(t, `{"error":"no build archive"}`, body)
+}
+
+// buildLogs reads everything the build logs websocket sends
+func buildLogs(t *testing.T, app, build string) string {
+	ts := httptest.NewServer(NewRouter())
+	defer ts.Close()
+
+	config, err := websocket.NewConfig(fmt.Sprintf("ws%s/apps/%s/builds/%s/logs", strings.TrimPrefix(ts.URL, "http"), app, build), ts.URL)
+	require.Nil(t, err)
+
+	config.Header.Set("Version", "dev")
+
+	ws, err := websocket.DialConfig(config)
+	require.Nil(t, err)
+
+	defer ws.Close()
+
+	data, err := ioutil.ReadAll(ws)
+	require.Nil(t, err)
+
+	return string(data)
+}
+
+func TestBuildLogsFinished(t *testing.T) {
+	models.TestProvider = &provider.TestProvider{
+		Build: structs.Build{
+			Id:     "build-id",
+			App:    "app-name",
+			Logs:   "building...\n",
+			Status: "complete",
+			Ended:  time.Now(),
+		},
+	}
+
+	models.TestProvider.On("BuildGet", "app-name", "build-id").Return(&models.TestProvider.Build, nil)
+	models.TestProvider.On("BuildLogs", "app-name", "build-id").Return("building...\n", nil)
+
+	// the saved output is sent without looking for the build container
+	assert.Equal(t, "building...\n", buildLogs(t, "app-name", "build-id"))
+
+	models.TestProvider.AssertExpectations(t)
+}
+
+func TestBuildLogsFinishedFailed(t *testing.T) {
+	for status, message := range map[string]string{
+		"failed":    "app-name build failed",
+		"timeout":   "app-name build timeout",
+		"cancelled": "app-name build cancelled",
+	} {
+		models.TestProvider = &provider.TestProvider{
+			Build: structs.Build{
+				Id:     "build-id",
+				App:    "app-name",
+				Logs:   "building...\n",
+				Status: status,
+				Ended:  time.Now(),
+			},
+		}
+
+		models.TestProvider.On("BuildGet", "app-name", "build-id").Return(&models.TestProvider.Build, nil)
+		models.TestProvider.On("BuildLogs", "app-name", "build-id").Return("building...\n", nil)
+
+		assert.Equal(t, fmt.Sprintf("building...\nERROR: %s\n", message), buildLogs(t, "app-name", "build-id"), status)
+	}
+}
+
+func TestBuildFinished(t *testing.T) {
+	for status, message := range map[string]string{
+		"complete":  "",
+		"error":     "app-name build failed",
+		"failed":    "app-name build failed",
+		"timeout":   "app-name build timeout",
+		"cancelled": "app-name build cancelled",
+	} {
+		done, err := buildFinished(&structs.Build{App: "app-name", Status: status})
+		assert.True(t, done, status)
+
+		if message == "" {
+			assert.Nil(t, err, status)
+		} else {
+			assert.EqualError(t, err, message, status)
+		}
+	}
+
+	for _, status := range []string{"created", "running"} {
+		done, err := buildFinished(&structs.Build{App: "app-name", Status: status})
+		assert.False(t, done, status)
+		assert.Nil(t, err, status)
+	}
+}
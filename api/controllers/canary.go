@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/convox/rack/api/httperr"
+	"github.com/convox/rack/api/models"
+	"github.com/gorilla/mux"
+)
+
+func CanaryShow(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	c, err := models.GetCanary(app)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil && strings.HasPrefix(err.Error(), "no canary") {
+		return httperr.Errorf(404, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, c)
+}
+
+func CanaryCreate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	vars := mux.Vars(r)
+	app := vars["app"]
+	release := vars["release"]
+
+	percent, err := strconv.Atoi(strings.TrimSuffix(r.FormValue("percent"), "%"))
+	if err != nil {
+		return httperr.Errorf(403, "invalid percent: %s", r.FormValue("percent"))
+	}
+
+	c, err := models.StartCanary(app, release, percent)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil && strings.HasPrefix(err.Error(), "no such release") {
+		return httperr.Errorf(404, "no such release: %s", release)
+	}
+	if err != nil {
+		return httperr.Errorf(403, "%s", err)
+	}
+
+	return RenderJson(rw, c)
+}
+
+func CanaryFinalize(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	c, err := models.GetCanary(app)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil && strings.HasPrefix(err.Error(), "no canary") {
+		return httperr.Errorf(404, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	rr, err := c.Finalize()
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(403, "%s", err.(awserr.Error).Message())
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	a, err := models.GetApp(app)
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	if err := a.RecordReleasePromotion(rr, requestUser(r)); err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, rr)
+}
+
+func CanaryAbort(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	c, err := models.GetCanary(app)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil && strings.HasPrefix(err.Error(), "no canary") {
+		return httperr.Errorf(404, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	if err := c.Abort(); err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderSuccess(rw)
+}
@@ -38,6 +38,21 @@ func CertificateDelete(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 func CertificateGenerate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 	domains := strings.Split(r.FormValue("domains"), ",")
 
+	if r.FormValue("letsencrypt") == "true" {
+		cert, err := models.GenerateACMECertificate(domains, r.FormValue("challenge"))
+		if err != nil {
+			for _, prefix := range []string{"acme: ", "challenge must", "must specify", "no route53 hosted zone"} {
+				if strings.HasPrefix(err.Error(), prefix) {
+					return httperr.Errorf(403, "%s", err)
+				}
+			}
+
+			return httperr.Server(err)
+		}
+
+		return RenderJson(rw, cert)
+	}
+
 	cert, err := models.Provider().CertificateGenerate(domains)
 
 	if err != nil {
@@ -58,3 +73,15 @@ func CertificateList(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 
 	return RenderJson(rw, certs)
 }
+
+// ACMEChallenge answers the http challenges of Let's Encrypt certificates the rack is issuing
+func ACMEChallenge(rw http.ResponseWriter, r *http.Request) {
+	keyAuth, err := models.ACMEChallengeResponse(mux.Vars(r)["token"])
+	if err != nil {
+		http.NotFound(rw, r)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "text/plain")
+	rw.Write([]byte(keyAuth))
+}
@@ -0,0 +1,42 @@
+package controllers_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/convox/rack/api/awsutil"
+	"github.com/convox/rack/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestACMEChallenge(t *testing.T) {
+	defer os.Setenv("SETTINGS_BUCKET", os.Getenv("SETTINGS_BUCKET"))
+	os.Setenv("SETTINGS_BUCKET", "convox-settings")
+
+	aws := test.StubAws(
+		awsutil.Cycle{
+			Request: awsutil.Request{
+				RequestURI: "/convox-settings/acme/challenges/token1",
+			},
+			Response: awsutil.Response{
+				StatusCode: 200,
+				Body:       "token1.thumbprint",
+			},
+		},
+		awsutil.Cycle{
+			Request: awsutil.Request{
+				RequestURI: "/convox-settings/acme/challenges/token2",
+			},
+			Response: awsutil.Response{
+				StatusCode: 404,
+				Body:       `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`,
+			},
+		},
+	)
+	defer aws.Close()
+
+	body := test.AssertStatus(t, 200, "GET", "http://convox/.well-known/acme-challenge/token1", nil)
+	assert.Equal(t, "token1.thumbprint", body)
+
+	test.AssertStatus(t, 404, "GET", "http://convox/.well-known/acme-challenge/token2", nil)
+}
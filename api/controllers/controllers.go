@@ -42,8 +42,44 @@ func GetForm(r *http.Request, name string) string {
 	}
 }
 
+// requestUser returns the user the CLI is acting on behalf of. Unless the request was
+// authenticated with a user key this is only asserted by the client, so it is good for the
+// rack's records but must not be used to make decisions, see requestIdentity.
+func requestUser(r *http.Request) string {
+	if user := requestIdentity(r); user != "" {
+		return user
+	}
+
+	if user := r.Header.Get("User"); user != "" {
+		return user
+	}
+
+	return "unknown"
+}
+
+// requestIdentity returns the user whose key authenticated the request, or an empty string
+// for requests authenticated with the rack password
+func requestIdentity(r *http.Request) string {
+	return r.Header.Get(authenticatedUserHeader)
+}
+
+// requirePassword denies a request that was not authenticated with the rack password
+func requirePassword(r *http.Request, action string) *httperr.Error {
+	if user := requestIdentity(r); user != "" {
+		return httperr.Errorf(403, "%s requires the rack password, %s authenticated with a user key", action, user)
+	}
+
+	return nil
+}
+
 func RenderError(rw http.ResponseWriter, err error) *httperr.Error {
-	rw.Write([]byte(fmt.Sprintf(`{"error":%q}`, err.Error())))
+	body := fmt.Sprintf(`{"error":%q}`, err.Error())
+
+	if he, ok := err.(*httperr.Error); ok && he.Field() != "" {
+		body = fmt.Sprintf(`{"error":%q,"field":%q}`, err.Error(), he.Field())
+	}
+
+	rw.Write([]byte(body))
 
 	return httperr.Server(err)
 }
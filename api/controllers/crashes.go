@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/convox/rack/api/httperr"
+	"github.com/convox/rack/api/models"
+	"github.com/gorilla/mux"
+)
+
+func CrashList(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	crashes, err := models.ListCrashes(app)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, crashes)
+}
+
+func CrashShow(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	vars := mux.Vars(r)
+	app := vars["app"]
+	crash := vars["crash"]
+
+	c, err := models.GetCrash(app, crash)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil && strings.HasPrefix(err.Error(), "no such crash") {
+		return httperr.Errorf(404, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, c)
+}
+
+// CrashDump streams the tar archive of the dump captured along with a crash
+func CrashDump(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	vars := mux.Vars(r)
+	app := vars["app"]
+	crash := vars["crash"]
+
+	c, err := models.GetCrash(app, crash)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil && strings.HasPrefix(err.Error(), "no such crash") {
+		return httperr.Errorf(404, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	if c.Dump == "" {
+		return httperr.Errorf(404, "crash has no dump: %s", crash)
+	}
+
+	rw.Header().Set("Content-Type", "application/x-tar")
+	rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.tar", c.Id))
+
+	if err := models.CrashDump(app, crash, rw); err != nil {
+		return httperr.Server(err)
+	}
+
+	return nil
+}
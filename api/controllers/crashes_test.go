@@ -0,0 +1,75 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/convox/rack/api/awsutil"
+	"github.com/convox/rack/api/models"
+	"github.com/convox/rack/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCrashShow(t *testing.T) {
+	aws := test.StubAws(
+		test.DescribeAppStackCycle("convox-test-bar"),
+		awsutil.Cycle{
+			Request: awsutil.Request{
+				RequestURI: "/apache-app-settings-2gkjc9lf123nm/crashes/0123456789ab.json",
+			},
+			Response: awsutil.Response{
+				StatusCode: 200,
+				Body:       `{"id":"0123456789ab","app":"bar","process":"web","exit-code":2,"output":"panic: boom\n"}`,
+			},
+		},
+	)
+	defer aws.Close()
+
+	body := test.HTTPBody("GET", "http://convox/apps/bar/crashes/0123456789ab", nil)
+
+	var crash models.Crash
+
+	if assert.Nil(t, json.Unmarshal([]byte(body), &crash)) {
+		assert.Equal(t, "web", crash.Process)
+		assert.Equal(t, 2, crash.ExitCode)
+		assert.Equal(t, "panic: boom\n", crash.Output)
+	}
+}
+
+func TestCrashShowNotFound(t *testing.T) {
+	aws := test.StubAws(
+		test.DescribeAppStackCycle("convox-test-bar"),
+		awsutil.Cycle{
+			Request: awsutil.Request{
+				RequestURI: "/apache-app-settings-2gkjc9lf123nm/crashes/0123456789ab.json",
+			},
+			Response: awsutil.Response{
+				StatusCode: 404,
+				Body:       `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`,
+			},
+		},
+	)
+	defer aws.Close()
+
+	body := test.AssertStatus(t, 404, "GET", "http://convox/apps/bar/crashes/0123456789ab", nil)
+	assert.Equal(t, "{\"error\":\"no such crash: 0123456789ab\"}", body)
+}
+
+func TestCrashDumpWithoutDump(t *testing.T) {
+	aws := test.StubAws(
+		test.DescribeAppStackCycle("convox-test-bar"),
+		awsutil.Cycle{
+			Request: awsutil.Request{
+				RequestURI: "/apache-app-settings-2gkjc9lf123nm/crashes/0123456789ab.json",
+			},
+			Response: awsutil.Response{
+				StatusCode: 200,
+				Body:       `{"id":"0123456789ab","app":"bar","process":"web","exit-code":2}`,
+			},
+		},
+	)
+	defer aws.Close()
+
+	body := test.AssertStatus(t, 404, "GET", "http://convox/apps/bar/crashes/0123456789ab/dump", nil)
+	assert.Equal(t, "{\"error\":\"crash has no dump: 0123456789ab\"}", body)
+}
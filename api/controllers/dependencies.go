@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/convox/rack/api/httperr"
+	"github.com/convox/rack/api/models"
+	"github.com/gorilla/mux"
+)
+
+func DependencyList(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	deps, err := models.GetDependencies()
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, deps)
+}
+
+func DependencyCreate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	_, err := models.GetApp(app)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	on := []string{}
+
+	for _, name := range strings.Split(GetForm(r, "on"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			on = append(on, name)
+		}
+	}
+
+	if len(on) == 0 {
+		return httperr.Invalid("on", "must specify at least one app or resource")
+	}
+
+	for _, name := range on {
+		exists, err := dependencyExists(name)
+		if err != nil {
+			return httperr.Server(err)
+		}
+		if !exists {
+			return httperr.Errorf(404, "no such app or resource: %s", name)
+		}
+	}
+
+	deps, err := models.GetDependencies()
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	if err := deps.Add(app, on); err != nil {
+		return httperr.Errorf(403, "%s", err)
+	}
+
+	if err := deps.Save(); err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, deps)
+}
+
+func DependencyDelete(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	vars := mux.Vars(r)
+	app := vars["app"]
+	dependency := vars["dependency"]
+
+	deps, err := models.GetDependencies()
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	if err := deps.Remove(app, dependency); err != nil {
+		return httperr.Errorf(404, "%s", err)
+	}
+
+	if err := deps.Save(); err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, deps)
+}
+
+func DependencyImpact(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	name := mux.Vars(r)["name"]
+
+	deps, err := models.GetDependencies()
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, deps.Dependents(name))
+}
+
+// dependencyExists returns true if name is an app or a resource on this rack
+func dependencyExists(name string) (bool, error) {
+	_, err := models.GetApp(name)
+	if err == nil {
+		return true, nil
+	}
+	if awsError(err) != "ValidationError" {
+		return false, err
+	}
+
+	_, err = models.Provider().ServiceGet(name)
+	if err == nil {
+		return true, nil
+	}
+	if awsError(err) != "ValidationError" {
+		return false, err
+	}
+
+	return false, nil
+}
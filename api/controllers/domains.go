@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/convox/rack/api/httperr"
+	"github.com/convox/rack/api/models"
+	"github.com/gorilla/mux"
+)
+
+func DomainList(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	domains, err := models.ListDomains(app)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, domains)
+}
+
+func DomainAdd(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	d, err := models.AddDomain(app, GetForm(r, "domain"), GetForm(r, "process"))
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil {
+		for _, prefix := range []string{"invalid domain", "domain already", "process must", "no route53 hosted zone", "route53 "} {
+			if strings.HasPrefix(err.Error(), prefix) {
+				return httperr.Errorf(403, "%s", err)
+			}
+		}
+
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, d)
+}
+
+func DomainRemove(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	vars := mux.Vars(r)
+	app := vars["app"]
+	domain := vars["domain"]
+
+	err := models.RemoveDomain(app, domain)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil && strings.HasPrefix(err.Error(), "no such domain") {
+		return httperr.Errorf(404, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderSuccess(rw)
+}
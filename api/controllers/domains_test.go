@@ -0,0 +1,72 @@
+package controllers_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/convox/rack/api/awsutil"
+	"github.com/convox/rack/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomainAddInvalid(t *testing.T) {
+	body := test.AssertStatus(t, 403, "POST", "http://convox/apps/bar/domains", url.Values{"domain": {"not a domain"}})
+	assert.Equal(t, `{"error":"invalid domain: not a domain"}`, body)
+}
+
+func TestDomainAddExisting(t *testing.T) {
+	aws := test.StubAws(
+		test.DescribeAppStackCycle("convox-test-bar"),
+		awsutil.Cycle{
+			Request: awsutil.Request{
+				RequestURI: "/apache-app-settings-2gkjc9lf123nm/domains/example.org.json",
+			},
+			Response: awsutil.Response{
+				StatusCode: 200,
+				Body:       `{"domain":"example.org","app":"bar","process":"web","status":"active"}`,
+			},
+		},
+	)
+	defer aws.Close()
+
+	body := test.AssertStatus(t, 403, "POST", "http://convox/apps/bar/domains", url.Values{"domain": {"Example.org."}})
+	assert.Equal(t, `{"error":"domain already added: example.org"}`, body)
+}
+
+func TestDomainAddWithoutBalancer(t *testing.T) {
+	aws := test.StubAws(
+		test.DescribeAppStackCycle("convox-test-bar"),
+		awsutil.Cycle{
+			Request: awsutil.Request{
+				RequestURI: "/apache-app-settings-2gkjc9lf123nm/domains/example.org.json",
+			},
+			Response: awsutil.Response{
+				StatusCode: 404,
+				Body:       `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`,
+			},
+		},
+	)
+	defer aws.Close()
+
+	body := test.AssertStatus(t, 403, "POST", "http://convox/apps/bar/domains", url.Values{"domain": {"example.org"}})
+	assert.Equal(t, `{"error":"process must have a load balancer, app has none"}`, body)
+}
+
+func TestDomainRemoveNotFound(t *testing.T) {
+	aws := test.StubAws(
+		test.DescribeAppStackCycle("convox-test-bar"),
+		awsutil.Cycle{
+			Request: awsutil.Request{
+				RequestURI: "/apache-app-settings-2gkjc9lf123nm/domains/example.org.json",
+			},
+			Response: awsutil.Response{
+				StatusCode: 404,
+				Body:       `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`,
+			},
+		},
+	)
+	defer aws.Close()
+
+	body := test.AssertStatus(t, 404, "DELETE", "http://convox/apps/bar/domains/example.org", nil)
+	assert.Equal(t, `{"error":"no such domain: example.org"}`, body)
+}
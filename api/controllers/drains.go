@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/convox/rack/api/httperr"
+	"github.com/convox/rack/api/models"
+	"github.com/gorilla/mux"
+)
+
+func DrainList(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	drains, err := models.ListDrains(app)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, drains)
+}
+
+func DrainCreate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	_, err := models.GetApp(app)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	d := models.NewDrain(app, r.FormValue("url"))
+
+	if err := d.Validate(); err != nil {
+		return httperr.Errorf(403, "%s", err)
+	}
+
+	if err := d.Save(); err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, d)
+}
+
+func DrainDelete(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	vars := mux.Vars(r)
+	app := vars["app"]
+	drain := vars["drain"]
+
+	d, err := models.GetDrain(app, drain)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil && strings.HasPrefix(err.Error(), "no such drain") {
+		return httperr.Errorf(404, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	if err := d.Delete(); err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, d)
+}
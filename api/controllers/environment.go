@@ -3,6 +3,7 @@ package controllers
 import (
 	"io/ioutil"
 	"net/http"
+	"strings"
 
 	"github.com/convox/rack/api/httperr"
 	"github.com/convox/rack/api/models"
@@ -34,17 +35,18 @@ func EnvironmentSet(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 		return httperr.Errorf(404, "no such app: %s", app)
 	}
 
-	body, err := ioutil.ReadAll(r.Body)
-	if err != nil {
-		return httperr.Server(err)
+	if err := immutable("environment.set", r, app, "changing the environment"); err != nil {
+		return err
 	}
 
-	releaseID, err := models.PutEnvironment(app, models.LoadEnvironment(body))
+	body, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		return httperr.Server(err)
 	}
 
-	rw.Header().Set("Release-Id", releaseID)
+	if err := putEnvironment(rw, r, app, models.LoadEnvironment(body)); err != nil {
+		return err
+	}
 
 	env, err := models.GetEnvironment(app)
 	if err != nil {
@@ -69,21 +71,63 @@ func EnvironmentDelete(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 		return httperr.Server(err)
 	}
 
+	if err := immutable("environment.delete", r, app, "changing the environment"); err != nil {
+		return err
+	}
+
 	delete(env, name)
 
-	releaseID, err := models.PutEnvironment(app, env)
+	if err := putEnvironment(rw, r, app, env); err != nil {
+		return err
+	}
+
+	env, err = models.GetEnvironment(app)
 
 	if err != nil {
 		return httperr.Server(err)
 	}
 
-	rw.Header().Set("Release-Id", releaseID)
+	return RenderJson(rw, env)
+}
 
-	env, err = models.GetEnvironment(app)
+// EnvironmentCommit creates a release carrying the staged environment of an app
+func EnvironmentCommit(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	if err := immutable("environment.commit", r, app, "changing the environment"); err != nil {
+		return err
+	}
 
+	release, err := models.CommitEnvironment(app, requestUser(r))
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil && strings.HasPrefix(err.Error(), "no staged environment changes") {
+		return httperr.Errorf(403, "%s", err)
+	}
 	if err != nil {
 		return httperr.Server(err)
 	}
 
-	return RenderJson(rw, env)
+	return RenderJson(rw, release)
+}
+
+// putEnvironment stores env and releases it unless the request asks to only stage it
+func putEnvironment(rw http.ResponseWriter, r *http.Request, app string, env models.Environment) *httperr.Error {
+	if r.URL.Query().Get("release") == "false" {
+		if err := models.StageEnvironment(app, env); err != nil {
+			return httperr.Server(err)
+		}
+
+		return nil
+	}
+
+	releaseID, err := models.PutEnvironment(app, env, requestUser(r))
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	rw.Header().Set("Release-Id", releaseID)
+
+	return nil
 }
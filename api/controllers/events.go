@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/convox/rack/api/httperr"
+	"github.com/convox/rack/api/models"
+)
+
+// SystemEvents lists the stored events of the rack, newest first
+func SystemEvents(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	filter := models.RackEventFilter{
+		App:   r.URL.Query().Get("app"),
+		Type:  r.URL.Query().Get("type"),
+		Limit: 100,
+	}
+
+	if filter.Type != "" && !validEventType(filter.Type) {
+		return httperr.Errorf(403, "type must be one of %s", strings.Join(models.RackEventTypes, ", "))
+	}
+
+	if since := r.URL.Query().Get("since"); since != "" {
+		d, err := parseSince(since)
+		if err != nil {
+			return httperr.Errorf(403, "since must be a duration like 24h or 7d: %s", since)
+		}
+
+		filter.Since = time.Now().Add(-d)
+	}
+
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil || l < 1 {
+			return httperr.Errorf(403, "limit must be a positive number: %s", limit)
+		}
+
+		filter.Limit = l
+	}
+
+	events, err := models.ListRackEvents(filter)
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, events)
+}
+
+// parseSince accepts a number of days like 7d as well as the durations understood by time.ParseDuration
+func parseSince(since string) (time.Duration, error) {
+	if strings.HasSuffix(since, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(since, "d"))
+		if err != nil || days < 0 {
+			return 0, fmt.Errorf("invalid duration: %s", since)
+		}
+
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+
+	return time.ParseDuration(since)
+}
+
+func validEventType(t string) bool {
+	for _, et := range models.RackEventTypes {
+		if t == et {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,11 +1,15 @@
 package controllers
 
 import (
+	"fmt"
 	"net/http"
+	"os"
 	"strconv"
 
+	"github.com/convox/logger"
 	"github.com/convox/rack/api/httperr"
 	"github.com/convox/rack/api/models"
+	"github.com/convox/rack/api/structs"
 	"github.com/gorilla/mux"
 )
 
@@ -30,11 +34,13 @@ func FormationSet(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 		return httperr.Server(err)
 	}
 
+	before := *pf
+
 	// update based on form input
 	if cc := GetForm(r, "count"); cc != "" {
 		c, err := strconv.Atoi(cc)
 		if err != nil {
-			return httperr.Errorf(403, "count must be numeric")
+			return httperr.Invalid("count", "count must be numeric")
 		}
 
 		switch {
@@ -51,7 +57,7 @@ func FormationSet(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 	if cc := GetForm(r, "cpu"); cc != "" {
 		c, err := strconv.Atoi(cc)
 		if err != nil {
-			return httperr.Errorf(403, "cpu must be numeric")
+			return httperr.Invalid("cpu", "cpu must be numeric")
 		}
 
 		switch {
@@ -65,7 +71,7 @@ func FormationSet(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 	if mm := GetForm(r, "memory"); mm != "" {
 		m, err := strconv.Atoi(mm)
 		if err != nil {
-			return httperr.Errorf(403, "memory must be numeric")
+			return httperr.Invalid("memory", "memory must be numeric")
 		}
 
 		switch {
@@ -76,10 +82,97 @@ func FormationSet(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 		}
 	}
 
+	switch GetForm(r, "autoscale") {
+	case "true":
+		as := structs.ProcessAutoscale{}
+
+		if pf.Autoscale != nil {
+			as = *pf.Autoscale
+		}
+
+		for _, o := range []struct {
+			name  string
+			field *int
+		}{
+			{"autoscale-min", &as.Min},
+			{"autoscale-max", &as.Max},
+			{"autoscale-cpu", &as.CPU},
+		} {
+			if v := GetForm(r, o.name); v != "" {
+				n, err := strconv.Atoi(v)
+				if err != nil {
+					return httperr.Invalid(o.name, "%s must be numeric", o.name)
+				}
+
+				*o.field = n
+			}
+		}
+
+		pf.Autoscale = &as
+	case "false":
+		pf.Autoscale = nil
+	}
+
+	if err := formationCapacity(before, *pf, GetForm(r, "grow") == "true"); err != nil {
+		return err
+	}
+
 	err = models.Provider().FormationSave(app, pf)
 	if err != nil {
 		return httperr.Server(err)
 	}
 
+	data := map[string]string{
+		"app":     app,
+		"process": process,
+		"count":   strconv.Itoa(pf.Count),
+		"cpu":     strconv.Itoa(pf.CPU),
+		"memory":  strconv.Itoa(pf.Memory),
+	}
+
+	if pf.Autoscale != nil {
+		data["autoscale"] = fmt.Sprintf("%d-%d at %d%% cpu", pf.Autoscale.Min, pf.Autoscale.Max, pf.Autoscale.CPU)
+	}
+
+	if err := models.RecordRackEvent("release:scale", "success", data); err != nil {
+		logger.New("ns=api.controllers").At("FormationSet").Error(err)
+	}
+
 	return RenderSuccess(rw)
 }
+
+// formationCapacity makes sure a formation change fits on the rack's instances.
+// When grow is set the rack instance count is raised to make room instead of refusing the change.
+func formationCapacity(before, after structs.ProcessFormation, grow bool) *httperr.Error {
+	// the autoscaler adds instances as needed
+	if os.Getenv("AUTOSCALE") == "true" {
+		return nil
+	}
+
+	capacity, err := models.Provider().CapacityGet()
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	ce := capacity.FormationFit(before, after)
+	if ce == nil {
+		return nil
+	}
+
+	if !grow {
+		return httperr.New(403, ce)
+	}
+
+	rack, err := models.Provider().SystemGet()
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	rack.Count += ce.Instances
+
+	if err := models.Provider().SystemSave(*rack); err != nil {
+		return httperr.Server(err)
+	}
+
+	return nil
+}
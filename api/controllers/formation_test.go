@@ -54,6 +54,7 @@ func TestFormationSetAll(t *testing.T) {
 		after := &structs.ProcessFormation{Name: "web", Count: 4, CPU: 200, Memory: 300, Ports: []int{3000, 3001}}
 
 		models.TestProvider.On("FormationGet", "myapp", "web").Return(before, nil)
+		models.TestProvider.On("CapacityGet").Return(&structs.Capacity{}, nil)
 		models.TestProvider.On("FormationSave", "myapp", after).Return(nil)
 
 		hf := test.NewHandlerFunc(controllers.HandlerFunc)
@@ -76,6 +77,7 @@ func TestFormationSetOne(t *testing.T) {
 		after := &structs.ProcessFormation{Name: "web", Count: 2, CPU: 200, Memory: 1024, Ports: []int{3000, 3001}}
 
 		models.TestProvider.On("FormationGet", "myapp", "web").Return(before, nil)
+		models.TestProvider.On("CapacityGet").Return(&structs.Capacity{}, nil)
 		models.TestProvider.On("FormationSave", "myapp", after).Return(nil)
 
 		hf := test.NewHandlerFunc(controllers.HandlerFunc)
@@ -90,6 +92,70 @@ func TestFormationSetOne(t *testing.T) {
 	})
 }
 
+func TestFormationSetAutoscale(t *testing.T) {
+	models.Test(t, func() {
+		before := &structs.ProcessFormation{Name: "web", Count: 2, CPU: 128, Memory: 1024, Ports: []int{3000, 3001}}
+		after := &structs.ProcessFormation{Name: "web", Count: 2, CPU: 128, Memory: 1024, Ports: []int{3000, 3001}, Autoscale: &structs.ProcessAutoscale{Min: 2, Max: 10, CPU: 70}}
+
+		models.TestProvider.On("FormationGet", "myapp", "web").Return(before, nil)
+		models.TestProvider.On("CapacityGet").Return(&structs.Capacity{}, nil)
+		models.TestProvider.On("FormationSave", "myapp", after).Return(nil)
+
+		hf := test.NewHandlerFunc(controllers.HandlerFunc)
+
+		v := url.Values{}
+		v.Add("autoscale", "true")
+		v.Add("autoscale-min", "2")
+		v.Add("autoscale-max", "10")
+		v.Add("autoscale-cpu", "70")
+
+		if assert.Nil(t, hf.Request("POST", "/apps/myapp/formation/web", v)) {
+			hf.AssertCode(t, 200)
+			hf.AssertSuccess(t)
+		}
+	})
+}
+
+func TestFormationSetAutoscaleOff(t *testing.T) {
+	models.Test(t, func() {
+		before := &structs.ProcessFormation{Name: "web", Count: 4, CPU: 128, Memory: 1024, Ports: []int{3000, 3001}, Autoscale: &structs.ProcessAutoscale{Min: 2, Max: 10, CPU: 70}}
+		after := &structs.ProcessFormation{Name: "web", Count: 4, CPU: 128, Memory: 1024, Ports: []int{3000, 3001}}
+
+		models.TestProvider.On("FormationGet", "myapp", "web").Return(before, nil)
+		models.TestProvider.On("CapacityGet").Return(&structs.Capacity{}, nil)
+		models.TestProvider.On("FormationSave", "myapp", after).Return(nil)
+
+		hf := test.NewHandlerFunc(controllers.HandlerFunc)
+
+		v := url.Values{}
+		v.Add("autoscale", "false")
+
+		if assert.Nil(t, hf.Request("POST", "/apps/myapp/formation/web", v)) {
+			hf.AssertCode(t, 200)
+			hf.AssertSuccess(t)
+		}
+	})
+}
+
+func TestFormationSetAutoscaleInvalid(t *testing.T) {
+	models.Test(t, func() {
+		before := &structs.ProcessFormation{Name: "web", Count: 2, CPU: 128, Memory: 1024, Ports: []int{3000, 3001}}
+
+		models.TestProvider.On("FormationGet", "myapp", "web").Return(before, nil)
+
+		hf := test.NewHandlerFunc(controllers.HandlerFunc)
+
+		v := url.Values{}
+		v.Add("autoscale", "true")
+		v.Add("autoscale-max", "lots")
+
+		if assert.Nil(t, hf.Request("POST", "/apps/myapp/formation/web", v)) {
+			hf.AssertCode(t, 403)
+			hf.AssertError(t, "autoscale-max must be numeric")
+		}
+	})
+}
+
 func TestFormationSetFailedGet(t *testing.T) {
 	models.Test(t, func() {
 		models.TestProvider.On("FormationGet", "myapp", "web").Return(nil, fmt.Errorf("could not fetch"))
@@ -114,6 +180,7 @@ func TestFormationSetFailedSave(t *testing.T) {
 		after := &structs.ProcessFormation{Name: "web", Count: 4, CPU: 200, Memory: 300, Ports: []int{3000, 3001}}
 
 		models.TestProvider.On("FormationGet", "myapp", "web").Return(before, nil)
+		models.TestProvider.On("CapacityGet").Return(&structs.Capacity{}, nil)
 		models.TestProvider.On("FormationSave", "myapp", after).Return(fmt.Errorf("could not save"))
 
 		hf := test.NewHandlerFunc(controllers.HandlerFunc)
@@ -138,6 +205,7 @@ func TestFormationSetEdgeCases(t *testing.T) {
 		after := &structs.ProcessFormation{Name: "web", Count: 2, CPU: 200, Memory: 1024, Ports: []int{3000, 3001}}
 
 		models.TestProvider.On("FormationGet", "myapp", "web").Return(before, nil)
+		models.TestProvider.On("CapacityGet").Return(&structs.Capacity{}, nil)
 		models.TestProvider.On("FormationSave", "myapp", after).Return(nil)
 
 		hf := test.NewHandlerFunc(controllers.HandlerFunc)
@@ -159,6 +227,7 @@ func TestFormationSetEdgeCases(t *testing.T) {
 		after := &structs.ProcessFormation{Name: "web", Count: 2, CPU: 200, Memory: 1024, Ports: []int{3000, 3001}}
 
 		models.TestProvider.On("FormationGet", "myapp", "web").Return(before, nil)
+		models.TestProvider.On("CapacityGet").Return(&structs.Capacity{}, nil)
 		models.TestProvider.On("FormationSave", "myapp", after).Return(nil)
 
 		hf := test.NewHandlerFunc(controllers.HandlerFunc)
@@ -179,6 +248,7 @@ func TestFormationSetEdgeCases(t *testing.T) {
 		after := &structs.ProcessFormation{Name: "web", Count: 4, CPU: 128, Memory: 1024, Ports: []int{3000, 3001}}
 
 		models.TestProvider.On("FormationGet", "myapp", "web").Return(before, nil)
+		models.TestProvider.On("CapacityGet").Return(&structs.Capacity{}, nil)
 		models.TestProvider.On("FormationSave", "myapp", after).Return(nil)
 
 		hf := test.NewHandlerFunc(controllers.HandlerFunc)
@@ -199,6 +269,7 @@ func TestFormationSetEdgeCases(t *testing.T) {
 		after := &structs.ProcessFormation{Name: "web", Count: 4, CPU: 128, Memory: 1024, Ports: []int{3000, 3001}}
 
 		models.TestProvider.On("FormationGet", "myapp", "web").Return(before, nil)
+		models.TestProvider.On("CapacityGet").Return(&structs.Capacity{}, nil)
 		models.TestProvider.On("FormationSave", "myapp", after).Return(nil)
 
 		hf := test.NewHandlerFunc(controllers.HandlerFunc)
@@ -219,6 +290,7 @@ func TestFormationSetEdgeCases(t *testing.T) {
 		after := &structs.ProcessFormation{Name: "web", Count: 4, CPU: 128, Memory: 1024, Ports: []int{3000, 3001}}
 
 		models.TestProvider.On("FormationGet", "myapp", "web").Return(before, nil)
+		models.TestProvider.On("CapacityGet").Return(&structs.Capacity{}, nil)
 		models.TestProvider.On("FormationSave", "myapp", after).Return(nil)
 
 		hf := test.NewHandlerFunc(controllers.HandlerFunc)
@@ -247,7 +319,7 @@ func TestFormationSetNonNumeric(t *testing.T) {
 
 		if assert.Nil(t, hf.Request("POST", "/apps/myapp/formation/web", v)) {
 			hf.AssertCode(t, 403)
-			hf.AssertError(t, "count must be numeric")
+			hf.AssertJSON(t, `{"error":"count must be numeric","field":"count"}`)
 		}
 	})
 
@@ -283,3 +355,72 @@ func TestFormationSetNonNumeric(t *testing.T) {
 		}
 	})
 }
+
+func TestFormationSetInsufficientCapacity(t *testing.T) {
+	models.Test(t, func() {
+		before := &structs.ProcessFormation{Name: "web", Count: 2, CPU: 128, Memory: 1024}
+		capacity := &structs.Capacity{ClusterCPU: 2048, ClusterMemory: 4096, InstanceCPU: 1024, InstanceMemory: 2048, ProcessCPU: 256, ProcessMemory: 3072}
+
+		models.TestProvider.On("FormationGet", "myapp", "web").Return(before, nil)
+		models.TestProvider.On("CapacityGet").Return(capacity, nil)
+
+		hf := test.NewHandlerFunc(controllers.HandlerFunc)
+
+		v := url.Values{}
+		v.Add("count", "6")
+
+		if assert.Nil(t, hf.Request("POST", "/apps/myapp/formation/web", v)) {
+			hf.AssertCode(t, 403)
+			hf.AssertError(t, "insufficient cluster memory: 6144 required, 3072 available of 4096, 2 more instances needed")
+		}
+	})
+}
+
+func TestFormationSetScaleDownFullCluster(t *testing.T) {
+	models.Test(t, func() {
+		before := &structs.ProcessFormation{Name: "web", Count: 4, CPU: 128, Memory: 1024}
+		after := &structs.ProcessFormation{Name: "web", Count: 3, CPU: 128, Memory: 1024}
+		capacity := &structs.Capacity{ClusterCPU: 2048, ClusterMemory: 4096, InstanceCPU: 1024, InstanceMemory: 2048, ProcessCPU: 512, ProcessMemory: 6144}
+
+		models.TestProvider.On("FormationGet", "myapp", "web").Return(before, nil)
+		models.TestProvider.On("CapacityGet").Return(capacity, nil)
+		models.TestProvider.On("FormationSave", "myapp", after).Return(nil)
+
+		hf := test.NewHandlerFunc(controllers.HandlerFunc)
+
+		v := url.Values{}
+		v.Add("count", "3")
+
+		if assert.Nil(t, hf.Request("POST", "/apps/myapp/formation/web", v)) {
+			hf.AssertCode(t, 200)
+			hf.AssertSuccess(t)
+		}
+	})
+}
+
+func TestFormationSetGrow(t *testing.T) {
+	models.Test(t, func() {
+		before := &structs.ProcessFormation{Name: "web", Count: 2, CPU: 128, Memory: 1024}
+		after := &structs.ProcessFormation{Name: "web", Count: 6, CPU: 128, Memory: 1024}
+		capacity := &structs.Capacity{ClusterCPU: 2048, ClusterMemory: 4096, InstanceCPU: 1024, InstanceMemory: 2048, ProcessCPU: 256, ProcessMemory: 3072}
+		rack := &structs.System{Count: 2, Name: "test", Type: "t2.small", Version: "dev"}
+		grown := structs.System{Count: 4, Name: "test", Type: "t2.small", Version: "dev"}
+
+		models.TestProvider.On("FormationGet", "myapp", "web").Return(before, nil)
+		models.TestProvider.On("CapacityGet").Return(capacity, nil)
+		models.TestProvider.On("SystemGet").Return(rack, nil)
+		models.TestProvider.On("SystemSave", grown).Return(nil)
+		models.TestProvider.On("FormationSave", "myapp", after).Return(nil)
+
+		hf := test.NewHandlerFunc(controllers.HandlerFunc)
+
+		v := url.Values{}
+		v.Add("count", "6")
+		v.Add("grow", "true")
+
+		if assert.Nil(t, hf.Request("POST", "/apps/myapp/formation/web", v)) {
+			hf.AssertCode(t, 200)
+			hf.AssertSuccess(t)
+		}
+	})
+}
@@ -3,11 +3,13 @@ package controllers
 import (
 	"net/http"
 	"strconv"
+	"strings"
+	"sync"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/convox/rack/api/httperr"
 	"github.com/convox/rack/api/models"
+	"github.com/convox/rack/api/structs"
+	"github.com/convox/rack/client"
 	"github.com/gorilla/mux"
 	"golang.org/x/net/websocket"
 )
@@ -16,7 +18,7 @@ func init() {
 }
 
 func InstancesKeyroll(rw http.ResponseWriter, r *http.Request) *httperr.Error {
-	err := models.InstanceKeyroll()
+	err := models.Provider().InstanceKeyroll()
 	if err != nil {
 		return httperr.Server(err)
 	}
@@ -30,9 +32,50 @@ func InstancesList(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 		return httperr.Server(err)
 	}
 
+	if r.URL.Query().Get("stats") == "true" {
+		w := new(sync.WaitGroup)
+
+		for i := range instances {
+			w.Add(1)
+
+			// instances whose storage driver does not report usage are listed without disk stats
+			go func(i *structs.Instance) {
+				defer w.Done()
+
+				if disk, err := i.DiskUsage(); err == nil {
+					i.Disk = disk
+				}
+			}(&instances[i])
+		}
+
+		w.Wait()
+	}
+
 	return RenderJson(rw, instances)
 }
 
+// InstanceProcesses lists the containers of every app running on an instance
+func InstanceProcesses(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	id := mux.Vars(r)["id"]
+	stats := r.URL.Query().Get("stats") == "true"
+
+	processes, err := models.InstanceProcesses(id)
+	if err != nil && strings.HasPrefix(err.Error(), "no such instance") {
+		return httperr.Errorf(404, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	if stats {
+		if err := fetchProcessStats(processes); err != nil {
+			return httperr.Server(err)
+		}
+	}
+
+	return RenderJson(rw, processes)
+}
+
 func InstanceSSH(ws *websocket.Conn) *httperr.Error {
 	vars := mux.Vars(ws.Request())
 	id := vars["id"]
@@ -53,27 +96,21 @@ func InstanceSSH(ws *websocket.Conn) *httperr.Error {
 		}
 	}
 
-	return httperr.Server(models.InstanceSSH(id, cmd, term, height, width, ws))
+	if ws.Request().Header.Get(client.AgentForwardingHeader) == "true" {
+		agent := client.NewAgentMux(ws, nil)
+		return httperr.Server(models.InstanceSSH(id, cmd, term, height, width, agent, agent.Open))
+	}
+
+	return httperr.Server(models.InstanceSSH(id, cmd, term, height, width, ws, nil))
 }
 
 func InstanceTerminate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
-	rack, err := models.Provider().SystemGet()
+	id := mux.Vars(r)["id"]
 
-	if awsError(err) == "ValidationError" {
-		return httperr.Errorf(404, "no such stack: %s", rack)
+	err := models.Provider().InstanceTerminate(id)
+	if err != nil && strings.HasPrefix(err.Error(), "no such instance") {
+		return httperr.Errorf(404, "%s", err)
 	}
-
-	if err != nil {
-		return httperr.Server(err)
-	}
-
-	instanceId := mux.Vars(r)["id"]
-
-	_, err = models.AutoScaling().TerminateInstanceInAutoScalingGroup(&autoscaling.TerminateInstanceInAutoScalingGroupInput{
-		InstanceId:                     aws.String(instanceId),
-		ShouldDecrementDesiredCapacity: aws.Bool(false),
-	})
-
 	if err != nil {
 		return httperr.Server(err)
 	}
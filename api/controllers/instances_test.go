@@ -2,13 +2,14 @@ package controllers_test
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"testing"
 
 	"github.com/convox/rack/api/controllers"
 	"github.com/convox/rack/api/models"
 	"github.com/convox/rack/api/structs"
-	"github.com/convox/rack/client"
+	cmodels "github.com/convox/rack/client/models"
 	"github.com/convox/rack/provider"
 	"github.com/convox/rack/test"
 	"github.com/stretchr/testify/assert"
@@ -36,7 +37,7 @@ func TestInstanceList(t *testing.T) {
 
 	body := test.HTTPBody("GET", "http://convox/instances", nil)
 
-	var resp []client.Instance
+	var resp []cmodels.Instance
 
 	err := json.Unmarshal([]byte(body), &resp)
 
@@ -46,21 +47,40 @@ func TestInstanceList(t *testing.T) {
 }
 
 func TestInstanceTerminate(t *testing.T) {
-	models.TestProvider.On("SystemGet").Return(nil, nil)
+	models.Test(t, func() {
+		models.TestProvider.On("InstanceTerminate", "i-4a5513f4").Return(nil)
 
-	os.Setenv("RACK", "convox-test")
+		hf := test.NewHandlerFunc(controllers.HandlerFunc)
 
-	aws := test.StubAws(
-		test.DeleteInstanceCycle("i-4a5513f4"),
-	)
-	defer aws.Close()
+		if assert.Nil(t, hf.Request("DELETE", "/instances/i-4a5513f4", nil)) {
+			hf.AssertCode(t, 200)
+			hf.AssertSuccess(t)
+		}
+	})
+}
 
-	body := test.HTTPBody("DELETE", "http://convox/instances/i-4a5513f4", nil)
+func TestInstanceTerminateNotFound(t *testing.T) {
+	models.Test(t, func() {
+		models.TestProvider.On("InstanceTerminate", "i-missing").Return(fmt.Errorf("no such instance: i-missing"))
 
-	var resp map[string]bool
-	err := json.Unmarshal([]byte(body), &resp)
+		hf := test.NewHandlerFunc(controllers.HandlerFunc)
 
-	if assert.Nil(t, err) {
-		assert.Equal(t, true, resp["success"])
-	}
+		if assert.Nil(t, hf.Request("DELETE", "/instances/i-missing", nil)) {
+			hf.AssertCode(t, 404)
+			hf.AssertError(t, "no such instance: i-missing")
+		}
+	})
+}
+
+func TestInstancesKeyroll(t *testing.T) {
+	models.Test(t, func() {
+		models.TestProvider.On("InstanceKeyroll").Return(nil)
+
+		hf := test.NewHandlerFunc(controllers.HandlerFunc)
+
+		if assert.Nil(t, hf.Request("POST", "/instances/keyroll", nil)) {
+			hf.AssertCode(t, 200)
+			hf.AssertSuccess(t)
+		}
+	})
 }
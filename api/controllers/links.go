@@ -2,9 +2,11 @@ package controllers
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/convox/rack/api/httperr"
 	"github.com/convox/rack/api/models"
+	"github.com/convox/rack/api/structs"
 	"github.com/gorilla/mux"
 )
 
@@ -22,6 +24,10 @@ func LinkCreate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 		return httperr.Errorf(403, "can not link service with status: %s", s.Status)
 	}
 
+	if models.EnvironmentLinkTypes[s.Type] {
+		return linkEnvironment(rw, r, GetForm(r, "app"), s, models.LinkEnvironment)
+	}
+
 	s, err = models.Provider().ServiceLink(service, GetForm(r, "app"), GetForm(r, "process"))
 	if err != nil {
 		return httperr.Server(err)
@@ -45,6 +51,10 @@ func LinkDelete(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 		return httperr.Errorf(403, "can not unlink service with status: %s", s.Status)
 	}
 
+	if models.EnvironmentLinkTypes[s.Type] {
+		return linkEnvironment(rw, r, app, s, models.UnlinkEnvironment)
+	}
+
 	s, err = models.Provider().ServiceUnlink(service, app, GetForm(r, "process"))
 	if err != nil {
 		return httperr.Server(err)
@@ -52,3 +62,22 @@ func LinkDelete(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 
 	return RenderJson(rw, s)
 }
+
+// linkEnvironment links or unlinks a service through the environment of an app and returns
+// the release that carries the change in the Release-Id header
+func linkEnvironment(rw http.ResponseWriter, r *http.Request, app string, s *structs.Service, fn func(string, *structs.Service, string) (string, error)) *httperr.Error {
+	releaseID, err := fn(app, s, requestUser(r))
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil && (strings.Contains(err.Error(), "linked to app") || strings.HasSuffix(err.Error(), "no url to link")) {
+		return httperr.Errorf(403, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	rw.Header().Set("Release-Id", releaseID)
+
+	return RenderJson(rw, s)
+}
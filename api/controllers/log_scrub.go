@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/convox/rack/api/httperr"
+	"github.com/convox/rack/api/models"
+	"github.com/convox/rack/api/structs"
+	"github.com/gorilla/mux"
+)
+
+func LogScrubList(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	rules, err := models.Provider().LogScrubList(app)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, rules)
+}
+
+func LogScrubCreate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	rule := structs.LogScrubRule{
+		Name:    r.FormValue("name"),
+		Field:   r.FormValue("field"),
+		Pattern: r.FormValue("pattern"),
+	}
+
+	if err := rule.Validate(); err != nil {
+		return httperr.Errorf(403, "%s", err)
+	}
+
+	rules, err := models.Provider().LogScrubList(app)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	if _, ok := rules.Find(rule.Name); ok {
+		return httperr.Errorf(403, "log scrub rule already exists: %s", rule.Name)
+	}
+
+	if err := models.Provider().LogScrubSave(app, append(rules, rule)); err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, rule)
+}
+
+func LogScrubDelete(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	vars := mux.Vars(r)
+	app := vars["app"]
+	name := vars["rule"]
+
+	rules, err := models.Provider().LogScrubList(app)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	if _, ok := rules.Find(name); !ok {
+		return httperr.Errorf(404, "no such log scrub rule: %s", name)
+	}
+
+	remaining := structs.LogScrubRules{}
+
+	for _, rule := range rules {
+		if rule.Name != name {
+			remaining = append(remaining, rule)
+		}
+	}
+
+	if err := models.Provider().LogScrubSave(app, remaining); err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderSuccess(rw)
+}
@@ -0,0 +1,58 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/convox/rack/api/models"
+	"github.com/convox/rack/api/structs"
+	"github.com/convox/rack/provider"
+	"github.com/convox/rack/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogScrubCreate(t *testing.T) {
+	models.TestProvider = &provider.TestProvider{}
+
+	existing := structs.LogScrubRules{{Name: "email", Field: "email"}}
+
+	models.TestProvider.On("LogScrubList", "myapp").Return(existing, nil)
+	models.TestProvider.On("LogScrubSave", "myapp", structs.LogScrubRules{
+		{Name: "email", Field: "email"},
+		{Name: "cards", Pattern: `\d{16}`},
+	}).Return(nil)
+
+	body := test.HTTPBody("POST", "http://convox/apps/myapp/logs/scrub", url.Values{"name": {"cards"}, "pattern": {`\d{16}`}})
+
+	var rule structs.LogScrubRule
+
+	if assert.Nil(t, json.Unmarshal([]byte(body), &rule)) {
+		assert.Equal(t, structs.LogScrubRule{Name: "cards", Pattern: `\d{16}`}, rule)
+	}
+
+	body = test.AssertStatus(t, 403, "POST", "http://convox/apps/myapp/logs/scrub", url.Values{"name": {"email"}, "field": {"email"}})
+	assert.Equal(t, `{"error":"log scrub rule already exists: email"}`, body)
+
+	body = test.AssertStatus(t, 403, "POST", "http://convox/apps/myapp/logs/scrub", url.Values{"name": {"both"}, "field": {"email"}, "pattern": {"x"}})
+	assert.Equal(t, `{"error":"must specify either a field or a pattern"}`, body)
+
+	models.TestProvider.AssertExpectations(t)
+}
+
+func TestLogScrubDelete(t *testing.T) {
+	models.TestProvider = &provider.TestProvider{}
+
+	existing := structs.LogScrubRules{{Name: "email", Field: "email"}, {Name: "cards", Pattern: `\d{16}`}}
+
+	models.TestProvider.On("LogScrubList", "myapp").Return(existing, nil)
+	models.TestProvider.On("LogScrubSave", "myapp", structs.LogScrubRules{{Name: "cards", Pattern: `\d{16}`}}).Return(nil)
+
+	body := test.HTTPBody("DELETE", "http://convox/apps/myapp/logs/scrub/email", nil)
+	assert.Equal(t, `{"success":true}`, body)
+
+	body = test.AssertStatus(t, 404, "DELETE", "http://convox/apps/myapp/logs/scrub/phone", nil)
+	assert.Equal(t, `{"error":"no such log scrub rule: phone"}`, body)
+
+	models.TestProvider.AssertExpectations(t)
+}
@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/convox/rack/api/httperr"
+	"github.com/convox/rack/api/models"
+	"github.com/gorilla/mux"
+)
+
+// AppMetrics returns the cpu, memory and request metrics of the processes of an app
+func AppMetrics(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	period := time.Hour
+
+	if p := r.URL.Query().Get("period"); p != "" {
+		d, err := time.ParseDuration(p)
+		if err != nil || d < time.Minute {
+			return httperr.Errorf(403, "invalid period: %s", p)
+		}
+
+		period = d
+	}
+
+	metrics, err := models.AppMetrics(app, r.URL.Query().Get("process"), period)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil && strings.HasPrefix(err.Error(), "no such process") {
+		return httperr.Errorf(404, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, metrics)
+}
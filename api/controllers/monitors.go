@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/convox/rack/api/httperr"
+	"github.com/convox/rack/api/models"
+	"github.com/gorilla/mux"
+)
+
+func MonitorList(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	monitors, err := models.ListMonitors(app)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, monitors)
+}
+
+func MonitorCreate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	_, err := models.GetApp(app)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	m := models.NewMonitor(app, r.FormValue("url"))
+
+	for field, value := range map[string]*int{"interval": &m.Interval, "max-latency": &m.MaxLatency, "threshold": &m.Threshold} {
+		if v := r.FormValue(field); v != "" {
+			i, err := strconv.Atoi(v)
+			if err != nil {
+				return httperr.Invalid(field, "%s must be numeric", field)
+			}
+			*value = i
+		}
+	}
+
+	if err := m.Validate(); err != nil {
+		return httperr.Errorf(403, "%s", err)
+	}
+
+	if err := m.Save(); err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, m)
+}
+
+func MonitorShow(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	vars := mux.Vars(r)
+	app := vars["app"]
+	monitor := vars["monitor"]
+
+	m, err := models.GetMonitor(app, monitor)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil && strings.HasPrefix(err.Error(), "no such monitor") {
+		return httperr.Errorf(404, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, m)
+}
+
+func MonitorDelete(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	vars := mux.Vars(r)
+	app := vars["app"]
+	monitor := vars["monitor"]
+
+	m, err := models.GetMonitor(app, monitor)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil && strings.HasPrefix(err.Error(), "no such monitor") {
+		return httperr.Errorf(404, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	if err := m.Delete(); err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, m)
+}
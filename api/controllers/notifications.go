@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/convox/rack/api/httperr"
+	"github.com/convox/rack/api/models"
+	"github.com/gorilla/mux"
+)
+
+func NotificationList(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	notifications, err := models.ListNotifications()
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, notifications)
+}
+
+func NotificationAdd(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	events := []string{}
+
+	if e := GetForm(r, "events"); e != "" {
+		events = strings.Split(e, ",")
+	}
+
+	n, err := models.AddNotification(GetForm(r, "url"), events)
+	if err != nil {
+		for _, prefix := range []string{"notification must", "sns notification", "invalid "} {
+			if strings.HasPrefix(err.Error(), prefix) {
+				return httperr.Errorf(403, "%s", err)
+			}
+		}
+
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, n)
+}
+
+func NotificationRemove(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	id := mux.Vars(r)["id"]
+
+	err := models.RemoveNotification(id)
+	if err != nil && strings.HasPrefix(err.Error(), "no such notification") {
+		return httperr.Errorf(404, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderSuccess(rw)
+}
@@ -0,0 +1,40 @@
+package controllers_test
+
+import (
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/convox/rack/api/awsutil"
+	"github.com/convox/rack/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationAddInvalid(t *testing.T) {
+	aws := test.StubAws()
+	defer aws.Close()
+
+	body := test.AssertStatus(t, 403, "POST", "http://convox/notifications", url.Values{"url": {"https://example.org/hook"}})
+	assert.Equal(t, `{"error":"notification must be a slack://, webhook:// or sns:// url"}`, body)
+}
+
+func TestNotificationRemoveMissing(t *testing.T) {
+	defer os.Setenv("SETTINGS_BUCKET", os.Getenv("SETTINGS_BUCKET"))
+	os.Setenv("SETTINGS_BUCKET", "convox-settings")
+
+	aws := test.StubAws(
+		awsutil.Cycle{
+			Request: awsutil.Request{
+				RequestURI: "/convox-settings/notifications/N1234567890.json",
+			},
+			Response: awsutil.Response{
+				StatusCode: 404,
+				Body:       `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`,
+			},
+		},
+	)
+	defer aws.Close()
+
+	body := test.AssertStatus(t, 404, "DELETE", "http://convox/notifications/N1234567890", nil)
+	assert.Equal(t, `{"error":"no such notification: N1234567890"}`, body)
+}
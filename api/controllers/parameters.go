@@ -3,6 +3,7 @@ package controllers
 import (
 	"net/http"
 
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/convox/rack/api/httperr"
 	"github.com/convox/rack/api/models"
 	"github.com/gorilla/mux"
@@ -24,6 +25,48 @@ func ParametersList(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 	return RenderJson(rw, a.Parameters)
 }
 
+func ParametersSchema(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	a, err := models.GetApp(app)
+
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	schema, err := a.ParameterSchema()
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, schema)
+}
+
+func ParametersHistory(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	a, err := models.GetApp(app)
+
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	history, err := a.ParameterHistory()
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, history)
+}
+
 func ParametersSet(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 	app := mux.Vars(r)["app"]
 
@@ -51,5 +94,44 @@ func ParametersSet(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 		return httperr.Server(err)
 	}
 
+	if err := a.RecordParameterChanges(requestUser(r), params); err != nil {
+		return httperr.Server(err)
+	}
+
 	return RenderSuccess(rw)
 }
+
+// ParametersPreview returns the stack changes that setting the parameters would make
+func ParametersPreview(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	a, err := models.GetApp(app)
+
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	r.ParseMultipartForm(2048)
+
+	params := map[string]string{}
+
+	for key, values := range r.Form {
+		params[key] = values[0]
+	}
+
+	changes, err := a.PreviewParams(params)
+
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(403, "%s", err.(awserr.Error).Message())
+	}
+
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, changes)
+}
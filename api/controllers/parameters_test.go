@@ -0,0 +1,111 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/convox/rack/api/awsutil"
+	"github.com/convox/rack/api/models"
+	"github.com/convox/rack/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParametersSchema(t *testing.T) {
+	aws := test.StubAws(
+		test.DescribeAppStackCycle("convox-test-bar"),
+		test.GetTemplateSummaryCycle("convox-test-bar"),
+	)
+	defer aws.Close()
+
+	body := test.HTTPBody("GET", "http://convox/apps/bar/parameters/schema", nil)
+
+	var schema models.ParameterSchemas
+
+	if assert.Nil(t, json.Unmarshal([]byte(body), &schema)) {
+		assert.Equal(t, models.ParameterSchemas{
+			{Name: "Cluster", Type: "String"},
+			{Name: "Internal", Type: "String", Default: "No", Description: "Only allow access to this app from inside the VPC", Allowed: []string{"Yes", "No"}},
+			{Name: "Key", Type: "String", Secret: true},
+		}, schema)
+	}
+}
+
+func TestParametersHistory(t *testing.T) {
+	aws := test.StubAws(
+		test.DescribeAppStackCycle("convox-test-bar"),
+		awsutil.Cycle{
+			Request: awsutil.Request{
+				RequestURI: "/apache-app-settings-2gkjc9lf123nm/parameters/bar.json",
+			},
+			Response: awsutil.Response{
+				StatusCode: 200,
+				Body:       `[{"name":"Internal","old":"No","new":"Yes","user":"ops@example.org","created":"2016-10-01T12:00:00Z"},{"name":"Internal","old":"Yes","new":"No","user":"dev","created":"2016-10-02T12:00:00Z"}]`,
+			},
+		},
+	)
+	defer aws.Close()
+
+	body := test.HTTPBody("GET", "http://convox/apps/bar/parameters/history", nil)
+
+	var history models.ParameterChanges
+
+	if assert.Nil(t, json.Unmarshal([]byte(body), &history)) && assert.Len(t, history, 2) {
+		assert.Equal(t, "dev", history[0].User)
+		assert.Equal(t, "ops@example.org", history[1].User)
+	}
+}
+
+func TestParametersPreview(t *testing.T) {
+	aws := test.StubAws(
+		test.DescribeAppStackCycle("convox-test-bar"),
+		awsutil.Cycle{
+			Request: awsutil.Request{
+				RequestURI: "/",
+				Body:       `/^Action=CreateChangeSet&Capabilities.member.1=CAPABILITY_IAM&ChangeSetName=preview-\d+&.*ParameterKey=MainDesiredCount&Parameters.member.6.ParameterValue=3&.*StackName=convox-test-bar&UsePreviousTemplate=true/`,
+			},
+			Response: awsutil.Response{
+				StatusCode: 200,
+				Body:       `<CreateChangeSetResponse><CreateChangeSetResult><Id>arn:aws:cloudformation:us-east-1:123456789012:changeSet/preview/1</Id></CreateChangeSetResult></CreateChangeSetResponse>`,
+			},
+		},
+		awsutil.Cycle{
+			Request: awsutil.Request{
+				RequestURI: "/",
+				Body:       `/^Action=DescribeChangeSet&ChangeSetName=preview-\d+&StackName=convox-test-bar/`,
+			},
+			Response: awsutil.Response{
+				StatusCode: 200,
+				Body: `<DescribeChangeSetResponse><DescribeChangeSetResult>
+					<Status>CREATE_COMPLETE</Status>
+					<Changes>
+						<member><Type>Resource</Type><ResourceChange><Action>Modify</Action><LogicalResourceId>Balancer</LogicalResourceId><ResourceType>AWS::ElasticLoadBalancing::LoadBalancer</ResourceType><Replacement>True</Replacement></ResourceChange></member>
+						<member><Type>Resource</Type><ResourceChange><Action>Add</Action><LogicalResourceId>BalancerSecurityGroup</LogicalResourceId><ResourceType>AWS::EC2::SecurityGroup</ResourceType></ResourceChange></member>
+					</Changes>
+				</DescribeChangeSetResult></DescribeChangeSetResponse>`,
+			},
+		},
+		awsutil.Cycle{
+			Request: awsutil.Request{
+				RequestURI: "/",
+				Body:       `/^Action=DeleteChangeSet&ChangeSetName=preview-\d+&StackName=convox-test-bar/`,
+			},
+			Response: awsutil.Response{
+				StatusCode: 200,
+				Body:       `<DeleteChangeSetResponse><DeleteChangeSetResult></DeleteChangeSetResult></DeleteChangeSetResponse>`,
+			},
+		},
+	)
+	defer aws.Close()
+
+	body := test.HTTPBody("POST", "http://convox/apps/bar/parameters/preview", url.Values{"MainDesiredCount": []string{"3"}})
+
+	var changes models.StackChanges
+
+	if assert.Nil(t, json.Unmarshal([]byte(body), &changes)) {
+		assert.Equal(t, models.StackChanges{
+			{Action: "Modify", Resource: "Balancer", Type: "AWS::ElasticLoadBalancing::LoadBalancer", Replacement: "True"},
+			{Action: "Add", Resource: "BalancerSecurityGroup", Type: "AWS::EC2::SecurityGroup"},
+		}, changes)
+	}
+}
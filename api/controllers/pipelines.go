@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/convox/rack/api/httperr"
+	"github.com/convox/rack/api/models"
+	"github.com/gorilla/mux"
+)
+
+// pipelineError maps errors of the pipeline models to responses
+func pipelineError(err error) *httperr.Error {
+	if strings.HasPrefix(err.Error(), "no such") {
+		return httperr.Errorf(404, "%s", err)
+	}
+
+	for _, prefix := range []string{"must specify", "stages must", "duplicate stage", "pipeline already exists", "pipelines are not available", "run ", "runs must", "stage ", "status must"} {
+		if strings.HasPrefix(err.Error(), prefix) {
+			return httperr.Errorf(403, "%s", err)
+		}
+	}
+
+	return httperr.Server(err)
+}
+
+func PipelineList(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	pipelines, err := models.ListPipelines()
+	if err != nil {
+		return pipelineError(err)
+	}
+
+	return RenderJson(rw, pipelines)
+}
+
+func PipelineCreate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	p, err := models.ParsePipeline(GetForm(r, "name"), GetForm(r, "app"), GetForm(r, "stages"), GetForm(r, "require-approval"))
+	if err != nil {
+		return pipelineError(err)
+	}
+
+	if err := models.CreatePipeline(p); err != nil {
+		return pipelineError(err)
+	}
+
+	return RenderJson(rw, p)
+}
+
+func PipelineDelete(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	if err := models.DeletePipeline(mux.Vars(r)["pipeline"]); err != nil {
+		return pipelineError(err)
+	}
+
+	return RenderSuccess(rw)
+}
+
+func PipelineRunList(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	name := mux.Vars(r)["pipeline"]
+
+	if _, err := models.GetPipeline(name); err != nil {
+		return pipelineError(err)
+	}
+
+	runs, err := models.ListPipelineRuns(name)
+	if err != nil {
+		return pipelineError(err)
+	}
+
+	return RenderJson(rw, runs)
+}
+
+func PipelineRunCreate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	p, err := models.GetPipeline(mux.Vars(r)["pipeline"])
+	if err != nil {
+		return pipelineError(err)
+	}
+
+	run, err := p.StartRun(GetForm(r, "build"), requestUser(r))
+	if err != nil {
+		return pipelineError(err)
+	}
+
+	return RenderJson(rw, run)
+}
+
+// PipelineRunApprove lets the stage a run is waiting at start
+func PipelineRunApprove(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	vars := mux.Vars(r)
+
+	run, err := models.GetPipelineRun(vars["pipeline"], vars["id"])
+	if err != nil {
+		return pipelineError(err)
+	}
+
+	if err := run.Approve(requestIdentity(r)); err != nil {
+		return pipelineError(err)
+	}
+
+	return RenderJson(rw, run)
+}
+
+// PipelineRunStageUpdate records the progress of a stage, refusing to start a stage that has not
+// been approved
+func PipelineRunStageUpdate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	vars := mux.Vars(r)
+
+	p, err := models.GetPipeline(vars["pipeline"])
+	if err != nil {
+		return pipelineError(err)
+	}
+
+	run, err := models.GetPipelineRun(p.Name, vars["id"])
+	if err != nil {
+		return pipelineError(err)
+	}
+
+	if err := run.UpdateStage(p, vars["stage"], GetForm(r, "status"), GetForm(r, "build"), GetForm(r, "release")); err != nil {
+		return pipelineError(err)
+	}
+
+	return RenderJson(rw, run)
+}
@@ -0,0 +1,105 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/convox/rack/api/awsutil"
+	"github.com/convox/rack/api/controllers"
+	"github.com/convox/rack/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func pipelineCycle() awsutil.Cycle {
+	return awsutil.Cycle{
+		Request: awsutil.Request{
+			RequestURI: "/",
+			Operation:  "DynamoDB_20120810.GetItem",
+			Body:       `/"TableName":"convox-pipelines"/`,
+		},
+		Response: awsutil.Response{
+			StatusCode: 200,
+			Body:       `{"Item":{"name":{"S":"web"},"data":{"S":"{\"name\":\"web\",\"app\":\"bar\",\"stages\":[{\"name\":\"staging\",\"rack\":\"org/staging\"},{\"name\":\"production\",\"rack\":\"org/production\",\"approval\":true}]}"}}}`,
+		},
+	}
+}
+
+func pipelineRunCycle() awsutil.Cycle {
+	return awsutil.Cycle{
+		Request: awsutil.Request{
+			RequestURI: "/",
+			Operation:  "DynamoDB_20120810.GetItem",
+			Body:       `/"TableName":"convox-pipeline-runs"/`,
+		},
+		Response: awsutil.Response{
+			StatusCode: 200,
+			Body:       `{"Item":{"pipeline":{"S":"web"},"id":{"S":"P1"},"status":{"S":"running"},"data":{"S":"{\"id\":\"P1\",\"pipeline\":\"web\",\"build\":\"B1\",\"status\":\"running\",\"user\":\"alice\",\"stages\":[{\"name\":\"staging\",\"build\":\"B1\",\"release\":\"R1\",\"status\":\"complete\"},{\"name\":\"production\",\"status\":\"pending\"}]}"}}}`,
+		},
+	}
+}
+
+func setPipelineTables() func() {
+	tables := os.Getenv("DYNAMO_PIPELINES")
+	runs := os.Getenv("DYNAMO_PIPELINE_RUNS")
+
+	os.Setenv("DYNAMO_PIPELINES", "convox-pipelines")
+	os.Setenv("DYNAMO_PIPELINE_RUNS", "convox-pipeline-runs")
+
+	return func() {
+		os.Setenv("DYNAMO_PIPELINES", tables)
+		os.Setenv("DYNAMO_PIPELINE_RUNS", runs)
+	}
+}
+
+func TestPipelineStageRequiresApproval(t *testing.T) {
+	aws := test.StubAws(
+		pipelineCycle(),
+		pipelineRunCycle(),
+		awsutil.Cycle{
+			Request: awsutil.Request{
+				RequestURI: "/",
+				Operation:  "DynamoDB_20120810.PutItem",
+				Body:       `/"ConditionExpression":"#status = :prev".*":prev":{"S":"running"}.*"status":{"S":"awaiting-approval"}/`,
+			},
+			Response: awsutil.Response{
+				StatusCode: 200,
+				Body:       `{}`,
+			},
+		},
+	)
+	defer aws.Close()
+	defer setPipelineTables()()
+
+	// the cli can not start a stage that requires approval on its own
+	req, _ := http.NewRequest("PUT", "http://convox/pipelines/web/runs/P1/stages/production", strings.NewReader("status=running"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Version", "dev")
+
+	w := httptest.NewRecorder()
+	controllers.HandlerFunc(w, req)
+
+	assert.Equal(t, 403, w.Code)
+	assert.Equal(t, `{"error":"stage production requires approval"}`, w.Body.String())
+}
+
+func TestPipelineRunApproveWithoutUserKey(t *testing.T) {
+	aws := test.StubAws(
+		pipelineRunCycle(),
+	)
+	defer aws.Close()
+	defer setPipelineTables()()
+
+	// the user header is asserted by the client and does not count as an approver
+	req, _ := http.NewRequest("POST", "http://convox/pipelines/web/runs/P1/approve", nil)
+	req.Header.Set("Version", "dev")
+	req.Header.Set("User", "bob")
+
+	w := httptest.NewRecorder()
+	controllers.HandlerFunc(w, req)
+
+	assert.Equal(t, 403, w.Code)
+	assert.Equal(t, `{"error":"runs must be approved with a user key"}`, w.Body.String())
+}
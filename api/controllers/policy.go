@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/convox/rack/api/httperr"
+	"github.com/convox/rack/api/models"
+	"github.com/gorilla/mux"
+)
+
+// PolicyShow returns the policy of an app
+func PolicyShow(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	p, err := models.GetPolicy(app)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, p)
+}
+
+// PolicyUpdate changes the policy of an app
+func PolicyUpdate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	p, err := models.GetPolicy(app)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return updatePolicy(rw, r, p)
+}
+
+// SystemPolicyShow returns the policy that applies to every app of the rack
+func SystemPolicyShow(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	p, err := models.GetPolicy("")
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, p)
+}
+
+// SystemPolicyUpdate changes the policy that applies to every app of the rack
+func SystemPolicyUpdate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	p, err := models.GetPolicy("")
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return updatePolicy(rw, r, p)
+}
+
+func updatePolicy(rw http.ResponseWriter, r *http.Request, p *models.Policy) *httperr.Error {
+	// a user key could otherwise lift the policy that break glass grants are checked against
+	if err := requirePassword(r, "changing a policy"); err != nil {
+		return err
+	}
+
+	switch GetForm(r, "immutable") {
+	case "true":
+		p.Immutable = true
+	case "false":
+		p.Immutable = false
+	case "":
+	default:
+		return httperr.Errorf(403, "immutable must be true or false")
+	}
+
+	p.User = requestUser(r)
+
+	if err := p.Save(); err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, p)
+}
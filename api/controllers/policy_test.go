@@ -0,0 +1,131 @@
+package controllers_test
+
+import (
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/convox/rack/api/awsutil"
+	"github.com/convox/rack/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// immutableAppCycles stubs checking the policy of app bar, made immutable by the rack policy, and
+// finding no break glass grant
+func immutableAppCycles() []awsutil.Cycle {
+	return []awsutil.Cycle{
+		test.DescribeAppStackCycle("convox-test-bar"),
+		awsutil.Cycle{
+			Request: awsutil.Request{
+				RequestURI: "/apache-app-settings-2gkjc9lf123nm/policy.json",
+			},
+			Response: awsutil.Response{
+				StatusCode: 404,
+				Body:       `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`,
+			},
+		},
+		awsutil.Cycle{
+			Request: awsutil.Request{
+				RequestURI: "/convox-settings/policy.json",
+			},
+			Response: awsutil.Response{
+				StatusCode: 200,
+				Body:       `{"immutable":true}`,
+			},
+		},
+		test.DescribeAppStackCycle("convox-test-bar"),
+		awsutil.Cycle{
+			Request: awsutil.Request{
+				RequestURI: "/apache-app-settings-2gkjc9lf123nm?prefix=breakglass%2F",
+			},
+			Response: awsutil.Response{
+				StatusCode: 200,
+				Body:       `<?xml version="1.0" encoding="UTF-8"?><ListBucketResult><Name>apache-app-settings-2gkjc9lf123nm</Name><Prefix>breakglass/</Prefix><IsTruncated>false</IsTruncated></ListBucketResult>`,
+			},
+		},
+	}
+}
+
+func TestProcessRunImmutable(t *testing.T) {
+	defer os.Setenv("SETTINGS_BUCKET", os.Getenv("SETTINGS_BUCKET"))
+	os.Setenv("SETTINGS_BUCKET", "convox-settings")
+
+	aws := test.StubAws(immutableAppCycles()...)
+	defer aws.Close()
+
+	body := test.AssertStatus(t, 403, "POST", "http://convox/apps/bar/processes/web/run", url.Values{"command": {"bin/migrate"}})
+	assert.Equal(t, `{"error":"bar is immutable, run is not allowed"}`, body)
+}
+
+func TestProcessProfileImmutable(t *testing.T) {
+	defer os.Setenv("SETTINGS_BUCKET", os.Getenv("SETTINGS_BUCKET"))
+	os.Setenv("SETTINGS_BUCKET", "convox-settings")
+
+	aws := test.StubAws(immutableAppCycles()...)
+	defer aws.Close()
+
+	body := test.AssertStatus(t, 403, "GET", "http://convox/apps/bar/processes/p1/profile", nil)
+	assert.Equal(t, `{"error":"bar is immutable, profile is not allowed"}`, body)
+}
+
+func TestSecretSetImmutable(t *testing.T) {
+	defer os.Setenv("SETTINGS_BUCKET", os.Getenv("SETTINGS_BUCKET"))
+	os.Setenv("SETTINGS_BUCKET", "convox-settings")
+
+	aws := test.StubAws(immutableAppCycles()...)
+	defer aws.Close()
+
+	body := test.AssertStatus(t, 403, "POST", "http://convox/apps/bar/secrets/db-password", nil)
+	assert.Equal(t, `{"error":"bar is immutable, changing secrets is not allowed"}`, body)
+}
+
+func TestSecretDeleteImmutable(t *testing.T) {
+	defer os.Setenv("SETTINGS_BUCKET", os.Getenv("SETTINGS_BUCKET"))
+	os.Setenv("SETTINGS_BUCKET", "convox-settings")
+
+	aws := test.StubAws(immutableAppCycles()...)
+	defer aws.Close()
+
+	body := test.AssertStatus(t, 403, "DELETE", "http://convox/apps/bar/secrets/db-password", nil)
+	assert.Equal(t, `{"error":"bar is immutable, changing secrets is not allowed"}`, body)
+}
+
+func TestPolicyUpdateInvalid(t *testing.T) {
+	aws := test.StubAws(
+		test.DescribeAppStackCycle("convox-test-bar"),
+		awsutil.Cycle{
+			Request: awsutil.Request{
+				RequestURI: "/apache-app-settings-2gkjc9lf123nm/policy.json",
+			},
+			Response: awsutil.Response{
+				StatusCode: 200,
+				Body:       `{"app":"bar","immutable":true}`,
+			},
+		},
+	)
+	defer aws.Close()
+
+	body := test.AssertStatus(t, 403, "POST", "http://convox/apps/bar/policy", url.Values{"immutable": {"maybe"}})
+	assert.Equal(t, `{"error":"immutable must be true or false"}`, body)
+}
+
+func TestSystemPolicyShow(t *testing.T) {
+	defer os.Setenv("SETTINGS_BUCKET", os.Getenv("SETTINGS_BUCKET"))
+	os.Setenv("SETTINGS_BUCKET", "convox-settings")
+
+	aws := test.StubAws(
+		awsutil.Cycle{
+			Request: awsutil.Request{
+				RequestURI: "/convox-settings/policy.json",
+			},
+			Response: awsutil.Response{
+				StatusCode: 404,
+				Body:       `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`,
+			},
+		},
+	)
+	defer aws.Close()
+
+	body := test.HTTPBody("GET", "http://convox/system/policy", nil)
+	assert.Equal(t, "{\n  \"immutable\": false,\n  \"updated\": \"0001-01-01T00:00:00Z\"\n}\n", body)
+}
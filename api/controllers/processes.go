@@ -1,11 +1,14 @@
 package controllers
 
 import (
+	"fmt"
+	"io"
 	"net/http"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/convox/rack/api/httperr"
 	"github.com/convox/rack/api/models"
@@ -29,36 +32,43 @@ func ProcessList(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 	}
 
 	if stats {
-		w := new(sync.WaitGroup)
-		erch := make(chan error, len(processes))
+		if err := fetchProcessStats(processes); err != nil {
+			return httperr.Server(err)
+		}
+	}
+
+	sort.Sort(models.Processes(processes))
 
-		for _, p := range processes {
-			w.Add(1)
+	return RenderJson(rw, processes)
+}
 
-			go func(p *models.Process, w *sync.WaitGroup, erch chan error) {
-				err := p.FetchStats()
+// fetchProcessStats gathers cpu and memory usage for each process concurrently
+func fetchProcessStats(processes []*models.Process) error {
+	w := new(sync.WaitGroup)
+	erch := make(chan error, len(processes))
 
-				w.Done()
+	for _, p := range processes {
+		w.Add(1)
 
-				if err != nil {
-					erch <- err
-				}
-			}(p, w, erch)
-		}
+		go func(p *models.Process, w *sync.WaitGroup, erch chan error) {
+			err := p.FetchStats()
 
-		w.Wait()
+			w.Done()
 
-		select {
-		case err := <-erch:
-			return httperr.Server(err)
-		default:
-			// noop
-		}
+			if err != nil {
+				erch <- err
+			}
+		}(p, w, erch)
 	}
 
-	sort.Sort(models.Processes(processes))
+	w.Wait()
 
-	return RenderJson(rw, processes)
+	select {
+	case err := <-erch:
+		return err
+	default:
+		return nil
+	}
 }
 
 func ProcessShow(rw http.ResponseWriter, r *http.Request) *httperr.Error {
@@ -97,6 +107,10 @@ func ProcessExecAttached(ws *websocket.Conn) *httperr.Error {
 	height, _ := strconv.Atoi(header.Get("Height"))
 	width, _ := strconv.Atoi(header.Get("Width"))
 
+	if err := immutable("process.exec.attach", ws.Request(), app, "exec"); err != nil {
+		return err
+	}
+
 	a, err := models.GetApp(app)
 
 	if awsError(err) == "ValidationError" {
@@ -110,6 +124,92 @@ func ProcessExecAttached(ws *websocket.Conn) *httperr.Error {
 	return httperr.Server(a.ExecAttached(pid, command, height, width, ws))
 }
 
+// ProcessProfile records a profile of a running process and returns it
+func ProcessProfile(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	vars := mux.Vars(r)
+	app := vars["app"]
+	pid := vars["pid"]
+	typ := r.URL.Query().Get("type")
+
+	if err := immutable("process.profile", r, app, "profile"); err != nil {
+		return err
+	}
+
+	if typ == "" {
+		typ = "cpu"
+	}
+
+	duration := 30 * time.Second
+
+	if d := r.URL.Query().Get("duration"); d != "" {
+		pd, err := time.ParseDuration(d)
+		if err != nil {
+			return httperr.Errorf(403, "invalid duration: %s", d)
+		}
+
+		duration = pd
+	}
+
+	a, err := models.GetApp(app)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	p, err := a.Profile(pid, typ, duration)
+	if err != nil && strings.HasPrefix(err.Error(), "no such process") {
+		return httperr.Errorf(404, "%s", err)
+	}
+	if err != nil {
+		for _, prefix := range []string{"process ", "profile ", "unable to fetch profile"} {
+			if strings.HasPrefix(err.Error(), prefix) {
+				return httperr.Errorf(403, "%s", err)
+			}
+		}
+
+		return httperr.Server(err)
+	}
+
+	defer p.Close()
+
+	rw.Header().Set("Content-Type", "application/octet-stream")
+	rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s-%s.%s", pid, typ, p.Format))
+	rw.Header().Set("Profile-Format", p.Format)
+
+	if _, err := io.Copy(rw, p); err != nil {
+		return httperr.Server(err)
+	}
+
+	return nil
+}
+
+// ProcessDebugAttached starts a debugging container next to a process and attaches to it
+func ProcessDebugAttached(ws *websocket.Conn) *httperr.Error {
+	vars := mux.Vars(ws.Request())
+	header := ws.Request().Header
+
+	app := vars["app"]
+	pid := vars["pid"]
+	height, _ := strconv.Atoi(header.Get("Height"))
+	width, _ := strconv.Atoi(header.Get("Width"))
+
+	if err := immutable("process.debug.attach", ws.Request(), app, "debug"); err != nil {
+		return err
+	}
+
+	a, err := models.GetApp(app)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return httperr.Server(a.DebugAttached(pid, header.Get("Image"), header.Get("Command"), height, width, ws))
+}
+
 func ProcessRunDetached(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 	vars := mux.Vars(r)
 	app := vars["app"]
@@ -117,6 +217,10 @@ func ProcessRunDetached(rw http.ResponseWriter, r *http.Request) *httperr.Error
 	command := GetForm(r, "command")
 	release := GetForm(r, "release")
 
+	if err := immutable("process.run.detach", r, app, "run"); err != nil {
+		return err
+	}
+
 	a, err := models.GetApp(app)
 
 	if awsError(err) == "ValidationError" {
@@ -147,6 +251,10 @@ func ProcessRunAttached(ws *websocket.Conn) *httperr.Error {
 	height, _ := strconv.Atoi(header.Get("Height"))
 	width, _ := strconv.Atoi(header.Get("Width"))
 
+	if err := immutable("process.run.attach", ws.Request(), app, "run"); err != nil {
+		return err
+	}
+
 	a, err := models.GetApp(app)
 
 	if awsError(err) == "ValidationError" {
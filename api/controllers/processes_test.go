@@ -9,7 +9,7 @@ import (
 	"github.com/convox/rack/api/controllers"
 	"github.com/convox/rack/api/models"
 	"github.com/convox/rack/api/structs"
-	"github.com/convox/rack/client"
+	cmodels "github.com/convox/rack/client/models"
 	"github.com/convox/rack/provider"
 	"github.com/convox/rack/test"
 	"github.com/stretchr/testify/assert"
@@ -70,7 +70,7 @@ func TestProcessesList(t *testing.T) {
 	v.Add("stats", "true")
 	body := test.HTTPBody("GET", "http://convox/apps/myapp-staging/processes", v)
 
-	var resp client.Processes
+	var resp cmodels.Processes
 	err := json.Unmarshal([]byte(body), &resp)
 
 	if assert.Nil(t, err) {
@@ -130,7 +130,7 @@ func TestGetProcessesWithDeployments(t *testing.T) {
 	v.Add("stats", "true")
 	body := test.HTTPBody("GET", "http://convox/apps/myapp-staging/processes", v)
 
-	var resp client.Processes
+	var resp cmodels.Processes
 	err := json.Unmarshal([]byte(body), &resp)
 
 	if assert.Nil(t, err) {
@@ -186,7 +186,7 @@ func TestProcessesListWithAttached(t *testing.T) {
 
 	body := test.HTTPBody("GET", "http://convox/apps/myapp-staging/processes", url.Values{})
 
-	var resp client.Processes
+	var resp cmodels.Processes
 	err := json.Unmarshal([]byte(body), &resp)
 
 	if assert.Nil(t, err) {
@@ -246,7 +246,7 @@ func TestProcessesListWithDetached(t *testing.T) {
 
 	body := test.HTTPBody("GET", "http://convox/apps/myapp-staging/processes", url.Values{})
 
-	var resp client.Processes
+	var resp cmodels.Processes
 	err := json.Unmarshal([]byte(body), &resp)
 
 	if assert.Nil(t, err) {
@@ -256,6 +256,120 @@ func TestProcessesListWithDetached(t *testing.T) {
 	}
 }
 
+func TestProcessesListControlCluster(t *testing.T) {
+	models.TestProvider = &provider.TestProvider{
+		Instances: []structs.Instance{
+			structs.Instance{},
+			structs.Instance{},
+			structs.Instance{},
+		},
+	}
+
+	models.TestProvider.On("InstanceList").Return(models.TestProvider.Instances, nil)
+
+	defer os.Setenv("RACK", os.Getenv("RACK"))
+	defer os.Setenv("CLUSTER", os.Getenv("CLUSTER"))
+	defer os.Unsetenv("CONTROL_CLUSTER")
+
+	// the rack itself runs in the control cluster
+	os.Setenv("RACK", "convox-test-myapp-staging")
+	os.Setenv("CLUSTER", "convox-test-apps")
+	os.Setenv("CONTROL_CLUSTER", "convox-test-cluster")
+
+	aws := test.StubAws(
+		test.DescribeAppStackCycle("convox-test-myapp-staging"),
+		test.DescribeAppStackCycle("convox-test-myapp-staging"),
+		test.DescribeAppStackResourcesCycle("convox-test-myapp-staging"),
+
+		test.ListContainerInstancesCycle("convox-test-cluster"),
+		test.DescribeContainerInstancesCycle("convox-test-cluster"),
+		test.DescribeInstancesCycle(),
+
+		test.ListTasksCycle("convox-test-cluster", "convox-test-myapp-staging-worker-SCELGCIYSKF"),
+		test.DescribeTasksCycle("convox-test-cluster"),
+		test.ListTasksOneoffEmptyCycle("convox-test-cluster"),
+		test.DescribeTaskDefinitionCycle("convox-test-cluster"),
+
+		test.DescribeAppStackResourcesCycle("convox-test-myapp-staging"),
+		test.DescribeServicesCycle("convox-test-cluster"),
+	)
+	defer aws.Close()
+
+	docker := test.StubDocker(
+		test.ListECSContainersCycle(),
+		test.ListOneoffContainersEmptyCycle(),
+		test.ListOneoffContainersEmptyCycle(),
+		test.ListOneoffContainersEmptyCycle(),
+	)
+	defer docker.Close()
+
+	body := test.HTTPBody("GET", "http://convox/apps/convox-test-myapp-staging/processes", url.Values{})
+
+	var resp cmodels.Processes
+	err := json.Unmarshal([]byte(body), &resp)
+
+	if assert.Nil(t, err) {
+		assert.Equal(t, 1, len(resp))
+	}
+}
+
+func TestProcessesListAppWithControlCluster(t *testing.T) {
+	models.TestProvider = &provider.TestProvider{
+		Instances: []structs.Instance{
+			structs.Instance{},
+			structs.Instance{},
+			structs.Instance{},
+		},
+	}
+
+	models.TestProvider.On("InstanceList").Return(models.TestProvider.Instances, nil)
+
+	defer os.Setenv("RACK", os.Getenv("RACK"))
+	defer os.Setenv("CLUSTER", os.Getenv("CLUSTER"))
+	defer os.Unsetenv("CONTROL_CLUSTER")
+
+	// apps stay in the app cluster
+	os.Setenv("RACK", "convox-test")
+	os.Setenv("CLUSTER", "convox-test-cluster")
+	os.Setenv("CONTROL_CLUSTER", "convox-test-control")
+
+	aws := test.StubAws(
+		test.DescribeAppStackCycle("convox-test-myapp-staging"),
+		test.DescribeAppStackCycle("convox-test-myapp-staging"),
+		test.DescribeAppStackResourcesCycle("convox-test-myapp-staging"),
+
+		test.ListContainerInstancesCycle("convox-test-cluster"),
+		test.DescribeContainerInstancesCycle("convox-test-cluster"),
+		test.DescribeInstancesCycle(),
+
+		test.ListTasksCycle("convox-test-cluster", "convox-test-myapp-staging-worker-SCELGCIYSKF"),
+		test.DescribeTasksCycle("convox-test-cluster"),
+		test.ListTasksOneoffEmptyCycle("convox-test-cluster"),
+		test.DescribeTaskDefinitionCycle("convox-test-cluster"),
+
+		test.DescribeAppStackResourcesCycle("convox-test-myapp-staging"),
+		test.DescribeServicesCycle("convox-test-cluster"),
+	)
+	defer aws.Close()
+
+	docker := test.StubDocker(
+		test.ListECSContainersCycle(),
+		test.ListOneoffContainersEmptyCycle(),
+		test.ListOneoffContainersEmptyCycle(),
+		test.ListOneoffContainersEmptyCycle(),
+	)
+	defer docker.Close()
+
+	body := test.HTTPBody("GET", "http://convox/apps/myapp-staging/processes", url.Values{})
+
+	var resp cmodels.Processes
+	err := json.Unmarshal([]byte(body), &resp)
+
+	if assert.Nil(t, err) {
+		assert.Equal(t, 1, len(resp))
+	}
+}
+
 // func TestProcessShow(t *testing.T) {}
 
 // func TestProcessStop(t *testing.T) {}
@@ -3,6 +3,7 @@ package controllers
 import (
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/convox/rack/api/httperr"
@@ -43,17 +44,39 @@ func ReleaseGet(rw http.ResponseWriter, req *http.Request) *httperr.Error {
 	return RenderJson(rw, r)
 }
 
+func ReleaseDiff(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	vars := mux.Vars(r)
+	app := vars["app"]
+
+	d, err := models.Provider().ReleaseDiff(app, vars["release"], vars["other"])
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil && strings.HasPrefix(err.Error(), "no such release") {
+		return httperr.Errorf(404, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, d)
+}
+
 func ReleasePromote(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 	vars := mux.Vars(r)
 	app := vars["app"]
 	release := vars["release"]
 
-	_, err := models.GetApp(app)
+	a, err := models.GetApp(app)
 
 	if awsError(err) == "ValidationError" {
 		return httperr.Errorf(404, "no such app: %s", app)
 	}
 
+	if err != nil {
+		return httperr.Server(err)
+	}
+
 	rr, err := models.GetRelease(app, release)
 
 	if err != nil && strings.HasPrefix(err.Error(), "no such release") {
@@ -64,7 +87,27 @@ func ReleasePromote(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 		return httperr.Server(err)
 	}
 
-	err = rr.Promote()
+	if c, err := models.GetCanary(app); err == nil {
+		return httperr.Errorf(403, "%s has a canary of %s, finalize or abort it first", app, c.Release)
+	}
+
+	wait := r.FormValue("wait") == "true"
+	timeout := models.PromotionTimeout
+
+	if t := r.FormValue("timeout"); t != "" {
+		d, err := time.ParseDuration(t)
+		if err != nil || d <= 0 {
+			return httperr.Errorf(403, "invalid timeout: %s", t)
+		}
+
+		timeout = d
+	}
+
+	err = rr.PromoteProcesses(promoteProcesses(r))
+
+	if err != nil && strings.HasPrefix(err.Error(), "release "+rr.Id+" has no process") {
+		return httperr.Errorf(403, "%s", err)
+	}
 
 	if awsError(err) == "ValidationError" {
 		message := err.(awserr.Error).Message()
@@ -75,5 +118,99 @@ func ReleasePromote(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 		return httperr.Server(err)
 	}
 
+	// a synchronous promote only succeeds once the new processes are healthy
+	if wait {
+		if err := rr.WaitForPromotion(timeout); err != nil {
+			return httperr.Errorf(403, "%s", err)
+		}
+	}
+
+	if err := a.RecordReleasePromotion(rr, requestUser(r)); err != nil {
+		return httperr.Server(err)
+	}
+
 	return RenderJson(rw, rr)
 }
+
+func ReleasePromotions(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	vars := mux.Vars(r)
+	app := vars["app"]
+	release := vars["release"]
+
+	a, err := models.GetApp(app)
+
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	promotions, err := a.ReleasePromotions(release)
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, promotions)
+}
+
+// ReleasePromotePreview returns the stack changes that promoting a release would make
+func ReleasePromotePreview(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	vars := mux.Vars(r)
+	app := vars["app"]
+	release := vars["release"]
+
+	_, err := models.GetApp(app)
+
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	rr, err := models.GetRelease(app, release)
+
+	if err != nil && strings.HasPrefix(err.Error(), "no such release") {
+		return httperr.Errorf(404, "no such release: %s", release)
+	}
+
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	if c, err := models.GetCanary(app); err == nil {
+		return httperr.Errorf(403, "%s has a canary of %s, finalize or abort it first", app, c.Release)
+	}
+
+	changes, err := rr.PromotePreview(promoteProcesses(r))
+
+	if err != nil && strings.HasPrefix(err.Error(), "release "+rr.Id+" has no process") {
+		return httperr.Errorf(403, "%s", err)
+	}
+
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(403, "%s", err.(awserr.Error).Message())
+	}
+
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, changes)
+}
+
+// promoteProcesses returns the processes a promote is limited to, if any
+func promoteProcesses(r *http.Request) []string {
+	processes := []string{}
+
+	for _, p := range strings.Split(r.FormValue("processes"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			processes = append(processes, p)
+		}
+	}
+
+	return processes
+}
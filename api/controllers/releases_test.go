@@ -0,0 +1,63 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/convox/rack/api/awsutil"
+	"github.com/convox/rack/api/models"
+	"github.com/convox/rack/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleasePromotions(t *testing.T) {
+	aws := test.StubAws(
+		test.DescribeAppStackCycle("convox-test-bar"),
+		awsutil.Cycle{
+			Request: awsutil.Request{
+				RequestURI: "/apache-app-settings-2gkjc9lf123nm/releases/R1234/promotions.json",
+			},
+			Response: awsutil.Response{
+				StatusCode: 200,
+				Body:       `[{"release":"R1234","user":"ops@example.org","created":"2016-10-01T12:00:00Z"},{"release":"R1234","user":"dev","created":"2016-10-02T12:00:00Z"}]`,
+			},
+		},
+	)
+	defer aws.Close()
+
+	body := test.HTTPBody("GET", "http://convox/apps/bar/releases/R1234/promotions", nil)
+
+	var promotions models.ReleasePromotions
+
+	if assert.Nil(t, json.Unmarshal([]byte(body), &promotions)) && assert.Len(t, promotions, 2) {
+		assert.Equal(t, "dev", promotions[0].User)
+		assert.Equal(t, "ops@example.org", promotions[1].User)
+	}
+}
+
+func TestReleasePromoteUnknownProcess(t *testing.T) {
+	os.Setenv("DYNAMO_RELEASES", "convox-releases")
+	defer os.Unsetenv("DYNAMO_RELEASES")
+
+	aws := test.StubAws(
+		test.DescribeAppStackCycle("convox-test-bar"),
+		test.GetItemAppReleaseCycle("bar"),
+		test.DescribeAppStackCycle("convox-test-bar"),
+		awsutil.Cycle{
+			Request: awsutil.Request{
+				RequestURI: "/apache-app-settings-2gkjc9lf123nm/canary.json",
+			},
+			Response: awsutil.Response{
+				StatusCode: 404,
+				Body:       `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`,
+			},
+		},
+		test.DescribeAppStackCycle("convox-test-bar"),
+	)
+	defer aws.Close()
+
+	body := test.AssertStatus(t, 403, "POST", "http://convox/apps/bar/releases/RCSUVJNDLDK/promote", url.Values{"processes": {"main, worker"}})
+	assert.Equal(t, `{"error":"release RCSUVJNDLDK has no process: worker"}`, body)
+}
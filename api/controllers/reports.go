@@ -0,0 +1,35 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/convox/rack/api/httperr"
+	"github.com/convox/rack/api/models"
+	"github.com/gorilla/mux"
+)
+
+// AppUptimeReport returns the availability of the balanced processes of an app for a month
+func AppUptimeReport(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	month := r.URL.Query().Get("month")
+
+	if month == "" {
+		month = time.Now().UTC().Format("2006-01")
+	}
+
+	report, err := models.AppUptime(app, month)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil && strings.HasPrefix(err.Error(), "month ") {
+		return httperr.Errorf(403, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, report)
+}
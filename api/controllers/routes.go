@@ -16,40 +16,112 @@ func NewRouter() (router *mux.Router) {
 
 	router.HandleFunc("/apps", api("app.list", AppList)).Methods("GET")
 	router.HandleFunc("/apps", api("app.create", AppCreate)).Methods("POST")
+	router.HandleFunc("/apps/import/ecs", api("app.import.ecs", AppImportECS)).Methods("GET")
 	router.HandleFunc("/apps/{app}", api("app.get", AppShow)).Methods("GET")
 	router.HandleFunc("/apps/{app}", api("app.delete", AppDelete)).Methods("DELETE")
+	router.HandleFunc("/apps/{app}/alarms", api("alarm.list", AlarmList)).Methods("GET")
+	router.HandleFunc("/apps/{app}/alarms/custom", api("alarm.custom.list", CustomAlarmList)).Methods("GET")
+	router.HandleFunc("/apps/{app}/alarms/custom", api("alarm.custom.create", CustomAlarmCreate)).Methods("POST")
+	router.HandleFunc("/apps/{app}/alarms/custom/{alarm}", api("alarm.custom.update", CustomAlarmUpdate)).Methods("PUT")
+	router.HandleFunc("/apps/{app}/alarms/custom/{alarm}", api("alarm.custom.delete", CustomAlarmDelete)).Methods("DELETE")
+	router.HandleFunc("/apps/{app}/alarms/{metric}", api("alarm.enable", AlarmEnable)).Methods("POST")
+	router.HandleFunc("/apps/{app}/alarms/{metric}", api("alarm.disable", AlarmDisable)).Methods("DELETE")
+	router.HandleFunc("/apps/{app}/cancel", api("app.cancel", AppCancel)).Methods("POST")
+	router.HandleFunc("/apps/{app}/canary", api("canary.show", CanaryShow)).Methods("GET")
+	router.HandleFunc("/apps/{app}/canary", api("canary.abort", CanaryAbort)).Methods("DELETE")
+	router.HandleFunc("/apps/{app}/canary/finalize", api("canary.finalize", CanaryFinalize)).Methods("POST")
+	router.HandleFunc("/apps/{app}/breakglass", api("breakglass.list", BreakGlassList)).Methods("GET")
+	router.HandleFunc("/apps/{app}/breakglass", api("breakglass.request", BreakGlassRequest)).Methods("POST")
+	router.HandleFunc("/apps/{app}/breakglass/{id}", api("breakglass.revoke", BreakGlassRevoke)).Methods("DELETE")
+	router.HandleFunc("/apps/{app}/breakglass/{id}/approve", api("breakglass.approve", BreakGlassApprove)).Methods("POST")
+	router.HandleFunc("/apps/{app}/breakglass/{id}/deny", api("breakglass.deny", BreakGlassDeny)).Methods("POST")
 	router.HandleFunc("/apps/{app}/builds", api("build.list", BuildList)).Methods("GET")
 	router.HandleFunc("/apps/{app}/builds", api("build.create", BuildCreate)).Methods("POST")
+	router.HandleFunc("/apps/{app}/builds/import", api("build.import", BuildImport)).Methods("POST")
+	router.HandleFunc("/apps/{app}/builds/schedules", api("build.schedule.list", BuildScheduleList)).Methods("GET")
+	router.HandleFunc("/apps/{app}/builds/schedules", api("build.schedule.create", BuildScheduleCreate)).Methods("POST")
+	router.HandleFunc("/apps/{app}/builds/schedules/{schedule}", api("build.schedule.delete", BuildScheduleDelete)).Methods("DELETE")
 	router.HandleFunc("/apps/{app}/builds/{build}", api("build.get", BuildGet)).Methods("GET")
 	router.HandleFunc("/apps/{app}/builds/{build}", api("build.update", BuildUpdate)).Methods("PUT")
 	router.HandleFunc("/apps/{app}/builds/{build}", api("build.delete", BuildDelete)).Methods("DELETE")
+	router.HandleFunc("/apps/{app}/builds/{build}/cancel", api("build.cancel", BuildCancel)).Methods("POST")
 	router.HandleFunc("/apps/{app}/builds/{build}/copy", api("build.copy", BuildCopy)).Methods("POST")
+	router.HandleFunc("/apps/{app}/builds/{build}/export", api("build.export", BuildExport)).Methods("GET")
+	router.HandleFunc("/apps/{app}/builds/{build}/release", api("build.release", BuildRelease)).Methods("POST")
+	router.HandleFunc("/apps/{app}/crashes", api("crash.list", CrashList)).Methods("GET")
+	router.HandleFunc("/apps/{app}/crashes/{crash}", api("crash.show", CrashShow)).Methods("GET")
+	router.HandleFunc("/apps/{app}/crashes/{crash}/dump", api("crash.dump", CrashDump)).Methods("GET")
+	router.HandleFunc("/apps/{app}/dependencies", api("dependency.create", DependencyCreate)).Methods("POST")
+	router.HandleFunc("/apps/{app}/dependencies/{dependency}", api("dependency.delete", DependencyDelete)).Methods("DELETE")
+	router.HandleFunc("/apps/{app}/domains", api("domain.list", DomainList)).Methods("GET")
+	router.HandleFunc("/apps/{app}/domains", api("domain.add", DomainAdd)).Methods("POST")
+	router.HandleFunc("/apps/{app}/domains/{domain}", api("domain.remove", DomainRemove)).Methods("DELETE")
+	router.HandleFunc("/apps/{app}/drains", api("drain.list", DrainList)).Methods("GET")
+	router.HandleFunc("/apps/{app}/drains", api("drain.create", DrainCreate)).Methods("POST")
+	router.HandleFunc("/apps/{app}/drains/{drain}", api("drain.delete", DrainDelete)).Methods("DELETE")
 	router.HandleFunc("/apps/{app}/environment", api("environment.list", EnvironmentList)).Methods("GET")
 	router.HandleFunc("/apps/{app}/environment", api("environment.set", EnvironmentSet)).Methods("POST")
+	router.HandleFunc("/apps/{app}/environment/commit", api("environment.commit", EnvironmentCommit)).Methods("POST")
 	router.HandleFunc("/apps/{app}/environment/{name}", api("environment.delete", EnvironmentDelete)).Methods("DELETE")
 	router.HandleFunc("/apps/{app}/formation", api("formation.list", FormationList)).Methods("GET")
 	router.HandleFunc("/apps/{app}/formation/{process}", api("formation.set", FormationSet)).Methods("POST")
+	router.HandleFunc("/apps/{app}/logs/scrub", api("log.scrub.list", LogScrubList)).Methods("GET")
+	router.HandleFunc("/apps/{app}/logs/scrub", api("log.scrub.create", LogScrubCreate)).Methods("POST")
+	router.HandleFunc("/apps/{app}/logs/scrub/{rule}", api("log.scrub.delete", LogScrubDelete)).Methods("DELETE")
+	router.HandleFunc("/apps/{app}/metrics", api("app.metrics", AppMetrics)).Methods("GET")
+	router.HandleFunc("/apps/{app}/reports/uptime", api("app.reports.uptime", AppUptimeReport)).Methods("GET")
+	router.HandleFunc("/apps/{app}/monitors", api("monitor.list", MonitorList)).Methods("GET")
+	router.HandleFunc("/apps/{app}/monitors", api("monitor.create", MonitorCreate)).Methods("POST")
+	router.HandleFunc("/apps/{app}/monitors/{monitor}", api("monitor.show", MonitorShow)).Methods("GET")
+	router.HandleFunc("/apps/{app}/monitors/{monitor}", api("monitor.delete", MonitorDelete)).Methods("DELETE")
 	router.HandleFunc("/apps/{app}/parameters", api("parameters.list", ParametersList)).Methods("GET")
 	router.HandleFunc("/apps/{app}/parameters", api("parameters.set", ParametersSet)).Methods("POST")
+	router.HandleFunc("/apps/{app}/parameters/preview", api("parameters.preview", ParametersPreview)).Methods("POST")
+	router.HandleFunc("/apps/{app}/parameters/history", api("parameters.history", ParametersHistory)).Methods("GET")
+	router.HandleFunc("/apps/{app}/parameters/schema", api("parameters.schema", ParametersSchema)).Methods("GET")
+	router.HandleFunc("/apps/{app}/policy", api("policy.show", PolicyShow)).Methods("GET")
+	router.HandleFunc("/apps/{app}/policy", api("policy.update", PolicyUpdate)).Methods("POST")
 	router.HandleFunc("/apps/{app}/processes", api("process.list", ProcessList)).Methods("GET")
 	router.HandleFunc("/apps/{app}/processes/{process}", api("process.get", ProcessShow)).Methods("GET")
 	router.HandleFunc("/apps/{app}/processes/{process}", api("process.stop", ProcessStop)).Methods("DELETE")
+	router.HandleFunc("/apps/{app}/processes/{pid}/profile", api("process.profile", ProcessProfile)).Methods("GET")
 	router.HandleFunc("/apps/{app}/processes/{process}/run", api("process.run.detach", ProcessRunDetached)).Methods("POST")
 	router.HandleFunc("/apps/{app}/releases", api("release.list", ReleaseList)).Methods("GET")
 	router.HandleFunc("/apps/{app}/releases/{release}", api("release.get", ReleaseGet)).Methods("GET")
+	router.HandleFunc("/apps/{app}/releases/{release}/diff/{other}", api("release.diff", ReleaseDiff)).Methods("GET")
+	router.HandleFunc("/apps/{app}/releases/{release}/canary", api("release.canary", CanaryCreate)).Methods("POST")
 	router.HandleFunc("/apps/{app}/releases/{release}/promote", api("release.promote", ReleasePromote)).Methods("POST")
+	router.HandleFunc("/apps/{app}/releases/{release}/promote/preview", api("release.promote.preview", ReleasePromotePreview)).Methods("POST")
+	router.HandleFunc("/apps/{app}/releases/{release}/promotions", api("release.promotions", ReleasePromotions)).Methods("GET")
+	router.HandleFunc("/apps/{app}/secrets", api("secret.list", SecretList)).Methods("GET")
+	router.HandleFunc("/apps/{app}/secrets/{name}", api("secret.show", SecretShow)).Methods("GET")
+	router.HandleFunc("/apps/{app}/secrets/{name}", api("secret.set", SecretSet)).Methods("POST")
+	router.HandleFunc("/apps/{app}/secrets/{name}", api("secret.delete", SecretDelete)).Methods("DELETE")
 	router.HandleFunc("/apps/{app}/ssl", api("ssl.list", SSLList)).Methods("GET")
 	router.HandleFunc("/apps/{app}/ssl/{process}/{port}", api("ssl.update", SSLUpdate)).Methods("PUT")
+	router.HandleFunc("/apps/{app}/status", api("status.show", AppStatusShow)).Methods("GET")
+	router.HandleFunc("/apps/{app}/status", api("status.update", AppStatusUpdate)).Methods("POST")
+	router.HandleFunc("/apps/{app}/timers", api("timer.list", TimerList)).Methods("GET")
+	router.HandleFunc("/apps/{app}/timers/{timer}/runs", api("timer.runs", TimerRuns)).Methods("GET")
+	router.HandleFunc("/apps/{app}/timers/{timer}/runs", api("timer.run", TimerRun)).Methods("POST")
+	router.HandleFunc("/apps/{app}/webhook", api("app.webhook", AppWebhookShow)).Methods("GET")
+	router.HandleFunc("/audit", api("audit.list", AuditList)).Methods("GET")
 	router.HandleFunc("/auth", api("auth", Auth)).Methods("GET")
 	router.HandleFunc("/certificates", api("certificate.list", CertificateList)).Methods("GET")
 	router.HandleFunc("/certificates", api("certificate.create", CertificateCreate)).Methods("POST")
 	router.HandleFunc("/certificates/generate", api("certificate.generate", CertificateGenerate)).Methods("POST")
 	router.HandleFunc("/certificates/{id}", api("certificate.delete", CertificateDelete)).Methods("DELETE")
+	router.HandleFunc("/dependencies", api("dependency.list", DependencyList)).Methods("GET")
+	router.HandleFunc("/dependencies/{name}/impact", api("dependency.impact", DependencyImpact)).Methods("GET")
 	router.HandleFunc("/index/diff", api("index.diff", IndexDiff)).Methods("POST")
 	router.HandleFunc("/index/update", api("index.update", IndexUpdate)).Methods("POST")
 	router.HandleFunc("/instances", api("instances.get", InstancesList)).Methods("GET")
 	router.HandleFunc("/instances/{id}", api("instance.delete", InstanceTerminate)).Methods("DELETE")
 	router.HandleFunc("/instances/keyroll", api("instances.keyroll", InstancesKeyroll)).Methods("POST")
+	router.HandleFunc("/instances/{id}/processes", api("instance.processes", InstanceProcesses)).Methods("GET")
+	router.HandleFunc("/notifications", api("notification.list", NotificationList)).Methods("GET")
+	router.HandleFunc("/notifications", api("notification.add", NotificationAdd)).Methods("POST")
+	router.HandleFunc("/notifications/{id}", api("notification.remove", NotificationRemove)).Methods("DELETE")
 	router.HandleFunc("/racks", api("rack.list", RackList)).Methods("GET")
 	router.HandleFunc("/registries", api("registry.list", RegistryList)).Methods("GET")
 	router.HandleFunc("/registries", api("registry.create", RegistryCreate)).Methods("POST")
@@ -67,17 +139,43 @@ func NewRouter() (router *mux.Router) {
 	router.HandleFunc("/system", api("system.show", SystemShow)).Methods("GET")
 	router.HandleFunc("/system", api("system.update", SystemUpdate)).Methods("PUT")
 	router.HandleFunc("/system/capacity", api("system.capacity", SystemCapacity)).Methods("GET")
+	router.HandleFunc("/system/checks", api("system.checks", SystemCheck)).Methods("GET")
+	router.HandleFunc("/system/events", api("system.events", SystemEvents)).Methods("GET")
+	router.HandleFunc("/system/health", api("system.health", SystemHealth)).Methods("GET")
+	router.HandleFunc("/system/policy", api("system.policy.show", SystemPolicyShow)).Methods("GET")
+	router.HandleFunc("/system/policy", api("system.policy.update", SystemPolicyUpdate)).Methods("POST")
 	router.HandleFunc("/system/releases", api("system.release.list", SystemReleases)).Methods("GET")
+	router.HandleFunc("/pipelines", api("pipeline.list", PipelineList)).Methods("GET")
+	router.HandleFunc("/pipelines", api("pipeline.create", PipelineCreate)).Methods("POST")
+	router.HandleFunc("/pipelines/{pipeline}", api("pipeline.delete", PipelineDelete)).Methods("DELETE")
+	router.HandleFunc("/pipelines/{pipeline}/runs", api("pipeline.run.list", PipelineRunList)).Methods("GET")
+	router.HandleFunc("/pipelines/{pipeline}/runs", api("pipeline.run.create", PipelineRunCreate)).Methods("POST")
+	router.HandleFunc("/pipelines/{pipeline}/runs/{id}/approve", api("pipeline.run.approve", PipelineRunApprove)).Methods("POST")
+	router.HandleFunc("/pipelines/{pipeline}/runs/{id}/stages/{stage}", api("pipeline.run.stage.update", PipelineRunStageUpdate)).Methods("PUT")
 	router.HandleFunc("/switch", api("switch", Switch)).Methods("POST")
+	router.HandleFunc("/users", api("user.list", UserList)).Methods("GET")
+	router.HandleFunc("/users", api("user.create", UserCreate)).Methods("POST")
+	router.HandleFunc("/users/{user}", api("user.delete", UserDelete)).Methods("DELETE")
+
+	// public
+	router.HandleFunc("/status/{app}", StatusPage).Methods("GET")
+	router.HandleFunc("/.well-known/acme-challenge/{token}", ACMEChallenge).Methods("GET")
+	router.HandleFunc("/webhooks/github", GitHubWebhook).Methods("POST")
+	router.HandleFunc("/webhooks/gitlab", GitLabWebhook).Methods("POST")
 
 	// websockets
+	router.Handle("/apps/{app}/events", ws("app.events", AppEvents)).Methods("GET")
 	router.Handle("/apps/{app}/logs", ws("app.logs", AppLogs)).Methods("GET")
 	router.Handle("/apps/{app}/builds/{build}/logs", ws("build.logs", BuildLogs)).Methods("GET")
+	router.Handle("/apps/{app}/processes/{pid}/debug", ws("process.debug.attach", ProcessDebugAttached)).Methods("GET")
 	router.Handle("/apps/{app}/processes/{pid}/exec", ws("process.exec.attach", ProcessExecAttached)).Methods("GET")
 	router.Handle("/apps/{app}/processes/{process}/run", ws("process.run.attach", ProcessRunAttached)).Methods("GET")
 	router.Handle("/instances/{id}/ssh", ws("instance.ssh", InstanceSSH)).Methods("GET")
 	router.Handle("/proxy/{host}/{port}", ws("proxy", Proxy)).Methods("GET")
 	router.Handle("/system/logs", ws("system.logs", SystemLogs)).Methods("GET")
+	router.HandleFunc("/uploads", api("upload.create", UploadCreate)).Methods("POST")
+	router.HandleFunc("/uploads/{id}", api("upload.show", UploadShow)).Methods("GET")
+	router.HandleFunc("/uploads/{id}", api("upload.chunk", UploadChunk)).Methods("PUT")
 
 	// utility
 	router.HandleFunc("/boom", UtilityBoom).Methods("GET")
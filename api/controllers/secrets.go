@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/convox/rack/api/httperr"
+	"github.com/convox/rack/api/models"
+	"github.com/gorilla/mux"
+)
+
+func SecretList(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	secrets, err := models.ListSecrets(app)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, secrets)
+}
+
+func SecretShow(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	vars := mux.Vars(r)
+	app := vars["app"]
+	name := vars["name"]
+
+	value, err := models.GetSecret(app, name)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil && strings.HasPrefix(err.Error(), "no such secret") {
+		return httperr.Errorf(404, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, value)
+}
+
+// SecretSet stores the request body as a secret so the value is never part of a form the audit log summarizes
+func SecretSet(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	vars := mux.Vars(r)
+	app := vars["app"]
+	name := vars["name"]
+
+	if err := immutable("secret.set", r, app, "changing secrets"); err != nil {
+		return err
+	}
+
+	value, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	err = models.SetSecret(app, name, string(value))
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil && (strings.HasPrefix(err.Error(), "secret names") || strings.HasPrefix(err.Error(), "rack has no encryption key")) {
+		return httperr.Errorf(403, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderSuccess(rw)
+}
+
+func SecretDelete(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	vars := mux.Vars(r)
+	app := vars["app"]
+	name := vars["name"]
+
+	if err := immutable("secret.delete", r, app, "changing secrets"); err != nil {
+		return err
+	}
+
+	err := models.DeleteSecret(app, name)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil && strings.HasPrefix(err.Error(), "no such secret") {
+		return httperr.Errorf(404, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderSuccess(rw)
+}
@@ -30,6 +30,15 @@ func ServiceShow(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 		return httperr.Server(err)
 	}
 
+	if models.EnvironmentLinkTypes[sv.Type] {
+		links, err := models.ListServiceLinks(sv.Name)
+		if err != nil {
+			return httperr.Server(err)
+		}
+
+		sv.Links = links
+	}
+
 	return RenderJson(rw, sv)
 }
 
@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/convox/logger"
+	"github.com/convox/rack/api/models"
+)
+
+// AuthSchemes returns the ways a client can authenticate, advertised on every response so
+// clients can sign requests instead of sending the password to racks that accept it. A client
+// asking for a user key is told to use basic auth when the rack holds no signing secret for it.
+func AuthSchemes(r *http.Request) string {
+	if !models.SignaturesAvailable() {
+		return "basic"
+	}
+
+	if user := r.Header.Get("Key-User"); user != "" && r.Header.Get("Authorization") == "" && !models.UserKeyCanSign(user) {
+		return "basic"
+	}
+
+	return "basic, signature"
+}
+
+const (
+	// signatureScheme prefixes the Authorization header of a signed request
+	signatureScheme = "Convox-HMAC-SHA256"
+
+	// signatureWindow is how far the timestamp of a signed request may be from now
+	signatureWindow = 5 * time.Minute
+
+	// signatureMaxBody is the largest body a client hashes, larger bodies are sent unsigned
+	signatureMaxBody = 10 * 1024 * 1024
+
+	// unsignedPayload is sent as the body hash of streamed uploads
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+// unsignedRoutes are the streamed uploads and builds that may send UNSIGNED-PAYLOAD in place of
+// the hash of their body. Every other request has to sign its body.
+var unsignedRoutes = []struct {
+	method string
+	path   *regexp.Regexp
+}{
+	{"POST", regexp.MustCompile(`^/apps/[^/]+/builds(/import)?$`)},
+	{"POST", regexp.MustCompile(`^/index/update$`)},
+	{"PUT", regexp.MustCompile(`^/uploads/[^/]+$`)},
+}
+
+// unsignedAllowed returns true if r may send its body unsigned
+func unsignedAllowed(r *http.Request) bool {
+	for _, u := range unsignedRoutes {
+		if r.Method == u.method && u.path.MatchString(r.URL.Path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// signatureCheck verifies a request signed with an HMAC of its method, uri,
+// timestamp and body hash keyed with password
+func signatureCheck(r *http.Request, password string) bool {
+	signature := strings.TrimPrefix(r.Header.Get("Authorization"), signatureScheme+" Signature=")
+
+	ts, err := strconv.ParseInt(r.Header.Get("Timestamp"), 10, 64)
+	if err != nil {
+		return false
+	}
+
+	now := time.Now()
+
+	if d := now.Sub(time.Unix(ts, 0)); d > signatureWindow || d < -signatureWindow {
+		return false
+	}
+
+	hash := r.Header.Get("Content-Sha256")
+
+	if hash == unsignedPayload && !unsignedAllowed(r) {
+		return false
+	}
+
+	if hash != unsignedPayload {
+		data, err := ioutil.ReadAll(io.LimitReader(r.Body, signatureMaxBody+1))
+		if err != nil || len(data) > signatureMaxBody {
+			return false
+		}
+
+		r.Body = ioutil.NopCloser(bytes.NewReader(data))
+
+		sum := sha256.Sum256(data)
+
+		if hex.EncodeToString(sum[:]) != hash {
+			return false
+		}
+	}
+
+	expected := requestSignature(password, r.Method, r.URL.RequestURI(), r.Header.Get("Timestamp"), hash)
+
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return false
+	}
+
+	// signatures are remembered for as long as their timestamp is accepted
+	recorded, err := models.RecordSignature(signature, now.Add(2*signatureWindow))
+	if err != nil {
+		logger.New("ns=api.controllers").At("signatureCheck").Error(err)
+		return false
+	}
+
+	return recorded
+}
+
+// requestSignature must match the signature computed by the client
+func requestSignature(key, method, uri, timestamp, hash string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%s", method, uri, timestamp, hash)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
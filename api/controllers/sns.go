@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"strings"
@@ -9,6 +10,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/sns"
 	"github.com/convox/rack/api/models"
+	cmodels "github.com/convox/rack/client/models"
 	"github.com/ddollar/logger"
 )
 
@@ -70,6 +72,17 @@ func SNSProxy(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if id := r.FormValue("notification"); id != "" {
+		if err := deliverNotification(id, payload); err != nil {
+			log.Error(err)
+			http.Error(w, err.Error(), 500)
+			return
+		}
+
+		w.Write([]byte("ok"))
+		return
+	}
+
 	url := r.FormValue("endpoint")
 	resp, err := http.Post(url, "application/json", strings.NewReader(payload["Message"]))
 	if err != nil {
@@ -81,3 +94,31 @@ func SNSProxy(w http.ResponseWriter, r *http.Request) {
 	log.Log("proxied=true status=%s", resp.Status)
 	w.Write([]byte("ok"))
 }
+
+// deliverNotification sends an event from the notification topic on to a notification. Events
+// for notifications that have since been removed are dropped.
+func deliverNotification(id string, payload map[string]string) error {
+	if payload["TopicArn"] != models.NotificationTopic {
+		return fmt.Errorf("unknown topic: %s", payload["TopicArn"])
+	}
+
+	n, err := models.GetNotification(id)
+	if err != nil && strings.HasPrefix(err.Error(), "no such notification") {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var e cmodels.NotifyEvent
+
+	if err := json.Unmarshal([]byte(payload["Message"]), &e); err != nil {
+		return err
+	}
+
+	if !n.Matches(e) {
+		return nil
+	}
+
+	return n.Deliver(e)
+}
@@ -3,6 +3,7 @@ package controllers
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/convox/rack/api/httperr"
 	"github.com/convox/rack/api/models"
@@ -48,6 +49,14 @@ func SSLUpdate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 		return httperr.Errorf(404, "%s", err)
 	}
 
+	if err != nil && strings.HasPrefix(err.Error(), "no such certificate") {
+		return httperr.Errorf(404, "%s", err)
+	}
+
+	if err != nil && (strings.HasPrefix(err.Error(), "can not update app") || strings.HasPrefix(err.Error(), "Process and port") || strings.HasSuffix(err.Error(), "pending validation")) {
+		return httperr.Errorf(403, "%s", err)
+	}
+
 	if err != nil {
 		return httperr.Server(err)
 	}
@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/convox/rack/api/httperr"
+	"github.com/convox/rack/api/models"
+	"github.com/gorilla/mux"
+)
+
+var statusPage = template.Must(template.New("status").Funcs(template.FuncMap{
+	"uptime": func(ds models.StatusDays) string {
+		return fmt.Sprintf("%.2f", ds.Uptime())
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.App}} status</title>
+<style>
+body { font-family: sans-serif; max-width: 40em; margin: 2em auto; color: #333; }
+.state { padding: 1em; border-radius: 4px; color: #fff; }
+.operational { background: #2a2; }
+.degraded { background: #e90; }
+.outage { background: #c22; }
+.days { display: flex; margin-top: 1em; }
+.day { flex: 1; height: 2em; margin-right: 1px; background: #2a2; }
+.day.partial { background: #e90; }
+</style>
+</head>
+<body>
+<h1>{{.App}}</h1>
+<div class="state {{.State}}">{{.State}}{{if .Message}}: {{.Message}}{{end}}</div>
+<div class="days">{{range .Days}}<div class="day{{if lt .Up .Checks}} partial{{end}}" title="{{.Date}}: {{.Up}}/{{.Checks}} checks passed"></div>{{end}}</div>
+<p>{{uptime .Days}}% uptime over the last {{len .Days}} days</p>
+</body>
+</html>
+`))
+
+func AppStatusShow(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	s, err := models.GetAppStatus(app)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, s)
+}
+
+func AppStatusUpdate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	s, err := models.GetAppStatus(app)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	if state := GetForm(r, "state"); state != "" {
+		if err := s.SetState(state, GetForm(r, "message")); err != nil {
+			return httperr.Invalid("state", "%s", err)
+		}
+	}
+
+	switch GetForm(r, "public") {
+	case "":
+	case "true":
+		s.Public = true
+	case "false":
+		s.Public = false
+	default:
+		return httperr.Invalid("public", "public must be true or false")
+	}
+
+	if err := s.Save(); err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, s)
+}
+
+// StatusPage renders the public status page of an app without authentication
+func StatusPage(rw http.ResponseWriter, r *http.Request) {
+	app := mux.Vars(r)["app"]
+
+	s, err := models.GetAppStatus(app)
+	if err != nil || !s.Public {
+		http.NotFound(rw, r)
+		return
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), "application/json") {
+		rw.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(rw).Encode(s)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+	statusPage.Execute(rw, s)
+}
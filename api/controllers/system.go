@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"golang.org/x/net/websocket"
@@ -32,7 +33,7 @@ func SystemUpdate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 	if cc := GetForm(r, "count"); cc != "" {
 		c, err := strconv.Atoi(cc)
 		if err != nil {
-			return httperr.Errorf(403, "count must be numeric")
+			return httperr.Invalid("count", "count must be numeric")
 		}
 
 		switch {
@@ -41,7 +42,7 @@ func SystemUpdate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 		case c == -1:
 			// -1 indicates no change
 		case c <= 1:
-			return httperr.Errorf(403, "count must be greater than 1")
+			return httperr.Invalid("count", "count must be greater than 1")
 		default:
 			rack.Count = c
 		}
@@ -51,7 +52,19 @@ func SystemUpdate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 		rack.Type = t
 	}
 
-	if v := GetForm(r, "version"); v != "" {
+	if v := GetForm(r, "version"); v != "" && v != rack.Version {
+		// refuse updates that would take apps down unless forced
+		if GetForm(r, "force") != "true" {
+			checks, err := models.Provider().SystemCheck(v)
+			if err != nil {
+				return httperr.Server(err)
+			}
+
+			if failed := checks.Failed(); len(failed) > 0 {
+				return httperr.Errorf(403, "pre-flight checks failed: %s", strings.Join(failed, ", "))
+			}
+		}
+
 		rack.Version = v
 	}
 
@@ -65,6 +78,31 @@ func SystemUpdate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 	return RenderJson(rw, rack)
 }
 
+// SystemCheck runs the pre-flight checks for updating the rack to a version
+func SystemCheck(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	version := r.URL.Query().Get("version")
+	if version == "" {
+		return httperr.Errorf(403, "must specify a version")
+	}
+
+	checks, err := models.Provider().SystemCheck(version)
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, checks)
+}
+
+// SystemHealth runs a deep health check of the services the rack depends on
+func SystemHealth(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	checks, err := models.Provider().SystemHealth()
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, checks)
+}
+
 func SystemCapacity(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 	capacity, err := models.Provider().CapacityGet()
 	if err != nil {
@@ -49,6 +49,24 @@ func TestSystemShowRackFetchError(t *testing.T) {
 	})
 }
 
+func TestSystemHealth(t *testing.T) {
+	models.Test(t, func() {
+		checks := structs.SystemChecks{
+			{Name: "dynamodb", Status: "pass"},
+			{Name: "certificate", Status: "fail", Message: "certificate expires in 2 days"},
+		}
+
+		models.TestProvider.On("SystemHealth").Return(checks, nil)
+
+		hf := test.NewHandlerFunc(controllers.HandlerFunc)
+
+		if assert.Nil(t, hf.Request("GET", "/system/health", nil)) {
+			hf.AssertCode(t, 200)
+			hf.AssertJSON(t, `[{"name":"dynamodb","status":"pass","message":""},{"name":"certificate","status":"fail","message":"certificate expires in 2 days"}]`)
+		}
+	})
+}
+
 func TestSystemUpdate(t *testing.T) {
 	models.Test(t, func() {
 		before := &structs.System{
@@ -69,6 +87,7 @@ func TestSystemUpdate(t *testing.T) {
 		}
 
 		models.TestProvider.On("SystemGet").Return(before, nil)
+		models.TestProvider.On("SystemCheck", "latest").Return(structs.SystemChecks{}, nil)
 		models.TestProvider.On("SystemSave", change).Return(nil)
 
 		hf := test.NewHandlerFunc(controllers.HandlerFunc)
@@ -85,6 +104,70 @@ func TestSystemUpdate(t *testing.T) {
 	})
 }
 
+func TestSystemUpdateChecksFailed(t *testing.T) {
+	models.Test(t, func() {
+		before := &structs.System{
+			Count:   3,
+			Name:    "test",
+			Region:  "us-test-1",
+			Status:  "running",
+			Type:    "t2.small",
+			Version: "dev",
+		}
+		checks := structs.SystemChecks{
+			{Name: "template", Status: "fail", Message: "would replace resources used by running apps: Cluster"},
+			{Name: "parameters", Status: "pass"},
+		}
+
+		models.TestProvider.On("SystemGet").Return(before, nil)
+		models.TestProvider.On("SystemCheck", "latest").Return(checks, nil)
+
+		hf := test.NewHandlerFunc(controllers.HandlerFunc)
+
+		v := url.Values{}
+		v.Add("version", "latest")
+
+		if assert.Nil(t, hf.Request("PUT", "/system", v)) {
+			hf.AssertCode(t, 403)
+			hf.AssertError(t, "pre-flight checks failed: template")
+		}
+	})
+}
+
+func TestSystemUpdateChecksForced(t *testing.T) {
+	models.Test(t, func() {
+		before := &structs.System{
+			Count:   3,
+			Name:    "test",
+			Region:  "us-test-1",
+			Status:  "running",
+			Type:    "t2.small",
+			Version: "dev",
+		}
+		change := structs.System{
+			Count:   3,
+			Name:    "test",
+			Region:  "us-test-1",
+			Status:  "running",
+			Type:    "t2.small",
+			Version: "latest",
+		}
+
+		models.TestProvider.On("SystemGet").Return(before, nil)
+		models.TestProvider.On("SystemSave", change).Return(nil)
+
+		hf := test.NewHandlerFunc(controllers.HandlerFunc)
+
+		v := url.Values{}
+		v.Add("version", "latest")
+		v.Add("force", "true")
+
+		if assert.Nil(t, hf.Request("PUT", "/system", v)) {
+			hf.AssertCode(t, 200)
+		}
+	})
+}
+
 func TestSystemUpdateRackFetchError(t *testing.T) {
 	models.Test(t, func() {
 		models.TestProvider.On("SystemGet").Return(nil, fmt.Errorf("some error"))
@@ -118,6 +201,7 @@ func TestSystemUpdateCountNoChange(t *testing.T) {
 		}
 
 		models.TestProvider.On("SystemGet").Return(before, nil)
+		models.TestProvider.On("SystemCheck", "latest").Return(structs.SystemChecks{}, nil)
 		models.TestProvider.On("SystemSave", change).Return(nil)
 
 		hf := test.NewHandlerFunc(controllers.HandlerFunc)
@@ -313,3 +397,36 @@ func TestSystemReleasesError(t *testing.T) {
 		}
 	})
 }
+
+func TestSystemEventsBadType(t *testing.T) {
+	models.Test(t, func() {
+		hf := test.NewHandlerFunc(controllers.HandlerFunc)
+
+		if assert.Nil(t, hf.Request("GET", "/system/events", url.Values{"type": []string{"bogus"}})) {
+			hf.AssertCode(t, 403)
+			hf.AssertError(t, "type must be one of alarm, api, app, crash, error, instance, rack, release, scaling, secret, stack")
+		}
+	})
+}
+
+func TestSystemEventsBadSince(t *testing.T) {
+	models.Test(t, func() {
+		hf := test.NewHandlerFunc(controllers.HandlerFunc)
+
+		if assert.Nil(t, hf.Request("GET", "/system/events", url.Values{"type": []string{"scaling"}, "since": []string{"7x"}})) {
+			hf.AssertCode(t, 403)
+			hf.AssertError(t, "since must be a duration like 24h or 7d: 7x")
+		}
+	})
+}
+
+func TestSystemEventsUnavailable(t *testing.T) {
+	models.Test(t, func() {
+		hf := test.NewHandlerFunc(controllers.HandlerFunc)
+
+		if assert.Nil(t, hf.Request("GET", "/system/events", url.Values{"since": []string{"7d"}})) {
+			hf.AssertCode(t, 500)
+			hf.AssertError(t, "rack events are not available, update your rack with `convox rack update`")
+		}
+	})
+}
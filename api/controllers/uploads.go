@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/convox/rack/api/httperr"
+	"github.com/convox/rack/api/models"
+	"github.com/gorilla/mux"
+)
+
+// UploadCreate starts a chunked upload that a build or import can later use as its source
+func UploadCreate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	size, err := strconv.ParseInt(r.FormValue("size"), 10, 64)
+	if err != nil || size < 1 {
+		return httperr.Invalid("size", "size must be a positive number")
+	}
+
+	u, err := models.NewUpload(size)
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, u)
+}
+
+// UploadShow returns how much of an upload the rack has received so a client can resume it
+func UploadShow(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	id := mux.Vars(r)["id"]
+
+	u, err := models.GetUpload(id)
+	if err != nil && strings.HasPrefix(err.Error(), "no such upload") {
+		return httperr.Errorf(404, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, u)
+}
+
+// UploadChunk appends the request body to an upload at the offset given in the query string
+func UploadChunk(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	id := mux.Vars(r)["id"]
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	offset, err := strconv.ParseInt(r.URL.Query().Get("offset"), 10, 64)
+	if err != nil {
+		return httperr.Invalid("offset", "offset must be a number")
+	}
+
+	u, err := models.GetUpload(id)
+	if err != nil && strings.HasPrefix(err.Error(), "no such upload") {
+		return httperr.Errorf(404, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	if offset != u.Offset {
+		return httperr.Errorf(409, "upload is at offset %d", u.Offset)
+	}
+
+	if offset+int64(len(data)) > u.Size {
+		return httperr.Invalid("offset", "chunk exceeds the upload size of %d bytes", u.Size)
+	}
+
+	err = u.Write(offset, data)
+	if _, ok := err.(*models.UploadOffsetError); ok {
+		return httperr.Errorf(403, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, u)
+}
+
+// uploadSource returns the contents of the upload named by the upload form value, if any
+func uploadSource(r *http.Request) (io.Reader, *httperr.Error) {
+	id := r.FormValue("upload")
+
+	if id == "" {
+		return nil, nil
+	}
+
+	u, err := models.GetUpload(id)
+	if err != nil && strings.HasPrefix(err.Error(), "no such upload") {
+		return nil, httperr.Errorf(404, "%s", err)
+	}
+	if err != nil {
+		return nil, httperr.Server(err)
+	}
+
+	if !u.Complete() {
+		return nil, httperr.Errorf(403, "upload is incomplete: %d of %d bytes received", u.Offset, u.Size)
+	}
+
+	return u.Reader(), nil
+}
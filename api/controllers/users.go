@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/convox/rack/api/httperr"
+	"github.com/convox/rack/api/models"
+	"github.com/gorilla/mux"
+)
+
+// UserList returns the users that have a key for the rack
+func UserList(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	if err := requirePassword(r, "listing users"); err != nil {
+		return err
+	}
+
+	users, err := models.ListUserKeys()
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, users)
+}
+
+// UserCreate makes a key for a user and returns it, the key can not be shown again
+func UserCreate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	if err := requirePassword(r, "creating user keys"); err != nil {
+		return err
+	}
+
+	user := GetForm(r, "user")
+
+	key, err := models.CreateUserKey(user)
+	if err != nil && strings.HasPrefix(err.Error(), "invalid user name") {
+		return httperr.Errorf(403, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, map[string]string{"user": user, "key": key})
+}
+
+// UserDelete removes the key of a user
+func UserDelete(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	if err := requirePassword(r, "deleting user keys"); err != nil {
+		return err
+	}
+
+	user := mux.Vars(r)["user"]
+
+	err := models.DeleteUserKey(user)
+	if err != nil && strings.HasPrefix(err.Error(), "no such user") {
+		return httperr.Errorf(404, "%s", err)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderSuccess(rw)
+}
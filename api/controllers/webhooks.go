@@ -0,0 +1,154 @@
+package controllers
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/convox/logger"
+	"github.com/convox/rack/api/httperr"
+	"github.com/convox/rack/api/models"
+	"github.com/gorilla/mux"
+)
+
+// maxPushBody is the largest push webhook body that is read
+const maxPushBody = 5 * 1024 * 1024
+
+func AppWebhookShow(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	app := mux.Vars(r)["app"]
+
+	a, err := models.GetApp(app)
+	if awsError(err) == "ValidationError" {
+		return httperr.Errorf(404, "no such app: %s", app)
+	}
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	w, err := a.Webhook()
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, w)
+}
+
+// GitHubWebhook builds and promotes the apps whose Repo and Branch match a GitHub push event
+func GitHubWebhook(rw http.ResponseWriter, r *http.Request) {
+	switch r.Header.Get("X-GitHub-Event") {
+	case "ping":
+		rw.Write([]byte("pong"))
+		return
+	case "push":
+	default:
+		rw.Write([]byte("ignored"))
+		return
+	}
+
+	receivePush(rw, r, models.ParseGitHubPush, func(a *models.App, body []byte) (bool, error) {
+		signature := r.Header.Get("X-Hub-Signature-256")
+
+		if signature == "" {
+			signature = r.Header.Get("X-Hub-Signature")
+		}
+
+		return a.ValidGitHubSignature(body, signature)
+	})
+}
+
+// GitLabWebhook builds and promotes the apps whose Repo and Branch match a GitLab push hook
+func GitLabWebhook(rw http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Gitlab-Event") != "Push Hook" {
+		rw.Write([]byte("ignored"))
+		return
+	}
+
+	receivePush(rw, r, models.ParseGitLabPush, func(a *models.App, body []byte) (bool, error) {
+		return a.ValidGitLabToken(r.Header.Get("X-Gitlab-Token"))
+	})
+}
+
+// receivePush deploys a push to every matching app the webhook is valid for and responds with
+// the names of those apps
+func receivePush(rw http.ResponseWriter, r *http.Request, parse func([]byte) (*models.Push, error), valid func(*models.App, []byte) (bool, error)) {
+	log := logger.New("ns=api.controllers").At("receivePush")
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxPushBody))
+	if err != nil {
+		http.Error(rw, err.Error(), 400)
+		return
+	}
+
+	p, err := parse(body)
+	if err != nil {
+		http.Error(rw, err.Error(), 400)
+		return
+	}
+
+	if p == nil {
+		rw.Write([]byte("ignored"))
+		return
+	}
+
+	apps, err := models.PushApps(p)
+	if err != nil {
+		log.Error(err)
+		http.Error(rw, err.Error(), 500)
+		return
+	}
+
+	deployed := []string{}
+
+	for i := range apps {
+		a := &apps[i]
+
+		ok, err := valid(a, body)
+		if err != nil {
+			log.Error(err)
+			http.Error(rw, err.Error(), 500)
+			return
+		}
+
+		if !ok {
+			log.Logf("app=%s valid=false", a.Name)
+			continue
+		}
+
+		go deployPush(a, p)
+
+		deployed = append(deployed, a.Name)
+	}
+
+	// a push that matched apps but none of their secrets is most likely misconfigured
+	if len(apps) > 0 && len(deployed) == 0 {
+		http.Error(rw, "invalid signature", 403)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(rw).Encode(map[string][]string{"apps": deployed})
+}
+
+func deployPush(a *models.App, p *models.Push) {
+	log := logger.New("ns=api.controllers").At("deployPush").Namespace("app=%s commit=%s", a.Name, p.Commit)
+
+	data := map[string]string{
+		"app":    a.Name,
+		"branch": p.Branch,
+		"commit": p.Commit,
+		"user":   p.User,
+	}
+
+	release, err := a.DeployPush(p)
+	if err != nil {
+		log.Error(err)
+		models.NotifyError("build:push", err, data)
+		return
+	}
+
+	data["release"] = release
+
+	log.Logf("release=%s", release)
+	models.NotifySuccess("build:push", data)
+}
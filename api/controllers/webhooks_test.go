@@ -0,0 +1,53 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/convox/rack/api/controllers"
+	"github.com/convox/rack/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitHubWebhookPing(t *testing.T) {
+	aws := test.StubAws()
+	defer aws.Close()
+
+	r, _ := http.NewRequest("POST", "http://convox/webhooks/github", strings.NewReader(`{"zen":"Keep it logically awesome."}`))
+	r.Header.Set("X-GitHub-Event", "ping")
+
+	w := httptest.NewRecorder()
+	controllers.HandlerFunc(w, r)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "pong", w.Body.String())
+}
+
+func TestGitHubWebhookDeletedBranch(t *testing.T) {
+	aws := test.StubAws()
+	defer aws.Close()
+
+	r, _ := http.NewRequest("POST", "http://convox/webhooks/github", strings.NewReader(`{"ref":"refs/heads/feature","deleted":true}`))
+	r.Header.Set("X-GitHub-Event", "push")
+
+	w := httptest.NewRecorder()
+	controllers.HandlerFunc(w, r)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "ignored", w.Body.String())
+}
+
+func TestGitLabWebhookInvalidBody(t *testing.T) {
+	aws := test.StubAws()
+	defer aws.Close()
+
+	r, _ := http.NewRequest("POST", "http://convox/webhooks/gitlab", strings.NewReader(`not json`))
+	r.Header.Set("X-Gitlab-Event", "Push Hook")
+
+	w := httptest.NewRecorder()
+	controllers.HandlerFunc(w, r)
+
+	assert.Equal(t, 400, w.Code)
+}
@@ -1,6 +1,8 @@
 package crypt
 
 import (
+	"os"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -25,9 +27,20 @@ func (cc *Credentials) Retrieve() (credentials.Value, error) {
 	return creds, nil
 }
 
+// KMS returns a KMS client with the credentials of c, or the default credential chain
+// (such as an ECS task role) when c has none
 func KMS(c *Crypt) *kms.KMS {
-	return kms.New(session.New(), &aws.Config{
-		Credentials: credentials.NewCredentials(&Credentials{Crypt: c}),
-		Region:      aws.String(c.AwsRegion),
-	})
+	config := &aws.Config{
+		Region: aws.String(c.AwsRegion),
+	}
+
+	if c.AwsAccess != "" {
+		config.Credentials = credentials.NewCredentials(&Credentials{Crypt: c})
+	}
+
+	if e := os.Getenv("AWS_ENDPOINT"); e != "" {
+		config.Endpoint = aws.String(e)
+	}
+
+	return kms.New(session.New(), config)
 }
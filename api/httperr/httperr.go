@@ -13,6 +13,7 @@ const ErrorHandlerSkipLines = 7
 type Error struct {
 	code  int
 	err   error
+	field string
 	stack rollbar.Stack
 	trace []string
 }
@@ -40,6 +41,13 @@ func Errorf(code int, format string, args ...interface{}) *Error {
 	return New(code, fmt.Errorf(format, args...))
 }
 
+// Invalid returns a 403 error blaming the named request parameter
+func Invalid(field, format string, args ...interface{}) *Error {
+	e := New(403, fmt.Errorf(format, args...))
+	e.field = field
+	return e
+}
+
 func (e *Error) Code() int {
 	return e.code
 }
@@ -48,6 +56,11 @@ func (e *Error) Error() string {
 	return e.err.Error()
 }
 
+// Field is the request parameter the error is about, if any
+func (e *Error) Field() string {
+	return e.field
+}
+
 func (e *Error) Save() error {
 	rollbar.ErrorWithStack(rollbar.ERR, e.err, e.stack)
 	return nil
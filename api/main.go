@@ -2,10 +2,15 @@ package main
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/convox/logger"
 	"github.com/convox/rack/api/models"
 )
 
+// buildQueueInterval is how often this api process looks for queued builds it has room to start
+var buildQueueInterval = 10 * time.Second
+
 func recoverWith(f func(err error)) {
 	if r := recover(); r != nil {
 		if err, ok := r.(error); ok {
@@ -24,5 +29,23 @@ func main() {
 		models.PullAppImages()
 	}()
 
+	// queued builds are kept in the builds table so they survive a restart and
+	// can be started by whichever api process has a free build slot
+	go startBuildQueue()
+
 	startWeb()
 }
+
+func startBuildQueue() {
+	log := logger.New("ns=api.build_queue")
+
+	defer recoverWith(func(err error) {
+		log.Error(err)
+	})
+
+	for range time.Tick(buildQueueInterval) {
+		if err := models.Provider().BuildDequeue(); err != nil {
+			log.Error(err)
+		}
+	}
+}
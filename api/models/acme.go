@@ -0,0 +1,409 @@
+package models
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/convox/rack/acme"
+	"github.com/convox/rack/api/structs"
+)
+
+// ACMEChallenges are the ways a rack can prove control of a domain to Let's Encrypt. A dns
+// challenge adds a TXT record to the Route53 hosted zone of the domain. An http challenge is
+// answered by the rack under /.well-known/acme-challenge/ on its balancer, so port 80 of the
+// domain must reach the rack or redirect that path to it. App balancers send every request to
+// the app, so custom domains of apps are always proven with a dns challenge.
+var ACMEChallenges = map[string]string{
+	"dns":  "dns-01",
+	"http": "http-01",
+}
+
+// ACMERenewBefore is how long before it expires a certificate is renewed
+const ACMERenewBefore = 30 * 24 * time.Hour
+
+// ACMECertificate is a certificate issued by Let's Encrypt that the rack renews
+type ACMECertificate struct {
+	Id         string    `json:"id"`
+	Domains    []string  `json:"domains"`
+	Challenge  string    `json:"challenge"`
+	Expiration time.Time `json:"expiration"`
+
+	// Replaced is the certificate this one renewed, deleted once no listener uses it
+	Replaced string `json:"replaced,omitempty"`
+}
+
+var certificateParameter = regexp.MustCompile(`^(\w+)Port(\d+)Certificate$`)
+
+// GenerateACMECertificate issues a certificate for domains from Let's Encrypt, uploads it and
+// keeps track of it so that it is renewed before it expires
+func GenerateACMECertificate(domains []string, challenge string) (*structs.Certificate, error) {
+	if len(domains) < 1 || domains[0] == "" {
+		return nil, fmt.Errorf("must specify at least one domain")
+	}
+
+	if challenge == "" {
+		challenge = "dns"
+	}
+
+	if _, ok := ACMEChallenges[challenge]; !ok {
+		return nil, fmt.Errorf("challenge must be one of dns, http")
+	}
+
+	cert, err := issueACMECertificate(domains, challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	ac := &ACMECertificate{
+		Id:         cert.Id,
+		Domains:    domains,
+		Challenge:  challenge,
+		Expiration: cert.Expiration,
+	}
+
+	if err := saveACMECertificate(ac); err != nil {
+		return nil, err
+	}
+
+	NotifySuccess("certificate:generate", map[string]string{"id": cert.Id, "domains": strings.Join(domains, ",")})
+
+	return cert, nil
+}
+
+// ListACMECertificates returns the certificates issued by Let's Encrypt that the rack renews
+func ListACMECertificates() ([]ACMECertificate, error) {
+	keys, err := s3Keys(os.Getenv("SETTINGS_BUCKET"), "acme/certificates/")
+	if err != nil {
+		return nil, err
+	}
+
+	certs := []ACMECertificate{}
+
+	for _, key := range keys {
+		data, err := s3Get(os.Getenv("SETTINGS_BUCKET"), key)
+		if err != nil {
+			return nil, err
+		}
+
+		var ac ACMECertificate
+
+		if err := json.Unmarshal(data, &ac); err != nil {
+			return nil, err
+		}
+
+		certs = append(certs, ac)
+	}
+
+	return certs, nil
+}
+
+// RenewACMECertificate issues a new certificate for the domains of one that is about to expire
+// and moves every load balancer listener that uses the old certificate onto the new one
+func RenewACMECertificate(ac ACMECertificate) (*ACMECertificate, error) {
+	cert, err := issueACMECertificate(ac.Domains, ac.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	renewed := &ACMECertificate{
+		Id:         cert.Id,
+		Domains:    ac.Domains,
+		Challenge:  ac.Challenge,
+		Expiration: cert.Expiration,
+		Replaced:   ac.Id,
+	}
+
+	// keep track of the new certificate first so that a failed listener update is retried
+	// against the right certificate
+	if err := saveACMECertificate(renewed); err != nil {
+		return nil, err
+	}
+
+	if err := s3Delete(os.Getenv("SETTINGS_BUCKET"), acmeCertificateKey(ac.Id)); err != nil {
+		return nil, err
+	}
+
+	if err := replaceCertificate(ac.Id, renewed.Id); err != nil {
+		return nil, err
+	}
+
+	NotifySuccess("certificate:renew", map[string]string{"id": renewed.Id, "replaced": ac.Id, "domains": strings.Join(ac.Domains, ",")})
+
+	return renewed, nil
+}
+
+// DeleteReplacedCertificate deletes the certificate a renewal replaced once no listener uses it.
+// It returns false while the certificate is still in use.
+func DeleteReplacedCertificate(ac *ACMECertificate) (bool, error) {
+	if ac.Replaced == "" {
+		return true, nil
+	}
+
+	if err := replaceCertificate(ac.Replaced, ac.Id); err != nil {
+		return false, err
+	}
+
+	err := Provider().CertificateDelete(ac.Replaced)
+	if awserrCode(err) == "DeleteConflict" {
+		return false, nil
+	}
+	if err != nil && awserrCode(err) != "NoSuchEntity" {
+		return false, err
+	}
+
+	ac.Replaced = ""
+
+	return true, saveACMECertificate(ac)
+}
+
+// ACMEChallengeResponse returns the key authorization of an http challenge the rack is answering
+func ACMEChallengeResponse(token string) (string, error) {
+	data, err := s3Get(os.Getenv("SETTINGS_BUCKET"), acmeChallengeKey(token))
+	if awserrCode(err) == "NoSuchKey" {
+		return "", fmt.Errorf("no such challenge: %s", token)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func issueACMECertificate(domains []string, challenge string) (*structs.Certificate, error) {
+	c, err := acmeClient()
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := acmeAppHosts{}
+
+	if challenge == "http" {
+		hosts, err = appHosts()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	issued, err := c.ObtainWith(domains, func(domain string) acme.Solver {
+		if challenge == "dns" || hosts.served(domain) {
+			return acmeDNSSolver{}
+		}
+
+		return acmeHTTPSolver{}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return Provider().CertificateCreate(issued.Certificate, issued.PrivateKey, issued.Chain)
+}
+
+// acmeClient returns a client for the Let's Encrypt account of the rack, creating the account
+// the first time
+func acmeClient() (*acme.Client, error) {
+	bucket := os.Getenv("SETTINGS_BUCKET")
+
+	data, err := s3Get(bucket, "acme/account.pem")
+	if err != nil && awserrCode(err) != "NoSuchKey" {
+		return nil, err
+	}
+
+	var key *ecdsa.PrivateKey
+
+	if err == nil {
+		key, err = acme.DecodeKey(data)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		key, err = acme.GenerateKey()
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := acme.EncodeKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := S3Put(bucket, "acme/account.pem", data, false); err != nil {
+			return nil, err
+		}
+	}
+
+	directory := acme.LetsEncrypt
+
+	if d := os.Getenv("ACME_DIRECTORY"); d != "" {
+		directory = d
+	}
+
+	c := acme.NewClient(directory, key)
+
+	if err := c.Register(os.Getenv("ACME_EMAIL")); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// replaceCertificate points the listeners of apps that use one certificate at another. An app
+// is updated one listener at a time, so apps that are updating or have more listeners to move
+// are left for a later pass.
+func replaceCertificate(old, id string) error {
+	apps, err := ListApps()
+	if err != nil {
+		return err
+	}
+
+	for _, a := range apps {
+		if a.Status != "running" {
+			continue
+		}
+
+		for key, value := range a.Parameters {
+			m := certificateParameter.FindStringSubmatch(key)
+
+			if m == nil || !strings.HasSuffix(value, "/"+old) {
+				continue
+			}
+
+			port, err := strconv.Atoi(m[2])
+			if err != nil {
+				return err
+			}
+
+			if _, err := UpdateSSL(a.Name, DashName(m[1]), port, id); err != nil {
+				return fmt.Errorf("unable to update certificate of %s: %s", a.Name, err)
+			}
+
+			break
+		}
+	}
+
+	return nil
+}
+
+func saveACMECertificate(ac *ACMECertificate) error {
+	data, err := json.Marshal(ac)
+	if err != nil {
+		return err
+	}
+
+	return S3Put(os.Getenv("SETTINGS_BUCKET"), acmeCertificateKey(ac.Id), data, false)
+}
+
+func acmeCertificateKey(id string) string {
+	return fmt.Sprintf("acme/certificates/%s.json", id)
+}
+
+func acmeChallengeKey(token string) string {
+	return fmt.Sprintf("acme/challenges/%s", token)
+}
+
+// acmeLookupCNAME resolves the canonical name of a domain, replaced in tests
+var acmeLookupCNAME = net.LookupCNAME
+
+// acmeAppHosts are the custom domains of apps and the hostnames of their balancers
+type acmeAppHosts struct {
+	domains   map[string]bool
+	balancers map[string]bool
+}
+
+// appHosts collects the custom domains and balancers of every app of the rack
+func appHosts() (acmeAppHosts, error) {
+	hosts := acmeAppHosts{domains: map[string]bool{}, balancers: map[string]bool{}}
+
+	apps, err := ListApps()
+	if err != nil {
+		return hosts, err
+	}
+
+	for _, a := range apps {
+		for key, value := range a.Outputs {
+			if key == "BalancerHost" || domainBalancer.MatchString(key) {
+				hosts.balancers[strings.ToLower(value)] = true
+			}
+		}
+
+		domains, err := ListDomains(a.Name)
+		if err != nil {
+			return hosts, err
+		}
+
+		for _, d := range domains {
+			hosts.domains[d.Domain] = true
+		}
+	}
+
+	return hosts, nil
+}
+
+// served returns true if a domain reaches an app balancer, either as a custom domain the rack
+// added for an app or through a CNAME record
+func (h acmeAppHosts) served(domain string) bool {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+
+	if h.domains[domain] || h.domains["*."+domain] {
+		return true
+	}
+
+	if len(h.balancers) == 0 {
+		return false
+	}
+
+	cname, err := acmeLookupCNAME(domain)
+	if err != nil {
+		return false
+	}
+
+	return h.balancers[strings.ToLower(strings.TrimSuffix(cname, "."))]
+}
+
+// acmeHTTPSolver answers http challenges from the settings bucket so that any api process can respond
+type acmeHTTPSolver struct{}
+
+func (acmeHTTPSolver) Type() string {
+	return "http-01"
+}
+
+func (acmeHTTPSolver) Present(domain, token, keyAuth string) error {
+	return S3Put(os.Getenv("SETTINGS_BUCKET"), acmeChallengeKey(token), []byte(keyAuth), false)
+}
+
+func (acmeHTTPSolver) CleanUp(domain, token, keyAuth string) error {
+	return s3Delete(os.Getenv("SETTINGS_BUCKET"), acmeChallengeKey(token))
+}
+
+// acmeDNSSolver answers dns challenges with a TXT record in the Route53 hosted zone of the domain
+type acmeDNSSolver struct{}
+
+func (acmeDNSSolver) Type() string {
+	return "dns-01"
+}
+
+func (acmeDNSSolver) Present(domain, token, keyAuth string) error {
+	return acmeDNSChange("UPSERT", domain, keyAuth)
+}
+
+func (acmeDNSSolver) CleanUp(domain, token, keyAuth string) error {
+	return acmeDNSChange("DELETE", domain, keyAuth)
+}
+
+func acmeDNSChange(action, domain, keyAuth string) error {
+	// a wildcard is validated with the record of its base domain
+	domain = strings.TrimPrefix(domain, "*.")
+
+	zone, err := route53Zone(domain)
+	if err != nil {
+		return err
+	}
+
+	return route53ChangeTXT(zone, action, fmt.Sprintf("_acme-challenge.%s.", domain), acme.DNSRecord(keyAuth))
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestACMEAppHostsServed(t *testing.T) {
+	defer func(lookup func(string) (string, error)) { acmeLookupCNAME = lookup }(acmeLookupCNAME)
+
+	acmeLookupCNAME = func(domain string) (string, error) {
+		switch domain {
+		case "www.example.org":
+			return "myapp-web-abcdef-123.us-east-1.elb.amazonaws.com.", nil
+		case "rack.example.org":
+			return "convox-123.us-east-1.elb.amazonaws.com.", nil
+		}
+
+		return "", fmt.Errorf("no such host")
+	}
+
+	hosts := acmeAppHosts{
+		domains:   map[string]bool{"shop.example.org": true, "*.apps.example.org": true},
+		balancers: map[string]bool{"myapp-web-abcdef-123.us-east-1.elb.amazonaws.com": true},
+	}
+
+	// custom domains of apps and domains that point at app balancers are proven with dns
+	assert.True(t, hosts.served("shop.example.org"))
+	assert.True(t, hosts.served("apps.example.org"))
+	assert.True(t, hosts.served("WWW.example.org."))
+
+	// the rack balancer answers http challenges
+	assert.False(t, hosts.served("rack.example.org"))
+	assert.False(t, hosts.served("missing.example.org"))
+}
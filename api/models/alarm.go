@@ -0,0 +1,234 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/convox/rack/manifest"
+)
+
+// AlarmMetrics maps the metrics an app can alarm on to the stack parameter that enables them.
+// Latency and 5xx alarms are only created for processes behind a load balancer.
+var AlarmMetrics = map[string]string{
+	"5xx":     "Alarm5xx",
+	"cpu":     "AlarmCpu",
+	"latency": "AlarmLatency",
+}
+
+// Alarm is an anomaly detection alarm on a metric of one process of an app
+type Alarm struct {
+	Name    string    `json:"name"`
+	Metric  string    `json:"metric"`
+	Process string    `json:"process"`
+	State   string    `json:"state"`
+	Reason  string    `json:"reason"`
+	Updated time.Time `json:"updated"`
+}
+
+type Alarms []Alarm
+
+func (as Alarms) Len() int           { return len(as) }
+func (as Alarms) Less(i, j int) bool { return as[i].Name < as[j].Name }
+func (as Alarms) Swap(i, j int)      { as[i], as[j] = as[j], as[i] }
+
+// AlarmSetting is whether alarms on a metric are enabled for an app and the alarms it has created
+type AlarmSetting struct {
+	Metric  string  `json:"metric"`
+	Enabled bool    `json:"enabled"`
+	Alarms  []Alarm `json:"alarms"`
+}
+
+// AlarmMetricNames returns the metrics alarms can be enabled for, sorted
+func AlarmMetricNames() []string {
+	names := []string{}
+
+	for name := range AlarmMetrics {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// AlarmName is the name the app template gives the alarm on a metric of a process
+func AlarmName(stack, process, metric string) string {
+	return fmt.Sprintf("%s-%s-%s", stack, process, metric)
+}
+
+// AlarmTransition returns triggered when an alarm has started alarming and resolved when it
+// has gone back to normal. An alarm seen for the first time has no transition.
+func AlarmTransition(previous string, a Alarm) string {
+	switch {
+	case previous == "":
+		return ""
+	case a.State == cloudwatch.StateValueAlarm && previous != cloudwatch.StateValueAlarm:
+		return "triggered"
+	case a.State == cloudwatch.StateValueOk && previous == cloudwatch.StateValueAlarm:
+		return "resolved"
+	}
+
+	return ""
+}
+
+// HasAlarms returns true if any alarm metric is enabled for the app
+func (a *App) HasAlarms() bool {
+	for _, param := range AlarmMetrics {
+		if a.Parameters[param] == "Yes" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Alarms returns the alarms of the enabled metrics of an app with their current state
+func (a *App) Alarms() ([]Alarm, error) {
+	alarms := []Alarm{}
+
+	if a.Release == "" || !a.HasAlarms() {
+		return alarms, nil
+	}
+
+	r, err := GetRelease(a.Name, a.Release)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := manifest.Load([]byte(r.Manifest))
+	if err != nil {
+		return nil, err
+	}
+
+	expected := map[string]Alarm{}
+	names := []*string{}
+
+	for _, metric := range AlarmMetricNames() {
+		if a.Parameters[AlarmMetrics[metric]] != "Yes" {
+			continue
+		}
+
+		for _, s := range m.Services {
+			if metric != "cpu" && !s.HasBalancer() {
+				continue
+			}
+
+			name := AlarmName(a.StackName(), s.Name, metric)
+			expected[name] = Alarm{Name: name, Metric: metric, Process: s.Name}
+			names = append(names, aws.String(name))
+		}
+	}
+
+	// cloudwatch describes at most 100 alarms by name at a time
+	for i := 0; i < len(names); i += 100 {
+		j := i + 100
+
+		if j > len(names) {
+			j = len(names)
+		}
+
+		res, err := CloudWatch().DescribeAlarms(&cloudwatch.DescribeAlarmsInput{
+			AlarmNames: names[i:j],
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ma := range res.MetricAlarms {
+			alarm, ok := expected[*ma.AlarmName]
+			if !ok {
+				continue
+			}
+
+			alarm.State = *ma.StateValue
+
+			if ma.StateReason != nil {
+				alarm.Reason = *ma.StateReason
+			}
+
+			if ma.StateUpdatedTimestamp != nil {
+				alarm.Updated = *ma.StateUpdatedTimestamp
+			}
+
+			alarms = append(alarms, alarm)
+		}
+	}
+
+	sort.Sort(Alarms(alarms))
+
+	return alarms, nil
+}
+
+// ListAlarms returns the alarm settings of every metric of an app
+func ListAlarms(app string) ([]AlarmSetting, error) {
+	a, err := GetApp(app)
+	if err != nil {
+		return nil, err
+	}
+
+	alarms, err := a.Alarms()
+	if err != nil {
+		return nil, err
+	}
+
+	settings := []AlarmSetting{}
+
+	for _, metric := range AlarmMetricNames() {
+		s := AlarmSetting{
+			Metric:  metric,
+			Enabled: a.Parameters[AlarmMetrics[metric]] == "Yes",
+			Alarms:  []Alarm{},
+		}
+
+		for _, alarm := range alarms {
+			if alarm.Metric == metric {
+				s.Alarms = append(s.Alarms, alarm)
+			}
+		}
+
+		settings = append(settings, s)
+	}
+
+	return settings, nil
+}
+
+// SetAlarm turns the anomaly detection alarms on a metric of an app on or off
+func SetAlarm(app, metric string, enabled bool) error {
+	param, ok := AlarmMetrics[metric]
+	if !ok {
+		return fmt.Errorf("metric must be one of %s", strings.Join(AlarmMetricNames(), ", "))
+	}
+
+	a, err := GetApp(app)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := a.Parameters[param]; !ok {
+		return fmt.Errorf("promote a new release of %s to enable alarms", app)
+	}
+
+	value := "No"
+	action := "alarm:disable"
+
+	if enabled {
+		value = "Yes"
+		action = "alarm:enable"
+	}
+
+	if a.Parameters[param] == value {
+		return nil
+	}
+
+	if err := a.UpdateParams(map[string]string{param: value}); err != nil {
+		return err
+	}
+
+	NotifySuccess(action, map[string]string{"app": app, "metric": metric})
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/convox/rack/api/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlarmName(t *testing.T) {
+	assert.Equal(t, "convox-myapp-web-latency", models.AlarmName("convox-myapp", "web", "latency"))
+}
+
+func TestAlarmMetricNames(t *testing.T) {
+	assert.Equal(t, []string{"5xx", "cpu", "latency"}, models.AlarmMetricNames())
+}
+
+func TestAlarmTransition(t *testing.T) {
+	alarm := models.Alarm{State: "ALARM"}
+	ok := models.Alarm{State: "OK"}
+	missing := models.Alarm{State: "INSUFFICIENT_DATA"}
+
+	assert.Equal(t, "", models.AlarmTransition("", alarm))
+	assert.Equal(t, "triggered", models.AlarmTransition("OK", alarm))
+	assert.Equal(t, "triggered", models.AlarmTransition("INSUFFICIENT_DATA", alarm))
+	assert.Equal(t, "", models.AlarmTransition("ALARM", alarm))
+	assert.Equal(t, "resolved", models.AlarmTransition("ALARM", ok))
+	assert.Equal(t, "", models.AlarmTransition("INSUFFICIENT_DATA", ok))
+	assert.Equal(t, "", models.AlarmTransition("ALARM", missing))
+}
+
+func TestAppHasAlarms(t *testing.T) {
+	a := models.App{Parameters: map[string]string{"AlarmCpu": "No", "AlarmLatency": "No"}}
+	assert.False(t, a.HasAlarms())
+
+	a.Parameters["AlarmLatency"] = "Yes"
+	assert.True(t, a.HasAlarms())
+}
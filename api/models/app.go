@@ -223,9 +223,38 @@ func (a *App) Delete() error {
 	return nil
 }
 
+// CancelUpdate stops an update that is in progress, rolling the app back to its previous release
+func (a *App) CancelUpdate() error {
+	if a.Status != "updating" {
+		return fmt.Errorf("app is not updating: %s", a.Name)
+	}
+
+	_, err := CloudFormation().CancelUpdateStack(&cloudformation.CancelUpdateStackInput{
+		StackName: aws.String(a.StackName()),
+	})
+	if err != nil {
+		return err
+	}
+
+	NotifySuccess("app:cancel", map[string]string{"name": a.Name})
+
+	return nil
+}
+
 // Shortcut for updating current parameters
 // If template changed, more care about new or removed parameters must be taken (see Release.Promote or System.Save)
 func (a *App) UpdateParams(changes map[string]string) error {
+	_, err := UpdateStack(a.paramsUpdate(changes))
+
+	return err
+}
+
+// PreviewParams returns the changes to the stack of an app that UpdateParams would make
+func (a *App) PreviewParams(changes map[string]string) (StackChanges, error) {
+	return PreviewStackUpdate(a.paramsUpdate(changes))
+}
+
+func (a *App) paramsUpdate(changes map[string]string) *cloudformation.UpdateStackInput {
 	req := &cloudformation.UpdateStackInput{
 		StackName:           aws.String(a.StackName()),
 		Capabilities:        []*string{aws.String("CAPABILITY_IAM")},
@@ -253,9 +282,57 @@ func (a *App) UpdateParams(changes map[string]string) error {
 		}
 	}
 
-	_, err := UpdateStack(req)
+	return req
+}
 
-	return err
+// ParameterSchema describes a stack parameter as it is declared in the stack template
+type ParameterSchema struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Default     string   `json:"default,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Allowed     []string `json:"allowed,omitempty"`
+	Secret      bool     `json:"secret,omitempty"`
+}
+
+type ParameterSchemas []ParameterSchema
+
+func (ps ParameterSchemas) Len() int           { return len(ps) }
+func (ps ParameterSchemas) Less(i, j int) bool { return ps[i].Name < ps[j].Name }
+func (ps ParameterSchemas) Swap(i, j int)      { ps[i], ps[j] = ps[j], ps[i] }
+
+// ParameterSchema returns the parameters declared by the template the stack is currently running
+func (a *App) ParameterSchema() (ParameterSchemas, error) {
+	res, err := CloudFormation().GetTemplateSummary(&cloudformation.GetTemplateSummaryInput{
+		StackName: aws.String(a.StackName()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	schema := ParameterSchemas{}
+
+	for _, p := range res.Parameters {
+		ps := ParameterSchema{
+			Name:        cs(p.ParameterKey, ""),
+			Type:        cs(p.ParameterType, "String"),
+			Default:     cs(p.DefaultValue, ""),
+			Description: cs(p.Description, ""),
+			Secret:      p.NoEcho != nil && *p.NoEcho,
+		}
+
+		if p.ParameterConstraints != nil {
+			for _, v := range p.ParameterConstraints.AllowedValues {
+				ps.Allowed = append(ps.Allowed, *v)
+			}
+		}
+
+		schema = append(schema, ps)
+	}
+
+	sort.Sort(schema)
+
+	return schema, nil
 }
 
 func (a *App) Formation(m manifest.Manifest) (string, error) {
@@ -450,6 +527,10 @@ func (a *App) RunAttached(process, command, releaseID string, height, width int,
 		releaseEnv := structs.Environment{}
 		releaseEnv.LoadRaw(release.Env)
 
+		if err := resolveSecrets(a.Name, releaseEnv); err != nil {
+			return err
+		}
+
 		for key, value := range releaseEnv {
 			containerEnvs[key] = value
 		}
@@ -678,12 +759,19 @@ func (a *App) RunDetached(process, command, releaseID string) error {
 				}
 			}
 
+			// secret references are resolved by the entrypoint of the image with the task role
+			if len(SecretReferences(Environment(env))) > 0 {
+				cd.Environment = secretsBucketEnvironment(cd.Environment, a.settingsBucket())
+			}
+
 			taskInput := &ecs.RegisterTaskDefinitionInput{
 				ContainerDefinitions: []*ecs.ContainerDefinition{
 					cd,
 				},
-				Family:  task.TaskDefinition.Family,
-				Volumes: []*ecs.Volume{},
+				Family:      task.TaskDefinition.Family,
+				NetworkMode: task.TaskDefinition.NetworkMode,
+				TaskRoleArn: task.TaskDefinition.TaskRoleArn,
+				Volumes:     []*ecs.Volume{},
 			}
 
 			resp, err := ECS().RegisterTaskDefinition(taskInput)
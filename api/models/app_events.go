@@ -0,0 +1,102 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+// stackEventsPollInterval is how often the events of an updating stack are fetched
+var stackEventsPollInterval = 2 * time.Second
+
+// StreamEvents writes the cloudformation events of the current or most recent update of
+// the app stack, oldest first, until the stack has stopped updating
+func (a *App) StreamEvents(w io.Writer) error {
+	stack := a.StackName()
+	seen := map[string]bool{}
+
+	for {
+		// check the status before fetching so the events of a finished update are all written
+		app, err := GetApp(a.Name)
+		if err != nil {
+			return err
+		}
+
+		events, err := currentUpdateEvents(stack)
+		if err != nil {
+			return err
+		}
+
+		for i := len(events) - 1; i >= 0; i-- {
+			e := events[i]
+
+			if seen[*e.EventId] {
+				continue
+			}
+
+			seen[*e.EventId] = true
+
+			if _, err := fmt.Fprintln(w, stackEventLine(e)); err != nil {
+				return err
+			}
+		}
+
+		switch app.Status {
+		case "updating", "rollback":
+		default:
+			return nil
+		}
+
+		time.Sleep(stackEventsPollInterval)
+	}
+}
+
+// currentUpdateEvents returns the events of a stack back to the start of its latest update, newest first
+func currentUpdateEvents(stack string) ([]*cloudformation.StackEvent, error) {
+	events := []*cloudformation.StackEvent{}
+
+	err := CloudFormation().DescribeStackEventsPages(&cloudformation.DescribeStackEventsInput{
+		StackName: aws.String(stack),
+	}, func(res *cloudformation.DescribeStackEventsOutput, last bool) bool {
+		events = append(events, res.StackEvents...)
+		return updateStart(events, stack) < 0
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if i := updateStart(events, stack); i >= 0 {
+		return events[0 : i+1], nil
+	}
+
+	return events, nil
+}
+
+// updateStart returns the index of the event that began the latest update of a stack or -1
+func updateStart(events []*cloudformation.StackEvent, stack string) int {
+	for i, e := range events {
+		if *e.LogicalResourceId != stack {
+			continue
+		}
+
+		switch *e.ResourceStatus {
+		case "CREATE_IN_PROGRESS", "UPDATE_IN_PROGRESS":
+			return i
+		}
+	}
+
+	return -1
+}
+
+func stackEventLine(e *cloudformation.StackEvent) string {
+	line := fmt.Sprintf("%s %s %s", e.Timestamp.UTC().Format("2006-01-02 15:04:05"), *e.LogicalResourceId, *e.ResourceStatus)
+
+	if e.ResourceStatusReason != nil && *e.ResourceStatusReason != "" {
+		line += fmt.Sprintf(" (%s)", *e.ResourceStatusReason)
+	}
+
+	return line
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateStart(t *testing.T) {
+	at := time.Date(2016, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	events := []*cloudformation.StackEvent{
+		stackEvent("convox-myapp", "UPDATE_COMPLETE", at.Add(3*time.Minute)),
+		stackEvent("ServiceWeb", "UPDATE_COMPLETE", at.Add(2*time.Minute)),
+		stackEvent("ServiceWeb", "UPDATE_IN_PROGRESS", at.Add(1*time.Minute)),
+		stackEvent("convox-myapp", "UPDATE_IN_PROGRESS", at),
+		stackEvent("convox-myapp", "UPDATE_COMPLETE", at.Add(-time.Hour)),
+		stackEvent("convox-myapp", "UPDATE_IN_PROGRESS", at.Add(-2*time.Hour)),
+	}
+
+	assert.Equal(t, 3, updateStart(events, "convox-myapp"))
+	assert.Equal(t, -1, updateStart(events[0:3], "convox-myapp"))
+}
+
+func TestStackEventLine(t *testing.T) {
+	at := time.Date(2016, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	e := stackEvent("ServiceWeb", "UPDATE_FAILED", at)
+	assert.Equal(t, "2016-10-01 12:00:00 ServiceWeb UPDATE_FAILED", stackEventLine(e))
+
+	e.ResourceStatusReason = aws.String("ECS Service did not stabilize")
+	assert.Equal(t, "2016-10-01 12:00:00 ServiceWeb UPDATE_FAILED (ECS Service did not stabilize)", stackEventLine(e))
+}
+
+func stackEvent(resource, status string, at time.Time) *cloudformation.StackEvent {
+	return &cloudformation.StackEvent{
+		EventId:           aws.String(resource + status + at.String()),
+		LogicalResourceId: aws.String(resource),
+		ResourceStatus:    aws.String(status),
+		Timestamp:         aws.Time(at),
+	}
+}
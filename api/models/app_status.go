@@ -0,0 +1,156 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AppStates are the states an app status page can show, from best to worst
+var AppStates = []string{"operational", "degraded", "outage"}
+
+// statusDays is how many days of health checks a status page keeps
+const statusDays = 90
+
+const appStatusKey = "status.json"
+
+// AppStatus is the public status page of an app. The state and message are set
+// by hand during incidents and uptime is recorded by the rack's health checks.
+type AppStatus struct {
+	App     string     `json:"app"`
+	Public  bool       `json:"public"`
+	State   string     `json:"state"`
+	Message string     `json:"message"`
+	Updated time.Time  `json:"updated"`
+	Days    StatusDays `json:"days"`
+}
+
+// StatusDay counts the health checks of an app on a single day
+type StatusDay struct {
+	Date   string `json:"date"`
+	Up     int    `json:"up"`
+	Checks int    `json:"checks"`
+}
+
+// StatusDays are sorted with the oldest day first
+type StatusDays []StatusDay
+
+// GetAppStatus returns the status page of an app
+func GetAppStatus(app string) (*AppStatus, error) {
+	a, err := GetApp(app)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &AppStatus{App: a.Name, State: "operational", Days: StatusDays{}}
+
+	data, err := s3Get(a.settingsBucket(), appStatusKey)
+	if awserrCode(err) == "NoSuchKey" {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Save stores the status page in the settings bucket of its app
+func (s *AppStatus) Save() error {
+	a, err := GetApp(s.App)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	return S3Put(a.settingsBucket(), appStatusKey, data, false)
+}
+
+// SetState marks the app as operational, degraded or suffering an outage with an explanation
+func (s *AppStatus) SetState(state, message string) error {
+	if !containsString(AppStates, state) {
+		return fmt.Errorf("state must be one of: %v", AppStates)
+	}
+
+	s.State = state
+	s.Message = message
+	s.Updated = time.Now().UTC()
+
+	return nil
+}
+
+// Record adds the result of a health check at the given time, keeping the last statusDays days
+func (s *AppStatus) Record(up bool, at time.Time) {
+	date := at.UTC().Format("2006-01-02")
+
+	if len(s.Days) == 0 || s.Days[len(s.Days)-1].Date != date {
+		s.Days = append(s.Days, StatusDay{Date: date})
+	}
+
+	day := &s.Days[len(s.Days)-1]
+
+	day.Checks++
+
+	if up {
+		day.Up++
+	}
+
+	if len(s.Days) > statusDays {
+		s.Days = s.Days[len(s.Days)-statusDays:]
+	}
+}
+
+// Uptime returns the percentage of health checks that passed, or 100 if there have been none
+func (ds StatusDays) Uptime() float64 {
+	up, checks := 0, 0
+
+	for _, d := range ds {
+		up += d.Up
+		checks += d.Checks
+	}
+
+	if checks == 0 {
+		return 100
+	}
+
+	return float64(up) * 100 / float64(checks)
+}
+
+// Healthy returns true if the app is running and every process with a desired count has started
+func (a *App) Healthy() (bool, error) {
+	if a.Status != "running" && a.Status != "updating" {
+		return false, nil
+	}
+
+	formation, err := Provider().FormationList(a.Name)
+	if err != nil {
+		return false, err
+	}
+
+	ps, err := ListProcesses(a.Name)
+	if err != nil {
+		return false, err
+	}
+
+	running := map[string]int{}
+
+	for _, p := range ps {
+		running[p.Name]++
+	}
+
+	for _, f := range formation {
+		if f.Count > 0 && running[f.Name] == 0 {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
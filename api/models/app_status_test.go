@@ -0,0 +1,41 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppStatusRecord(t *testing.T) {
+	s := &AppStatus{App: "web", State: "operational"}
+
+	start := time.Date(2016, 10, 1, 23, 50, 0, 0, time.UTC)
+
+	s.Record(true, start)
+	s.Record(false, start.Add(5*time.Minute))
+	s.Record(true, start.Add(15*time.Minute))
+
+	assert.Equal(t, StatusDays{
+		{Date: "2016-10-01", Up: 1, Checks: 2},
+		{Date: "2016-10-02", Up: 1, Checks: 1},
+	}, s.Days)
+
+	assert.InDelta(t, 66.67, s.Days.Uptime(), 0.01)
+
+	for i := 0; i < statusDays+5; i++ {
+		s.Record(true, start.Add(time.Duration(i+1)*24*time.Hour))
+	}
+
+	assert.Len(t, s.Days, statusDays)
+}
+
+func TestAppStatusSetState(t *testing.T) {
+	s := &AppStatus{App: "web", State: "operational"}
+
+	assert.Nil(t, s.SetState("degraded", "elevated error rates"))
+	assert.Equal(t, "degraded", s.State)
+	assert.Equal(t, "elevated error rates", s.Message)
+
+	assert.EqualError(t, s.SetState("broken", ""), "state must be one of: [operational degraded outage]")
+}
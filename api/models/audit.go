@@ -0,0 +1,297 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// auditTimeFormat sorts lexically in the same order as the times it formats
+const auditTimeFormat = "2006-01-02T15:04:05.000000000Z"
+
+// auditSummaryLength is the longest payload summary kept for an audit event
+const auditSummaryLength = 200
+
+// auditSafe are the form keys whose values are written to the audit log. Every other value is
+// hidden since urls, build args, commands and parameters can all carry credentials.
+var auditSafe = map[string]bool{
+	"app":           true,
+	"autoscale":     true,
+	"cache":         true,
+	"cluster":       true,
+	"concurrency":   true,
+	"count":         true,
+	"cpu":           true,
+	"description":   true,
+	"domain":        true,
+	"domains":       true,
+	"duration":      true,
+	"force":         true,
+	"git-sha":       true,
+	"grow":          true,
+	"id":            true,
+	"immutable":     true,
+	"letsencrypt":   true,
+	"manifest":      true,
+	"memory":        true,
+	"metric":        true,
+	"name":          true,
+	"percent":       true,
+	"priority":      true,
+	"private":       true,
+	"process":       true,
+	"processes":     true,
+	"public":        true,
+	"reason":        true,
+	"release":       true,
+	"schedule":      true,
+	"server":        true,
+	"serveraddress": true,
+	"service":       true,
+	"size":          true,
+	"state":         true,
+	"status":        true,
+	"strict":        true,
+	"threshold":     true,
+	"timeout":       true,
+	"type":          true,
+	"upload":        true,
+	"user":          true,
+	"version":       true,
+	"wait":          true,
+}
+
+// AuditEvent records a single mutating call made against the rack api
+type AuditEvent struct {
+	Id      string    `json:"id"`
+	Action  string    `json:"action"`
+	App     string    `json:"app"`
+	Method  string    `json:"method"`
+	Path    string    `json:"path"`
+	Status  int       `json:"status"`
+	Summary string    `json:"summary"`
+	User    string    `json:"user"`
+	Created time.Time `json:"created"`
+
+	// Authenticated is set when User owns the key that made the call, otherwise User is
+	// only asserted by the client
+	Authenticated bool `json:"authenticated"`
+}
+
+type AuditEvents []AuditEvent
+
+func (es AuditEvents) Len() int           { return len(es) }
+func (es AuditEvents) Less(i, j int) bool { return es[i].Created.After(es[j].Created) }
+func (es AuditEvents) Swap(i, j int)      { es[i], es[j] = es[j], es[i] }
+
+// AuditFilter narrows the events returned by ListAudit
+type AuditFilter struct {
+	Action string
+	App    string
+	User   string
+	Since  time.Time
+	Limit  int
+}
+
+// Matches returns true if the event passes every filter that is set
+func (f AuditFilter) Matches(e AuditEvent) bool {
+	if f.Action != "" && e.Action != f.Action {
+		return false
+	}
+
+	if f.App != "" && e.App != f.App {
+		return false
+	}
+
+	if f.User != "" && e.User != f.User {
+		return false
+	}
+
+	return true
+}
+
+// NewAuditEvent returns an event for an api call that has not been saved yet
+func NewAuditEvent(action, method, path string) *AuditEvent {
+	return &AuditEvent{
+		Id:      generateId("A", 10),
+		Action:  action,
+		Method:  method,
+		Path:    path,
+		Created: time.Now().UTC(),
+	}
+}
+
+// Save stores the event in the audit table. Racks installed before the table
+// existed have nowhere to store events and skip recording them.
+func (e *AuditEvent) Save() error {
+	table := os.Getenv("DYNAMO_AUDIT")
+
+	if table == "" {
+		return nil
+	}
+
+	item := map[string]*dynamodb.AttributeValue{
+		"day":     &dynamodb.AttributeValue{S: aws.String(auditDay(e.Created))},
+		"created": &dynamodb.AttributeValue{S: aws.String(e.Created.Format(auditTimeFormat))},
+		"id":      &dynamodb.AttributeValue{S: aws.String(e.Id)},
+		"action":  &dynamodb.AttributeValue{S: aws.String(e.Action)},
+		"method":  &dynamodb.AttributeValue{S: aws.String(e.Method)},
+		"path":    &dynamodb.AttributeValue{S: aws.String(e.Path)},
+		"status":  &dynamodb.AttributeValue{N: aws.String(strconv.Itoa(e.Status))},
+		"user":    &dynamodb.AttributeValue{S: aws.String(e.User)},
+
+		"authenticated": &dynamodb.AttributeValue{BOOL: aws.Bool(e.Authenticated)},
+	}
+
+	// dynamodb does not accept empty strings
+	if e.App != "" {
+		item["app"] = &dynamodb.AttributeValue{S: aws.String(e.App)}
+	}
+
+	if e.Summary != "" {
+		item["summary"] = &dynamodb.AttributeValue{S: aws.String(e.Summary)}
+	}
+
+	_, err := DynamoDB().PutItem(&dynamodb.PutItemInput{
+		Item:      item,
+		TableName: aws.String(table),
+	})
+
+	return err
+}
+
+// ListAudit returns the events matching filter, newest first
+func ListAudit(filter AuditFilter) (AuditEvents, error) {
+	table := os.Getenv("DYNAMO_AUDIT")
+
+	if table == "" {
+		return nil, fmt.Errorf("audit log is not available, update your rack with `convox rack update`")
+	}
+
+	events := AuditEvents{}
+
+	now := time.Now().UTC()
+	since := filter.Since.UTC()
+
+	if since.IsZero() {
+		since = now.Add(-24 * time.Hour)
+	}
+
+	// events are partitioned by day so walk backwards one day at a time
+	for day := now; !day.Before(since.Truncate(24 * time.Hour)); day = day.Add(-24 * time.Hour) {
+		req := &dynamodb.QueryInput{
+			KeyConditionExpression: aws.String("#day = :day AND #created >= :since"),
+			ExpressionAttributeNames: map[string]*string{
+				"#day":     aws.String("day"),
+				"#created": aws.String("created"),
+			},
+			ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+				":day":   &dynamodb.AttributeValue{S: aws.String(auditDay(day))},
+				":since": &dynamodb.AttributeValue{S: aws.String(since.Format(auditTimeFormat))},
+			},
+			ScanIndexForward: aws.Bool(false),
+			TableName:        aws.String(table),
+		}
+
+		err := DynamoDB().QueryPages(req, func(res *dynamodb.QueryOutput, last bool) bool {
+			for _, item := range res.Items {
+				e := auditEventFromItem(item)
+
+				if filter.Matches(e) {
+					events = append(events, e)
+				}
+			}
+
+			return filter.Limit == 0 || len(events) < filter.Limit
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if filter.Limit > 0 && len(events) >= filter.Limit {
+			break
+		}
+	}
+
+	sort.Sort(events)
+
+	if filter.Limit > 0 && len(events) > filter.Limit {
+		events = events[0:filter.Limit]
+	}
+
+	return events, nil
+}
+
+// AuditSummary describes the parameters of a request, hiding the values of parameters that are
+// not known to be safe. Bodies that are not forms, like an environment, are only measured.
+func AuditSummary(form url.Values, length int64) string {
+	keys := []string{}
+
+	for key := range form {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	params := []string{}
+
+	for _, key := range keys {
+		value := "***"
+
+		if auditSafe[key] {
+			value = strings.Join(form[key], ",")
+		}
+
+		params = append(params, fmt.Sprintf("%s=%s", key, value))
+	}
+
+	summary := strings.Join(params, " ")
+
+	if summary == "" && length > 0 {
+		summary = fmt.Sprintf("%d bytes", length)
+	}
+
+	if len(summary) > auditSummaryLength {
+		summary = summary[0:auditSummaryLength-3] + "..."
+	}
+
+	return summary
+}
+
+func auditDay(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+func auditEventFromItem(item map[string]*dynamodb.AttributeValue) AuditEvent {
+	created, _ := time.Parse(auditTimeFormat, coalesce(item["created"], ""))
+	status, _ := strconv.Atoi(coalesceN(item["status"]))
+
+	return AuditEvent{
+		Id:      coalesce(item["id"], ""),
+		Action:  coalesce(item["action"], ""),
+		App:     coalesce(item["app"], ""),
+		Method:  coalesce(item["method"], ""),
+		Path:    coalesce(item["path"], ""),
+		Status:  status,
+		Summary: coalesce(item["summary"], ""),
+		User:    coalesce(item["user"], ""),
+		Created: created,
+
+		Authenticated: item["authenticated"] != nil && item["authenticated"].BOOL != nil && *item["authenticated"].BOOL,
+	}
+}
+
+func coalesceN(av *dynamodb.AttributeValue) string {
+	if av == nil || av.N == nil {
+		return "0"
+	}
+
+	return *av.N
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/convox/rack/api/structs"
+)
+
+// SourceWithBlobs returns a gzipped source tarball with the files of blobs added from the
+// rack index. Clients upload large files to the index once and leave them out of their
+// source tarballs, so unchanged files are never sent again even when building other apps.
+func SourceWithBlobs(source io.Reader, blobs structs.Index) (io.Reader, error) {
+	for _, item := range blobs {
+		if !validBlobName(item.Name) {
+			return nil, fmt.Errorf("invalid blob name: %s", item.Name)
+		}
+	}
+
+	dir, err := ioutil.TempDir("", "blobs")
+	if err != nil {
+		return nil, err
+	}
+
+	defer os.RemoveAll(dir)
+
+	if err := Provider().IndexDownload(&blobs, dir); err != nil {
+		return nil, err
+	}
+
+	gr, err := gzip.NewReader(source)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+	tr := tar.NewReader(gr)
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return nil, err
+		}
+
+		if _, err := io.Copy(tw, tr); err != nil {
+			return nil, err
+		}
+	}
+
+	hashes := []string{}
+
+	for hash := range blobs {
+		hashes = append(hashes, hash)
+	}
+
+	sort.Strings(hashes)
+
+	for _, hash := range hashes {
+		item := blobs[hash]
+
+		data, err := ioutil.ReadFile(filepath.Join(dir, item.Name))
+		if err != nil {
+			return nil, err
+		}
+
+		header := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     item.Name,
+			Mode:     int64(item.Mode.Perm()),
+			ModTime:  item.ModTime,
+			Size:     int64(len(data)),
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return nil, err
+		}
+
+		if _, err := tw.Write(data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+// validBlobName allows relative paths that stay inside the source directory
+func validBlobName(name string) bool {
+	if name == "" || strings.HasPrefix(name, "/") {
+		return false
+	}
+
+	clean := path.Clean(name)
+
+	return clean != ".." && !strings.HasPrefix(clean, "../")
+}
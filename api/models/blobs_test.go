@@ -0,0 +1,74 @@
+package models
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/convox/rack/api/structs"
+	"github.com/convox/rack/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceWithBlobs(t *testing.T) {
+	os.Setenv("PROVIDER", "test")
+	TestProvider = &provider.TestProvider{}
+
+	blobs := structs.Index{
+		"1111": structs.IndexItem{Name: "vendor/model.bin", Mode: 0644},
+	}
+
+	TestProvider.On("IndexDownload", &blobs, mock.Anything).Run(func(args mock.Arguments) {
+		dir := args.Get(1).(string)
+		os.MkdirAll(filepath.Join(dir, "vendor"), 0755)
+		ioutil.WriteFile(filepath.Join(dir, "vendor", "model.bin"), []byte("weights"), 0644)
+	}).Return(nil)
+
+	src := &bytes.Buffer{}
+	gz := gzip.NewWriter(src)
+	tw := tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: "Dockerfile", Mode: 0644, Size: 11})
+	tw.Write([]byte("FROM ruby\n\n"))
+	tw.Close()
+	gz.Close()
+
+	out, err := SourceWithBlobs(src, blobs)
+	require.NoError(t, err)
+
+	gr, err := gzip.NewReader(out)
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	tr := tar.NewReader(gr)
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		data, err := ioutil.ReadAll(tr)
+		require.NoError(t, err)
+
+		files[header.Name] = string(data)
+	}
+
+	assert.Equal(t, map[string]string{"Dockerfile": "FROM ruby\n\n", "vendor/model.bin": "weights"}, files)
+
+	TestProvider.AssertExpectations(t)
+}
+
+func TestSourceWithBlobsInvalidName(t *testing.T) {
+	for _, name := range []string{"../etc/passwd", "/etc/passwd", "vendor/../../x", ""} {
+		_, err := SourceWithBlobs(&bytes.Buffer{}, structs.Index{"1111": structs.IndexItem{Name: name}})
+		assert.EqualError(t, err, "invalid blob name: "+name)
+	}
+}
@@ -0,0 +1,293 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MaxBreakGlassDuration is the longest a break glass grant can last
+const MaxBreakGlassDuration = 8 * time.Hour
+
+// BreakGlass is a request by a user for temporary exec, run and environment access to an
+// immutable app. It has to be approved by another user and lapses on its own once it expires.
+// Requests and approvals are made by users authenticated with their own key, see UserKey.
+type BreakGlass struct {
+	Id       string        `json:"id"`
+	App      string        `json:"app"`
+	User     string        `json:"user"`
+	Reason   string        `json:"reason"`
+	Duration time.Duration `json:"duration"`
+
+	// Status is one of pending, approved, denied, revoked or expired
+	Status string `json:"status"`
+
+	// Reviewer approved or denied the request and Revoker ended it early
+	Reviewer string `json:"reviewer,omitempty"`
+	Revoker  string `json:"revoker,omitempty"`
+
+	Created time.Time `json:"created"`
+	Expires time.Time `json:"expires"`
+}
+
+// BreakGlasses are sorted with the latest request first
+type BreakGlasses []BreakGlass
+
+func (bs BreakGlasses) Len() int           { return len(bs) }
+func (bs BreakGlasses) Less(i, j int) bool { return bs[i].Created.After(bs[j].Created) }
+func (bs BreakGlasses) Swap(i, j int)      { bs[i], bs[j] = bs[j], bs[i] }
+
+// RequestBreakGlass asks for temporary access to an immutable app on behalf of the authenticated user
+func RequestBreakGlass(app, user, reason string, duration time.Duration) (*BreakGlass, error) {
+	if user == "" {
+		return nil, fmt.Errorf("break glass request must be made with a user key")
+	}
+
+	if strings.TrimSpace(reason) == "" {
+		return nil, fmt.Errorf("must specify a reason")
+	}
+
+	if duration < time.Minute || duration > MaxBreakGlassDuration {
+		return nil, fmt.Errorf("duration must be between 1m and %s", MaxBreakGlassDuration)
+	}
+
+	immutable, err := Immutable(app)
+	if err != nil {
+		return nil, err
+	}
+
+	if !immutable {
+		return nil, fmt.Errorf("app is not immutable: %s", app)
+	}
+
+	b := &BreakGlass{
+		Id:       generateId("G", 10),
+		App:      app,
+		User:     user,
+		Reason:   reason,
+		Duration: duration,
+		Status:   "pending",
+		Created:  time.Now().UTC(),
+	}
+
+	if err := b.save(); err != nil {
+		return nil, err
+	}
+
+	b.notify("breakglass:request")
+
+	return b, nil
+}
+
+// ListBreakGlass returns the break glass requests of an app, latest first
+func ListBreakGlass(app string) (BreakGlasses, error) {
+	a, err := GetApp(app)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := s3Keys(a.settingsBucket(), "breakglass/")
+	if err != nil {
+		return nil, err
+	}
+
+	grants := BreakGlasses{}
+
+	for _, key := range keys {
+		b, err := getBreakGlass(a.settingsBucket(), key)
+		if err != nil {
+			return nil, err
+		}
+
+		grants = append(grants, *b)
+	}
+
+	sort.Sort(grants)
+
+	return grants, nil
+}
+
+// GetBreakGlass returns a single break glass request of an app
+func GetBreakGlass(app, id string) (*BreakGlass, error) {
+	a, err := GetApp(app)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := getBreakGlass(a.settingsBucket(), breakGlassKey(id))
+	if awserrCode(err) == "NoSuchKey" {
+		return nil, fmt.Errorf("no such break glass request: %s", id)
+	}
+
+	return b, err
+}
+
+// ActiveBreakGlass returns the approved and unexpired break glass grant of an authenticated user on
+// an app, or nil if the user has none
+func ActiveBreakGlass(app, user string) (*BreakGlass, error) {
+	if user == "" {
+		return nil, nil
+	}
+
+	grants, err := ListBreakGlass(app)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range grants {
+		if b.User == user && b.Active() {
+			return &b, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// ExpireBreakGlass marks the grants of an app that have run out as expired
+func ExpireBreakGlass(app string) error {
+	grants, err := ListBreakGlass(app)
+	if err != nil {
+		return err
+	}
+
+	for i := range grants {
+		b := &grants[i]
+
+		if b.Status != "approved" || b.Active() {
+			continue
+		}
+
+		b.Status = "expired"
+
+		if err := b.save(); err != nil {
+			return err
+		}
+
+		b.notify("breakglass:expire")
+	}
+
+	return nil
+}
+
+// Active returns true while an approved grant has not expired
+func (b *BreakGlass) Active() bool {
+	return b.Status == "approved" && time.Now().Before(b.Expires)
+}
+
+// Approve starts a pending grant on behalf of the authenticated user. Users can not approve their
+// own requests.
+func (b *BreakGlass) Approve(user string) error {
+	if b.Status != "pending" {
+		return fmt.Errorf("break glass request is %s: %s", b.status(), b.Id)
+	}
+
+	if user == "" {
+		return fmt.Errorf("break glass request must be approved with a user key")
+	}
+
+	if user == b.User {
+		return fmt.Errorf("break glass request must be approved by another user")
+	}
+
+	b.Status = "approved"
+	b.Reviewer = user
+	b.Expires = time.Now().UTC().Add(b.Duration)
+
+	if err := b.save(); err != nil {
+		return err
+	}
+
+	b.notify("breakglass:approve")
+
+	return nil
+}
+
+// Deny refuses a pending request
+func (b *BreakGlass) Deny(user string) error {
+	if b.Status != "pending" {
+		return fmt.Errorf("break glass request is %s: %s", b.status(), b.Id)
+	}
+
+	b.Status = "denied"
+	b.Reviewer = user
+
+	if err := b.save(); err != nil {
+		return err
+	}
+
+	b.notify("breakglass:deny")
+
+	return nil
+}
+
+// Revoke ends a pending or active grant early
+func (b *BreakGlass) Revoke(user string) error {
+	if b.Status != "pending" && !b.Active() {
+		return fmt.Errorf("break glass request is %s: %s", b.status(), b.Id)
+	}
+
+	b.Status = "revoked"
+	b.Revoker = user
+
+	if err := b.save(); err != nil {
+		return err
+	}
+
+	b.notify("breakglass:revoke")
+
+	return nil
+}
+
+// status is the status of the grant including expiry the worker has not recorded yet
+func (b *BreakGlass) status() string {
+	if b.Status == "approved" && !b.Active() {
+		return "expired"
+	}
+
+	return b.Status
+}
+
+func (b *BreakGlass) notify(action string) {
+	NotifySuccess(action, map[string]string{
+		"app":    b.App,
+		"id":     b.Id,
+		"user":   b.User,
+		"reason": b.Reason,
+		"status": b.Status,
+	})
+}
+
+func (b *BreakGlass) save() error {
+	a, err := GetApp(b.App)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+
+	return S3Put(a.settingsBucket(), breakGlassKey(b.Id), data, false)
+}
+
+func getBreakGlass(bucket, key string) (*BreakGlass, error) {
+	data, err := s3Get(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var b BreakGlass
+
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, err
+	}
+
+	return &b, nil
+}
+
+func breakGlassKey(id string) string {
+	return fmt.Sprintf("breakglass/%s.json", id)
+}
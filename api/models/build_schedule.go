@@ -0,0 +1,280 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// gitRemote matches the urls a build can clone, i.e. https://github.com/convox/rack.git#master or git@github.com:convox/rack.git
+var gitRemote = regexp.MustCompile(`^(https?://|ssh://|git://|[\w.-]+@[\w.-]+:).+`)
+
+// BuildSchedule rebuilds an app from a git url on a cron schedule and promotes the release,
+// i.e. to pick up security patches of base images every night
+type BuildSchedule struct {
+	Id  string `json:"id"`
+	App string `json:"app"`
+
+	// Schedule is a five field crontab expression evaluated in UTC
+	Schedule string `json:"schedule"`
+
+	// URL is the git repository to build with an optional #branch, tag or commit
+	URL      string `json:"url"`
+	Manifest string `json:"manifest"`
+
+	Created    time.Time `json:"created"`
+	LastRun    time.Time `json:"last-run"`
+	LastBuild  string    `json:"last-build"`
+	LastStatus string    `json:"last-status"`
+}
+
+type BuildSchedules []BuildSchedule
+
+func (ss BuildSchedules) Len() int           { return len(ss) }
+func (ss BuildSchedules) Less(i, j int) bool { return ss[i].Created.Before(ss[j].Created) }
+func (ss BuildSchedules) Swap(i, j int)      { ss[i], ss[j] = ss[j], ss[i] }
+
+// NewBuildSchedule returns a schedule for app that has not run yet
+func NewBuildSchedule(app, schedule, url, manifest string) *BuildSchedule {
+	if manifest == "" {
+		manifest = "docker-compose.yml"
+	}
+
+	return &BuildSchedule{
+		Id:       generateId("S", 10),
+		App:      app,
+		Schedule: schedule,
+		URL:      url,
+		Manifest: manifest,
+		Created:  time.Now().UTC(),
+	}
+}
+
+// ListBuildSchedules returns the build schedules of an app, oldest first
+func ListBuildSchedules(app string) (BuildSchedules, error) {
+	a, err := GetApp(app)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := s3Keys(a.settingsBucket(), "build-schedules/")
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := BuildSchedules{}
+
+	for _, key := range keys {
+		s, err := getBuildSchedule(a.settingsBucket(), key)
+		if err != nil {
+			return nil, err
+		}
+
+		schedules = append(schedules, *s)
+	}
+
+	sort.Sort(schedules)
+
+	return schedules, nil
+}
+
+// GetBuildSchedule returns a single build schedule of an app
+func GetBuildSchedule(app, id string) (*BuildSchedule, error) {
+	a, err := GetApp(app)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := getBuildSchedule(a.settingsBucket(), buildScheduleKey(id))
+	if awserrCode(err) == "NoSuchKey" {
+		return nil, fmt.Errorf("no such build schedule: %s", id)
+	}
+
+	return s, err
+}
+
+func getBuildSchedule(bucket, key string) (*BuildSchedule, error) {
+	data, err := s3Get(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var s BuildSchedule
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// Validate returns an error if the schedule can not be run
+func (s *BuildSchedule) Validate() error {
+	if _, err := parseCronExpression(s.Schedule); err != nil {
+		return err
+	}
+
+	if !gitRemote.MatchString(s.URL) {
+		return fmt.Errorf("url must be a git repository the rack can clone")
+	}
+
+	return nil
+}
+
+// Save stores the schedule in the settings bucket of its app
+func (s *BuildSchedule) Save() error {
+	a, err := GetApp(s.App)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	return S3Put(a.settingsBucket(), buildScheduleKey(s.Id), data, false)
+}
+
+// Delete removes the schedule
+func (s *BuildSchedule) Delete() error {
+	a, err := GetApp(s.App)
+	if err != nil {
+		return err
+	}
+
+	return s3Delete(a.settingsBucket(), buildScheduleKey(s.Id))
+}
+
+// Due returns true if the schedule matches the minute of now and has not run during it
+func (s *BuildSchedule) Due(now time.Time) bool {
+	ce, err := parseCronExpression(s.Schedule)
+	if err != nil {
+		return false
+	}
+
+	minute := now.UTC().Truncate(time.Minute)
+
+	return ce.matches(minute) && s.LastRun.Before(minute)
+}
+
+func buildScheduleKey(id string) string {
+	return fmt.Sprintf("build-schedules/%s.json", id)
+}
+
+// cronExpression is a parsed crontab schedule of minute, hour, day of month, month and day of week
+type cronExpression struct {
+	fields [5]map[int]bool
+
+	// standard cron matches either day field when both are restricted
+	anyDom bool
+	anyDow bool
+}
+
+var cronFieldRanges = [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+
+func parseCronExpression(expr string) (*cronExpression, error) {
+	parts := strings.Fields(expr)
+
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("schedule must have five fields: minute hour day-of-month month day-of-week")
+	}
+
+	ce := &cronExpression{
+		anyDom: parts[2] == "*",
+		anyDow: parts[4] == "*",
+	}
+
+	for i, part := range parts {
+		values, err := parseCronField(part, cronFieldRanges[i][0], cronFieldRanges[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %s", expr, err)
+		}
+
+		ce.fields[i] = values
+	}
+
+	// sunday can be written as 0 or 7
+	if ce.fields[4][7] {
+		ce.fields[4][0] = true
+	}
+
+	return ce, nil
+}
+
+// parseCronField expands a comma separated list of values, ranges and steps like 1,5-10,*/15
+func parseCronField(field string, min, max int) (map[int]bool, error) {
+	values := map[int]bool{}
+
+	for _, item := range strings.Split(field, ",") {
+		step := 1
+
+		if i := strings.Index(item, "/"); i >= 0 {
+			s, err := strconv.Atoi(item[i+1:])
+			if err != nil || s < 1 {
+				return nil, fmt.Errorf("invalid step: %s", item)
+			}
+
+			step = s
+			item = item[:i]
+		}
+
+		lo, hi := min, max
+
+		switch {
+		case item == "*":
+		case strings.Contains(item, "-"):
+			r := strings.SplitN(item, "-", 2)
+
+			l, lerr := strconv.Atoi(r[0])
+			h, herr := strconv.Atoi(r[1])
+
+			if lerr != nil || herr != nil {
+				return nil, fmt.Errorf("invalid range: %s", item)
+			}
+
+			lo, hi = l, h
+		default:
+			v, err := strconv.Atoi(item)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value: %s", item)
+			}
+
+			lo, hi = v, v
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return nil, fmt.Errorf("%s is outside of %d-%d", item, min, max)
+		}
+
+		for v := lo; v <= hi; v += step {
+			values[v] = true
+		}
+	}
+
+	return values, nil
+}
+
+func (ce *cronExpression) matches(t time.Time) bool {
+	if !ce.fields[0][t.Minute()] || !ce.fields[1][t.Hour()] || !ce.fields[3][int(t.Month())] {
+		return false
+	}
+
+	dom := ce.fields[2][t.Day()]
+	dow := ce.fields[4][int(t.Weekday())]
+
+	switch {
+	case ce.anyDom && ce.anyDow:
+		return true
+	case ce.anyDom:
+		return dow
+	case ce.anyDow:
+		return dom
+	default:
+		return dom || dow
+	}
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildScheduleDue(t *testing.T) {
+	s := NewBuildSchedule("web", "30 2 * * *", "https://github.com/convox/rack.git", "")
+
+	assert.Equal(t, "docker-compose.yml", s.Manifest)
+
+	at := time.Date(2016, 10, 1, 2, 30, 15, 0, time.UTC)
+
+	assert.True(t, s.Due(at))
+	assert.False(t, s.Due(at.Add(1*time.Minute)))
+	assert.False(t, s.Due(at.Add(-1*time.Hour)))
+
+	s.LastRun = at
+	assert.False(t, s.Due(at.Add(30*time.Second)))
+	assert.True(t, s.Due(at.Add(24*time.Hour)))
+}
+
+func TestBuildScheduleValidate(t *testing.T) {
+	s := NewBuildSchedule("web", "0 3 * * 1-5", "git@github.com:convox/rack.git#master", "")
+	assert.NoError(t, s.Validate())
+
+	s.URL = "/tmp/rack"
+	assert.EqualError(t, s.Validate(), "url must be a git repository the rack can clone")
+
+	s.URL = "https://github.com/convox/rack.git"
+	s.Schedule = "0 3 * *"
+	assert.EqualError(t, s.Validate(), "schedule must have five fields: minute hour day-of-month month day-of-week")
+
+	s.Schedule = "0 25 * * *"
+	assert.EqualError(t, s.Validate(), `invalid schedule "0 25 * * *": 25 is outside of 0-23`)
+}
+
+func TestCronExpression(t *testing.T) {
+	ce, err := parseCronExpression("*/15 9-17 * * 1-5")
+	assert.NoError(t, err)
+
+	// saturday 2016-10-01
+	assert.False(t, ce.matches(time.Date(2016, 10, 1, 9, 0, 0, 0, time.UTC)))
+	assert.True(t, ce.matches(time.Date(2016, 10, 3, 9, 45, 0, 0, time.UTC)))
+	assert.False(t, ce.matches(time.Date(2016, 10, 3, 9, 40, 0, 0, time.UTC)))
+	assert.False(t, ce.matches(time.Date(2016, 10, 3, 18, 0, 0, 0, time.UTC)))
+
+	// either day field matches when both are restricted
+	ce, err = parseCronExpression("0 0 1 * 7")
+	assert.NoError(t, err)
+	assert.True(t, ce.matches(time.Date(2016, 10, 1, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, ce.matches(time.Date(2016, 10, 2, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, ce.matches(time.Date(2016, 10, 3, 0, 0, 0, 0, time.UTC)))
+
+	_, err = parseCronExpression("0 0 * * */0")
+	assert.EqualError(t, err, `invalid schedule "0 0 * * */0": invalid step: */0`)
+}
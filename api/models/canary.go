@@ -0,0 +1,324 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/convox/rack/manifest"
+)
+
+// Canary runs a release next to the active release behind the same balancers. Classic
+// balancers spread requests across registered tasks, so the share of traffic the canary
+// receives follows the share of tasks it runs.
+type Canary struct {
+	App     string `json:"app"`
+	Release string `json:"release"`
+	Percent int    `json:"percent"`
+
+	// Services are the ecs services running the canary release
+	Services []string `json:"services"`
+
+	Created time.Time `json:"created"`
+}
+
+// GetCanary returns the canary of an app
+func GetCanary(app string) (*Canary, error) {
+	a, err := GetApp(app)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s3Get(a.settingsBucket(), "canary.json")
+	if awserrCode(err) == "NoSuchKey" {
+		return nil, fmt.Errorf("no canary for app: %s", app)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var c Canary
+
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// StartCanary runs a release next to the active release for every process with a balancer,
+// sized to receive about percent of the traffic of each process
+func StartCanary(app, release string, percent int) (*Canary, error) {
+	if percent < 1 || percent > 99 {
+		return nil, fmt.Errorf("canary percent must be between 1 and 99")
+	}
+
+	a, err := GetApp(app)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.Status != "running" {
+		return nil, fmt.Errorf("app is %s: %s", a.Status, app)
+	}
+
+	if c, err := GetCanary(app); err == nil {
+		return nil, fmt.Errorf("app already has a canary of %s, finalize or abort it first", c.Release)
+	}
+
+	if release == a.Release {
+		return nil, fmt.Errorf("%s is already the active release", release)
+	}
+
+	r, err := GetRelease(app, release)
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := GetRelease(app, a.Release)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := manifest.Load([]byte(r.Manifest))
+	if err != nil {
+		return nil, err
+	}
+
+	services, err := GetAppServices(app)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Canary{
+		App:      app,
+		Release:  release,
+		Percent:  percent,
+		Services: []string{},
+		Created:  time.Now().UTC(),
+	}
+
+	for _, s := range services {
+		if len(s.LoadBalancers) == 0 {
+			continue
+		}
+
+		process := *s.LoadBalancers[0].ContainerName
+
+		entry, ok := m.Services[process]
+		if !ok {
+			continue
+		}
+
+		arn, err := startCanaryService(a, s, process, entry.RegistryImage(app, r.Build, a.Outputs), LoadEnvironment([]byte(active.Env)), LoadEnvironment([]byte(r.Env)), release, canaryCount(*s.DesiredCount, percent))
+		if err != nil {
+			c.stop()
+			return nil, err
+		}
+
+		c.Services = append(c.Services, arn)
+	}
+
+	if len(c.Services) == 0 {
+		return nil, fmt.Errorf("%s has no processes behind a balancer to canary", app)
+	}
+
+	if err := c.save(a); err != nil {
+		c.stop()
+		return nil, err
+	}
+
+	NotifySuccess("release:canary", map[string]string{
+		"app":     app,
+		"id":      release,
+		"percent": fmt.Sprintf("%d", percent),
+	})
+
+	return c, nil
+}
+
+// Finalize promotes the canary release and removes the canary services
+func (c *Canary) Finalize() (*Release, error) {
+	r, err := GetRelease(c.App, c.Release)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.Promote(); err != nil {
+		return nil, err
+	}
+
+	if err := c.remove(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Abort removes the canary services leaving the active release to serve all traffic
+func (c *Canary) Abort() error {
+	if err := c.remove(); err != nil {
+		return err
+	}
+
+	NotifySuccess("release:canary:abort", map[string]string{
+		"app": c.App,
+		"id":  c.Release,
+	})
+
+	return nil
+}
+
+func (c *Canary) remove() error {
+	a, err := GetApp(c.App)
+	if err != nil {
+		return err
+	}
+
+	if err := c.stop(); err != nil {
+		return err
+	}
+
+	return s3Delete(a.settingsBucket(), "canary.json")
+}
+
+func (c *Canary) save(a *App) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	return S3Put(a.settingsBucket(), "canary.json", data, false)
+}
+
+// stop scales down and deletes the canary services
+func (c *Canary) stop() error {
+	cluster := appCluster(c.App)
+
+	for _, arn := range c.Services {
+		_, err := ECS().UpdateService(&ecs.UpdateServiceInput{
+			Cluster:      aws.String(cluster),
+			Service:      aws.String(arn),
+			DesiredCount: aws.Int64(0),
+		})
+		if awsError(err) == "ServiceNotFoundException" || awsError(err) == "ServiceNotActiveException" {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = ECS().DeleteService(&ecs.DeleteServiceInput{
+			Cluster: aws.String(cluster),
+			Service: aws.String(arn),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// startCanaryService copies the task definition of a service with the image and environment
+// of the canary release and runs it behind the balancer of the service. Everything else about
+// the task definition is kept, only the family changes so the canary gets its own revisions.
+func startCanaryService(a *App, s *ecs.Service, process, image string, before, after Environment, release string, count int64) (string, error) {
+	res, err := ECS().DescribeTaskDefinition(&ecs.DescribeTaskDefinitionInput{
+		TaskDefinition: s.TaskDefinition,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	td := res.TaskDefinition
+	name := fmt.Sprintf("%s-%s-canary", a.StackName(), process)
+
+	for _, cd := range td.ContainerDefinitions {
+		if *cd.Name == process {
+			cd.Image = aws.String(image)
+			cd.Environment = canaryEnvironment(cd.Environment, before, after, release)
+
+			// secret references are resolved by the entrypoint of the image with the task role
+			if len(SecretReferences(after)) > 0 {
+				cd.Environment = secretsBucketEnvironment(cd.Environment, a.settingsBucket())
+			}
+		}
+	}
+
+	tres, err := ECS().RegisterTaskDefinition(&ecs.RegisterTaskDefinitionInput{
+		ContainerDefinitions: td.ContainerDefinitions,
+		Family:               aws.String(name),
+		NetworkMode:          td.NetworkMode,
+		TaskRoleArn:          td.TaskRoleArn,
+		Volumes:              td.Volumes,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sres, err := ECS().CreateService(&ecs.CreateServiceInput{
+		Cluster:                 aws.String(appCluster(a.Name)),
+		DeploymentConfiguration: s.DeploymentConfiguration,
+		DesiredCount:            aws.Int64(count),
+		LoadBalancers:           s.LoadBalancers,
+		Role:                    s.RoleArn,
+		ServiceName:             aws.String(name),
+		TaskDefinition:          tres.TaskDefinition.TaskDefinitionArn,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return *sres.Service.ServiceArn, nil
+}
+
+// canaryCount returns how many tasks next to stable tasks receive about percent of the traffic
+func canaryCount(stable int64, percent int) int64 {
+	n := (stable*int64(percent) + int64(100-percent)/2) / int64(100-percent)
+
+	if n < 1 {
+		return 1
+	}
+
+	return n
+}
+
+// canaryEnvironment swaps the environment of the active release for that of the canary
+// release, keeping the variables the rack sets on every process
+func canaryEnvironment(env []*ecs.KeyValuePair, before, after Environment, release string) []*ecs.KeyValuePair {
+	vars := []*ecs.KeyValuePair{}
+
+	for _, kv := range env {
+		if _, ok := before[*kv.Name]; ok {
+			continue
+		}
+
+		if _, ok := after[*kv.Name]; ok {
+			continue
+		}
+
+		if *kv.Name == "RELEASE" {
+			continue
+		}
+
+		vars = append(vars, kv)
+	}
+
+	keys := []string{}
+
+	for key := range after {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		vars = append(vars, &ecs.KeyValuePair{Name: aws.String(key), Value: aws.String(after[key])})
+	}
+
+	return append(vars, &ecs.KeyValuePair{Name: aws.String("RELEASE"), Value: aws.String(release)})
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanaryCount(t *testing.T) {
+	assert.Equal(t, int64(1), canaryCount(1, 10))
+	assert.Equal(t, int64(1), canaryCount(9, 10))
+	assert.Equal(t, int64(2), canaryCount(18, 10))
+	assert.Equal(t, int64(4), canaryCount(4, 50))
+	assert.Equal(t, int64(9), canaryCount(1, 90))
+}
+
+func TestCanaryEnvironment(t *testing.T) {
+	env := []*ecs.KeyValuePair{
+		{Name: aws.String("APP"), Value: aws.String("myapp")},
+		{Name: aws.String("FOO"), Value: aws.String("old")},
+		{Name: aws.String("REMOVED"), Value: aws.String("yes")},
+		{Name: aws.String("RELEASE"), Value: aws.String("R1")},
+		{Name: aws.String("PROCESS"), Value: aws.String("web")},
+	}
+
+	before := Environment{"FOO": "old", "REMOVED": "yes"}
+	after := Environment{"FOO": "new", "BAR": "added"}
+
+	vars := map[string]string{}
+	names := []string{}
+
+	for _, kv := range canaryEnvironment(env, before, after, "R2") {
+		vars[*kv.Name] = *kv.Value
+		names = append(names, *kv.Name)
+	}
+
+	assert.Equal(t, []string{"APP", "PROCESS", "BAR", "FOO", "RELEASE"}, names)
+	assert.Equal(t, "new", vars["FOO"])
+	assert.Equal(t, "added", vars["BAR"])
+	assert.Equal(t, "R2", vars["RELEASE"])
+	assert.Equal(t, "myapp", vars["APP"])
+}
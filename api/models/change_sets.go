@@ -0,0 +1,114 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+// StackChange is a resource that a stack update would add, modify or remove
+type StackChange struct {
+	Action      string `json:"action"`
+	Resource    string `json:"resource"`
+	Type        string `json:"type"`
+	Replacement string `json:"replacement,omitempty"`
+}
+
+type StackChanges []StackChange
+
+// ChangeSetTimeout is how long to wait for CloudFormation to work out the changes of an update
+var ChangeSetTimeout = 2 * time.Minute
+
+// PreviewStackUpdate returns the changes an update would make to a stack without applying
+// it. The changes are read from a change set which is deleted once it has been described.
+func PreviewStackUpdate(req *cloudformation.UpdateStackInput) (StackChanges, error) {
+	name := fmt.Sprintf("preview-%d", time.Now().UnixNano())
+
+	_, err := CloudFormation().CreateChangeSet(&cloudformation.CreateChangeSetInput{
+		Capabilities:        req.Capabilities,
+		ChangeSetName:       aws.String(name),
+		Parameters:          req.Parameters,
+		StackName:           req.StackName,
+		TemplateURL:         req.TemplateURL,
+		UsePreviousTemplate: req.UsePreviousTemplate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer CloudFormation().DeleteChangeSet(&cloudformation.DeleteChangeSetInput{
+		ChangeSetName: aws.String(name),
+		StackName:     req.StackName,
+	})
+
+	timeout := time.After(ChangeSetTimeout)
+	tick := time.Tick(2 * time.Second)
+
+	for {
+		changes, done, err := describeChangeSet(*req.StackName, name)
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			return changes, nil
+		}
+
+		select {
+		case <-tick:
+		case <-timeout:
+			return nil, fmt.Errorf("timeout waiting for the changes of %s", *req.StackName)
+		}
+	}
+}
+
+// describeChangeSet returns the changes of a change set once CloudFormation has finished creating it
+func describeChangeSet(stack, name string) (StackChanges, bool, error) {
+	changes := StackChanges{}
+
+	req := &cloudformation.DescribeChangeSetInput{
+		ChangeSetName: aws.String(name),
+		StackName:     aws.String(stack),
+	}
+
+	for {
+		res, err := CloudFormation().DescribeChangeSet(req)
+		if err != nil {
+			return nil, false, err
+		}
+
+		switch status := aws.StringValue(res.Status); status {
+		case "CREATE_COMPLETE":
+		case "FAILED":
+			reason := aws.StringValue(res.StatusReason)
+
+			// an update that changes nothing can not be made into a change set
+			if strings.Contains(reason, "didn't contain changes") {
+				return changes, true, nil
+			}
+
+			return nil, false, fmt.Errorf("unable to preview changes: %s", reason)
+		default:
+			return nil, false, nil
+		}
+
+		for _, c := range res.Changes {
+			if rc := c.ResourceChange; rc != nil {
+				changes = append(changes, StackChange{
+					Action:      aws.StringValue(rc.Action),
+					Resource:    aws.StringValue(rc.LogicalResourceId),
+					Type:        aws.StringValue(rc.ResourceType),
+					Replacement: aws.StringValue(rc.Replacement),
+				})
+			}
+		}
+
+		if res.NextToken == nil {
+			return changes, true, nil
+		}
+
+		req.NextToken = res.NextToken
+	}
+}
@@ -0,0 +1,327 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/convox/rack/manifest"
+	"github.com/fsouza/go-dockerclient"
+)
+
+// CrashHistory is how many crashes are kept for each app
+var CrashHistory = 100
+
+// crashOutputLines is how many lines of output are kept from a crashed container
+const crashOutputLines = 100
+
+// crashWindow is how long after exiting a container is still captured, so that a new
+// monitor does not capture every container the instances still have around
+const crashWindow = 1 * time.Hour
+
+// Crash is a container of an app that exited with an error along with the end of its output.
+// Processes opt in with the convox.crash.capture label, and convox.crash.dump names a file
+// such as a core dump or heap snapshot to keep as well.
+type Crash struct {
+	Id        string    `json:"id"`
+	App       string    `json:"app"`
+	Process   string    `json:"process"`
+	Release   string    `json:"release"`
+	Instance  string    `json:"instance"`
+	ExitCode  int       `json:"exit-code"`
+	OOMKilled bool      `json:"oom-killed"`
+	Output    string    `json:"output"`
+	Dump      string    `json:"dump"`
+	DumpError string    `json:"dump-error,omitempty"`
+	Started   time.Time `json:"started"`
+	Crashed   time.Time `json:"crashed"`
+}
+
+// Crashes are sorted with the latest crash first
+type Crashes []Crash
+
+func (cs Crashes) Len() int           { return len(cs) }
+func (cs Crashes) Less(i, j int) bool { return cs[i].Crashed.After(cs[j].Crashed) }
+func (cs Crashes) Swap(i, j int)      { cs[i], cs[j] = cs[j], cs[i] }
+
+// IsCrash returns true if a container that exited with code crashed. Containers stopped by
+// ECS during a deploy or scale down exit from SIGTERM, or SIGKILL when they do not stop in time.
+func IsCrash(code int, oomKilled bool) bool {
+	switch {
+	case oomKilled:
+		return true
+	case code == 0, code == 128+9, code == 128+15:
+		return false
+	}
+
+	return true
+}
+
+// ListCrashes returns the captured crashes of an app, latest first
+func ListCrashes(app string) (Crashes, error) {
+	a, err := GetApp(app)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.crashes()
+}
+
+// GetCrash returns a single captured crash of an app
+func GetCrash(app, id string) (*Crash, error) {
+	a, err := GetApp(app)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := getCrash(a.settingsBucket(), crashKey(id))
+	if awserrCode(err) == "NoSuchKey" {
+		return nil, fmt.Errorf("no such crash: %s", id)
+	}
+
+	return c, err
+}
+
+// CrashDump writes the tar archive of the dump captured with a crash to w
+func CrashDump(app, id string, w io.Writer) error {
+	c, err := GetCrash(app, id)
+	if err != nil {
+		return err
+	}
+
+	if c.Dump == "" {
+		return fmt.Errorf("crash has no dump: %s", id)
+	}
+
+	a, err := GetApp(app)
+	if err != nil {
+		return err
+	}
+
+	res, err := S3().GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(a.settingsBucket()),
+		Key:    aws.String(crashDumpKey(id)),
+	})
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	_, err = io.Copy(w, res.Body)
+
+	return err
+}
+
+// CaptureCrash keeps the output and dump of an exited container on an instance if it crashed
+// and its process captures crashes. It returns nil when there was nothing to capture.
+func CaptureCrash(d *docker.Client, instance, id string) (*Crash, error) {
+	ci, err := d.InspectContainer(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !IsCrash(ci.State.ExitCode, ci.State.OOMKilled) || ci.State.FinishedAt.Before(time.Now().Add(-crashWindow)) {
+		return nil, nil
+	}
+
+	env := map[string]string{}
+
+	for _, e := range ci.Config.Env {
+		parts := strings.SplitN(e, "=", 2)
+
+		if len(parts) == 2 {
+			env[parts[0]] = parts[1]
+		}
+	}
+
+	if env["APP"] == "" || env["PROCESS"] == "" || env["RELEASE"] == "" {
+		return nil, nil
+	}
+
+	a, err := GetApp(env["APP"])
+	if awserrCode(err) == "ValidationError" {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := GetRelease(a.Name, env["RELEASE"])
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := manifest.Load([]byte(r.Manifest))
+	if err != nil {
+		return nil, err
+	}
+
+	s, ok := m.Services[env["PROCESS"]]
+	if !ok || !s.CrashCapture() {
+		return nil, nil
+	}
+
+	c := &Crash{
+		Id:        id[0:12],
+		App:       a.Name,
+		Process:   env["PROCESS"],
+		Release:   env["RELEASE"],
+		Instance:  instance,
+		ExitCode:  ci.State.ExitCode,
+		OOMKilled: ci.State.OOMKilled,
+		Started:   ci.State.StartedAt,
+		Crashed:   ci.State.FinishedAt,
+	}
+
+	// a monitor that has just taken over does not know what has been captured already
+	if _, err := s3Get(a.settingsBucket(), crashKey(c.Id)); err == nil {
+		return nil, nil
+	} else if awserrCode(err) != "NoSuchKey" {
+		return nil, err
+	}
+
+	var output bytes.Buffer
+
+	err = d.Logs(docker.LogsOptions{
+		Container:    id,
+		OutputStream: &output,
+		ErrorStream:  &output,
+		Stdout:       true,
+		Stderr:       true,
+		Tail:         strconv.Itoa(crashOutputLines),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.Output = output.String()
+
+	// a missing dump should not lose the rest of the crash
+	if path := s.CrashDump(); path != "" {
+		if err := a.captureCrashDump(d, id, c.Id, path); err != nil {
+			c.DumpError = err.Error()
+		} else {
+			c.Dump = path
+		}
+	}
+
+	if err := a.saveCrash(c); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (a *App) captureCrashDump(d *docker.Client, container, id, path string) error {
+	f, err := ioutil.TempFile("", "crash")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	err = d.DownloadFromContainer(container, docker.DownloadFromContainerOptions{
+		OutputStream: f,
+		Path:         path,
+	})
+	if err != nil {
+		return err
+	}
+
+	return S3PutFile(a.settingsBucket(), crashDumpKey(id), f, false)
+}
+
+func (a *App) crashes() (Crashes, error) {
+	keys, err := s3Keys(a.settingsBucket(), "crashes/")
+	if err != nil {
+		return nil, err
+	}
+
+	crashes := Crashes{}
+
+	for _, key := range keys {
+		if !strings.HasSuffix(key, ".json") {
+			continue
+		}
+
+		c, err := getCrash(a.settingsBucket(), key)
+		if err != nil {
+			return nil, err
+		}
+
+		crashes = append(crashes, *c)
+	}
+
+	sort.Sort(crashes)
+
+	return crashes, nil
+}
+
+// saveCrash stores a crash and removes the oldest crashes beyond CrashHistory
+func (a *App) saveCrash(c *Crash) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	if err := S3Put(a.settingsBucket(), crashKey(c.Id), data, false); err != nil {
+		return err
+	}
+
+	crashes, err := a.crashes()
+	if err != nil {
+		return err
+	}
+
+	if len(crashes) <= CrashHistory {
+		return nil
+	}
+
+	for _, old := range crashes[CrashHistory:] {
+		if old.Dump != "" {
+			if err := s3Delete(a.settingsBucket(), crashDumpKey(old.Id)); err != nil {
+				return err
+			}
+		}
+
+		if err := s3Delete(a.settingsBucket(), crashKey(old.Id)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func getCrash(bucket, key string) (*Crash, error) {
+	data, err := s3Get(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Crash
+
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+func crashKey(id string) string {
+	return fmt.Sprintf("crashes/%s.json", id)
+}
+
+func crashDumpKey(id string) string {
+	return fmt.Sprintf("crashes/%s.tar", id)
+}
@@ -0,0 +1,18 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/convox/rack/api/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsCrash(t *testing.T) {
+	assert.False(t, models.IsCrash(0, false))
+	assert.False(t, models.IsCrash(137, false))
+	assert.False(t, models.IsCrash(143, false))
+
+	assert.True(t, models.IsCrash(1, false))
+	assert.True(t, models.IsCrash(139, false))
+	assert.True(t, models.IsCrash(137, true))
+}
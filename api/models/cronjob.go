@@ -45,6 +45,16 @@ func (cr *CronJob) Process() string {
 	return cr.Service.Name
 }
 
+// Expression returns the schedule in the crontab format it was defined with
+func (cr *CronJob) Expression() string {
+	return strings.TrimSuffix(strings.TrimPrefix(cr.Schedule, "cron("), " *)")
+}
+
+// StartedBy returns the ECS startedBy tag for tasks run by this job
+func (cr *CronJob) StartedBy() string {
+	return fmt.Sprintf("cron:%s", cr.Name)
+}
+
 func (cr *CronJob) ShortName() string {
 	shortName := fmt.Sprintf("%s%s", strings.Title(cr.Service.Name), strings.Title(cr.Name))
 
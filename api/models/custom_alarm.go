@@ -0,0 +1,462 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/convox/rack/manifest"
+)
+
+// customAlarmMetric is where CloudWatch keeps a metric custom alarms can watch and how it is usually aggregated
+type customAlarmMetric struct {
+	Namespace string
+	Statistic string
+}
+
+// CustomAlarmMetrics are the metrics of an app's load balancers and services that custom alarms can watch
+var CustomAlarmMetrics = map[string]customAlarmMetric{
+	"BackendConnectionErrors": {"AWS/ELB", "Sum"},
+	"HTTPCode_Backend_2XX":    {"AWS/ELB", "Sum"},
+	"HTTPCode_Backend_3XX":    {"AWS/ELB", "Sum"},
+	"HTTPCode_Backend_4XX":    {"AWS/ELB", "Sum"},
+	"HTTPCode_Backend_5XX":    {"AWS/ELB", "Sum"},
+	"HTTPCode_ELB_4XX":        {"AWS/ELB", "Sum"},
+	"HTTPCode_ELB_5XX":        {"AWS/ELB", "Sum"},
+	"HealthyHostCount":        {"AWS/ELB", "Minimum"},
+	"Latency":                 {"AWS/ELB", "Average"},
+	"RequestCount":            {"AWS/ELB", "Sum"},
+	"SpilloverCount":          {"AWS/ELB", "Sum"},
+	"SurgeQueueLength":        {"AWS/ELB", "Maximum"},
+	"UnHealthyHostCount":      {"AWS/ELB", "Maximum"},
+	"CPUUtilization":          {"AWS/ECS", "Average"},
+	"MemoryUtilization":       {"AWS/ECS", "Average"},
+}
+
+var customAlarmStatistics = []string{"Average", "Maximum", "Minimum", "SampleCount", "Sum"}
+
+// CustomAlarm is a CloudWatch alarm on a metric of an app process defined through the rack.
+// The definition is kept in the app settings bucket and the CloudWatch alarm is recreated
+// with the current resources of the app whenever a stack update replaces them.
+type CustomAlarm struct {
+	Id      string `json:"id"`
+	App     string `json:"app"`
+	Process string `json:"process"`
+	Metric  string `json:"metric"`
+
+	Statistic string  `json:"statistic"`
+	Threshold float64 `json:"threshold"`
+
+	// Comparison is above or below the threshold
+	Comparison string `json:"comparison"`
+
+	// Period is the number of seconds each datapoint covers
+	Period int `json:"period"`
+
+	// Evaluations is the number of consecutive periods past the threshold that trigger the alarm
+	Evaluations int `json:"evaluations"`
+
+	// Action is notify to send to every webhook or notify:<service> to send to one webhook service
+	Action string `json:"action"`
+
+	State   string    `json:"state"`
+	Reason  string    `json:"reason"`
+	Created time.Time `json:"created"`
+}
+
+type CustomAlarms []CustomAlarm
+
+func (cs CustomAlarms) Len() int           { return len(cs) }
+func (cs CustomAlarms) Less(i, j int) bool { return cs[i].Created.Before(cs[j].Created) }
+func (cs CustomAlarms) Swap(i, j int)      { cs[i], cs[j] = cs[j], cs[i] }
+
+// NewCustomAlarm returns a custom alarm on app that notifies every webhook
+func NewCustomAlarm(app string) *CustomAlarm {
+	return &CustomAlarm{
+		Id:          generateId("A", 10),
+		App:         app,
+		Comparison:  "above",
+		Period:      60,
+		Evaluations: 1,
+		Action:      "notify",
+		State:       "INSUFFICIENT_DATA",
+		Created:     time.Now().UTC(),
+	}
+}
+
+// ListCustomAlarms returns the custom alarms of an app, oldest first
+func ListCustomAlarms(app string) (CustomAlarms, error) {
+	a, err := GetApp(app)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := s3Keys(a.settingsBucket(), "alarms/")
+	if err != nil {
+		return nil, err
+	}
+
+	alarms := CustomAlarms{}
+
+	for _, key := range keys {
+		c, err := getCustomAlarm(a.settingsBucket(), key)
+		if err != nil {
+			return nil, err
+		}
+
+		alarms = append(alarms, *c)
+	}
+
+	sort.Sort(alarms)
+
+	return alarms, nil
+}
+
+// GetCustomAlarm returns a single custom alarm of an app
+func GetCustomAlarm(app, id string) (*CustomAlarm, error) {
+	a, err := GetApp(app)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := getCustomAlarm(a.settingsBucket(), customAlarmKey(id))
+	if awserrCode(err) == "NoSuchKey" {
+		return nil, fmt.Errorf("no such alarm: %s", id)
+	}
+
+	return c, err
+}
+
+func getCustomAlarm(bucket, key string) (*CustomAlarm, error) {
+	data, err := s3Get(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var c CustomAlarm
+
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// Validate returns an error if CloudWatch can not alarm on the definition
+func (c *CustomAlarm) Validate() error {
+	metric, ok := CustomAlarmMetrics[c.Metric]
+	if !ok {
+		return fmt.Errorf("metric must be one of %s", strings.Join(CustomAlarmMetricNames(), ", "))
+	}
+
+	if c.Statistic == "" {
+		c.Statistic = metric.Statistic
+	}
+
+	if !containsString(customAlarmStatistics, c.Statistic) {
+		return fmt.Errorf("statistic must be one of %s", strings.Join(customAlarmStatistics, ", "))
+	}
+
+	if c.Comparison != "above" && c.Comparison != "below" {
+		return fmt.Errorf("comparison must be above or below")
+	}
+
+	if c.Period < 60 || c.Period%60 != 0 {
+		return fmt.Errorf("period must be a multiple of 60 seconds")
+	}
+
+	if c.Evaluations < 1 {
+		return fmt.Errorf("evaluations must be at least 1")
+	}
+
+	if c.Action != "notify" && !strings.HasPrefix(c.Action, "notify:") {
+		return fmt.Errorf("action must be notify or notify:<webhook>")
+	}
+
+	return nil
+}
+
+// Name is the name of the CloudWatch alarm for the definition
+func (c *CustomAlarm) Name(a *App) string {
+	return fmt.Sprintf("%s-%s", a.StackName(), c.Id)
+}
+
+// Webhook returns the name of the webhook service the alarm notifies, or an empty
+// string if it notifies every webhook
+func (c *CustomAlarm) Webhook() string {
+	return strings.TrimPrefix(strings.TrimPrefix(c.Action, "notify"), ":")
+}
+
+// Save validates the definition, creates or updates its CloudWatch alarm and stores it in
+// the settings bucket of its app
+func (c *CustomAlarm) Save() error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	if hook := c.Webhook(); hook != "" {
+		if _, err := webhookURL(hook); err != nil {
+			return err
+		}
+	}
+
+	a, err := GetApp(c.App)
+	if err != nil {
+		return err
+	}
+
+	dimensions, err := c.dimensions(a)
+	if err != nil {
+		return err
+	}
+
+	if err := c.put(a, dimensions); err != nil {
+		return err
+	}
+
+	return c.store(a)
+}
+
+// Delete removes the definition and its CloudWatch alarm
+func (c *CustomAlarm) Delete() error {
+	a, err := GetApp(c.App)
+	if err != nil {
+		return err
+	}
+
+	_, err = CloudWatch().DeleteAlarms(&cloudwatch.DeleteAlarmsInput{
+		AlarmNames: []*string{aws.String(c.Name(a))},
+	})
+	if err != nil {
+		return err
+	}
+
+	return s3Delete(a.settingsBucket(), customAlarmKey(c.Id))
+}
+
+// Sync recreates the CloudWatch alarm if it is missing or watches resources the app no
+// longer has, and records its current state. It returns triggered or resolved when the
+// state changed in a way worth notifying about.
+func (c *CustomAlarm) Sync(a *App) (string, error) {
+	dimensions, err := c.dimensions(a)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := CloudWatch().DescribeAlarms(&cloudwatch.DescribeAlarmsInput{
+		AlarmNames: []*string{aws.String(c.Name(a))},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if len(res.MetricAlarms) == 0 || !sameDimensions(res.MetricAlarms[0].Dimensions, dimensions) {
+		return "", c.put(a, dimensions)
+	}
+
+	ma := res.MetricAlarms[0]
+
+	transition := AlarmTransition(c.State, Alarm{State: *ma.StateValue})
+
+	if *ma.StateValue != c.State {
+		c.State = *ma.StateValue
+		c.Reason = ""
+
+		if ma.StateReason != nil {
+			c.Reason = *ma.StateReason
+		}
+
+		if err := c.store(a); err != nil {
+			return "", err
+		}
+	}
+
+	return transition, nil
+}
+
+// Notify sends a notification about a state change of the alarm to its action
+func (c *CustomAlarm) Notify(transition string) error {
+	data := map[string]string{
+		"app":       c.App,
+		"alarm":     c.Id,
+		"process":   c.Process,
+		"metric":    c.Metric,
+		"threshold": fmt.Sprintf("%g", c.Threshold),
+	}
+
+	status := "success"
+
+	if transition == "triggered" {
+		status = "error"
+		data["message"] = c.Reason
+	}
+
+	if hook := c.Webhook(); hook != "" {
+		url, err := webhookURL(hook)
+		if err != nil {
+			return err
+		}
+
+		return NotifyWebhook(url, "alarm:"+transition, status, data)
+	}
+
+	return Notify("alarm:"+transition, status, data)
+}
+
+func (c *CustomAlarm) put(a *App, dimensions []*cloudwatch.Dimension) error {
+	comparison := cloudwatch.ComparisonOperatorGreaterThanThreshold
+
+	if c.Comparison == "below" {
+		comparison = cloudwatch.ComparisonOperatorLessThanThreshold
+	}
+
+	_, err := CloudWatch().PutMetricAlarm(&cloudwatch.PutMetricAlarmInput{
+		AlarmName:          aws.String(c.Name(a)),
+		AlarmDescription:   aws.String(fmt.Sprintf("%s %s %s %s %g", c.App, c.Process, c.Metric, c.Comparison, c.Threshold)),
+		ComparisonOperator: aws.String(comparison),
+		Dimensions:         dimensions,
+		EvaluationPeriods:  aws.Int64(int64(c.Evaluations)),
+		MetricName:         aws.String(c.Metric),
+		Namespace:          aws.String(CustomAlarmMetrics[c.Metric].Namespace),
+		Period:             aws.Int64(int64(c.Period)),
+		Statistic:          aws.String(c.Statistic),
+		Threshold:          aws.Float64(c.Threshold),
+	})
+
+	return err
+}
+
+func (c *CustomAlarm) store(a *App) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	return S3Put(a.settingsBucket(), customAlarmKey(c.Id), data, false)
+}
+
+// dimensions finds the current load balancer or service of the alarm's process. An alarm
+// without a process watches the only process of the app the metric applies to.
+func (c *CustomAlarm) dimensions(a *App) ([]*cloudwatch.Dimension, error) {
+	if a.Release == "" {
+		return nil, fmt.Errorf("app has not been promoted yet: %s", a.Name)
+	}
+
+	r, err := GetRelease(a.Name, a.Release)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := manifest.Load([]byte(r.Manifest))
+	if err != nil {
+		return nil, err
+	}
+
+	balanced := CustomAlarmMetrics[c.Metric].Namespace == "AWS/ELB"
+
+	if c.Process == "" {
+		candidates := []string{}
+
+		for _, s := range m.Services {
+			if !balanced || s.HasBalancer() {
+				candidates = append(candidates, s.Name)
+			}
+		}
+
+		if len(candidates) != 1 {
+			return nil, fmt.Errorf("process is required, %s has %d processes %s can alarm on", a.Name, len(candidates), c.Metric)
+		}
+
+		c.Process = candidates[0]
+	}
+
+	s, ok := m.Services[c.Process]
+	if !ok {
+		return nil, fmt.Errorf("no such process: %s", c.Process)
+	}
+
+	resources, err := a.Resources()
+	if err != nil {
+		return nil, err
+	}
+
+	if balanced {
+		if !s.HasBalancer() {
+			return nil, fmt.Errorf("%s is not behind a load balancer", c.Process)
+		}
+
+		balancer, ok := resources[m.BalancerResourceName(c.Process)]
+		if !ok {
+			return nil, fmt.Errorf("no load balancer found for %s", c.Process)
+		}
+
+		return []*cloudwatch.Dimension{
+			{Name: aws.String("LoadBalancerName"), Value: aws.String(balancer.Id)},
+		}, nil
+	}
+
+	service, ok := resources["Service"+UpperName(c.Process)]
+	if !ok {
+		return nil, fmt.Errorf("no service found for %s", c.Process)
+	}
+
+	// the service resource is its arn, the metrics use the name at the end of it
+	parts := strings.Split(service.Id, "/")
+
+	return []*cloudwatch.Dimension{
+		{Name: aws.String("ClusterName"), Value: aws.String(os.Getenv("CLUSTER"))},
+		{Name: aws.String("ServiceName"), Value: aws.String(parts[len(parts)-1])},
+	}, nil
+}
+
+// CustomAlarmMetricNames returns the metrics custom alarms can watch, sorted
+func CustomAlarmMetricNames() []string {
+	names := []string{}
+
+	for name := range CustomAlarmMetrics {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func customAlarmKey(id string) string {
+	return fmt.Sprintf("alarms/%s.json", id)
+}
+
+func sameDimensions(current, desired []*cloudwatch.Dimension) bool {
+	if len(current) != len(desired) {
+		return false
+	}
+
+	values := map[string]string{}
+
+	for _, d := range current {
+		values[*d.Name] = *d.Value
+	}
+
+	for _, d := range desired {
+		if values[*d.Name] != *d.Value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// webhookURL returns the url a webhook service sends notifications to
+func webhookURL(name string) (string, error) {
+	s, err := Provider().ServiceGet(name)
+	if err != nil || s.Type != "webhook" || s.Exports["URL"] == "" {
+		return "", fmt.Errorf("no such webhook: %s", name)
+	}
+
+	return s.Exports["URL"], nil
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomAlarmValidate(t *testing.T) {
+	c := NewCustomAlarm("myapp")
+	c.Metric = "RequestCount"
+	c.Threshold = 10000
+
+	if assert.NoError(t, c.Validate()) {
+		assert.Equal(t, "Sum", c.Statistic)
+	}
+
+	for _, tc := range []struct {
+		change func(c *CustomAlarm)
+		err    string
+	}{
+		{func(c *CustomAlarm) { c.Metric = "Bogus" }, "metric must be one of "},
+		{func(c *CustomAlarm) { c.Statistic = "p99" }, "statistic must be one of Average, Maximum, Minimum, SampleCount, Sum"},
+		{func(c *CustomAlarm) { c.Comparison = "equal" }, "comparison must be above or below"},
+		{func(c *CustomAlarm) { c.Period = 90 }, "period must be a multiple of 60 seconds"},
+		{func(c *CustomAlarm) { c.Period = 0 }, "period must be a multiple of 60 seconds"},
+		{func(c *CustomAlarm) { c.Evaluations = 0 }, "evaluations must be at least 1"},
+		{func(c *CustomAlarm) { c.Action = "email" }, "action must be notify or notify:<webhook>"},
+	} {
+		c := NewCustomAlarm("myapp")
+		c.Metric = "Latency"
+
+		tc.change(c)
+
+		if err := c.Validate(); assert.Error(t, err) {
+			assert.Contains(t, err.Error(), tc.err)
+		}
+	}
+}
+
+func TestCustomAlarmWebhook(t *testing.T) {
+	c := NewCustomAlarm("myapp")
+	assert.Equal(t, "", c.Webhook())
+
+	c.Action = "notify:slack"
+	assert.Equal(t, "slack", c.Webhook())
+}
+
+func TestSameDimensions(t *testing.T) {
+	dims := func(kv ...string) []*cloudwatch.Dimension {
+		ds := []*cloudwatch.Dimension{}
+
+		for i := 0; i < len(kv); i += 2 {
+			ds = append(ds, &cloudwatch.Dimension{Name: aws.String(kv[i]), Value: aws.String(kv[i+1])})
+		}
+
+		return ds
+	}
+
+	assert.True(t, sameDimensions(dims("ClusterName", "c", "ServiceName", "s"), dims("ServiceName", "s", "ClusterName", "c")))
+	assert.False(t, sameDimensions(dims("LoadBalancerName", "old"), dims("LoadBalancerName", "new")))
+	assert.False(t, sameDimensions(dims(), dims("LoadBalancerName", "new")))
+}
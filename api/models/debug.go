@@ -0,0 +1,147 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/fsouza/go-dockerclient"
+)
+
+// DefaultDebugImage has the usual network and process debugging tools
+const DefaultDebugImage = "nicolaka/netshoot"
+
+// debugCapabilities let a debug container capture packets and trace the processes of its target
+var debugCapabilities = []string{"NET_ADMIN", "NET_RAW", "SYS_PTRACE"}
+
+// DebugAttached starts a container from image next to a running process, sharing its network
+// and process namespaces, and attaches rw to it. The container is removed when it exits.
+func (a *App) DebugAttached(pid, image, command string, height, width int, rw io.ReadWriter) error {
+	ps, err := runningProcess(a.Name, pid)
+	if err != nil {
+		return err
+	}
+
+	d, err := ps.Docker()
+	if err != nil {
+		return err
+	}
+
+	if image == "" {
+		image = DefaultDebugImage
+	}
+
+	image, err = pullImage(d, image)
+	if err != nil {
+		return err
+	}
+
+	res, err := d.CreateContainer(debugContainerOptions(a.Name, ps, image, command))
+	if err != nil {
+		return err
+	}
+
+	defer d.RemoveContainer(docker.RemoveContainerOptions{ID: res.ID, Force: true})
+
+	ir, iw := io.Pipe()
+	or, ow := io.Pipe()
+
+	go d.AttachToContainer(docker.AttachToContainerOptions{
+		Container:    res.ID,
+		InputStream:  ir,
+		OutputStream: ow,
+		ErrorStream:  ow,
+		Stream:       true,
+		Stdin:        true,
+		Stdout:       true,
+		Stderr:       true,
+		RawTerminal:  true,
+	})
+
+	go io.Copy(iw, rw)
+	go io.Copy(rw, or)
+
+	// give the attach a moment to connect so no output is missed
+	time.Sleep(100 * time.Millisecond)
+
+	if err := d.StartContainer(res.ID, nil); err != nil {
+		return err
+	}
+
+	if err := d.ResizeContainerTTY(res.ID, height, width); err != nil {
+		fmt.Printf("fn=DebugAttached level=warning msg=\"unable to resize container: %s\"", err)
+	}
+
+	code, err := d.WaitContainer(res.ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = rw.Write([]byte(fmt.Sprintf("%s%d\n", StatusCodePrefix, code)))
+	return err
+}
+
+// runningProcess returns a process of an app that is running on an instance
+func runningProcess(app, pid string) (*Process, error) {
+	pss, err := ListProcesses(app)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range pss {
+		if p.Id == pid {
+			return p, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no such process id: %s", pid)
+}
+
+// pullImage pulls an image to the docker host of a process and returns its full name
+func pullImage(d *docker.Client, image string) (string, error) {
+	repository, tag := docker.ParseRepositoryTag(image)
+
+	if tag == "" {
+		tag = "latest"
+	}
+
+	if err := d.PullImage(docker.PullImageOptions{Repository: repository, Tag: tag}, docker.AuthConfiguration{}); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s:%s", repository, tag), nil
+}
+
+// debugContainerOptions configure a debug container that joins the namespaces of a process
+func debugContainerOptions(app string, ps *Process, image, command string) docker.CreateContainerOptions {
+	target := fmt.Sprintf("container:%s", ps.containerId)
+
+	config := &docker.Config{
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+		OpenStdin:    true,
+		Tty:          true,
+		Image:        image,
+		Labels: map[string]string{
+			"com.convox.rack.type":    "debug",
+			"com.convox.rack.app":     app,
+			"com.convox.rack.process": ps.Name,
+			"com.convox.rack.target":  ps.Id,
+		},
+	}
+
+	// without a command the image runs its default shell
+	if command != "" {
+		config.Cmd = []string{"sh", "-c", command}
+	}
+
+	return docker.CreateContainerOptions{
+		Config: config,
+		HostConfig: &docker.HostConfig{
+			CapAdd:      debugCapabilities,
+			NetworkMode: target,
+			PidMode:     target,
+		},
+	}
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugContainerOptions(t *testing.T) {
+	ps := &Process{Id: "0123456789ab", Name: "web", containerId: "0123456789abcdef"}
+
+	opts := debugContainerOptions("myapp", ps, "nicolaka/netshoot:latest", "tcpdump -i eth0")
+
+	assert.Equal(t, "nicolaka/netshoot:latest", opts.Config.Image)
+	assert.Equal(t, []string{"sh", "-c", "tcpdump -i eth0"}, opts.Config.Cmd)
+	assert.Equal(t, "debug", opts.Config.Labels["com.convox.rack.type"])
+	assert.Equal(t, "0123456789ab", opts.Config.Labels["com.convox.rack.target"])
+	assert.Equal(t, "container:0123456789abcdef", opts.HostConfig.NetworkMode)
+	assert.Equal(t, "container:0123456789abcdef", opts.HostConfig.PidMode)
+	assert.Contains(t, opts.HostConfig.CapAdd, "SYS_PTRACE")
+
+	opts = debugContainerOptions("myapp", ps, "nicolaka/netshoot:latest", "")
+
+	assert.Nil(t, opts.Config.Cmd)
+}
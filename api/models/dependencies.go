@@ -0,0 +1,155 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+const dependenciesKey = "dependencies.json"
+
+// Dependencies maps each app to the apps and resources it depends on
+type Dependencies map[string][]string
+
+// DependencyImpact is an app affected by a change to another app or resource,
+// with the chain of dependencies leading from the app to it
+type DependencyImpact struct {
+	App  string   `json:"app"`
+	Path []string `json:"path"`
+}
+
+type DependencyImpacts []DependencyImpact
+
+func (is DependencyImpacts) Len() int { return len(is) }
+func (is DependencyImpacts) Less(i, j int) bool {
+	if len(is[i].Path) != len(is[j].Path) {
+		return len(is[i].Path) < len(is[j].Path)
+	}
+	return is[i].App < is[j].App
+}
+func (is DependencyImpacts) Swap(i, j int) { is[i], is[j] = is[j], is[i] }
+
+// GetDependencies returns the declared dependencies of every app on the rack
+func GetDependencies() (Dependencies, error) {
+	data, err := s3Get(os.Getenv("SETTINGS_BUCKET"), dependenciesKey)
+	if awserrCode(err) == "NoSuchKey" {
+		return Dependencies{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	deps := Dependencies{}
+
+	if err := json.Unmarshal(data, &deps); err != nil {
+		return nil, err
+	}
+
+	return deps, nil
+}
+
+// Save stores the dependencies in the rack settings bucket
+func (d Dependencies) Save() error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+
+	return S3Put(os.Getenv("SETTINGS_BUCKET"), dependenciesKey, data, false)
+}
+
+// Add declares that app depends on each of on, refusing dependencies that would form a cycle
+func (d Dependencies) Add(app string, on []string) error {
+	for _, name := range on {
+		if name == app {
+			return fmt.Errorf("app can not depend on itself: %s", app)
+		}
+
+		for _, impact := range d.Dependents(app) {
+			if impact.App == name {
+				return fmt.Errorf("circular dependency: %s already depends on %s", name, app)
+			}
+		}
+
+		if !containsString(d[app], name) {
+			d[app] = append(d[app], name)
+		}
+	}
+
+	sort.Strings(d[app])
+
+	return nil
+}
+
+// Remove drops the dependency of app on name
+func (d Dependencies) Remove(app, name string) error {
+	if !containsString(d[app], name) {
+		return fmt.Errorf("%s does not depend on %s", app, name)
+	}
+
+	deps := []string{}
+
+	for _, dep := range d[app] {
+		if dep != name {
+			deps = append(deps, dep)
+		}
+	}
+
+	if len(deps) == 0 {
+		delete(d, app)
+	} else {
+		d[app] = deps
+	}
+
+	return nil
+}
+
+// Dependents returns every app that depends on name directly or through other apps, nearest first
+func (d Dependencies) Dependents(name string) DependencyImpacts {
+	impacts := DependencyImpacts{}
+
+	seen := map[string]bool{name: true}
+	queue := []DependencyImpact{{App: name, Path: []string{name}}}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		apps := []string{}
+
+		for app, deps := range d {
+			if !seen[app] && containsString(deps, current.App) {
+				apps = append(apps, app)
+			}
+		}
+
+		sort.Strings(apps)
+
+		for _, app := range apps {
+			seen[app] = true
+
+			impact := DependencyImpact{
+				App:  app,
+				Path: append([]string{app}, current.Path...),
+			}
+
+			impacts = append(impacts, impact)
+			queue = append(queue, impact)
+		}
+	}
+
+	sort.Sort(impacts)
+
+	return impacts
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
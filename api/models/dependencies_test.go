@@ -0,0 +1,28 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependenciesDependents(t *testing.T) {
+	deps := Dependencies{}
+
+	assert.Nil(t, deps.Add("api", []string{"postgres-123"}))
+	assert.Nil(t, deps.Add("web", []string{"api"}))
+	assert.Nil(t, deps.Add("worker", []string{"postgres-123", "api"}))
+
+	assert.Equal(t, DependencyImpacts{
+		{App: "api", Path: []string{"api", "postgres-123"}},
+		{App: "worker", Path: []string{"worker", "postgres-123"}},
+		{App: "web", Path: []string{"web", "api", "postgres-123"}},
+	}, deps.Dependents("postgres-123"))
+
+	assert.EqualError(t, deps.Add("api", []string{"web"}), "circular dependency: web already depends on api")
+	assert.EqualError(t, deps.Add("api", []string{"api"}), "app can not depend on itself: api")
+
+	assert.Nil(t, deps.Remove("worker", "api"))
+	assert.Equal(t, []string{"postgres-123"}, deps["worker"])
+	assert.EqualError(t, deps.Remove("worker", "api"), "worker does not depend on api")
+}
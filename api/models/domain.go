@@ -0,0 +1,280 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elb"
+)
+
+// Domain is a custom domain of an app with a Route53 alias record that points at the balancer
+// of one of its processes
+type Domain struct {
+	Domain  string `json:"domain"`
+	App     string `json:"app"`
+	Process string `json:"process"`
+
+	// Zone is the Route53 hosted zone of the domain and Target the balancer its record points at
+	Zone       string `json:"zone"`
+	Target     string `json:"target"`
+	TargetZone string `json:"target-zone"`
+
+	// Status is pending until Change has reached all of the Route53 name servers, then active
+	Status string `json:"status"`
+	Change string `json:"change,omitempty"`
+
+	Created time.Time `json:"created"`
+}
+
+type Domains []Domain
+
+func (ds Domains) Len() int           { return len(ds) }
+func (ds Domains) Less(i, j int) bool { return ds[i].Domain < ds[j].Domain }
+func (ds Domains) Swap(i, j int)      { ds[i], ds[j] = ds[j], ds[i] }
+
+var (
+	domainName       = regexp.MustCompile(`^(\*\.)?([a-z0-9]([a-z0-9-]*[a-z0-9])?\.)+[a-z]{2,}$`)
+	domainBalancer   = regexp.MustCompile(`^Balancer(\w+)Host$`)
+	domainBalancerId = regexp.MustCompile(`^(\w+)Port\d+BalancerName$`)
+)
+
+// AddDomain points a domain at the balancer of a process of an app. The process can be left
+// empty when the app has a single balancer.
+func AddDomain(app, domain, process string) (*Domain, error) {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+
+	if !domainName.MatchString(domain) {
+		return nil, fmt.Errorf("invalid domain: %s", domain)
+	}
+
+	a, err := GetApp(app)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = getDomain(a, domain)
+	if err == nil {
+		return nil, fmt.Errorf("domain already added: %s", domain)
+	}
+	if !strings.HasPrefix(err.Error(), "no such domain") {
+		return nil, err
+	}
+
+	process, balancer, err := a.domainBalancer(process)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := ELB().DescribeLoadBalancers(&elb.DescribeLoadBalancersInput{
+		LoadBalancerNames: []*string{aws.String(balancer)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(res.LoadBalancerDescriptions) < 1 {
+		return nil, fmt.Errorf("no such balancer: %s", balancer)
+	}
+
+	lb := res.LoadBalancerDescriptions[0]
+
+	zone, err := route53Zone(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Domain{
+		Domain:     domain,
+		App:        a.Name,
+		Process:    process,
+		Zone:       zone,
+		Target:     *lb.DNSName,
+		TargetZone: *lb.CanonicalHostedZoneNameID,
+		Status:     "pending",
+		Created:    time.Now().UTC(),
+	}
+
+	// CREATE fails rather than replacing a record that was set up by hand
+	d.Change, err = route53Change(d.Zone, "CREATE", d.record())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.save(a); err != nil {
+		return nil, err
+	}
+
+	NotifySuccess("domain:add", map[string]string{"app": a.Name, "domain": domain, "process": process})
+
+	return d, nil
+}
+
+// ListDomains returns the custom domains of an app, marking those whose records have reached
+// all of the Route53 name servers as active
+func ListDomains(app string) (Domains, error) {
+	a, err := GetApp(app)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := s3Keys(a.settingsBucket(), "domains/")
+	if err != nil {
+		return nil, err
+	}
+
+	domains := Domains{}
+
+	for _, key := range keys {
+		data, err := s3Get(a.settingsBucket(), key)
+		if err != nil {
+			return nil, err
+		}
+
+		var d Domain
+
+		if err := json.Unmarshal(data, &d); err != nil {
+			return nil, err
+		}
+
+		if d.Status == "pending" {
+			synced, err := route53ChangeSynced(d.Change)
+			if err != nil {
+				return nil, err
+			}
+
+			if synced {
+				d.Status = "active"
+				d.Change = ""
+
+				if err := d.save(a); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		domains = append(domains, d)
+	}
+
+	sort.Sort(domains)
+
+	return domains, nil
+}
+
+// RemoveDomain deletes the alias record of a custom domain of an app
+func RemoveDomain(app, domain string) error {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+
+	a, err := GetApp(app)
+	if err != nil {
+		return err
+	}
+
+	d, err := getDomain(a, domain)
+	if err != nil {
+		return err
+	}
+
+	// a record that was already deleted by hand only needs to be forgotten
+	if _, err := route53Change(d.Zone, "DELETE", d.record()); err != nil && !strings.Contains(err.Error(), "but it was not found") {
+		return err
+	}
+
+	if err := s3Delete(a.settingsBucket(), domainKey(domain)); err != nil {
+		return err
+	}
+
+	NotifySuccess("domain:remove", map[string]string{"app": a.Name, "domain": domain})
+
+	return nil
+}
+
+func getDomain(a *App, domain string) (*Domain, error) {
+	data, err := s3Get(a.settingsBucket(), domainKey(domain))
+	if awserrCode(err) == "NoSuchKey" {
+		return nil, fmt.Errorf("no such domain: %s", domain)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var d Domain
+
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+func (d *Domain) save(a *App) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+
+	return S3Put(a.settingsBucket(), domainKey(d.Domain), data, false)
+}
+
+func (d *Domain) record() route53RecordSet {
+	return route53RecordSet{
+		Name: d.Domain + ".",
+		Type: "A",
+		AliasTarget: &route53AliasTarget{
+			HostedZoneId: d.TargetZone,
+			DNSName:      d.Target + ".",
+		},
+	}
+}
+
+// domainBalancer returns the process and name of the balancer a domain of the app should point
+// at, which is the only balancer of the app when no process is given
+func (a *App) domainBalancer(process string) (string, string, error) {
+	balancers := map[string]string{}
+
+	for key := range a.Outputs {
+		if m := domainBalancer.FindStringSubmatch(key); m != nil {
+			balancers[DashName(m[1])] = ""
+		}
+	}
+
+	for key, value := range a.Outputs {
+		if m := domainBalancerId.FindStringSubmatch(key); m != nil {
+			if _, ok := balancers[DashName(m[1])]; ok {
+				balancers[DashName(m[1])] = value
+			}
+		}
+	}
+
+	names := []string{}
+
+	for name := range balancers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	switch {
+	case len(names) == 0:
+		return "", "", fmt.Errorf("process must have a load balancer, app has none")
+	case process == "" && len(names) > 1:
+		return "", "", fmt.Errorf("process must be specified, app has load balancers for: %s", strings.Join(names, ", "))
+	case process == "":
+		process = names[0]
+	}
+
+	balancer := balancers[process]
+
+	if balancer == "" {
+		return "", "", fmt.Errorf("process must have a load balancer: %s", process)
+	}
+
+	return process, balancer, nil
+}
+
+func domainKey(domain string) string {
+	return fmt.Sprintf("domains/%s.json", domain)
+}
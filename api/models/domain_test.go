@@ -0,0 +1,41 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomainBalancer(t *testing.T) {
+	a := &App{Outputs: map[string]string{
+		"BalancerWebHost":         "foo-web.us-east-1.elb.amazonaws.com",
+		"WebPort80BalancerName":   "foo-web-abc",
+		"WebPort443BalancerName":  "foo-web-abc",
+		"BalancerAdminHost":       "foo-admin.us-east-1.elb.amazonaws.com",
+		"AdminPort80BalancerName": "foo-admin-def",
+	}}
+
+	process, balancer, err := a.domainBalancer("admin")
+	assert.Nil(t, err)
+	assert.Equal(t, "admin", process)
+	assert.Equal(t, "foo-admin-def", balancer)
+
+	_, _, err = a.domainBalancer("")
+	assert.EqualError(t, err, "process must be specified, app has load balancers for: admin, web")
+
+	_, _, err = a.domainBalancer("worker")
+	assert.EqualError(t, err, "process must have a load balancer: worker")
+
+	a = &App{Outputs: map[string]string{
+		"BalancerWebHost":       "foo-web.us-east-1.elb.amazonaws.com",
+		"WebPort80BalancerName": "foo-web-abc",
+	}}
+
+	process, balancer, err = a.domainBalancer("")
+	assert.Nil(t, err)
+	assert.Equal(t, "web", process)
+	assert.Equal(t, "foo-web-abc", balancer)
+
+	_, _, err = (&App{Outputs: map[string]string{"BalancerHost": "foo.us-east-1.elb.amazonaws.com"}}).domainBalancer("")
+	assert.EqualError(t, err, "process must have a load balancer, app has none")
+}
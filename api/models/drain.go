@@ -0,0 +1,388 @@
+package models
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/convox/rack/api/structs"
+)
+
+// DrainBatchSize is the most log events a drain forwards at a time
+const DrainBatchSize = 1000
+
+// DrainSchemes are the URL schemes of the endpoints logs can be forwarded to
+var DrainSchemes = []string{"http", "https", "kinesis", "syslog", "syslog+tcp", "syslog+tls", "syslog+udp"}
+
+var drainClient = &http.Client{Timeout: 10 * time.Second}
+
+// Drain forwards the logs of an app to a syslog, HTTP or Kinesis endpoint
+type Drain struct {
+	Id  string `json:"id"`
+	App string `json:"app"`
+	URL string `json:"url"`
+
+	// Cursor is the timestamp in milliseconds of the last log event forwarded
+	Cursor int64 `json:"cursor"`
+
+	Status  string    `json:"status"`
+	Error   string    `json:"error,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+// DrainEvent is a line of app output on its way to a drain
+type DrainEvent struct {
+	Time    time.Time `json:"time"`
+	App     string    `json:"app"`
+	Stream  string    `json:"stream"`
+	Message string    `json:"message"`
+}
+
+type DrainEvents []DrainEvent
+
+type Drains []Drain
+
+func (ds Drains) Len() int           { return len(ds) }
+func (ds Drains) Less(i, j int) bool { return ds[i].Created.Before(ds[j].Created) }
+func (ds Drains) Swap(i, j int)      { ds[i], ds[j] = ds[j], ds[i] }
+
+// NewDrain returns a drain of app to url that forwards logs from now on
+func NewDrain(app, url string) *Drain {
+	now := time.Now().UTC()
+
+	return &Drain{
+		Id:      generateId("D", 10),
+		App:     app,
+		URL:     url,
+		Cursor:  now.UnixNano() / int64(time.Millisecond),
+		Status:  "active",
+		Created: now,
+	}
+}
+
+// ListDrains returns the drains of an app, oldest first
+func ListDrains(app string) (Drains, error) {
+	a, err := GetApp(app)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := s3Keys(a.settingsBucket(), "drains/")
+	if err != nil {
+		return nil, err
+	}
+
+	drains := Drains{}
+
+	for _, key := range keys {
+		d, err := getDrain(a.settingsBucket(), key)
+		if err != nil {
+			return nil, err
+		}
+
+		drains = append(drains, *d)
+	}
+
+	sort.Sort(drains)
+
+	return drains, nil
+}
+
+// GetDrain returns a single drain of an app
+func GetDrain(app, id string) (*Drain, error) {
+	a, err := GetApp(app)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := getDrain(a.settingsBucket(), drainKey(id))
+	if awserrCode(err) == "NoSuchKey" {
+		return nil, fmt.Errorf("no such drain: %s", id)
+	}
+
+	return d, err
+}
+
+func getDrain(bucket, key string) (*Drain, error) {
+	data, err := s3Get(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var d Drain
+
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// Validate returns an error if logs can not be forwarded to the url of the drain
+func (d *Drain) Validate() error {
+	u, err := url.Parse(d.URL)
+	if err != nil {
+		return fmt.Errorf("invalid url: %s", d.URL)
+	}
+
+	known := false
+
+	for _, scheme := range DrainSchemes {
+		if u.Scheme == scheme {
+			known = true
+		}
+	}
+
+	if !known {
+		return fmt.Errorf("url scheme must be one of: %s", strings.Join(DrainSchemes, ", "))
+	}
+
+	if u.Host == "" {
+		return fmt.Errorf("url must include a host")
+	}
+
+	if strings.HasPrefix(u.Scheme, "syslog") {
+		if _, _, err := net.SplitHostPort(u.Host); err != nil {
+			return fmt.Errorf("syslog url must include a port")
+		}
+	}
+
+	return nil
+}
+
+// Save stores the drain in the settings bucket of its app
+func (d *Drain) Save() error {
+	a, err := GetApp(d.App)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+
+	return S3Put(a.settingsBucket(), drainKey(d.Id), data, false)
+}
+
+// Delete stops the drain from forwarding any more logs
+func (d *Drain) Delete() error {
+	a, err := GetApp(d.App)
+	if err != nil {
+		return err
+	}
+
+	return s3Delete(a.settingsBucket(), drainKey(d.Id))
+}
+
+// Events returns the log events of the app written after the cursor of the drain, oldest
+// first and scrubbed by the log scrub rules of the app
+func (d *Drain) Events() (DrainEvents, error) {
+	a, err := GetApp(d.App)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := Provider().LogScrubList(d.App)
+	if err != nil {
+		return nil, err
+	}
+
+	scrubber, err := structs.NewLogScrubber(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &cloudwatchlogs.FilterLogEventsInput{
+		Interleaved:  aws.Bool(true),
+		LogGroupName: aws.String(a.Outputs["LogGroup"]),
+		StartTime:    aws.Int64(d.Cursor + 1),
+	}
+
+	raw := []*cloudwatchlogs.FilteredLogEvent{}
+
+	for len(raw) < DrainBatchSize {
+		res, err := CloudWatchLogs().FilterLogEvents(req)
+		if err != nil {
+			return nil, err
+		}
+
+		raw = append(raw, res.Events...)
+
+		if res.NextToken == nil {
+			break
+		}
+
+		req.NextToken = res.NextToken
+	}
+
+	sort.Sort(drainLogEvents(raw))
+
+	events := DrainEvents{}
+
+	for _, e := range raw {
+		ms := aws.Int64Value(e.Timestamp)
+
+		events = append(events, DrainEvent{
+			Time:    time.Unix(0, ms*int64(time.Millisecond)).UTC(),
+			App:     d.App,
+			Stream:  aws.StringValue(e.LogStreamName),
+			Message: scrubber.Scrub(aws.StringValue(e.Message)),
+		})
+	}
+
+	return events, nil
+}
+
+// Forward sends events to the endpoint of the drain and moves its cursor past them
+func (d *Drain) Forward(events DrainEvents) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	u, err := url.Parse(d.URL)
+	if err != nil {
+		return err
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		err = forwardHTTP(d.URL, events)
+	case "kinesis":
+		err = forwardKinesis(u.Host, events)
+	case "syslog", "syslog+tcp":
+		err = forwardSyslog("tcp", u.Host, false, events)
+	case "syslog+tls":
+		err = forwardSyslog("tcp", u.Host, true, events)
+	case "syslog+udp":
+		err = forwardSyslog("udp", u.Host, false, events)
+	default:
+		err = fmt.Errorf("unknown drain scheme: %s", u.Scheme)
+	}
+	if err != nil {
+		return err
+	}
+
+	d.Cursor = events[len(events)-1].Time.UnixNano() / int64(time.Millisecond)
+
+	return nil
+}
+
+// Syslog returns the event as an RFC 5424 message. The stream of an event is named
+// process:release/container so the process is used as the app name of the message.
+func (e DrainEvent) Syslog() string {
+	process, proc := e.Stream, "-"
+
+	if parts := strings.SplitN(e.Stream, ":", 2); len(parts) == 2 {
+		process, proc = parts[0], parts[1]
+	}
+
+	// facility user, severity informational
+	return fmt.Sprintf("<14>1 %s %s %s %s - - %s\n", e.Time.Format("2006-01-02T15:04:05.000Z07:00"), e.App, process, proc, e.Message)
+}
+
+func forwardHTTP(endpoint string, events DrainEvents) error {
+	data, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+
+	res, err := drainClient.Post(endpoint, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode >= 400 {
+		return fmt.Errorf("response status %d", res.StatusCode)
+	}
+
+	return nil
+}
+
+func forwardKinesis(stream string, events DrainEvents) error {
+	// PutRecords takes at most 500 records at a time
+	for start := 0; start < len(events); start += 500 {
+		end := start + 500
+
+		if end > len(events) {
+			end = len(events)
+		}
+
+		req := &kinesis.PutRecordsInput{
+			StreamName: aws.String(stream),
+		}
+
+		for _, e := range events[start:end] {
+			data, err := json.Marshal(e)
+			if err != nil {
+				return err
+			}
+
+			req.Records = append(req.Records, &kinesis.PutRecordsRequestEntry{
+				Data:         data,
+				PartitionKey: aws.String(e.Stream),
+			})
+		}
+
+		res, err := Kinesis().PutRecords(req)
+		if err != nil {
+			return err
+		}
+
+		if n := aws.Int64Value(res.FailedRecordCount); n > 0 {
+			return fmt.Errorf("kinesis rejected %d records", n)
+		}
+	}
+
+	return nil
+}
+
+func forwardSyslog(network, address string, secure bool, events DrainEvents) error {
+	var conn net.Conn
+	var err error
+
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+
+	if secure {
+		conn, err = tls.DialWithDialer(dialer, network, address, &tls.Config{})
+	} else {
+		conn, err = dialer.Dial(network, address)
+	}
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	conn.SetWriteDeadline(time.Now().Add(30 * time.Second))
+
+	for _, e := range events {
+		if _, err := conn.Write([]byte(e.Syslog())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func drainKey(id string) string {
+	return fmt.Sprintf("drains/%s.json", id)
+}
+
+type drainLogEvents []*cloudwatchlogs.FilteredLogEvent
+
+func (e drainLogEvents) Len() int           { return len(e) }
+func (e drainLogEvents) Less(i, j int) bool { return *(e[i].Timestamp) < *(e[j].Timestamp) }
+func (e drainLogEvents) Swap(i, j int)      { e[i], e[j] = e[j], e[i] }
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrainValidate(t *testing.T) {
+	valid := []string{
+		"syslog+tls://logs.papertrailapp.com:12345",
+		"syslog+udp://10.0.0.1:514",
+		"https://logs.example.org/ingest?token=abc",
+		"kinesis://app-logs",
+	}
+
+	for _, url := range valid {
+		assert.Nil(t, NewDrain("web", url).Validate(), url)
+	}
+
+	invalid := map[string]string{
+		"ftp://example.org":             "url scheme must be one of: http, https, kinesis, syslog, syslog+tcp, syslog+tls, syslog+udp",
+		"syslog+tls://logs.example.org": "syslog url must include a port",
+		"https://":                      "url must include a host",
+	}
+
+	for url, message := range invalid {
+		if err := NewDrain("web", url).Validate(); assert.NotNil(t, err, url) {
+			assert.Equal(t, message, err.Error())
+		}
+	}
+}
+
+func TestDrainEventSyslog(t *testing.T) {
+	e := DrainEvent{
+		Time:    time.Date(2016, 10, 1, 12, 0, 0, 250000000, time.UTC),
+		App:     "web",
+		Stream:  "worker:RABCDEFGHI/0123456789ab",
+		Message: "job finished",
+	}
+
+	assert.Equal(t, "<14>1 2016-10-01T12:00:00.250Z web worker RABCDEFGHI/0123456789ab - - job finished\n", e.Syslog())
+}
+
+func TestDrainForwardHTTP(t *testing.T) {
+	received := DrainEvents{}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer ts.Close()
+
+	d := NewDrain("web", ts.URL)
+
+	events := drainTestEvents()
+
+	if assert.Nil(t, d.Forward(events)) {
+		assert.Equal(t, events, received)
+		assert.Equal(t, int64(1475323201000), d.Cursor)
+	}
+}
+
+func TestDrainForwardSyslog(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer l.Close()
+
+	lines := make(chan string, 2)
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		s := bufio.NewScanner(conn)
+
+		for s.Scan() {
+			lines <- s.Text()
+		}
+	}()
+
+	d := NewDrain("web", "syslog+tcp://"+l.Addr().String())
+
+	if assert.Nil(t, d.Forward(drainTestEvents())) {
+		assert.Equal(t, "<14>1 2016-10-01T12:00:00.000Z web web RA/1 - - starting", <-lines)
+		assert.Equal(t, "<14>1 2016-10-01T12:00:01.000Z web web RA/1 - - listening", <-lines)
+	}
+}
+
+func TestDrainForwardFailure(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(503)
+	}))
+	defer ts.Close()
+
+	d := NewDrain("web", ts.URL)
+	cursor := d.Cursor
+
+	if err := d.Forward(drainTestEvents()); assert.NotNil(t, err) {
+		assert.Equal(t, "response status 503", err.Error())
+		assert.Equal(t, cursor, d.Cursor)
+	}
+}
+
+func drainTestEvents() DrainEvents {
+	return DrainEvents{
+		{Time: time.Date(2016, 10, 1, 12, 0, 0, 0, time.UTC), App: "web", Stream: "web:RA/1", Message: "starting"},
+		{Time: time.Date(2016, 10, 1, 12, 0, 1, 0, time.UTC), App: "web", Stream: "web:RA/1", Message: "listening"},
+	}
+}
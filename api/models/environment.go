@@ -65,50 +65,111 @@ func GetEnvironment(app string) (Environment, error) {
 	return LoadEnvironment(data), nil
 }
 
-func PutEnvironment(app string, env Environment) (string, error) {
-	a, err := GetApp(app)
+// PutEnvironment stores the environment of an app and creates a release carrying it
+func PutEnvironment(app string, env Environment, user string) (string, error) {
+	a, err := environmentApp(app)
 	if err != nil {
 		return "", err
 	}
 
+	release, err := a.releaseEnvironment(env, user)
+	if err != nil {
+		return "", err
+	}
+
+	if err := a.storeEnvironment(env); err != nil {
+		return "", err
+	}
+
+	return release.Id, nil
+}
+
+// StageEnvironment stores the environment of an app without creating a release. Staged
+// changes are released by CommitEnvironment or the next environment change or build.
+func StageEnvironment(app string, env Environment) error {
+	a, err := environmentApp(app)
+	if err != nil {
+		return err
+	}
+
+	return a.storeEnvironment(env)
+}
+
+// CommitEnvironment creates a release carrying the staged environment of an app
+func CommitEnvironment(app, user string) (*Release, error) {
+	a, err := environmentApp(app)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := GetEnvironment(app)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.Release != "" {
+		current, err := GetRelease(app, a.Release)
+		if err != nil {
+			return nil, err
+		}
+
+		if LoadEnvironment([]byte(current.Env)).Raw() == env.Raw() {
+			return nil, fmt.Errorf("no staged environment changes")
+		}
+	}
+
+	return a.releaseEnvironment(env, user)
+}
+
+// environmentApp returns an app whose environment can be changed
+func environmentApp(app string) (*App, error) {
+	a, err := GetApp(app)
+	if err != nil {
+		return nil, err
+	}
+
 	switch a.Status {
 	case "creating":
-		return "", fmt.Errorf("app is still creating: %s", app)
+		return nil, fmt.Errorf("app is still creating: %s", app)
 	case "running", "updating":
 	default:
-		return "", fmt.Errorf("unable to set environment on app: %s", app)
+		return nil, fmt.Errorf("unable to set environment on app: %s", app)
 	}
 
+	return a, nil
+}
+
+func (a *App) releaseEnvironment(env Environment, user string) (*Release, error) {
 	release, err := a.ForkRelease()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	release.Env = env.Raw()
+	release.CreatedBy = user
 
-	err = release.Save()
-	if err != nil {
-		return "", err
+	if err := release.Save(); err != nil {
+		return nil, err
 	}
 
+	return release, nil
+}
+
+func (a *App) storeEnvironment(env Environment) error {
 	e := []byte(env.Raw())
 
 	if a.Parameters["Key"] != "" {
 		cr := crypt.New(os.Getenv("AWS_REGION"), os.Getenv("AWS_ACCESS"), os.Getenv("AWS_SECRET"))
 
-		e, err = cr.Encrypt(a.Parameters["Key"], e)
-
+		data, err := cr.Encrypt(a.Parameters["Key"], e)
 		if err != nil {
-			return "", err
+			return err
 		}
-	}
 
-	err = S3Put(a.Outputs["Settings"], "env", []byte(e), true)
-	if err != nil {
-		return "", err
+		e = data
 	}
 
-	return release.Id, nil
+	return S3Put(a.Outputs["Settings"], "env", e, false)
 }
 
 // Use the Rack Settings bucket and EncryptionKey KMS key to store and retrieve
@@ -158,7 +219,7 @@ func PutRackSettings(env Environment) error {
 		return err
 	}
 
-	key := resources["EncryptionKey"].Id
+	key := os.Getenv("ENCRYPTION_KEY")
 	settings := resources["Settings"].Id
 
 	e, err := json.Marshal(env)
@@ -176,7 +237,7 @@ func PutRackSettings(env Environment) error {
 		}
 	}
 
-	err = S3Put(settings, "env", []byte(e), true)
+	err = S3Put(settings, "env", []byte(e), false)
 	return err
 }
 
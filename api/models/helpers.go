@@ -237,6 +237,35 @@ func s3Get(bucket, key string) ([]byte, error) {
 	return ioutil.ReadAll(res.Body)
 }
 
+// s3Keys returns the keys of every object in bucket under prefix
+func s3Keys(bucket, prefix string) ([]string, error) {
+	keys := []string{}
+
+	err := S3().ListObjectsPages(&s3.ListObjectsInput{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsOutput, last bool) bool {
+		for _, o := range page.Contents {
+			keys = append(keys, *o.Key)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func s3Delete(bucket, key string) error {
+	_, err := S3().DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+
+	return err
+}
+
 func S3Put(bucket, key string, data []byte, public bool) error {
 	req := &s3.PutObjectInput{
 		Body:          bytes.NewReader(data),
@@ -249,11 +278,27 @@ func S3Put(bucket, key string, data []byte, public bool) error {
 		req.ACL = aws.String("public-read")
 	}
 
+	s3Encryption(req, public)
+
 	_, err := S3().PutObject(req)
 
 	return err
 }
 
+// s3Encryption encrypts an object at rest with the rack encryption key. Public objects
+// are fetched anonymously by url, which kms does not allow, so they use an s3 managed key.
+func s3Encryption(req *s3.PutObjectInput, public bool) {
+	key := os.Getenv("ENCRYPTION_KEY")
+
+	if public || key == "" {
+		req.ServerSideEncryption = aws.String(s3.ServerSideEncryptionAes256)
+		return
+	}
+
+	req.ServerSideEncryption = aws.String(s3.ServerSideEncryptionAwsKms)
+	req.SSEKMSKeyId = aws.String(key)
+}
+
 func S3PutFile(bucket, key string, f io.ReadSeeker, public bool) error {
 	// seek to end of f to determine length, then seek back to beginning for upload
 	l, err := f.Seek(0, 2)
@@ -279,6 +324,8 @@ func S3PutFile(bucket, key string, f io.ReadSeeker, public bool) error {
 		req.ACL = aws.String("public-read")
 	}
 
+	s3Encryption(req, public)
+
 	_, err = S3().PutObject(req)
 
 	if err != nil {
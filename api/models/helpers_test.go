@@ -0,0 +1,36 @@
+package models
+
+import (
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestS3Encryption(t *testing.T) {
+	defer os.Setenv("ENCRYPTION_KEY", os.Getenv("ENCRYPTION_KEY"))
+
+	os.Setenv("ENCRYPTION_KEY", "arn:aws:kms:us-east-1:123456789012:key/test")
+
+	req := &s3.PutObjectInput{}
+	s3Encryption(req, false)
+
+	assert.Equal(t, "aws:kms", aws.StringValue(req.ServerSideEncryption))
+	assert.Equal(t, "arn:aws:kms:us-east-1:123456789012:key/test", aws.StringValue(req.SSEKMSKeyId))
+
+	req = &s3.PutObjectInput{}
+	s3Encryption(req, true)
+
+	assert.Equal(t, "AES256", aws.StringValue(req.ServerSideEncryption))
+	assert.Nil(t, req.SSEKMSKeyId)
+
+	os.Setenv("ENCRYPTION_KEY", "")
+
+	req = &s3.PutObjectInput{}
+	s3Encryption(req, false)
+
+	assert.Equal(t, "AES256", aws.StringValue(req.ServerSideEncryption))
+	assert.Nil(t, req.SSEKMSKeyId)
+}
@@ -3,46 +3,20 @@ package models
 import (
 	"fmt"
 	"io"
-	"math/rand"
 	"os"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/fsouza/go-dockerclient"
 	"golang.org/x/crypto/ssh"
 )
 
-func InstanceKeyroll() error {
-	keyname := fmt.Sprintf("%s-keypair-%d", os.Getenv("RACK"), (rand.Intn(8999) + 1000))
-	keypair, err := EC2().CreateKeyPair(&ec2.CreateKeyPairInput{
-		KeyName: &keyname,
-	})
-	if err != nil {
-		return err
-	}
-
-	env, err := GetRackSettings()
-	if err != nil {
-		return err
-	}
-
-	env["InstancePEM"] = *keypair.KeyMaterial
-	err = PutRackSettings(env)
-	if err != nil {
-		return err
-	}
-
-	app, err := GetApp(os.Getenv("RACK"))
-	if err != nil {
-		return err
-	}
-
-	err = app.UpdateParams(map[string]string{
-		"Key": keyname,
-	})
-	return err
-}
-
-func InstanceSSH(id, command, term string, height, width int, rw io.ReadWriter) error {
+// InstanceSSH runs a shell or command on an instance over rw. When agent is set it is called
+// to open a connection to the ssh agent of the client each time the instance asks for one.
+func InstanceSSH(id, command, term string, height, width int, rw io.ReadWriter, agent func() io.ReadWriteCloser) error {
 	instanceIds := []*string{&id}
 	ec2Res, err := EC2().DescribeInstances(&ec2.DescribeInstancesInput{
 		Filters: []*ec2.Filter{
@@ -106,6 +80,18 @@ func InstanceSSH(id, command, term string, height, width int, rw io.ReadWriter)
 		}
 	}
 
+	if agent != nil {
+		go forwardAgent(conn.HandleChannelOpen("auth-agent@openssh.com"), agent)
+
+		ok, err := session.SendRequest("auth-agent-req@openssh.com", true, nil)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("instance refused agent forwarding")
+		}
+	}
+
 	code := 0
 	// Start remote shell
 	if command != "" {
@@ -128,6 +114,30 @@ func InstanceSSH(id, command, term string, height, width int, rw io.ReadWriter)
 	return err
 }
 
+// forwardAgent connects each agent channel the instance opens to a new agent connection
+func forwardAgent(chans <-chan ssh.NewChannel, agent func() io.ReadWriteCloser) {
+	for nc := range chans {
+		ch, reqs, err := nc.Accept()
+		if err != nil {
+			continue
+		}
+
+		go ssh.DiscardRequests(reqs)
+
+		conn := agent()
+
+		go func() {
+			io.Copy(conn, ch)
+			conn.Close()
+		}()
+
+		go func() {
+			io.Copy(ch, conn)
+			ch.Close()
+		}()
+	}
+}
+
 func exitCode(err error) int {
 	if ee, ok := err.(*ssh.ExitError); ok {
 		return ee.Waitmsg.ExitStatus()
@@ -139,3 +149,91 @@ func exitCode(err error) int {
 
 	return 0
 }
+
+// InstanceProcesses lists every container running on an instance, including
+// those of other apps and of the rack itself, by asking its docker daemon directly
+func InstanceProcesses(id string) (Processes, error) {
+	res, err := EC2().DescribeInstances(&ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			&ec2.Filter{Name: aws.String("instance-id"), Values: []*string{aws.String(id)}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.Reservations) == 0 || len(res.Reservations[0].Instances) == 0 {
+		return nil, fmt.Errorf("no such instance: %s", id)
+	}
+
+	instance := res.Reservations[0].Instances[0]
+
+	if instance.PrivateIpAddress == nil {
+		return nil, fmt.Errorf("instance is not running: %s", id)
+	}
+
+	host := *instance.PrivateIpAddress
+	if os.Getenv("DEVELOPMENT") == "true" {
+		host = *instance.PublicIpAddress
+	}
+
+	d, err := (&Process{Host: host}).Docker()
+	if err != nil {
+		return nil, err
+	}
+
+	containers, err := d.ListContainers(docker.ListContainersOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	ps := Processes{}
+
+	for _, c := range containers {
+		p := &Process{
+			Id:          c.ID[0:12],
+			Command:     c.Command,
+			Host:        host,
+			Image:       c.Image,
+			Ports:       []string{},
+			Started:     time.Unix(c.Created, 0),
+			containerId: c.ID,
+		}
+
+		// containers that are not part of an app are named after the container itself
+		if len(c.Names) > 0 {
+			p.Name = strings.TrimPrefix(c.Names[0], "/")
+		}
+
+		for _, port := range c.Ports {
+			p.Ports = append(p.Ports, fmt.Sprintf("%d:%d", port.PublicPort, port.PrivatePort))
+		}
+
+		ci, err := d.InspectContainer(c.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		env := map[string]string{}
+
+		for _, e := range ci.Config.Env {
+			parts := strings.SplitN(e, "=", 2)
+
+			if len(parts) == 2 {
+				env[parts[0]] = parts[1]
+			}
+		}
+
+		if env["APP"] != "" && env["PROCESS"] != "" {
+			p.App = env["APP"]
+			p.Name = env["PROCESS"]
+			p.Release = env["RELEASE"]
+		}
+
+		ps = append(ps, p)
+	}
+
+	sort.Sort(ps)
+
+	return ps, nil
+}
@@ -0,0 +1,201 @@
+package models
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/convox/rack/api/structs"
+)
+
+// EnvironmentLinkTypes are the services that are linked to an app by adding their url to its environment
+var EnvironmentLinkTypes = map[string]bool{
+	"memcached": true,
+	"mysql":     true,
+	"postgres":  true,
+	"redis":     true,
+	"s3":        true,
+	"sns":       true,
+	"sqs":       true,
+}
+
+// LinkEnvironmentName is the environment variable that holds the url of a linked service, e.g. MAINDB_URL
+func LinkEnvironmentName(service string) string {
+	return fmt.Sprintf("%s_URL", strings.ToUpper(strings.Replace(service, "-", "_", -1)))
+}
+
+// LinkEnvironment adds the url of a service to the environment of an app and returns the
+// release that carries it
+func LinkEnvironment(app string, s *structs.Service, user string) (string, error) {
+	url := s.Exports["URL"]
+
+	if url == "" {
+		return "", fmt.Errorf("service %s has no url to link", s.Name)
+	}
+
+	env, err := GetEnvironment(app)
+	if err != nil {
+		return "", err
+	}
+
+	name := LinkEnvironmentName(s.Name)
+
+	if env[name] == url {
+		return "", fmt.Errorf("Service %s is already linked to app %s", s.Name, app)
+	}
+
+	env[name] = url
+
+	release, err := PutEnvironment(app, env, user)
+	if err != nil {
+		return "", err
+	}
+
+	link := structs.ServiceLink{
+		App:      app,
+		Variable: name,
+		Release:  release,
+		User:     user,
+		Created:  time.Now().UTC(),
+	}
+
+	if err := putServiceLink(s.Name, link); err != nil {
+		return release, err
+	}
+
+	NotifySuccess("service:link", map[string]string{"app": app, "service": s.Name, "release": release})
+
+	return release, nil
+}
+
+// UnlinkEnvironment removes the url of a service from the environment of an app and returns
+// the release that no longer carries it
+func UnlinkEnvironment(app string, s *structs.Service, user string) (string, error) {
+	env, err := GetEnvironment(app)
+	if err != nil {
+		return "", err
+	}
+
+	name := LinkEnvironmentName(s.Name)
+
+	if _, ok := env[name]; !ok {
+		return "", fmt.Errorf("Service %s is not linked to app %s", s.Name, app)
+	}
+
+	delete(env, name)
+
+	release, err := PutEnvironment(app, env, user)
+	if err != nil {
+		return "", err
+	}
+
+	if err := deleteServiceLink(s.Name, app); err != nil {
+		return release, err
+	}
+
+	NotifySuccess("service:unlink", map[string]string{"app": app, "service": s.Name, "release": release})
+
+	return release, nil
+}
+
+// ListServiceLinks returns the apps that have the url of a service in their environment.
+// Racks installed before links were tracked have no links to list.
+func ListServiceLinks(service string) (structs.ServiceLinks, error) {
+	table := os.Getenv("DYNAMO_LINKS")
+
+	links := structs.ServiceLinks{}
+
+	if table == "" {
+		return links, nil
+	}
+
+	req := &dynamodb.QueryInput{
+		KeyConditionExpression: aws.String("#service = :service"),
+		ExpressionAttributeNames: map[string]*string{
+			"#service": aws.String("service"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":service": &dynamodb.AttributeValue{S: aws.String(service)},
+		},
+		TableName: aws.String(table),
+	}
+
+	err := DynamoDB().QueryPages(req, func(res *dynamodb.QueryOutput, last bool) bool {
+		for _, item := range res.Items {
+			created, _ := time.Parse(auditTimeFormat, coalesce(item["created"], ""))
+
+			links = append(links, structs.ServiceLink{
+				App:      coalesce(item["app"], ""),
+				Variable: coalesce(item["variable"], ""),
+				Release:  coalesce(item["release"], ""),
+				User:     coalesce(item["user"], ""),
+				Created:  created,
+			})
+		}
+
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Sort(serviceLinksByApp(links))
+
+	return links, nil
+}
+
+type serviceLinksByApp structs.ServiceLinks
+
+func (ls serviceLinksByApp) Len() int           { return len(ls) }
+func (ls serviceLinksByApp) Less(i, j int) bool { return ls[i].App < ls[j].App }
+func (ls serviceLinksByApp) Swap(i, j int)      { ls[i], ls[j] = ls[j], ls[i] }
+
+func putServiceLink(service string, link structs.ServiceLink) error {
+	table := os.Getenv("DYNAMO_LINKS")
+
+	if table == "" {
+		return nil
+	}
+
+	item := map[string]*dynamodb.AttributeValue{
+		"service":  &dynamodb.AttributeValue{S: aws.String(service)},
+		"app":      &dynamodb.AttributeValue{S: aws.String(link.App)},
+		"variable": &dynamodb.AttributeValue{S: aws.String(link.Variable)},
+		"release":  &dynamodb.AttributeValue{S: aws.String(link.Release)},
+		"created":  &dynamodb.AttributeValue{S: aws.String(link.Created.Format(auditTimeFormat))},
+	}
+
+	// dynamodb does not accept empty strings
+	if link.User != "" {
+		item["user"] = &dynamodb.AttributeValue{S: aws.String(link.User)}
+	}
+
+	_, err := DynamoDB().PutItem(&dynamodb.PutItemInput{
+		Item:      item,
+		TableName: aws.String(table),
+	})
+
+	return err
+}
+
+func deleteServiceLink(service, app string) error {
+	table := os.Getenv("DYNAMO_LINKS")
+
+	if table == "" {
+		return nil
+	}
+
+	_, err := DynamoDB().DeleteItem(&dynamodb.DeleteItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"service": &dynamodb.AttributeValue{S: aws.String(service)},
+			"app":     &dynamodb.AttributeValue{S: aws.String(app)},
+		},
+		TableName: aws.String(table),
+	})
+
+	return err
+}
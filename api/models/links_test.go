@@ -0,0 +1,13 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/convox/rack/api/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkEnvironmentName(t *testing.T) {
+	assert.Equal(t, "MAINDB_URL", models.LinkEnvironmentName("maindb"))
+	assert.Equal(t, "MAIN_DB_URL", models.LinkEnvironmentName("main-db"))
+}
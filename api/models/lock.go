@@ -0,0 +1,74 @@
+package models
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// AcquireLock takes or renews the named lock for owner until ttl has passed.
+// Returns false if another owner holds an unexpired lock.
+func AcquireLock(name, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+
+	_, err := DynamoDB().PutItem(&dynamodb.PutItemInput{
+		ConditionExpression: aws.String("attribute_not_exists(#name) OR #owner = :owner OR #expires < :now"),
+		ExpressionAttributeNames: map[string]*string{
+			"#expires": aws.String("expires"),
+			"#name":    aws.String("name"),
+			"#owner":   aws.String("owner"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":now":   &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(now.Unix(), 10))},
+			":owner": &dynamodb.AttributeValue{S: aws.String(owner)},
+		},
+		Item: map[string]*dynamodb.AttributeValue{
+			"name":    &dynamodb.AttributeValue{S: aws.String(name)},
+			"owner":   &dynamodb.AttributeValue{S: aws.String(owner)},
+			"expires": &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(now.Add(ttl).Unix(), 10))},
+		},
+		TableName: aws.String(locksTable()),
+	})
+	if awserrCode(err) == "ConditionalCheckFailedException" {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ReleaseLock gives up the named lock if it is still held by owner
+func ReleaseLock(name, owner string) error {
+	_, err := DynamoDB().DeleteItem(&dynamodb.DeleteItemInput{
+		ConditionExpression: aws.String("#owner = :owner"),
+		ExpressionAttributeNames: map[string]*string{
+			"#owner": aws.String("owner"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":owner": &dynamodb.AttributeValue{S: aws.String(owner)},
+		},
+		Key: map[string]*dynamodb.AttributeValue{
+			"name": &dynamodb.AttributeValue{S: aws.String(name)},
+		},
+		TableName: aws.String(locksTable()),
+	})
+	if awserrCode(err) == "ConditionalCheckFailedException" {
+		return nil
+	}
+
+	return err
+}
+
+func locksTable() string {
+	if table := os.Getenv("DYNAMO_LOCKS"); table != "" {
+		return table
+	}
+
+	return fmt.Sprintf("%s-locks", os.Getenv("RACK"))
+}
@@ -0,0 +1,109 @@
+package models_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/convox/rack/api/awsutil"
+	"github.com/convox/rack/api/models"
+	"github.com/convox/rack/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAcquireLock(t *testing.T) {
+	aws := test.StubAws(
+		test.AcquireLockCycle("monitor", "host-a", false),
+		test.AcquireLockCycle("monitor", "host-a", false),
+		test.AcquireLockCycle("monitor", "host-b", true),
+	)
+	defer aws.Close()
+
+	defer os.Setenv("DYNAMO_LOCKS", os.Getenv("DYNAMO_LOCKS"))
+	os.Setenv("DYNAMO_LOCKS", "convox-locks")
+
+	// acquire
+	ok, err := models.AcquireLock("monitor", "host-a", 30*time.Second)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// renew
+	ok, err = models.AcquireLock("monitor", "host-a", 30*time.Second)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// held by another owner
+	ok, err = models.AcquireLock("monitor", "host-b", 30*time.Second)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestAcquireLockExpired(t *testing.T) {
+	now := time.Now().Unix()
+
+	// the lock is written to expire ttl from now and can be taken over once an earlier expiry has passed
+	aws := test.StubAws(
+		test.AcquireLockCycle("monitor", "host-b", true),
+		awsutil.Cycle{
+			Request: awsutil.Request{
+				RequestURI: "/",
+				Operation:  "DynamoDB_20120810.PutItem",
+				Body:       fmt.Sprintf(`/OR #expires < :now",.*":now":\{"N":"(%d|%d)"\}.*"Item":\{"expires":\{"N":"(%d|%d)"\},"name":\{"S":"monitor"\},"owner":\{"S":"host-b"\}\}/`, now, now+1, now+30, now+31),
+			},
+			Response: awsutil.Response{
+				StatusCode: 200,
+				Body:       `{}`,
+			},
+		},
+	)
+	defer aws.Close()
+
+	defer os.Setenv("DYNAMO_LOCKS", os.Getenv("DYNAMO_LOCKS"))
+	os.Setenv("DYNAMO_LOCKS", "convox-locks")
+
+	ok, err := models.AcquireLock("monitor", "host-b", 30*time.Second)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = models.AcquireLock("monitor", "host-b", 30*time.Second)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestAcquireLockError(t *testing.T) {
+	aws := test.StubAws(
+		awsutil.Cycle{
+			Request: awsutil.Request{
+				RequestURI: "/",
+				Operation:  "DynamoDB_20120810.PutItem",
+				Body:       "ignore",
+			},
+			Response: awsutil.Response{
+				StatusCode: 400,
+				Body:       `{"__type":"com.amazonaws.dynamodb.v20120810#ResourceNotFoundException","message":"Requested resource not found"}`,
+			},
+		},
+	)
+	defer aws.Close()
+
+	ok, err := models.AcquireLock("monitor", "host-a", 30*time.Second)
+	assert.EqualError(t, err, "ResourceNotFoundException: Requested resource not found\n\tstatus code: 400, request id: ")
+	assert.False(t, ok)
+}
+
+func TestReleaseLock(t *testing.T) {
+	aws := test.StubAws(
+		test.ReleaseLockCycle("monitor", "host-a", false),
+		test.ReleaseLockCycle("monitor", "host-a", true),
+	)
+	defer aws.Close()
+
+	defer os.Setenv("DYNAMO_LOCKS", os.Getenv("DYNAMO_LOCKS"))
+	os.Setenv("DYNAMO_LOCKS", "convox-locks")
+
+	assert.NoError(t, models.ReleaseLock("monitor", "host-a"))
+
+	// a lock already taken over by another owner is left alone
+	assert.NoError(t, models.ReleaseLock("monitor", "host-a"))
+}
@@ -0,0 +1,234 @@
+package models
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/convox/rack/manifest"
+)
+
+// MaxMetricPoints is the most datapoints returned for a metric over any period
+const MaxMetricPoints = 30
+
+// Metric is a series of measurements of a process of an app
+type Metric struct {
+	Process string       `json:"process"`
+	Name    string       `json:"name"`
+	Unit    string       `json:"unit"`
+	Points  MetricPoints `json:"points"`
+}
+
+// MetricPoint is a single measurement of a metric
+type MetricPoint struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// MetricPoints are sorted with the oldest measurement first
+type MetricPoints []MetricPoint
+
+type Metrics []Metric
+
+func (ps MetricPoints) Len() int           { return len(ps) }
+func (ps MetricPoints) Less(i, j int) bool { return ps[i].Time.Before(ps[j].Time) }
+func (ps MetricPoints) Swap(i, j int)      { ps[i], ps[j] = ps[j], ps[i] }
+
+// metricQuery is a CloudWatch metric reported for a process
+type metricQuery struct {
+	namespace string
+	name      string
+	statistic string
+	dimension string
+	value     string
+}
+
+// AppMetrics returns the cpu, memory, request count, latency and 5xx rate of each process of
+// an app over period, or of a single process when process is set. Request metrics are only
+// available for processes behind a load balancer.
+func AppMetrics(app, process string, period time.Duration) (Metrics, error) {
+	a, err := GetApp(app)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.Release == "" {
+		return Metrics{}, nil
+	}
+
+	r, err := GetRelease(app, a.Release)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := manifest.Load([]byte(r.Manifest))
+	if err != nil {
+		return nil, err
+	}
+
+	resources, err := a.Resources()
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+
+	for name := range m.Services {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	if process != "" {
+		if _, ok := m.Services[process]; !ok {
+			return nil, fmt.Errorf("no such process: %s", process)
+		}
+
+		names = []string{process}
+	}
+
+	end := time.Now().UTC()
+	start := end.Add(-period)
+	step := MetricStep(period)
+
+	metrics := Metrics{}
+
+	for _, name := range names {
+		if svc, ok := resources[fmt.Sprintf("Service%s", UpperName(name))]; ok {
+			// the physical id of a service is its arn, metrics are reported by its name
+			parts := strings.Split(svc.Id, "/")
+			service := parts[len(parts)-1]
+
+			for _, mq := range []struct {
+				name   string
+				metric string
+			}{{"cpu", "CPUUtilization"}, {"memory", "MemoryUtilization"}} {
+				points, err := metricPoints(metricQuery{"AWS/ECS", mq.metric, "Average", "ServiceName", service}, start, end, step)
+				if err != nil {
+					return nil, err
+				}
+
+				metrics = append(metrics, Metric{Process: name, Name: mq.name, Unit: "percent", Points: points})
+			}
+		}
+
+		bn := m.BalancerResourceName(name)
+		if bn == "" {
+			continue
+		}
+
+		balancer, ok := resources[bn]
+		if !ok {
+			continue
+		}
+
+		requests, err := metricPoints(metricQuery{"AWS/ELB", "RequestCount", "Sum", "LoadBalancerName", balancer.Id}, start, end, step)
+		if err != nil {
+			return nil, err
+		}
+
+		latency, err := metricPoints(metricQuery{"AWS/ELB", "Latency", "Average", "LoadBalancerName", balancer.Id}, start, end, step)
+		if err != nil {
+			return nil, err
+		}
+
+		failures, err := metricPoints(metricQuery{"AWS/ELB", "HTTPCode_Backend_5XX", "Sum", "LoadBalancerName", balancer.Id}, start, end, step)
+		if err != nil {
+			return nil, err
+		}
+
+		// latency is reported in seconds
+		for i := range latency {
+			latency[i].Value *= 1000
+		}
+
+		metrics = append(metrics,
+			Metric{Process: name, Name: "requests", Unit: "count", Points: requests},
+			Metric{Process: name, Name: "latency", Unit: "milliseconds", Points: latency},
+			Metric{Process: name, Name: "5xx", Unit: "percent", Points: ErrorRate(requests, failures)},
+		)
+	}
+
+	return metrics, nil
+}
+
+// MetricStep returns the interval between datapoints that gives at most MaxMetricPoints
+// datapoints over period, in whole minutes as CloudWatch requires
+func MetricStep(period time.Duration) time.Duration {
+	step := period / MaxMetricPoints
+
+	if step < time.Minute {
+		return time.Minute
+	}
+
+	return (step + time.Minute - 1) / time.Minute * time.Minute
+}
+
+// ErrorRate returns the percentage of requests that were errors for each point in time.
+// Times without requests are left out.
+func ErrorRate(requests, failures MetricPoints) MetricPoints {
+	counts := map[time.Time]float64{}
+
+	for _, e := range failures {
+		counts[e.Time] = e.Value
+	}
+
+	rate := MetricPoints{}
+
+	for _, r := range requests {
+		if r.Value == 0 {
+			continue
+		}
+
+		rate = append(rate, MetricPoint{Time: r.Time, Value: counts[r.Time] * 100 / r.Value})
+	}
+
+	return rate
+}
+
+func metricPoints(q metricQuery, start, end time.Time, step time.Duration) (MetricPoints, error) {
+	dimensions := []*cloudwatch.Dimension{
+		{Name: aws.String(q.dimension), Value: aws.String(q.value)},
+	}
+
+	// ecs service metrics are scoped to the cluster
+	if q.namespace == "AWS/ECS" {
+		dimensions = append(dimensions, &cloudwatch.Dimension{Name: aws.String("ClusterName"), Value: aws.String(os.Getenv("CLUSTER"))})
+	}
+
+	res, err := CloudWatch().GetMetricStatistics(&cloudwatch.GetMetricStatisticsInput{
+		Dimensions: dimensions,
+		EndTime:    aws.Time(end),
+		MetricName: aws.String(q.name),
+		Namespace:  aws.String(q.namespace),
+		Period:     aws.Int64(int64(step / time.Second)),
+		StartTime:  aws.Time(start),
+		Statistics: []*string{aws.String(q.statistic)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	points := MetricPoints{}
+
+	for _, d := range res.Datapoints {
+		value := aws.Float64Value(d.Average)
+
+		switch q.statistic {
+		case "Minimum":
+			value = aws.Float64Value(d.Minimum)
+		case "Sum":
+			value = aws.Float64Value(d.Sum)
+		}
+
+		points = append(points, MetricPoint{Time: aws.TimeValue(d.Timestamp).UTC(), Value: value})
+	}
+
+	sort.Sort(points)
+
+	return points, nil
+}
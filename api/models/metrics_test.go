@@ -0,0 +1,26 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricStep(t *testing.T) {
+	assert.Equal(t, 1*time.Minute, MetricStep(10*time.Minute))
+	assert.Equal(t, 2*time.Minute, MetricStep(1*time.Hour))
+	assert.Equal(t, 5*time.Minute, MetricStep(2*time.Hour+10*time.Minute))
+	assert.Equal(t, 48*time.Minute, MetricStep(24*time.Hour))
+}
+
+func TestErrorRate(t *testing.T) {
+	t0 := time.Date(2016, 10, 1, 12, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Minute)
+	t2 := t1.Add(time.Minute)
+
+	requests := MetricPoints{{Time: t0, Value: 200}, {Time: t1, Value: 0}, {Time: t2, Value: 50}}
+	failures := MetricPoints{{Time: t0, Value: 5}}
+
+	assert.Equal(t, MetricPoints{{Time: t0, Value: 2.5}, {Time: t2, Value: 0}}, ErrorRate(requests, failures))
+}
@@ -0,0 +1,289 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// monitorHistory is how many checks each monitor keeps
+const monitorHistory = 500
+
+// MinimumMonitorInterval is the shortest time allowed between two checks of a monitor
+const MinimumMonitorInterval = 10
+
+// Monitor periodically requests a URL from the rack and alerts when it stops responding
+type Monitor struct {
+	Id  string `json:"id"`
+	App string `json:"app"`
+	URL string `json:"url"`
+
+	// Interval is the number of seconds between checks
+	Interval int `json:"interval"`
+
+	// MaxLatency is the number of milliseconds after which a response counts as a failure, 0 for no limit
+	MaxLatency int `json:"max-latency"`
+
+	// Threshold is the number of consecutive failed checks that mark the monitor down
+	Threshold int `json:"threshold"`
+
+	Status   string    `json:"status"`
+	Failures int       `json:"failures"`
+	Created  time.Time `json:"created"`
+
+	Checks MonitorChecks `json:"checks"`
+}
+
+// MonitorCheck is the result of a single request made by a monitor
+type MonitorCheck struct {
+	Time    time.Time `json:"time"`
+	Up      bool      `json:"up"`
+	Code    int       `json:"code"`
+	Latency int       `json:"latency"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// MonitorChecks are sorted with the oldest check first
+type MonitorChecks []MonitorCheck
+
+type Monitors []Monitor
+
+func (ms Monitors) Len() int           { return len(ms) }
+func (ms Monitors) Less(i, j int) bool { return ms[i].Created.Before(ms[j].Created) }
+func (ms Monitors) Swap(i, j int)      { ms[i], ms[j] = ms[j], ms[i] }
+
+// NewMonitor returns a monitor for url on app that has not been checked yet
+func NewMonitor(app, url string) *Monitor {
+	return &Monitor{
+		Id:        generateId("M", 10),
+		App:       app,
+		URL:       url,
+		Interval:  30,
+		Threshold: 3,
+		Status:    "unknown",
+		Created:   time.Now().UTC(),
+		Checks:    MonitorChecks{},
+	}
+}
+
+// ListMonitors returns the monitors of an app, oldest first
+func ListMonitors(app string) (Monitors, error) {
+	a, err := GetApp(app)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := s3Keys(a.settingsBucket(), "monitors/")
+	if err != nil {
+		return nil, err
+	}
+
+	monitors := Monitors{}
+
+	for _, key := range keys {
+		m, err := getMonitor(a.settingsBucket(), key)
+		if err != nil {
+			return nil, err
+		}
+
+		monitors = append(monitors, *m)
+	}
+
+	sort.Sort(monitors)
+
+	return monitors, nil
+}
+
+// GetMonitor returns a single monitor of an app
+func GetMonitor(app, id string) (*Monitor, error) {
+	a, err := GetApp(app)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := getMonitor(a.settingsBucket(), monitorKey(id))
+	if awserrCode(err) == "NoSuchKey" {
+		return nil, fmt.Errorf("no such monitor: %s", id)
+	}
+
+	return m, err
+}
+
+func getMonitor(bucket, key string) (*Monitor, error) {
+	data, err := s3Get(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var m Monitor
+
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// Validate returns an error if the monitor can not be checked
+func (m *Monitor) Validate() error {
+	if !strings.HasPrefix(m.URL, "http://") && !strings.HasPrefix(m.URL, "https://") {
+		return fmt.Errorf("url must start with http:// or https://")
+	}
+
+	if m.Interval < MinimumMonitorInterval {
+		return fmt.Errorf("interval must be at least %ds", MinimumMonitorInterval)
+	}
+
+	if m.Threshold < 1 {
+		return fmt.Errorf("threshold must be at least 1")
+	}
+
+	if m.MaxLatency < 0 {
+		return fmt.Errorf("max latency can not be negative")
+	}
+
+	return nil
+}
+
+// Save stores the monitor in the settings bucket of its app
+func (m *Monitor) Save() error {
+	a, err := GetApp(m.App)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	return S3Put(a.settingsBucket(), monitorKey(m.Id), data, false)
+}
+
+// Delete removes the monitor and its history
+func (m *Monitor) Delete() error {
+	a, err := GetApp(m.App)
+	if err != nil {
+		return err
+	}
+
+	return s3Delete(a.settingsBucket(), monitorKey(m.Id))
+}
+
+// Due returns true if the monitor should be checked at now
+func (m *Monitor) Due(now time.Time) bool {
+	if len(m.Checks) == 0 {
+		return true
+	}
+
+	last := m.Checks[len(m.Checks)-1].Time
+
+	return !now.Before(last.Add(time.Duration(m.Interval) * time.Second))
+}
+
+// Check requests the monitored URL once
+func (m *Monitor) Check(client *http.Client) MonitorCheck {
+	check := MonitorCheck{Time: time.Now().UTC()}
+
+	res, err := client.Get(m.URL)
+
+	check.Latency = int(time.Since(check.Time) / time.Millisecond)
+
+	if err != nil {
+		check.Error = err.Error()
+		return check
+	}
+
+	res.Body.Close()
+
+	check.Code = res.StatusCode
+
+	switch {
+	case res.StatusCode >= 400:
+		check.Error = fmt.Sprintf("response status %d", res.StatusCode)
+	case m.MaxLatency > 0 && check.Latency > m.MaxLatency:
+		check.Error = fmt.Sprintf("response took %dms", check.Latency)
+	default:
+		check.Up = true
+	}
+
+	return check
+}
+
+// Record adds a check to the history of the monitor and returns the new status
+// of the monitor if the check changed it between up and down
+func (m *Monitor) Record(check MonitorCheck) string {
+	m.Checks = append(m.Checks, check)
+
+	if len(m.Checks) > monitorHistory {
+		m.Checks = m.Checks[len(m.Checks)-monitorHistory:]
+	}
+
+	if check.Up {
+		m.Failures = 0
+	} else {
+		m.Failures++
+	}
+
+	status := m.Status
+
+	switch {
+	case check.Up:
+		status = "up"
+	case m.Failures >= m.Threshold:
+		status = "down"
+	}
+
+	// the first successful check is not a recovery worth alerting on
+	changed := status != m.Status && (status == "down" || m.Status == "down")
+
+	m.Status = status
+
+	if changed {
+		return status
+	}
+
+	return ""
+}
+
+// Availability returns the percentage of recorded checks that succeeded, or 100 if there are none
+func (cs MonitorChecks) Availability() float64 {
+	if len(cs) == 0 {
+		return 100
+	}
+
+	up := 0
+
+	for _, c := range cs {
+		if c.Up {
+			up++
+		}
+	}
+
+	return float64(up) * 100 / float64(len(cs))
+}
+
+// AverageLatency returns the average latency in milliseconds of the successful checks
+func (cs MonitorChecks) AverageLatency() int {
+	total, count := 0, 0
+
+	for _, c := range cs {
+		if c.Up {
+			total += c.Latency
+			count++
+		}
+	}
+
+	if count == 0 {
+		return 0
+	}
+
+	return total / count
+}
+
+func monitorKey(id string) string {
+	return fmt.Sprintf("monitors/%s.json", id)
+}
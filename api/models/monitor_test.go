@@ -0,0 +1,63 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMonitorRecord(t *testing.T) {
+	m := NewMonitor("web", "https://example.com/healthz")
+	m.Threshold = 2
+
+	assert.Equal(t, "", m.Record(MonitorCheck{Up: true, Latency: 100}))
+	assert.Equal(t, "up", m.Status)
+
+	assert.Equal(t, "", m.Record(MonitorCheck{Error: "response status 500"}))
+	assert.Equal(t, "up", m.Status)
+
+	assert.Equal(t, "down", m.Record(MonitorCheck{Error: "response status 500"}))
+	assert.Equal(t, "", m.Record(MonitorCheck{Error: "response status 500"}))
+	assert.Equal(t, 3, m.Failures)
+
+	assert.Equal(t, "up", m.Record(MonitorCheck{Up: true, Latency: 300}))
+	assert.Equal(t, 0, m.Failures)
+
+	assert.InDelta(t, 40.0, m.Checks.Availability(), 0.01)
+	assert.Equal(t, 200, m.Checks.AverageLatency())
+
+	for i := 0; i < monitorHistory+5; i++ {
+		m.Record(MonitorCheck{Up: true})
+	}
+
+	assert.Len(t, m.Checks, monitorHistory)
+}
+
+func TestMonitorDue(t *testing.T) {
+	m := NewMonitor("web", "https://example.com/healthz")
+
+	now := time.Date(2016, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.True(t, m.Due(now))
+
+	m.Record(MonitorCheck{Time: now, Up: true})
+
+	assert.False(t, m.Due(now.Add(29*time.Second)))
+	assert.True(t, m.Due(now.Add(30*time.Second)))
+}
+
+func TestMonitorValidate(t *testing.T) {
+	m := NewMonitor("web", "example.com")
+	assert.EqualError(t, m.Validate(), "url must start with http:// or https://")
+
+	m = NewMonitor("web", "https://example.com")
+	assert.Nil(t, m.Validate())
+
+	m.Interval = 5
+	assert.EqualError(t, m.Validate(), "interval must be at least 10s")
+
+	m.Interval = 30
+	m.Threshold = 0
+	assert.EqualError(t, m.Validate(), "threshold must be at least 1")
+}
@@ -0,0 +1,345 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sns"
+	cmodels "github.com/convox/rack/client/models"
+)
+
+// Notification sends the rack events on the notification topic to Slack, a webhook or another
+// SNS topic. Each notification is subscribed to the topic through the /sns endpoint of the rack,
+// which formats the events for the notification and sends them on.
+type Notification struct {
+	Id string `json:"id"`
+
+	// Url is the notification as it was added, such as slack://hooks.slack.com/services/...,
+	// webhook://https://example.org/hook or sns://arn:aws:sns:us-east-1:123456789012:deploys
+	Url string `json:"url"`
+
+	// Type is one of slack, webhook or sns and Target is the url or topic arn events are sent to
+	Type   string `json:"type"`
+	Target string `json:"target"`
+
+	// Events limits the notification to actions such as build:create or to every action of a
+	// kind such as release. Every event is sent when it is empty.
+	Events []string `json:"events,omitempty"`
+
+	Created time.Time `json:"created"`
+}
+
+type Notifications []Notification
+
+func (ns Notifications) Len() int           { return len(ns) }
+func (ns Notifications) Less(i, j int) bool { return ns[i].Created.Before(ns[j].Created) }
+func (ns Notifications) Swap(i, j int)      { ns[i], ns[j] = ns[j], ns[i] }
+
+// AddNotification subscribes a slack://, webhook:// or sns:// url to the rack events, optionally
+// limited to some events
+func AddNotification(u string, events []string) (*Notification, error) {
+	typ, target, err := parseNotificationUrl(u)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, e := range events {
+		e = strings.TrimSpace(e)
+		events[i] = e
+
+		if e == "" || strings.HasPrefix(e, ":") || strings.HasSuffix(e, ":") {
+			return nil, fmt.Errorf("invalid event: %s", e)
+		}
+	}
+
+	n := &Notification{
+		Id:      generateId("N", 10),
+		Url:     u,
+		Type:    typ,
+		Target:  target,
+		Events:  events,
+		Created: time.Now().UTC(),
+	}
+
+	if err := n.save(); err != nil {
+		return nil, err
+	}
+
+	// the subscription is confirmed by the /sns endpoint once SNS reaches it
+	_, err = SNS().Subscribe(&sns.SubscribeInput{
+		Protocol: aws.String("http"),
+		Endpoint: aws.String(n.endpoint()),
+		TopicArn: aws.String(NotificationTopic),
+	})
+	if err != nil {
+		s3Delete(os.Getenv("SETTINGS_BUCKET"), notificationKey(n.Id))
+		return nil, err
+	}
+
+	NotifySuccess("notification:add", map[string]string{"id": n.Id, "type": n.Type})
+
+	return n, nil
+}
+
+// ListNotifications returns the notifications of the rack, oldest first
+func ListNotifications() (Notifications, error) {
+	keys, err := s3Keys(os.Getenv("SETTINGS_BUCKET"), "notifications/")
+	if err != nil {
+		return nil, err
+	}
+
+	notifications := Notifications{}
+
+	for _, key := range keys {
+		n, err := getNotification(key)
+		if err != nil {
+			return nil, err
+		}
+
+		notifications = append(notifications, *n)
+	}
+
+	sort.Sort(notifications)
+
+	return notifications, nil
+}
+
+// GetNotification returns a single notification of the rack
+func GetNotification(id string) (*Notification, error) {
+	n, err := getNotification(notificationKey(id))
+	if awserrCode(err) == "NoSuchKey" {
+		return nil, fmt.Errorf("no such notification: %s", id)
+	}
+
+	return n, err
+}
+
+// RemoveNotification unsubscribes a notification from the rack events
+func RemoveNotification(id string) error {
+	n, err := GetNotification(id)
+	if err != nil {
+		return err
+	}
+
+	var token *string
+
+	for {
+		res, err := SNS().ListSubscriptionsByTopic(&sns.ListSubscriptionsByTopicInput{
+			NextToken: token,
+			TopicArn:  aws.String(NotificationTopic),
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, s := range res.Subscriptions {
+			// subscriptions that were never confirmed can not be removed and lapse on their own
+			if *s.Endpoint != n.endpoint() || !strings.HasPrefix(*s.SubscriptionArn, "arn:") {
+				continue
+			}
+
+			_, err := SNS().Unsubscribe(&sns.UnsubscribeInput{SubscriptionArn: s.SubscriptionArn})
+			if err != nil {
+				return err
+			}
+		}
+
+		if res.NextToken == nil {
+			break
+		}
+
+		token = res.NextToken
+	}
+
+	if err := s3Delete(os.Getenv("SETTINGS_BUCKET"), notificationKey(n.Id)); err != nil {
+		return err
+	}
+
+	NotifySuccess("notification:remove", map[string]string{"id": n.Id, "type": n.Type})
+
+	return nil
+}
+
+// Matches returns true if the notification should be sent an event
+func (n *Notification) Matches(e cmodels.NotifyEvent) bool {
+	if len(n.Events) == 0 {
+		return true
+	}
+
+	for _, ev := range n.Events {
+		if e.Action == ev || strings.HasPrefix(e.Action, ev+":") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Deliver sends an event to the target of the notification
+func (n *Notification) Deliver(e cmodels.NotifyEvent) error {
+	switch n.Type {
+	case "sns":
+		message, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+
+		_, err = SNS().Publish(&sns.PublishInput{
+			Message:   aws.String(string(message)),
+			Subject:   aws.String(e.Action),
+			TargetArn: aws.String(n.Target),
+		})
+
+		return err
+	case "slack":
+		return notificationPost(n.Target, map[string]string{"text": describeEvent(e)})
+	default:
+		return notificationPost(n.Target, e)
+	}
+}
+
+func (n *Notification) endpoint() string {
+	return fmt.Sprintf("http://%s/sns?notification=%s", os.Getenv("NOTIFICATION_HOST"), n.Id)
+}
+
+func (n *Notification) save() error {
+	data, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+
+	return S3Put(os.Getenv("SETTINGS_BUCKET"), notificationKey(n.Id), data, false)
+}
+
+func getNotification(key string) (*Notification, error) {
+	data, err := s3Get(os.Getenv("SETTINGS_BUCKET"), key)
+	if err != nil {
+		return nil, err
+	}
+
+	var n Notification
+
+	if err := json.Unmarshal(data, &n); err != nil {
+		return nil, err
+	}
+
+	return &n, nil
+}
+
+func notificationKey(id string) string {
+	return fmt.Sprintf("notifications/%s.json", id)
+}
+
+func notificationPost(target string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	res, err := webhookClient.Post(target, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("%s responded with %s", target, res.Status)
+	}
+
+	return nil
+}
+
+// parseNotificationUrl returns the type and target of a notification url
+func parseNotificationUrl(u string) (string, string, error) {
+	parts := strings.SplitN(u, "://", 2)
+
+	if len(parts) != 2 || parts[1] == "" {
+		return "", "", fmt.Errorf("notification must be a slack://, webhook:// or sns:// url")
+	}
+
+	typ, target := parts[0], parts[1]
+
+	switch typ {
+	case "slack":
+		target = "https://" + target
+	case "webhook":
+		if !strings.HasPrefix(target, "http://") && !strings.HasPrefix(target, "https://") {
+			target = "https://" + target
+		}
+	case "sns":
+		if !strings.HasPrefix(target, "arn:aws:sns:") {
+			return "", "", fmt.Errorf("sns notification must be a topic arn: %s", target)
+		}
+
+		return typ, target, nil
+	default:
+		return "", "", fmt.Errorf("notification must be a slack://, webhook:// or sns:// url")
+	}
+
+	if pu, err := url.Parse(target); err != nil || pu.Host == "" {
+		return "", "", fmt.Errorf("invalid %s url: %s", typ, target)
+	}
+
+	return typ, target, nil
+}
+
+// describeEvent returns a line of text about an event for chat
+func describeEvent(e cmodels.NotifyEvent) string {
+	d := e.Data
+	text := ""
+
+	switch {
+	case e.Action == "build:create" && e.Status == "success":
+		text = fmt.Sprintf("build %s of %s started", d["id"], d["app"])
+	case e.Action == "build:create":
+		text = fmt.Sprintf("build %s of %s failed", d["id"], d["app"])
+	case e.Action == "release:promote" && e.Status == "success":
+		text = fmt.Sprintf("release %s of %s promoted", d["id"], d["app"])
+	case e.Action == "release:scale" && d["process"] != "":
+		text = fmt.Sprintf("%s of %s scaled to count=%s cpu=%s memory=%s", d["process"], d["app"], d["count"], d["cpu"], d["memory"])
+	case e.Action == "app:update" && e.Status == "error":
+		text = fmt.Sprintf("update of %s failed", d["app"])
+	default:
+		keys := []string{}
+
+		for key := range d {
+			if key != "rack" && key != "message" {
+				keys = append(keys, key)
+			}
+		}
+
+		sort.Strings(keys)
+
+		attrs := []string{}
+
+		for _, key := range keys {
+			attrs = append(attrs, fmt.Sprintf("%s=%s", key, d[key]))
+		}
+
+		status := "succeeded"
+
+		if e.Status == "error" {
+			status = "failed"
+		}
+
+		text = strings.TrimSpace(fmt.Sprintf("%s %s %s", e.Action, status, strings.Join(attrs, " ")))
+	}
+
+	if d["message"] != "" {
+		text = fmt.Sprintf("%s: %s", text, d["message"])
+	}
+
+	if d["rack"] != "" {
+		text = fmt.Sprintf("[%s] %s", d["rack"], text)
+	}
+
+	return text
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"testing"
+
+	cmodels "github.com/convox/rack/client/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNotificationUrl(t *testing.T) {
+	typ, target, err := parseNotificationUrl("slack://hooks.slack.com/services/T000/B000/XXXX")
+	assert.Nil(t, err)
+	assert.Equal(t, "slack", typ)
+	assert.Equal(t, "https://hooks.slack.com/services/T000/B000/XXXX", target)
+
+	typ, target, err = parseNotificationUrl("webhook://http://example.org/hook")
+	assert.Nil(t, err)
+	assert.Equal(t, "webhook", typ)
+	assert.Equal(t, "http://example.org/hook", target)
+
+	_, target, err = parseNotificationUrl("webhook://example.org/hook")
+	assert.Nil(t, err)
+	assert.Equal(t, "https://example.org/hook", target)
+
+	typ, target, err = parseNotificationUrl("sns://arn:aws:sns:us-east-1:123456789012:deploys")
+	assert.Nil(t, err)
+	assert.Equal(t, "sns", typ)
+	assert.Equal(t, "arn:aws:sns:us-east-1:123456789012:deploys", target)
+
+	_, _, err = parseNotificationUrl("sns://deploys")
+	assert.EqualError(t, err, "sns notification must be a topic arn: deploys")
+
+	_, _, err = parseNotificationUrl("https://example.org/hook")
+	assert.EqualError(t, err, "notification must be a slack://, webhook:// or sns:// url")
+}
+
+func TestNotificationMatches(t *testing.T) {
+	n := &Notification{}
+	assert.True(t, n.Matches(cmodels.NotifyEvent{Action: "app:create"}))
+
+	n = &Notification{Events: []string{"build", "release:promote"}}
+	assert.True(t, n.Matches(cmodels.NotifyEvent{Action: "build:create"}))
+	assert.True(t, n.Matches(cmodels.NotifyEvent{Action: "release:promote"}))
+	assert.False(t, n.Matches(cmodels.NotifyEvent{Action: "release:scale"}))
+	assert.False(t, n.Matches(cmodels.NotifyEvent{Action: "builds:create"}))
+}
+
+func TestDescribeEvent(t *testing.T) {
+	assert.Equal(t, "[production] build B123 of web started", describeEvent(cmodels.NotifyEvent{
+		Action: "build:create",
+		Status: "success",
+		Data:   map[string]string{"rack": "production", "app": "web", "id": "B123"},
+	}))
+
+	assert.Equal(t, "[production] build B123 of web failed: exit status 1", describeEvent(cmodels.NotifyEvent{
+		Action: "build:create",
+		Status: "error",
+		Data:   map[string]string{"rack": "production", "app": "web", "id": "B123", "message": "exit status 1"},
+	}))
+
+	assert.Equal(t, "[production] worker of web scaled to count=3 cpu=256 memory=512", describeEvent(cmodels.NotifyEvent{
+		Action: "release:scale",
+		Status: "success",
+		Data:   map[string]string{"rack": "production", "app": "web", "process": "worker", "count": "3", "cpu": "256", "memory": "512"},
+	}))
+
+	assert.Equal(t, "domain:add succeeded app=web domain=example.org", describeEvent(cmodels.NotifyEvent{
+		Action: "domain:add",
+		Status: "success",
+		Data:   map[string]string{"app": "web", "domain": "example.org"},
+	}))
+}
@@ -1,14 +1,16 @@
 package models
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/sns"
-	"github.com/convox/rack/client"
+	cmodels "github.com/convox/rack/client/models"
 	"github.com/ddollar/logger"
 )
 
@@ -36,7 +38,11 @@ func Notify(name, status string, data map[string]string) error {
 	log := logger.New("ns=kernel")
 	data["rack"] = os.Getenv("RACK")
 
-	event := &client.NotifyEvent{
+	if err := RecordRackEvent(name, status, data); err != nil {
+		log.At("Notify").Error(err)
+	}
+
+	event := &cmodels.NotifyEvent{
 		Action:    name,
 		Status:    status,
 		Data:      data,
@@ -65,3 +71,44 @@ func Notify(name, status string, data map[string]string) error {
 
 	return nil
 }
+
+// webhookClient sends notifications that go straight to a single webhook
+var webhookClient = &http.Client{Timeout: 10 * time.Second}
+
+// NotifyWebhook sends a notification to one webhook instead of every subscriber of the
+// notification topic. The body is the same one the topic delivers to webhooks.
+func NotifyWebhook(url, name, status string, data map[string]string) error {
+	if PauseNotifications {
+		return nil
+	}
+
+	log := logger.New("ns=kernel")
+	data["rack"] = os.Getenv("RACK")
+
+	if err := RecordRackEvent(name, status, data); err != nil {
+		log.At("NotifyWebhook").Error(err)
+	}
+
+	event := &cmodels.NotifyEvent{
+		Action:    name,
+		Status:    status,
+		Data:      data,
+		Timestamp: time.Now().UTC(),
+	}
+
+	message, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	res, err := webhookClient.Post(url, "application/json", bytes.NewReader(message))
+	if err != nil {
+		return err
+	}
+
+	res.Body.Close()
+
+	log.At("NotifyWebhook").Log("status=%d", res.StatusCode)
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// ParameterChange is a recorded change to a single stack parameter
+type ParameterChange struct {
+	Name    string    `json:"name"`
+	Old     string    `json:"old"`
+	New     string    `json:"new"`
+	User    string    `json:"user"`
+	Created time.Time `json:"created"`
+}
+
+// ParameterChanges are sorted with the latest change first
+type ParameterChanges []ParameterChange
+
+func (cs ParameterChanges) Len() int           { return len(cs) }
+func (cs ParameterChanges) Less(i, j int) bool { return cs[i].Created.After(cs[j].Created) }
+func (cs ParameterChanges) Swap(i, j int)      { cs[i], cs[j] = cs[j], cs[i] }
+
+// ParameterHistory returns every recorded parameter change of the app, latest first
+func (a *App) ParameterHistory() (ParameterChanges, error) {
+	data, err := s3Get(a.settingsBucket(), a.parameterHistoryKey())
+	if awserrCode(err) == "NoSuchKey" {
+		return ParameterChanges{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var changes ParameterChanges
+
+	if err := json.Unmarshal(data, &changes); err != nil {
+		return nil, err
+	}
+
+	sort.Sort(changes)
+
+	return changes, nil
+}
+
+// RecordParameterChanges adds the parameters in params that differ from the
+// current values to the app's parameter history. The history is only ever appended to.
+func (a *App) RecordParameterChanges(user string, params map[string]string) error {
+	changes, err := a.ParameterHistory()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+
+	keys := []string{}
+
+	for key := range params {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		old := a.Parameters[key]
+		value := params[key]
+
+		if value == old {
+			continue
+		}
+
+		// cloudformation masks NoEcho parameters so do not store their new values either
+		if old == "****" {
+			value = "****"
+		}
+
+		changes = append(changes, ParameterChange{
+			Name:    key,
+			Old:     old,
+			New:     value,
+			User:    user,
+			Created: now,
+		})
+	}
+
+	sort.Sort(changes)
+
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	return S3Put(a.settingsBucket(), a.parameterHistoryKey(), data, false)
+}
+
+func (a *App) parameterHistoryKey() string {
+	return fmt.Sprintf("parameters/%s.json", a.Name)
+}
+
+// settingsBucket returns the settings bucket of an app, or of the rack for the rack itself
+func (a *App) settingsBucket() string {
+	if bucket := a.Outputs["Settings"]; bucket != "" {
+		return bucket
+	}
+
+	return os.Getenv("SETTINGS_BUCKET")
+}
@@ -0,0 +1,499 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Pipeline promotes the builds of an app through a series of racks. Pipelines span racks, the
+// definition and its runs are kept on the rack the pipeline was created on, which also decides
+// whether a stage may start.
+type Pipeline struct {
+	Name    string          `json:"name"`
+	App     string          `json:"app"`
+	Stages  []PipelineStage `json:"stages"`
+	Created time.Time       `json:"created"`
+}
+
+// PipelineStage is a rack a build is promoted on, optionally held until approved
+type PipelineStage struct {
+	Name     string `json:"name"`
+	Rack     string `json:"rack"`
+	Approval bool   `json:"approval"`
+}
+
+type Pipelines []Pipeline
+
+func (ps Pipelines) Len() int           { return len(ps) }
+func (ps Pipelines) Less(i, j int) bool { return ps[i].Name < ps[j].Name }
+func (ps Pipelines) Swap(i, j int)      { ps[i], ps[j] = ps[j], ps[i] }
+
+// PipelineRun tracks one build through the stages of a pipeline
+type PipelineRun struct {
+	Id       string             `json:"id"`
+	Pipeline string             `json:"pipeline"`
+	Build    string             `json:"build"`
+	Status   string             `json:"status"`
+	Stages   []PipelineRunStage `json:"stages"`
+	User     string             `json:"user"`
+	Started  time.Time          `json:"started"`
+	Ended    time.Time          `json:"ended"`
+}
+
+// PipelineRunStage is the build and release a run created on the rack of a stage
+type PipelineRunStage struct {
+	Name     string `json:"name"`
+	Rack     string `json:"rack"`
+	Build    string `json:"build"`
+	Release  string `json:"release"`
+	Status   string `json:"status"`
+	Approver string `json:"approver,omitempty"`
+}
+
+// PipelineRuns are sorted with the latest run first
+type PipelineRuns []PipelineRun
+
+func (rs PipelineRuns) Len() int           { return len(rs) }
+func (rs PipelineRuns) Less(i, j int) bool { return rs[i].Started.After(rs[j].Started) }
+func (rs PipelineRuns) Swap(i, j int)      { rs[i], rs[j] = rs[j], rs[i] }
+
+// ParsePipeline reads stages written as name:rack in promotion order and the comma separated
+// stages that require approval
+func ParsePipeline(name, app, stages, approval string) (*Pipeline, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("must specify a name")
+	}
+
+	p := &Pipeline{Name: name, App: app, Stages: []PipelineStage{}}
+
+	approve := map[string]bool{}
+
+	for _, s := range strings.Split(approval, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			approve[s] = true
+		}
+	}
+
+	seen := map[string]bool{}
+
+	for _, s := range strings.Split(stages, ",") {
+		parts := strings.SplitN(strings.TrimSpace(s), ":", 2)
+
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("stages must be written as name:rack")
+		}
+
+		if seen[parts[0]] {
+			return nil, fmt.Errorf("duplicate stage: %s", parts[0])
+		}
+
+		seen[parts[0]] = true
+
+		p.Stages = append(p.Stages, PipelineStage{Name: parts[0], Rack: parts[1], Approval: approve[parts[0]]})
+	}
+
+	for s := range approve {
+		if !seen[s] {
+			return nil, fmt.Errorf("no such stage: %s", s)
+		}
+	}
+
+	return p, nil
+}
+
+// CreatePipeline saves a new pipeline
+func CreatePipeline(p *Pipeline) error {
+	table, err := pipelinesTable("DYNAMO_PIPELINES")
+	if err != nil {
+		return err
+	}
+
+	p.Created = time.Now().UTC()
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	_, err = DynamoDB().PutItem(&dynamodb.PutItemInput{
+		ConditionExpression: aws.String("attribute_not_exists(#name)"),
+		ExpressionAttributeNames: map[string]*string{
+			"#name": aws.String("name"),
+		},
+		Item: map[string]*dynamodb.AttributeValue{
+			"name": &dynamodb.AttributeValue{S: aws.String(p.Name)},
+			"data": &dynamodb.AttributeValue{S: aws.String(string(data))},
+		},
+		TableName: aws.String(table),
+	})
+	if awserrCode(err) == "ConditionalCheckFailedException" {
+		return fmt.Errorf("pipeline already exists: %s", p.Name)
+	}
+
+	return err
+}
+
+// ListPipelines returns every pipeline of the rack by name
+func ListPipelines() (Pipelines, error) {
+	table, err := pipelinesTable("DYNAMO_PIPELINES")
+	if err != nil {
+		return nil, err
+	}
+
+	pipelines := Pipelines{}
+
+	err = DynamoDB().ScanPages(&dynamodb.ScanInput{
+		ConsistentRead: aws.Bool(true),
+		TableName:      aws.String(table),
+	}, func(res *dynamodb.ScanOutput, last bool) bool {
+		for _, item := range res.Items {
+			var p Pipeline
+
+			if err := json.Unmarshal([]byte(coalesce(item["data"], "{}")), &p); err == nil {
+				pipelines = append(pipelines, p)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Sort(pipelines)
+
+	return pipelines, nil
+}
+
+// GetPipeline returns a single pipeline
+func GetPipeline(name string) (*Pipeline, error) {
+	table, err := pipelinesTable("DYNAMO_PIPELINES")
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := DynamoDB().GetItem(&dynamodb.GetItemInput{
+		ConsistentRead: aws.Bool(true),
+		Key: map[string]*dynamodb.AttributeValue{
+			"name": &dynamodb.AttributeValue{S: aws.String(name)},
+		},
+		TableName: aws.String(table),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Item == nil {
+		return nil, fmt.Errorf("no such pipeline: %s", name)
+	}
+
+	var p Pipeline
+
+	if err := json.Unmarshal([]byte(coalesce(res.Item["data"], "{}")), &p); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// DeletePipeline removes a pipeline and its runs
+func DeletePipeline(name string) error {
+	if _, err := GetPipeline(name); err != nil {
+		return err
+	}
+
+	runs, err := ListPipelineRuns(name)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range runs {
+		_, err := DynamoDB().DeleteItem(&dynamodb.DeleteItemInput{
+			Key: map[string]*dynamodb.AttributeValue{
+				"pipeline": &dynamodb.AttributeValue{S: aws.String(name)},
+				"id":       &dynamodb.AttributeValue{S: aws.String(r.Id)},
+			},
+			TableName: aws.String(os.Getenv("DYNAMO_PIPELINE_RUNS")),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = DynamoDB().DeleteItem(&dynamodb.DeleteItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"name": &dynamodb.AttributeValue{S: aws.String(name)},
+		},
+		TableName: aws.String(os.Getenv("DYNAMO_PIPELINES")),
+	})
+
+	return err
+}
+
+// StartRun begins tracking a build of the first stage through the pipeline
+func (p *Pipeline) StartRun(build, user string) (*PipelineRun, error) {
+	if strings.TrimSpace(build) == "" {
+		return nil, fmt.Errorf("must specify a build")
+	}
+
+	r := &PipelineRun{
+		Id:       generateId("P", 9),
+		Pipeline: p.Name,
+		Build:    build,
+		Status:   "pending",
+		Stages:   []PipelineRunStage{},
+		User:     user,
+		Started:  time.Now().UTC(),
+	}
+
+	for _, s := range p.Stages {
+		r.Stages = append(r.Stages, PipelineRunStage{Name: s.Name, Rack: s.Rack, Status: "pending"})
+	}
+
+	// the build starts on the rack of the first stage
+	r.Stages[0].Build = build
+
+	if err := r.save(""); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// ListPipelineRuns returns the runs of a pipeline, latest first
+func ListPipelineRuns(pipeline string) (PipelineRuns, error) {
+	table, err := pipelinesTable("DYNAMO_PIPELINE_RUNS")
+	if err != nil {
+		return nil, err
+	}
+
+	runs := PipelineRuns{}
+
+	err = DynamoDB().QueryPages(&dynamodb.QueryInput{
+		ConsistentRead:         aws.Bool(true),
+		KeyConditionExpression: aws.String("pipeline = :pipeline"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":pipeline": &dynamodb.AttributeValue{S: aws.String(pipeline)},
+		},
+		TableName: aws.String(table),
+	}, func(res *dynamodb.QueryOutput, last bool) bool {
+		for _, item := range res.Items {
+			var r PipelineRun
+
+			if err := json.Unmarshal([]byte(coalesce(item["data"], "{}")), &r); err == nil {
+				runs = append(runs, r)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Sort(runs)
+
+	return runs, nil
+}
+
+// GetPipelineRun returns a single run of a pipeline
+func GetPipelineRun(pipeline, id string) (*PipelineRun, error) {
+	table, err := pipelinesTable("DYNAMO_PIPELINE_RUNS")
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := DynamoDB().GetItem(&dynamodb.GetItemInput{
+		ConsistentRead: aws.Bool(true),
+		Key: map[string]*dynamodb.AttributeValue{
+			"pipeline": &dynamodb.AttributeValue{S: aws.String(pipeline)},
+			"id":       &dynamodb.AttributeValue{S: aws.String(id)},
+		},
+		TableName: aws.String(table),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Item == nil {
+		return nil, fmt.Errorf("no such run: %s", id)
+	}
+
+	var r PipelineRun
+
+	if err := json.Unmarshal([]byte(coalesce(res.Item["data"], "{}")), &r); err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+// Approve lets the stage a run is waiting at start, on behalf of the user of a user key. The user
+// that started the run can not approve it.
+func (r *PipelineRun) Approve(user string) error {
+	if user == "" {
+		return fmt.Errorf("runs must be approved with a user key")
+	}
+
+	if user == r.User {
+		return fmt.Errorf("run %s was started by %s, another user must approve it", r.Id, user)
+	}
+
+	if r.Status != "awaiting-approval" {
+		return fmt.Errorf("run %s is %s", r.Id, r.Status)
+	}
+
+	prev := r.Status
+
+	for i := range r.Stages {
+		if r.Stages[i].Status == "awaiting-approval" {
+			r.Stages[i].Status = "approved"
+			r.Stages[i].Approver = user
+			break
+		}
+	}
+
+	r.Status = "approved"
+
+	return r.save(prev)
+}
+
+// UpdateStage records the progress of a stage reported by the cli promoting it. A stage that
+// requires approval can not start until the run is approved.
+func (r *PipelineRun) UpdateStage(p *Pipeline, stage, status, build, release string) error {
+	prev := r.Status
+
+	if err := r.updateStage(p, stage, status, build, release); err != nil {
+		// record that the run is waiting so it can be approved
+		if r.Status == "awaiting-approval" && prev != r.Status {
+			if serr := r.save(prev); serr != nil {
+				return serr
+			}
+		}
+
+		return err
+	}
+
+	return r.save(prev)
+}
+
+// updateStage checks that a stage may move to status and applies it to the run
+func (r *PipelineRun) updateStage(p *Pipeline, stage, status, build, release string) error {
+	i := -1
+
+	for j := range r.Stages {
+		if r.Stages[j].Name == stage {
+			i = j
+		}
+	}
+
+	if i < 0 || i >= len(p.Stages) {
+		return fmt.Errorf("no such stage: %s", stage)
+	}
+
+	rs := &r.Stages[i]
+
+	switch r.Status {
+	case "complete", "failed":
+		return fmt.Errorf("run %s is %s", r.Id, r.Status)
+	}
+
+	switch status {
+	case "running":
+		for j := 0; j < i; j++ {
+			if r.Stages[j].Status != "complete" {
+				return fmt.Errorf("stage %s is %s", r.Stages[j].Name, r.Stages[j].Status)
+			}
+		}
+
+		if p.Stages[i].Approval && rs.Status != "approved" {
+			rs.Status = "awaiting-approval"
+			r.Status = "awaiting-approval"
+			return fmt.Errorf("stage %s requires approval", stage)
+		}
+
+		if rs.Status != "pending" && rs.Status != "approved" {
+			return fmt.Errorf("stage %s is %s", stage, rs.Status)
+		}
+
+		rs.Status = "running"
+		r.Status = "running"
+	case "complete", "failed":
+		if rs.Status != "running" {
+			return fmt.Errorf("stage %s is %s", stage, rs.Status)
+		}
+
+		rs.Status = status
+
+		if build != "" {
+			rs.Build = build
+		}
+
+		if release != "" {
+			rs.Release = release
+		}
+
+		if status == "failed" || i == len(r.Stages)-1 {
+			r.Status = status
+			r.Ended = time.Now().UTC()
+		}
+	default:
+		return fmt.Errorf("status must be one of: running, complete, failed")
+	}
+
+	return nil
+}
+
+// save stores the run, failing if it changed since it was read with a status of prev
+func (r *PipelineRun) save(prev string) error {
+	table, err := pipelinesTable("DYNAMO_PIPELINE_RUNS")
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	req := &dynamodb.PutItemInput{
+		ConditionExpression: aws.String("attribute_not_exists(id)"),
+		Item: map[string]*dynamodb.AttributeValue{
+			"pipeline": &dynamodb.AttributeValue{S: aws.String(r.Pipeline)},
+			"id":       &dynamodb.AttributeValue{S: aws.String(r.Id)},
+			"status":   &dynamodb.AttributeValue{S: aws.String(r.Status)},
+			"data":     &dynamodb.AttributeValue{S: aws.String(string(data))},
+		},
+		TableName: aws.String(table),
+	}
+
+	if prev != "" {
+		req.ConditionExpression = aws.String("#status = :prev")
+		req.ExpressionAttributeNames = map[string]*string{"#status": aws.String("status")}
+		req.ExpressionAttributeValues = map[string]*dynamodb.AttributeValue{":prev": &dynamodb.AttributeValue{S: aws.String(prev)}}
+	}
+
+	_, err = DynamoDB().PutItem(req)
+	if awserrCode(err) == "ConditionalCheckFailedException" {
+		return fmt.Errorf("run %s was changed by someone else, try again", r.Id)
+	}
+
+	return err
+}
+
+// pipelinesTable returns the table named by env. Racks installed before pipelines were kept on
+// the rack do not have one.
+func pipelinesTable(env string) (string, error) {
+	table := os.Getenv(env)
+
+	if table == "" {
+		return "", fmt.Errorf("pipelines are not available, update your rack with `convox rack update`")
+	}
+
+	return table, nil
+}
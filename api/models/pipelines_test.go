@@ -0,0 +1,64 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePipeline(t *testing.T) {
+	p, err := ParsePipeline("web", "foo", "staging:org/staging,production:org/production", "production")
+	require.NoError(t, err)
+
+	assert.Equal(t, []PipelineStage{
+		{Name: "staging", Rack: "org/staging"},
+		{Name: "production", Rack: "org/production", Approval: true},
+	}, p.Stages)
+
+	_, err = ParsePipeline("web", "foo", "staging", "")
+	assert.EqualError(t, err, "stages must be written as name:rack")
+
+	_, err = ParsePipeline("web", "foo", "staging:org/staging,staging:org/other", "")
+	assert.EqualError(t, err, "duplicate stage: staging")
+
+	_, err = ParsePipeline("web", "foo", "staging:org/staging", "production")
+	assert.EqualError(t, err, "no such stage: production")
+}
+
+func TestPipelineRunRequiresApproval(t *testing.T) {
+	p, err := ParsePipeline("web", "foo", "staging:org/staging,production:org/production", "production")
+	require.NoError(t, err)
+
+	r := &PipelineRun{
+		Id:     "P1",
+		Status: "pending",
+		Stages: []PipelineRunStage{
+			{Name: "staging", Status: "pending", Build: "B1"},
+			{Name: "production", Status: "pending"},
+		},
+	}
+
+	assert.EqualError(t, r.updateStage(p, "production", "running", "", ""), "stage staging is pending")
+
+	require.NoError(t, r.updateStage(p, "staging", "running", "", ""))
+	require.NoError(t, r.updateStage(p, "staging", "complete", "B1", "R1"))
+
+	assert.EqualError(t, r.updateStage(p, "production", "running", "", ""), "stage production requires approval")
+	assert.Equal(t, "awaiting-approval", r.Status)
+	assert.Equal(t, "awaiting-approval", r.Stages[1].Status)
+
+	// asking again does not get around the approval
+	assert.EqualError(t, r.updateStage(p, "production", "running", "", ""), "stage production requires approval")
+
+	r.Stages[1].Status = "approved"
+	r.Status = "approved"
+
+	require.NoError(t, r.updateStage(p, "production", "running", "", ""))
+	require.NoError(t, r.updateStage(p, "production", "complete", "B2", "R2"))
+
+	assert.Equal(t, "complete", r.Status)
+	assert.Equal(t, "R2", r.Stages[1].Release)
+
+	assert.EqualError(t, r.updateStage(p, "production", "running", "", ""), "run P1 is complete")
+}
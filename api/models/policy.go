@@ -0,0 +1,121 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+const policyKey = "policy.json"
+
+// Policy restricts how an app can be changed. An immutable app only changes through releases so
+// exec, debug, ad-hoc runs and direct environment changes are denied. A policy without an app
+// applies to every app of the rack.
+type Policy struct {
+	App       string    `json:"app,omitempty"`
+	Immutable bool      `json:"immutable"`
+	Updated   time.Time `json:"updated"`
+	User      string    `json:"user,omitempty"`
+}
+
+// GetPolicy returns the policy of an app, or of the rack when app is empty
+func GetPolicy(app string) (*Policy, error) {
+	bucket, err := policyBucket(app)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Policy{App: app}
+
+	data, err := s3Get(bucket, policyKey)
+	if awserrCode(err) == "NoSuchKey" {
+		return p, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Save stores the policy in the settings bucket of its app or of the rack
+func (p *Policy) Save() error {
+	bucket, err := policyBucket(p.App)
+	if err != nil {
+		return err
+	}
+
+	p.Updated = time.Now().UTC()
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	if err := S3Put(bucket, policyKey, data, false); err != nil {
+		return err
+	}
+
+	NotifySuccess("policy:update", map[string]string{"app": p.App, "immutable": fmt.Sprintf("%t", p.Immutable)})
+
+	return nil
+}
+
+// Immutable returns true if the policy of an app or of the rack only allows the app to change
+// through releases
+func Immutable(app string) (bool, error) {
+	p, err := GetPolicy(app)
+	if err != nil {
+		return false, err
+	}
+
+	rack, err := GetPolicy("")
+	if err != nil {
+		return false, err
+	}
+
+	return rack.Immutable || p.Immutable, nil
+}
+
+// CheckImmutable returns an error if the policy of an app or of the rack denies an action that
+// changes the app outside of a release. A user with an active break glass grant is allowed and
+// the grant is returned.
+func CheckImmutable(app, action, user string) (*BreakGlass, error) {
+	immutable, err := Immutable(app)
+	if err != nil {
+		return nil, err
+	}
+
+	if !immutable {
+		return nil, nil
+	}
+
+	grant, err := ActiveBreakGlass(app, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if grant == nil {
+		return nil, fmt.Errorf("%s is immutable, %s is not allowed", app, action)
+	}
+
+	return grant, nil
+}
+
+func policyBucket(app string) (string, error) {
+	if app == "" {
+		return os.Getenv("SETTINGS_BUCKET"), nil
+	}
+
+	a, err := GetApp(app)
+	if err != nil {
+		return "", err
+	}
+
+	return a.settingsBucket(), nil
+}
@@ -36,12 +36,16 @@ type Processes []*Process
 // DescribeContainerInstances lists and describes all the ECS instances.
 // It handles pagination for clusters > 100 instances.
 func DescribeContainerInstances() (*ecs.DescribeContainerInstancesOutput, error) {
+	return describeClusterInstances(os.Getenv("CLUSTER"))
+}
+
+func describeClusterInstances(cluster string) (*ecs.DescribeContainerInstancesOutput, error) {
 	instances := []*ecs.ContainerInstance{}
 	var nextToken string
 
 	for {
 		res, err := ECS().ListContainerInstances(&ecs.ListContainerInstancesInput{
-			Cluster:   aws.String(os.Getenv("CLUSTER")),
+			Cluster:   aws.String(cluster),
 			NextToken: &nextToken,
 		})
 		if err != nil {
@@ -49,7 +53,7 @@ func DescribeContainerInstances() (*ecs.DescribeContainerInstancesOutput, error)
 		}
 
 		dres, err := ECS().DescribeContainerInstances(&ecs.DescribeContainerInstancesInput{
-			Cluster:            aws.String(os.Getenv("CLUSTER")),
+			Cluster:            aws.String(cluster),
 			ContainerInstances: res.ContainerInstanceArns,
 		})
 		if err != nil {
@@ -72,6 +76,16 @@ func DescribeContainerInstances() (*ecs.DescribeContainerInstancesOutput, error)
 	}, nil
 }
 
+// appCluster returns the ECS cluster an app's processes run in. The rack's own processes run
+// in a separate cluster when the rack has a dedicated control plane.
+func appCluster(app string) string {
+	if control := os.Getenv("CONTROL_CLUSTER"); control != "" && app == os.Getenv("RACK") {
+		return control
+	}
+
+	return os.Getenv("CLUSTER")
+}
+
 func GetAppServices(app string) ([]*ecs.Service, error) {
 	services := []*ecs.Service{}
 
@@ -87,14 +101,15 @@ func GetAppServices(app string) ([]*ecs.Service, error) {
 	for _, r := range resources {
 		i = i + 1
 
-		if r.Type == "Custom::ECSService" {
+		switch r.Type {
+		case "AWS::ECS::Service", "Custom::ECSService":
 			arns = append(arns, aws.String(r.Id))
 		}
 
 		//have to make requests in batches of ten
 		if len(arns) == 10 || (i == len(resources) && len(arns) > 0) {
 			dres, err := ECS().DescribeServices(&ecs.DescribeServicesInput{
-				Cluster:  aws.String(os.Getenv("CLUSTER")),
+				Cluster:  aws.String(appCluster(app)),
 				Services: arns,
 			})
 
@@ -137,8 +152,10 @@ func ListProcesses(app string) ([]*Process, error) {
 		}
 	}
 
+	cluster := appCluster(app)
+
 	// get ECS and EC2 instance info up front
-	dres, err := DescribeContainerInstances()
+	dres, err := describeClusterInstances(cluster)
 	if err != nil {
 		return nil, err
 	}
@@ -169,7 +186,7 @@ func ListProcesses(app string) ([]*Process, error) {
 	// Describe Service Tasks
 	for _, service := range services {
 		taskArns, err := ECS().ListTasks(&ecs.ListTasksInput{
-			Cluster:     aws.String(os.Getenv("CLUSTER")),
+			Cluster:     aws.String(cluster),
 			ServiceName: aws.String(service),
 		})
 
@@ -182,7 +199,7 @@ func ListProcesses(app string) ([]*Process, error) {
 		}
 
 		ts, err := ECS().DescribeTasks(&ecs.DescribeTasksInput{
-			Cluster: aws.String(os.Getenv("CLUSTER")),
+			Cluster: aws.String(cluster),
 			Tasks:   taskArns.TaskArns,
 		})
 
@@ -195,7 +212,7 @@ func ListProcesses(app string) ([]*Process, error) {
 
 	// Describe one-off Tasks
 	lreq, err := ECS().ListTasks(&ecs.ListTasksInput{
-		Cluster:   aws.String(os.Getenv("CLUSTER")),
+		Cluster:   aws.String(cluster),
 		StartedBy: aws.String("convox"),
 	})
 
@@ -205,7 +222,7 @@ func ListProcesses(app string) ([]*Process, error) {
 
 	if len(lreq.TaskArns) > 0 {
 		dreq, err := ECS().DescribeTasks(&ecs.DescribeTasksInput{
-			Cluster: aws.String(os.Getenv("CLUSTER")),
+			Cluster: aws.String(cluster),
 			Tasks:   lreq.TaskArns,
 		})
 
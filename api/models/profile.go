@@ -0,0 +1,224 @@
+package models
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/convox/rack/manifest"
+	"github.com/fsouza/go-dockerclient"
+)
+
+// ProfileFormats are the profile endpoints a process can serve along with the types of profile
+// each of them has. Processes declare the port of the endpoint with the convox.profile.port label
+// and its format with convox.profile.format.
+var ProfileFormats = map[string][]string{
+	"jfr":   {"cpu", "heap"},
+	"pprof": {"allocs", "block", "cpu", "goroutine", "heap", "mutex", "trace"},
+}
+
+// MaxProfileDuration is the longest a profile can be recorded for
+const MaxProfileDuration = 5 * time.Minute
+
+// profileTimeout is how long a profile endpoint has to respond beyond the recording itself
+const profileTimeout = 30 * time.Second
+
+// ProcessProfile is a profile fetched from a process. It is kept in a temporary file that is
+// removed when the profile is closed.
+type ProcessProfile struct {
+	Format string
+	*os.File
+}
+
+// Close removes the profile
+func (p *ProcessProfile) Close() error {
+	p.File.Close()
+
+	return os.Remove(p.File.Name())
+}
+
+// ProfileURL returns the url of a profile on a process that serves profiles in format on port
+func ProfileURL(format, port, path, typ string, duration time.Duration) (string, error) {
+	types, ok := ProfileFormats[format]
+	if !ok {
+		return "", fmt.Errorf("profile format must be one of %s", strings.Join(profileFormatNames(), ", "))
+	}
+
+	if !containsString(types, typ) {
+		return "", fmt.Errorf("profile type for %s must be one of %s", format, strings.Join(types, ", "))
+	}
+
+	if duration < time.Second || duration > MaxProfileDuration {
+		return "", fmt.Errorf("profile duration must be between 1s and %s", MaxProfileDuration)
+	}
+
+	seconds := strconv.Itoa(int(duration.Seconds()))
+	base := fmt.Sprintf("http://127.0.0.1:%s%s", port, strings.TrimSuffix(path, "/"))
+
+	if format == "jfr" {
+		return fmt.Sprintf("%s?%s", base, url.Values{"type": {typ}, "seconds": {seconds}}.Encode()), nil
+	}
+
+	switch typ {
+	case "cpu":
+		return fmt.Sprintf("%s/profile?seconds=%s", base, seconds), nil
+	case "trace":
+		return fmt.Sprintf("%s/trace?seconds=%s", base, seconds), nil
+	}
+
+	return fmt.Sprintf("%s/%s", base, typ), nil
+}
+
+// Profile records a profile of a running process from the endpoint its service declares. The
+// profile is fetched by a container that shares the network of the process so that the endpoint
+// does not need to be exposed.
+func (a *App) Profile(pid, typ string, duration time.Duration) (*ProcessProfile, error) {
+	ps, err := runningProcess(a.Name, pid)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := GetRelease(a.Name, ps.Release)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := manifest.Load([]byte(r.Manifest))
+	if err != nil {
+		return nil, err
+	}
+
+	s, ok := m.Services[ps.Name]
+	if !ok || s.ProfilePort() == "" {
+		return nil, fmt.Errorf("process %s does not serve profiles, set the convox.profile.port label", ps.Name)
+	}
+
+	u, err := ProfileURL(s.ProfileFormat(), s.ProfilePort(), s.ProfilePath(), typ, duration)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := ps.Docker()
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := pullImage(d, DefaultDebugImage)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := d.CreateContainer(profileContainerOptions(a.Name, ps, image, u, duration+profileTimeout))
+	if err != nil {
+		return nil, err
+	}
+
+	defer d.RemoveContainer(docker.RemoveContainerOptions{ID: res.ID, Force: true})
+
+	f, err := ioutil.TempFile("", "profile")
+	if err != nil {
+		return nil, err
+	}
+
+	p := &ProcessProfile{Format: s.ProfileFormat(), File: f}
+
+	if err := fetchProfile(d, res.ID, f); err != nil {
+		p.Close()
+		return nil, err
+	}
+
+	if _, err := f.Seek(0, 0); err != nil {
+		p.Close()
+		return nil, err
+	}
+
+	NotifySuccess("process:profile", map[string]string{"app": a.Name, "process": ps.Name, "type": typ})
+
+	return p, nil
+}
+
+// fetchProfile runs a profile container and writes its output to f
+func fetchProfile(d *docker.Client, container string, f *os.File) error {
+	var stderr bytes.Buffer
+
+	success := make(chan struct{})
+	attached := make(chan error, 1)
+
+	go func() {
+		attached <- d.AttachToContainer(docker.AttachToContainerOptions{
+			Container:    container,
+			OutputStream: f,
+			ErrorStream:  &stderr,
+			Stream:       true,
+			Stdout:       true,
+			Stderr:       true,
+			Success:      success,
+		})
+	}()
+
+	select {
+	case <-success:
+		success <- struct{}{}
+	case err := <-attached:
+		return err
+	}
+
+	if err := d.StartContainer(container, nil); err != nil {
+		return err
+	}
+
+	code, err := d.WaitContainer(container)
+	if err != nil {
+		return err
+	}
+
+	// the attach ends once all of the output has been written
+	if err := <-attached; err != nil {
+		return err
+	}
+
+	if code != 0 {
+		return fmt.Errorf("unable to fetch profile: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+// profileContainerOptions configure a container that fetches a profile from inside the network of a process
+func profileContainerOptions(app string, ps *Process, image, u string, timeout time.Duration) docker.CreateContainerOptions {
+	return docker.CreateContainerOptions{
+		Config: &docker.Config{
+			AttachStdout: true,
+			AttachStderr: true,
+			Cmd:          []string{"curl", "-sSf", "--max-time", strconv.Itoa(int(timeout.Seconds())), u},
+			Image:        image,
+			Labels: map[string]string{
+				"com.convox.rack.type":    "profile",
+				"com.convox.rack.app":     app,
+				"com.convox.rack.process": ps.Name,
+				"com.convox.rack.target":  ps.Id,
+			},
+		},
+		HostConfig: &docker.HostConfig{
+			NetworkMode: fmt.Sprintf("container:%s", ps.containerId),
+		},
+	}
+}
+
+func profileFormatNames() []string {
+	names := []string{}
+
+	for name := range ProfileFormats {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,32 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/convox/rack/api/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfileURL(t *testing.T) {
+	u, err := models.ProfileURL("pprof", "6060", "/debug/pprof", "cpu", 30*time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, "http://127.0.0.1:6060/debug/pprof/profile?seconds=30", u)
+
+	u, err = models.ProfileURL("pprof", "6060", "/debug/pprof/", "heap", 30*time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, "http://127.0.0.1:6060/debug/pprof/heap", u)
+
+	u, err = models.ProfileURL("jfr", "9999", "/jfr", "cpu", time.Minute)
+	assert.Nil(t, err)
+	assert.Equal(t, "http://127.0.0.1:9999/jfr?seconds=60&type=cpu", u)
+
+	_, err = models.ProfileURL("jfr", "9999", "/jfr", "goroutine", time.Minute)
+	assert.EqualError(t, err, "profile type for jfr must be one of cpu, heap")
+
+	_, err = models.ProfileURL("hprof", "9999", "/", "heap", time.Minute)
+	assert.EqualError(t, err, "profile format must be one of jfr, pprof")
+
+	_, err = models.ProfileURL("pprof", "6060", "/debug/pprof", "cpu", 10*time.Minute)
+	assert.EqualError(t, err, "profile duration must be between 1s and 5m0s")
+}
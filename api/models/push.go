@@ -0,0 +1,307 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/convox/rack/api/structs"
+)
+
+// PushBuildTimeout is how long a build started by a push may take before it is given up on
+var PushBuildTimeout = 2 * time.Hour
+
+// Push is a push to a branch of a git repository received from a GitHub or GitLab webhook
+type Push struct {
+	// Repos are the urls the repository can be cloned from
+	Repos  []string
+	Branch string
+	Commit string
+	User   string
+}
+
+// AppWebhook is what a repository needs to send push webhooks to the rack for an app
+type AppWebhook struct {
+	Repo   string `json:"repo"`
+	Branch string `json:"branch"`
+	Secret string `json:"secret"`
+	GitHub string `json:"github"`
+	GitLab string `json:"gitlab"`
+}
+
+// scpRepo matches the user@host:path form of ssh git urls
+var scpRepo = regexp.MustCompile(`^[\w.-]+@([\w.-]+):(.+)$`)
+
+// ParseGitHubPush reads the body of a GitHub push event. Pushes that delete a branch are nil.
+func ParseGitHubPush(body []byte) (*Push, error) {
+	var event struct {
+		Ref     string
+		After   string
+		Deleted bool
+		Pusher  struct {
+			Name string
+		}
+		Repository struct {
+			CloneURL string `json:"clone_url"`
+			SSHURL   string `json:"ssh_url"`
+			HTMLURL  string `json:"html_url"`
+		}
+	}
+
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, err
+	}
+
+	if event.Deleted || !strings.HasPrefix(event.Ref, "refs/heads/") {
+		return nil, nil
+	}
+
+	return &Push{
+		Repos:  []string{event.Repository.CloneURL, event.Repository.SSHURL, event.Repository.HTMLURL},
+		Branch: strings.TrimPrefix(event.Ref, "refs/heads/"),
+		Commit: event.After,
+		User:   event.Pusher.Name,
+	}, nil
+}
+
+// ParseGitLabPush reads the body of a GitLab push hook. Pushes that delete a branch are nil.
+func ParseGitLabPush(body []byte) (*Push, error) {
+	var event struct {
+		Ref         string
+		CheckoutSha string `json:"checkout_sha"`
+		UserName    string `json:"user_name"`
+		Project     struct {
+			GitHTTPURL string `json:"git_http_url"`
+			GitSSHURL  string `json:"git_ssh_url"`
+			WebURL     string `json:"web_url"`
+		}
+	}
+
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, err
+	}
+
+	if event.CheckoutSha == "" || !strings.HasPrefix(event.Ref, "refs/heads/") {
+		return nil, nil
+	}
+
+	return &Push{
+		Repos:  []string{event.Project.GitHTTPURL, event.Project.GitSSHURL, event.Project.WebURL},
+		Branch: strings.TrimPrefix(event.Ref, "refs/heads/"),
+		Commit: event.CheckoutSha,
+		User:   event.UserName,
+	}, nil
+}
+
+// PushApps returns the apps whose Repo and Branch parameters match a push
+func PushApps(p *Push) (Apps, error) {
+	apps, err := ListApps()
+	if err != nil {
+		return nil, err
+	}
+
+	matched := Apps{}
+
+	for _, a := range apps {
+		if a.Parameters["Repo"] == "" {
+			continue
+		}
+
+		branch := a.Parameters["Branch"]
+
+		if branch == "" {
+			branch = "master"
+		}
+
+		if branch != p.Branch {
+			continue
+		}
+
+		for _, repo := range p.Repos {
+			if repo != "" && normalizeRepo(repo) == normalizeRepo(a.Parameters["Repo"]) {
+				matched = append(matched, a)
+				break
+			}
+		}
+	}
+
+	return matched, nil
+}
+
+// Webhook returns the secret and urls a repository sends push webhooks for the app with,
+// generating the secret the first time
+func (a *App) Webhook() (*AppWebhook, error) {
+	secret, err := a.webhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	branch := a.Parameters["Branch"]
+
+	if branch == "" {
+		branch = "master"
+	}
+
+	return &AppWebhook{
+		Repo:   a.Parameters["Repo"],
+		Branch: branch,
+		Secret: secret,
+		GitHub: pushWebhookURL("github"),
+		GitLab: pushWebhookURL("gitlab"),
+	}, nil
+}
+
+// ValidGitHubSignature returns true if an X-Hub-Signature-256 or X-Hub-Signature header is the
+// HMAC of body with the webhook secret of the app
+func (a *App) ValidGitHubSignature(body []byte, signature string) (bool, error) {
+	var mac func() hash.Hash
+
+	switch {
+	case strings.HasPrefix(signature, "sha256="):
+		mac = sha256.New
+	case strings.HasPrefix(signature, "sha1="):
+		mac = sha1.New
+	default:
+		return false, nil
+	}
+
+	expected, err := hex.DecodeString(signature[strings.Index(signature, "=")+1:])
+	if err != nil {
+		return false, nil
+	}
+
+	secret, err := a.webhookSecret()
+	if err != nil {
+		return false, err
+	}
+
+	h := hmac.New(mac, []byte(secret))
+	h.Write(body)
+
+	return hmac.Equal(h.Sum(nil), expected), nil
+}
+
+// ValidGitLabToken returns true if an X-Gitlab-Token header is the webhook secret of the app
+func (a *App) ValidGitLabToken(token string) (bool, error) {
+	secret, err := a.webhookSecret()
+	if err != nil {
+		return false, err
+	}
+
+	return subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1, nil
+}
+
+// DeployPush builds the commit of a push from the Repo of the app and promotes the release
+func (a *App) DeployPush(p *Push) (string, error) {
+	// the commit of the push replaces any branch the repo names
+	source := fmt.Sprintf("%s#%s", strings.SplitN(a.Parameters["Repo"], "#", 2)[0], p.Commit)
+	description := fmt.Sprintf("push to %s by %s", p.Branch, p.User)
+
+	b, err := Provider().BuildCreateRepo(a.Name, source, structs.BuildOptions{
+		Cache:       true,
+		Description: description,
+		Manifest:    "docker-compose.yml",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return PromoteBuild(a.Name, b, PushBuildTimeout, fmt.Sprintf("push:%s", p.User))
+}
+
+// PromoteBuild waits for a build to complete then promotes its release on behalf of user.
+// Returns the promoted release id.
+func PromoteBuild(app string, b *structs.Build, timeout time.Duration, user string) (string, error) {
+	deadline := time.Now().Add(timeout)
+
+	var err error
+
+	for b.Status != "complete" {
+		switch b.Status {
+		case "cancelled", "error", "failed", "timeout":
+			return "", fmt.Errorf("build %s %s", b.Id, b.Status)
+		}
+
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("build %s did not finish within %s", b.Id, timeout)
+		}
+
+		time.Sleep(10 * time.Second)
+
+		b, err = Provider().BuildGet(app, b.Id)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	a, err := GetApp(app)
+	if err != nil {
+		return "", err
+	}
+
+	r, err := GetRelease(app, b.Release)
+	if err != nil {
+		return "", err
+	}
+
+	if err := r.Promote(); err != nil {
+		return "", err
+	}
+
+	if err := a.RecordReleasePromotion(r, user); err != nil {
+		return "", err
+	}
+
+	return r.Id, nil
+}
+
+func (a *App) webhookSecret() (string, error) {
+	data, err := s3Get(a.settingsBucket(), "webhook-secret")
+	if awserrCode(err) == "NoSuchKey" {
+		secret := generateId("", 32)
+
+		if err := S3Put(a.settingsBucket(), "webhook-secret", []byte(secret), false); err != nil {
+			return "", err
+		}
+
+		return secret, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func pushWebhookURL(provider string) string {
+	return fmt.Sprintf("https://%s/webhooks/%s", os.Getenv("NOTIFICATION_HOST"), provider)
+}
+
+// normalizeRepo reduces the ways to refer to a git repository to host/path, i.e.
+// git@github.com:convox/rack.git and https://user@github.com/convox/rack to github.com/convox/rack
+func normalizeRepo(repo string) string {
+	repo = strings.TrimSpace(repo)
+
+	if m := scpRepo.FindStringSubmatch(repo); m != nil {
+		repo = fmt.Sprintf("ssh://%s/%s", m[1], m[2])
+	}
+
+	u, err := url.Parse(repo)
+	if err != nil || u.Host == "" {
+		return strings.ToLower(repo)
+	}
+
+	path := strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), ".git")
+
+	return strings.ToLower(u.Host + path)
+}
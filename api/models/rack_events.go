@@ -0,0 +1,270 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// RackEventTypes are the types a rack event can be listed by
+var RackEventTypes = []string{"alarm", "api", "app", "crash", "error", "instance", "rack", "release", "scaling", "secret", "stack"}
+
+// RackEvent is something that happened to the rack or one of its apps. Events are kept
+// for EVENT_RETENTION days, long after CloudFormation has stopped listing stack events.
+type RackEvent struct {
+	Action  string            `json:"action"`
+	Type    string            `json:"type"`
+	Status  string            `json:"status"`
+	App     string            `json:"app"`
+	Message string            `json:"message"`
+	Data    map[string]string `json:"data"`
+	Created time.Time         `json:"created"`
+}
+
+type RackEvents []RackEvent
+
+func (es RackEvents) Len() int           { return len(es) }
+func (es RackEvents) Less(i, j int) bool { return es[i].Created.After(es[j].Created) }
+func (es RackEvents) Swap(i, j int)      { es[i], es[j] = es[j], es[i] }
+
+// RackEventFilter narrows the events returned by ListRackEvents
+type RackEventFilter struct {
+	App   string
+	Type  string
+	Since time.Time
+	Limit int
+}
+
+// Matches returns true if the event passes every filter that is set. Failed events of
+// any kind have the error type as well as their own.
+func (f RackEventFilter) Matches(e RackEvent) bool {
+	if f.App != "" && e.App != f.App {
+		return false
+	}
+
+	if f.Type != "" && e.Type != f.Type && !(f.Type == "error" && e.Status == "error") {
+		return false
+	}
+
+	return true
+}
+
+// RackEventType groups actions so that, for example, every stack change can be listed together
+func RackEventType(action string) string {
+	switch action {
+	case "app:create", "app:delete", "rack:converge", "rack:drift", "rack:update", "release:canary", "release:canary:abort", "release:promote":
+		return "stack"
+	case "rack:scale", "release:scale":
+		return "scaling"
+	}
+
+	return strings.SplitN(action, ":", 2)[0]
+}
+
+// RecordRackEvent stores an event in the events table. Racks installed before the table
+// existed have nowhere to store events and skip recording them.
+func RecordRackEvent(action, status string, data map[string]string) error {
+	table := os.Getenv("DYNAMO_EVENTS")
+
+	if table == "" {
+		return nil
+	}
+
+	e := RackEvent{
+		Action:  action,
+		Type:    RackEventType(action),
+		Status:  status,
+		App:     data["app"],
+		Message: data["message"],
+		Data:    data,
+		Created: time.Now().UTC(),
+	}
+
+	// app events name the app they are about
+	if e.App == "" && e.Type == "app" {
+		e.App = data["name"]
+	}
+
+	payload, err := json.Marshal(e.Data)
+	if err != nil {
+		return err
+	}
+
+	item := map[string]*dynamodb.AttributeValue{
+		"day":     &dynamodb.AttributeValue{S: aws.String(auditDay(e.Created))},
+		"created": &dynamodb.AttributeValue{S: aws.String(e.Created.Format(auditTimeFormat))},
+		"action":  &dynamodb.AttributeValue{S: aws.String(e.Action)},
+		"type":    &dynamodb.AttributeValue{S: aws.String(e.Type)},
+		"status":  &dynamodb.AttributeValue{S: aws.String(e.Status)},
+		"data":    &dynamodb.AttributeValue{S: aws.String(string(payload))},
+	}
+
+	// dynamodb does not accept empty strings
+	if e.App != "" {
+		item["app"] = &dynamodb.AttributeValue{S: aws.String(e.App)}
+	}
+
+	if e.Message != "" {
+		item["message"] = &dynamodb.AttributeValue{S: aws.String(e.Message)}
+	}
+
+	_, err = DynamoDB().PutItem(&dynamodb.PutItemInput{
+		Item:      item,
+		TableName: aws.String(table),
+	})
+
+	return err
+}
+
+// ListRackEvents returns the events matching filter, newest first
+func ListRackEvents(filter RackEventFilter) (RackEvents, error) {
+	table := os.Getenv("DYNAMO_EVENTS")
+
+	if table == "" {
+		return nil, fmt.Errorf("rack events are not available, update your rack with `convox rack update`")
+	}
+
+	events := RackEvents{}
+
+	now := time.Now().UTC()
+	since := filter.Since.UTC()
+
+	if since.IsZero() {
+		since = now.Add(-24 * time.Hour)
+	}
+
+	// events are partitioned by day so walk backwards one day at a time
+	for day := now; !day.Before(since.Truncate(24 * time.Hour)); day = day.Add(-24 * time.Hour) {
+		req := &dynamodb.QueryInput{
+			KeyConditionExpression: aws.String("#day = :day AND #created >= :since"),
+			ExpressionAttributeNames: map[string]*string{
+				"#day":     aws.String("day"),
+				"#created": aws.String("created"),
+			},
+			ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+				":day":   &dynamodb.AttributeValue{S: aws.String(auditDay(day))},
+				":since": &dynamodb.AttributeValue{S: aws.String(since.Format(auditTimeFormat))},
+			},
+			ScanIndexForward: aws.Bool(false),
+			TableName:        aws.String(table),
+		}
+
+		err := DynamoDB().QueryPages(req, func(res *dynamodb.QueryOutput, last bool) bool {
+			for _, item := range res.Items {
+				e := rackEventFromItem(item)
+
+				if filter.Matches(e) {
+					events = append(events, e)
+				}
+			}
+
+			return filter.Limit == 0 || len(events) < filter.Limit
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if filter.Limit > 0 && len(events) >= filter.Limit {
+			break
+		}
+	}
+
+	sort.Sort(events)
+
+	if filter.Limit > 0 && len(events) > filter.Limit {
+		events = events[0:filter.Limit]
+	}
+
+	return events, nil
+}
+
+// RackEventRetention returns how long events are kept before PruneRackEvents removes them
+func RackEventRetention() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("EVENT_RETENTION"))
+	if err != nil || days < 1 {
+		days = 30
+	}
+
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// PruneRackEvents removes the events created before a time and returns how many were removed
+func PruneRackEvents(before time.Time) (int, error) {
+	table := os.Getenv("DYNAMO_EVENTS")
+
+	if table == "" {
+		return 0, nil
+	}
+
+	keys := []map[string]*dynamodb.AttributeValue{}
+
+	req := &dynamodb.ScanInput{
+		FilterExpression: aws.String("#created < :before"),
+		ExpressionAttributeNames: map[string]*string{
+			"#day":     aws.String("day"),
+			"#created": aws.String("created"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":before": &dynamodb.AttributeValue{S: aws.String(before.UTC().Format(auditTimeFormat))},
+		},
+		ProjectionExpression: aws.String("#day, #created"),
+		TableName:            aws.String(table),
+	}
+
+	err := DynamoDB().ScanPages(req, func(res *dynamodb.ScanOutput, last bool) bool {
+		keys = append(keys, res.Items...)
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// dynamodb deletes at most 25 items per batch, anything it leaves unprocessed goes on the next prune
+	for i := 0; i < len(keys); i += 25 {
+		j := i + 25
+
+		if j > len(keys) {
+			j = len(keys)
+		}
+
+		writes := []*dynamodb.WriteRequest{}
+
+		for _, key := range keys[i:j] {
+			writes = append(writes, &dynamodb.WriteRequest{DeleteRequest: &dynamodb.DeleteRequest{Key: key}})
+		}
+
+		_, err := DynamoDB().BatchWriteItem(&dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]*dynamodb.WriteRequest{table: writes},
+		})
+		if err != nil {
+			return i, err
+		}
+	}
+
+	return len(keys), nil
+}
+
+func rackEventFromItem(item map[string]*dynamodb.AttributeValue) RackEvent {
+	created, _ := time.Parse(auditTimeFormat, coalesce(item["created"], ""))
+
+	e := RackEvent{
+		Action:  coalesce(item["action"], ""),
+		Type:    coalesce(item["type"], ""),
+		Status:  coalesce(item["status"], ""),
+		App:     coalesce(item["app"], ""),
+		Message: coalesce(item["message"], ""),
+		Data:    map[string]string{},
+		Created: created,
+	}
+
+	json.Unmarshal([]byte(coalesce(item["data"], "{}")), &e.Data)
+
+	return e
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRackEventType(t *testing.T) {
+	assert.Equal(t, "stack", RackEventType("release:promote"))
+	assert.Equal(t, "stack", RackEventType("rack:update"))
+	assert.Equal(t, "scaling", RackEventType("release:scale"))
+	assert.Equal(t, "scaling", RackEventType("rack:scale"))
+	assert.Equal(t, "instance", RackEventType("instance:unhealthy"))
+	assert.Equal(t, "app", RackEventType("app:cancel"))
+	assert.Equal(t, "api", RackEventType("api:error"))
+}
+
+func TestRackEventFilterMatches(t *testing.T) {
+	e := RackEvent{Action: "release:scale", Type: "scaling", Status: "error", App: "foo"}
+
+	assert.True(t, RackEventFilter{}.Matches(e))
+	assert.True(t, RackEventFilter{App: "foo", Type: "scaling"}.Matches(e))
+	assert.True(t, RackEventFilter{Type: "error"}.Matches(e))
+	assert.False(t, RackEventFilter{Type: "stack"}.Matches(e))
+	assert.False(t, RackEventFilter{App: "bar"}.Matches(e))
+
+	e.Status = "success"
+
+	assert.False(t, RackEventFilter{Type: "error"}.Matches(e))
+}
+
+func TestRackEventRetention(t *testing.T) {
+	defer os.Setenv("EVENT_RETENTION", os.Getenv("EVENT_RETENTION"))
+
+	os.Setenv("EVENT_RETENTION", "7")
+	assert.Equal(t, 7*24*time.Hour, RackEventRetention())
+
+	os.Setenv("EVENT_RETENTION", "")
+	assert.Equal(t, 30*24*time.Hour, RackEventRetention())
+}
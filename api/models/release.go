@@ -30,6 +30,22 @@ type Release struct {
 	Env      string    `json:"env"`
 	Manifest string    `json:"manifest"`
 	Created  time.Time `json:"created"`
+
+	// CreatedBy is the user that created the release, if known
+	CreatedBy string `json:"created-by,omitempty"`
+
+	// Overrides are the manifest overrides for this rack applied when the release was promoted
+	Overrides []string `json:"overrides,omitempty"`
+
+	// Processes are the processes a partial promote updated to the release, every process when empty
+	Processes []string `json:"processes,omitempty"`
+}
+
+// overrideScale is the position in a formation parameter that each scale override sets
+var overrideScale = map[string]int{
+	"scale.count":  0,
+	"scale.cpu":    1,
+	"scale.memory": 2,
 }
 
 type Releases []Release
@@ -66,6 +82,11 @@ func GetRelease(app, id string) (*Release, error) {
 
 	release := releaseFromItem(res.Item)
 
+	release.Env, err = releaseEnv(res.Item)
+	if err != nil {
+		return nil, err
+	}
+
 	return release, nil
 }
 
@@ -92,13 +113,26 @@ func (r *Release) Save() error {
 	}
 
 	if r.Env != "" {
-		req.Item["env"] = &dynamodb.AttributeValue{S: aws.String(r.Env)}
+		av, err := releaseEnvItem(r.Env)
+		if err != nil {
+			return err
+		}
+
+		if av.B != nil {
+			req.Item["env-encrypted"] = av
+		} else {
+			req.Item["env"] = av
+		}
 	}
 
 	if r.Manifest != "" {
 		req.Item["manifest"] = &dynamodb.AttributeValue{S: aws.String(r.Manifest)}
 	}
 
+	if r.CreatedBy != "" {
+		req.Item["created-by"] = &dynamodb.AttributeValue{S: aws.String(r.CreatedBy)}
+	}
+
 	_, err := DynamoDB().PutItem(req)
 
 	if err != nil {
@@ -129,34 +163,109 @@ func (r *Release) Save() error {
 }
 
 func (r *Release) Promote() error {
-	app, err := GetApp(r.App)
+	return r.PromoteProcesses(nil)
+}
+
+// PromoteProcesses updates only some processes of the app to the release, leaving the others
+// on the releases they run. Every process is updated when processes is empty.
+func (r *Release) PromoteProcesses(processes []string) error {
+	req, releases, err := r.promoteUpdate(false, processes)
 	if err != nil {
 		return err
 	}
 
-	if !app.IsBound() {
-		return fmt.Errorf("unbound apps are no longer supported for promotion")
+	_, err = UpdateStack(req)
+
+	if err == nil {
+		app, aerr := GetApp(r.App)
+		if aerr != nil {
+			return aerr
+		}
+
+		err = app.saveProcessReleases(releases)
 	}
 
-	formation, err := r.Formation()
+	data := map[string]string{
+		"app": r.App,
+		"id":  r.Id,
+	}
+
+	if len(processes) > 0 {
+		data["processes"] = strings.Join(processes, ",")
+	}
+
+	NotifySuccess("release:promote", data)
+
+	return err
+}
+
+// PromotePreview returns the changes to the stack of an app that promoting the release to some
+// or all of its processes would make
+func (r *Release) PromotePreview(processes []string) (StackChanges, error) {
+	req, _, err := r.promoteUpdate(true, processes)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// If release formation was saved in S3, get that instead
-	f, err := s3Get(app.Outputs["Settings"], fmt.Sprintf("templates/%s", r.Id))
-	if err != nil && awserrCode(err) != "NoSuchKey" {
-		return err
+	return PreviewStackUpdate(req)
+}
+
+// promoteUpdate returns the stack update that promotes the release to processes, or to every
+// process when processes is empty, and the processes that are left on other releases. A preview
+// does not upload the self signed certificates that new secure ports would otherwise be given.
+func (r *Release) promoteUpdate(preview bool, processes []string) (*cloudformation.UpdateStackInput, map[string]string, error) {
+	app, err := GetApp(r.App)
+	if err != nil {
+		return nil, nil, err
 	}
-	if err == nil {
-		formation = string(f)
+
+	if !app.IsBound() {
+		return nil, nil, fmt.Errorf("unbound apps are no longer supported for promotion")
 	}
 
-	fmt.Printf("ns=kernel at=release.promote at=s3Get found=%t\n", err == nil)
+	if err := r.checkSecrets(); err != nil {
+		return nil, nil, err
+	}
+
+	pins := map[string]*Release{}
+	releases := map[string]string{}
+
+	if len(processes) > 0 {
+		pins, releases, err = r.processPins(app, processes)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	r.Processes = processes
+
+	formation, err := r.formation(pins)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key := r.Id
+
+	if len(pins) > 0 {
+		// the formation of a partial promote depends on the releases the other processes run so
+		// it is neither reused nor kept as the formation of the release
+		key = fmt.Sprintf("%s-partial", r.Id)
+	} else {
+		// If release formation was saved in S3, get that instead
+		f, err := s3Get(app.Outputs["Settings"], fmt.Sprintf("templates/%s", r.Id))
+		if err != nil && awserrCode(err) != "NoSuchKey" {
+			return nil, nil, err
+		}
+		if err == nil {
+			formation = string(f)
+		}
+
+		fmt.Printf("ns=kernel at=release.promote at=s3Get found=%t\n", err == nil)
+	}
 
 	existing, err := formationParameters(formation)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	oldVersion := app.Parameters["Version"]
@@ -180,9 +289,15 @@ func (r *Release) Promote() error {
 
 	app.Parameters["SubnetsPrivate"] = subnetsPrivate
 
-	m, err := manifest.Load([]byte(r.Manifest))
+	m, overrides, err := r.manifest()
 	if err != nil {
-		return err
+		return nil, nil, err
+	}
+
+	r.Overrides = []string{}
+
+	for _, o := range overrides {
+		r.Overrides = append(r.Overrides, o.String())
 	}
 
 	for _, entry := range m.Services {
@@ -191,22 +306,22 @@ func (r *Release) Promote() error {
 		if vals, ok := app.Parameters[fmt.Sprintf("%sFormation", UpperName(entry.Name))]; ok {
 			parts := strings.SplitN(vals, ",", 3)
 			if len(parts) != 3 {
-				return fmt.Errorf("%s formation settings not in Count,Cpu,Memory format", entry.Name)
+				return nil, nil, fmt.Errorf("%s formation settings not in Count,Cpu,Memory format", entry.Name)
 			}
 
 			_, err = strconv.Atoi(parts[0])
 			if err != nil {
-				return fmt.Errorf("%s %s not numeric", entry.Name, "count")
+				return nil, nil, fmt.Errorf("%s %s not numeric", entry.Name, "count")
 			}
 
 			_, err = strconv.Atoi(parts[1])
 			if err != nil {
-				return fmt.Errorf("%s %s not numeric", entry.Name, "CPU")
+				return nil, nil, fmt.Errorf("%s %s not numeric", entry.Name, "CPU")
 			}
 
 			_, err = strconv.Atoi(parts[2])
 			if err != nil {
-				return fmt.Errorf("%s %s not numeric", entry.Name, "memory")
+				return nil, nil, fmt.Errorf("%s %s not numeric", entry.Name, "memory")
 			}
 
 			app.Parameters[fmt.Sprintf("%sDesiredCount", UpperName(entry.Name))] = parts[0]
@@ -230,6 +345,22 @@ func (r *Release) Promote() error {
 			app.Parameters[fmt.Sprintf("%sFormation", UpperName(entry.Name))] = strings.Join(parts, ",")
 		}
 
+		// scale overrides for this rack replace the formation on every promote
+		for _, o := range overrides {
+			i, ok := overrideScale[o.Key]
+			if !ok || o.Service != entry.Name {
+				continue
+			}
+
+			parts := strings.SplitN(app.Parameters[fmt.Sprintf("%sFormation", UpperName(entry.Name))], ",", 3)
+			parts[i] = o.Value
+
+			app.Parameters[fmt.Sprintf("%sDesiredCount", UpperName(entry.Name))] = parts[0]
+			app.Parameters[fmt.Sprintf("%sCpu", UpperName(entry.Name))] = parts[1]
+			app.Parameters[fmt.Sprintf("%sMemory", UpperName(entry.Name))] = parts[2]
+			app.Parameters[fmt.Sprintf("%sFormation", UpperName(entry.Name))] = strings.Join(parts, ",")
+		}
+
 		for _, mapping := range entry.Ports {
 			certParam := fmt.Sprintf("%sPort%dCertificate", UpperName(entry.Name), mapping.Balancer)
 			protoParam := fmt.Sprintf("%sPort%dProtocol", UpperName(entry.Name), mapping.Balancer)
@@ -241,32 +372,32 @@ func (r *Release) Promote() error {
 			// if the proto param is set to a non-default value and doesnt match the label, error
 			if ap, ok := app.Parameters[protoParam]; ok {
 				if ap != "tcp" && ap != proto {
-					return fmt.Errorf("%s parameter has been deprecated. Please set the convox.port.%d.protocol label instead", protoParam, mapping.Balancer)
+					return nil, nil, fmt.Errorf("%s parameter has been deprecated. Please set the convox.port.%d.protocol label instead", protoParam, mapping.Balancer)
 				}
 			}
 
 			// if the proxy param is set and doesnt match the label, error
 			if ap, ok := app.Parameters[proxyParam]; ok {
 				if ap == "Yes" && entry.Labels[fmt.Sprintf("convox.port.%d.proxy", mapping.Balancer)] != "true" {
-					return fmt.Errorf("%s parameter has been deprecated. Please set the convox.port.%d.proxy label instead", proxyParam, mapping.Balancer)
+					return nil, nil, fmt.Errorf("%s parameter has been deprecated. Please set the convox.port.%d.proxy label instead", proxyParam, mapping.Balancer)
 				}
 			}
 
 			// if the secure param is set and doesnt match the label, error
 			if ap, ok := app.Parameters[secureParam]; ok {
 				if ap == "Yes" && entry.Labels[fmt.Sprintf("convox.port.%d.secure", mapping.Balancer)] != "true" {
-					return fmt.Errorf("%s parameter has been deprecated. Please set the convox.port.%d.secure label instead", secureParam, mapping.Balancer)
+					return nil, nil, fmt.Errorf("%s parameter has been deprecated. Please set the convox.port.%d.secure label instead", secureParam, mapping.Balancer)
 				}
 			}
 
 			switch proto {
 			case "https", "tls":
-				if app.Parameters[certParam] == "" {
+				if app.Parameters[certParam] == "" && !preview {
 					name := fmt.Sprintf("cert-%s-%d-%05d", os.Getenv("RACK"), time.Now().Unix(), rand.Intn(100000))
 
 					body, key, err := generateSelfSignedCertificate("*.*.elb.amazonaws.com")
 					if err != nil {
-						return err
+						return nil, nil, err
 					}
 
 					input := &iam.UploadServerCertificateInput{
@@ -278,7 +409,7 @@ func (r *Release) Promote() error {
 					// upload certificate
 					res, err := IAM().UploadServerCertificate(input)
 					if err != nil {
-						return err
+						return nil, nil, err
 					}
 
 					app.Parameters[certParam] = *res.ServerCertificateMetadata.Arn
@@ -304,17 +435,17 @@ func (r *Release) Promote() error {
 		}
 	}
 
-	err = S3Put(app.Outputs["Settings"], fmt.Sprintf("templates/%s", r.Id), []byte(formation), false)
+	err = S3Put(app.Outputs["Settings"], fmt.Sprintf("templates/%s", key), []byte(formation), false)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	// loop until we can find the template
-	if err := waitForTemplate(app.Outputs["Settings"], r.Id); err != nil {
-		return fmt.Errorf("error waiting for template: %s", err)
+	if err := waitForTemplate(app.Outputs["Settings"], key); err != nil {
+		return nil, nil, fmt.Errorf("error waiting for template: %s", err)
 	}
 
-	url := fmt.Sprintf("https://s3.amazonaws.com/%s/templates/%s", app.Outputs["Settings"], r.Id)
+	url := fmt.Sprintf("https://s3.amazonaws.com/%s/templates/%s", app.Outputs["Settings"], key)
 
 	req := &cloudformation.UpdateStackInput{
 		Capabilities: []*string{aws.String("CAPABILITY_IAM")},
@@ -323,14 +454,52 @@ func (r *Release) Promote() error {
 		Parameters:   params,
 	}
 
-	_, err = UpdateStack(req)
+	return req, releases, nil
+}
 
-	NotifySuccess("release:promote", map[string]string{
-		"app": r.App,
-		"id":  r.Id,
-	})
+// armInstanceFamily matches the instance families with arm processors, i.e. a1, m6g, c6gn or t4g
+var armInstanceFamily = regexp.MustCompile(`^(a1|[a-z]+[0-9]+g[a-z]*)$`)
 
-	return err
+// instanceArchitecture returns the cpu architecture of an instance type
+func instanceArchitecture(instanceType string) string {
+	if armInstanceFamily.MatchString(strings.SplitN(instanceType, ".", 2)[0]) {
+		return "arm64"
+	}
+
+	return "amd64"
+}
+
+// buildVariant returns the tag of the images of a build to run on the instances of this rack.
+// Images of a matrix build are picked by the architecture of the instances, which is returned
+// so processes are only placed on instances of that architecture.
+func buildVariant(app, build string) (string, string, error) {
+	if build == "" {
+		return build, "", nil
+	}
+
+	b, err := Provider().BuildGet(app, build)
+	if err != nil {
+		return "", "", err
+	}
+
+	if len(b.Architectures) == 0 {
+		return build, "", nil
+	}
+
+	s, err := Provider().SystemGet()
+	if err != nil {
+		return "", "", err
+	}
+
+	arch := instanceArchitecture(s.Type)
+
+	for _, a := range b.Architectures {
+		if a == arch {
+			return fmt.Sprintf("%s-%s", build, arch), arch, nil
+		}
+	}
+
+	return "", "", fmt.Errorf("build %s has no images for %s instances, build with --matrix arch=%s", build, arch, arch)
 }
 
 func (r *Release) EnvironmentUrl() string {
@@ -345,12 +514,18 @@ func (r *Release) EnvironmentUrl() string {
 }
 
 func (r *Release) Formation() (string, error) {
+	return r.formation(nil)
+}
+
+// formation renders the stack template of the release with the processes in pins kept on the
+// image, configuration and environment of another release
+func (r *Release) formation(pins map[string]*Release) (string, error) {
 	app, err := GetApp(r.App)
 	if err != nil {
 		return "", err
 	}
 
-	manifest, err := manifest.Load([]byte(r.Manifest))
+	manifest, _, err := r.manifest()
 	if err != nil {
 		return "", err
 	}
@@ -390,13 +565,39 @@ func (r *Release) Formation() (string, error) {
 		}
 	}
 
+	tag, arch, err := buildVariant(r.App, r.Build)
+	if err != nil {
+		return "", err
+	}
+
 	// set the image
 	for i, entry := range manifest.Services {
 		s := manifest.Services[i]
-		s.Image = entry.RegistryImage(app.Name, r.Build, app.Outputs)
+		s.Image = entry.RegistryImage(app.Name, tag, app.Outputs)
+		s.Architecture = arch
 		manifest.Services[i] = s
 	}
 
+	for name, pr := range pins {
+		pm, _, err := pr.manifest()
+		if err != nil {
+			return "", err
+		}
+
+		tag, arch, err := buildVariant(pr.App, pr.Build)
+		if err != nil {
+			return "", err
+		}
+
+		s := pm.Services[name]
+		s.Image = s.RegistryImage(app.Name, tag, app.Outputs)
+		s.Architecture = arch
+		s.Primary = manifest.Services[name].Primary
+		s.Release = pr.Id
+		s.ReleaseEnvironment = pr.EnvironmentUrl()
+		manifest.Services[name] = s
+	}
+
 	manifest, err = r.resolveLinks(*app, manifest)
 	if err != nil {
 		return "", err
@@ -405,6 +606,21 @@ func (r *Release) Formation() (string, error) {
 	return app.Formation(*manifest)
 }
 
+// manifest loads the manifest of the release with the overrides for this rack applied
+func (r *Release) manifest() (*manifest.Manifest, manifest.Overrides, error) {
+	m, err := manifest.Load([]byte(r.Manifest))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	overrides, err := m.ApplyOverrides(os.Getenv("RACK"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return m, overrides, nil
+}
+
 func (r *Release) resolveLinks(app App, manifest *manifest.Manifest) (*manifest.Manifest, error) {
 	m := *manifest
 
@@ -553,6 +769,51 @@ func primaryProcess(stackName string) (string, error) {
 	return "", nil
 }
 
+// checkSecrets returns an error if the environment of the release or its manifest
+// refers to secrets the app does not have
+func (r *Release) checkSecrets() error {
+	env := LoadEnvironment([]byte(r.Env))
+
+	if m, err := manifest.Load([]byte(r.Manifest)); err == nil {
+		for name, s := range m.Services {
+			for key, value := range s.Environment {
+				env[fmt.Sprintf("%s/%s", name, key)] = value
+			}
+		}
+	}
+
+	refs := SecretReferences(env)
+
+	if len(refs) == 0 {
+		return nil
+	}
+
+	names, err := ListSecrets(r.App)
+	if err != nil {
+		return err
+	}
+
+	exists := map[string]bool{}
+
+	for _, name := range names {
+		exists[name] = true
+	}
+
+	missing := []string{}
+
+	for _, ref := range refs {
+		if !exists[ref] {
+			missing = append(missing, ref)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("release refers to missing secrets: %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
 func releasesTable(app string) string {
 	return os.Getenv("DYNAMO_RELEASES")
 }
@@ -567,11 +828,45 @@ func releaseFromItem(item map[string]*dynamodb.AttributeValue) *Release {
 		Env:      coalesce(item["env"], ""),
 		Manifest: coalesce(item["manifest"], ""),
 		Created:  created,
+
+		CreatedBy: coalesce(item["created-by"], ""),
 	}
 
 	return release
 }
 
+// releaseEnvItem encrypts a release environment with the rack key when one is configured
+func releaseEnvItem(env string) (*dynamodb.AttributeValue, error) {
+	key := os.Getenv("ENCRYPTION_KEY")
+
+	if key == "" {
+		return &dynamodb.AttributeValue{S: aws.String(env)}, nil
+	}
+
+	data, err := crypt.New(os.Getenv("AWS_REGION"), os.Getenv("AWS_ACCESS"), os.Getenv("AWS_SECRET")).Encrypt(key, []byte(env))
+	if err != nil {
+		return nil, err
+	}
+
+	return &dynamodb.AttributeValue{B: data}, nil
+}
+
+// releaseEnv returns the environment of a release item, decrypting it if it was saved encrypted
+func releaseEnv(item map[string]*dynamodb.AttributeValue) (string, error) {
+	av := item["env-encrypted"]
+
+	if av == nil || av.B == nil {
+		return coalesce(item["env"], ""), nil
+	}
+
+	data, err := crypt.New(os.Getenv("AWS_REGION"), os.Getenv("AWS_ACCESS"), os.Getenv("AWS_SECRET")).Decrypt(os.Getenv("ENCRYPTION_KEY"), av.B)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
 func waitForTemplate(bucket string, id string) error {
 	tick := time.Tick(1 * time.Second)
 	timeout := time.Tick(1 * time.Minute)
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/ecs"
+)
+
+// PromotionTimeout is how long a synchronous promote waits for a release to become healthy
+const PromotionTimeout = 10 * time.Minute
+
+// promotionPollInterval is how often a synchronous promote checks the stack and its services
+var promotionPollInterval = 10 * time.Second
+
+// WaitForPromotion blocks until the app stack has finished updating to the release and every
+// service runs its desired count on a single deployment. A release that does not become
+// healthy within timeout has its update cancelled so the app rolls back to the previous release.
+func (r *Release) WaitForPromotion(timeout time.Duration) error {
+	started := time.Now()
+	deadline := time.After(timeout)
+	tick := time.Tick(promotionPollInterval)
+
+	for {
+		select {
+		case <-tick:
+			a, err := GetApp(r.App)
+			if err != nil {
+				return err
+			}
+
+			if a.Status != "running" {
+				continue
+			}
+
+			if a.Release != r.Id {
+				return fmt.Errorf("release %s failed to promote: %s", r.Id, stackFailureReason(a.StackName(), started))
+			}
+
+			services, err := GetAppServices(r.App)
+			if err != nil {
+				return err
+			}
+
+			if servicesHealthy(services) {
+				return nil
+			}
+		case <-deadline:
+			a, err := GetApp(r.App)
+			if err != nil {
+				return err
+			}
+
+			if a.Status == "updating" {
+				if err := a.CancelUpdate(); err != nil {
+					return err
+				}
+
+				return fmt.Errorf("release %s did not become healthy within %s, rolling back", r.Id, timeout)
+			}
+
+			return fmt.Errorf("release %s did not become healthy within %s", r.Id, timeout)
+		}
+	}
+}
+
+// servicesHealthy returns true once every service has replaced its old tasks and runs its desired count
+func servicesHealthy(services []*ecs.Service) bool {
+	for _, s := range services {
+		if len(s.Deployments) != 1 {
+			return false
+		}
+
+		if *s.RunningCount != *s.DesiredCount {
+			return false
+		}
+	}
+
+	return true
+}
+
+// UpdateFailure describes the first resource that failed to update the app stack since a time
+func (a *App) UpdateFailure(since time.Time) string {
+	return stackFailureReason(a.StackName(), since)
+}
+
+// stackFailureReason describes the first resource that failed to update since a time
+func stackFailureReason(stack string, since time.Time) string {
+	res, err := CloudFormation().DescribeStackEvents(&cloudformation.DescribeStackEventsInput{
+		StackName: aws.String(stack),
+	})
+	if err != nil {
+		return "update rolled back"
+	}
+
+	return failedStackEvent(res.StackEvents, since)
+}
+
+// failedStackEvent returns the reason of the oldest failed event after since from events sorted newest first
+func failedStackEvent(events []*cloudformation.StackEvent, since time.Time) string {
+	reason := "update rolled back"
+
+	for _, e := range events {
+		if e.Timestamp.Before(since) {
+			break
+		}
+
+		if strings.HasSuffix(*e.ResourceStatus, "_FAILED") && e.ResourceStatusReason != nil {
+			reason = fmt.Sprintf("%s: %s", *e.LogicalResourceId, *e.ResourceStatusReason)
+		}
+	}
+
+	return reason
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServicesHealthy(t *testing.T) {
+	deployment := &ecs.Deployment{Status: aws.String("PRIMARY")}
+	old := &ecs.Deployment{Status: aws.String("ACTIVE")}
+
+	healthy := &ecs.Service{
+		Deployments:  []*ecs.Deployment{deployment},
+		DesiredCount: aws.Int64(2),
+		RunningCount: aws.Int64(2),
+	}
+
+	draining := &ecs.Service{
+		Deployments:  []*ecs.Deployment{deployment, old},
+		DesiredCount: aws.Int64(2),
+		RunningCount: aws.Int64(2),
+	}
+
+	starting := &ecs.Service{
+		Deployments:  []*ecs.Deployment{deployment},
+		DesiredCount: aws.Int64(2),
+		RunningCount: aws.Int64(1),
+	}
+
+	assert.True(t, servicesHealthy([]*ecs.Service{}))
+	assert.True(t, servicesHealthy([]*ecs.Service{healthy}))
+	assert.False(t, servicesHealthy([]*ecs.Service{healthy, draining}))
+	assert.False(t, servicesHealthy([]*ecs.Service{starting, healthy}))
+}
+
+func TestFailedStackEvent(t *testing.T) {
+	since := time.Date(2016, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	events := []*cloudformation.StackEvent{
+		{
+			LogicalResourceId: aws.String("myapp"),
+			ResourceStatus:    aws.String("UPDATE_ROLLBACK_IN_PROGRESS"),
+			Timestamp:         aws.Time(since.Add(3 * time.Minute)),
+		},
+		{
+			LogicalResourceId:    aws.String("ServiceWeb"),
+			ResourceStatus:       aws.String("UPDATE_FAILED"),
+			ResourceStatusReason: aws.String("Resource update cancelled"),
+			Timestamp:            aws.Time(since.Add(2 * time.Minute)),
+		},
+		{
+			LogicalResourceId:    aws.String("ServiceWorker"),
+			ResourceStatus:       aws.String("UPDATE_FAILED"),
+			ResourceStatusReason: aws.String("ECS Service did not stabilize"),
+			Timestamp:            aws.Time(since.Add(1 * time.Minute)),
+		},
+		{
+			LogicalResourceId:    aws.String("ServiceWeb"),
+			ResourceStatus:       aws.String("UPDATE_FAILED"),
+			ResourceStatusReason: aws.String("an earlier failure"),
+			Timestamp:            aws.Time(since.Add(-1 * time.Minute)),
+		},
+	}
+
+	assert.Equal(t, "ServiceWorker: ECS Service did not stabilize", failedStackEvent(events, since))
+	assert.Equal(t, "update rolled back", failedStackEvent(events[0:1], since))
+}
@@ -0,0 +1,160 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// processReleasesKey holds the processes of an app that a partial promote left on an older
+// release than the one the app is on
+const processReleasesKey = "process-releases.json"
+
+// ProcessReleases returns the processes of an app that run a release other than the active
+// release of the app and the release each of them runs
+func (a *App) ProcessReleases() (map[string]string, error) {
+	releases := map[string]string{}
+
+	data, err := s3Get(a.settingsBucket(), processReleasesKey)
+	if awserrCode(err) == "NoSuchKey" {
+		return releases, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &releases); err != nil {
+		return nil, err
+	}
+
+	return releases, nil
+}
+
+// BuildsInUse returns the builds of every release running on an app: the active release, the
+// releases processes were left on by a partial promote and the release of a running canary
+func (a *App) BuildsInUse() ([]string, error) {
+	releases := map[string]bool{}
+
+	if a.Release != "" {
+		releases[a.Release] = true
+	}
+
+	pins, err := a.ProcessReleases()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, release := range pins {
+		releases[release] = true
+	}
+
+	c, err := GetCanary(a.Name)
+	if err != nil && !strings.HasPrefix(err.Error(), "no canary") {
+		return nil, err
+	}
+
+	if c != nil {
+		releases[c.Release] = true
+	}
+
+	builds := []string{}
+
+	for id := range releases {
+		r, err := GetRelease(a.Name, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if r.Build != "" {
+			builds = append(builds, r.Build)
+		}
+	}
+
+	sort.Strings(builds)
+
+	return builds, nil
+}
+
+func (a *App) saveProcessReleases(releases map[string]string) error {
+	if len(releases) == 0 {
+		return s3Delete(a.settingsBucket(), processReleasesKey)
+	}
+
+	data, err := json.Marshal(releases)
+	if err != nil {
+		return err
+	}
+
+	return S3Put(a.settingsBucket(), processReleasesKey, data, false)
+}
+
+// processPins returns the releases the processes of an app that are not being promoted stay
+// on, and the processes that will be left on another release once the promote is done
+func (r *Release) processPins(a *App, processes []string) (map[string]*Release, map[string]string, error) {
+	m, _, err := r.manifest()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	selected := map[string]bool{}
+
+	for _, p := range processes {
+		if _, ok := m.Services[p]; !ok {
+			return nil, nil, fmt.Errorf("release %s has no process: %s", r.Id, p)
+		}
+
+		selected[p] = true
+	}
+
+	current, err := a.ProcessReleases()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pins := map[string]*Release{}
+	releases := map[string]string{}
+	loaded := map[string]*Release{}
+
+	for name := range m.Services {
+		if selected[name] {
+			continue
+		}
+
+		id := current[name]
+
+		if id == "" {
+			id = a.Release
+		}
+
+		if id == "" || id == r.Id {
+			continue
+		}
+
+		pr, ok := loaded[id]
+
+		if !ok {
+			pr, err = GetRelease(a.Name, id)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			loaded[id] = pr
+		}
+
+		pm, _, err := pr.manifest()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// a process that is new in the release has no older release to stay on
+		if _, ok := pm.Services[name]; !ok {
+			continue
+		}
+
+		pins[name] = pr
+		releases[name] = id
+	}
+
+	return pins, releases, nil
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// ReleasePromotion is a recorded deployment of a release
+type ReleasePromotion struct {
+	Release string    `json:"release"`
+	User    string    `json:"user"`
+	Created time.Time `json:"created"`
+
+	// Rack is the rack the release was promoted on and Overrides the manifest overrides for it
+	Rack      string   `json:"rack,omitempty"`
+	Overrides []string `json:"overrides,omitempty"`
+
+	// Processes are the only processes a partial promote updated to the release
+	Processes []string `json:"processes,omitempty"`
+}
+
+// ReleasePromotions are sorted with the latest promotion first
+type ReleasePromotions []ReleasePromotion
+
+func (ps ReleasePromotions) Len() int           { return len(ps) }
+func (ps ReleasePromotions) Less(i, j int) bool { return ps[i].Created.After(ps[j].Created) }
+func (ps ReleasePromotions) Swap(i, j int)      { ps[i], ps[j] = ps[j], ps[i] }
+
+// ReleasePromotions returns every recorded promotion of a release, latest first
+func (a *App) ReleasePromotions(release string) (ReleasePromotions, error) {
+	data, err := s3Get(a.settingsBucket(), releasePromotionsKey(release))
+	if awserrCode(err) == "NoSuchKey" {
+		return ReleasePromotions{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var promotions ReleasePromotions
+
+	if err := json.Unmarshal(data, &promotions); err != nil {
+		return nil, err
+	}
+
+	sort.Sort(promotions)
+
+	return promotions, nil
+}
+
+// RecordReleasePromotion adds a promotion by user to a release's deployment history
+func (a *App) RecordReleasePromotion(r *Release, user string) error {
+	promotions, err := a.ReleasePromotions(r.Id)
+	if err != nil {
+		return err
+	}
+
+	promotions = append(promotions, ReleasePromotion{
+		Release:   r.Id,
+		User:      user,
+		Created:   time.Now().UTC(),
+		Rack:      os.Getenv("RACK"),
+		Overrides: r.Overrides,
+		Processes: r.Processes,
+	})
+
+	sort.Sort(promotions)
+
+	data, err := json.Marshal(promotions)
+	if err != nil {
+		return err
+	}
+
+	return S3Put(a.settingsBucket(), releasePromotionsKey(r.Id), data, false)
+}
+
+func releasePromotionsKey(release string) string {
+	return fmt.Sprintf("releases/%s/promotions.json", release)
+}
@@ -8,7 +8,9 @@ import (
 	"testing"
 
 	"github.com/aryann/difflib"
+	"github.com/convox/rack/api/structs"
 	"github.com/convox/rack/test"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -82,3 +84,50 @@ func TestLinks(t *testing.T) {
 
 	Diff(t, "web_redis", string(fixData), formation)
 }
+
+func TestInstanceArchitecture(t *testing.T) {
+	for instanceType, arch := range map[string]string{
+		"t2.small":   "amd64",
+		"g4dn.large": "amd64",
+		"a1.large":   "arm64",
+		"m6g.xlarge": "arm64",
+		"c6gn.large": "arm64",
+		"t4g.micro":  "arm64",
+	} {
+		assert.Equal(t, arch, instanceArchitecture(instanceType), instanceType)
+	}
+}
+
+func TestBuildVariant(t *testing.T) {
+	os.Setenv("PROVIDER", "test")
+
+	Test(t, func() {
+		TestProvider.Build = structs.Build{Id: "B1"}
+		TestProvider.On("BuildGet", "myapp", "B1").Return(nil, nil)
+
+		tag, arch, err := buildVariant("myapp", "B1")
+		assert.NoError(t, err)
+		assert.Equal(t, "B1", tag)
+		assert.Equal(t, "", arch)
+	})
+
+	Test(t, func() {
+		TestProvider.Build = structs.Build{Id: "B1", Architectures: []string{"amd64", "arm64"}}
+		TestProvider.On("BuildGet", "myapp", "B1").Return(nil, nil)
+		TestProvider.On("SystemGet").Return(&structs.System{Type: "m6g.large"}, nil)
+
+		tag, arch, err := buildVariant("myapp", "B1")
+		assert.NoError(t, err)
+		assert.Equal(t, "B1-arm64", tag)
+		assert.Equal(t, "arm64", arch)
+	})
+
+	Test(t, func() {
+		TestProvider.Build = structs.Build{Id: "B1", Architectures: []string{"arm64"}}
+		TestProvider.On("BuildGet", "myapp", "B1").Return(nil, nil)
+		TestProvider.On("SystemGet").Return(&structs.System{Type: "t2.small"}, nil)
+
+		_, _, err := buildVariant("myapp", "B1")
+		assert.EqualError(t, err, "build B1 has no images for amd64 instances, build with --matrix arch=amd64")
+	})
+}
@@ -0,0 +1,210 @@
+package models
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/signer/v4"
+)
+
+// route53 is not part of the vendored aws sdk so the few calls dns challenges need are signed
+// and sent directly to its rest api
+const route53Endpoint = "https://route53.amazonaws.com/2013-04-01"
+
+type route53HostedZone struct {
+	Id   string `xml:"Id"`
+	Name string `xml:"Name"`
+}
+
+type route53HostedZones struct {
+	HostedZones []route53HostedZone `xml:"HostedZones>HostedZone"`
+	IsTruncated bool                `xml:"IsTruncated"`
+	NextMarker  string              `xml:"NextMarker"`
+}
+
+type route53ChangeInfo struct {
+	Id     string `xml:"ChangeInfo>Id"`
+	Status string `xml:"ChangeInfo>Status"`
+}
+
+type route53Error struct {
+	Code    string `xml:"Error>Code"`
+	Message string `xml:"Error>Message"`
+}
+
+// route53Zone returns the id of the most specific hosted zone that contains domain
+func route53Zone(domain string) (string, error) {
+	name := strings.TrimSuffix(domain, ".") + "."
+	best := route53HostedZone{}
+	marker := ""
+
+	for {
+		path := "/hostedzone"
+
+		if marker != "" {
+			path = fmt.Sprintf("%s?marker=%s", path, url.QueryEscape(marker))
+		}
+
+		var zones route53HostedZones
+
+		if err := route53Request("GET", path, nil, &zones); err != nil {
+			return "", err
+		}
+
+		for _, z := range zones.HostedZones {
+			if (name == z.Name || strings.HasSuffix(name, "."+z.Name)) && len(z.Name) > len(best.Name) {
+				best = z
+			}
+		}
+
+		if !zones.IsTruncated {
+			break
+		}
+
+		marker = zones.NextMarker
+	}
+
+	if best.Id == "" {
+		return "", fmt.Errorf("no route53 hosted zone found for %s", domain)
+	}
+
+	return strings.TrimPrefix(best.Id, "/hostedzone/"), nil
+}
+
+type route53ChangeRequest struct {
+	XMLName xml.Name              `xml:"https://route53.amazonaws.com/doc/2013-04-01/ ChangeResourceRecordSetsRequest"`
+	Changes []route53RecordChange `xml:"ChangeBatch>Changes>Change"`
+}
+
+type route53RecordChange struct {
+	Action string           `xml:"Action"`
+	Record route53RecordSet `xml:"ResourceRecordSet"`
+}
+
+type route53RecordSet struct {
+	Name        string              `xml:"Name"`
+	Type        string              `xml:"Type"`
+	TTL         int                 `xml:"TTL,omitempty"`
+	Records     *route53Records     `xml:"ResourceRecords,omitempty"`
+	AliasTarget *route53AliasTarget `xml:"AliasTarget,omitempty"`
+}
+
+type route53Records struct {
+	Values []string `xml:"ResourceRecord>Value"`
+}
+
+type route53AliasTarget struct {
+	HostedZoneId         string `xml:"HostedZoneId"`
+	DNSName              string `xml:"DNSName"`
+	EvaluateTargetHealth bool   `xml:"EvaluateTargetHealth"`
+}
+
+// route53ChangeTXT creates, updates or deletes a TXT record and waits for the change to reach
+// all of the route53 name servers
+func route53ChangeTXT(zone, action, name, value string) error {
+	record := route53RecordSet{
+		Name:    name,
+		Type:    "TXT",
+		TTL:     60,
+		Records: &route53Records{Values: []string{fmt.Sprintf("%q", value)}},
+	}
+
+	change, err := route53Change(zone, action, record)
+	if err != nil {
+		return err
+	}
+
+	// a deleted record does not need to be waited on
+	if action == "DELETE" {
+		return nil
+	}
+
+	deadline := time.Now().Add(5 * time.Minute)
+
+	for {
+		synced, err := route53ChangeSynced(change)
+		if err != nil {
+			return err
+		}
+
+		if synced {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timeout waiting for route53 change %s", change)
+		}
+
+		time.Sleep(5 * time.Second)
+	}
+}
+
+// route53Change makes a change to a record of a hosted zone and returns the id of the change
+func route53Change(zone, action string, record route53RecordSet) (string, error) {
+	req := route53ChangeRequest{
+		Changes: []route53RecordChange{{Action: action, Record: record}},
+	}
+
+	data, err := xml.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+
+	var change route53ChangeInfo
+
+	if err := route53Request("POST", fmt.Sprintf("/hostedzone/%s/rrset", zone), append([]byte(xml.Header), data...), &change); err != nil {
+		return "", err
+	}
+
+	return strings.TrimPrefix(change.Id, "/change/"), nil
+}
+
+// route53ChangeSynced returns true once a change has reached all of the route53 name servers
+func route53ChangeSynced(id string) (bool, error) {
+	var change route53ChangeInfo
+
+	if err := route53Request("GET", fmt.Sprintf("/change/%s", id), nil, &change); err != nil {
+		return false, err
+	}
+
+	return change.Status == "INSYNC", nil
+}
+
+func route53Request(method, path string, body []byte, out interface{}) error {
+	req, err := http.NewRequest(method, route53Endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "text/xml")
+	}
+
+	if _, err := v4.NewSigner(awsConfig().Credentials).Sign(req, bytes.NewReader(body), "route53", "us-east-1", time.Now()); err != nil {
+		return err
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode >= 400 {
+		var e route53Error
+
+		if err := xml.NewDecoder(res.Body).Decode(&e); err != nil || e.Message == "" {
+			return fmt.Errorf("route53 response status %d", res.StatusCode)
+		}
+
+		return fmt.Errorf("route53 %s: %s", e.Code, e.Message)
+	}
+
+	return xml.NewDecoder(res.Body).Decode(out)
+}
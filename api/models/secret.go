@@ -0,0 +1,175 @@
+package models
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/convox/rack/api/crypt"
+)
+
+// secretName are the names a secret can be referenced by as ${secret:NAME}
+var secretName = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// SecretReference matches an environment value that refers to a secret. The value is resolved
+// by the entrypoint of the image when a process starts so it is never stored in a release
+// or a task definition.
+var SecretReference = regexp.MustCompile(`^\$\{secret:([A-Za-z0-9_.-]+)\}$`)
+
+// ListSecrets returns the names of the secrets of an app
+func ListSecrets(app string) ([]string, error) {
+	a, err := GetApp(app)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := s3Keys(a.settingsBucket(), "secrets/")
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+
+	for _, key := range keys {
+		names = append(names, strings.TrimPrefix(key, "secrets/"))
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// GetSecret returns the decrypted value of a secret
+func GetSecret(app, name string) (string, error) {
+	a, err := GetApp(app)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := s3Get(a.settingsBucket(), secretKey(name))
+	if awserrCode(err) == "NoSuchKey" {
+		return "", fmt.Errorf("no such secret: %s", name)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	value, err := secretCrypt().Decrypt(os.Getenv("ENCRYPTION_KEY"), data)
+	if err != nil {
+		return "", err
+	}
+
+	return string(value), nil
+}
+
+// SetSecret encrypts a value with the rack key and stores it as a secret of an app
+func SetSecret(app, name, value string) error {
+	if !secretName.MatchString(name) {
+		return fmt.Errorf("secret names may only contain letters, numbers, dashes, dots and underscores")
+	}
+
+	key := os.Getenv("ENCRYPTION_KEY")
+
+	if key == "" {
+		return fmt.Errorf("rack has no encryption key to protect secrets with")
+	}
+
+	a, err := GetApp(app)
+	if err != nil {
+		return err
+	}
+
+	data, err := secretCrypt().Encrypt(key, []byte(value))
+	if err != nil {
+		return err
+	}
+
+	if err := S3Put(a.settingsBucket(), secretKey(name), data, false); err != nil {
+		return err
+	}
+
+	NotifySuccess("secret:set", map[string]string{"app": app, "name": name})
+
+	return nil
+}
+
+// DeleteSecret removes a secret of an app
+func DeleteSecret(app, name string) error {
+	a, err := GetApp(app)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s3Get(a.settingsBucket(), secretKey(name)); awserrCode(err) == "NoSuchKey" {
+		return fmt.Errorf("no such secret: %s", name)
+	}
+
+	if err := s3Delete(a.settingsBucket(), secretKey(name)); err != nil {
+		return err
+	}
+
+	NotifySuccess("secret:delete", map[string]string{"app": app, "name": name})
+
+	return nil
+}
+
+// SecretReferences returns the sorted names of the secrets an environment refers to
+func SecretReferences(env Environment) []string {
+	refs := map[string]bool{}
+
+	for _, value := range env {
+		if m := SecretReference.FindStringSubmatch(value); m != nil {
+			refs[m[1]] = true
+		}
+	}
+
+	names := []string{}
+
+	for name := range refs {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// resolveSecrets replaces the secret references in env with their values
+func resolveSecrets(app string, env map[string]string) error {
+	for key, value := range env {
+		if m := SecretReference.FindStringSubmatch(value); m != nil {
+			secret, err := GetSecret(app, m[1])
+			if err != nil {
+				return err
+			}
+
+			env[key] = secret
+		}
+	}
+
+	return nil
+}
+
+func secretCrypt() *crypt.Crypt {
+	return crypt.New(os.Getenv("AWS_REGION"), os.Getenv("AWS_ACCESS"), os.Getenv("AWS_SECRET"))
+}
+
+func secretKey(name string) string {
+	return fmt.Sprintf("secrets/%s", name)
+}
+
+// secretsBucketEnvironment points the entrypoint of an image at the bucket holding the secrets of an app
+func secretsBucketEnvironment(env []*ecs.KeyValuePair, bucket string) []*ecs.KeyValuePair {
+	for _, kv := range env {
+		if *kv.Name == "SECRETS_BUCKET" {
+			kv.Value = aws.String(bucket)
+			return env
+		}
+	}
+
+	return append(env, &ecs.KeyValuePair{Name: aws.String("SECRETS_BUCKET"), Value: aws.String(bucket)})
+}
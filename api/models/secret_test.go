@@ -0,0 +1,24 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecretReferences(t *testing.T) {
+	env := Environment{
+		"DATABASE_PASSWORD": "${secret:db-password}",
+		"API_TOKEN":         "${secret:api.token}",
+		"TOKEN_COPY":        "${secret:api.token}",
+		"PLAIN":             "value",
+		"EMBEDDED":          "prefix-${secret:db-password}",
+		"ESCAPED":           "$${secret:db-password}",
+	}
+
+	assert.Equal(t, []string{"api.token", "db-password"}, SecretReferences(env))
+}
+
+func TestSetSecretInvalidName(t *testing.T) {
+	assert.EqualError(t, SetSecret("myapp", "db password", "x"), "secret names may only contain letters, numbers, dashes, dots and underscores")
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// SignaturesAvailable returns true if the rack has a table to remember request signatures in.
+// Racks installed before the table existed only accept basic auth.
+func SignaturesAvailable() bool {
+	return os.Getenv("DYNAMO_SIGNATURES") != ""
+}
+
+// RecordSignature remembers the signature of a request until expires, when the table lets it
+// lapse, and returns false if it was already recorded. The table is shared by every rack host
+// so a signed request can not be replayed against another host or after a restart.
+func RecordSignature(signature string, expires time.Time) (bool, error) {
+	if !SignaturesAvailable() {
+		return false, fmt.Errorf("signed requests are not available, update your rack with `convox rack update`")
+	}
+
+	_, err := DynamoDB().PutItem(&dynamodb.PutItemInput{
+		ConditionExpression: aws.String("attribute_not_exists(signature)"),
+		Item: map[string]*dynamodb.AttributeValue{
+			"signature": &dynamodb.AttributeValue{S: aws.String(signature)},
+			"expires":   &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(expires.Unix(), 10))},
+		},
+		TableName: aws.String(os.Getenv("DYNAMO_SIGNATURES")),
+	})
+	if awserrCode(err) == "ConditionalCheckFailedException" {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
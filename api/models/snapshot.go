@@ -0,0 +1,232 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const snapshotKey = "snapshots/latest.json"
+
+// Snapshot is the configuration of the rack and its apps at a point in time
+type Snapshot struct {
+	Created time.Time                `json:"created"`
+	Rack    StackSnapshot            `json:"rack"`
+	Apps    map[string]StackSnapshot `json:"apps"`
+}
+
+// StackSnapshot is the configuration of a single stack. Only environment keys
+// are kept so that snapshots never contain secrets.
+type StackSnapshot struct {
+	Parameters  map[string]string `json:"parameters"`
+	Environment []string          `json:"environment,omitempty"`
+	Formation   map[string]string `json:"formation,omitempty"`
+}
+
+// TakeSnapshot captures the current configuration of the rack and its apps.
+// Apps that are mid-deploy keep their configuration from prev, which may be nil.
+func TakeSnapshot(prev *Snapshot) (*Snapshot, error) {
+	rack, err := GetApp(os.Getenv("RACK"))
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Snapshot{
+		Created: time.Now().UTC(),
+		Rack:    StackSnapshot{Parameters: rack.Parameters},
+		Apps:    map[string]StackSnapshot{},
+	}
+
+	apps, err := ListApps()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range apps {
+		// apps in transition do not have a stable configuration to compare
+		if a.Status != "running" {
+			if prev != nil {
+				if ss, ok := prev.Apps[a.Name]; ok {
+					s.Apps[a.Name] = ss
+				}
+			}
+
+			continue
+		}
+
+		ss := StackSnapshot{
+			Parameters: map[string]string{},
+			Formation:  map[string]string{},
+		}
+
+		for k, v := range a.Parameters {
+			// formation parameters are compared as scale below
+			if !strings.HasSuffix(k, "Formation") {
+				ss.Parameters[k] = v
+			}
+		}
+
+		env, err := GetEnvironment(a.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		for k := range env {
+			ss.Environment = append(ss.Environment, k)
+		}
+
+		sort.Strings(ss.Environment)
+
+		formation, err := Provider().FormationList(a.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, pf := range formation {
+			ss.Formation[pf.Name] = fmt.Sprintf("count=%d cpu=%d memory=%d", pf.Count, pf.CPU, pf.Memory)
+		}
+
+		s.Apps[a.Name] = ss
+	}
+
+	return s, nil
+}
+
+// LatestSnapshot returns the last saved snapshot or nil if there is none yet
+func LatestSnapshot() (*Snapshot, error) {
+	data, err := s3Get(os.Getenv("SETTINGS_BUCKET"), snapshotKey)
+	if awserrCode(err) == "NoSuchKey" {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var s Snapshot
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// Save stores the snapshot as the latest one along with a dated copy
+func (s *Snapshot) Save() error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	if err := S3Put(os.Getenv("SETTINGS_BUCKET"), fmt.Sprintf("snapshots/%s.json", s.Created.Format("20060102")), data, false); err != nil {
+		return err
+	}
+
+	return S3Put(os.Getenv("SETTINGS_BUCKET"), snapshotKey, data, false)
+}
+
+// Drift describes each difference between this snapshot and an earlier one
+func (s *Snapshot) Drift(prev *Snapshot) []string {
+	drift := stackDrift("rack", prev.Rack, s.Rack)
+
+	names := []string{}
+
+	for name := range prev.Apps {
+		names = append(names, name)
+	}
+
+	for name := range s.Apps {
+		if _, ok := prev.Apps[name]; !ok {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		before, existed := prev.Apps[name]
+		after, exists := s.Apps[name]
+
+		switch {
+		case !existed:
+			drift = append(drift, fmt.Sprintf("app %s: created", name))
+		case !exists:
+			drift = append(drift, fmt.Sprintf("app %s: deleted", name))
+		default:
+			drift = append(drift, stackDrift(fmt.Sprintf("app %s", name), before, after)...)
+		}
+	}
+
+	return drift
+}
+
+func stackDrift(prefix string, before, after StackSnapshot) []string {
+	drift := []string{}
+
+	for _, d := range mapDrift(before.Parameters, after.Parameters) {
+		drift = append(drift, fmt.Sprintf("%s: parameter %s", prefix, d))
+	}
+
+	for _, d := range mapDrift(before.Formation, after.Formation) {
+		drift = append(drift, fmt.Sprintf("%s: scale of %s", prefix, d))
+	}
+
+	keys := map[string]bool{}
+
+	for _, k := range before.Environment {
+		keys[k] = false
+	}
+
+	for _, k := range after.Environment {
+		if _, ok := keys[k]; !ok {
+			drift = append(drift, fmt.Sprintf("%s: environment key %s added", prefix, k))
+		}
+
+		keys[k] = true
+	}
+
+	for _, k := range before.Environment {
+		if !keys[k] {
+			drift = append(drift, fmt.Sprintf("%s: environment key %s removed", prefix, k))
+		}
+	}
+
+	return drift
+}
+
+func mapDrift(before, after map[string]string) []string {
+	names := []string{}
+
+	for k := range before {
+		names = append(names, k)
+	}
+
+	for k := range after {
+		if _, ok := before[k]; !ok {
+			names = append(names, k)
+		}
+	}
+
+	sort.Strings(names)
+
+	drift := []string{}
+
+	for _, k := range names {
+		old, existed := before[k]
+		value, exists := after[k]
+
+		switch {
+		case !existed:
+			drift = append(drift, fmt.Sprintf("%s added (%s)", k, value))
+		case !exists:
+			drift = append(drift, fmt.Sprintf("%s removed", k))
+		case old != value:
+			drift = append(drift, fmt.Sprintf("%s changed from %s to %s", k, old, value))
+		}
+	}
+
+	return drift
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotDrift(t *testing.T) {
+	prev := &Snapshot{
+		Rack: StackSnapshot{Parameters: map[string]string{"Autoscale": "Yes", "InstanceCount": "3"}},
+		Apps: map[string]StackSnapshot{
+			"api": {
+				Parameters:  map[string]string{"Internal": "No"},
+				Environment: []string{"DATABASE_URL", "DEBUG"},
+				Formation:   map[string]string{"web": "count=2 cpu=0 memory=256"},
+			},
+			"old": {},
+		},
+	}
+
+	s := &Snapshot{
+		Rack: StackSnapshot{Parameters: map[string]string{"Autoscale": "No", "InstanceCount": "3"}},
+		Apps: map[string]StackSnapshot{
+			"api": {
+				Parameters:  map[string]string{"Internal": "No"},
+				Environment: []string{"DATABASE_URL", "SECRET_KEY"},
+				Formation:   map[string]string{"web": "count=4 cpu=0 memory=256"},
+			},
+			"new": {},
+		},
+	}
+
+	assert.Equal(t, []string{
+		"rack: parameter Autoscale changed from Yes to No",
+		"app api: scale of web changed from count=2 cpu=0 memory=256 to count=4 cpu=0 memory=256",
+		"app api: environment key SECRET_KEY added",
+		"app api: environment key DEBUG removed",
+		"app new: created",
+		"app old: deleted",
+	}, s.Drift(prev))
+
+	assert.Empty(t, s.Drift(s))
+}
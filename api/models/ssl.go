@@ -163,6 +163,10 @@ func UpdateSSL(app, process string, port int, id string) (*SSL, error) {
 			ServerCertificateName: aws.String(id),
 		})
 
+		if awserrCode(err) == "NoSuchEntity" {
+			return nil, fmt.Errorf("no such certificate: %s", id)
+		}
+
 		if err != nil {
 			return nil, err
 		}
@@ -170,6 +174,11 @@ func UpdateSSL(app, process string, port int, id string) (*SSL, error) {
 		arn = *res.ServerCertificate.ServerCertificateMetadata.Arn
 	}
 
+	// an empty certificate parameter would remove ssl from the listener
+	if arn == "" {
+		return nil, fmt.Errorf("no such certificate: %s", id)
+	}
+
 	// update cloudformation
 	req := &cloudformation.UpdateStackInput{
 		StackName:           aws.String(a.StackName()),
@@ -68,7 +68,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _templatesAppTmpl = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\xb4\x3c\x6b\x6f\x1b\xbb\x95\xdf\xf3\x2b\x08\xe2\x2e\x9c\x14\xb2\xfc\xb8\xd8\xee\x76\xba\x59\xc0\x91\x95\x44\xad\x9d\x68\x25\x27\x17\xdb\xc4\x28\xe8\x19\x4a\x9a\x5a\x22\xe7\x92\x1c\xc7\xbe\x82\xfe\x7b\xc1\xc7\xcc\xf0\x29\xc9\x8f\xb4\xb8\x49\x34\x73\x78\x78\x78\x78\xde\x3c\x9c\xf5\x1a\x14\x78\x56\x12\x0c\x20\xaa\x2a\x08\x36\x9b\x57\x00\xac\xd7\xe0\x17\x54\x55\x20\x7b\x0b\xfa\x67\x55\xd5\x3d\x5c\x21\x52\xce\x30\x17\xea\xcd\x65\xf3\x43\xbf\x7e\x05\x00\x00\xf0\xec\xb7\xe9\x15\x5e\x55\x4b\x24\xf0\x7b\xca\x56\x48\x7c\xc5\x8c\x97\x94\x40\x90\x01\x78\x7a\x7c\x72\x7c\x78\xfc\x97\xc3\xe3\xbf\xc0\x9e\x06\x1f\x50\x52\x94\xa2\xa4\x84\xc3\xcc\xa0\x50\x33\x09\x83\x03\xc0\x1b\xb4\x44\x24\xc7\xec\x30\xef\x40\xfd\xb9\x83\x41\x15\xa3\x39\xe6\xfc\x51\x63\x18\x9e\x97\x5c\xb0\x87\x5d\x83\xe0\x88\x08\xcc\x08\x5a\x4a\x8a\x01\x7c\x4f\xb2\x6c\xf8\x7b\x8d\x96\x72\x05\xdf\xe4\x93\x09\x9e\xc1\xcc\x02\x03\x9b\x1e\x80\xff\x8f\x39\x04\xd7\x60\xd3\x6b\xb0\x8c\x59\x79\x87\x04\xde\x81\xa4\x81\x8a\xe3\x78\xb7\x44\xe4\x76\x8a\xf3\x9a\x95\xe2\xe1\x03\xa3\x75\x25\xd9\xbc\xb6\xd1\x81\x0c\x7c\x5b\x2b\x6c\x72\x03\x5c\x58\x89\x13\x5e\xeb\x75\x19\xa4\x70\x8c\x18\x5a\x61\x81\x99\x1a\xba\x7d\x47\x2a\x09\xfb\x88\xdd\x88\xc2\x37\x6b\x19\x2c\x6b\x2e\x30\xb3\xc4\x00\x00\x78\xf5\x50\x61\x4d\xb8\x60\x25\x99\xc3\x5e\xf7\xea\x1c\xcf\x50\xbd\x14\xea\xad\xfb\x9c\xe7\xac\xac\x44\x23\x73\xd0\xbc\xea\xb8\x76\x8e\xab\x25\x7d\x58\x61\x22\x2e\xd1\x7d\xb9\xaa\x57\x91\x39\x33\x00\x3f\xd5\xab\x1b\xcc\x62\x53\x2a\x49\x3e\x4e\x4d\x9a\x01\x68\xf0\x82\x0a\xb3\x1c\x13\x81\xe6\x18\xd0\x19\x30\x6c\xc0\x1c\x08\x0a\x6e\x31\xae\x00\xab\x09\x29\xc9\x1c\xfc\x58\x94\x4b\x0c\x0a\x45\x97\x5c\xe6\x36\x92\x4b\xf2\x44\x92\x4f\xb6\x93\xac\xf1\xbe\x1c\xc9\x43\x72\x57\x32\x4a\x24\xcd\x71\x62\xd3\x5b\xba\x65\x47\xa3\x1b\x6a\x2b\xe4\x7e\xf3\x38\x08\x3f\x93\xe5\x03\x40\xcb\x25\xfd\x01\x50\x2e\x97\x2b\x17\x2b\x16\x25\x07\xd2\x06\xce\x18\x5d\x81\x92\xf0\xb2\xc0\x40\x2c\x30\xf8\x3a\x1e\x24\x68\xfe\x44\xed\x17\x67\x12\x21\x2e\xbe\xa2\x65\x8d\xb5\x56\x2b\xfd\xed\x29\x38\x70\x1d\x2c\xe2\xef\xf8\xe1\x67\xf3\xc9\x32\x39\x4f\x60\xd3\x17\x8e\xc1\xb4\xbe\x21\x58\x70\x83\x48\xf2\x89\x57\x38\x2f\x67\x0f\x92\x2d\x87\x8a\x47\x4b\x8a\x0a\xd0\x98\x08\x80\x49\x51\xd1\x92\x08\xfe\x53\x78\x36\xc1\x4b\x8c\x78\x6c\x41\x2f\x6d\x33\x26\xb8\xa2\xbc\x14\x94\xc5\x36\xe9\x79\x93\x4d\x69\xcd\x72\x0c\x72\x5a\x60\xc0\xba\x69\x02\x12\x5c\xdb\xfd\xd2\x54\x5c\x2d\x30\xb8\x70\xb6\x8e\x9b\xf9\xc0\x5c\x4e\x08\x66\x94\xb5\x4a\x11\x21\x4e\x0b\x46\x82\xac\x8b\x92\x8b\xff\x39\xfb\x6d\x9a\x65\xc3\xc1\x69\x96\x69\xe0\x2c\x1b\x15\xff\xfb\x14\x52\xbf\x8e\x07\x80\xeb\xf9\xf6\xa3\x2a\x2d\xf7\x3f\x87\xb8\xca\xa8\xc7\x7e\x44\x36\x01\x92\x43\x9d\xa7\x7b\xaf\x27\xc3\xff\xfb\x32\x9a\x0c\xcf\xdf\x80\x0b\xb4\xba\x29\x10\x18\xd4\x5c\xd0\xd5\x15\xad\xca\x1c\x7c\x44\xa4\x58\x62\x06\x8c\x3a\x80\x06\xa3\x45\xe6\x65\x49\x2e\x30\x99\x8b\x85\x22\xf2\xc4\x7e\xe5\x19\x80\x90\xbe\xf1\x20\xc1\xb9\x8e\x69\x5f\xc7\x03\xc9\xb1\xa7\x32\x6c\x07\x83\xc6\x83\xc1\xe8\x7c\xf2\xe2\x22\x2f\x67\x96\x88\xe3\xd3\x3b\x51\xd1\x25\xaa\xaa\x92\xcc\x6d\xf9\x86\x63\xca\xc4\x98\x51\x41\x73\xea\x79\x9e\x85\x10\x95\x8e\xeb\xa4\x6c\x61\x82\x99\x05\x07\x3f\x5e\x5d\x8d\xa5\x49\x1b\x11\x2e\xa4\xa6\xc5\xde\x29\x5d\xc7\x29\x88\x29\xec\xb8\x63\xa6\xe3\xdb\xe7\x9b\x3e\x7b\x42\x67\x46\x91\x6f\x59\xdf\xd5\x20\xb9\x3c\xf3\x2a\x3d\xd9\x74\x7a\xe1\x4f\xb5\xdc\xb2\x34\x09\xfe\xbc\xa9\xc0\x26\xba\xdf\x13\xcc\x95\x55\x76\x36\xdc\x52\xb9\x09\x5d\x26\xdc\xa8\xd2\x89\xd1\xd9\x65\x96\x29\x18\x6b\x25\x63\x46\x2b\xcc\x44\x89\x5d\x2b\x29\xdd\x1e\xe7\xf5\x0a\x4b\xf8\x31\x5d\x96\xf9\xc3\x39\xcd\xeb\x20\x6e\xf2\x6c\x85\xcc\xa5\x4e\x0f\x4f\x8e\x0f\x4f\xfe\xcb\x9a\x44\x01\x4d\x05\x12\xd8\x8c\xff\xe6\xbc\x02\x1e\x3e\x05\x3e\x9c\xcd\x70\xae\x9c\xb1\x72\xbf\x1e\x36\x43\x7a\x49\xf2\xb2\x6a\x52\x9e\x29\x66\x77\x65\x8e\xb5\x83\x5e\x2a\x7b\xd4\x47\x2b\xf4\x07\x25\xe8\x07\xef\xe7\x74\xe5\x64\x29\xf6\x42\x73\x63\xd0\xbe\x01\xc8\x05\xcf\xba\x85\x77\xde\xbd\xf9\xdf\xc6\xf9\x6d\xbf\x75\x30\xc3\x31\x12\x0b\x49\xfc\x51\x4e\xc9\x1d\xbd\x3f\x82\xee\x5b\xc9\x50\xcd\x72\x97\x15\x3e\x23\x34\xe4\xc3\x27\xb4\xd2\xdb\x58\xac\x4a\x22\xd3\x41\x24\x28\x0b\x58\x02\x77\xec\x13\xd8\x77\xaf\x40\xb0\x5f\x92\xbf\xc1\x8e\x58\x9c\x83\x7f\x92\x3f\x1b\xf9\xd4\x0f\xc0\x66\x07\xf7\xec\x5f\x1d\xe4\x26\xb0\xb4\x96\x84\x6f\x91\x6e\xed\x81\xb2\xec\x7d\x4d\x34\x55\x7b\x09\xf9\x80\x16\x38\x14\xe8\xe9\xaf\xef\xea\xfc\x16\x8b\x2e\x0d\xfe\x1b\x2d\x8d\x84\x1c\xc2\x9e\xfc\x4b\xef\x2b\xec\x59\x59\xb1\x22\x63\x82\xe7\xca\x92\x6f\xc0\x75\x28\x6e\x70\xfa\xab\x09\xa8\x7d\xac\x1a\x29\xd3\xae\xf2\xc8\x41\xdb\x96\x2a\x64\x62\x7c\xa4\x05\xfb\x68\xa6\xaa\x18\x25\x25\xfd\x3f\xca\x0a\xea\xb9\x92\xc2\x68\x3c\xb1\x44\x56\x92\x02\xdf\xf7\xf1\xbd\x49\x4d\x1c\xb0\x4b\xbc\xa2\xec\x61\x5a\xfe\xa1\x98\x7a\x72\xfa\xdf\xee\xeb\xc6\xba\x68\xd2\x3f\x60\x71\x26\xb4\x6c\x04\x26\x48\x4a\x06\x23\x81\xba\xc1\x49\x4d\x44\xa9\x25\x99\xd0\x02\xff\x8b\xbb\x13\x5c\x95\x2b\x4c\x6b\x25\x61\xbf\x1e\x1f\xc3\xb4\x44\xc4\xf3\x7e\xd6\x5a\x47\xd0\x4f\xa4\xfc\x39\xa3\xe4\x5f\xf4\x66\x1f\xd0\xa6\x3a\x60\x83\xee\x59\x50\xe0\xda\x10\x6d\x41\xde\x16\x75\x52\xd8\x63\x83\x9a\xc8\x17\x26\x90\x72\xa1\x4b\x32\xae\xcf\xf8\x5c\x8b\xaa\x16\xbb\xeb\x58\xd4\xc0\x81\xfe\xf6\xc5\x75\x70\xfb\x16\xae\xe2\x23\xba\xfc\x41\x08\x2f\x86\x91\x56\x4a\xe6\x5a\x5a\xd8\x8c\x16\xb4\x70\xbe\x6f\x7c\x25\xff\x5b\xaf\x65\x4e\xa7\xf0\x5a\xa5\xc3\x58\xbd\xad\x29\x1a\x32\x44\xe6\x18\xfc\x72\xab\x6a\x86\x43\x22\x98\x32\xb2\xbc\x59\x0c\x1c\x12\x74\xb3\xc4\xc5\x7a\x0d\xea\xaa\xc2\x4c\x42\x6e\x36\x9d\xf8\x7f\xa2\x4a\xf6\xa3\x45\x32\xf9\x64\x8a\x97\xda\x58\x7e\x03\xc7\xb6\x32\xbb\xf8\xde\x37\x5a\xac\xed\x85\x54\xf0\xc3\x13\xa5\x37\x46\x75\xba\x75\x6d\x5f\x61\x53\xc3\xf2\x56\x87\x53\xab\xeb\xc8\xc0\x0e\x19\x56\x5c\xd1\x18\xd7\x01\x5d\xad\xd0\x39\x5e\x96\xab\x52\xe0\x42\xc6\x3b\xd0\x2a\x00\x75\x75\x9c\xde\x71\xef\xf4\x3f\xff\x6c\xbf\x73\x72\x05\x5d\x04\x0a\xaa\x37\xac\x26\x3d\x30\x18\x7f\x01\x35\x29\x85\x7e\x82\xa5\xfe\xe0\x1e\x40\xa4\x00\x97\xef\xe4\x88\xc9\xd9\xa5\xf5\x06\x76\xf2\xbd\x2f\x7b\x5a\x11\x54\xeb\x87\x17\x74\xee\xa6\xab\x11\x79\x6b\x61\xb4\x84\xf5\x76\xcc\x60\x29\x72\x6a\x0e\xd7\x5b\xd1\x39\x57\x7f\x6a\xa0\x7d\xa6\xe8\xcc\xca\x5e\x85\xef\x44\xb1\xbc\x9c\x75\xc3\xfa\x1f\x11\x1f\xb7\xbb\x61\x64\xc3\x93\x9e\x0e\xd8\xc4\x57\xdc\xaa\x39\x5b\x62\xd4\x97\x02\x06\x36\x9b\xe1\x60\x7a\x85\xf8\xed\xb9\x24\xbe\x14\x91\x0c\xb2\xc2\xa4\xe0\x9f\x95\xdb\x73\x3c\x7b\xaf\x8d\xe0\x94\x0f\xb9\x8e\xe4\x82\x1a\x5c\x26\x77\xfe\x1c\x16\xb0\x15\xe0\x9c\xf4\x8f\xf7\x8b\x02\xcc\xc4\x57\xf4\x16\x93\x9d\x2e\x2e\xe9\xde\x4c\x94\x96\x88\x18\xbc\x38\x61\x2a\x50\x7e\xab\x46\x28\xb5\x97\xdb\xd5\xf2\x10\x86\xb1\x83\x5d\x54\x6a\x11\x35\xcf\x3c\x50\xaf\xc6\xd9\x82\xdb\xcf\xbd\x21\x6d\x54\x62\x40\xe5\x6f\x0f\x44\x72\x7c\x8f\x80\xb5\x09\x55\xdd\x05\x05\xa1\xea\x68\x85\xe6\x16\x9c\xfa\x19\x03\x5c\xaf\xa5\xc0\xe2\xbe\xb2\x42\xa4\xe8\x9f\x31\x86\x1e\x36\x9b\x30\x5c\x35\x00\x91\xe4\x02\x38\x42\xad\x02\xa0\x1e\xf8\x05\x2f\x55\x70\xab\x44\x7c\x37\x7a\x9b\x18\x85\x61\xb3\xe9\xad\xd7\x78\xc9\xf1\x66\xb3\x5e\x63\x52\x24\xc7\xc0\xf5\xba\x99\x6b\xb3\x81\x51\xd2\xe2\xc3\xaf\x43\x56\xc8\xf9\xa4\x02\x13\x6c\xd3\xac\x4b\x0d\x00\xc2\xed\x6c\x59\xaf\xc1\x9d\xb4\x72\x91\xa1\x9b\x20\x2b\x8a\x13\x05\x07\x55\xdd\x09\xb8\xe5\xe2\x4e\xe2\x2e\xae\xdd\xff\xc0\xcf\xf9\x88\x75\xe8\x19\xc5\x7d\xfa\x5c\xdc\xa9\x92\x7f\x0b\x70\x36\x1e\x37\x92\x28\x4d\x65\x52\x68\xa5\x16\x9e\x0d\xfe\x6e\x60\x31\xb9\x33\xbf\x13\xb0\x67\xbf\x4d\xff\x39\x19\x7e\x18\x7d\xfe\x64\x8f\xb0\x9e\xc6\xc7\x59\xb1\x09\x7e\xe8\x81\x5f\xf4\xa6\x69\x31\xb5\x96\x02\x22\xbb\xad\xe4\x53\x0a\x87\x1e\x03\x61\x0c\xc8\xd8\x6d\x89\xdd\x44\x34\xad\x60\xe8\xbf\x42\x69\x48\x0b\x69\xe7\xb1\xf6\x5e\x46\xff\xa2\x24\xb7\x5f\x11\xe3\x71\xe2\x02\xda\xb6\x52\x95\x9a\x1d\x5e\x7c\xfe\xf0\xcf\x0f\x93\xcf\x5f\xc6\x29\xa7\x1e\xab\x27\x4c\x3e\x0f\x86\xd3\x69\x68\xbd\xfc\x2c\x36\x10\xb1\xaf\x74\x59\xaf\x22\xe9\xbc\xcb\x08\xdc\xbf\xa4\x35\x11\x32\xae\x34\x03\xe2\x2c\xd0\x5e\x1a\xff\x0e\xfa\x1f\x29\x17\x00\x1e\xdd\x21\x76\xc4\x6a\x72\x54\xd0\xfc\x16\xb3\x3e\xa7\xf9\x6d\x6a\x6b\x25\xe9\x6a\xd8\x66\x93\xad\xd7\xfd\x01\x25\x02\x95\x04\xb3\xa8\xa8\x69\x0e\x4a\xa3\x92\x40\x96\x48\x53\x8f\xee\x34\xf9\x47\x61\xfa\xeb\xb9\xb5\x23\x69\xfd\x14\x1f\xa5\x9d\x4c\x10\x16\xcb\x94\x3b\xf2\x92\xe2\x95\x7a\x03\xda\x73\x65\x45\xd1\x27\xaa\x83\x3b\xe0\x83\x06\x06\x16\x0e\xef\x05\x43\x92\xc6\x5d\x3b\x19\xd1\xcc\x76\xe8\x25\xaa\x12\xdb\x1a\xdf\x2f\x39\xc8\x76\x9a\x46\xf6\x63\xec\x90\x7e\xb3\x3a\x2b\x0a\x86\x39\x6f\xc0\x1b\xed\x88\xb9\x96\x47\xa9\xcc\x33\xf8\xd6\x44\x86\x71\xae\x3d\x1d\xef\x98\x32\x61\xd5\xb8\xb7\xec\x48\x5f\x82\xa6\xd4\xc9\x17\xe2\x4c\x4a\x71\x4a\xde\xd3\x8e\x46\x4e\xb1\x5e\x83\xfe\xbb\xe6\x28\x6a\xb3\x91\x7b\x17\xb5\x25\xc0\x58\xb2\x4e\xce\x13\x5b\x94\x10\xfd\x9f\xb2\x4d\x63\x56\xde\x95\x4b\x3c\xc7\x45\x67\xe2\xba\x67\x01\x81\xfb\x96\xe7\xcc\xee\x47\x38\xe6\x86\xfe\x6d\x53\x8d\x8e\x45\xbd\xf4\x3a\x16\x2b\xba\xd9\xc2\x2b\x87\x3d\x3a\x2c\xfc\x88\xb8\xb5\x1d\xaf\x7c\xf6\x77\xc9\x4b\x03\xd5\x94\x28\xd5\x64\x89\x00\x35\xc6\x7c\x37\x01\x88\xe4\x0e\x2a\x69\x79\x15\xe3\xbe\x9b\xf8\x0d\x07\xd2\x4a\x9a\x52\xf5\x7e\x25\xca\xae\x05\xa5\x15\xcf\xe6\x99\x17\xa3\x77\x0d\x19\x03\x4a\x66\xe5\xbc\x66\x7e\x5a\x6f\x00\x4d\x5f\xc5\x47\x8c\x96\x62\xf1\x30\xd6\xdd\x15\x9d\x54\x04\x8d\x1d\xa1\x45\x6a\xba\x49\xb6\x8d\x35\xfd\x26\xae\x60\xf9\x14\xf3\x92\xe1\x62\x20\x1d\x63\x34\xfc\x4b\x54\x4f\xf6\x0a\xff\x5a\x31\x89\x5a\x07\x78\x41\x51\xd1\xc8\x45\xcc\xbe\x44\x42\xc5\x56\x9d\xf7\x4b\x73\xec\x11\x92\x06\x33\xe2\xb5\x4a\x21\x3a\xc2\x8e\xdf\xb8\x76\x22\x82\xc6\xa6\xb5\xcb\x33\x3b\xb6\xec\x2f\xe9\x81\xa1\xf0\x8e\x34\xbc\x6d\x4e\x57\x7d\x6d\xc1\x4f\xa4\xc4\x51\x4d\x0a\xcb\x03\xdb\xf6\x37\xcc\xf5\x2d\x82\x3d\x63\x64\x4f\xb7\xab\x34\x14\x6d\xe0\x73\xcb\x67\x2d\x2b\xed\xda\xc8\x2f\xa6\x1c\xa3\xc8\xcb\xde\x1a\x7a\xfb\x63\xeb\xa9\x05\xdc\xcc\x32\x66\x78\x56\xde\x4b\xf8\x8a\x95\x44\xcc\x00\x6c\x70\xff\x07\x87\x2e\x4e\xbf\x0c\xd3\xb7\xbd\xa0\x55\x7b\x51\x5d\x76\x91\x39\xa2\x8e\x6a\x20\x4d\xcb\xac\xcc\x83\x7e\x83\x64\x8b\x9f\xbf\xd4\x9d\x68\x55\xbc\x17\xb4\xc3\x3c\x69\x4b\xe2\xd5\xcc\xf8\x76\xb4\x8d\x21\x32\xb5\xd8\x9b\x79\x9d\xa0\x35\xe3\xbd\x1d\x7c\x0c\x0f\x7f\x4a\x6b\xcf\x53\x28\x54\xe1\xc8\x53\x48\x93\x86\x52\x9b\xa4\x76\xb2\x09\x22\x05\x5d\x71\xf0\xba\x14\x14\x75\xb3\xbc\x09\x3c\xf4\xd6\x85\x3c\x69\xfb\xdd\x6a\x6d\xaa\x90\x69\x36\xf8\xd2\xb7\x7b\xbb\xa5\xa3\xd5\xbd\x96\xc7\x1e\x6b\x3d\x3e\x6e\x8f\x5c\xbc\xb1\x5d\x01\xdc\xaa\x29\xfb\xa6\x53\xee\x9b\x63\x9f\xe5\x38\x00\xcf\x3f\x4d\x75\xea\x74\xed\x1e\xfc\xff\x14\x71\x6e\xfe\xf9\x98\x20\x2d\x81\xdd\x29\xbf\x9a\x55\x43\x6f\xba\x97\x91\x70\xdf\x05\xfe\x04\xc2\x6d\xb1\xe9\xfb\x6e\x17\x08\x56\x63\x25\x8f\x7d\xdb\x58\x3f\x4f\xde\xfd\xb3\x83\x9f\x20\xf1\x11\x81\x4b\x35\xee\x3d\x93\x93\x7e\xb4\x7b\x2a\xa3\x39\x7b\x26\xab\xef\x33\x1a\xf1\x42\x05\xe6\x9e\x1e\x05\x29\x1c\xd8\xa3\xa4\x7e\xd8\x90\x1a\x14\x18\xdc\xa6\xc5\x11\x99\x9b\x84\xda\x4b\x31\xb6\xea\x9c\x81\xf2\x43\x46\x15\x71\xf6\x87\xe6\x7c\x3d\x4c\xe0\xe0\xa0\x2c\xd8\x48\xf2\x1b\x1e\xf7\xd5\xff\x8f\x8e\x23\x45\xef\x44\x55\xa6\x1b\x6d\xb5\x07\x98\x3e\xb4\x30\x8d\x4c\x25\x91\x70\x54\xd9\x2d\x47\x22\xaf\xc2\xfa\xfc\x7b\x46\x57\x56\xc4\xea\x68\x72\x00\x7c\x45\x53\xa0\x6e\x42\xb9\x2b\x34\xf4\xf6\x33\x92\xda\xda\x69\xd5\xd7\x2a\x1f\x15\x3e\x2b\x82\xe3\xe1\x5e\x52\x01\x62\x87\x9d\x5a\x68\x97\x88\x8b\x32\xef\x74\xbf\x24\xf3\x2c\xb3\x4d\x41\x27\xc4\x4f\x73\x0d\x4e\x5e\xbb\x87\x76\x76\xeb\x4e\x69\x4d\x57\x33\x9c\xe6\x0b\xbc\xc2\x00\x96\xdd\x45\x0f\x27\xfc\xd6\xef\x75\x2f\x48\xac\x0b\xc4\xea\x99\xd5\x5a\x37\x9a\x69\x2a\x9b\x7e\x55\x77\xfb\xad\x13\x7a\xb7\xad\xd5\x97\xc7\x00\xd0\xcd\x44\x1c\x05\x8d\x2a\x40\x47\xb9\x47\x58\xdb\x68\xdf\xb3\xd7\x94\x96\xa6\xe0\x6c\x2d\xb9\xe4\x51\x0c\x5b\xb8\xce\xe8\xda\xc2\x15\xb9\xe2\x2e\x45\x87\x60\xd5\xaa\x74\xce\x50\x49\x4a\x32\xd7\xfd\x5b\x9a\x0c\x23\x4b\x30\x53\x2e\xa7\x67\x77\xc7\xfc\xf9\xd8\x31\x66\x1d\x1e\xbb\x97\x02\xc0\x51\xb1\xc4\x56\x4b\x8d\x14\x32\xeb\x91\x4e\x05\x6d\x34\x8c\x72\xfe\x0f\x4a\x70\x33\x65\xf7\x4a\x97\x09\x06\x0b\x9c\xdf\xfa\xc5\x09\x53\x41\xb8\x5a\x30\xcc\x17\x74\xa9\x2a\x4b\xa7\xae\x40\x29\x26\xde\xa9\x2e\x3d\x45\x84\x1e\xd2\x3c\xf5\x0d\x0a\xbc\x42\x6c\x1e\xef\xbc\x0a\xca\x76\x16\xba\xc6\xa0\x81\xcd\x26\x4b\x4a\x68\x4a\x31\x9b\x40\xc3\xa0\xa2\x4c\xa4\x6a\x7b\xf6\x8c\x48\x2c\x3c\x13\x17\x9e\xda\x7a\xfc\xd7\x23\xad\x1d\x70\x80\xbf\x90\x45\x94\x9b\x5d\xba\x6b\xed\x49\xd3\x78\xfa\x92\x7e\xcb\x71\xee\x9a\x9d\xfd\xe8\xe9\x8a\xed\x3e\xdc\x78\xc9\x6b\x87\x55\xe3\xf7\xf7\x6f\x2e\x6a\x4f\x19\x55\xc2\x1b\x84\xee\x4f\x4c\xe0\x7a\x5d\x2f\xee\xf4\x22\xda\x18\x9a\xf4\x9e\xb6\x23\xd8\xdb\x45\xc6\x7a\x7d\x1d\xce\xf9\x00\x71\xce\x75\x78\xf4\xc4\xb1\xd2\xc9\x23\xf3\xc5\xc8\x71\xd6\x74\x7a\x61\xf1\xaa\x71\xb2\x3f\x6f\x2f\x02\x29\x48\x9a\xee\x6d\xa0\xcf\x25\x23\xac\xa0\xfb\xfd\xaa\x2f\x1b\xbf\x24\x9a\x80\xf7\x54\xe0\x50\x61\xef\x1f\xb6\x69\x6d\xa4\x98\xe9\xf6\x16\x6b\x87\xe3\xe0\x89\x36\x5d\xab\x41\x4d\xbc\xe4\x80\x5b\xaf\x62\xc7\xe1\x42\xb0\xf2\xa6\x16\x7a\xc1\x89\xc3\xb0\x86\x98\x5d\x64\x00\x27\xd5\x94\xee\x2a\x3c\x5c\xd9\x04\x27\x20\x9e\xfe\x70\xd3\xc1\xf7\x7c\x0d\x0a\xfa\x9c\x7b\xfe\x66\x85\xb2\xf2\x6c\xf9\xb9\x78\x37\xa0\xf4\xb6\xc4\x53\x51\xe6\xb7\x25\xc1\x9c\xb7\xf1\x83\x5c\x95\xbb\xbb\x68\xa6\xea\xa7\x0f\xd0\x61\x4b\xb4\xac\xbc\x06\x7b\xa4\xbd\xa9\x64\xca\x5c\xed\x6d\xad\x05\xe8\x84\x3b\x76\x2f\xb8\xed\xe8\x6d\x0f\xb5\x76\xc6\xc2\x9b\x70\x8c\x07\xd0\x71\xab\xdd\x18\x2b\x19\xd8\x95\x96\x47\x7a\x84\xad\xde\x39\xd5\x20\x32\x60\x94\xfc\x8d\xde\xf0\xb0\x07\x56\x46\x51\xc4\xbb\x86\xb1\xeb\x12\x46\x32\x11\xde\xf3\x02\xc6\x1e\x2d\xfd\x5b\x2e\x5f\x04\x0d\x5c\xbb\x2e\x5e\xbc\xcc\xb5\x8b\x47\x5c\xba\x48\x9c\x43\xda\x96\x34\x7d\xd9\x22\x69\x65\xdd\xb0\x6e\xff\x6b\x16\xbb\x2f\x59\xec\x79\xc5\x62\xeb\x85\x98\x78\xb7\xc0\x1e\x97\x62\x6c\xce\x42\x9c\xf3\x6c\x52\x93\x2b\xc4\x6f\xe3\xa0\xee\x85\x8d\x28\x88\x9d\xe0\x26\x8c\xf6\x19\x23\xed\x11\x42\x1c\x04\x68\x5a\x72\xfb\x24\x73\x47\x6c\xef\x0c\x46\x8c\x64\xe8\x07\xcf\x24\x92\x84\x37\x00\xa1\xfd\x6c\xaf\x62\xa4\x47\xc0\x47\xa0\x3b\xcb\x73\x5a\x13\x31\x2a\x76\x60\x34\xab\x3c\xda\x82\xb9\xed\x06\x1b\x5c\x7c\x99\x5e\x0d\x27\x30\xd1\x1d\x00\x9a\xa4\x22\xfa\x2e\xf6\x34\x7c\x16\x44\x35\x49\xe5\xb2\x0c\x67\xb4\x86\x22\x6d\x5b\x7b\xbb\x26\x65\xdf\x92\xd7\x70\xfc\xa2\x47\x6b\x29\x77\x17\x37\x60\x83\xcc\x3a\x75\x75\xda\xef\x0e\xa5\xd7\xb0\x5a\xf7\x0e\xa5\x11\xb7\xad\x40\x70\x03\x66\x61\x1e\x58\x30\x5b\xee\xb7\x34\xa4\x46\x8f\x39\xb7\x5e\x6b\xb1\x32\xc0\x93\x63\x27\x69\x0f\xee\x1d\xc1\x7f\x94\xd5\xfb\x72\x19\x69\x21\x86\xdf\x49\x98\xfb\x1e\xd4\x1c\x03\x2e\x58\x99\x8b\x83\xbf\xfa\x46\xea\x0e\x31\x80\x7e\x70\xf0\x16\x30\xfc\x7b\x5d\x32\xfc\xfa\x00\xfd\xe0\x87\xbc\xb8\x3d\x78\x13\x05\xc6\xb9\x04\x26\xf8\x87\x1c\xd6\x1f\x0e\xa6\xaf\xe3\x70\x46\xb8\xc1\x5b\x70\x10\x91\xe1\x38\x21\xc2\x39\xbf\x95\xf3\xac\xfd\x14\x3d\x2c\x6e\x87\xdd\xe6\x2d\x70\xbc\x29\x4d\xbe\x92\x34\x35\x32\x70\x90\x81\x03\x18\x6d\x1e\xd8\x76\xb4\xdc\x03\xf0\xa0\x17\xed\x48\x8b\xe7\x83\x7a\xda\x83\xec\xe0\xc0\x5f\x79\xd0\x30\x81\xef\x2b\x19\x74\x36\xa2\x07\xde\x82\x99\x11\xeb\xd7\xf8\x0e\x13\xd1\x03\x39\x25\x02\xdf\x8b\x37\x01\x7f\xd4\x2c\x92\x95\xfa\x64\x34\xc2\x41\xd8\xfc\x83\x0b\xc4\x04\x2e\xde\x3d\x64\xe0\x40\x6a\xc1\x41\x2f\x05\xe9\x6e\x4b\xe6\x6f\xd3\x37\x45\x54\xdf\x9c\x01\x5f\x27\xd1\x18\x89\xc8\x9a\x7f\xa4\x01\xa5\xf5\xcc\xc0\x49\x12\x80\xde\x61\xc6\xca\x02\xf3\x2c\xbd\x3c\x8d\xc8\xf4\x4a\x7c\xee\x06\x7c\xdb\x36\x00\x28\xa9\x21\x68\x85\x33\x67\x51\x92\xe3\xaa\x11\x3a\xfb\x06\x0e\xf8\xe2\xa0\x07\x0e\x0e\xf3\x83\x1e\xd0\x40\xe6\x9d\x94\x84\x6d\xc8\xaf\x53\x2f\xa3\xa3\x36\x81\x86\xc8\x3f\x70\xce\xfb\x4c\x3b\xea\xd7\x7a\x8b\x5b\x61\xe8\x17\x94\xe0\x50\x17\x37\x7f\x0d\x0a\x4f\x5d\x68\xb4\x2d\xe8\xb5\x95\x6d\x47\x2c\x2b\x95\x69\xba\xa0\x4c\x18\x7d\x99\xd4\x5b\xe2\xda\xa1\xe4\x19\xcf\x32\x05\xb4\xd3\xa0\x5b\x86\xbc\x7f\x41\xc9\xbc\xb1\xdb\x3c\x5f\xe0\xa2\x76\x6f\x28\x4f\xcd\xb3\xe1\x7d\xc5\x30\x6f\x22\x2b\x45\x9c\x79\xe3\x9d\xe4\xe9\x0a\x62\x90\x66\x2a\xd3\x9d\xb4\xef\x9d\xbf\x4a\xdc\xee\x18\x15\x11\x82\x4d\xb1\xd2\xab\x77\x56\xa6\xe2\xf7\xbd\xb9\xbb\xf3\x1d\x66\xe0\xbb\x1a\x6b\x32\x4a\xb0\xd9\x7c\x87\x3d\xf0\x1d\x1a\x21\xeb\x00\x4c\x6b\xbe\x02\xb0\x62\x82\x58\x26\x13\xd9\x22\xed\x7d\xc7\x98\xad\x4a\xce\x63\x6e\x1a\xf8\x7e\xda\x82\x8d\xed\x1a\x70\x33\x92\xbc\x3d\xe8\xd7\x91\x7e\x36\x22\x77\xf4\x16\xc7\xee\xdc\x3a\x3e\x1b\x3c\x91\xef\x56\xb2\x21\x27\x55\x8a\xc9\xbd\xf4\xc2\x16\x15\x15\xcd\x2a\x34\xc9\x73\xf7\x40\xa2\xad\x89\x1f\x9f\x2d\x46\x3f\xcf\xa5\x6f\x80\xe9\xf0\xf3\x23\xe2\xc3\x81\xfd\x95\x08\x45\xd4\x67\xe6\x04\xfd\xdb\xbe\xb5\xe5\x85\xb2\x00\xd6\xfc\x10\x23\x2e\xd4\x45\x41\xfb\x84\xe1\x91\x38\x7e\xe0\x17\xc2\x71\xfa\x0c\x1c\xb8\x3e\xcc\x31\x11\x0c\x2d\x9f\x45\x0a\xae\x9f\xbf\x1c\x54\x1d\x12\xca\xc4\xe2\xd9\xbc\x45\xd5\x21\xa7\xf5\x4b\x23\x52\x5c\x36\x78\xae\xb7\x5d\x17\x8c\xdd\x2d\xee\x04\x52\xbe\x89\x7c\x35\x28\x72\xc5\x6e\x62\x81\x35\x09\x80\x7d\xd8\xe9\x88\xb7\x01\x88\xde\xbd\x8b\x3b\x81\xe7\xdf\xb9\xb3\xbe\x7e\x14\x74\x44\x06\xfd\x00\xaf\x1a\xbd\xde\xcd\x37\xef\xb2\x68\xc3\x35\x5d\x06\xdf\x97\x11\xc1\x7d\xd2\x20\x85\x7c\x65\x2c\xcc\xd6\x6d\x79\xcc\x44\x3e\xfb\x22\x78\x7b\x01\xd3\x8c\xd5\x4b\x72\xc5\xbb\x74\xee\x34\x7d\xc6\x6f\xb6\xfa\x05\xae\xc4\xf6\xef\x55\xdc\x4a\xd6\x48\xbc\xf2\x4d\x57\x4c\xf2\x8b\x2c\x5e\x69\xc9\x79\xed\xb7\xba\x6e\xaf\xae\xb8\x75\xaf\xa0\x98\xd3\x55\xc1\x82\x0c\x1c\xca\xf0\xce\xf5\x59\x4f\xc9\xc9\xed\xd6\x87\x68\x85\xa9\xf1\x5e\x9d\x1f\x4f\xd5\xc5\xe2\x55\x31\x27\x58\x72\x2b\x62\x4e\xaf\x6f\x08\x96\xfe\x34\xcc\xcb\x7f\xf5\x25\xb9\xd7\x9a\xd5\xba\x77\x63\x49\x51\x71\xd3\xf6\x6e\xe8\x66\xa2\x1b\x9c\x28\x71\x25\xc6\x68\x83\x80\x59\x73\x64\xc0\xdf\x33\xba\x8a\x76\x81\xec\xc6\x36\xf1\x71\xfd\x56\x8a\xc5\x1e\xb8\xf2\xd3\x9d\xc4\xe7\xa7\xd9\x59\x2d\x16\x94\x95\x7f\xe0\x68\x5f\x53\x30\x2a\x76\x2e\x62\x55\x02\xa3\x7c\xfd\x53\x04\x8d\xf7\xc4\xeb\x23\x8f\x0a\xb1\xed\xc1\x76\xd8\x63\xfb\x73\x16\xe1\x57\x22\x5c\x9b\x33\xfd\x35\xcb\xcc\x17\x5b\x8c\xd1\x39\xc7\x4b\x2c\xe5\xa4\x3d\x12\x81\x13\x2c\x93\xc6\x1d\x46\x49\x7d\x68\x71\x40\x89\x60\xfa\x88\xd6\xef\x79\x81\x57\xc8\xbb\x8a\xb4\x6e\x2e\x4f\x43\xfe\xc0\x05\x5e\x49\x1b\xdb\x1e\x4c\x99\x8f\xc4\x00\x37\x02\x30\xf0\xa8\xaa\x6c\xe0\x2d\xce\x2b\xc6\x36\x8b\x6b\xff\x0e\x00\x00\xff\xff\x78\xd7\xfa\x70\x2d\x57\x00\x00")
+var _templatesAppTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xec\x7d\xff\x73\xe3\xb6\xb1\xf8\xef\xfa\x2b\x30\x98\x7c\xc6\x97\x7e\x68\xd9\xbe\x6b\xf2\x5a\xf6\xdd\x9b\xd1\xc9\x4e\xce\xad\x7d\xd6\x93\x7c\x97\xb6\x17\xcf\x0d\x4c\xc2\x12\x63\x09\x60\x01\xd0\x5f\xa2\xe1\xff\xfe\x06\x00\xbf\x00\x24\x40\xd1\xf2\x97\x36\x93\x73\x26\x73\x36\xb9\x58\x2c\x80\xdd\xc5\xee\x62\xb1\x5c\xaf\x41\x8c\xaf\x12\x82\x01\x44\x69\x0a\x41\x9e\x0f\x00\x58\xaf\xc1\x37\x28\x4d\x41\xf8\x16\x0c\x47\x69\x5a\x3f\x5c\x21\x92\x5c\x61\x2e\xd4\x9b\xd3\xf2\x0f\xfd\x7a\x00\x00\x00\x70\xf4\xd3\xec\x1c\xaf\xd2\x25\x12\xf8\x07\xca\x56\x48\x7c\xc2\x8c\x27\x94\x40\x10\x02\xf8\x7a\xff\x60\x7f\x77\xff\xcf\xbb\xfb\x7f\x86\x81\x06\x1f\x53\x12\x27\x22\xa1\x84\xc3\xb0\x40\xa1\x7a\x12\x05\x0e\x00\x2f\xd1\x12\x91\x08\xb3\xdd\xa8\x06\x6d\xf6\xdd\x6a\x94\x32\x1a\x61\xce\x1f\xd4\x86\xe1\x79\xc2\x05\xbb\xdf\xd4\x08\x1e\x13\x81\x19\x41\x4b\x49\x31\x80\x3f\x90\x30\x3c\xfa\x57\x86\x96\x72\x04\x9f\xe5\x93\x29\xbe\x82\xa1\x01\x06\xf2\x00\xc0\x7f\x60\x0e\xc1\x05\xc8\x83\x12\xcb\x84\x25\x37\x48\xe0\x0d\x48\x4a\x28\x37\x8e\x77\x4b\x44\xae\xff\x86\xef\x37\x20\x91\x10\x0a\x81\xa3\xf5\x0c\x47\x19\x4b\xc4\xfd\x8f\x8c\x66\x29\x04\x21\x58\x9b\x78\x40\x08\x3e\xaf\x15\x1a\x10\x02\x68\xc3\x2a\x84\x17\x7a\x56\x0a\xa4\x70\x82\x18\x5a\x61\x81\x99\x6a\xda\xbd\x9e\xa9\x84\x7d\xc0\x5a\x3a\xe1\xcb\xb1\x8c\x96\x88\xad\xbe\xbb\xbb\x33\xb8\x08\x00\x78\x7e\x9f\xca\x09\x86\x33\xc1\x12\x32\x87\x41\xfd\xe6\x10\xf3\x88\x25\xa9\x5c\x65\x18\x16\xcd\xc1\xed\x02\x13\x20\x16\x18\x7c\x77\x77\x07\x18\xe6\x29\x25\x1c\x73\x40\xaf\x00\x02\x05\xd9\x31\x28\xc8\x01\x88\x61\x40\x33\xc1\x93\x18\x4b\x08\xb1\xc0\x09\x03\xf8\x2e\xc5\x91\xc0\x31\x60\x88\xcc\xb1\xdd\xe1\x15\xca\x96\x42\x76\xf6\x81\x9a\x2f\x46\xcb\x25\xbd\xc5\xf1\x27\xb4\xcc\xb0\x5e\x38\xb5\xce\x81\x82\x03\x17\x05\x60\xbd\x6a\x8a\xd4\x71\x9a\x3d\xcd\x48\xc7\x93\x8f\x20\xe3\x58\x8f\xb1\x1c\x5a\xc2\xcd\x91\x25\x82\xbf\xd8\xb8\x4e\x90\xc0\x24\xba\x7f\x9a\xb1\x2d\x35\x32\xcf\xfa\xfd\x5b\x06\xf9\x8e\x21\x12\x2d\xfa\x0f\xaf\xea\x76\x85\xb8\xc0\xac\x63\xec\x1a\xb3\x1c\xca\x14\xa7\x14\xdc\x2e\x28\xc7\x20\xcd\xf8\x02\x6b\x56\xbd\xcc\x92\xa5\x00\x88\xa8\x09\x58\x51\x81\x63\xd8\xa6\x2e\x4b\x96\xf1\x14\x0b\x4c\x0a\xac\x0e\x2a\x3f\x64\xab\x4b\xcc\x3c\x54\xee\x9b\xcf\x4f\x13\xa2\xe6\xa5\xf5\xa2\x41\xb9\xc6\x28\x29\x97\x34\xc6\x1c\x08\x0a\xae\x31\x4e\x03\x40\x97\x31\x66\xe5\x53\x49\xba\x16\xb2\x64\x85\xe6\xc5\xa8\x52\x96\x11\x1c\x0f\xc1\xbe\x6a\xc1\x01\xbe\xc1\xec\x5e\xb7\x68\x0f\x6f\xbc\xcc\xd4\x24\xb6\xc7\x05\x3a\xa7\x5f\xbe\xf5\xd1\xaf\xde\xb5\x7a\x3a\xc4\xe9\x92\xde\xaf\x30\x11\xa7\xe8\x2e\x59\x65\xab\x2d\xe6\xf2\xf5\x7e\xd7\xa4\x15\x78\x41\x8a\x59\x84\x89\x40\x73\xc5\xc5\x05\x6f\xe3\x6a\x0e\x01\xcb\x08\x49\xc8\x1c\xdc\x2e\x92\x25\x06\xb1\xa2\x4b\x0e\xb3\x8b\xe4\x84\x6c\x49\xf2\x41\x37\xc9\x09\x79\x5a\x92\x8f\xc8\x4d\xc2\x28\x91\x34\x6f\x21\x51\x1d\x94\xb6\xbb\x32\xb7\xfc\x2d\x14\xd3\x19\x59\xde\x03\x24\x75\x05\x40\x91\x1c\xae\x1c\xac\x58\x24\x1c\x48\x2b\xeb\x8a\xd1\x15\x48\x88\x52\x45\x52\x6f\x7d\x9a\x8c\x9f\x45\xf9\x14\x86\xc2\x73\xce\x93\x61\xd4\x6c\x31\x4d\x1f\x39\x06\xb3\xec\x92\x60\xc1\x0b\x44\x40\x50\xc0\x53\x1c\x25\x57\xf7\x72\x5a\x76\xd5\x1c\x2d\x29\x8a\x4b\x7d\xce\x00\x26\x71\x4a\x13\x22\xf8\xb3\xcc\xd9\x14\x2f\x31\xe2\xf8\x05\x74\x86\x54\xdb\x4f\xbb\x3c\x3f\x26\x02\x30\x9c\x52\x9e\x08\xca\xee\x81\x58\x20\x61\x2a\xd3\x62\x1f\xe0\x8a\xe7\x24\x1f\xaa\x8d\x53\xb5\x89\x70\x72\x83\x39\x40\x6a\x03\x01\xb7\xf8\x72\x41\xe9\x75\x00\x92\x21\x1e\x82\x85\x10\x29\x0f\xf7\xf6\xe6\x89\x58\x64\x97\xc3\x88\xae\xf6\x22\x4a\x6e\xe8\xdd\x1e\x43\xd1\xf5\x70\x9e\x08\xf7\xd8\x34\x15\x4f\x3e\x91\x33\x9a\xb1\x08\x83\x88\xc6\xd8\x18\x6c\x9b\x04\xdb\x76\x7d\x6a\x2a\xce\x17\x18\x9c\x58\x6c\xc9\x8b\xfe\xc0\x5c\x76\x08\xae\x28\xab\x04\xde\x41\x9c\x66\x7a\x0f\x59\x27\x09\x17\xff\x3d\xfa\x69\x16\x86\x47\xe3\xd7\x61\xa8\x81\xc3\xf0\x38\xfe\x9f\x6d\x48\xfd\x34\x19\x03\xae\xfb\xeb\x47\x95\x5f\xa6\x9f\x87\xb8\x54\xf7\xd7\x93\xc8\xd2\xbd\xb4\xa8\x6b\x08\xc2\xab\xe9\xd1\xff\x7e\x3c\x9e\x1e\x1d\x7e\x0b\x4e\xd0\xea\x32\x46\x60\x9c\x71\x41\x57\xe7\x34\x4d\x22\xf0\x1e\x91\x78\x89\x19\x28\x44\x1d\x94\x18\x6d\x53\xe6\x04\x93\xb9\x58\x28\x22\x0f\x60\xd0\x98\x88\x9a\x77\xda\xf4\x4d\xc6\x9e\x99\xab\x27\xed\xd3\x64\x2c\x67\x6c\xdb\x09\xdb\x30\x41\x93\xf1\xf8\xf8\x70\xfa\xe4\x2c\x2f\x7b\x96\x88\xdd\xdd\x5b\x5e\xe1\x29\x4a\xd3\x84\xcc\x4d\xfe\x86\x13\xca\xc4\x84\x51\x41\x23\xda\xd8\x55\xa5\x82\x81\xca\xa1\x95\xbc\x85\x09\x66\x06\x1c\x7c\x7f\x7e\x3e\x81\x81\xdc\x91\xb9\x90\x92\xe6\x7a\xa7\x64\x1d\xfb\x20\x66\xb0\x9e\x9d\xa2\x3b\xde\xdd\xdf\xec\xd1\x1d\x5a\x3d\x8a\xa8\x63\x7c\xe7\x63\xef\xf0\xce\xc7\x1b\x3a\x9b\xcd\x4e\x9a\x5d\x2d\x3b\x86\x26\xc1\x1f\xd7\x15\xc8\x9d\xeb\x3d\xc5\x5c\x69\x65\x6b\xc1\x0d\x91\x9b\xd2\xa5\xc7\x44\x50\x32\x71\x3c\x3a\x0d\x43\x05\x63\x8c\x64\xc2\x68\x8a\x99\x48\x2c\xa4\x7a\x4b\xe7\x3c\x5b\x61\x09\x3f\xa1\xcb\x24\xba\x3f\xa4\x51\xd6\xb2\x09\x1b\xba\x42\x46\xa2\x5e\xef\x1e\xec\xef\x1e\xfc\x17\x0c\x6c\xa0\x99\x40\x02\x17\xed\x3f\x5b\xaf\x40\x03\x9f\x36\x42\xaf\xae\x70\x24\xb4\xf7\xb9\xa4\xb7\x30\x68\x83\x4c\x58\x42\xa2\x24\x2d\x03\x46\x33\xcc\x6e\x92\x08\x6b\xe3\x63\xa9\xf4\xd1\x10\xad\xd0\xaf\x94\xa0\x5b\x2e\xf7\x53\x2b\x4a\x63\x0e\x34\x2a\x14\xda\x67\x00\xb9\xe0\x61\x3d\xf0\xda\x72\x01\xc0\x5c\x90\xf2\xc7\x7c\x6b\x61\x86\x13\x24\xa4\x3b\x0a\xcb\x3d\x1c\xda\x6f\xe5\x84\xea\x29\xff\x3c\xe8\x9a\x08\x0d\x79\xff\x01\xad\xf4\x32\xc6\xab\x84\xc8\x60\x1a\x12\x94\xc1\xc0\x0d\xec\x5d\xa7\xde\x6b\xd5\x5e\x2f\xb0\x76\xac\x88\x31\x73\xf0\x0f\x30\xa8\xf9\x53\x3f\x00\xf9\x86\xd9\x33\xff\xba\x18\x34\x9f\xe6\x81\x83\xc3\x3b\xb8\x5b\xef\x40\x61\xf8\x43\x46\x34\x55\xbd\x98\x7c\x4c\x63\xdc\x66\xe8\xd9\x9b\x77\x59\x74\x8d\x45\x1d\xff\xfb\x2b\x4d\x0a\x0e\xd9\x85\x81\xfc\x47\xaf\x2b\x0c\x8c\x70\xa0\x22\x63\x8a\xe7\xb2\x73\x39\xf8\x36\xbb\xc1\xd9\x1b\x2b\xaa\x58\x63\xd5\x48\x99\xde\x2a\xf7\x2c\xb4\xe5\x8a\xa9\x48\xe3\x9e\x66\xec\xbd\x2b\x15\x03\x4e\x28\x19\xfe\x9a\xa4\x50\xf7\xe5\x65\xc6\x62\x27\x96\xc8\x12\x12\xe3\xbb\x21\xbe\x2b\xdc\x2e\x0b\xec\x14\xaf\x28\xbb\x9f\x25\xbf\xaa\x49\x3d\x78\xfd\x27\xfb\x75\xa9\x5d\x34\xe9\x3f\x62\x31\x12\x9a\x37\x5a\x2a\x48\x72\x06\x23\x2d\x71\x83\xd3\x8c\x88\x44\x73\x32\xa1\x31\xfe\x85\xdb\x1d\x9c\x27\x2b\x4c\x33\xc5\x61\x6f\xf6\xf7\xa1\x9f\x23\xdc\x71\x4f\x56\x69\x47\x30\xf4\x84\x3c\x23\x46\xc9\x2f\xf4\xb2\x0f\x68\x19\x1d\x35\x41\x7b\x06\x54\xb9\x56\x44\x1d\xc8\xab\x90\xb8\x0f\xbb\xab\x51\x69\xf9\x42\x0f\x52\x2e\x74\x40\xdb\xde\x33\xce\x32\x91\x66\x62\xf3\x29\x00\x2d\xe0\xc0\xb0\x7b\x70\x35\x5c\xdf\xb0\xbf\xbb\x45\xed\x3f\x08\xd1\xb0\x61\xa4\x96\x2a\x02\x5c\xb5\x14\x54\x70\xcd\xbd\x71\x20\xff\x5f\xaf\x01\x26\xb1\xc2\x6b\x1c\xbc\xb8\x4e\x2b\xca\x23\x17\x15\x8b\x04\xdf\x5c\xab\x13\x97\x23\x22\x98\x52\xb2\xbc\x1c\x0c\x3c\x22\xe8\x72\x89\xe3\xf5\x1a\x64\x69\x8a\x99\x84\xcc\xf3\x9a\xfd\x3f\x50\xc5\xfb\xce\xd3\x01\xf9\x64\x86\x97\x5a\x59\x7e\x06\xfb\xa6\x30\xdb\xf8\x7e\x28\xa5\x58\xeb\x0b\x29\xe0\xbb\x07\x4a\x6e\x2a\xd1\x81\xa3\x4c\x50\x1e\xa1\x25\xf6\x91\x32\x22\xb1\xb1\x8f\xac\x8d\xb3\x1f\x18\xfa\xc6\x61\xc8\xd2\x83\xc7\x73\xe0\x1f\x4f\x45\x6b\x3d\x9e\xfd\x72\x38\xaa\x3f\x63\x58\xc5\xc1\xc2\x86\x51\xf5\x1f\x4f\xe7\x49\x4d\xd9\x9b\x7d\xde\xd3\x24\x67\x9c\x66\x2f\x48\x8e\x3c\x6b\xe8\x24\xa7\x08\xda\xbf\x20\x49\x45\x8f\x6e\xb2\x6a\x19\xeb\x96\xb6\xf2\x3c\xa9\x21\x69\xd8\x27\x69\x35\xa1\xd8\x12\x09\xc3\xc6\x2d\x37\xfa\x31\x5d\xad\xd0\x21\x5e\x26\xab\x44\xe0\x58\xda\xde\x30\x18\x34\xfc\x2b\xb9\x75\x05\xfb\xc1\xeb\xef\xbe\x37\xdf\x79\x22\xe3\x56\x94\x94\x65\x24\xd0\x07\x37\x24\x11\xfa\x09\x96\xba\x1c\x07\x2a\xae\x73\xfa\x4e\xb6\x98\x8e\x4e\x8d\x37\xd0\xd2\xb5\xf6\x48\x6a\x61\xd8\x7e\x24\xfb\x81\xfa\xcf\x3b\x92\x32\xf6\x2b\xe9\x5b\x15\xa1\x6b\xd2\x1e\x9d\x1e\x56\x11\x20\x96\xe4\x2b\xba\x00\xcd\x04\x40\x97\xb4\x1c\xa0\x13\x26\x21\xe0\x12\x2f\xe9\xed\x10\x8c\xaa\x1e\xe8\x15\xd8\x07\x71\xc2\x25\x9f\x71\x80\x8a\x71\x56\x6e\xfa\x83\xb8\xa5\xda\x1d\x14\x3b\xc0\x13\x3a\xb7\x23\x49\x8e\xad\xa0\x82\xd1\xca\x3f\xd8\xd0\x83\xb1\xc7\xfa\xfa\xb0\x0d\x49\x3a\xe7\x61\x58\x01\xf5\xe9\xa2\xde\xf1\x7b\x9d\xe8\x7b\xb2\x00\x92\xab\xba\xd9\xf0\x3d\xe2\x93\x8a\x39\x15\x44\x4b\x98\x6a\xe0\xc2\xf5\xa9\x00\x6d\x5e\x1c\x4a\x79\x03\x79\x7e\x34\x9e\x9d\x23\x7e\x7d\x28\x89\x4f\x84\x23\xb8\x93\x62\x12\xf3\x33\xf9\xf8\xb3\x65\x74\x07\x95\x73\x55\x9a\x77\x12\x8f\xfa\xfd\xc2\x11\xb2\xd1\x4d\xc3\xb0\xdd\x9f\x01\x6c\xf8\x21\x07\xc3\xfd\x7e\xc6\x7a\x41\xc4\x39\xbd\xc6\x64\xa3\x25\xea\xb5\x42\x0b\x67\xca\x63\xd8\x37\xcc\xf9\x99\x40\xd1\xb5\x6a\xa1\x74\xe2\x7a\x6d\xcc\x27\x6c\x9b\xf8\xc5\x2a\xe2\x61\x19\xf4\xca\x6d\x97\xc7\x88\x7b\x17\xb8\x6a\xc0\xa6\xc3\x6c\x1f\xc3\xd8\xe0\xc6\xbb\x66\x4b\xc9\xa9\xcb\xce\x9e\xeb\x21\x96\xcf\x5a\x7e\x8a\xdd\x77\xdd\xc0\x7c\xde\x1a\x79\x21\x20\x06\x9a\xca\xd9\xb1\x13\x26\xec\x35\xad\xed\x3f\x97\xb1\x67\x7b\x08\x25\xdb\x39\x17\xbf\x7a\xeb\x5b\x79\x09\xd0\xc3\xe7\x2e\xbd\x6d\x7b\xb1\x5b\xde\xf6\xb1\x3c\x2e\xad\xe1\xd4\x9f\x2e\xc0\xf5\x5a\xb3\x84\x52\xf9\x24\x1e\x8e\x18\x43\xf7\x8d\xd5\x29\x7c\x51\x05\xd0\x22\x10\x00\x5b\xf8\x95\x0f\x17\x80\x6f\xf0\x52\xf9\xe7\x4a\x15\x6c\x46\x6f\x12\xa3\x30\xe4\x79\xb0\x5e\x4b\x56\xc9\xf3\xf5\x1a\x93\xd8\xdb\x06\xae\xd7\x65\x5f\x79\x0e\x9d\xa4\xb9\x9b\x5f\xb4\xa7\x42\xf6\x27\x45\x84\x60\x93\x66\x1d\x2d\x05\x10\x76\x4f\xcb\x7a\x0d\x6e\xe4\x6e\xe0\x68\x9a\xe7\xc1\xa0\x0f\x51\xb0\xc8\xf0\xe8\x6b\xd5\x56\xeb\xdf\x32\xd5\x9b\x88\xb5\xf7\xec\xc4\xfd\xfa\xb1\xb8\x7d\x27\xb2\xe5\x0f\x1c\x4d\x26\x25\x27\xca\x2d\xc5\xcb\xb4\x00\xc0\xe9\x68\xfc\xb7\x02\x16\x93\x9b\xe2\x6f\x0f\xec\xe8\xa7\xd9\x97\xe9\xd1\x8f\xc7\x67\x1f\xcc\x16\xc6\x53\x77\x3b\xc3\xbd\xc2\xf7\x01\xf8\x46\x2f\x9a\x66\x53\x5b\x73\x0d\x9c\xfc\x49\x70\xd9\x06\x42\xe0\x66\x4b\x35\xd4\x6b\x7c\x5f\x58\xc4\x15\x63\xe8\x7f\xda\xdc\xe0\x67\x52\x97\xe2\xda\x30\x8c\xe1\x49\x42\xae\x3f\x21\xc6\xdd\xc4\xb5\x68\xeb\xa4\xca\xd7\x3b\x3c\x39\xfb\xf1\xcb\x8f\xd3\xb3\x8f\x13\x9f\xf1\xe3\x0a\x89\x4e\xcf\xc6\x47\xb3\x59\x5b\x7b\x35\x40\x5b\x6d\xe1\x27\xba\xcc\x56\x8e\x88\x64\xc3\xee\x18\x9e\xd2\x8c\x08\x69\xf8\x15\x0d\xdc\x53\xa0\xf7\x41\xfc\x2f\x30\x7c\x4f\xb9\x00\x70\xef\x06\xb1\x3d\x96\x91\xbd\x98\x46\xd7\x98\x0d\x39\x8d\xae\x7d\x4b\x2b\x49\x57\xcd\xf2\x3c\x5c\xaf\x87\x63\x4a\x04\x4a\x08\x66\x4e\x56\xf3\xee\x77\xd5\x6b\x77\xa4\x6d\xef\x46\x93\xbf\x07\x83\x0d\x5b\xfe\xde\x7a\x5d\xcc\x63\x9e\x7b\x09\x73\x05\xfb\x7a\xb0\x97\xef\x0d\xa8\x52\x03\x15\x45\x1f\xa8\x36\x82\x41\x3e\xd8\xa0\x60\xe1\xd1\x9d\x60\x48\xd2\xb8\x69\x25\x1d\x92\x59\x35\x3d\x45\xa9\x67\x59\xdd\xeb\x25\x1b\x99\x9b\x66\xc1\xfb\x81\x1b\xfa\x38\x1d\xc5\x31\xc3\x9c\x97\xe0\xa5\x74\xb8\xb6\x96\x3c\x78\x99\x79\x2b\x2d\x68\xf7\xac\x6d\x8f\x57\x1e\xc7\x19\xc7\x74\x1d\x2b\x32\x94\xa0\x3e\x71\x6a\x32\x71\x28\xb9\xd8\xc7\xef\xfe\x8d\x46\x76\xb1\x5e\x83\xe1\xbb\xf2\x34\x3d\xcf\xe5\xda\x41\x37\xeb\x6a\x4d\x56\xf3\xb9\x67\x89\x3c\xac\xff\x2c\xcb\x24\xcf\xcc\x93\x25\x9e\xe3\xb8\x56\x71\xf5\xb3\x16\x81\x7d\x4f\x18\x8a\xd5\x77\xcc\x98\xed\x22\x75\xc7\x59\x5c\xb6\xa2\xed\x55\xb9\x3c\x85\xf7\x88\x1b\xcb\x31\x68\x6d\x24\x95\x93\x57\x42\x95\xa7\x2c\xaa\x33\x67\xa7\x1e\x6b\xdc\x72\x8e\x1c\x7e\x95\xb2\x9d\x07\xae\xd9\xb7\x1d\xe4\xa3\xb1\xd4\x92\xba\x4d\xcf\x53\x96\x3a\x43\xb0\x62\xcf\xf2\x59\xc3\x46\xaf\xf3\xe5\xc6\x94\x5c\x25\xf3\x8c\xa1\x96\xab\x0a\x8a\xdc\x01\x19\x8c\x78\x8f\xd1\x52\x2c\xee\x27\x3a\x6e\x51\x73\x45\x2b\xef\xce\xe1\x61\x15\xc9\x7e\x5d\x6d\xd1\x5d\xd9\xd6\x7b\xba\x72\x88\x79\xc2\x70\x3c\x96\x1b\x63\x6d\xfe\x1d\x5f\x69\x49\x75\x84\x6f\x7d\x3e\x45\x63\x1f\xaa\xc5\xa2\x05\xd6\x2f\xc2\xec\xb5\x2f\x4d\xa9\xf0\xf8\xaf\x23\x16\x2d\x12\x81\x23\x91\xb1\xb6\x2b\x39\x59\xa2\x08\x17\x4b\x24\x0f\x23\x55\x96\x98\xe3\x54\xb7\xe6\x9b\x15\x96\x31\xa9\xb3\x2b\x18\xc8\x2d\x2a\x95\xda\xbf\x90\x22\x24\x04\x4b\x2e\x33\x81\x43\x1c\xf1\x61\x94\x66\xbb\xc8\xec\xfa\xed\xdb\xda\x96\x68\x92\x05\xd1\x2a\xfe\xfe\x8f\x10\xe4\xf9\xdd\x9f\xbe\xff\xf2\xfd\x1f\x6b\x4b\x60\xbd\x6e\x01\xe7\x79\x25\x13\x4d\x45\x73\xb1\x49\x72\xaa\x59\x71\xaa\x68\x28\x13\x94\x4a\xe1\xe4\xfd\x4e\xb7\x2b\x9d\xda\xcf\xd7\x34\x5b\x48\x1a\x8a\x16\xaf\x94\x1f\x57\x13\xb6\xff\xad\xad\xac\x1d\x68\x4c\x5a\xeb\x40\x48\xcd\x3a\xfd\xd5\x4d\x4b\x5b\x77\x4c\x6a\xe7\xe9\x61\x23\xb4\xe4\xf2\xdc\x9d\xea\xac\x1d\xcb\xea\x92\x81\x76\x30\xca\x20\xb8\xbd\x23\x38\x30\x54\x92\xdc\x4e\xb3\xb0\xf6\x85\x9e\x12\xff\x42\xb9\x19\x2f\x9b\x76\x81\xd2\x74\x99\x44\x4a\xd7\xec\x1a\x71\xe1\x67\xcf\xc3\x08\xfa\x24\xa4\xbc\x74\xb2\x46\x15\xff\xdf\x2a\x51\xa3\x6b\xe1\x3a\x6c\xf1\xcd\x0b\xd8\x9a\xeb\x68\x49\xb3\xf8\x16\x89\x68\x11\xea\xe3\x85\x4b\xac\x4e\x83\x54\xfa\x2e\x8e\x78\xf5\xb4\x32\x92\x8b\xe7\x1f\xd3\x18\x89\xf2\x29\x6c\x9b\x6b\xa5\xe8\xd7\x69\x21\x9f\x55\x62\xc8\x85\x03\xae\x47\xf4\xa8\xcf\xc2\x82\xad\xb3\x4c\xba\x24\xfe\x1c\xb1\x39\x16\x4f\x2d\xf3\xa3\x5a\x58\x64\xfb\x99\x96\x95\x30\x94\xbf\xa0\xcb\xaa\xd7\x5e\x0a\xe1\x34\x21\x63\x94\xa2\x48\xe6\x07\x84\x5b\x98\x08\xad\x93\xdb\x81\x6d\x27\x75\x22\x3f\x78\x0c\xf2\x92\x3b\x8e\x63\x57\x50\x7e\x4f\xbb\xeb\xbc\xb4\x36\xdd\x06\x64\xc7\xb6\xe2\x5c\x90\x22\xc6\x5b\x9c\x6e\xb6\x28\xa2\x4b\x3c\x9a\x7e\x70\xef\x56\x1b\x37\x06\x5f\xf0\xb9\x5c\xd5\xc3\x64\x85\x49\xc9\xc4\x52\x8a\x8a\xb1\x85\x96\x1d\xe9\xb2\xcf\x65\x77\x3c\x45\x11\x2e\x5a\xc2\x2d\xb9\xf9\x2c\x7b\x51\x56\x4e\xc8\x5c\xeb\xbb\x7e\x9c\x6c\x29\xd1\x27\x38\xa4\x09\x00\x94\x49\x45\x8e\x75\xd6\x3d\xd5\x89\xc6\x38\x2d\xc8\x85\xad\x85\x4b\xc8\x5c\x4b\x63\xc9\xa5\x3d\x98\x5d\x37\x68\x9d\x77\xd4\xdd\xe8\xfe\x37\xb8\x39\xa3\xf8\x97\x8c\x0b\xb9\x0f\x54\xc7\x6b\x0b\x19\x2f\x38\xae\xe5\xbd\xb1\xf9\x8d\x29\x5d\xc6\xf4\x56\x2d\xe6\xf7\xfb\x2d\x9f\x07\x0b\x96\x44\xa3\xf9\x9c\xe1\xb9\xea\xb2\x5a\xc7\x1b\xcc\xd0\x1c\xb7\xd3\x36\x71\x5a\xd3\x50\x65\x0d\x68\x34\xea\xfa\xce\x0d\x5a\x9e\xd0\x5b\xcc\xde\xd1\x8c\xc4\xc5\xa5\xb4\x6a\xd6\xea\xa6\xea\xa4\xaf\x99\x14\xb8\xad\x09\x76\x4c\x7e\x5f\x1c\x9c\x90\xdf\x29\x03\xbf\xd9\x7f\x19\x0e\xfe\x98\xa6\xbd\x38\x78\xf7\xc9\x58\xf8\x7d\x32\x5f\x3c\x35\x13\x8f\xa5\x25\xf7\x93\xb2\xe4\x42\x65\xc2\xf5\xf4\x26\x24\xa8\x9d\x59\xd2\x62\x5b\xb0\x0d\xdb\x46\x69\x06\x16\x72\x9c\x0e\xe6\x55\x9d\x6a\x33\xb4\x99\x8b\xb4\x69\xf3\x92\x2b\x10\xd8\x59\xbc\xab\x14\xb1\x84\x53\x72\x96\x62\x9d\x1c\x2d\xef\x69\x31\x8c\x04\x66\xe7\x0b\x44\xce\x17\x0c\xf3\x05\x5d\xc6\xb6\x64\x54\x7b\xb1\xc3\xb8\xaf\xcf\x81\x0b\x43\x43\xfd\x19\xb8\x12\x52\x6a\x4b\xc4\x11\xab\x29\xb1\x18\x9b\x78\x03\xcb\xb6\x96\x8b\x65\xd9\x5a\xe3\x3a\x92\x21\x75\x25\x1b\x13\xcc\x12\x1a\x73\x25\x4a\xcd\x6c\x5f\xc9\xff\xd5\x18\x27\x1f\x3f\x8a\x64\x99\xfc\x8a\x1a\x89\x1a\xc5\x79\x78\x65\x77\x8c\x7e\x9a\xed\x1d\x8d\x67\x0d\x17\x49\xf5\xe2\xd8\x70\x94\x17\x93\x70\x91\x44\x3e\x01\x85\xf5\xd2\x6c\x73\x74\xda\x34\x2d\xb7\x14\xc6\x13\x7a\xfb\x7b\x90\x45\xe9\x10\x3e\x9d\x28\x1e\x93\xbe\x92\x78\x82\x39\xff\x2a\x86\x5a\x0c\x0f\xf6\x7f\x8b\x72\xf8\xe6\x79\xe5\xd0\x59\x7b\xc3\x96\xc1\x56\x82\xee\xf3\x8a\xe0\x93\x9a\x6f\x51\x9a\x79\xe5\xee\xb9\x64\x1d\x11\xba\x42\xcb\x7b\x57\xbf\x1b\xb7\x4b\x65\x0d\x79\x84\xd5\xc9\xd4\xdf\xb9\x78\xba\x47\xc4\x4c\x59\xa3\x70\x75\x00\x1d\xe9\x34\x12\x85\x64\x5b\x77\x78\x4c\xbf\x77\xbe\xeb\xd6\x28\x4f\xab\x59\x5e\x5c\xc3\x74\x44\xc7\x1e\xa2\x4c\xba\x95\x4a\x0b\xd0\x99\x6b\xe2\x53\x36\x86\xca\x31\xb5\xcd\xc6\x14\x94\x29\x16\x19\x23\x87\x48\x20\xd9\x4c\xb0\xac\xd1\xa6\xad\xd4\x35\xfb\xa0\xf8\xa0\x7d\xae\x34\xfa\x70\x76\x3a\x3a\xf9\xc7\x97\xc3\xa3\xf3\xa3\xf1\xf9\xf1\xd9\x87\x2f\xef\x46\x1f\x0e\x5f\xad\x0e\x02\xf0\xfa\x5b\x09\x7e\x82\x2e\xb1\xba\x72\x59\x96\xa7\x81\x81\x93\x84\x0e\xfd\x5e\x49\x48\xe1\x4b\xd4\xd4\x58\x50\x52\xae\x4e\x13\xce\x13\x32\x2f\x31\x13\x2a\xde\x31\x8c\xa2\x85\x71\xb7\xd9\x7d\xd1\xa9\xe3\x90\xd8\xab\x49\x9d\xd5\x7e\xda\xda\xb4\x75\xbf\xe0\x37\xa4\x51\x8b\x1a\x44\x2f\xaa\x55\x8b\x3e\xbf\x6a\xd6\xfe\x9a\xb5\x75\xdc\xe8\x54\xaf\x0f\x3c\x77\xdc\x5a\x1f\x96\x72\xd1\x53\x11\x9e\xbc\xfb\xaa\x08\xff\x43\x14\xa1\x57\xd5\xb5\x4a\xd3\xb5\xd5\x9c\x75\xc9\xec\x37\xa4\xe2\xe4\xd0\x5e\x52\xbd\xc9\xe2\x7c\x5f\x55\xdb\x6f\x54\xb5\xc9\x7a\x18\xf2\x2e\xfb\x97\x77\x28\xba\xc6\x24\xfe\xf2\xdd\xdf\xff\xfe\xef\xd2\x73\xb3\x6c\xf5\x55\xc7\x6d\x65\xec\x19\x49\x2e\xe6\x5f\x9b\xee\xd2\x39\x4b\xb9\xda\xd7\x2f\x2b\xde\x33\x2f\x93\x7d\x53\xdc\x5f\x53\xfc\x17\xbe\x2d\x74\xe4\x70\x62\x3c\x35\x80\xcb\x5e\x26\x0c\x5f\x25\x77\x12\x3e\x65\x09\x11\x57\x00\x96\xb8\xff\x1f\x87\x36\xce\xe6\xbd\xb5\xa1\x99\x0e\x0b\xf2\xdc\xaa\x98\xea\xe8\xc3\x99\xb1\x3a\x96\x0a\xf8\x4a\x9e\xd5\x34\xb2\x70\xbc\x77\x5b\x9b\x43\xdd\x88\xb6\x2c\xea\x6a\x2f\xd1\x56\x4b\xe2\xbe\x0d\xeb\x5e\x8e\xb2\x91\xba\x63\xd0\x7b\xf2\xea\xcd\xad\x6c\xdf\x58\xc1\x87\xcc\xe1\xb3\x94\x60\xdb\x86\x42\x95\x97\xbc\x0d\x69\xeb\x35\x28\xd2\xe2\xaa\xce\xa6\x88\xc4\x74\xc5\xc1\xab\x44\x50\x54\xf7\xf2\x6d\x2b\x55\xb7\x73\x20\x5b\x2d\xbf\x7d\xbd\xd5\x77\xf3\xb3\x58\xe0\xd3\xe6\x46\xb1\x99\x3b\x2a\xd9\xab\xe6\xb8\x31\xb5\x8d\x79\xec\x4e\x61\x6e\xb4\x85\x75\xb1\x2b\x7f\x10\x43\xae\x9b\xb5\xa1\xe5\xca\x9a\x38\xfc\x30\xab\x02\x18\x03\x7b\x16\x9f\x9e\x9d\xcb\x5f\x1f\x92\xad\xed\xc1\x6e\xdd\x51\x2d\x46\x0d\x1b\xdd\x3d\x0d\x87\x37\xd3\x30\x9f\x81\x70\x93\x6d\x86\x4d\x83\x05\xc8\x1d\x4f\xf1\xe3\xd0\x54\xd6\x8f\xe3\xf7\xe6\x65\xeb\x67\xe0\x78\x07\xc3\xf9\x8a\x10\x3e\x72\x26\x9b\x69\xef\xb2\x0e\x9f\xd5\x53\x30\xe8\xf6\x0b\xa0\x02\x7b\x02\x63\x7d\xb7\x24\xb5\x65\x99\xdb\x05\x18\x8f\xc9\xbc\xb8\x59\xd3\xb8\x6b\xd0\x29\x73\x6e\x3b\x5b\x07\x9f\x86\x47\x45\xad\x20\xe0\xb8\x4b\x99\xc4\xec\x38\x55\x07\xe6\x43\xf5\xdf\xde\xbe\xe3\xf6\xab\xe7\x7a\x56\xdd\xda\x28\x75\x54\xd4\xd4\x03\xb9\x0b\x8d\xeb\x36\x09\x3c\x4e\xcd\xf2\x69\xb2\x04\x5c\xb3\x29\xfc\x81\xd1\x95\x91\x35\x6d\x49\x72\x0b\xf8\x9c\xfa\x40\xfd\xe6\xa9\x8b\x38\x6f\x32\xff\xc0\x61\x5e\xc2\x4f\x69\xd4\x48\xcc\x90\xf5\x0d\x3d\x65\xe0\x5c\x1a\xd7\x51\x62\x42\x33\xed\x12\xc9\x53\x9f\x5a\xf6\x55\x52\x8b\xa9\x0a\x60\xd0\x4b\x58\xbc\x32\x62\x5d\x70\xe9\x21\x9d\xe0\x62\x93\xd4\xd4\x97\x07\x67\xd1\x02\xaf\x30\x80\x49\x5d\xf2\x3f\xb7\x33\x5b\xe4\x7b\x18\x1a\x10\xf6\xa9\x57\x5d\xff\xd3\xbe\x95\x51\xd6\xde\xf4\x5d\xc0\x68\x94\xe8\x04\xf9\x06\x40\xe8\xbf\x54\xe1\x14\x80\x9a\xf2\x06\x61\x55\x41\xe4\xc0\x1c\x93\x9f\x9b\xda\x69\x86\xbe\x21\x1f\xbb\xb0\xb5\xc7\xe9\x1c\x5b\x7b\x44\x36\xbb\x4b\xd6\x21\x58\x1d\x20\x1f\xca\xeb\x20\x75\xc2\x94\x22\xa3\xe0\x25\x18\xaa\x2d\x27\x30\x2b\x7d\x7d\xbf\x6f\x62\x36\xf0\xd8\x75\x01\x8e\xe3\x25\xae\x1b\x29\x26\x33\x1e\xd9\x15\x8d\x24\x1a\x46\x39\xff\x27\x25\xb8\xec\xb2\x7e\xa5\xef\x0b\x8d\x17\x38\xba\x6e\x86\x70\xf4\xab\x7b\xf3\x20\x14\xbe\xb6\x19\xaa\x4c\x17\x2a\x89\xd0\x4d\xca\xa7\x4d\x85\x02\xeb\xbc\x61\xd7\x2d\xd4\xd6\x5d\xaf\x02\x5d\xa9\xd0\x40\x9e\x87\x5e\x0e\xf5\x09\x66\x69\x68\x14\xa8\x28\x13\xbe\x4b\x7e\x66\x8f\x48\x2c\x1a\x2a\xae\x1d\x00\x6a\xcc\xbf\x6e\x69\xac\x80\x05\xfc\x91\x2c\x9c\xb3\x39\x70\x28\xd0\xaa\x88\xe6\x53\xee\x5b\xd6\xe6\xae\xa7\x73\xe8\xbc\x66\x6d\x6e\x1f\xb6\xbd\xd4\x28\xed\x09\x86\xcd\x51\x82\xee\xeb\xc7\x26\xea\x86\x30\x2a\x87\xb7\x65\xba\x6f\xe9\xc0\x05\x75\x5d\x51\x59\x3d\xf4\xe2\x01\xbb\xa7\xb9\x11\xf4\xde\x22\x5d\x75\x4b\xad\x99\x6b\x02\x80\x61\x37\x1e\xdd\xb1\x2b\xbf\xe0\x81\xfe\x62\x7b\xe4\x72\x4a\x8c\xb9\x3a\x8e\x9f\x7b\x2d\x7c\xb7\x5d\x37\xdd\xea\xf3\x48\xf7\x96\x64\xb4\xaf\xd2\x36\x9e\x3c\xb1\xfd\xe2\xb9\x23\xd3\x53\x80\xdb\x02\x7b\x77\xdf\x25\xb5\x8e\xc8\xae\x7d\xf5\x46\x6f\x38\x16\x1e\x18\xf8\x1a\x95\xf6\x92\x05\x6e\xbc\x72\x34\x1c\x95\x97\x16\xd5\x80\x3d\x37\x71\x4a\x62\x36\x91\x61\xbb\x9a\xed\x48\xa8\xfc\xc9\x5d\x97\x61\x4c\xf9\xd1\x84\x3c\x85\x04\x5d\x74\x87\x6e\x3d\xf7\x23\x1f\xc9\x3f\x27\xef\xc6\x94\x5e\x27\x78\x26\x92\xe8\x3a\x21\x98\xf3\xca\x7e\x90\xa3\xb2\x57\x17\x5d\xa9\x3b\x7c\xf7\xd0\x9a\x16\xe7\xd5\xc6\x35\xe8\xe1\xf6\xfa\x9c\xa9\xe2\x33\x4d\x95\xb6\x00\x35\x73\xbb\xbe\xf1\x54\xa2\xa9\xbf\xeb\xb4\xd1\x16\xce\xdb\x6d\x1a\x00\xf5\x6c\x55\x0b\x93\xf7\x2f\xb2\xe6\xa8\x77\x6a\x14\x1b\x53\x95\x62\xc6\x8c\x92\xbf\xd2\x4b\xde\xae\xe7\x29\xad\x28\xd2\xb8\xeb\xb8\xe9\xd2\xa2\xd7\x11\xee\x79\x61\xb1\x47\x79\xe2\x8e\x8b\x71\xeb\xc1\x03\x2f\xc3\x3d\x4d\x09\xe9\x07\x5c\x5c\xf4\x5c\x46\x0b\x06\x7d\xee\x22\x7a\xb5\xec\x60\xcb\x92\xd1\x9b\xef\x21\xf6\x2c\x17\xbd\xe1\xb2\xa2\xf3\xb4\xad\xcf\x45\xc5\x7a\x66\xd5\x4d\xa9\x69\x46\xe4\x1d\x5e\x37\xa8\x5d\x7c\xda\x09\x62\x3a\xb8\x1e\xa5\x3d\x62\xa4\x3a\x42\x70\x83\x00\x4d\x4b\x64\x96\x34\xd8\x60\xdb\x9b\x3f\x10\x31\x12\xa2\x5b\x2e\xaf\xbb\x87\x30\xf0\xc2\xf9\xca\x4a\xfb\x5b\xc0\x07\xa0\x1b\x45\x91\xbc\x66\x76\x1c\x6f\xc0\x58\x8c\x72\xaf\x03\x73\x55\x16\x6a\x7c\xf2\x71\x76\x7e\x34\x85\x9e\x32\x21\x95\x53\xe1\x7c\x97\xf7\xba\x12\xda\x7c\xe2\x17\xae\x7c\xd0\x84\xb1\x63\x28\x52\xb7\x55\x95\xc2\x7d\xfa\xcd\x5b\x52\xbc\x19\xf4\xa8\x34\xe5\xe6\xe0\x06\x2c\x91\x19\x37\xff\xad\x3a\x5c\xbb\xeb\xb5\xd6\xcc\xc5\x8e\xb1\x1b\x31\xa3\x67\x57\x35\xef\x45\xf1\xc0\x80\xe9\xa8\xd5\x5d\x92\xea\xbc\xa7\xd8\x59\xa2\xdb\xf0\x00\x0f\xf6\x83\x41\x57\x0d\x75\xf8\xcf\x24\xfd\x21\x59\x3a\x92\x21\xe0\xcf\xa4\xed\xfb\xee\x64\x1c\x03\x2e\x8f\x55\xc5\xce\x5f\x9a\x4a\xea\x06\x31\x80\x6e\x39\x78\x0b\x18\xfe\x57\x96\x30\xfc\x6a\x07\xdd\xf2\x5d\x1e\x5f\xef\x7c\xeb\x04\xc6\x91\x04\x26\xf8\x56\x36\x1b\x1e\x8d\x67\xaf\xdc\x70\x05\x73\x83\xb7\x60\xc7\xc1\xc3\x6e\x42\x84\x55\x43\x40\xf6\xb3\x86\x2d\x03\xa9\x19\xdc\x6e\x97\xe7\x34\xc4\xd2\x55\x9d\x0a\x00\xa0\x68\x2a\x79\x60\x27\x04\x3b\xd0\x99\x81\xdd\x55\xde\x20\x00\x70\x27\x70\x96\xa6\x72\xfb\x83\xba\xdb\x9d\x70\x67\xa7\x39\xf2\x56\xe5\x14\x7c\x97\x4a\xa3\xb3\x64\x3d\xf0\x16\x5c\x15\x6c\xfd\x0a\xdf\x60\x22\x02\x10\x51\x22\xf0\x9d\xf8\xb6\x35\x3f\x10\x00\x00\xe4\x54\xea\x93\x51\xf0\xd6\x0d\x21\x7f\xb8\x40\x4c\xe0\xf8\xdd\x7d\x08\x76\xa4\x14\x84\x3b\xe0\xff\x03\x85\x7f\x48\xd0\x0a\x07\xbe\x76\xf6\x22\x85\xcd\x45\xfb\xac\x51\x14\x27\xc2\x17\x5e\x34\x05\x7f\x84\xe5\x2f\x7e\x40\xa9\x4b\x43\x70\xe0\x05\xa0\x37\x98\xb1\x24\xc6\x3c\xf4\x0f\x56\x23\x2a\xaa\x77\x9c\xd5\x0d\x3e\x77\x35\x90\x3f\x6b\x20\x27\x23\xb4\x06\x25\xe7\x5f\xd5\x47\x0c\x3f\x83\x1d\xbe\xd8\x09\xc0\xce\x6e\xb4\x13\x14\x93\x57\xbc\x93\x7c\xd1\x85\xfc\xc2\xf7\xd2\xd9\x2a\xff\x8b\xeb\xa9\x2c\xea\xc2\xf4\xb6\xfd\x4a\x2f\x78\xc5\x1a\xc3\x98\x12\xdc\x96\xcc\xfc\x2f\xad\x30\x54\x3b\x67\xc2\x65\x02\x9b\xa2\xb7\xc1\xb2\x95\xa2\x35\x5b\x50\x26\x0a\xe9\x99\x66\x1d\x56\xee\x91\x9c\x33\x1e\x86\x0a\x68\xa3\x7a\x37\xd4\xfa\xf0\x84\x92\x79\xa9\xc5\x79\xb4\xc0\xb1\x89\xa1\x08\x02\xcb\x67\x76\x32\x8b\x22\xae\x78\xd3\x4c\x54\x53\xf1\xc4\x96\xd3\xa9\x14\xb9\x57\xdb\xd7\xbb\x97\xe7\x66\xfa\x71\xec\x20\xb8\x55\x7c\x40\x02\x92\xb4\x88\xff\xfd\x0c\x25\xcf\xfd\x0c\x43\xf0\x33\x34\xf4\xd4\xcf\x30\x00\x3f\x97\x55\x91\xeb\xb7\x85\xeb\x59\x01\x14\xfc\x57\x03\x14\xc5\x3c\x15\x80\x61\x3c\xe4\x17\xde\xf3\x0f\x73\xf5\xf4\x36\x3d\xc1\x6c\x95\x70\xee\xda\xcf\x41\x73\x43\x37\x60\x5d\x0b\x0a\x6c\xd7\x25\xaa\x32\x02\xb4\x4b\x10\x1e\x93\x1b\x7a\x8d\x5d\x1f\x1a\xb1\x36\x77\xb0\xe5\x92\x18\x5e\x89\xec\x54\xc9\x2c\x6f\xf8\x21\x26\x17\x29\xb3\x57\xa1\xf1\x1e\xd0\xb7\x98\xdd\xe8\xf8\xe1\x6e\xa5\xf3\x8b\xce\x4a\xb6\xa0\xb6\x53\xdf\x23\x7e\x34\x36\x3f\x8d\xa5\x88\x3a\x63\x96\x77\xd0\x59\x8d\xde\xb6\x79\x01\xcc\xf8\x2e\x46\x5c\xa8\xaf\x23\xe4\xc1\xd6\x38\x6e\xf1\x13\xe1\x78\xfd\x08\x1c\x38\xdb\x8d\x30\x11\x0c\x2d\x1f\x45\x0a\xce\x1e\x3f\x1c\x94\xee\x12\xca\xc4\xe2\xd1\x73\x8b\xd2\x5d\x4e\xb3\xa7\x46\xa4\x66\x79\x50\xdb\xf1\x79\xb0\x89\x23\x5b\xc5\xde\xa7\xc5\x1b\xc7\xa7\x12\x1d\x05\xcb\xa7\x06\x58\xf3\x4b\xea\xba\x60\xb6\xc1\xde\xc1\xa0\xe9\x22\xd7\x95\xcc\xdd\xfb\xc3\xe3\x2b\x98\x1b\x9f\x7c\x6c\x95\xef\x6a\x25\x0e\xd4\x1f\x48\xd9\x38\x6f\x8d\x32\xfc\xe5\xac\xe9\x78\x79\xdf\x89\x68\x65\xbf\xb6\x7c\xcd\x41\xa1\x61\x3a\x97\xe5\x21\x1d\x35\xa7\xcf\x81\x37\x68\x4d\x1a\xb8\xe8\x9e\x95\xc6\x97\x76\xac\x0a\x65\xee\x6f\x06\x34\x23\x61\x9e\xe5\xef\x15\x05\xf3\x06\x53\x9a\x85\x15\xaa\xa8\xd3\xa0\xe1\x91\x37\x62\x50\x9d\xa5\xb2\xba\xc3\x30\x76\x80\xcc\x53\x34\xd5\x41\x82\x0e\x92\x34\xf6\xac\x6d\x9c\xf7\x60\xb0\x21\x14\xd5\xfe\x54\xae\x2f\x80\xe6\x0e\x9f\xf9\x2b\x77\x58\xcb\xee\xa8\xbb\xf1\x92\x35\xd7\xbc\x6b\xad\xde\x62\x9d\xe4\x21\xbf\xdf\x7b\x59\x25\x79\x94\x45\xbb\x3c\xb1\x30\x4f\x1b\xad\x10\x30\x2b\xcf\x16\xb8\x4c\x9e\x71\xa6\x8b\x6c\xc6\x36\x6d\xe2\xfa\x29\x11\x8b\x1e\xb8\xa2\xd7\x1b\x89\x8f\x5e\x87\xa3\x4c\x2c\x28\x4b\x7e\xc5\xce\x04\xa8\x5e\xd5\xc4\xcc\xc2\x64\xae\x6e\xfe\xe0\x40\xd3\xff\x7b\x80\x2d\xaf\xe5\xc2\xd4\xc7\x8d\xaf\x19\x7c\xd5\x29\x3d\x75\xca\xae\x74\xe6\x7f\xfb\x9a\x25\x62\x58\xf0\xff\x6c\xad\xc2\xdf\xc8\x9d\xf5\xec\xf2\x17\x89\xe6\x29\xe4\xc9\x13\xec\x2a\x03\xe2\xfc\x4d\x18\x86\x76\x76\x92\xf1\x19\x10\x00\xf7\xb8\x9e\xb5\xbd\x3f\x40\x4f\x20\xf9\x62\xd3\xad\x13\xd7\xc9\xbf\xfc\x26\xc9\xe3\x4e\xed\x1f\x7f\xba\xe1\x9a\x2d\x00\xe0\xf5\x4a\xd6\x5e\x8c\xd8\x7d\x2a\x60\xef\x7b\x41\x1b\x16\x01\xb4\x3e\xc7\xe2\xc2\xbc\x39\xf2\xbe\xbd\xe6\xf3\xd8\x5c\xc6\xd7\x0b\xdb\x1f\x05\xb4\x35\xe3\xec\x4d\x18\x16\x1f\xe8\x2c\x54\xe3\x21\x5e\x62\x75\xbb\xac\x3c\x35\x96\x97\x7b\x50\x42\x36\xa8\xce\x48\x86\x25\x64\xc9\x5c\xa6\xb3\x58\x9a\x69\x81\xf0\x1c\x35\xca\xb6\xaf\xcb\x8f\xda\x40\x7e\xcf\x05\x5e\x99\xb7\xbe\xca\x6f\x82\x82\x3c\x70\xc0\xcb\xaf\x38\x07\x5e\x23\xd9\x34\xdb\x5d\xd3\x66\xcc\xda\xff\x0d\x00\x04\xbd\xf1\x87\x5a\x8a\x00\x00")
 
 func templatesAppTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -0,0 +1,357 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/convox/rack/manifest"
+)
+
+// maximum number of runs kept in a timer's history
+var TimerRunHistory = 50
+
+// Timer is a scheduled command defined by a convox.cron label in the manifest
+type Timer struct {
+	Name     string `json:"name"`
+	Process  string `json:"process"`
+	Schedule string `json:"schedule"`
+	Command  string `json:"command"`
+
+	LastRun    time.Time `json:"last-run"`
+	LastStatus string    `json:"last-status"`
+}
+
+type Timers []Timer
+
+func (ts Timers) Len() int           { return len(ts) }
+func (ts Timers) Less(i, j int) bool { return ts[i].Name < ts[j].Name }
+func (ts Timers) Swap(i, j int)      { ts[i], ts[j] = ts[j], ts[i] }
+
+// TimerRun is a single execution of a Timer
+type TimerRun struct {
+	Id       string    `json:"id"`
+	Timer    string    `json:"timer"`
+	Status   string    `json:"status"`
+	ExitCode int       `json:"exit-code"`
+	Manual   bool      `json:"manual"`
+	Started  time.Time `json:"started"`
+	Ended    time.Time `json:"ended"`
+}
+
+// TimerRuns are sorted with the latest run first
+type TimerRuns []TimerRun
+
+func (rs TimerRuns) Len() int           { return len(rs) }
+func (rs TimerRuns) Less(i, j int) bool { return rs[i].Started.After(rs[j].Started) }
+func (rs TimerRuns) Swap(i, j int)      { rs[i], rs[j] = rs[j], rs[i] }
+
+// ListTimers returns the timers defined in the active release of an app along with their last run
+func ListTimers(app string) (Timers, error) {
+	a, err := GetApp(app)
+	if err != nil {
+		return nil, err
+	}
+
+	cronjobs, err := a.ActiveCronJobs()
+	if err != nil {
+		return nil, err
+	}
+
+	timers := Timers{}
+
+	for _, cj := range cronjobs {
+		t := Timer{
+			Name:     cj.Name,
+			Process:  cj.Process(),
+			Schedule: cj.Expression(),
+			Command:  cj.Command,
+		}
+
+		runs, err := a.TimerRuns(cj.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(runs) > 0 {
+			t.LastRun = runs[0].Started
+			t.LastStatus = runs[0].Status
+		}
+
+		timers = append(timers, t)
+	}
+
+	sort.Sort(timers)
+
+	return timers, nil
+}
+
+// ActiveCronJobs returns the cron jobs defined in the manifest of the app's active release
+func (a *App) ActiveCronJobs() (CronJobs, error) {
+	if a.Release == "" {
+		return CronJobs{}, nil
+	}
+
+	r, err := GetRelease(a.Name, a.Release)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := manifest.Load([]byte(r.Manifest))
+	if err != nil {
+		return nil, err
+	}
+
+	cronjobs := CronJobs(a.CronJobs(*m))
+
+	sort.Sort(cronjobs)
+
+	return cronjobs, nil
+}
+
+// TimerRuns returns the stored run history of a timer, latest first
+func (a *App) TimerRuns(name string) (TimerRuns, error) {
+	data, err := s3Get(a.Outputs["Settings"], timerKey(name))
+	if awserrCode(err) == "NoSuchKey" {
+		return TimerRuns{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var runs TimerRuns
+
+	if err := json.Unmarshal(data, &runs); err != nil {
+		return nil, err
+	}
+
+	sort.Sort(runs)
+
+	return runs, nil
+}
+
+// RunTimer triggers a timer immediately, outside of its schedule
+func (a *App) RunTimer(name string) (*TimerRun, error) {
+	cronjobs, err := a.ActiveCronJobs()
+	if err != nil {
+		return nil, err
+	}
+
+	var cronjob *CronJob
+
+	for i := range cronjobs {
+		if cronjobs[i].Name == name {
+			cronjob = &cronjobs[i]
+			break
+		}
+	}
+
+	if cronjob == nil {
+		return nil, fmt.Errorf("no such timer: %s", name)
+	}
+
+	resources, err := a.Resources()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := ECS().RunTask(&ecs.RunTaskInput{
+		Cluster:        aws.String(os.Getenv("CLUSTER")),
+		Count:          aws.Int64(1),
+		StartedBy:      aws.String(cronjob.StartedBy()),
+		TaskDefinition: aws.String(resources[UpperName(cronjob.Process())+"ECSTaskDefinition"].Id),
+		Overrides: &ecs.TaskOverride{
+			ContainerOverrides: []*ecs.ContainerOverride{
+				&ecs.ContainerOverride{
+					Name:    aws.String(cronjob.Process()),
+					Command: []*string{aws.String("sh"), aws.String("-c"), aws.String(cronjob.Command)},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.Failures) > 0 {
+		return nil, fmt.Errorf("could not run timer %s: %s", name, *res.Failures[0].Reason)
+	}
+
+	if len(res.Tasks) != 1 {
+		return nil, fmt.Errorf("could not run timer: %s", name)
+	}
+
+	run := timerRunFromTask(name, res.Tasks[0])
+	run.Manual = true
+
+	runs, err := a.TimerRuns(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.saveTimerRuns(name, append(runs, run)); err != nil {
+		return nil, err
+	}
+
+	NotifySuccess("timer:run", map[string]string{"app": a.Name, "timer": name, "id": run.Id})
+
+	return &run, nil
+}
+
+// RecordTimerRuns updates the run history of each of the app's timers with the
+// tasks the scheduler has started since the last check.
+// ECS only reports stopped tasks for a short time so this needs to be called regularly.
+func (a *App) RecordTimerRuns() error {
+	cronjobs, err := a.ActiveCronJobs()
+	if err != nil {
+		return err
+	}
+
+	for _, cj := range cronjobs {
+		arns := []*string{}
+
+		for _, status := range []string{"RUNNING", "STOPPED"} {
+			err := ECS().ListTasksPages(&ecs.ListTasksInput{
+				Cluster:       aws.String(os.Getenv("CLUSTER")),
+				DesiredStatus: aws.String(status),
+				StartedBy:     aws.String(cj.StartedBy()),
+			}, func(page *ecs.ListTasksOutput, last bool) bool {
+				arns = append(arns, page.TaskArns...)
+				return true
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(arns) == 0 {
+			continue
+		}
+
+		runs, err := a.TimerRuns(cj.Name)
+		if err != nil {
+			return err
+		}
+
+		previous := append(TimerRuns{}, runs...)
+
+		known := map[string]int{}
+
+		for i, r := range runs {
+			known[r.Id] = i
+		}
+
+		// DescribeTasks accepts up to 100 tasks at a time
+		for len(arns) > 0 {
+			batch := arns
+			if len(batch) > 100 {
+				batch = batch[:100]
+			}
+			arns = arns[len(batch):]
+
+			res, err := ECS().DescribeTasks(&ecs.DescribeTasksInput{
+				Cluster: aws.String(os.Getenv("CLUSTER")),
+				Tasks:   batch,
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, task := range res.Tasks {
+				run := timerRunFromTask(cj.Name, task)
+
+				if i, ok := known[run.Id]; ok {
+					run.Manual = runs[i].Manual
+					runs[i] = run
+				} else {
+					runs = append(runs, run)
+				}
+			}
+		}
+
+		// most checks find nothing new so only write when the kept history changed
+		if timerRunsEqual(recentTimerRuns(previous), recentTimerRuns(runs)) {
+			continue
+		}
+
+		if err := a.saveTimerRuns(cj.Name, runs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *App) saveTimerRuns(name string, runs TimerRuns) error {
+	data, err := json.Marshal(recentTimerRuns(runs))
+	if err != nil {
+		return err
+	}
+
+	return S3Put(a.Outputs["Settings"], timerKey(name), data, false)
+}
+
+// recentTimerRuns returns the runs that are kept in the history of a timer, latest first
+func recentTimerRuns(runs TimerRuns) TimerRuns {
+	sorted := append(TimerRuns{}, runs...)
+	sort.Sort(sorted)
+
+	if len(sorted) > TimerRunHistory {
+		sorted = sorted[:TimerRunHistory]
+	}
+
+	return sorted
+}
+
+func timerRunsEqual(a, b TimerRuns) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		x, y := a[i], b[i]
+
+		if x.Id != y.Id || x.Status != y.Status || x.ExitCode != y.ExitCode || x.Manual != y.Manual || !x.Started.Equal(y.Started) || !x.Ended.Equal(y.Ended) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func timerKey(name string) string {
+	return fmt.Sprintf("timers/%s.json", name)
+}
+
+func timerRunFromTask(name string, task *ecs.Task) TimerRun {
+	arn := strings.Split(*task.TaskArn, "/")
+
+	run := TimerRun{
+		Id:      arn[len(arn)-1],
+		Timer:   name,
+		Status:  "running",
+		Started: ct(task.CreatedAt),
+		Ended:   ct(task.StoppedAt),
+	}
+
+	if *task.LastStatus == "STOPPED" {
+		run.Status = "failed"
+
+		for _, c := range task.Containers {
+			if c.ExitCode != nil {
+				run.ExitCode = int(*c.ExitCode)
+
+				if run.ExitCode == 0 {
+					run.Status = "succeeded"
+				}
+			}
+		}
+	}
+
+	return run
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimerRunsEqual(t *testing.T) {
+	started := time.Date(2016, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	runs := TimerRuns{
+		{Id: "task1", Timer: "cleanup", Status: "succeeded", Started: started},
+		{Id: "task2", Timer: "cleanup", Status: "running", Started: started.Add(time.Hour)},
+	}
+
+	// the same runs read back from the history in another order and time zone
+	stored := TimerRuns{
+		{Id: "task2", Timer: "cleanup", Status: "running", Started: started.Add(time.Hour).In(time.FixedZone("EST", -5*3600))},
+		{Id: "task1", Timer: "cleanup", Status: "succeeded", Started: started},
+	}
+
+	assert.True(t, timerRunsEqual(recentTimerRuns(runs), recentTimerRuns(stored)))
+
+	stored[0].Status = "succeeded"
+
+	assert.False(t, timerRunsEqual(recentTimerRuns(runs), recentTimerRuns(stored)))
+}
+
+func TestRecentTimerRunsKeepsHistory(t *testing.T) {
+	started := time.Date(2016, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	runs := TimerRuns{}
+
+	for i := 0; i < TimerRunHistory+5; i++ {
+		runs = append(runs, TimerRun{Id: fmt.Sprintf("task%d", i), Started: started.Add(time.Duration(i) * time.Minute)})
+	}
+
+	// runs ECS still reports that are older than the kept history do not change it
+	assert.True(t, timerRunsEqual(recentTimerRuns(runs[5:]), recentTimerRuns(runs)))
+
+	// the runs are sorted in a copy
+	assert.Equal(t, "task0", runs[0].Id)
+}
@@ -0,0 +1,157 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Upload is a file sent to the rack in chunks so that a transfer interrupted by a dropped
+// connection can resume from the last chunk the rack received instead of starting over.
+// Uploads that are never used expire with the uploads/ lifecycle rule of the settings bucket.
+type Upload struct {
+	Id     string `json:"id"`
+	Size   int64  `json:"size"`
+	Offset int64  `json:"offset"`
+
+	// Chunks are the sizes of the chunks received, in order
+	Chunks []int64 `json:"chunks"`
+
+	Created time.Time `json:"created"`
+}
+
+// NewUpload starts an upload of a file of size bytes
+func NewUpload(size int64) (*Upload, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("size must be a positive number")
+	}
+
+	u := &Upload{
+		Id:      generateId("U", 10),
+		Size:    size,
+		Chunks:  []int64{},
+		Created: time.Now().UTC(),
+	}
+
+	if err := u.save(); err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+// GetUpload returns an upload along with how much of it has been received
+func GetUpload(id string) (*Upload, error) {
+	data, err := s3Get(os.Getenv("SETTINGS_BUCKET"), uploadKey(id))
+	if awserrCode(err) == "NoSuchKey" {
+		return nil, fmt.Errorf("no such upload: %s", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var u Upload
+
+	if err := json.Unmarshal(data, &u); err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
+
+// UploadOffsetError is returned when a chunk is not written at the offset the rack has reached
+type UploadOffsetError struct {
+	Offset int64
+}
+
+func (e *UploadOffsetError) Error() string {
+	return fmt.Sprintf("upload is at offset %d", e.Offset)
+}
+
+// Write stores the next chunk of an upload. The offset has to match what the rack has
+// received so far, which lets a client that lost a response find out where to resume.
+func (u *Upload) Write(offset int64, data []byte) error {
+	if offset != u.Offset {
+		return &UploadOffsetError{Offset: u.Offset}
+	}
+
+	if u.Offset+int64(len(data)) > u.Size {
+		return fmt.Errorf("chunk exceeds the upload size of %d bytes", u.Size)
+	}
+
+	if err := S3Put(os.Getenv("SETTINGS_BUCKET"), u.chunkKey(len(u.Chunks)), data, false); err != nil {
+		return err
+	}
+
+	u.Chunks = append(u.Chunks, int64(len(data)))
+	u.Offset += int64(len(data))
+
+	return u.save()
+}
+
+// Complete returns true once every byte of the upload has been received
+func (u *Upload) Complete() bool {
+	return u.Offset == u.Size
+}
+
+// Reader returns the contents of a complete upload, fetching one chunk at a time.
+// The upload is removed once it has been read to the end.
+func (u *Upload) Reader() io.Reader {
+	return &uploadReader{upload: u, current: &bytes.Reader{}}
+}
+
+// Delete removes an upload and its chunks
+func (u *Upload) Delete() error {
+	for i := range u.Chunks {
+		if err := s3Delete(os.Getenv("SETTINGS_BUCKET"), u.chunkKey(i)); err != nil {
+			return err
+		}
+	}
+
+	return s3Delete(os.Getenv("SETTINGS_BUCKET"), uploadKey(u.Id))
+}
+
+func (u *Upload) save() error {
+	data, err := json.Marshal(u)
+	if err != nil {
+		return err
+	}
+
+	return S3Put(os.Getenv("SETTINGS_BUCKET"), uploadKey(u.Id), data, false)
+}
+
+func (u *Upload) chunkKey(i int) string {
+	return fmt.Sprintf("uploads/%s/%06d", u.Id, i)
+}
+
+func uploadKey(id string) string {
+	return fmt.Sprintf("uploads/%s.json", id)
+}
+
+type uploadReader struct {
+	upload  *Upload
+	chunk   int
+	current *bytes.Reader
+}
+
+func (r *uploadReader) Read(p []byte) (int, error) {
+	for r.current.Len() == 0 {
+		if r.chunk == len(r.upload.Chunks) {
+			r.upload.Delete()
+			return 0, io.EOF
+		}
+
+		data, err := s3Get(os.Getenv("SETTINGS_BUCKET"), r.upload.chunkKey(r.chunk))
+		if err != nil {
+			return 0, err
+		}
+
+		r.chunk++
+		r.current = bytes.NewReader(data)
+	}
+
+	return r.current.Read(p)
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUploadWriteValidation(t *testing.T) {
+	u := &Upload{Id: "U1", Size: 5, Offset: 3, Chunks: []int64{3}}
+
+	assert.EqualError(t, u.Write(0, []byte("abc")), "upload is at offset 3")
+	assert.EqualError(t, u.Write(3, []byte("def")), "chunk exceeds the upload size of 5 bytes")
+	assert.False(t, u.Complete())
+
+	u.Offset = 5
+
+	assert.True(t, u.Complete())
+}
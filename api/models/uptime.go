@@ -0,0 +1,211 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/convox/rack/manifest"
+)
+
+// uptimeStep is the resolution of the health checks an uptime report is computed from
+const uptimeStep = 5 * time.Minute
+
+// maxMetricDatapoints is the most datapoints CloudWatch returns for a single request
+const maxMetricDatapoints = 1440
+
+// UptimeReport is the availability of the balanced processes of an app over a calendar month
+type UptimeReport struct {
+	App       string          `json:"app"`
+	Month     string          `json:"month"`
+	Start     time.Time       `json:"start"`
+	End       time.Time       `json:"end"`
+	Processes []ProcessUptime `json:"processes"`
+}
+
+// ProcessUptime is the availability of a single process behind a load balancer.
+// Availability is the percentage of measured time the balancer had a healthy process to
+// send requests to, and SuccessRate is the percentage of requests that did not fail with a 5xx.
+type ProcessUptime struct {
+	Process      string  `json:"process"`
+	Availability float64 `json:"availability"`
+	Downtime     int     `json:"downtime"`
+	Measured     int     `json:"measured"`
+	Requests     int64   `json:"requests"`
+	Errors       int64   `json:"errors"`
+	SuccessRate  float64 `json:"success-rate"`
+}
+
+// ParseReportMonth returns the start and end of a month given as 2006-01. Reports for the
+// current month end now.
+func ParseReportMonth(month string, now time.Time) (time.Time, time.Time, error) {
+	start, err := time.Parse("2006-01", month)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("month must look like 2006-01: %s", month)
+	}
+
+	if start.After(now) {
+		return time.Time{}, time.Time{}, fmt.Errorf("month has not started yet: %s", month)
+	}
+
+	end := start.AddDate(0, 1, 0)
+
+	if end.After(now) {
+		end = now
+	}
+
+	return start, end, nil
+}
+
+// AppUptime computes the uptime report of an app for a month from the health checks and
+// response codes its load balancers report to CloudWatch
+func AppUptime(app, month string) (*UptimeReport, error) {
+	start, end, err := ParseReportMonth(month, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := GetApp(app)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &UptimeReport{
+		App:       app,
+		Month:     month,
+		Start:     start,
+		End:       end,
+		Processes: []ProcessUptime{},
+	}
+
+	if a.Release == "" {
+		return report, nil
+	}
+
+	r, err := GetRelease(app, a.Release)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := manifest.Load([]byte(r.Manifest))
+	if err != nil {
+		return nil, err
+	}
+
+	resources, err := a.Resources()
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+
+	for name := range m.Services {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		bn := m.BalancerResourceName(name)
+		if bn == "" {
+			continue
+		}
+
+		balancer, ok := resources[bn]
+		if !ok {
+			continue
+		}
+
+		health, err := metricPointsBetween(metricQuery{"AWS/ELB", "HealthyHostCount", "Minimum", "LoadBalancerName", balancer.Id}, start, end, uptimeStep)
+		if err != nil {
+			return nil, err
+		}
+
+		pu := ProcessUptime{Process: name}
+
+		measured, down := Downtime(health, uptimeStep)
+
+		pu.Measured = int(measured / time.Minute)
+		pu.Downtime = int(down / time.Minute)
+		pu.Availability = percentage(float64(measured-down), float64(measured))
+
+		for _, metric := range []struct {
+			name  string
+			total *int64
+		}{
+			{"RequestCount", &pu.Requests},
+			{"HTTPCode_Backend_5XX", &pu.Errors},
+			{"HTTPCode_ELB_5XX", &pu.Errors},
+		} {
+			points, err := metricPointsBetween(metricQuery{"AWS/ELB", metric.name, "Sum", "LoadBalancerName", balancer.Id}, start, end, 24*time.Hour)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, p := range points {
+				*metric.total += int64(p.Value)
+			}
+		}
+
+		pu.SuccessRate = percentage(float64(pu.Requests-pu.Errors), float64(pu.Requests))
+
+		report.Processes = append(report.Processes, pu)
+	}
+
+	return report, nil
+}
+
+// Downtime returns how much time the health points cover and how much of it a balancer had
+// no healthy processes. Gaps without a datapoint are not counted either way.
+func Downtime(health MetricPoints, step time.Duration) (measured, down time.Duration) {
+	for _, p := range health {
+		measured += step
+
+		if p.Value < 1 {
+			down += step
+		}
+	}
+
+	return measured, down
+}
+
+// MetricWindows splits a time range into the ranges CloudWatch can return in one request
+func MetricWindows(start, end time.Time, step time.Duration) [][2]time.Time {
+	windows := [][2]time.Time{}
+
+	for from := start; from.Before(end); from = from.Add(step * maxMetricDatapoints) {
+		to := from.Add(step * maxMetricDatapoints)
+
+		if to.After(end) {
+			to = end
+		}
+
+		windows = append(windows, [2]time.Time{from, to})
+	}
+
+	return windows
+}
+
+func metricPointsBetween(q metricQuery, start, end time.Time, step time.Duration) (MetricPoints, error) {
+	points := MetricPoints{}
+
+	for _, w := range MetricWindows(start, end, step) {
+		ps, err := metricPoints(q, w[0], w[1], step)
+		if err != nil {
+			return nil, err
+		}
+
+		points = append(points, ps...)
+	}
+
+	return points, nil
+}
+
+// percentage returns 100 when there is nothing to measure so an idle app is not reported as down
+func percentage(part, total float64) float64 {
+	if total == 0 {
+		return 100
+	}
+
+	return part * 100 / total
+}
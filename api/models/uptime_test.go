@@ -0,0 +1,57 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReportMonth(t *testing.T) {
+	now := time.Date(2016, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	start, end, err := ParseReportMonth("2016-09", now)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2016, 9, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2016, 10, 1, 0, 0, 0, 0, time.UTC), end)
+
+	start, end, err = ParseReportMonth("2016-10", now)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2016, 10, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, now, end)
+
+	_, _, err = ParseReportMonth("2016-11", now)
+	assert.Equal(t, fmt.Errorf("month has not started yet: 2016-11"), err)
+
+	_, _, err = ParseReportMonth("october", now)
+	assert.Equal(t, fmt.Errorf("month must look like 2006-01: october"), err)
+}
+
+func TestDowntime(t *testing.T) {
+	t0 := time.Date(2016, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	health := MetricPoints{
+		{Time: t0, Value: 2},
+		{Time: t0.Add(5 * time.Minute), Value: 0},
+		{Time: t0.Add(10 * time.Minute), Value: 1},
+		{Time: t0.Add(20 * time.Minute), Value: 0},
+	}
+
+	measured, down := Downtime(health, 5*time.Minute)
+
+	assert.Equal(t, 20*time.Minute, measured)
+	assert.Equal(t, 10*time.Minute, down)
+}
+
+func TestMetricWindows(t *testing.T) {
+	start := time.Date(2016, 9, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	windows := MetricWindows(start, end, 5*time.Minute)
+
+	// 1440 five minute datapoints are five days
+	assert.Equal(t, 6, len(windows))
+	assert.Equal(t, [2]time.Time{start, start.AddDate(0, 0, 5)}, windows[0])
+	assert.Equal(t, [2]time.Time{start.AddDate(0, 0, 25), end}, windows[5])
+}
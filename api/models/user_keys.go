@@ -0,0 +1,203 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// userName are the names a user key can be created for
+var userName = regexp.MustCompile(`^[A-Za-z0-9_.@+-]+$`)
+
+// UserKey authenticates a single user to the rack in place of the rack password. Only the hash of
+// the key is stored, the key itself is shown once when it is created. Secret is the key requests
+// of the user are signed with, encrypted with the rack encryption key.
+type UserKey struct {
+	User    string    `json:"user"`
+	Hash    string    `json:"hash,omitempty"`
+	Secret  []byte    `json:"secret,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+// UserKeys are sorted by user
+type UserKeys []UserKey
+
+func (ks UserKeys) Len() int           { return len(ks) }
+func (ks UserKeys) Less(i, j int) bool { return ks[i].User < ks[j].User }
+func (ks UserKeys) Swap(i, j int)      { ks[i], ks[j] = ks[j], ks[i] }
+
+// CreateUserKey makes a new key for user, replacing any key the user had, and returns it
+func CreateUserKey(user string) (string, error) {
+	// convox is the user name sent with the rack password and unknown stands in for missing users
+	if !userName.MatchString(user) || user == "convox" || user == "unknown" {
+		return "", fmt.Errorf("invalid user name: %s", user)
+	}
+
+	data := make([]byte, 32)
+
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+
+	key := hex.EncodeToString(data)
+
+	k := UserKey{
+		User:    user,
+		Hash:    UserKeyHash(key),
+		Created: time.Now().UTC(),
+	}
+
+	// without an encryption key the signing secret can not be stored and the user authenticates with basic auth
+	if ek := os.Getenv("ENCRYPTION_KEY"); ek != "" {
+		secret, err := secretCrypt().Encrypt(ek, []byte(UserKeySigningSecret(key)))
+		if err != nil {
+			return "", err
+		}
+
+		k.Secret = secret
+	}
+
+	enc, err := json.Marshal(k)
+	if err != nil {
+		return "", err
+	}
+
+	if err := S3Put(os.Getenv("SETTINGS_BUCKET"), userKeyKey(user), enc, false); err != nil {
+		return "", err
+	}
+
+	NotifySuccess("user:create", map[string]string{"user": user})
+
+	return key, nil
+}
+
+// ListUserKeys returns the users that have a key, without their hashes
+func ListUserKeys() (UserKeys, error) {
+	keys, err := s3Keys(os.Getenv("SETTINGS_BUCKET"), "users/")
+	if err != nil {
+		return nil, err
+	}
+
+	uks := UserKeys{}
+
+	for _, key := range keys {
+		k, err := getUserKey(strings.TrimSuffix(strings.TrimPrefix(key, "users/"), ".json"))
+		if err != nil {
+			return nil, err
+		}
+
+		k.Hash = ""
+		k.Secret = nil
+
+		uks = append(uks, *k)
+	}
+
+	sort.Sort(uks)
+
+	return uks, nil
+}
+
+// DeleteUserKey removes the key of user so it no longer authenticates
+func DeleteUserKey(user string) error {
+	if _, err := getUserKey(user); err != nil {
+		return err
+	}
+
+	if err := s3Delete(os.Getenv("SETTINGS_BUCKET"), userKeyKey(user)); err != nil {
+		return err
+	}
+
+	NotifySuccess("user:delete", map[string]string{"user": user})
+
+	return nil
+}
+
+// UserKeyHash is what is stored for a key to check basic auth against
+func UserKeyHash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// UserKeySigningSecret is what requests are signed with for a key. It must match the client
+// and can not be derived from the stored hash.
+func UserKeySigningSecret(key string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte("convox-user-key-signing"))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// AuthenticateUserKey returns true if key is the key of user
+func AuthenticateUserKey(user, key string) bool {
+	k, err := getUserKey(user)
+	if err != nil {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(k.Hash), []byte(UserKeyHash(key))) == 1
+}
+
+// UserKeyCanSign returns true if the rack holds the signing secret of the key of user
+func UserKeyCanSign(user string) bool {
+	k, err := getUserKey(user)
+	if err != nil {
+		return false
+	}
+
+	return len(k.Secret) > 0
+}
+
+// UserKeySigningKey returns the secret the requests of user are signed with
+func UserKeySigningKey(user string) (string, error) {
+	k, err := getUserKey(user)
+	if err != nil {
+		return "", err
+	}
+
+	if len(k.Secret) == 0 {
+		return "", fmt.Errorf("key of %s can not sign requests, create a new key with `convox users create`", user)
+	}
+
+	secret, err := secretCrypt().Decrypt(os.Getenv("ENCRYPTION_KEY"), k.Secret)
+	if err != nil {
+		return "", err
+	}
+
+	return string(secret), nil
+}
+
+func getUserKey(user string) (*UserKey, error) {
+	if !userName.MatchString(user) {
+		return nil, fmt.Errorf("no such user: %s", user)
+	}
+
+	data, err := s3Get(os.Getenv("SETTINGS_BUCKET"), userKeyKey(user))
+	if awserrCode(err) == "NoSuchKey" {
+		return nil, fmt.Errorf("no such user: %s", user)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var k UserKey
+
+	if err := json.Unmarshal(data, &k); err != nil {
+		return nil, err
+	}
+
+	return &k, nil
+}
+
+func userKeyKey(user string) string {
+	return fmt.Sprintf("users/%s.json", user)
+}
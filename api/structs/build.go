@@ -1,7 +1,10 @@
 package structs
 
 import (
+	"fmt"
 	"math/rand"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -16,13 +19,83 @@ type Build struct {
 	Reason string `json:"reason"`
 
 	Description string `json:"description"`
+	Priority    string `json:"priority"`
+
+	// GitSha is the commit the build was made from, if its source was a git repository
+	GitSha string `json:"git-sha"`
+
+	// Images maps each process to the image it was pushed as, including its digest
+	Images map[string]string `json:"images"`
+
+	// Timings show where the time of the build went, if the builder reported them
+	Timings *BuildTimings `json:"timings,omitempty"`
+
+	// Architectures are the cpu architectures a matrix build made images for. Each image is
+	// tagged with the build id and the architecture, i.e. web.BABCDEFGHIJ-arm64
+	Architectures []string `json:"architectures,omitempty"`
 
 	Started time.Time `json:"started"`
 	Ended   time.Time `json:"ended"`
 }
 
+// BuildTimings are how long each stage of a build took and how long each docker
+// build step took along with whether it came from the layer cache
+type BuildTimings struct {
+	Stages []BuildStageTiming `json:"stages"`
+	Steps  []BuildStepTiming  `json:"steps"`
+}
+
+type BuildStageTiming struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+}
+
+type BuildStepTiming struct {
+	Service  string        `json:"service"`
+	Step     string        `json:"step"`
+	Cached   bool          `json:"cached"`
+	Duration time.Duration `json:"duration"`
+}
+
 type Builds []Build
 
+// BuildPriorities are the priorities a build can be queued with, highest first
+var BuildPriorities = []string{"high", "normal", "low"}
+
+// Expired returns the builds a retention of the newest retain builds would prune, newest first.
+// Builds that are queued or in progress, builds that are already archived and the builds in keep,
+// like those of running releases, are never expired.
+func (bs Builds) Expired(retain int, keep []string) Builds {
+	sorted := make(Builds, len(bs))
+	copy(sorted, bs)
+	sort.Sort(sort.Reverse(sorted))
+
+	kept := map[string]bool{}
+
+	for _, id := range keep {
+		kept[id] = true
+	}
+
+	expired := Builds{}
+
+	for i, b := range sorted {
+		switch {
+		case i < retain:
+		case kept[b.Id]:
+		case b.Status == "queued" || b.Status == "created" || b.Status == "running":
+		case b.Status == "archived":
+		default:
+			expired = append(expired, b)
+		}
+	}
+
+	return expired
+}
+
+func (bs Builds) Len() int           { return len(bs) }
+func (bs Builds) Less(i, j int) bool { return bs[i].Started.Before(bs[j].Started) }
+func (bs Builds) Swap(i, j int)      { bs[i], bs[j] = bs[j], bs[i] }
+
 func NewBuild(app string) *Build {
 	return &Build{
 		App:    app,
@@ -31,6 +104,59 @@ func NewBuild(app string) *Build {
 	}
 }
 
+// ValidBuildPriority returns an error if priority is not one of BuildPriorities
+func ValidBuildPriority(priority string) error {
+	for _, p := range BuildPriorities {
+		if p == priority {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("priority must be one of: %s", strings.Join(BuildPriorities, ", "))
+}
+
+// BuildArchitectures are the cpu architectures a build matrix can make images for
+var BuildArchitectures = []string{"amd64", "arm64"}
+
+// ParseBuildMatrix returns the architectures of a matrix written as arch=amd64,arm64
+func ParseBuildMatrix(matrix string) ([]string, error) {
+	if matrix == "" {
+		return nil, nil
+	}
+
+	parts := strings.SplitN(matrix, "=", 2)
+
+	if len(parts) != 2 || parts[0] != "arch" {
+		return nil, fmt.Errorf("matrix must be written as arch=<architecture>,...")
+	}
+
+	archs := []string{}
+	seen := map[string]bool{}
+
+	for _, arch := range strings.Split(parts[1], ",") {
+		arch = strings.TrimSpace(arch)
+
+		valid := false
+
+		for _, a := range BuildArchitectures {
+			if a == arch {
+				valid = true
+			}
+		}
+
+		if !valid {
+			return nil, fmt.Errorf("architecture must be one of: %s", strings.Join(BuildArchitectures, ", "))
+		}
+
+		if !seen[arch] {
+			archs = append(archs, arch)
+			seen[arch] = true
+		}
+	}
+
+	return archs, nil
+}
+
 var idAlphabet = []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")
 
 func generateId(prefix string, size int) string {
@@ -0,0 +1,60 @@
+package structs_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/convox/rack/api/structs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildsExpired(t *testing.T) {
+	now := time.Date(2016, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	builds := structs.Builds{
+		{Id: "B1", Status: "complete", Started: now.Add(-5 * time.Hour)},
+		{Id: "B5", Status: "running", Started: now},
+		{Id: "B2", Status: "failed", Started: now.Add(-4 * time.Hour)},
+		{Id: "B4", Status: "complete", Started: now.Add(-1 * time.Hour)},
+		{Id: "B3", Status: "complete", Started: now.Add(-3 * time.Hour)},
+		{Id: "B0", Status: "running", Started: now.Add(-6 * time.Hour)},
+		{Id: "B6", Status: "queued", Started: now.Add(-7 * time.Hour)},
+		{Id: "B7", Status: "archived", Started: now.Add(-8 * time.Hour)},
+	}
+
+	ids := func(bs structs.Builds) []string {
+		s := []string{}
+		for _, b := range bs {
+			s = append(s, b.Id)
+		}
+		return s
+	}
+
+	assert.Equal(t, []string{"B3", "B2", "B1"}, ids(builds.Expired(2, nil)))
+	assert.Equal(t, []string{"B2", "B1"}, ids(builds.Expired(2, []string{"B3"})))
+	assert.Equal(t, []string{"B2"}, ids(builds.Expired(2, []string{"B3", "B1"})))
+	assert.Equal(t, []string{}, ids(builds.Expired(10, nil)))
+
+	// the input order is left alone
+	assert.Equal(t, "B1", builds[0].Id)
+}
+
+func TestParseBuildMatrix(t *testing.T) {
+	archs, err := structs.ParseBuildMatrix("arch=amd64,arm64")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"amd64", "arm64"}, archs)
+
+	archs, err = structs.ParseBuildMatrix("arch=arm64, arm64")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"arm64"}, archs)
+
+	archs, err = structs.ParseBuildMatrix("")
+	assert.NoError(t, err)
+	assert.Nil(t, archs)
+
+	_, err = structs.ParseBuildMatrix("amd64,arm64")
+	assert.EqualError(t, err, "matrix must be written as arch=<architecture>,...")
+
+	_, err = structs.ParseBuildMatrix("arch=amd64,ppc64le")
+	assert.EqualError(t, err, "architecture must be one of: amd64, arm64")
+}
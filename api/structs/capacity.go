@@ -1,5 +1,7 @@
 package structs
 
+import "fmt"
+
 type Capacity struct {
 	ClusterCPU     int64 `json:"cluster-cpu"`
 	ClusterMemory  int64 `json:"cluster-memory"`
@@ -10,3 +12,77 @@ type Capacity struct {
 	ProcessMemory  int64 `json:"process-memory"`
 	ProcessWidth   int64 `json:"process-width"`
 }
+
+// CapacityError is returned when a formation change does not fit on the rack's current instances
+type CapacityError struct {
+	Resource  string `json:"resource"`
+	Required  int64  `json:"required"`
+	Available int64  `json:"available"`
+	Total     int64  `json:"total"`
+	Instances int    `json:"instances"`
+}
+
+func (e *CapacityError) Error() string {
+	return fmt.Sprintf("insufficient cluster %s: %d required, %d available of %d, %d more instances needed", e.Resource, e.Required, e.Available, e.Total, e.Instances)
+}
+
+// FormationFit checks whether changing a process from before to after fits in the cluster.
+// Only changes that grow the resources a process needs are ever refused.
+// Returns a CapacityError describing the headroom and the instances needed if it does not.
+func (c Capacity) FormationFit(before, after ProcessFormation) *CapacityError {
+	var worst *CapacityError
+
+	for _, r := range []struct {
+		name     string
+		total    int64
+		used     int64
+		instance int64
+		before   int
+		after    int
+	}{
+		{"cpu", c.ClusterCPU, c.ProcessCPU, c.InstanceCPU, before.CPU, after.CPU},
+		{"memory", c.ClusterMemory, c.ProcessMemory, c.InstanceMemory, before.Memory, after.Memory},
+	} {
+		if r.instance <= 0 {
+			continue
+		}
+
+		held := reserved(before.Count, r.before)
+		others := r.used - held
+		required := reserved(after.Count, r.after)
+
+		// a change that needs no more than it already holds always fits, even on an over-committed cluster
+		if required <= held || others+required <= r.total {
+			continue
+		}
+
+		instances := int((others + required - r.total + r.instance - 1) / r.instance)
+
+		if worst == nil || instances > worst.Instances {
+			available := r.total - others
+
+			if available < 0 {
+				available = 0
+			}
+
+			worst = &CapacityError{
+				Resource:  r.name,
+				Required:  required,
+				Available: available,
+				Total:     r.total,
+				Instances: instances,
+			}
+		}
+	}
+
+	return worst
+}
+
+// reserved returns the resources held by count processes, treating negative counts as none running
+func reserved(count, size int) int64 {
+	if count < 0 {
+		return 0
+	}
+
+	return int64(count * size)
+}
@@ -8,6 +8,17 @@ type ProcessFormation struct {
 	Memory   int    `json:"memory"`
 	CPU      int    `json:"cpu"`
 	Ports    []int  `json:"ports"`
+
+	// Autoscale is nil when the count of the process is only changed by hand
+	Autoscale *ProcessAutoscale `json:"autoscale,omitempty"`
+}
+
+// ProcessAutoscale keeps the count of a process between Min and Max, adding a process
+// while the average CPU utilization of its service is above CPU percent
+type ProcessAutoscale struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+	CPU int `json:"cpu"`
 }
 
 // Formation represents the formation for an App
@@ -3,6 +3,8 @@ package structs
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/fsouza/go-dockerclient"
@@ -18,10 +20,27 @@ type Instance struct {
 	PublicIp  string    `json:"public-ip"`
 	Status    string    `json:"status"`
 	Started   time.Time `json:"started"`
+
+	// Disk is only included when stats are requested
+	Disk *InstanceDisk `json:"disk,omitempty"`
 }
 
 type Instances []Instance
 
+// InstanceDisk is the usage in bytes of the storage docker keeps images and containers on
+type InstanceDisk struct {
+	Used  int64 `json:"used"`
+	Total int64 `json:"total"`
+}
+
+func (d InstanceDisk) PercentUsed() float64 {
+	if d.Total == 0 {
+		return 0
+	}
+
+	return float64(d.Used) / float64(d.Total)
+}
+
 type InstanceResource struct {
 	Total int `json:"total"`
 	Free  int `json:"free"`
@@ -51,3 +70,80 @@ func (i *Instance) DockerHost() string {
 func (i *Instance) DockerClient() (*docker.Client, error) {
 	return docker.NewClient(i.DockerHost())
 }
+
+// DiskUsage returns the usage of the docker storage of the instance. Only storage
+// drivers that report their usage, such as devicemapper, are supported.
+func (i *Instance) DiskUsage() (*InstanceDisk, error) {
+	d, err := i.DockerClient()
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := d.Info()
+	if err != nil {
+		return nil, err
+	}
+
+	var status [][2]string
+
+	if err := info.GetJSON("DriverStatus", &status); err != nil {
+		return nil, err
+	}
+
+	return parseDriverStatus(status)
+}
+
+func parseDriverStatus(status [][2]string) (*InstanceDisk, error) {
+	disk := &InstanceDisk{}
+
+	for _, s := range status {
+		var err error
+
+		switch s[0] {
+		case "Data Space Used":
+			disk.Used, err = parseSize(s[1])
+		case "Data Space Total":
+			disk.Total, err = parseSize(s[1])
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if disk.Total == 0 {
+		return nil, fmt.Errorf("storage driver does not report disk usage")
+	}
+
+	return disk, nil
+}
+
+var sizeUnits = map[string]float64{
+	"B":  1,
+	"kB": 1000,
+	"KB": 1000,
+	"MB": 1000 * 1000,
+	"GB": 1000 * 1000 * 1000,
+	"TB": 1000 * 1000 * 1000 * 1000,
+}
+
+// parseSize converts a size reported by docker such as "1.234 GB" to bytes
+func parseSize(size string) (int64, error) {
+	parts := strings.Fields(size)
+
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("invalid size: %s", size)
+	}
+
+	unit, ok := sizeUnits[parts[1]]
+	if !ok {
+		return 0, fmt.Errorf("invalid size: %s", size)
+	}
+
+	n, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size: %s", size)
+	}
+
+	return int64(n * unit), nil
+}
@@ -0,0 +1,149 @@
+package structs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sync"
+)
+
+// LogScrubRedacted replaces the text removed by a LogScrubRule
+const LogScrubRedacted = "[REDACTED]"
+
+var logScrubRuleName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// LogScrubRule removes personal data from the logs of an app before they are stored.
+// Either Pattern is a regular expression whose matches are redacted, or Field is the
+// name of a key=value or json field whose value is redacted.
+type LogScrubRule struct {
+	Name    string `json:"name"`
+	Field   string `json:"field,omitempty"`
+	Pattern string `json:"pattern,omitempty"`
+}
+
+type LogScrubRules []LogScrubRule
+
+// Validate returns an error if the rule is missing its name or can not be compiled
+func (r LogScrubRule) Validate() error {
+	if !logScrubRuleName.MatchString(r.Name) {
+		return fmt.Errorf("name must contain only lowercase letters, numbers and dashes")
+	}
+
+	if (r.Field == "") == (r.Pattern == "") {
+		return fmt.Errorf("must specify either a field or a pattern")
+	}
+
+	_, err := r.compile()
+
+	return err
+}
+
+// Find returns the rule with the given name
+func (rs LogScrubRules) Find(name string) (*LogScrubRule, bool) {
+	for i := range rs {
+		if rs[i].Name == name {
+			return &rs[i], true
+		}
+	}
+
+	return nil, false
+}
+
+func (r LogScrubRule) compile() (*regexp.Regexp, error) {
+	if r.Field != "" {
+		// the value after field=, field: or "field": up to the next quote, space, comma, ampersand or brace
+		return regexp.MustCompile(fmt.Sprintf(`(?i)(\b%s"?\s*[=:]\s*"?)[^"\s,&}]+`, regexp.QuoteMeta(r.Field))), nil
+	}
+
+	re, err := regexp.Compile(r.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %s", err)
+	}
+
+	return re, nil
+}
+
+// LogScrubber applies a set of rules to log lines
+type LogScrubber struct {
+	fields   []*regexp.Regexp
+	patterns []*regexp.Regexp
+}
+
+// NewLogScrubber compiles rules into a scrubber
+func NewLogScrubber(rules LogScrubRules) (*LogScrubber, error) {
+	s := &LogScrubber{}
+
+	for _, r := range rules {
+		re, err := r.compile()
+		if err != nil {
+			return nil, fmt.Errorf("log scrub rule %s: %s", r.Name, err)
+		}
+
+		if r.Field != "" {
+			s.fields = append(s.fields, re)
+		} else {
+			s.patterns = append(s.patterns, re)
+		}
+	}
+
+	return s, nil
+}
+
+// Scrub returns line with everything matched by a rule redacted
+func (s *LogScrubber) Scrub(line string) string {
+	for _, re := range s.fields {
+		line = re.ReplaceAllString(line, "${1}"+LogScrubRedacted)
+	}
+
+	for _, re := range s.patterns {
+		line = re.ReplaceAllString(line, LogScrubRedacted)
+	}
+
+	return line
+}
+
+// Writer returns a writer that scrubs each complete line before writing it to w.
+// Scrubbers without rules return w unchanged.
+func (s *LogScrubber) Writer(w io.Writer) io.Writer {
+	if len(s.fields) == 0 && len(s.patterns) == 0 {
+		return w
+	}
+
+	return &logScrubWriter{scrubber: s, w: w}
+}
+
+type logScrubWriter struct {
+	sync.Mutex
+	scrubber *LogScrubber
+	w        io.Writer
+	partial  []byte
+}
+
+func (lw *logScrubWriter) Write(p []byte) (int, error) {
+	lw.Lock()
+	defer lw.Unlock()
+
+	data := append(lw.partial, p...)
+
+	i := bytes.LastIndexByte(data, '\n')
+	if i < 0 {
+		lw.partial = data
+		return len(p), nil
+	}
+
+	lw.partial = append([]byte{}, data[i+1:]...)
+
+	lines := bytes.SplitAfter(data[:i+1], []byte("\n"))
+	out := make([]byte, 0, i+1)
+
+	for _, line := range lines {
+		out = append(out, lw.scrubber.Scrub(string(line))...)
+	}
+
+	if _, err := lw.w.Write(out); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,40 @@
+package structs_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/convox/rack/api/structs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogScrubber(t *testing.T) {
+	s, err := structs.NewLogScrubber(structs.LogScrubRules{
+		{Name: "email", Field: "email"},
+		{Name: "cards", Pattern: `\b\d{4}-\d{4}-\d{4}-\d{4}\b`},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Equal(t, "user signup email=[REDACTED] plan=free", s.Scrub("user signup email=jane@example.org plan=free"))
+	assert.Equal(t, `{"Email": "[REDACTED]", "id": 4}`, s.Scrub(`{"Email": "jane@example.org", "id": 4}`))
+	assert.Equal(t, "charged [REDACTED] 10.00", s.Scrub("charged 4242-4242-4242-4242 10.00"))
+	assert.Equal(t, "emails=3", s.Scrub("emails=3"))
+
+	var buf bytes.Buffer
+	w := s.Writer(&buf)
+
+	w.Write([]byte("first email=a@b.c\nsecond ema"))
+	assert.Equal(t, "first email=[REDACTED]\n", buf.String())
+
+	w.Write([]byte("il=d@e.f\n"))
+	assert.Equal(t, "first email=[REDACTED]\nsecond email=[REDACTED]\n", buf.String())
+}
+
+func TestLogScrubRuleValidate(t *testing.T) {
+	assert.Nil(t, structs.LogScrubRule{Name: "email", Field: "email"}.Validate())
+	assert.EqualError(t, structs.LogScrubRule{Name: "Email", Field: "email"}.Validate(), "name must contain only lowercase letters, numbers and dashes")
+	assert.EqualError(t, structs.LogScrubRule{Name: "email"}.Validate(), "must specify either a field or a pattern")
+	assert.EqualError(t, structs.LogScrubRule{Name: "bad", Pattern: "("}.Validate(), "invalid pattern: error parsing regexp: missing closing ): `(`")
+}
@@ -2,8 +2,21 @@ package structs
 
 import "time"
 
+// BuildOptions are how a build is made from any of its sources
+type BuildOptions struct {
+	Architectures []string `json:"architectures"`
+	BuildArgs     []string `json:"build-args"`
+	Cache         bool     `json:"cache"`
+	Concurrency   int      `json:"concurrency"`
+	Description   string   `json:"description"`
+	Manifest      string   `json:"manifest"`
+	Priority      string   `json:"priority"`
+	Strict        string   `json:"strict"`
+}
+
 type LogStreamOptions struct {
-	Filter string        `json:"filter"`
-	Follow bool          `json:"follow"`
-	Since  time.Duration `json:"since"`
+	Filter  string        `json:"filter"`
+	Follow  bool          `json:"follow"`
+	Process string        `json:"process"`
+	Since   time.Duration `json:"since"`
 }
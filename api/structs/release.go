@@ -9,6 +9,9 @@ type Release struct {
 	Env      string    `json:"env"`
 	Manifest string    `json:"manifest"`
 	Created  time.Time `json:"created"`
+
+	// CreatedBy is the user that created the release, if known
+	CreatedBy string `json:"created-by,omitempty"`
 }
 
 type Releases []Release
@@ -0,0 +1,180 @@
+package structs
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ReleaseDiff describes what promoting one release in place of another changes
+type ReleaseDiff struct {
+	App  string `json:"app"`
+	From string `json:"from"`
+	To   string `json:"to"`
+
+	Build    *ReleaseDiffBuild  `json:"build,omitempty"`
+	Env      ReleaseDiffEntries `json:"env"`
+	Services ReleaseDiffEntries `json:"services"`
+}
+
+// ReleaseDiffBuild is set when two releases run different builds
+type ReleaseDiffBuild struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ReleaseDiffEntry is an environment variable or service that was added, removed or changed.
+// Environment values are left out so a diff never reveals secrets.
+type ReleaseDiffEntry struct {
+	Name   string `json:"name"`
+	Change string `json:"change"`
+
+	// Fields are the changed fields of a service definition
+	Fields []string `json:"fields,omitempty"`
+}
+
+type ReleaseDiffEntries []ReleaseDiffEntry
+
+// Empty returns true if the releases run the same build, services and environment
+func (d *ReleaseDiff) Empty() bool {
+	return d.Build == nil && len(d.Env) == 0 && len(d.Services) == 0
+}
+
+// DiffReleases compares the build, manifest and environment of two releases
+func DiffReleases(from, to Release) (*ReleaseDiff, error) {
+	d := &ReleaseDiff{
+		App:      to.App,
+		From:     from.Id,
+		To:       to.Id,
+		Env:      ReleaseDiffEntries{},
+		Services: ReleaseDiffEntries{},
+	}
+
+	if from.Build != to.Build {
+		d.Build = &ReleaseDiffBuild{From: from.Build, To: to.Build}
+	}
+
+	before, err := manifestServices(from.Manifest)
+	if err != nil {
+		return nil, fmt.Errorf("release %s: %s", from.Id, err)
+	}
+
+	after, err := manifestServices(to.Manifest)
+	if err != nil {
+		return nil, fmt.Errorf("release %s: %s", to.Id, err)
+	}
+
+	for _, name := range unionKeys(before, after) {
+		b, inBefore := before[name]
+		a, inAfter := after[name]
+
+		switch {
+		case !inBefore:
+			d.Services = append(d.Services, ReleaseDiffEntry{Name: name, Change: "added"})
+		case !inAfter:
+			d.Services = append(d.Services, ReleaseDiffEntry{Name: name, Change: "removed"})
+		default:
+			fields := []string{}
+
+			for _, field := range unionKeys(b, a) {
+				if !reflect.DeepEqual(b[field], a[field]) {
+					fields = append(fields, field)
+				}
+			}
+
+			if len(fields) > 0 {
+				d.Services = append(d.Services, ReleaseDiffEntry{Name: name, Change: "changed", Fields: fields})
+			}
+		}
+	}
+
+	fenv := releaseEnv(from.Env)
+	tenv := releaseEnv(to.Env)
+
+	for _, key := range unionKeys(fenv, tenv) {
+		fv, inBefore := fenv[key]
+		tv, inAfter := tenv[key]
+
+		switch {
+		case !inBefore:
+			d.Env = append(d.Env, ReleaseDiffEntry{Name: key, Change: "added"})
+		case !inAfter:
+			d.Env = append(d.Env, ReleaseDiffEntry{Name: key, Change: "removed"})
+		case fv != tv:
+			d.Env = append(d.Env, ReleaseDiffEntry{Name: key, Change: "changed"})
+		}
+	}
+
+	return d, nil
+}
+
+// manifestServices returns the fields of each service in a version 1 or 2 manifest
+func manifestServices(data string) (map[string]map[string]interface{}, error) {
+	var m map[string]interface{}
+
+	if err := yaml.Unmarshal([]byte(data), &m); err != nil {
+		return nil, fmt.Errorf("error loading manifest: %s", err)
+	}
+
+	raw := map[interface{}]interface{}{}
+
+	if _, ok := m["version"]; ok {
+		if ss, ok := m["services"].(map[interface{}]interface{}); ok {
+			raw = ss
+		}
+	} else {
+		for name, s := range m {
+			raw[name] = s
+		}
+	}
+
+	services := map[string]map[string]interface{}{}
+
+	for name, s := range raw {
+		fields := map[string]interface{}{}
+
+		if sm, ok := s.(map[interface{}]interface{}); ok {
+			for k, v := range sm {
+				fields[fmt.Sprintf("%v", k)] = v
+			}
+		}
+
+		services[fmt.Sprintf("%v", name)] = fields
+	}
+
+	return services, nil
+}
+
+func releaseEnv(env string) map[string]string {
+	vars := map[string]string{}
+
+	for _, line := range strings.Split(env, "\n") {
+		if parts := strings.SplitN(line, "=", 2); len(parts) == 2 {
+			vars[parts[0]] = parts[1]
+		}
+	}
+
+	return vars
+}
+
+// unionKeys returns the sorted keys present in either map
+func unionKeys(a, b interface{}) []string {
+	seen := map[string]bool{}
+	keys := []string{}
+
+	for _, m := range []interface{}{a, b} {
+		for _, k := range reflect.ValueOf(m).MapKeys() {
+			if key := k.String(); !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
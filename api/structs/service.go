@@ -1,5 +1,7 @@
 package structs
 
+import "time"
+
 type Service struct {
 	Name         string `json:"name"`
 	Stack        string `json:"-"`
@@ -9,6 +11,7 @@ type Service struct {
 
 	Apps    Apps              `json:"apps"`
 	Exports map[string]string `json:"exports"`
+	Links   ServiceLinks      `json:"links,omitempty"`
 
 	Outputs    map[string]string `json:"-"`
 	Parameters map[string]string `json:"-"`
@@ -16,3 +19,14 @@ type Service struct {
 }
 
 type Services []Service
+
+// ServiceLink is an app that has the url of a service in its environment
+type ServiceLink struct {
+	App      string    `json:"app"`
+	Variable string    `json:"variable"`
+	Release  string    `json:"release"`
+	User     string    `json:"user"`
+	Created  time.Time `json:"created"`
+}
+
+type ServiceLinks []ServiceLink
@@ -8,3 +8,25 @@ type System struct {
 	Type    string `json:"type"`
 	Version string `json:"version"`
 }
+
+// SystemCheck is the result of a single pre-flight check for a rack update
+type SystemCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+type SystemChecks []SystemCheck
+
+// Failed returns the names of the checks that should block an update
+func (cs SystemChecks) Failed() []string {
+	failed := []string{}
+
+	for _, c := range cs {
+		if c.Status == "fail" {
+			failed = append(failed, c.Name)
+		}
+	}
+
+	return failed
+}
@@ -0,0 +1,107 @@
+package workers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/convox/logger"
+	"github.com/convox/rack/api/helpers"
+	"github.com/convox/rack/api/models"
+)
+
+// alarmStates is the last state seen for each alarm so that a notification is only sent when it changes
+var alarmStates = map[string]string{}
+
+// StartAlarms watches the anomaly detection and custom alarms of every app and sends a
+// notification when one is triggered or resolved
+func StartAlarms() {
+	log := logger.New("ns=workers.alarms")
+
+	defer recoverWith(func(err error) {
+		helpers.Error(log, err)
+	})
+
+	checkAlarms()
+
+	for range time.Tick(1 * time.Minute) {
+		checkAlarms()
+	}
+}
+
+func checkAlarms() {
+	log := logger.New("ns=workers.alarms").At("checkAlarms")
+
+	apps, err := models.ListApps()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	for i := range apps {
+		a := &apps[i]
+
+		syncCustomAlarms(a)
+
+		if !a.HasAlarms() {
+			continue
+		}
+
+		alarms, err := a.Alarms()
+		if err != nil {
+			log.Namespace("app=%s", a.Name).Error(err)
+			continue
+		}
+
+		for _, alarm := range alarms {
+			data := map[string]string{
+				"app":     a.Name,
+				"process": alarm.Process,
+				"metric":  alarm.Metric,
+				"alarm":   alarm.Name,
+			}
+
+			switch models.AlarmTransition(alarmStates[alarm.Name], alarm) {
+			case "triggered":
+				models.NotifyError("alarm:triggered", fmt.Errorf("%s", alarm.Reason), data)
+			case "resolved":
+				models.NotifySuccess("alarm:resolved", data)
+			}
+
+			alarmStates[alarm.Name] = alarm.State
+		}
+	}
+}
+
+// syncCustomAlarms recreates the custom alarms of an app whose resources have been replaced
+// and sends their notifications
+func syncCustomAlarms(a *models.App) {
+	log := logger.New("ns=workers.alarms").At("syncCustomAlarms").Namespace("app=%s", a.Name)
+
+	if a.Release == "" {
+		return
+	}
+
+	alarms, err := models.ListCustomAlarms(a.Name)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	for i := range alarms {
+		c := &alarms[i]
+
+		transition, err := c.Sync(a)
+		if err != nil {
+			log.Namespace("alarm=%s", c.Id).Error(err)
+			continue
+		}
+
+		if transition == "" {
+			continue
+		}
+
+		if err := c.Notify(transition); err != nil {
+			log.Namespace("alarm=%s", c.Id).Error(err)
+		}
+	}
+}
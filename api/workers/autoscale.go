@@ -1,12 +1,17 @@
 package workers
 
 import (
+	"fmt"
 	"math"
 	"os"
+	"strconv"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
 	"github.com/convox/logger"
 	"github.com/convox/rack/api/models"
+	"github.com/convox/rack/manifest"
 )
 
 var (
@@ -16,9 +21,11 @@ var (
 
 func StartAutoscale() {
 	autoscaleRack()
+	autoscaleProcesses()
 
 	for range time.Tick(tick) {
 		autoscaleRack()
+		autoscaleProcesses()
 	}
 }
 
@@ -81,6 +88,8 @@ func autoscaleRack() {
 
 	log.Logf("change=%d", (desired - system.Count))
 
+	previous := system.Count
+
 	system.Count = desired
 
 	err = models.Provider().SystemSave(*system)
@@ -88,4 +97,163 @@ func autoscaleRack() {
 		log.Error(err)
 		return
 	}
+
+	err = models.RecordRackEvent("rack:scale", "success", map[string]string{
+		"count":    strconv.Itoa(desired),
+		"previous": strconv.Itoa(previous),
+		"message":  "rack autoscaled",
+	})
+	if err != nil {
+		log.Error(err)
+	}
+}
+
+// autoscaleProcesses scales processes that have a metric-based autoscaling policy
+// (e.g. SQS queue depth) configured with convox.autoscale.* labels
+func autoscaleProcesses() {
+	log := logger.New("ns=workers.autoscale").At("autoscaleProcesses")
+
+	apps, err := models.ListApps()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	for _, a := range apps {
+		if a.Release == "" || a.Status != "running" {
+			continue
+		}
+
+		if err := autoscaleApp(a.Name, a.Release); err != nil {
+			log.Namespace("app=%s", a.Name).Error(err)
+		}
+	}
+}
+
+func autoscaleApp(app, release string) error {
+	log := logger.New("ns=workers.autoscale").At("autoscaleApp").Namespace("app=%s", app)
+
+	r, err := models.Provider().ReleaseGet(app, release)
+	if err != nil {
+		return err
+	}
+
+	m, err := manifest.Load([]byte(r.Manifest))
+	if err != nil {
+		return err
+	}
+
+	// an error with one process is logged so the processes after it are still scaled
+	for _, s := range m.Services {
+		am, err := s.Autoscale()
+		if err != nil {
+			log.Namespace("process=%s", s.Name).Error(err)
+			continue
+		}
+		if am == nil {
+			continue
+		}
+
+		value, err := autoscaleMetricValue(am)
+		if err != nil {
+			log.Namespace("process=%s", s.Name).Error(err)
+			continue
+		}
+
+		pf, err := models.Provider().FormationGet(app, s.Name)
+		if err != nil {
+			log.Namespace("process=%s", s.Name).Error(err)
+			continue
+		}
+
+		// processes running one per instance are not scaled by count
+		if pf.Count < 0 {
+			continue
+		}
+
+		// processes scaled on cpu by their own scaling policies are left to them
+		if pf.Autoscale != nil {
+			continue
+		}
+
+		desired := am.Desired(value)
+
+		if pf.Count == desired {
+			continue
+		}
+
+		log.Logf("process=%s metric=%s value=%f count=%d desired=%d", s.Name, am.Name, value, pf.Count, desired)
+
+		previous := pf.Count
+
+		pf.Count = desired
+
+		// the app stack will be updating so leave any other processes for the next tick
+		if err := models.Provider().FormationSave(app, pf); err != nil {
+			log.Namespace("process=%s", s.Name).Error(err)
+			continue
+		}
+
+		err = models.RecordRackEvent("release:scale", "success", map[string]string{
+			"app":      app,
+			"process":  s.Name,
+			"count":    strconv.Itoa(desired),
+			"previous": strconv.Itoa(previous),
+			"message":  fmt.Sprintf("%s autoscaled on %s", s.Name, am.Name),
+		})
+		if err != nil {
+			log.Error(err)
+		}
+
+		return nil
+	}
+
+	return nil
+}
+
+// autoscaleMetricValue returns the most recent datapoint for an autoscale metric over the last five minutes
+func autoscaleMetricValue(am *manifest.AutoscaleMetric) (float64, error) {
+	dimensions := []*cloudwatch.Dimension{}
+
+	for k, v := range am.Dimensions {
+		dimensions = append(dimensions, &cloudwatch.Dimension{Name: aws.String(k), Value: aws.String(v)})
+	}
+
+	res, err := models.CloudWatch().GetMetricStatistics(&cloudwatch.GetMetricStatisticsInput{
+		Dimensions: dimensions,
+		EndTime:    aws.Time(time.Now()),
+		MetricName: aws.String(am.Name),
+		Namespace:  aws.String(am.Namespace),
+		Period:     aws.Int64(60),
+		StartTime:  aws.Time(time.Now().Add(-5 * time.Minute)),
+		Statistics: []*string{aws.String(am.Statistic)},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if len(res.Datapoints) == 0 {
+		return 0, fmt.Errorf("no datapoints for metric: %s %s", am.Namespace, am.Name)
+	}
+
+	latest := res.Datapoints[0]
+
+	for _, d := range res.Datapoints {
+		if d.Timestamp.After(*latest.Timestamp) {
+			latest = d
+		}
+	}
+
+	switch am.Statistic {
+	case "Maximum":
+		return *latest.Maximum, nil
+	case "Minimum":
+		return *latest.Minimum, nil
+	case "Sum":
+		return *latest.Sum, nil
+	case "SampleCount":
+		return *latest.SampleCount, nil
+	}
+
+	return *latest.Average, nil
 }
@@ -0,0 +1,39 @@
+package workers
+
+import (
+	"time"
+
+	"github.com/convox/logger"
+	"github.com/convox/rack/api/helpers"
+	"github.com/convox/rack/api/models"
+)
+
+// StartBreakGlass records break glass grants that have run out as expired. Grants stop allowing
+// access as soon as they expire whether or not this has run.
+func StartBreakGlass() {
+	log := logger.New("ns=workers.breakglass")
+
+	defer recoverWith(func(err error) {
+		helpers.Error(log, err)
+	})
+
+	for range time.Tick(5 * time.Minute) {
+		expireBreakGlass()
+	}
+}
+
+func expireBreakGlass() {
+	log := logger.New("ns=workers.breakglass").At("expireBreakGlass")
+
+	apps, err := models.ListApps()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	for _, a := range apps {
+		if err := models.ExpireBreakGlass(a.Name); err != nil {
+			log.Namespace("app=%s", a.Name).Error(err)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package workers
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/convox/logger"
+	"github.com/convox/rack/api/helpers"
+	"github.com/convox/rack/api/models"
+)
+
+// StartBuildRetention archives the builds and prunes the images of apps that set a BuildRetention
+func StartBuildRetention() {
+	log := logger.New("ns=workers.build_retention")
+
+	defer recoverWith(func(err error) {
+		helpers.Error(log, err)
+	})
+
+	pruneBuilds()
+
+	for range time.Tick(1 * time.Hour) {
+		pruneBuilds()
+	}
+}
+
+func pruneBuilds() {
+	log := logger.New("ns=workers.build_retention").At("pruneBuilds")
+
+	apps, err := models.ListApps()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	for _, a := range apps {
+		// apps without the parameter or with 0 keep every build
+		retain, err := strconv.Atoi(a.Parameters["BuildRetention"])
+		if err != nil || retain < 1 {
+			continue
+		}
+
+		if a.Status != "running" {
+			continue
+		}
+
+		// builds of releases that are still running are never pruned
+		keep, err := a.BuildsInUse()
+		if err != nil {
+			log.Namespace("app=%s", a.Name).Error(err)
+			continue
+		}
+
+		pruned, err := models.Provider().BuildPrune(a.Name, retain, keep)
+
+		for _, b := range pruned {
+			e := models.NewAuditEvent("build.prune", "DELETE", fmt.Sprintf("/apps/%s/builds/%s", a.Name, b.Id))
+
+			e.App = a.Name
+			e.Status = 200
+			e.Summary = fmt.Sprintf("retention=%d", retain)
+			e.User = "rack:build-retention"
+
+			if err := e.Save(); err != nil {
+				log.Error(err)
+			}
+		}
+
+		if err != nil {
+			log.Namespace("app=%s", a.Name).Error(err)
+			continue
+		}
+
+		log.Logf("app=%s retention=%d pruned=%d", a.Name, retain, len(pruned))
+	}
+}
@@ -0,0 +1,121 @@
+package workers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/convox/logger"
+	"github.com/convox/rack/api/helpers"
+	"github.com/convox/rack/api/models"
+	"github.com/convox/rack/api/structs"
+)
+
+// buildScheduleTimeout is how long a scheduled build may take before it is given up on
+var buildScheduleTimeout = 2 * time.Hour
+
+// StartBuildSchedules builds and promotes apps whose build schedules come due
+func StartBuildSchedules() {
+	log := logger.New("ns=workers.build_schedules")
+
+	defer recoverWith(func(err error) {
+		helpers.Error(log, err)
+	})
+
+	for range time.Tick(1 * time.Minute) {
+		runBuildSchedules()
+	}
+}
+
+func runBuildSchedules() {
+	log := logger.New("ns=workers.build_schedules").At("runBuildSchedules")
+
+	apps, err := models.ListApps()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	now := time.Now()
+
+	for _, a := range apps {
+		schedules, err := models.ListBuildSchedules(a.Name)
+		if err != nil {
+			log.Namespace("app=%s", a.Name).Error(err)
+			continue
+		}
+
+		for i := range schedules {
+			if !schedules[i].Due(now) {
+				continue
+			}
+
+			// mark the run before building so a slow build is not started twice
+			schedules[i].LastRun = now.UTC()
+			schedules[i].LastStatus = "running"
+
+			if err := schedules[i].Save(); err != nil {
+				log.Namespace("app=%s schedule=%s", a.Name, schedules[i].Id).Error(err)
+				continue
+			}
+
+			go runBuildSchedule(schedules[i])
+		}
+	}
+}
+
+func runBuildSchedule(s models.BuildSchedule) {
+	log := logger.New("ns=workers.build_schedules").At("runBuildSchedule").Namespace("app=%s schedule=%s", s.App, s.Id)
+
+	data := map[string]string{
+		"app":      s.App,
+		"schedule": s.Id,
+	}
+
+	release, err := scheduledBuild(&s)
+
+	data["build"] = s.LastBuild
+
+	// the schedule may have been deleted while it was building
+	if _, gerr := models.GetBuildSchedule(s.App, s.Id); gerr == nil {
+		s.LastStatus = "complete"
+
+		if err != nil {
+			s.LastStatus = "failed"
+		}
+
+		if serr := s.Save(); serr != nil {
+			log.Error(serr)
+		}
+	}
+
+	if err != nil {
+		log.Error(err)
+		models.NotifyError("build:schedule", err, data)
+		return
+	}
+
+	data["release"] = release
+
+	log.Logf("build=%s release=%s", s.LastBuild, release)
+	models.NotifySuccess("build:schedule", data)
+}
+
+// scheduledBuild builds the schedule's url without the cache so base images are pulled
+// fresh, then promotes the release. Returns the promoted release id.
+func scheduledBuild(s *models.BuildSchedule) (string, error) {
+	description := fmt.Sprintf("scheduled build %s", s.Id)
+
+	b, err := models.Provider().BuildCreateRepo(s.App, s.URL, structs.BuildOptions{
+		Description: description,
+		Manifest:    s.Manifest,
+		Priority:    "low",
+	})
+	if b != nil {
+		s.LastBuild = b.Id
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return models.PromoteBuild(s.App, b, buildScheduleTimeout, fmt.Sprintf("schedule:%s", s.Id))
+}
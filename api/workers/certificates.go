@@ -0,0 +1,56 @@
+package workers
+
+import (
+	"time"
+
+	"github.com/convox/logger"
+	"github.com/convox/rack/api/helpers"
+	"github.com/convox/rack/api/models"
+)
+
+// StartCertificates renews the Let's Encrypt certificates of the rack as they get close to
+// expiring and cleans up the certificates they replaced
+func StartCertificates() {
+	log := logger.New("ns=workers.certificates")
+
+	defer recoverWith(func(err error) {
+		helpers.Error(log, err)
+	})
+
+	renewCertificates()
+
+	for range time.Tick(1 * time.Hour) {
+		renewCertificates()
+	}
+}
+
+func renewCertificates() {
+	log := logger.New("ns=workers.certificates").At("renewCertificates")
+
+	certs, err := models.ListACMECertificates()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	for i := range certs {
+		ac := &certs[i]
+
+		if _, err := models.DeleteReplacedCertificate(ac); err != nil {
+			log.Namespace("id=%s", ac.Id).Error(err)
+		}
+
+		if ac.Expiration.Sub(time.Now()) > models.ACMERenewBefore {
+			continue
+		}
+
+		renewed, err := models.RenewACMECertificate(*ac)
+		if err != nil {
+			log.Namespace("id=%s", ac.Id).Error(err)
+			models.NotifyError("certificate:renew", err, map[string]string{"id": ac.Id})
+			continue
+		}
+
+		log.Successf("id=%s renewed=%s", ac.Id, renewed.Id)
+	}
+}
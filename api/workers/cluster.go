@@ -81,6 +81,14 @@ func StartCluster() {
 
 				// log for humans
 				fmt.Printf("who=\"convox/monitor\" what=\"marked instance %s unhealthy\" why=\"ECS reported agent disconnected\"\n", i.Id)
+
+				err = models.RecordRackEvent("instance:unhealthy", "success", map[string]string{
+					"instance": i.Id,
+					"message":  "ECS reported agent disconnected",
+				})
+				if err != nil {
+					log.Error(err)
+				}
 			}
 		}
 
@@ -125,6 +133,14 @@ func (instances Instances) describeASG() error {
 		if lastASGActivity.Before(*a.StartTime) {
 			fmt.Printf("who=\"EC2/ASG\" what=%q why=%q\n", *a.Description, *a.Cause)
 			lastASGActivity = *a.StartTime
+
+			err := models.RecordRackEvent("instance:activity", "success", map[string]string{
+				"cause":   *a.Cause,
+				"message": *a.Description,
+			})
+			if err != nil {
+				logger.New("ns=cluster_monitor").At("describeASG").Error(err)
+			}
 		}
 	}
 
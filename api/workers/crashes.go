@@ -0,0 +1,93 @@
+package workers
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/convox/logger"
+	"github.com/convox/rack/api/helpers"
+	"github.com/convox/rack/api/models"
+	"github.com/fsouza/go-dockerclient"
+)
+
+// crashesSeen are the exited containers that have already been looked at, so that each one
+// is only inspected once while it is still on its instance
+var crashesSeen = map[string]bool{}
+
+// StartCrashes captures the output of crashed containers every minute, before ECS cleans
+// them up along with their logs
+func StartCrashes() {
+	log := logger.New("ns=workers.crashes")
+
+	defer recoverWith(func(err error) {
+		helpers.Error(log, err)
+	})
+
+	for range time.Tick(1 * time.Minute) {
+		captureCrashes()
+	}
+}
+
+func captureCrashes() {
+	log := logger.New("ns=workers.crashes").At("captureCrashes")
+
+	instances, err := models.Provider().InstanceList()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	seen := map[string]bool{}
+
+	for _, i := range instances {
+		ilog := log.Namespace("instance=%s", i.Id)
+
+		d, err := i.DockerClient()
+		if err != nil {
+			ilog.Error(err)
+			continue
+		}
+
+		containers, err := d.ListContainers(docker.ListContainersOptions{
+			All:     true,
+			Filters: map[string][]string{"status": []string{"exited"}},
+		})
+		if err != nil {
+			ilog.Error(err)
+			continue
+		}
+
+		for _, c := range containers {
+			if crashesSeen[c.ID] {
+				seen[c.ID] = true
+				continue
+			}
+
+			crash, err := models.CaptureCrash(d, i.Id, c.ID)
+			if err != nil {
+				ilog.Namespace("container=%s", c.ID[0:12]).Error(err)
+				continue
+			}
+
+			seen[c.ID] = true
+
+			if crash == nil {
+				continue
+			}
+
+			ilog.Logf("app=%s process=%s crash=%s exit=%d", crash.App, crash.Process, crash.Id, crash.ExitCode)
+
+			models.NotifyError("crash:capture", fmt.Errorf("process %s crashed with exit code %d", crash.Process, crash.ExitCode), map[string]string{
+				"app":     crash.App,
+				"process": crash.Process,
+				"release": crash.Release,
+				"crash":   crash.Id,
+				"exit":    strconv.Itoa(crash.ExitCode),
+			})
+		}
+	}
+
+	// forget containers that have been removed from their instance
+	crashesSeen = seen
+}
@@ -0,0 +1,131 @@
+package workers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/convox/logger"
+	"github.com/convox/rack/api/helpers"
+	"github.com/convox/rack/api/models"
+	"github.com/convox/rack/api/structs"
+	"github.com/fsouza/go-dockerclient"
+)
+
+// diskThreshold is the fraction of docker storage in use above which an instance is cleaned up
+const diskThreshold = 0.8
+
+// StartDiskCleanup checks the docker storage of every instance every ten minutes
+// and removes unused images and volumes from instances that are filling up
+func StartDiskCleanup() {
+	log := logger.New("ns=workers.disk")
+
+	defer recoverWith(func(err error) {
+		helpers.Error(log, err)
+	})
+
+	for range time.Tick(10 * time.Minute) {
+		checkDisks()
+	}
+}
+
+func checkDisks() {
+	log := logger.New("ns=workers.disk").At("checkDisks")
+
+	instances, err := models.Provider().InstanceList()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	for _, i := range instances {
+		ilog := log.Namespace("instance=%s", i.Id)
+
+		disk, err := i.DiskUsage()
+		if err != nil {
+			ilog.Error(err)
+			continue
+		}
+
+		used := disk.PercentUsed()
+
+		ilog.Logf("used=%d total=%d percent=%0.2f", disk.Used, disk.Total, used*100)
+
+		if used < diskThreshold {
+			continue
+		}
+
+		images, volumes, err := cleanupInstance(&i)
+		if err != nil {
+			ilog.Error(err)
+			continue
+		}
+
+		ilog.Logf("images=%d volumes=%d", images, volumes)
+
+		models.NotifySuccess("instance:cleanup", map[string]string{
+			"instance": i.Id,
+			"images":   strconv.Itoa(images),
+			"volumes":  strconv.Itoa(volumes),
+		})
+	}
+}
+
+// cleanupInstance removes the images that no container uses and the volumes that no
+// container mounts, returning how many of each were removed
+func cleanupInstance(i *structs.Instance) (int, int, error) {
+	d, err := i.DockerClient()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	containers, err := d.ListContainers(docker.ListContainersOptions{All: true})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	used := map[string]bool{}
+
+	for _, c := range containers {
+		ci, err := d.InspectContainer(c.ID)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		used[ci.Image] = true
+	}
+
+	images, err := d.ListImages(docker.ListImagesOptions{})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	removedImages := 0
+
+	for _, image := range images {
+		if used[image.ID] {
+			continue
+		}
+
+		// images can still be referenced by a tag or child image so failures are expected
+		if err := d.RemoveImage(image.ID); err == nil {
+			removedImages++
+		}
+	}
+
+	volumes, err := d.ListVolumes(docker.ListVolumesOptions{
+		Filters: map[string][]string{"dangling": []string{"true"}},
+	})
+	if err != nil {
+		return removedImages, 0, err
+	}
+
+	removedVolumes := 0
+
+	for _, v := range volumes {
+		if err := d.RemoveVolume(v.Name); err == nil {
+			removedVolumes++
+		}
+	}
+
+	return removedImages, removedVolumes, nil
+}
@@ -0,0 +1,100 @@
+package workers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/convox/logger"
+	"github.com/convox/rack/api/helpers"
+	"github.com/convox/rack/api/models"
+)
+
+// drainInterval is how often new log events are forwarded to each drain
+const drainInterval = 10 * time.Second
+
+// StartDrains forwards the logs of every app to its drains
+func StartDrains() {
+	log := logger.New("ns=workers.drains")
+
+	defer recoverWith(func(err error) {
+		helpers.Error(log, err)
+	})
+
+	for range time.Tick(drainInterval) {
+		runDrains()
+	}
+}
+
+func runDrains() {
+	log := logger.New("ns=workers.drains").At("runDrains")
+
+	apps, err := models.ListApps()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	var wg sync.WaitGroup
+
+	for _, a := range apps {
+		drains, err := models.ListDrains(a.Name)
+		if err != nil {
+			log.Namespace("app=%s", a.Name).Error(err)
+			continue
+		}
+
+		for i := range drains {
+			wg.Add(1)
+			go runDrain(&drains[i], &wg)
+		}
+	}
+
+	wg.Wait()
+}
+
+func runDrain(d *models.Drain, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	log := logger.New("ns=workers.drains").At("runDrain").Namespace("app=%s drain=%s", d.App, d.Id)
+
+	events, err := d.Events()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	status := "active"
+	data := map[string]string{"app": d.App, "id": d.Id, "url": d.URL}
+
+	if err := d.Forward(events); err != nil {
+		// the events are sent again on the next run
+		log.Error(err)
+
+		status = "failing"
+		d.Error = err.Error()
+
+		if d.Status != status {
+			models.NotifyError("drain:failing", err, data)
+		}
+	} else {
+		d.Error = ""
+
+		if d.Status != status {
+			models.NotifySuccess("drain:active", data)
+		}
+	}
+
+	d.Status = status
+
+	// the drain may have been deleted while it was forwarding
+	if _, err := models.GetDrain(d.App, d.Id); err != nil {
+		return
+	}
+
+	if err := d.Save(); err != nil {
+		log.Error(err)
+		return
+	}
+
+	log.Logf("events=%d status=%s", len(events), d.Status)
+}
@@ -0,0 +1,38 @@
+package workers
+
+import (
+	"time"
+
+	"github.com/convox/logger"
+	"github.com/convox/rack/api/helpers"
+	"github.com/convox/rack/api/models"
+)
+
+// StartEventRetention removes rack events older than the EventRetention of the rack
+func StartEventRetention() {
+	log := logger.New("ns=workers.event_retention")
+
+	defer recoverWith(func(err error) {
+		helpers.Error(log, err)
+	})
+
+	pruneEvents()
+
+	for range time.Tick(1 * time.Hour) {
+		pruneEvents()
+	}
+}
+
+func pruneEvents() {
+	log := logger.New("ns=workers.event_retention").At("pruneEvents")
+
+	retention := models.RackEventRetention()
+
+	pruned, err := models.PruneRackEvents(time.Now().Add(-retention))
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	log.Logf("retention=%s pruned=%d", retention, pruned)
+}
@@ -0,0 +1,93 @@
+package workers
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/convox/logger"
+	"github.com/convox/rack/api/models"
+)
+
+const (
+	leaderLock  = "monitor"
+	leaderRenew = 10 * time.Second
+	leaderTTL   = 30 * time.Second
+)
+
+// WaitForLeadership blocks until this process is the leader among the rack's monitor
+// processes, then keeps renewing the lease in the background. Only the leader runs the
+// background workers so that standby monitors can take over if it goes away.
+// The process exits if leadership is lost so a standby can take over cleanly.
+func WaitForLeadership() {
+	log := logger.New("ns=workers.leader")
+
+	owner, err := os.Hostname()
+	if err != nil {
+		owner = fmt.Sprintf("pid-%d", os.Getpid())
+	}
+
+	log = log.Namespace("owner=%s", owner)
+
+	acquireLeadership(log, owner, leaderRenew)
+
+	go func() {
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+
+		os.Exit(renewLeadership(log, owner, time.Tick(leaderRenew), stop))
+	}()
+}
+
+// acquireLeadership tries to take the leader lock every wait until it succeeds
+func acquireLeadership(log *logger.Logger, owner string, wait time.Duration) {
+	for {
+		leader, err := models.AcquireLock(leaderLock, owner, leaderTTL)
+		if err != nil {
+			log.At("acquire").Error(err)
+		}
+
+		if leader {
+			log.At("acquire").Logf("leader=true")
+			return
+		}
+
+		time.Sleep(wait)
+	}
+}
+
+// renewLeadership renews the leader lock on every tick until it is lost or the process is
+// told to stop, and returns the code the process should exit with
+func renewLeadership(log *logger.Logger, owner string, tick <-chan time.Time, stop <-chan os.Signal) int {
+	last := time.Now()
+
+	for {
+		select {
+		case <-stop:
+			if err := models.ReleaseLock(leaderLock, owner); err != nil {
+				log.At("release").Error(err)
+			}
+
+			return 0
+		case <-tick:
+			leader, err := models.AcquireLock(leaderLock, owner, leaderTTL)
+			if err != nil {
+				log.At("renew").Error(err)
+
+				// keep going through transient errors until the lease runs out
+				if time.Since(last) < leaderTTL {
+					continue
+				}
+			}
+
+			if !leader {
+				log.At("renew").Logf("leader=false")
+				return 1
+			}
+
+			last = time.Now()
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package workers
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/convox/logger"
+	"github.com/convox/rack/api/awsutil"
+	"github.com/convox/rack/test"
+	"github.com/stretchr/testify/assert"
+)
+
+var logs bytes.Buffer
+
+func init() {
+	logger.Output = &logs
+}
+
+// renewLeader runs renewLeadership for host-a in the background and returns the channels that
+// drive it along with one that receives its exit code
+func renewLeader() (chan time.Time, chan os.Signal, chan int) {
+	logs.Reset()
+
+	tick := make(chan time.Time)
+	stop := make(chan os.Signal)
+	code := make(chan int, 1)
+
+	go func() {
+		code <- renewLeadership(logger.New("ns=workers.leader"), "host-a", tick, stop)
+	}()
+
+	return tick, stop, code
+}
+
+func lockErrorCycle() awsutil.Cycle {
+	return awsutil.Cycle{
+		Request: awsutil.Request{
+			RequestURI: "/",
+			Operation:  "DynamoDB_20120810.PutItem",
+			Body:       "ignore",
+		},
+		Response: awsutil.Response{
+			StatusCode: 400,
+			Body:       `{"__type":"com.amazonaws.dynamodb.v20120810#ResourceNotFoundException","message":"Requested resource not found"}`,
+		},
+	}
+}
+
+func TestAcquireLeadership(t *testing.T) {
+	aws := test.StubAws(
+		test.AcquireLockCycle("monitor", "host-a", true),
+		lockErrorCycle(),
+		test.AcquireLockCycle("monitor", "host-a", false),
+	)
+	defer aws.Close()
+
+	defer os.Setenv("DYNAMO_LOCKS", os.Getenv("DYNAMO_LOCKS"))
+	os.Setenv("DYNAMO_LOCKS", "convox-locks")
+
+	logs.Reset()
+
+	done := make(chan bool)
+
+	go func() {
+		acquireLeadership(logger.New("ns=workers.leader"), "host-a", 10*time.Millisecond)
+		done <- true
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("leadership was not acquired")
+	}
+
+	assert.Equal(t, 1, strings.Count(logs.String(), "ResourceNotFoundException"))
+	assert.Contains(t, logs.String(), "at=acquire leader=true")
+}
+
+func TestRenewLeadershipLost(t *testing.T) {
+	aws := test.StubAws(
+		test.AcquireLockCycle("monitor", "host-a", false),
+		test.AcquireLockCycle("monitor", "host-a", true),
+	)
+	defer aws.Close()
+
+	defer os.Setenv("DYNAMO_LOCKS", os.Getenv("DYNAMO_LOCKS"))
+	os.Setenv("DYNAMO_LOCKS", "convox-locks")
+
+	tick, _, code := renewLeader()
+
+	tick <- time.Now()
+	tick <- time.Now()
+
+	assert.Equal(t, 1, <-code)
+	assert.NotContains(t, logs.String(), "error")
+	assert.Contains(t, logs.String(), "at=renew leader=false")
+}
+
+func TestRenewLeadershipTransientError(t *testing.T) {
+	aws := test.StubAws(
+		lockErrorCycle(),
+		test.AcquireLockCycle("monitor", "host-a", false),
+		test.ReleaseLockCycle("monitor", "host-a", false),
+	)
+	defer aws.Close()
+
+	defer os.Setenv("DYNAMO_LOCKS", os.Getenv("DYNAMO_LOCKS"))
+	os.Setenv("DYNAMO_LOCKS", "convox-locks")
+
+	tick, stop, code := renewLeader()
+
+	// an error while the lease is still running keeps the leadership
+	tick <- time.Now()
+	tick <- time.Now()
+
+	stop <- syscall.SIGTERM
+
+	assert.Equal(t, 0, <-code)
+	assert.Equal(t, 1, strings.Count(logs.String(), "ResourceNotFoundException"))
+	assert.NotContains(t, logs.String(), "leader=false")
+}
+
+func TestRenewLeadershipRelease(t *testing.T) {
+	aws := test.StubAws(
+		test.AcquireLockCycle("monitor", "host-a", false),
+		test.ReleaseLockCycle("monitor", "host-a", false),
+	)
+	defer aws.Close()
+
+	defer os.Setenv("DYNAMO_LOCKS", os.Getenv("DYNAMO_LOCKS"))
+	os.Setenv("DYNAMO_LOCKS", "convox-locks")
+
+	tick, stop, code := renewLeader()
+
+	tick <- time.Now()
+
+	stop <- syscall.SIGTERM
+
+	assert.Equal(t, 0, <-code)
+	assert.NotContains(t, logs.String(), "error")
+}
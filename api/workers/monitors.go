@@ -0,0 +1,95 @@
+package workers
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/convox/logger"
+	"github.com/convox/rack/api/helpers"
+	"github.com/convox/rack/api/models"
+)
+
+var monitorClient = &http.Client{Timeout: 10 * time.Second}
+
+// StartMonitors runs the checks of every app's monitors as they come due and
+// sends a notification when a monitor goes down or recovers
+func StartMonitors() {
+	log := logger.New("ns=workers.monitors")
+
+	defer recoverWith(func(err error) {
+		helpers.Error(log, err)
+	})
+
+	for range time.Tick(models.MinimumMonitorInterval * time.Second) {
+		runMonitors()
+	}
+}
+
+func runMonitors() {
+	log := logger.New("ns=workers.monitors").At("runMonitors")
+
+	apps, err := models.ListApps()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	var wg sync.WaitGroup
+
+	now := time.Now()
+
+	for _, a := range apps {
+		monitors, err := models.ListMonitors(a.Name)
+		if err != nil {
+			log.Namespace("app=%s", a.Name).Error(err)
+			continue
+		}
+
+		for i := range monitors {
+			if !monitors[i].Due(now) {
+				continue
+			}
+
+			wg.Add(1)
+			go runMonitor(&monitors[i], &wg)
+		}
+	}
+
+	wg.Wait()
+}
+
+func runMonitor(m *models.Monitor, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	log := logger.New("ns=workers.monitors").At("runMonitor").Namespace("app=%s monitor=%s", m.App, m.Id)
+
+	check := m.Check(monitorClient)
+
+	data := map[string]string{
+		"app":     m.App,
+		"id":      m.Id,
+		"url":     m.URL,
+		"latency": fmt.Sprintf("%dms", check.Latency),
+	}
+
+	switch m.Record(check) {
+	case "down":
+		models.NotifyError("monitor:down", fmt.Errorf("%s", check.Error), data)
+	case "up":
+		models.NotifySuccess("monitor:up", data)
+	}
+
+	// the monitor may have been deleted while it was being checked
+	if _, err := models.GetMonitor(m.App, m.Id); err != nil {
+		return
+	}
+
+	if err := m.Save(); err != nil {
+		log.Error(err)
+		return
+	}
+
+	log.Logf("up=%t latency=%d", check.Up, check.Latency)
+}
@@ -0,0 +1,60 @@
+package workers
+
+import (
+	"strings"
+	"time"
+
+	"github.com/convox/logger"
+	"github.com/convox/rack/api/helpers"
+	"github.com/convox/rack/api/models"
+)
+
+// StartSnapshots saves the configuration of the rack and its apps once a day
+// and sends a notification when it has changed since the previous snapshot
+func StartSnapshots() {
+	log := logger.New("ns=workers.snapshots")
+
+	defer recoverWith(func(err error) {
+		helpers.Error(log, err)
+	})
+
+	snapshot()
+
+	for range time.Tick(24 * time.Hour) {
+		snapshot()
+	}
+}
+
+func snapshot() {
+	log := logger.New("ns=workers.snapshots").At("snapshot")
+
+	prev, err := models.LatestSnapshot()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	s, err := models.TakeSnapshot(prev)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	if prev != nil {
+		if drift := s.Drift(prev); len(drift) > 0 {
+			log.Logf("drift=%d", len(drift))
+
+			models.NotifySuccess("rack:drift", map[string]string{
+				"since":   prev.Created.Format(time.RFC3339),
+				"changes": strings.Join(drift, "\n"),
+			})
+		}
+	}
+
+	if err := s.Save(); err != nil {
+		log.Error(err)
+		return
+	}
+
+	log.Success()
+}
@@ -0,0 +1,70 @@
+package workers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/convox/logger"
+	"github.com/convox/rack/api/helpers"
+	"github.com/convox/rack/api/models"
+)
+
+// stackState is the status of an app stack the last time it was checked and when it was last
+// seen before it started rolling back
+type stackState struct {
+	status string
+	stable time.Time
+}
+
+// StartStackFailures sends an app:update error notification when an app stack finishes rolling
+// back a failed update
+func StartStackFailures() {
+	log := logger.New("ns=workers.stacks")
+
+	defer recoverWith(func(err error) {
+		helpers.Error(log, err)
+	})
+
+	states := map[string]stackState{}
+
+	for range time.Tick(1 * time.Minute) {
+		checkStackFailures(states)
+	}
+}
+
+func checkStackFailures(states map[string]stackState) {
+	log := logger.New("ns=workers.stacks").At("checkStackFailures")
+
+	apps, err := models.ListApps()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	now := time.Now()
+
+	for _, a := range apps {
+		prev, ok := states[a.Name]
+
+		switch {
+		case a.Status != "rollback":
+			if ok && prev.status == "rollback" {
+				reason := a.UpdateFailure(prev.stable)
+
+				log.Namespace("app=%s", a.Name).Logf("reason=%q", reason)
+
+				models.NotifyError("app:update", fmt.Errorf("%s", reason), map[string]string{
+					"app":     a.Name,
+					"release": a.Release,
+				})
+			}
+
+			states[a.Name] = stackState{status: a.Status, stable: now}
+		case ok:
+			states[a.Name] = stackState{status: a.Status, stable: prev.stable}
+		default:
+			// the update started before the monitor so look back as far as the check interval
+			states[a.Name] = stackState{status: a.Status, stable: now.Add(-1 * time.Minute)}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package workers
+
+import (
+	"time"
+
+	"github.com/convox/logger"
+	"github.com/convox/rack/api/helpers"
+	"github.com/convox/rack/api/models"
+)
+
+// StartStatusChecks checks the health of every app with a public status page
+// every five minutes and records the result as its uptime
+func StartStatusChecks() {
+	log := logger.New("ns=workers.status")
+
+	defer recoverWith(func(err error) {
+		helpers.Error(log, err)
+	})
+
+	for range time.Tick(5 * time.Minute) {
+		checkStatus()
+	}
+}
+
+func checkStatus() {
+	log := logger.New("ns=workers.status").At("checkStatus")
+
+	apps, err := models.ListApps()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	for _, a := range apps {
+		alog := log.Namespace("app=%s", a.Name)
+
+		s, err := models.GetAppStatus(a.Name)
+		if err != nil {
+			alog.Error(err)
+			continue
+		}
+
+		if !s.Public {
+			continue
+		}
+
+		up, err := a.Healthy()
+		if err != nil {
+			alog.Error(err)
+			continue
+		}
+
+		s.Record(up, time.Now())
+
+		if err := s.Save(); err != nil {
+			alog.Error(err)
+		}
+	}
+}
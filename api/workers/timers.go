@@ -0,0 +1,39 @@
+package workers
+
+import (
+	"time"
+
+	"github.com/convox/logger"
+	"github.com/convox/rack/api/helpers"
+	"github.com/convox/rack/api/models"
+)
+
+// StartTimers records the runs of each app's timers so their history
+// outlives the short time ECS keeps stopped tasks around
+func StartTimers() {
+	log := logger.New("ns=workers.timers")
+
+	defer recoverWith(func(err error) {
+		helpers.Error(log, err)
+	})
+
+	for range time.Tick(1 * time.Minute) {
+		recordTimerRuns()
+	}
+}
+
+func recordTimerRuns() {
+	log := logger.New("ns=workers.timers").At("recordTimerRuns")
+
+	apps, err := models.ListApps()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	for _, a := range apps {
+		if err := a.RecordTimerRuns(); err != nil {
+			log.Namespace("app=%s", a.Name).Error(err)
+		}
+	}
+}
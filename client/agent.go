@@ -0,0 +1,219 @@
+package client
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+
+	"golang.org/x/net/websocket"
+)
+
+// AgentForwardingHeader asks the rack to forward the ssh agent of the client over an instance ssh websocket
+const AgentForwardingHeader = "Agent-Forwarding"
+
+// agentFrame is a websocket frame along with its payload type
+type agentFrame struct {
+	payloadType byte
+	data        []byte
+}
+
+var agentCodec = websocket.Codec{
+	Marshal: func(v interface{}) ([]byte, byte, error) {
+		f := v.(agentFrame)
+		return f.data, f.payloadType, nil
+	},
+	Unmarshal: func(data []byte, payloadType byte, v interface{}) error {
+		f := v.(*agentFrame)
+		f.payloadType = payloadType
+		f.data = data
+		return nil
+	},
+}
+
+// AgentMux carries a terminal and any number of ssh agent channels over one websocket.
+// Terminal data is sent in text frames. Agent data is sent in binary frames that start with
+// the big endian id of their channel, and a frame with only an id closes that channel.
+//
+// The rack opens channels as the instance asks for the agent. The client answers each new
+// channel by dialing its local agent.
+type AgentMux struct {
+	ws   *websocket.Conn
+	dial func() (io.ReadWriteCloser, error)
+
+	buf []byte
+
+	lock     sync.Mutex
+	channels map[uint32]*agentChannel
+	next     uint32
+}
+
+// NewAgentMux wraps a websocket. dial connects to the local ssh agent and is only used on
+// the client side.
+func NewAgentMux(ws *websocket.Conn, dial func() (io.ReadWriteCloser, error)) *AgentMux {
+	return &AgentMux{
+		ws:       ws,
+		dial:     dial,
+		channels: map[uint32]*agentChannel{},
+	}
+}
+
+// Read returns terminal data, handling agent frames as they arrive
+func (m *AgentMux) Read(p []byte) (int, error) {
+	for len(m.buf) == 0 {
+		var f agentFrame
+
+		if err := agentCodec.Receive(m.ws, &f); err != nil {
+			return 0, err
+		}
+
+		switch f.payloadType {
+		case websocket.BinaryFrame:
+			if err := m.receive(f.data); err != nil {
+				return 0, err
+			}
+		default:
+			m.buf = f.data
+		}
+	}
+
+	n := copy(p, m.buf)
+	m.buf = m.buf[n:]
+
+	return n, nil
+}
+
+// Write sends terminal data
+func (m *AgentMux) Write(p []byte) (int, error) {
+	if err := agentCodec.Send(m.ws, agentFrame{websocket.TextFrame, p}); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Open starts a new agent channel
+func (m *AgentMux) Open() io.ReadWriteCloser {
+	r, w := io.Pipe()
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.next++
+
+	ch := &agentChannel{id: m.next, mux: m, r: r, w: w}
+	m.channels[ch.id] = ch
+
+	return ch
+}
+
+func (m *AgentMux) receive(data []byte) error {
+	if len(data) < 4 {
+		return fmt.Errorf("invalid agent frame")
+	}
+
+	id := binary.BigEndian.Uint32(data[0:4])
+	data = data[4:]
+
+	m.lock.Lock()
+	ch, ok := m.channels[id]
+	m.lock.Unlock()
+
+	switch {
+	case ok && len(data) == 0:
+		m.remove(id)
+		ch.w.Close()
+		return nil
+	case ok:
+		// a channel that can no longer be written to is closed without ending the terminal
+		if _, err := ch.w.Write(data); err != nil {
+			return ch.Close()
+		}
+		return nil
+	case len(data) == 0 || m.dial == nil:
+		return nil
+	}
+
+	ch = &agentChannel{id: id, mux: m}
+
+	conn, err := m.dial()
+	if err != nil {
+		return ch.Close()
+	}
+
+	ch.w = conn
+
+	m.lock.Lock()
+	m.channels[id] = ch
+	m.lock.Unlock()
+
+	go func() {
+		io.Copy(ch, conn)
+		ch.Close()
+	}()
+
+	if _, err := conn.Write(data); err != nil {
+		return ch.Close()
+	}
+
+	return nil
+}
+
+func (m *AgentMux) remove(id uint32) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.channels, id)
+}
+
+func (m *AgentMux) send(id uint32, data []byte) error {
+	frame := make([]byte, 4+len(data))
+
+	binary.BigEndian.PutUint32(frame, id)
+	copy(frame[4:], data)
+
+	return agentCodec.Send(m.ws, agentFrame{websocket.BinaryFrame, frame})
+}
+
+// agentChannel is one end of an agent connection. Data written to it is sent to the other
+// side of the websocket, and data from the other side is written to w.
+type agentChannel struct {
+	id   uint32
+	mux  *AgentMux
+	r    io.Reader
+	w    io.WriteCloser
+	once sync.Once
+}
+
+func (ch *agentChannel) Read(p []byte) (int, error) {
+	return ch.r.Read(p)
+}
+
+func (ch *agentChannel) Write(p []byte) (int, error) {
+	// a frame without data would close the channel
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	if err := ch.mux.send(ch.id, p); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+func (ch *agentChannel) Close() error {
+	var err error
+
+	ch.once.Do(func() {
+		ch.mux.remove(ch.id)
+
+		if ch.w != nil {
+			ch.w.Close()
+		}
+
+		err = ch.mux.send(ch.id, nil)
+	})
+
+	return err
+}
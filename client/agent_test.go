@@ -0,0 +1,64 @@
+package client
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/websocket"
+)
+
+func TestAgentMux(t *testing.T) {
+	ts := httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		m := NewAgentMux(ws, nil)
+
+		in := make([]byte, 2)
+		io.ReadFull(m, in)
+
+		go io.Copy(ioutil.Discard, m)
+
+		ch := m.Open()
+		ch.Write([]byte("ping"))
+
+		out := make([]byte, 4)
+		io.ReadFull(ch, out)
+		ch.Close()
+
+		m.Write([]byte(string(in) + ":" + string(out)))
+	}))
+
+	defer ts.Close()
+
+	ws, err := websocket.Dial(strings.Replace(ts.URL, "http", "ws", 1), "", ts.URL)
+	assert.Nil(t, err)
+
+	defer ws.Close()
+
+	requests := make(chan string, 1)
+
+	m := NewAgentMux(ws, func() (io.ReadWriteCloser, error) {
+		local, agent := net.Pipe()
+
+		go func() {
+			req := make([]byte, 4)
+			io.ReadFull(agent, req)
+			requests <- string(req)
+			agent.Write([]byte("pong"))
+		}()
+
+		return local, nil
+	})
+
+	m.Write([]byte("hi"))
+
+	out := make([]byte, 7)
+	_, err = io.ReadFull(m, out)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "hi:pong", string(out))
+	assert.Equal(t, "ping", <-requests)
+}
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/convox/rack/client/models"
+)
+
+// GetAlarms returns whether each alarm metric is enabled for an app and the state of its alarms
+func (c *Client) GetAlarms(app string) (models.AlarmSettings, error) {
+	var alarms models.AlarmSettings
+
+	err := c.Get(fmt.Sprintf("/apps/%s/alarms", app), &alarms)
+	if err != nil {
+		return nil, err
+	}
+
+	return alarms, nil
+}
+
+// EnableAlarm turns on anomaly detection alarms on a metric of an app
+func (c *Client) EnableAlarm(app, metric string) error {
+	var success interface{}
+
+	return c.Post(fmt.Sprintf("/apps/%s/alarms/%s", app, metric), Params{}, &success)
+}
+
+// DisableAlarm turns off anomaly detection alarms on a metric of an app
+func (c *Client) DisableAlarm(app, metric string) error {
+	var success interface{}
+
+	return c.Delete(fmt.Sprintf("/apps/%s/alarms/%s", app, metric), &success)
+}
+
+// CustomAlarmOptions are the fields of a custom alarm to set, empty fields are left alone
+type CustomAlarmOptions struct {
+	Action      string
+	Comparison  string
+	Evaluations string
+	Metric      string
+	Period      string
+	Process     string
+	Statistic   string
+	Threshold   string
+}
+
+func (o CustomAlarmOptions) Params() map[string]string {
+	params := map[string]string{}
+
+	for key, value := range map[string]string{
+		"action":      o.Action,
+		"comparison":  o.Comparison,
+		"evaluations": o.Evaluations,
+		"metric":      o.Metric,
+		"period":      o.Period,
+		"process":     o.Process,
+		"statistic":   o.Statistic,
+		"threshold":   o.Threshold,
+	} {
+		if value != "" {
+			params[key] = value
+		}
+	}
+
+	return params
+}
+
+// GetCustomAlarms returns the custom alarms of an app
+func (c *Client) GetCustomAlarms(app string) (models.CustomAlarms, error) {
+	var alarms models.CustomAlarms
+
+	err := c.Get(fmt.Sprintf("/apps/%s/alarms/custom", app), &alarms)
+	if err != nil {
+		return nil, err
+	}
+
+	return alarms, nil
+}
+
+// CreateCustomAlarm creates a CloudWatch alarm on a metric of an app
+func (c *Client) CreateCustomAlarm(app string, opts CustomAlarmOptions) (*models.CustomAlarm, error) {
+	var alarm models.CustomAlarm
+
+	err := c.Post(fmt.Sprintf("/apps/%s/alarms/custom", app), opts.Params(), &alarm)
+	if err != nil {
+		return nil, err
+	}
+
+	return &alarm, nil
+}
+
+// UpdateCustomAlarm changes the fields of a custom alarm that are set in opts
+func (c *Client) UpdateCustomAlarm(app, id string, opts CustomAlarmOptions) (*models.CustomAlarm, error) {
+	var alarm models.CustomAlarm
+
+	err := c.Put(fmt.Sprintf("/apps/%s/alarms/custom/%s", app, id), opts.Params(), &alarm)
+	if err != nil {
+		return nil, err
+	}
+
+	return &alarm, nil
+}
+
+// DeleteCustomAlarm removes a custom alarm of an app
+func (c *Client) DeleteCustomAlarm(app, id string) error {
+	var success interface{}
+
+	return c.Delete(fmt.Sprintf("/apps/%s/alarms/custom/%s", app, id), &success)
+}
@@ -0,0 +1,24 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/convox/rack/client/models"
+)
+
+// GetAppImportECS describes an app that runs the containers of an ECS service in the rack account
+func (c *Client) GetAppImportECS(cluster, service string) (*models.AppImport, error) {
+	var imp models.AppImport
+
+	q := url.Values{}
+	q.Set("cluster", cluster)
+	q.Set("service", service)
+
+	err := c.Get(fmt.Sprintf("/apps/import/ecs?%s", q.Encode()), &imp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &imp, nil
+}
@@ -4,18 +4,12 @@ import (
 	"fmt"
 	"io"
 	"time"
-)
-
-type App struct {
-	Name    string `json:"name"`
-	Release string `json:"release"`
-	Status  string `json:"status"`
-}
 
-type Apps []App
+	"github.com/convox/rack/client/models"
+)
 
-func (c *Client) GetApps() (Apps, error) {
-	var apps Apps
+func (c *Client) GetApps() (models.Apps, error) {
+	var apps models.Apps
 
 	err := c.Get("/apps", &apps)
 
@@ -26,12 +20,12 @@ func (c *Client) GetApps() (Apps, error) {
 	return apps, nil
 }
 
-func (c *Client) CreateApp(name string) (*App, error) {
+func (c *Client) CreateApp(name string) (*models.App, error) {
 	params := Params{
 		"name": name,
 	}
 
-	var app App
+	var app models.App
 
 	err := c.Post("/apps", params, &app)
 
@@ -42,8 +36,8 @@ func (c *Client) CreateApp(name string) (*App, error) {
 	return &app, nil
 }
 
-func (c *Client) GetApp(name string) (*App, error) {
-	var app App
+func (c *Client) GetApp(name string) (*models.App, error) {
+	var app models.App
 
 	err := c.Get(fmt.Sprintf("/apps/%s", name), &app)
 
@@ -54,8 +48,8 @@ func (c *Client) GetApp(name string) (*App, error) {
 	return &app, nil
 }
 
-func (c *Client) DeleteApp(name string) (*App, error) {
-	var app App
+func (c *Client) DeleteApp(name string) (*models.App, error) {
+	var app models.App
 
 	err := c.Delete(fmt.Sprintf("/apps/%s", name), &app)
 
@@ -66,10 +60,24 @@ func (c *Client) DeleteApp(name string) (*App, error) {
 	return &app, nil
 }
 
-func (c *Client) StreamAppLogs(app, filter string, follow bool, since time.Duration, output io.WriteCloser) error {
+// CancelAppUpdate stops the update of an app that is in progress and rolls it back
+func (c *Client) CancelAppUpdate(name string) error {
+	var success interface{}
+
+	return c.Post(fmt.Sprintf("/apps/%s/cancel", name), Params{}, &success)
+}
+
+// StreamAppEvents writes the stack events of an app update to output until the update has finished
+func (c *Client) StreamAppEvents(app string, output io.WriteCloser) error {
+	return c.Stream(fmt.Sprintf("/apps/%s/events", app), nil, nil, output)
+}
+
+// StreamAppLogs writes the logs of an app to output, limited to one process when process is set
+func (c *Client) StreamAppLogs(app, process, filter string, follow bool, since time.Duration, output io.WriteCloser) error {
 	return c.Stream(fmt.Sprintf("/apps/%s/logs", app), map[string]string{
-		"Filter": filter,
-		"Follow": fmt.Sprintf("%t", follow),
-		"Since":  since.String(),
+		"Filter":  filter,
+		"Follow":  fmt.Sprintf("%t", follow),
+		"Process": process,
+		"Since":   since.String(),
 	}, nil, output)
 }
@@ -3,14 +3,15 @@ package client
 import (
 	"testing"
 
+	"github.com/convox/rack/client/models"
 	"github.com/convox/rack/test"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestGetApps(t *testing.T) {
 	ts := testServer(t,
-		test.Http{Method: "GET", Path: "/apps", Code: 200, Response: Apps{
-			App{Name: "sinatra", Status: "running"},
+		test.Http{Method: "GET", Path: "/apps", Code: 200, Response: models.Apps{
+			models.App{Name: "sinatra", Status: "running"},
 		}},
 	)
 
@@ -45,7 +46,7 @@ func TestGetAppsFailure(t *testing.T) {
 
 func TestGetApp(t *testing.T) {
 	ts := testServer(t,
-		test.Http{Method: "GET", Path: "/apps/sinatra", Code: 200, Response: App{
+		test.Http{Method: "GET", Path: "/apps/sinatra", Code: 200, Response: models.App{
 			Name:   "sinatra",
 			Status: "running",
 		}},
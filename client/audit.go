@@ -0,0 +1,53 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/convox/rack/client/models"
+)
+
+// AuditOptions narrow the events returned by GetAudit. Zero values are not filtered on.
+type AuditOptions struct {
+	Action string
+	App    string
+	User   string
+	Since  time.Duration
+	Limit  int
+}
+
+// GetAudit returns the mutating calls made against the rack, newest first
+func (c *Client) GetAudit(opts AuditOptions) (models.AuditEvents, error) {
+	var events models.AuditEvents
+
+	q := url.Values{}
+
+	if opts.Action != "" {
+		q.Set("action", opts.Action)
+	}
+
+	if opts.App != "" {
+		q.Set("app", opts.App)
+	}
+
+	if opts.User != "" {
+		q.Set("user", opts.User)
+	}
+
+	if opts.Since > 0 {
+		q.Set("since", opts.Since.String())
+	}
+
+	if opts.Limit > 0 {
+		q.Set("limit", strconv.Itoa(opts.Limit))
+	}
+
+	err := c.Get(fmt.Sprintf("/audit?%s", q.Encode()), &events)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
@@ -2,18 +2,15 @@ package client
 
 import (
 	"fmt"
-	"net/http"
 )
 
 func (c *Client) Auth() error {
-	req, err := http.NewRequest("GET", fmt.Sprintf("https://%s/auth", c.Host), nil)
+	req, err := c.request("GET", "/auth", nil)
 
 	if err != nil {
 		return err
 	}
 
-	req.SetBasicAuth("convox", string(c.Password))
-
 	resp, err := c.client().Do(req)
 
 	if err != nil {
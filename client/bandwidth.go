@@ -0,0 +1,36 @@
+package client
+
+import (
+	"io"
+	"time"
+)
+
+// throttledBody paces the body of a request so it is sent at no more than limit bytes per second
+type throttledBody struct {
+	io.ReadCloser
+
+	limit   int64
+	sent    int64
+	started time.Time
+}
+
+func (b *throttledBody) Read(p []byte) (int, error) {
+	if b.started.IsZero() {
+		b.started = time.Now()
+	}
+
+	// never read more than a second's worth at once so the pace stays even
+	if int64(len(p)) > b.limit {
+		p = p[:b.limit]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+
+	b.sent += int64(n)
+
+	if wait := time.Duration(b.sent*int64(time.Second)/b.limit) - time.Since(b.started); wait > 0 {
+		time.Sleep(wait)
+	}
+
+	return n, err
+}
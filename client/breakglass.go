@@ -0,0 +1,73 @@
+package client
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/convox/rack/client/models"
+)
+
+// GetBreakGlass returns the break glass requests of an app, latest first
+func (c *Client) GetBreakGlass(app string) (models.BreakGlasses, error) {
+	var grants models.BreakGlasses
+
+	err := c.Get(fmt.Sprintf("/apps/%s/breakglass", app), &grants)
+	if err != nil {
+		return nil, err
+	}
+
+	return grants, nil
+}
+
+// RequestBreakGlass asks for temporary exec, run and environment access to an immutable app
+func (c *Client) RequestBreakGlass(app string, duration time.Duration, reason string) (*models.BreakGlass, error) {
+	var b models.BreakGlass
+
+	params := Params{
+		"duration": duration.String(),
+		"reason":   reason,
+	}
+
+	err := c.Post(fmt.Sprintf("/apps/%s/breakglass", app), params, &b)
+	if err != nil {
+		return nil, err
+	}
+
+	return &b, nil
+}
+
+// ApproveBreakGlass grants a break glass request of another user
+func (c *Client) ApproveBreakGlass(app, id string) (*models.BreakGlass, error) {
+	var b models.BreakGlass
+
+	err := c.Post(fmt.Sprintf("/apps/%s/breakglass/%s/approve", app, id), Params{}, &b)
+	if err != nil {
+		return nil, err
+	}
+
+	return &b, nil
+}
+
+// DenyBreakGlass refuses a break glass request
+func (c *Client) DenyBreakGlass(app, id string) (*models.BreakGlass, error) {
+	var b models.BreakGlass
+
+	err := c.Post(fmt.Sprintf("/apps/%s/breakglass/%s/deny", app, id), Params{}, &b)
+	if err != nil {
+		return nil, err
+	}
+
+	return &b, nil
+}
+
+// RevokeBreakGlass ends a break glass grant early
+func (c *Client) RevokeBreakGlass(app, id string) (*models.BreakGlass, error) {
+	var b models.BreakGlass
+
+	err := c.Delete(fmt.Sprintf("/apps/%s/breakglass/%s", app, id), &b)
+	if err != nil {
+		return nil, err
+	}
+
+	return &b, nil
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/convox/rack/client/models"
+)
+
+// GetBuildSchedules returns the build schedules of an app
+func (c *Client) GetBuildSchedules(app string) (models.BuildSchedules, error) {
+	var schedules models.BuildSchedules
+
+	err := c.Get(fmt.Sprintf("/apps/%s/builds/schedules", app), &schedules)
+	if err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+// CreateBuildSchedule builds and promotes an app from a git url whenever the cron schedule matches
+func (c *Client) CreateBuildSchedule(app, schedule, url, manifest string) (*models.BuildSchedule, error) {
+	var s models.BuildSchedule
+
+	params := Params{
+		"schedule": schedule,
+		"url":      url,
+	}
+
+	if manifest != "" {
+		params["manifest"] = manifest
+	}
+
+	err := c.Post(fmt.Sprintf("/apps/%s/builds/schedules", app), params, &s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// DeleteBuildSchedule stops building an app on a schedule
+func (c *Client) DeleteBuildSchedule(app, id string) error {
+	var success interface{}
+
+	return c.Delete(fmt.Sprintf("/apps/%s/builds/schedules/%s", app, id), &success)
+}
@@ -4,27 +4,67 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"time"
+	"strconv"
+	"strings"
+
+	"github.com/convox/rack/client/models"
 )
 
-type Build struct {
-	Id       string `json:"id"`
-	App      string `json:"app"`
-	Logs     string `json:"logs"`
-	Manifest string `json:"manifest"`
-	Release  string `json:"release"`
-	Status   string `json:"status"`
+// BuildOptions carries the settings shared by every way of creating a build
+type BuildOptions struct {
+	Cache       bool
+	Manifest    string
+	Description string
+
+	// Priority places the build in the rack build queue, empty for the default
+	Priority string
+
+	// Concurrency is the number of services built at once, 0 for the rack default
+	Concurrency int
 
-	Description string `json:"description"`
+	// Strict is the manifest validation mode, empty for the rack default
+	Strict string
 
-	Started time.Time `json:"started"`
-	Ended   time.Time `json:"ended"`
+	// BuildArgs are KEY=VALUE build args passed on to docker build
+	BuildArgs []string
+
+	// Matrix builds the images for several architectures, i.e. arch=amd64,arm64
+	Matrix string
 }
 
-type Builds []Build
+// Params returns the form values that the CreateBuild functions send for the options
+func (o BuildOptions) Params() map[string]string {
+	params := map[string]string{
+		"cache":       fmt.Sprintf("%t", o.Cache),
+		"description": o.Description,
+		"manifest":    o.Manifest,
+	}
 
-func (c *Client) GetBuilds(app string) (Builds, error) {
-	var builds Builds
+	if o.Priority != "" {
+		params["priority"] = o.Priority
+	}
+
+	if o.Concurrency > 0 {
+		params["concurrency"] = strconv.Itoa(o.Concurrency)
+	}
+
+	if o.Strict != "" {
+		params["strict"] = o.Strict
+	}
+
+	if len(o.BuildArgs) > 0 {
+		params["build-args"] = strings.Join(o.BuildArgs, "\n")
+	}
+
+	if o.Matrix != "" {
+		params["matrix"] = o.Matrix
+	}
+
+	return params
+}
+
+func (c *Client) GetBuilds(app string) (models.Builds, error) {
+	var builds models.Builds
 
 	err := c.Get(fmt.Sprintf("/apps/%s/builds", app), &builds)
 	if err != nil {
@@ -35,8 +75,8 @@ func (c *Client) GetBuilds(app string) (Builds, error) {
 }
 
 // GetBuildsWithLimit returns a list of the latest builds, with the length specified in limit
-func (c *Client) GetBuildsWithLimit(app string, limit int) (Builds, error) {
-	var builds Builds
+func (c *Client) GetBuildsWithLimit(app string, limit int) (models.Builds, error) {
+	var builds models.Builds
 
 	err := c.Get(fmt.Sprintf("/apps/%s/builds?limit=%d", app, limit), &builds)
 	if err != nil {
@@ -46,20 +86,16 @@ func (c *Client) GetBuildsWithLimit(app string, limit int) (Builds, error) {
 	return builds, nil
 }
 
-func (c *Client) CreateBuildIndex(app string, index Index, cache bool, manifest string, description string) (*Build, error) {
-	var build Build
+func (c *Client) CreateBuildIndex(app string, index models.Index, opts BuildOptions) (*models.Build, error) {
+	var build models.Build
 
 	data, err := json.Marshal(index)
 	if err != nil {
 		return nil, err
 	}
 
-	params := map[string]string{
-		"cache":       fmt.Sprintf("%t", cache),
-		"description": description,
-		"index":       string(data),
-		"manifest":    manifest,
-	}
+	params := opts.Params()
+	params["index"] = string(data)
 
 	err = c.Post(fmt.Sprintf("/apps/%s/builds", app), params, &build)
 	if err != nil {
@@ -70,25 +106,33 @@ func (c *Client) CreateBuildIndex(app string, index Index, cache bool, manifest
 }
 
 // CreateBuildSource will create a new build from source. If progress of the uploaded is needed, see CreateBuildSourceProgress
-func (c *Client) CreateBuildSource(app string, source []byte, cache bool, manifest string, description string) (*Build, error) {
-	return c.CreateBuildSourceProgress(app, source, cache, manifest, description, nil)
+func (c *Client) CreateBuildSource(app string, source io.Reader, opts BuildOptions) (*models.Build, error) {
+	return c.CreateBuildSourceProgress(app, source, opts, nil)
 }
 
 // CreateBuildSourceProgress will create a new build from source with an optional callback to provide progress of the source being uploaded.
-func (c *Client) CreateBuildSourceProgress(app string, source []byte, cache bool, manifest string, description string, progressCallback func(s string)) (*Build, error) {
-	var build Build
+// The source is streamed to the rack as it is read.
+func (c *Client) CreateBuildSourceProgress(app string, source io.Reader, opts BuildOptions, progressCallback func(s string)) (*models.Build, error) {
+	return c.CreateBuildSourceBlobs(app, source, nil, opts, progressCallback)
+}
 
-	files := map[string][]byte{
-		"source": source,
-	}
+// CreateBuildSourceBlobs will create a new build from source, adding the files in blobs to it
+// from the rack index. The files in blobs must already be uploaded with IndexUpdate.
+func (c *Client) CreateBuildSourceBlobs(app string, source io.Reader, blobs models.Index, opts BuildOptions, progressCallback func(s string)) (*models.Build, error) {
+	var build models.Build
 
-	params := map[string]string{
-		"cache":       fmt.Sprintf("%t", cache),
-		"description": description,
-		"manifest":    manifest,
+	params := opts.Params()
+
+	if len(blobs) > 0 {
+		data, err := json.Marshal(blobs)
+		if err != nil {
+			return nil, err
+		}
+
+		params["blobs"] = string(data)
 	}
 
-	err := c.PostMultipartP(fmt.Sprintf("/apps/%s/builds", app), files, params, &build, progressCallback)
+	err := c.PostMultipartStream(fmt.Sprintf("/apps/%s/builds", app), "source", source, params, &build, progressCallback)
 	if err != nil {
 		return nil, err
 	}
@@ -96,16 +140,36 @@ func (c *Client) CreateBuildSourceProgress(app string, source []byte, cache bool
 	return &build, nil
 }
 
-func (c *Client) CreateBuildUrl(app string, url string, cache bool, manifest string, description string) (*Build, error) {
-	var build Build
+// CreateBuildUpload builds an app from a source tarball sent with CreateUpload
+func (c *Client) CreateBuildUpload(app string, upload string, blobs models.Index, opts BuildOptions) (*models.Build, error) {
+	var build models.Build
 
-	params := map[string]string{
-		"cache":       fmt.Sprintf("%t", cache),
-		"description": description,
-		"repo":        url,
-		"manifest":    manifest,
+	params := opts.Params()
+	params["upload"] = upload
+
+	if len(blobs) > 0 {
+		data, err := json.Marshal(blobs)
+		if err != nil {
+			return nil, err
+		}
+
+		params["blobs"] = string(data)
 	}
 
+	err := c.Post(fmt.Sprintf("/apps/%s/builds", app), params, &build)
+	if err != nil {
+		return nil, err
+	}
+
+	return &build, nil
+}
+
+func (c *Client) CreateBuildUrl(app string, url string, opts BuildOptions) (*models.Build, error) {
+	var build models.Build
+
+	params := opts.Params()
+	params["repo"] = url
+
 	err := c.Post(fmt.Sprintf("/apps/%s/builds", app), params, &build)
 
 	if err != nil {
@@ -115,8 +179,8 @@ func (c *Client) CreateBuildUrl(app string, url string, cache bool, manifest str
 	return &build, nil
 }
 
-func (c *Client) GetBuild(app, id string) (*Build, error) {
-	var build Build
+func (c *Client) GetBuild(app, id string) (*models.Build, error) {
+	var build models.Build
 
 	err := c.Get(fmt.Sprintf("/apps/%s/builds/%s", app, id), &build)
 	if err != nil {
@@ -130,8 +194,32 @@ func (c *Client) StreamBuildLogs(app, id string, output io.WriteCloser) error {
 	return c.Stream(fmt.Sprintf("/apps/%s/builds/%s/logs", app, id), nil, nil, output)
 }
 
-func (c *Client) CopyBuild(app, id, destApp string) (*Build, error) {
-	var build Build
+// CancelBuild stops a build that is still in progress
+func (c *Client) CancelBuild(app, id string) (*models.Build, error) {
+	var build models.Build
+
+	err := c.Post(fmt.Sprintf("/apps/%s/builds/%s/cancel", app, id), Params{}, &build)
+	if err != nil {
+		return nil, err
+	}
+
+	return &build, nil
+}
+
+// CreateBuildRelease returns the release of a completed build, creating it if it no longer exists
+func (c *Client) CreateBuildRelease(app, id string) (*models.Release, error) {
+	var release models.Release
+
+	err := c.Post(fmt.Sprintf("/apps/%s/builds/%s/release", app, id), Params{}, &release)
+	if err != nil {
+		return nil, err
+	}
+
+	return &release, nil
+}
+
+func (c *Client) CopyBuild(app, id, destApp string) (*models.Build, error) {
+	var build models.Build
 
 	params := map[string]string{
 		"app": destApp,
@@ -146,22 +234,85 @@ func (c *Client) CopyBuild(app, id, destApp string) (*Build, error) {
 	return &build, nil
 }
 
-func (c *Client) DeleteBuild(app, id string) (*Build, error) {
-	var build Build
+// ExportBuild writes an archive of the manifest and images of a completed build to w
+func (c *Client) ExportBuild(app, id string, w io.Writer) error {
+	return c.Download(fmt.Sprintf("/apps/%s/builds/%s/export", app, id), w)
+}
+
+// ImportBuild creates a build and release from an archive written by ExportBuild
+func (c *Client) ImportBuild(app string, source io.Reader, progressCallback func(s string)) (*models.Build, error) {
+	var build models.Build
+
+	err := c.PostMultipartStream(fmt.Sprintf("/apps/%s/builds/import", app), "source", source, Params{}, &build, progressCallback)
+	if err != nil {
+		return nil, err
+	}
+
+	return &build, nil
+}
+
+// ImportBuildUpload imports a build archive sent with CreateUpload
+func (c *Client) ImportBuildUpload(app string, upload string) (*models.Build, error) {
+	var build models.Build
+
+	err := c.Post(fmt.Sprintf("/apps/%s/builds/import", app), Params{"upload": upload}, &build)
+	if err != nil {
+		return nil, err
+	}
+
+	return &build, nil
+}
+
+func (c *Client) DeleteBuild(app, id string) (*models.Build, error) {
+	var build models.Build
 
 	err := c.Delete(fmt.Sprintf("/apps/%s/builds/%s", app, id), &build)
 
 	return &build, err
 }
 
-func (c *Client) UpdateBuild(app, id, manifest, status, reason string) (*Build, error) {
+// BuildUpdateOptions holds the optional metadata a builder can report when updating a build
+type BuildUpdateOptions struct {
+	GitSha  string
+	Images  map[string]string
+	Timings *models.BuildTimings
+}
+
+func (c *Client) UpdateBuild(app, id, manifest, status, reason string) (*models.Build, error) {
+	return c.UpdateBuildWithOptions(app, id, manifest, status, reason, BuildUpdateOptions{})
+}
+
+// UpdateBuildWithOptions updates a build along with its git sha, pushed images and timings
+func (c *Client) UpdateBuildWithOptions(app, id, manifest, status, reason string, opts BuildUpdateOptions) (*models.Build, error) {
 	params := Params{
 		"manifest": manifest,
 		"status":   status,
 		"reason":   reason,
 	}
 
-	var build Build
+	if opts.GitSha != "" {
+		params["git-sha"] = opts.GitSha
+	}
+
+	if len(opts.Images) > 0 {
+		data, err := json.Marshal(opts.Images)
+		if err != nil {
+			return nil, err
+		}
+
+		params["images"] = string(data)
+	}
+
+	if opts.Timings != nil {
+		data, err := json.Marshal(opts.Timings)
+		if err != nil {
+			return nil, err
+		}
+
+		params["timings"] = string(data)
+	}
+
+	var build models.Build
 
 	err := c.Put(fmt.Sprintf("/apps/%s/builds/%s", app, id), params, &build)
 	if err != nil {
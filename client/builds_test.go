@@ -0,0 +1,46 @@
+package client
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/convox/rack/client/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateBuildSourceStreams(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/apps/foo/builds", r.URL.Path)
+		assert.Equal(t, int64(-1), r.ContentLength)
+
+		source, _, err := r.FormFile("source")
+		require.Nil(t, err)
+
+		data, err := ioutil.ReadAll(source)
+		require.Nil(t, err)
+
+		assert.Equal(t, "tarball", string(data))
+		assert.Equal(t, "true", r.FormValue("cache"))
+		assert.Equal(t, "high", r.FormValue("priority"))
+
+		json.NewEncoder(w).Encode(models.Build{Id: "B1234", Status: "created"})
+	}))
+
+	defer ts.Close()
+
+	progress := ""
+
+	build, err := testClient(t, ts.URL).CreateBuildSourceProgress("foo", strings.NewReader("tarball"), BuildOptions{Cache: true, Priority: "high"}, func(s string) {
+		progress = s
+	})
+
+	if assert.Nil(t, err) {
+		assert.Equal(t, "B1234", build.Id)
+		assert.NotEqual(t, "", progress)
+	}
+}
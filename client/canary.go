@@ -0,0 +1,54 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/convox/rack/client/models"
+)
+
+// GetCanary returns the canary running for an app
+func (c *Client) GetCanary(app string) (*models.Canary, error) {
+	var canary models.Canary
+
+	err := c.Get(fmt.Sprintf("/apps/%s/canary", app), &canary)
+	if err != nil {
+		return nil, err
+	}
+
+	return &canary, nil
+}
+
+// CreateCanary runs a release next to the active release with about percent of its traffic
+func (c *Client) CreateCanary(app, release string, percent int) (*models.Canary, error) {
+	var canary models.Canary
+
+	params := Params{
+		"percent": fmt.Sprintf("%d", percent),
+	}
+
+	err := c.Post(fmt.Sprintf("/apps/%s/releases/%s/canary", app, release), params, &canary)
+	if err != nil {
+		return nil, err
+	}
+
+	return &canary, nil
+}
+
+// FinalizeCanary promotes the canary release of an app
+func (c *Client) FinalizeCanary(app string) (*models.Release, error) {
+	var release models.Release
+
+	err := c.Post(fmt.Sprintf("/apps/%s/canary/finalize", app), Params{}, &release)
+	if err != nil {
+		return nil, err
+	}
+
+	return &release, nil
+}
+
+// AbortCanary stops the canary of an app leaving the active release to serve all traffic
+func (c *Client) AbortCanary(app string) error {
+	var success interface{}
+
+	return c.Delete(fmt.Sprintf("/apps/%s/canary", app), &success)
+}
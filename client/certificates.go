@@ -3,19 +3,12 @@ package client
 import (
 	"fmt"
 	"strings"
-	"time"
-)
-
-type Certificate struct {
-	Id         string    `json:"id"`
-	Domain     string    `json:"domain"`
-	Expiration time.Time `json:"expiration"`
-}
 
-type Certificates []Certificate
+	"github.com/convox/rack/client/models"
+)
 
-func (c *Client) CreateCertificate(pub, key, chain string) (*Certificate, error) {
-	var cert Certificate
+func (c *Client) CreateCertificate(pub, key, chain string) (*models.Certificate, error) {
+	var cert models.Certificate
 
 	params := Params{
 		"public":  pub,
@@ -36,8 +29,8 @@ func (c *Client) DeleteCertificate(id string) error {
 	return c.Delete(fmt.Sprintf("/certificates/%s", id), nil)
 }
 
-func (c *Client) GenerateCertificate(domains []string) (*Certificate, error) {
-	var cert Certificate
+func (c *Client) GenerateCertificate(domains []string) (*models.Certificate, error) {
+	var cert models.Certificate
 
 	params := Params{
 		"domains": strings.Join(domains, ","),
@@ -52,8 +45,27 @@ func (c *Client) GenerateCertificate(domains []string) (*Certificate, error) {
 	return &cert, nil
 }
 
-func (c *Client) ListCertificates() (Certificates, error) {
-	var certs Certificates
+// GenerateLetsEncryptCertificate issues a certificate for domains from Let's Encrypt that the
+// rack renews before it expires. challenge is dns or http.
+func (c *Client) GenerateLetsEncryptCertificate(domains []string, challenge string) (*models.Certificate, error) {
+	var cert models.Certificate
+
+	params := Params{
+		"challenge":   challenge,
+		"domains":     strings.Join(domains, ","),
+		"letsencrypt": "true",
+	}
+
+	err := c.Post("/certificates/generate", params, &cert)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cert, nil
+}
+
+func (c *Client) ListCertificates() (models.Certificates, error) {
+	var certs models.Certificates
 
 	err := c.Get("/certificates", &certs)
 
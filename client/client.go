@@ -3,7 +3,9 @@ package client
 import (
 	"bufio"
 	"bytes"
+	"crypto/sha256"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -13,7 +15,6 @@ import (
 	"net"
 	"net/http"
 	"net/url"
-	"os"
 	"strings"
 	"sync"
 	"time"
@@ -25,7 +26,7 @@ import (
 
 var MinimumServerVersion = "20151023042141"
 
-//this just needs to be random enough to never show up again in a byte stream
+// this just needs to be random enough to never show up again in a byte stream
 var StatusCodePrefix = "F1E49A85-0AD7-4AEF-A618-C249C6E6568D:"
 
 type Client struct {
@@ -34,6 +35,26 @@ type Client struct {
 	Version  string
 
 	Rack string
+
+	// User identifies who is making changes, for the rack's records
+	User string
+
+	// KeyUser is the user a key in Password belongs to, empty when Password is the rack password
+	KeyUser string
+
+	// CA verifies the certificate of a rack signed by a private authority.
+	// Without it the certificate of a rack is not verified.
+	CA *x509.CertPool
+
+	// Pin is the sha256 hash of a public key in the certificate chain of the
+	// rack, like sha256//<base64>, that connections must present
+	Pin string
+
+	// Sign authenticates requests with an HMAC signature instead of sending the password
+	Sign bool
+
+	// BandwidthLimit is the most bytes per second request bodies are sent at, 0 for no limit
+	BandwidthLimit int64
 }
 
 type Params map[string]string
@@ -73,6 +94,29 @@ func (c *Client) Get(path string, out interface{}) error {
 	return json.Unmarshal(data, out)
 }
 
+// Download writes the raw response body of a GET request to out
+func (c *Client) Download(path string, out io.Writer) error {
+	req, err := c.request("GET", path, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.client().Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if err := responseError(res); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(out, res.Body)
+
+	return err
+}
+
 func (c *Client) Post(path string, params Params, out interface{}) error {
 	form := url.Values{}
 
@@ -178,7 +222,9 @@ func (c *Client) PostMultipartP(path string, files map[string][]byte, params Par
 		return err
 	}
 
-	req.SetBasicAuth("convox", string(c.Password))
+	if !c.Sign {
+		req.SetBasicAuth(c.authUser(), string(c.Password))
+	}
 
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 
@@ -215,6 +261,92 @@ func (c *Client) PostMultipartP(path string, files map[string][]byte, params Par
 	return nil
 }
 
+// PostMultipartStream posts a multipart message with a single file part read from source.
+// The body is streamed with a chunked upload so source is never held in memory, and
+// callback, if set, is called with the number of bytes sent so far.
+func (c *Client) PostMultipartStream(path, name string, source io.Reader, params Params, out interface{}, callback func(s string)) error {
+	pr, pw := io.Pipe()
+
+	writer := multipart.NewWriter(pw)
+
+	go func() {
+		pw.CloseWithError(writeMultipart(writer, name, source, params))
+	}()
+
+	var body io.Reader = pr
+
+	if callback != nil {
+		body = &progressReader{Reader: pr, callback: callback}
+	}
+
+	req, err := c.request("POST", path, body)
+	if err != nil {
+		pr.Close()
+		return err
+	}
+
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	res, err := c.client().Do(req)
+	if err != nil {
+		pr.Close()
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if err := responseError(res); err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if out != nil {
+		return json.Unmarshal(data, out)
+	}
+
+	return nil
+}
+
+func writeMultipart(writer *multipart.Writer, name string, source io.Reader, params Params) error {
+	for key, value := range params {
+		if err := writer.WriteField(key, value); err != nil {
+			return err
+		}
+	}
+
+	part, err := writer.CreateFormFile(name, "source.tgz")
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(part, source); err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
+
+// progressReader reports the number of bytes read through it
+type progressReader struct {
+	io.Reader
+
+	callback func(s string)
+	total    int64
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+
+	r.total += int64(n)
+	r.callback(pb.Format(r.total).To(pb.U_BYTES).String())
+
+	return n, err
+}
+
 func (c *Client) Put(path string, params Params, out interface{}) error {
 	form := url.Values{}
 
@@ -298,17 +430,42 @@ func (c *Client) DeleteResponse(path string, out interface{}) (*http.Response, e
 }
 
 func (c *Client) Stream(path string, headers map[string]string, in io.Reader, out io.WriteCloser) error {
-	origin := fmt.Sprintf("https://%s", c.Host)
-	endpoint := fmt.Sprintf("wss://%s%s", c.Host, path)
+	ws, err := c.dialWebsocket(path, headers)
+	if err != nil {
+		return err
+	}
 
-	config, err := websocket.NewConfig(endpoint, origin)
+	defer ws.Close()
+
+	return stream(ws, in, out)
+}
+
+// StreamAgent is Stream with the local ssh agent dialed by dial forwarded over the websocket
+func (c *Client) StreamAgent(path string, headers map[string]string, in io.Reader, out io.WriteCloser, dial func() (io.ReadWriteCloser, error)) error {
+	if headers == nil {
+		headers = map[string]string{}
+	}
 
+	headers[AgentForwardingHeader] = "true"
+
+	ws, err := c.dialWebsocket(path, headers)
 	if err != nil {
 		return err
 	}
 
-	config.TlsConfig = &tls.Config{
-		InsecureSkipVerify: true,
+	defer ws.Close()
+
+	return stream(NewAgentMux(ws, dial), in, out)
+}
+
+func (c *Client) dialWebsocket(path string, headers map[string]string) (*websocket.Conn, error) {
+	origin := fmt.Sprintf("https://%s", c.Host)
+	endpoint := fmt.Sprintf("wss://%s%s", c.Host, path)
+
+	config, err := websocket.NewConfig(endpoint, origin)
+
+	if err != nil {
+		return nil, err
 	}
 
 	if c.Rack != "" {
@@ -317,39 +474,30 @@ func (c *Client) Stream(path string, headers map[string]string, in io.Reader, ou
 
 	config.Header.Set("Version", c.Version)
 
-	userpass := fmt.Sprintf("convox:%s", c.Password)
-	userpass_encoded := base64.StdEncoding.EncodeToString([]byte(userpass))
+	if c.Sign {
+		c.sign(config.Header, "GET", config.Location.RequestURI(), hashBytes(nil))
+	} else {
+		userpass := fmt.Sprintf("%s:%s", c.authUser(), c.Password)
+		userpass_encoded := base64.StdEncoding.EncodeToString([]byte(userpass))
 
-	config.Header.Add("Authorization", fmt.Sprintf("Basic %s", userpass_encoded))
+		config.Header.Add("Authorization", fmt.Sprintf("Basic %s", userpass_encoded))
+	}
 
 	for k, v := range headers {
 		config.Header.Add(k, v)
 	}
 
-	if c.requiresVerification() {
-		config.TlsConfig = &tls.Config{
-			ServerName: c.Host,
-		}
-	} else {
-		config.TlsConfig = &tls.Config{
-			InsecureSkipVerify: true,
-		}
-	}
-
-	var ws *websocket.Conn
-
-	if proxy := os.Getenv("HTTPS_PROXY"); proxy != "" {
-		ws, err = c.proxyWebsocket(config, proxy)
-	} else {
-		ws, err = websocket.DialConfig(config)
-	}
+	config.TlsConfig = c.tlsConfig()
 
+	conn, err := c.dialTLS("tcp", c.hostPort())
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	defer ws.Close()
+	return websocket.NewClient(config, conn)
+}
 
+func stream(ws io.ReadWriter, in io.Reader, out io.WriteCloser) error {
 	var wg sync.WaitGroup
 
 	if in != nil {
@@ -375,24 +523,107 @@ func (c *Client) requiresVerification() bool {
 func (c *Client) client() *http.Client {
 	client := &http.Client{}
 
-	var config *tls.Config
+	// proxies are handled by dialTLS so pins are checked on every connection
+	client.Transport = &http.Transport{
+		DialTLS:         c.dialTLS,
+		TLSClientConfig: c.tlsConfig(),
+	}
 
-	if c.requiresVerification() {
-		config = &tls.Config{
-			ServerName: c.Host,
-		}
+	return client
+}
+
+func (c *Client) tlsConfig() *tls.Config {
+	switch {
+	case c.requiresVerification():
+		return &tls.Config{ServerName: c.Host}
+	case c.CA != nil:
+		return &tls.Config{RootCAs: c.CA, ServerName: c.hostname()}
+	default:
+		return &tls.Config{InsecureSkipVerify: true}
+	}
+}
+
+// dialTLS connects to the rack, through a proxy if the environment asks for one
+func (c *Client) dialTLS(network, addr string) (net.Conn, error) {
+	proxy, err := c.streamProxy()
+	if err != nil {
+		return nil, err
+	}
+
+	var raw net.Conn
+
+	if proxy != nil {
+		raw, err = c.proxyDial(proxy)
 	} else {
-		config = &tls.Config{
-			InsecureSkipVerify: true,
+		raw, err = net.DialTimeout(network, addr, 10*time.Second)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	conn := tls.Client(raw, c.tlsConfig())
+
+	if err := conn.Handshake(); err != nil {
+		raw.Close()
+		return nil, err
+	}
+
+	if err := c.verifyPin(conn.ConnectionState()); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// verifyPin returns an error unless the rack proved it holds the pinned public key
+func (c *Client) verifyPin(state tls.ConnectionState) error {
+	if c.Pin == "" {
+		return nil
+	}
+
+	// a verified chain links the leaf to every certificate in it
+	for _, chain := range state.VerifiedChains {
+		for _, cert := range chain {
+			if SPKIPin(cert) == c.Pin {
+				return nil
+			}
 		}
 	}
 
-	client.Transport = &http.Transport{
-		Proxy:           http.ProxyFromEnvironment,
-		TLSClientConfig: config,
+	// without a verified chain the handshake only proves the leaf key, anything after it is unchecked
+	if len(state.VerifiedChains) == 0 && len(state.PeerCertificates) > 0 {
+		if SPKIPin(state.PeerCertificates[0]) == c.Pin {
+			return nil
+		}
 	}
 
-	return client
+	return fmt.Errorf("certificate of %s does not match pin %s", c.Host, c.Pin)
+}
+
+// SPKIPin returns the pin of the public key of a certificate
+func SPKIPin(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+
+	return fmt.Sprintf("sha256//%s", base64.StdEncoding.EncodeToString(sum[:]))
+}
+
+// hostPort returns the address of the rack including a port
+func (c *Client) hostPort() string {
+	if _, _, err := net.SplitHostPort(c.Host); err == nil {
+		return c.Host
+	}
+
+	return net.JoinHostPort(c.Host, "443")
+}
+
+// hostname returns the host of the rack without a port
+func (c *Client) hostname() string {
+	if host, _, err := net.SplitHostPort(c.Host); err == nil {
+		return host
+	}
+
+	return c.Host
 }
 
 func copyAsync(dst io.Writer, src io.Reader, wg *sync.WaitGroup) {
@@ -401,13 +632,32 @@ func copyAsync(dst io.Writer, src io.Reader, wg *sync.WaitGroup) {
 }
 
 func (c *Client) request(method, path string, body io.Reader) (*http.Request, error) {
+	hash := ""
+
+	if c.Sign {
+		h, b, err := payloadHash(body)
+		if err != nil {
+			return nil, err
+		}
+
+		hash, body = h, b
+	}
+
 	req, err := http.NewRequest(method, fmt.Sprintf("https://%s%s", c.Host, path), body)
 
 	if err != nil {
 		return nil, err
 	}
 
-	req.SetBasicAuth("convox", string(c.Password))
+	if c.BandwidthLimit > 0 && req.Body != nil {
+		req.Body = &throttledBody{ReadCloser: req.Body, limit: c.BandwidthLimit}
+	}
+
+	if c.Sign {
+		c.sign(req.Header, method, req.URL.RequestURI(), hash)
+	} else {
+		req.SetBasicAuth(c.authUser(), string(c.Password))
+	}
 
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add("Version", c.Version)
@@ -416,38 +666,39 @@ func (c *Client) request(method, path string, body io.Reader) (*http.Request, er
 		req.Header.Add("Rack", c.Rack)
 	}
 
+	if c.User != "" {
+		req.Header.Add("User", c.User)
+	}
+
 	return req, nil
 }
 
-func (c *Client) proxyWebsocket(config *websocket.Config, proxy string) (*websocket.Conn, error) {
-	u, err := url.Parse(proxy)
-
-	if err != nil {
-		return nil, err
-	}
-
-	host := u.Host
+// proxyDial opens a tunnel to the rack through an http proxy with CONNECT
+func (c *Client) proxyDial(proxy *url.URL) (net.Conn, error) {
+	host := proxy.Host
 
 	if !strings.Contains(host, ":") {
-		host += ":443"
+		host += ":80"
 	}
 
-	conn, err := net.DialTimeout("tcp", u.Host, 3*time.Second)
-
+	conn, err := net.DialTimeout("tcp", host, 3*time.Second)
 	if err != nil {
 		return nil, err
 	}
 
-	if _, err = conn.Write([]byte(fmt.Sprintf("CONNECT %s:443 HTTP/1.1\r\n", c.Host))); err != nil {
+	target := c.hostPort()
+
+	if _, err = conn.Write([]byte(fmt.Sprintf("CONNECT %s HTTP/1.1\r\n", target))); err != nil {
 		return nil, err
 	}
 
-	if _, err = conn.Write([]byte(fmt.Sprintf("Host: %s:443\r\n", c.Host))); err != nil {
+	if _, err = conn.Write([]byte(fmt.Sprintf("Host: %s\r\n", target))); err != nil {
 		return nil, err
 	}
 
-	if auth := u.User; auth != nil {
-		enc := base64.StdEncoding.EncodeToString([]byte(auth.String()))
+	if auth := proxy.User; auth != nil {
+		password, _ := auth.Password()
+		enc := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", auth.Username(), password)))
 
 		if _, err = conn.Write([]byte(fmt.Sprintf("Proxy-Authorization: Basic %s\r\n", enc))); err != nil {
 			return nil, err
@@ -458,16 +709,25 @@ func (c *Client) proxyWebsocket(config *websocket.Config, proxy string) (*websoc
 		return nil, err
 	}
 
-	data, err := bufio.NewReader(conn).ReadString('\n')
-
+	// read the whole response so its headers are not mistaken for the tls handshake
+	res, err := http.ReadResponse(bufio.NewReader(conn), &http.Request{Method: "CONNECT"})
 	if err != nil {
+		conn.Close()
 		return nil, err
 	}
 
-	// need an http 200 response
-	if !strings.Contains(string(data), " 200 ") {
-		return nil, fmt.Errorf("proxy error: %s", strings.TrimSpace(string(data)))
+	res.Body.Close()
+
+	if res.StatusCode != 200 {
+		conn.Close()
+		return nil, fmt.Errorf("proxy error: %s", res.Status)
 	}
 
-	return websocket.NewClient(config, tls.Client(conn, config.TlsConfig))
+	return conn, nil
+}
+
+// streamProxy returns the proxy to use for connections to the rack from
+// HTTPS_PROXY and NO_PROXY
+func (c *Client) streamProxy() (*url.URL, error) {
+	return http.ProxyFromEnvironment(&http.Request{URL: &url.URL{Scheme: "https", Host: c.Host}})
 }
@@ -1,12 +1,24 @@
 package client
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/convox/rack/client/models"
 	"github.com/convox/rack/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -23,7 +35,7 @@ func testClient(t *testing.T, serverUrl string) *Client {
 }
 
 func testServer(t *testing.T, stubs ...test.Http) *httptest.Server {
-	stubs = append(stubs, test.Http{Method: "GET", Path: "/system", Code: 200, Response: System{
+	stubs = append(stubs, test.Http{Method: "GET", Path: "/system", Code: 200, Response: models.System{
 		Version: "test",
 	}})
 
@@ -76,3 +88,177 @@ func TestClientGetErrors(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Equal(t, "parse https:///%: invalid URL escape \"%\"", err.Error())
 }
+
+func TestClientTypedErrors(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/missing", Code: 404, Response: Error{Error: "no such app: missing"}},
+		test.Http{Method: "GET", Path: "/apps/invalid", Code: 403, Response: Error{Error: "count must be numeric", Field: "count"}},
+		test.Http{Method: "GET", Path: "/apps/denied", Code: 401, Response: "invalid authorization"},
+	)
+
+	defer ts.Close()
+
+	client := testClient(t, ts.URL)
+
+	_, err := client.GetApp("missing")
+	assert.Equal(t, ErrNotFound{Message: "no such app: missing"}, err)
+
+	_, err = client.GetApp("invalid")
+	assert.Equal(t, ErrValidation{Message: "count must be numeric", Field: "count"}, err)
+
+	_, err = client.GetApp("denied")
+	assert.Equal(t, ErrUnauthorized{Message: "response status: 401"}, err)
+}
+
+func TestClientProxyDialRejected(t *testing.T) {
+	headers := make(chan http.Header, 1)
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		headers <- r.Header
+		rw.WriteHeader(407)
+	}))
+
+	defer proxy.Close()
+
+	u, _ := url.Parse(proxy.URL)
+	u.User = url.UserPassword("user", "p@ss")
+
+	client := New("rack.example.com", "test", "test")
+
+	_, err := client.proxyDial(u)
+
+	require.NotNil(t, err)
+	assert.Equal(t, "proxy error: 407 Proxy Authentication Required", err.Error())
+	assert.Equal(t, "Basic dXNlcjpwQHNz", (<-headers).Get("Proxy-Authorization"))
+}
+
+func testCertificate(t *testing.T, name string) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-1 * time.Hour),
+		NotAfter:     time.Now().Add(1 * time.Hour),
+		DNSNames:     []string{name},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+func pinnedServer(t *testing.T, cert tls.Certificate) *httptest.Server {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Write([]byte(`{"version":"test"}`))
+	}))
+
+	ts.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+	ts.StartTLS()
+
+	return ts
+}
+
+func TestClientPin(t *testing.T) {
+	rack := testCertificate(t, "rack.example.com")
+
+	ts := pinnedServer(t, rack)
+	defer ts.Close()
+
+	client := testClient(t, ts.URL)
+	client.Pin = SPKIPin(rack.Leaf)
+
+	system, err := client.GetSystem()
+	require.NoError(t, err)
+	assert.Equal(t, "test", system.Version)
+}
+
+func TestClientPinAttackerLeaf(t *testing.T) {
+	rack := testCertificate(t, "rack.example.com")
+	attacker := testCertificate(t, "rack.example.com")
+
+	// the attacker holds its own key and appends the public pinned certificate to its chain
+	chain := attacker
+	chain.Certificate = [][]byte{attacker.Certificate[0], rack.Certificate[0]}
+
+	ts := pinnedServer(t, chain)
+	defer ts.Close()
+
+	client := testClient(t, ts.URL)
+	client.Pin = SPKIPin(rack.Leaf)
+
+	_, err := client.GetSystem()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not match pin")
+}
+
+func TestClientSign(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "POST", Path: "/apps", Body: "name=foo", Code: 200, Response: models.App{Name: "foo"}},
+	)
+
+	defer ts.Close()
+
+	client := testClient(t, ts.URL)
+	client.Sign = true
+
+	req, err := client.request("POST", "/apps?x=1", strings.NewReader("name=foo"))
+	require.Nil(t, err)
+
+	hash := req.Header.Get("Content-Sha256")
+
+	assert.Equal(t, hashBytes([]byte("name=foo")), hash)
+	assert.Equal(t, fmt.Sprintf("Convox-HMAC-SHA256 Signature=%s", requestSignature("test", "POST", "/apps?x=1", req.Header.Get("Timestamp"), hash)), req.Header.Get("Authorization"))
+
+	body, err := ioutil.ReadAll(req.Body)
+	require.Nil(t, err)
+	assert.Equal(t, "name=foo", string(body))
+
+	req, err = client.request("POST", "/apps", io.MultiReader(strings.NewReader("stream")))
+	require.Nil(t, err)
+	assert.Equal(t, "UNSIGNED-PAYLOAD", req.Header.Get("Content-Sha256"))
+
+	app, err := client.CreateApp("foo")
+	require.Nil(t, err)
+	assert.Equal(t, "foo", app.Name)
+}
+
+func TestClientSignKeyUser(t *testing.T) {
+	client := New("rack.example.com", "secret", "test")
+	client.Sign = true
+	client.KeyUser = "alice"
+
+	req, err := client.request("GET", "/apps", nil)
+	require.Nil(t, err)
+
+	// the rack derives the same secret, the stored hash of the key never signs
+	secret := "6d5bfcc228b3e444646e7d62062fa5b2c532e8b011a7d07588d5915bf6ad4291"
+
+	assert.Equal(t, secret, userKeySigningSecret("secret"))
+	assert.Equal(t, "alice", req.Header.Get("Key-User"))
+	assert.Equal(t, fmt.Sprintf("Convox-HMAC-SHA256 Signature=%s", requestSignature(secret, "GET", "/apps", req.Header.Get("Timestamp"), hashBytes(nil))), req.Header.Get("Authorization"))
+}
+
+func TestClientBandwidthLimit(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "PUT", Path: "/uploads/U1", Body: "0123456789", Code: 200, Response: models.Upload{Id: "U1", Size: 10, Offset: 10}},
+	)
+
+	defer ts.Close()
+
+	client := testClient(t, ts.URL)
+	client.BandwidthLimit = 20
+
+	started := time.Now()
+
+	upload, err := client.UploadChunk("U1", 0, []byte("0123456789"))
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(10), upload.Offset)
+	assert.True(t, time.Since(started) >= 400*time.Millisecond, "body should be sent at the limit")
+}
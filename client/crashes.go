@@ -0,0 +1,37 @@
+package client
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/convox/rack/client/models"
+)
+
+// GetCrashes returns the captured crashes of an app, latest first
+func (c *Client) GetCrashes(app string) (models.Crashes, error) {
+	var crashes models.Crashes
+
+	err := c.Get(fmt.Sprintf("/apps/%s/crashes", app), &crashes)
+	if err != nil {
+		return nil, err
+	}
+
+	return crashes, nil
+}
+
+// GetCrash returns a captured crash of an app along with the end of its output
+func (c *Client) GetCrash(app, id string) (*models.Crash, error) {
+	var crash models.Crash
+
+	err := c.Get(fmt.Sprintf("/apps/%s/crashes/%s", app, id), &crash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &crash, nil
+}
+
+// DownloadCrashDump writes the tar archive of the dump captured with a crash to w
+func (c *Client) DownloadCrashDump(app, id string, w io.Writer) error {
+	return c.Download(fmt.Sprintf("/apps/%s/crashes/%s/dump", app, id), w)
+}
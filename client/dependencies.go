@@ -0,0 +1,60 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/convox/rack/client/models"
+)
+
+// GetDependencies returns the declared dependencies of every app on the rack
+func (c *Client) GetDependencies() (models.Dependencies, error) {
+	var deps models.Dependencies
+
+	err := c.Get("/dependencies", &deps)
+	if err != nil {
+		return nil, err
+	}
+
+	return deps, nil
+}
+
+// AddDependencies declares that app depends on each of the apps and resources in on
+func (c *Client) AddDependencies(app string, on []string) (models.Dependencies, error) {
+	var deps models.Dependencies
+
+	params := Params{
+		"on": strings.Join(on, ","),
+	}
+
+	err := c.Post(fmt.Sprintf("/apps/%s/dependencies", app), params, &deps)
+	if err != nil {
+		return nil, err
+	}
+
+	return deps, nil
+}
+
+// RemoveDependency drops the dependency of app on name
+func (c *Client) RemoveDependency(app, name string) (models.Dependencies, error) {
+	var deps models.Dependencies
+
+	err := c.Delete(fmt.Sprintf("/apps/%s/dependencies/%s", app, name), &deps)
+	if err != nil {
+		return nil, err
+	}
+
+	return deps, nil
+}
+
+// GetDependencyImpact returns every app that depends on name directly or indirectly
+func (c *Client) GetDependencyImpact(name string) (models.DependencyImpacts, error) {
+	var impacts models.DependencyImpacts
+
+	err := c.Get(fmt.Sprintf("/dependencies/%s/impact", name), &impacts)
+	if err != nil {
+		return nil, err
+	}
+
+	return impacts, nil
+}
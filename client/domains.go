@@ -0,0 +1,46 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/convox/rack/client/models"
+)
+
+// GetDomains returns the custom domains of an app
+func (c *Client) GetDomains(app string) (models.Domains, error) {
+	var domains models.Domains
+
+	err := c.Get(fmt.Sprintf("/apps/%s/domains", app), &domains)
+	if err != nil {
+		return nil, err
+	}
+
+	return domains, nil
+}
+
+// AddDomain points a domain at the balancer of a process of an app
+func (c *Client) AddDomain(app, domain, process string) (*models.Domain, error) {
+	var d models.Domain
+
+	params := Params{
+		"domain": domain,
+	}
+
+	if process != "" {
+		params["process"] = process
+	}
+
+	err := c.Post(fmt.Sprintf("/apps/%s/domains", app), params, &d)
+	if err != nil {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// RemoveDomain deletes the record of a custom domain of an app
+func (c *Client) RemoveDomain(app, domain string) error {
+	var success interface{}
+
+	return c.Delete(fmt.Sprintf("/apps/%s/domains/%s", app, domain), &success)
+}
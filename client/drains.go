@@ -0,0 +1,47 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/convox/rack/client/models"
+)
+
+// GetDrains returns the log drains of an app
+func (c *Client) GetDrains(app string) (models.Drains, error) {
+	var drains models.Drains
+
+	err := c.Get(fmt.Sprintf("/apps/%s/drains", app), &drains)
+	if err != nil {
+		return nil, err
+	}
+
+	return drains, nil
+}
+
+// CreateDrain starts forwarding the logs of an app to url
+func (c *Client) CreateDrain(app, url string) (*models.Drain, error) {
+	var drain models.Drain
+
+	params := Params{
+		"url": url,
+	}
+
+	err := c.Post(fmt.Sprintf("/apps/%s/drains", app), params, &drain)
+	if err != nil {
+		return nil, err
+	}
+
+	return &drain, nil
+}
+
+// DeleteDrain stops forwarding logs to a drain
+func (c *Client) DeleteDrain(app, id string) (*models.Drain, error) {
+	var drain models.Drain
+
+	err := c.Delete(fmt.Sprintf("/apps/%s/drains/%s", app, id), &drain)
+	if err != nil {
+		return nil, err
+	}
+
+	return &drain, nil
+}
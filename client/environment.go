@@ -3,12 +3,12 @@ package client
 import (
 	"fmt"
 	"io"
-)
 
-type Environment map[string]string
+	"github.com/convox/rack/client/models"
+)
 
-func (c *Client) GetEnvironment(app string) (Environment, error) {
-	var env Environment
+func (c *Client) GetEnvironment(app string) (models.Environment, error) {
+	var env models.Environment
 
 	err := c.Get(fmt.Sprintf("/apps/%s/environment", app), &env)
 
@@ -19,8 +19,8 @@ func (c *Client) GetEnvironment(app string) (Environment, error) {
 	return env, nil
 }
 
-func (c *Client) SetEnvironment(app string, body io.Reader) (Environment, string, error) {
-	var env Environment
+func (c *Client) SetEnvironment(app string, body io.Reader) (models.Environment, string, error) {
+	var env models.Environment
 
 	res, err := c.PostBodyResponse(fmt.Sprintf("/apps/%s/environment", app), body, &env)
 
@@ -31,8 +31,8 @@ func (c *Client) SetEnvironment(app string, body io.Reader) (Environment, string
 	return env, res.Header.Get("Release-Id"), nil
 }
 
-func (c *Client) DeleteEnvironment(app, key string) (Environment, string, error) {
-	var env Environment
+func (c *Client) DeleteEnvironment(app, key string) (models.Environment, string, error) {
+	var env models.Environment
 
 	res, err := c.DeleteResponse(fmt.Sprintf("/apps/%s/environment/%s", app, key), &env)
 
@@ -42,3 +42,36 @@ func (c *Client) DeleteEnvironment(app, key string) (Environment, string, error)
 
 	return env, res.Header.Get("Release-Id"), nil
 }
+
+// StageEnvironment replaces the environment of an app without creating a release
+func (c *Client) StageEnvironment(app string, body io.Reader) (models.Environment, error) {
+	var env models.Environment
+
+	if _, err := c.PostBodyResponse(fmt.Sprintf("/apps/%s/environment?release=false", app), body, &env); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+// StageDeleteEnvironment removes a key from the environment of an app without creating a release
+func (c *Client) StageDeleteEnvironment(app, key string) (models.Environment, error) {
+	var env models.Environment
+
+	if _, err := c.DeleteResponse(fmt.Sprintf("/apps/%s/environment/%s?release=false", app, key), &env); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+// CommitEnvironment creates a release carrying the staged environment of an app
+func (c *Client) CommitEnvironment(app string) (*models.Release, error) {
+	var release models.Release
+
+	if err := c.Post(fmt.Sprintf("/apps/%s/environment/commit", app), Params{}, &release); err != nil {
+		return nil, err
+	}
+
+	return &release, nil
+}
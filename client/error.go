@@ -9,6 +9,36 @@ import (
 
 type Error struct {
 	Error string `json:"error"`
+	Field string `json:"field,omitempty"`
+}
+
+// ErrNotFound is returned when the requested object does not exist on the rack
+type ErrNotFound struct {
+	Message string
+}
+
+func (e ErrNotFound) Error() string {
+	return e.Message
+}
+
+// ErrUnauthorized is returned when the rack rejects the client's credentials
+type ErrUnauthorized struct {
+	Message string
+}
+
+func (e ErrUnauthorized) Error() string {
+	return e.Message
+}
+
+// ErrValidation is returned when the rack refuses a request as invalid.
+// Field is the name of the offending parameter when the rack reports one.
+type ErrValidation struct {
+	Message string
+	Field   string
+}
+
+func (e ErrValidation) Error() string {
+	return e.Message
 }
 
 func responseError(res *http.Response) error {
@@ -24,11 +54,24 @@ func responseError(res *http.Response) error {
 
 	var e Error
 
-	err = json.Unmarshal(data, &e)
+	message := ""
 
-	if err != nil {
-		return fmt.Errorf("response status: %d", res.StatusCode)
+	if err := json.Unmarshal(data, &e); err == nil {
+		message = e.Error
+	}
+
+	if message == "" {
+		message = fmt.Sprintf("response status: %d", res.StatusCode)
+	}
+
+	switch res.StatusCode {
+	case 400, 403, 422:
+		return ErrValidation{Message: message, Field: e.Field}
+	case 401:
+		return ErrUnauthorized{Message: message}
+	case 404:
+		return ErrNotFound{Message: message}
 	}
 
-	return fmt.Errorf(e.Error)
+	return fmt.Errorf("%s", message)
 }
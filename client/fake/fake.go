@@ -0,0 +1,553 @@
+// Package fake is an in-memory implementation of client.Interface for testing
+// programs built on the rack client without a live rack.
+package fake
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/models"
+)
+
+// Client keeps apps, builds, releases, environments and processes in memory
+type Client struct {
+	sync.Mutex
+
+	// calling a part of the rack api the fake does not keep panics
+	client.Interface
+
+	apps      map[string]*models.App
+	builds    map[string]models.Builds
+	releases  map[string]models.Releases
+	env       map[string]models.Environment
+	processes map[string]models.Processes
+
+	ids int
+}
+
+var _ client.Interface = &Client{}
+
+// New returns an empty fake rack
+func New() *Client {
+	return &Client{
+		apps:      map[string]*models.App{},
+		builds:    map[string]models.Builds{},
+		releases:  map[string]models.Releases{},
+		env:       map[string]models.Environment{},
+		processes: map[string]models.Processes{},
+	}
+}
+
+// AddBuild stores a build for an existing app, assigning an id if it has none
+func (c *Client) AddBuild(app string, b models.Build) (*models.Build, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if _, ok := c.apps[app]; !ok {
+		return nil, noSuchApp(app)
+	}
+
+	if b.Id == "" {
+		b.Id = c.id("B")
+	}
+
+	if b.Started.IsZero() {
+		b.Started = time.Now()
+	}
+
+	b.App = app
+
+	c.builds[app] = append(c.builds[app], b)
+
+	return &b, nil
+}
+
+// AddRelease stores a release for an existing app, assigning an id if it has none
+func (c *Client) AddRelease(app string, r models.Release) (*models.Release, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if _, ok := c.apps[app]; !ok {
+		return nil, noSuchApp(app)
+	}
+
+	if r.Id == "" {
+		r.Id = c.id("R")
+	}
+
+	if r.Created.IsZero() {
+		r.Created = time.Now()
+	}
+
+	r.App = app
+
+	c.releases[app] = append(c.releases[app], r)
+
+	return &r, nil
+}
+
+// AddProcess stores a running process for an existing app, assigning an id if it has none
+func (c *Client) AddProcess(app string, p models.Process) (*models.Process, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if _, ok := c.apps[app]; !ok {
+		return nil, noSuchApp(app)
+	}
+
+	if p.Id == "" {
+		p.Id = c.id("P")
+	}
+
+	if p.Started.IsZero() {
+		p.Started = time.Now()
+	}
+
+	p.App = app
+
+	c.processes[app] = append(c.processes[app], p)
+
+	return &p, nil
+}
+
+func (c *Client) GetApps() (models.Apps, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	apps := models.Apps{}
+
+	for _, a := range c.apps {
+		apps = append(apps, *a)
+	}
+
+	sort.Sort(appsByName(apps))
+
+	return apps, nil
+}
+
+func (c *Client) CreateApp(name string) (*models.App, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if _, ok := c.apps[name]; ok {
+		return nil, client.ErrValidation{Message: fmt.Sprintf("app already exists: %s", name), Field: "name"}
+	}
+
+	a := &models.App{Name: name, Status: "running"}
+
+	c.apps[name] = a
+	c.env[name] = models.Environment{}
+
+	app := *a
+
+	return &app, nil
+}
+
+func (c *Client) GetApp(name string) (*models.App, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	a, ok := c.apps[name]
+	if !ok {
+		return nil, noSuchApp(name)
+	}
+
+	app := *a
+
+	return &app, nil
+}
+
+func (c *Client) DeleteApp(name string) (*models.App, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	a, ok := c.apps[name]
+	if !ok {
+		return nil, noSuchApp(name)
+	}
+
+	delete(c.apps, name)
+	delete(c.builds, name)
+	delete(c.releases, name)
+	delete(c.env, name)
+	delete(c.processes, name)
+
+	app := *a
+	app.Status = "deleting"
+
+	return &app, nil
+}
+
+func (c *Client) GetBuilds(app string) (models.Builds, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if _, ok := c.apps[app]; !ok {
+		return nil, noSuchApp(app)
+	}
+
+	builds := append(models.Builds{}, c.builds[app]...)
+
+	sort.Sort(buildsByNewest(builds))
+
+	return builds, nil
+}
+
+func (c *Client) GetBuild(app, id string) (*models.Build, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	i, err := c.build(app, id)
+	if err != nil {
+		return nil, err
+	}
+
+	b := c.builds[app][i]
+
+	return &b, nil
+}
+
+// StreamBuildLogs writes the stored logs of a build to output
+func (c *Client) StreamBuildLogs(app, id string, output io.WriteCloser) error {
+	b, err := c.GetBuild(app, id)
+	if err != nil {
+		return err
+	}
+
+	defer output.Close()
+
+	_, err = io.WriteString(output, b.Logs)
+
+	return err
+}
+
+func (c *Client) CancelBuild(app, id string) (*models.Build, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	i, err := c.build(app, id)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &c.builds[app][i]
+
+	if !b.Ended.IsZero() {
+		return nil, client.ErrValidation{Message: fmt.Sprintf("build has already finished: %s", id)}
+	}
+
+	b.Status = "cancelled"
+	b.Ended = time.Now()
+
+	build := *b
+
+	return &build, nil
+}
+
+func (c *Client) DeleteBuild(app, id string) (*models.Build, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	i, err := c.build(app, id)
+	if err != nil {
+		return nil, err
+	}
+
+	b := c.builds[app][i]
+
+	c.builds[app] = append(c.builds[app][:i], c.builds[app][i+1:]...)
+
+	return &b, nil
+}
+
+func (c *Client) GetReleases(app string) (models.Releases, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if _, ok := c.apps[app]; !ok {
+		return nil, noSuchApp(app)
+	}
+
+	releases := append(models.Releases{}, c.releases[app]...)
+
+	sort.Sort(releasesByNewest(releases))
+
+	return releases, nil
+}
+
+func (c *Client) GetRelease(app, id string) (*models.Release, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	i, err := c.release(app, id)
+	if err != nil {
+		return nil, err
+	}
+
+	r := c.releases[app][i]
+
+	return &r, nil
+}
+
+// PromoteRelease makes a release the active release of its app
+func (c *Client) PromoteRelease(app, id string) (*models.Release, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	i, err := c.release(app, id)
+	if err != nil {
+		return nil, err
+	}
+
+	c.apps[app].Release = id
+
+	r := c.releases[app][i]
+
+	return &r, nil
+}
+
+func (c *Client) GetEnvironment(app string) (models.Environment, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if _, ok := c.apps[app]; !ok {
+		return nil, noSuchApp(app)
+	}
+
+	return copyEnvironment(c.env[app]), nil
+}
+
+// SetEnvironment replaces the app environment with the KEY=VALUE lines in body
+// and creates a new release carrying it, like the rack does
+func (c *Client) SetEnvironment(app string, body io.Reader) (models.Environment, string, error) {
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	env := models.Environment{}
+
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+
+		if len(parts) != 2 {
+			return nil, "", client.ErrValidation{Message: fmt.Sprintf("invalid environment line: %s", line)}
+		}
+
+		env[parts[0]] = parts[1]
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if _, ok := c.apps[app]; !ok {
+		return nil, "", noSuchApp(app)
+	}
+
+	c.env[app] = env
+
+	return copyEnvironment(env), c.envRelease(app), nil
+}
+
+// DeleteEnvironment removes a key from the app environment and creates a new release carrying it
+func (c *Client) DeleteEnvironment(app, key string) (models.Environment, string, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if _, ok := c.apps[app]; !ok {
+		return nil, "", noSuchApp(app)
+	}
+
+	delete(c.env[app], key)
+
+	return copyEnvironment(c.env[app]), c.envRelease(app), nil
+}
+
+func (c *Client) GetProcesses(app string, stats bool) (models.Processes, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if _, ok := c.apps[app]; !ok {
+		return nil, noSuchApp(app)
+	}
+
+	return append(models.Processes{}, c.processes[app]...), nil
+}
+
+func (c *Client) GetProcess(app, id string) (*models.Process, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	i, err := c.process(app, id)
+	if err != nil {
+		return nil, err
+	}
+
+	p := c.processes[app][i]
+
+	return &p, nil
+}
+
+// RunProcessDetached starts a one-off process that keeps running until stopped
+func (c *Client) RunProcessDetached(app, process, command, release string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	a, ok := c.apps[app]
+	if !ok {
+		return noSuchApp(app)
+	}
+
+	if release == "" {
+		release = a.Release
+	}
+
+	c.processes[app] = append(c.processes[app], models.Process{
+		Id:      c.id("P"),
+		App:     app,
+		Command: command,
+		Name:    process,
+		Release: release,
+		Started: time.Now(),
+	})
+
+	return nil
+}
+
+func (c *Client) StopProcess(app, id string) (*models.Process, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	i, err := c.process(app, id)
+	if err != nil {
+		return nil, err
+	}
+
+	p := c.processes[app][i]
+
+	c.processes[app] = append(c.processes[app][:i], c.processes[app][i+1:]...)
+
+	return &p, nil
+}
+
+func (c *Client) build(app, id string) (int, error) {
+	if _, ok := c.apps[app]; !ok {
+		return 0, noSuchApp(app)
+	}
+
+	for i, b := range c.builds[app] {
+		if b.Id == id {
+			return i, nil
+		}
+	}
+
+	return 0, client.ErrNotFound{Message: fmt.Sprintf("no such build: %s", id)}
+}
+
+func (c *Client) release(app, id string) (int, error) {
+	if _, ok := c.apps[app]; !ok {
+		return 0, noSuchApp(app)
+	}
+
+	for i, r := range c.releases[app] {
+		if r.Id == id {
+			return i, nil
+		}
+	}
+
+	return 0, client.ErrNotFound{Message: fmt.Sprintf("no such release: %s", id)}
+}
+
+func (c *Client) process(app, id string) (int, error) {
+	if _, ok := c.apps[app]; !ok {
+		return 0, noSuchApp(app)
+	}
+
+	for i, p := range c.processes[app] {
+		if p.Id == id {
+			return i, nil
+		}
+	}
+
+	return 0, client.ErrNotFound{Message: fmt.Sprintf("no such process: %s", id)}
+}
+
+// envRelease creates a release from the latest one with the current environment and returns its id
+func (c *Client) envRelease(app string) string {
+	r := models.Release{
+		Id:      c.id("R"),
+		App:     app,
+		Created: time.Now(),
+	}
+
+	if n := len(c.releases[app]); n > 0 {
+		r.Build = c.releases[app][n-1].Build
+		r.Manifest = c.releases[app][n-1].Manifest
+	}
+
+	lines := []string{}
+
+	for k, v := range c.env[app] {
+		lines = append(lines, fmt.Sprintf("%s=%s", k, v))
+	}
+
+	sort.Strings(lines)
+
+	r.Env = strings.Join(lines, "\n")
+
+	c.releases[app] = append(c.releases[app], r)
+
+	return r.Id
+}
+
+func (c *Client) id(prefix string) string {
+	c.ids++
+	return fmt.Sprintf("%s%09d", prefix, c.ids)
+}
+
+func copyEnvironment(env models.Environment) models.Environment {
+	copied := models.Environment{}
+
+	for k, v := range env {
+		copied[k] = v
+	}
+
+	return copied
+}
+
+func noSuchApp(app string) error {
+	return client.ErrNotFound{Message: fmt.Sprintf("no such app: %s", app)}
+}
+
+type appsByName models.Apps
+
+func (a appsByName) Len() int           { return len(a) }
+func (a appsByName) Less(i, j int) bool { return a[i].Name < a[j].Name }
+func (a appsByName) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+
+type buildsByNewest models.Builds
+
+func (b buildsByNewest) Len() int           { return len(b) }
+func (b buildsByNewest) Less(i, j int) bool { return b[i].Started.After(b[j].Started) }
+func (b buildsByNewest) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+
+type releasesByNewest models.Releases
+
+func (r releasesByNewest) Len() int           { return len(r) }
+func (r releasesByNewest) Less(i, j int) bool { return r[i].Created.After(r[j].Created) }
+func (r releasesByNewest) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
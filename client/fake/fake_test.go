@@ -0,0 +1,89 @@
+package fake_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/fake"
+	"github.com/convox/rack/client/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeApps(t *testing.T) {
+	c := fake.New()
+
+	_, err := c.CreateApp("myapp")
+	require.Nil(t, err)
+
+	_, err = c.CreateApp("myapp")
+	assert.Equal(t, client.ErrValidation{Message: "app already exists: myapp", Field: "name"}, err)
+
+	apps, err := c.GetApps()
+	require.Nil(t, err)
+	assert.Equal(t, models.Apps{{Name: "myapp", Status: "running"}}, apps)
+
+	_, err = c.DeleteApp("myapp")
+	require.Nil(t, err)
+
+	_, err = c.GetApp("myapp")
+	assert.Equal(t, client.ErrNotFound{Message: "no such app: myapp"}, err)
+}
+
+func TestFakeEnvironmentReleases(t *testing.T) {
+	c := fake.New()
+
+	_, err := c.CreateApp("myapp")
+	require.Nil(t, err)
+
+	_, err = c.AddRelease("myapp", models.Release{Id: "R1", Build: "B1"})
+	require.Nil(t, err)
+
+	env, id, err := c.SetEnvironment("myapp", strings.NewReader("FOO=bar\nBAZ=qux\n"))
+	require.Nil(t, err)
+	assert.Equal(t, models.Environment{"FOO": "bar", "BAZ": "qux"}, env)
+
+	r, err := c.GetRelease("myapp", id)
+	require.Nil(t, err)
+	assert.Equal(t, "B1", r.Build)
+	assert.Equal(t, "BAZ=qux\nFOO=bar", r.Env)
+
+	_, err = c.PromoteRelease("myapp", id)
+	require.Nil(t, err)
+
+	a, err := c.GetApp("myapp")
+	require.Nil(t, err)
+	assert.Equal(t, id, a.Release)
+}
+
+func TestFakeBuildsProcesses(t *testing.T) {
+	c := fake.New()
+
+	_, err := c.CreateApp("myapp")
+	require.Nil(t, err)
+
+	b, err := c.AddBuild("myapp", models.Build{Status: "running"})
+	require.Nil(t, err)
+
+	b, err = c.CancelBuild("myapp", b.Id)
+	require.Nil(t, err)
+	assert.Equal(t, "cancelled", b.Status)
+
+	_, err = c.CancelBuild("myapp", b.Id)
+	assert.IsType(t, client.ErrValidation{}, err)
+
+	require.Nil(t, c.RunProcessDetached("myapp", "web", "rake db:migrate", ""))
+
+	ps, err := c.GetProcesses("myapp", false)
+	require.Nil(t, err)
+	require.Len(t, ps, 1)
+	assert.Equal(t, "rake db:migrate", ps[0].Command)
+
+	_, err = c.StopProcess("myapp", ps[0].Id)
+	require.Nil(t, err)
+
+	ps, err = c.GetProcesses("myapp", false)
+	require.Nil(t, err)
+	assert.Len(t, ps, 0)
+}
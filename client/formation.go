@@ -1,17 +1,10 @@
 package client
 
-import "fmt"
-
-type FormationEntry struct {
-	Balancer string `json:"balancer"`
-	Name     string `json:"name"`
-	Count    int    `json:"count"`
-	Memory   int    `json:"memory"`
-	CPU      int    `json:"cpu"`
-	Ports    []int  `json:"ports"`
-}
+import (
+	"fmt"
 
-type Formation []FormationEntry
+	"github.com/convox/rack/client/models"
+)
 
 // FormationOptions carries the numeric dimensions that can change for a process type.
 // Empty string indicates no change.
@@ -19,10 +12,59 @@ type FormationOptions struct {
 	Count  string
 	CPU    string
 	Memory string
+
+	// Grow adds rack instances when the change does not fit on the current ones
+	Grow bool
+
+	// Autoscale is "true" to keep the count between AutoscaleMin and AutoscaleMax based on
+	// CPU utilization, "false" to go back to a fixed count
+	Autoscale    string
+	AutoscaleMin string
+	AutoscaleMax string
+	AutoscaleCPU string
 }
 
-func (c *Client) ListFormation(app string) (Formation, error) {
-	var formation Formation
+// Params returns the form values that SetFormation sends for the options
+func (o FormationOptions) Params() map[string]string {
+	params := map[string]string{}
+
+	if o.Count != "" {
+		params["count"] = o.Count
+	}
+
+	if o.CPU != "" {
+		params["cpu"] = o.CPU
+	}
+
+	if o.Memory != "" {
+		params["memory"] = o.Memory
+	}
+
+	if o.Grow {
+		params["grow"] = "true"
+	}
+
+	if o.Autoscale != "" {
+		params["autoscale"] = o.Autoscale
+	}
+
+	if o.AutoscaleMin != "" {
+		params["autoscale-min"] = o.AutoscaleMin
+	}
+
+	if o.AutoscaleMax != "" {
+		params["autoscale-max"] = o.AutoscaleMax
+	}
+
+	if o.AutoscaleCPU != "" {
+		params["autoscale-cpu"] = o.AutoscaleCPU
+	}
+
+	return params
+}
+
+func (c *Client) ListFormation(app string) (models.Formation, error) {
+	var formation models.Formation
 
 	err := c.Get(fmt.Sprintf("/apps/%s/formation", app), &formation)
 	if err != nil {
@@ -36,20 +78,6 @@ func (c *Client) ListFormation(app string) (Formation, error) {
 func (c *Client) SetFormation(app, process string, opts FormationOptions) error {
 	var success interface{}
 
-	params := map[string]string{}
-
-	if opts.Count != "" {
-		params["count"] = opts.Count
-	}
-
-	if opts.CPU != "" {
-		params["cpu"] = opts.CPU
-	}
-
-	if opts.Memory != "" {
-		params["memory"] = opts.Memory
-	}
-
-	err := c.Post(fmt.Sprintf("/apps/%s/formation/%s", app, process), params, &success)
+	err := c.Post(fmt.Sprintf("/apps/%s/formation/%s", app, process), opts.Params(), &success)
 	return err
 }
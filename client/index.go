@@ -3,20 +3,11 @@ package client
 import (
 	"encoding/json"
 	"fmt"
-	"os"
-	"time"
-)
-
-type Index map[string]IndexItem
 
-type IndexItem struct {
-	Name    string      `json:"name"`
-	Mode    os.FileMode `json:"mode"`
-	ModTime time.Time   `json:"mtime"`
-	Size    int         `json:"-"`
-}
+	"github.com/convox/rack/client/models"
+)
 
-func (c *Client) IndexMissing(index Index) ([]string, error) {
+func (c *Client) IndexMissing(index models.Index) ([]string, error) {
 	var missing []string
 
 	data, err := json.Marshal(index)
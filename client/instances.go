@@ -5,23 +5,12 @@ import (
 	"fmt"
 	"io"
 	"strconv"
-	"time"
-)
 
-type Instance struct {
-	Agent     bool      `json:"agent"`
-	Cpu       float64   `json:"cpu"`
-	Id        string    `json:"id"`
-	Memory    float64   `json:"memory"`
-	PrivateIp string    `json:"private-ip"`
-	Processes int       `json:"processes"`
-	PublicIp  string    `json:"public-ip"`
-	Status    string    `json:"status"`
-	Started   time.Time `json:"started"`
-}
+	"github.com/convox/rack/client/models"
+)
 
-func (c *Client) GetInstances() ([]*Instance, error) {
-	var instances []*Instance
+func (c *Client) GetInstances() ([]*models.Instance, error) {
+	var instances []*models.Instance
 
 	err := c.Get("/instances", &instances)
 
@@ -32,6 +21,30 @@ func (c *Client) GetInstances() ([]*Instance, error) {
 	return instances, nil
 }
 
+// GetInstancesStats returns the instances of the rack along with the disk usage of each
+func (c *Client) GetInstancesStats() ([]*models.Instance, error) {
+	var instances []*models.Instance
+
+	err := c.Get("/instances?stats=true", &instances)
+	if err != nil {
+		return nil, err
+	}
+
+	return instances, nil
+}
+
+// GetInstanceProcesses lists the containers of every app running on an instance
+func (c *Client) GetInstanceProcesses(id string, stats bool) (models.Processes, error) {
+	var processes models.Processes
+
+	err := c.Get(fmt.Sprintf("/instances/%s/processes?stats=%t", id, stats), &processes)
+	if err != nil {
+		return nil, err
+	}
+
+	return processes, nil
+}
+
 func (c *Client) InstanceKeyroll() error {
 	var response map[string]interface{}
 	err := c.Post("/instances/keyroll", nil, &response)
@@ -47,7 +60,9 @@ func (c *Client) InstanceKeyroll() error {
 	return nil
 }
 
-func (c *Client) SSHInstance(id, cmd string, height, width int, isTerm bool, in io.Reader, out io.WriteCloser) (int, error) {
+// SSHInstance runs a shell or command on an instance through the rack. When agent is set the
+// local ssh agent it dials is forwarded to the instance.
+func (c *Client) SSHInstance(id, cmd string, height, width int, isTerm bool, agent func() (io.ReadWriteCloser, error), in io.Reader, out io.WriteCloser) (int, error) {
 	r, w := io.Pipe()
 
 	defer r.Close()
@@ -66,8 +81,15 @@ func (c *Client) SSHInstance(id, cmd string, height, width int, isTerm bool, in
 		headers["Width"] = strconv.Itoa(width)
 		headers["Terminal"] = "xterm"
 	}
-	err := c.Stream(fmt.Sprintf("/instances/%s/ssh", id), headers, in, w)
+	path := fmt.Sprintf("/instances/%s/ssh", id)
 
+	var err error
+
+	if agent != nil {
+		err = c.StreamAgent(path, headers, in, w, agent)
+	} else {
+		err = c.Stream(path, headers, in, w)
+	}
 	if err != nil {
 		return -1, err
 	}
@@ -0,0 +1,216 @@
+package client
+
+import (
+	"io"
+	"time"
+
+	"github.com/convox/rack/client/models"
+)
+
+// Interface is the rack API the convox CLI uses. Client implements it against a live rack and
+// the fake package implements the apps, builds, releases, environment and processes in memory for tests.
+type Interface interface {
+	Get(path string, out interface{}) error
+	Delete(path string, out interface{}) error
+
+	GetApps() (models.Apps, error)
+	CreateApp(name string) (*models.App, error)
+	GetApp(name string) (*models.App, error)
+	DeleteApp(name string) (*models.App, error)
+	CancelAppUpdate(name string) error
+	StreamAppEvents(app string, output io.WriteCloser) error
+	StreamAppLogs(app, process, filter string, follow bool, since time.Duration, output io.WriteCloser) error
+
+	GetBuilds(app string) (models.Builds, error)
+	GetBuild(app, id string) (*models.Build, error)
+	StreamBuildLogs(app, id string, output io.WriteCloser) error
+	CancelBuild(app, id string) (*models.Build, error)
+	DeleteBuild(app, id string) (*models.Build, error)
+	CreateBuildIndex(app string, index models.Index, opts BuildOptions) (*models.Build, error)
+	CreateBuildSourceBlobs(app string, source io.Reader, blobs models.Index, opts BuildOptions, progressCallback func(s string)) (*models.Build, error)
+	CreateBuildUpload(app string, upload string, blobs models.Index, opts BuildOptions) (*models.Build, error)
+	CreateBuildUrl(app string, url string, opts BuildOptions) (*models.Build, error)
+	CreateBuildRelease(app, id string) (*models.Release, error)
+	CopyBuild(app, id, destApp string) (*models.Build, error)
+	ExportBuild(app, id string, w io.Writer) error
+	ImportBuild(app string, source io.Reader, progressCallback func(s string)) (*models.Build, error)
+	ImportBuildUpload(app string, upload string) (*models.Build, error)
+
+	GetReleases(app string) (models.Releases, error)
+	GetRelease(app, id string) (*models.Release, error)
+	PromoteRelease(app, id string) (*models.Release, error)
+	DiffReleases(app, from, to string) (*models.ReleaseDiff, error)
+	PromoteReleaseProcesses(app, id string, processes []string) (*models.Release, error)
+	PreviewPromoteRelease(app, id string, processes []string) (models.StackChanges, error)
+	PromoteReleaseWait(app, id string, timeout time.Duration, processes []string) (*models.Release, error)
+	GetReleasePromotions(app, id string) (models.ReleasePromotions, error)
+
+	GetEnvironment(app string) (models.Environment, error)
+	SetEnvironment(app string, body io.Reader) (models.Environment, string, error)
+	DeleteEnvironment(app, key string) (models.Environment, string, error)
+	StageEnvironment(app string, body io.Reader) (models.Environment, error)
+	StageDeleteEnvironment(app, key string) (models.Environment, error)
+	CommitEnvironment(app string) (*models.Release, error)
+
+	GetProcesses(app string, stats bool) (models.Processes, error)
+	GetProcess(app, id string) (*models.Process, error)
+	RunProcessDetached(app, process, command, release string) error
+	StopProcess(app, id string) (*models.Process, error)
+	ExecProcessAttached(app, pid, command string, in io.Reader, out io.WriteCloser, height, width int) (int, error)
+	DebugProcessAttached(app, pid, image, command string, in io.Reader, out io.WriteCloser, height, width int) (int, error)
+	RunProcessAttached(app, process, command, release string, height, width int, in io.Reader, out io.WriteCloser) (int, error)
+	ProfileProcess(app, pid, typ, duration string, w io.Writer) (string, error)
+
+	GetAlarms(app string) (models.AlarmSettings, error)
+	EnableAlarm(app, metric string) error
+	DisableAlarm(app, metric string) error
+	GetCustomAlarms(app string) (models.CustomAlarms, error)
+	CreateCustomAlarm(app string, opts CustomAlarmOptions) (*models.CustomAlarm, error)
+	UpdateCustomAlarm(app, id string, opts CustomAlarmOptions) (*models.CustomAlarm, error)
+	DeleteCustomAlarm(app, id string) error
+
+	GetAppImportECS(cluster, service string) (*models.AppImport, error)
+
+	GetAudit(opts AuditOptions) (models.AuditEvents, error)
+
+	GetBreakGlass(app string) (models.BreakGlasses, error)
+	RequestBreakGlass(app string, duration time.Duration, reason string) (*models.BreakGlass, error)
+	ApproveBreakGlass(app, id string) (*models.BreakGlass, error)
+	DenyBreakGlass(app, id string) (*models.BreakGlass, error)
+	RevokeBreakGlass(app, id string) (*models.BreakGlass, error)
+
+	GetBuildSchedules(app string) (models.BuildSchedules, error)
+	CreateBuildSchedule(app, schedule, url, manifest string) (*models.BuildSchedule, error)
+	DeleteBuildSchedule(app, id string) error
+
+	GetCanary(app string) (*models.Canary, error)
+	CreateCanary(app, release string, percent int) (*models.Canary, error)
+	FinalizeCanary(app string) (*models.Release, error)
+	AbortCanary(app string) error
+
+	CreateCertificate(pub, key, chain string) (*models.Certificate, error)
+	DeleteCertificate(id string) error
+	GenerateCertificate(domains []string) (*models.Certificate, error)
+	GenerateLetsEncryptCertificate(domains []string, challenge string) (*models.Certificate, error)
+	ListCertificates() (models.Certificates, error)
+
+	GetCrashes(app string) (models.Crashes, error)
+	GetCrash(app, id string) (*models.Crash, error)
+	DownloadCrashDump(app, id string, w io.Writer) error
+
+	GetDependencies() (models.Dependencies, error)
+	AddDependencies(app string, on []string) (models.Dependencies, error)
+	RemoveDependency(app, name string) (models.Dependencies, error)
+	GetDependencyImpact(name string) (models.DependencyImpacts, error)
+
+	GetDomains(app string) (models.Domains, error)
+	AddDomain(app, domain, process string) (*models.Domain, error)
+	RemoveDomain(app, domain string) error
+
+	GetDrains(app string) (models.Drains, error)
+	CreateDrain(app, url string) (*models.Drain, error)
+	DeleteDrain(app, id string) (*models.Drain, error)
+
+	ListFormation(app string) (models.Formation, error)
+	SetFormation(app, process string, opts FormationOptions) error
+
+	IndexMissing(index models.Index) ([]string, error)
+	IndexUpdate(update []byte, progressCallback func(s string)) error
+
+	GetInstances() ([]*models.Instance, error)
+	GetInstancesStats() ([]*models.Instance, error)
+	GetInstanceProcesses(id string, stats bool) (models.Processes, error)
+	InstanceKeyroll() error
+	SSHInstance(id, cmd string, height, width int, isTerm bool, agent func() (io.ReadWriteCloser, error), in io.Reader, out io.WriteCloser) (int, error)
+	TerminateInstance(id string) error
+
+	CreateLink(app, name string) (*models.Service, string, error)
+	DeleteLink(app, name string) (*models.Service, string, error)
+
+	GetLogScrubRules(app string) (models.LogScrubRules, error)
+	CreateLogScrubRule(app, name, field, pattern string) (*models.LogScrubRule, error)
+	DeleteLogScrubRule(app, name string) error
+
+	GetAppMetrics(app, process string, period time.Duration) (models.Metrics, error)
+
+	GetMonitors(app string) (models.Monitors, error)
+	GetMonitor(app, id string) (*models.Monitor, error)
+	CreateMonitor(app, url string, opts MonitorOptions) (*models.Monitor, error)
+	DeleteMonitor(app, id string) (*models.Monitor, error)
+
+	GetNotifications() (models.Notifications, error)
+	AddNotification(url string, events []string) (*models.Notification, error)
+	RemoveNotification(id string) error
+
+	ListParameters(app string) (models.Parameters, error)
+	GetParameterSchema(app string) (models.ParameterSchemas, error)
+	GetParameterHistory(app string) (models.ParameterChanges, error)
+	SetParameters(app string, params map[string]string) error
+	PreviewParameters(app string, params map[string]string) (models.StackChanges, error)
+
+	GetPipelines() (models.Pipelines, error)
+	CreatePipeline(name, app, stages, approval string) (*models.Pipeline, error)
+	DeletePipeline(name string) error
+	GetPipelineRuns(name string) (models.PipelineRuns, error)
+	CreatePipelineRun(name, build string) (*models.PipelineRun, error)
+	ApprovePipelineRun(name, id string) (*models.PipelineRun, error)
+	UpdatePipelineStage(name, id, stage, status, build, release string) (*models.PipelineRun, error)
+
+	GetPolicy(app string) (*models.Policy, error)
+	SetPolicy(app string, immutable bool) (*models.Policy, error)
+
+	Proxy(host string, port int, rw io.ReadWriteCloser) error
+
+	GetRackEvents(opts RackEventOptions) (models.RackEvents, error)
+
+	Racks() ([]models.Rack, error)
+	StreamRackLogs(filter string, follow bool, since time.Duration, output io.WriteCloser) error
+
+	AddRegistry(server, username, password, email string) (*models.Registry, error)
+	RemoveRegistry(server string) (*models.Registry, error)
+	ListRegistries() (*models.Registries, error)
+
+	GetUptimeReport(app, month string) (*models.UptimeReport, error)
+
+	GetSecrets(app string) ([]string, error)
+	GetSecret(app, name string) (string, error)
+	SetSecret(app, name, value string) error
+	DeleteSecret(app, name string) error
+
+	GetServices() (models.Services, error)
+	CreateService(kind string, options map[string]string) (*models.Service, error)
+	GetService(name string) (*models.Service, error)
+	DeleteService(name string) (*models.Service, error)
+	UpdateService(name string, options map[string]string) (*models.Service, error)
+
+	ListSSL(app string) (*models.SSLs, error)
+	UpdateSSL(app, process, port, id string) (*models.SSL, error)
+
+	GetAppStatus(app string) (*models.AppStatus, error)
+	SetAppStatus(app, state, message string) (*models.AppStatus, error)
+	SetAppStatusPublic(app string, public bool) (*models.AppStatus, error)
+
+	GetSystem() (*models.System, error)
+	GetSystemCapacity() (*models.SystemCapacity, error)
+	GetSystemChecks(version string) (models.SystemChecks, error)
+	GetSystemHealth() (models.SystemChecks, error)
+	GetSystemReleases() (models.Releases, error)
+	UpdateSystem(version string, force bool) (*models.System, error)
+	ScaleSystem(count int, typ string) (*models.System, error)
+
+	GetTimers(app string) (models.Timers, error)
+	GetTimerRuns(app, name string) (models.TimerRuns, error)
+	RunTimer(app, name string) (*models.TimerRun, error)
+
+	CreateUpload(size int64) (*models.Upload, error)
+	GetUpload(id string) (*models.Upload, error)
+	UploadChunk(id string, offset int64, data []byte) (*models.Upload, error)
+
+	GetUsers() (models.UserKeys, error)
+	CreateUser(user string) (string, error)
+	DeleteUser(user string) error
+
+	GetAppWebhook(app string) (*models.AppWebhook, error)
+}
+
+var _ Interface = &Client{}
@@ -1,31 +1,38 @@
 package client
 
-import "fmt"
+import (
+	"fmt"
+	"net/url"
+	"strings"
 
-func (c *Client) CreateLink(app, name string) (*Service, error) {
-	params := Params{
-		"app": app,
-	}
+	"github.com/convox/rack/client/models"
+)
 
-	var service Service
+// CreateLink links a service to an app. Services that are linked through the environment of
+// the app return the release that carries their url.
+func (c *Client) CreateLink(app, name string) (*models.Service, string, error) {
+	form := url.Values{}
+	form.Set("app", app)
 
-	err := c.Post(fmt.Sprintf("/services/%s/links", name), params, &service)
+	var service models.Service
 
+	res, err := c.PostBodyResponse(fmt.Sprintf("/services/%s/links", name), strings.NewReader(form.Encode()), &service)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	return &service, nil
+	return &service, res.Header.Get("Release-Id"), nil
 }
 
-func (c *Client) DeleteLink(app, name string) (*Service, error) {
-	var service Service
-
-	err := c.Delete(fmt.Sprintf("/services/%s/links/%s", name, app), &service)
+// DeleteLink unlinks a service from an app. Services that are linked through the environment
+// of the app return the release that no longer carries their url.
+func (c *Client) DeleteLink(app, name string) (*models.Service, string, error) {
+	var service models.Service
 
+	res, err := c.DeleteResponse(fmt.Sprintf("/services/%s/links/%s", name, app), &service)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	return &service, nil
+	return &service, res.Header.Get("Release-Id"), nil
 }
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/convox/rack/client/models"
+)
+
+// GetLogScrubRules returns the rules that redact the logs of an app
+func (c *Client) GetLogScrubRules(app string) (models.LogScrubRules, error) {
+	var rules models.LogScrubRules
+
+	err := c.Get(fmt.Sprintf("/apps/%s/logs/scrub", app), &rules)
+	if err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// CreateLogScrubRule redacts the value of field, or everything matching pattern, from the logs of an app
+func (c *Client) CreateLogScrubRule(app, name, field, pattern string) (*models.LogScrubRule, error) {
+	var rule models.LogScrubRule
+
+	params := Params{
+		"name": name,
+	}
+
+	if field != "" {
+		params["field"] = field
+	}
+
+	if pattern != "" {
+		params["pattern"] = pattern
+	}
+
+	err := c.Post(fmt.Sprintf("/apps/%s/logs/scrub", app), params, &rule)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+// DeleteLogScrubRule stops redacting the logs of an app with a rule
+func (c *Client) DeleteLogScrubRule(app, name string) error {
+	var success interface{}
+
+	return c.Delete(fmt.Sprintf("/apps/%s/logs/scrub/%s", app, name), &success)
+}
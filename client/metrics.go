@@ -0,0 +1,30 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/convox/rack/client/models"
+)
+
+// GetAppMetrics returns the metrics of the processes of an app over period, or of a single
+// process when process is set
+func (c *Client) GetAppMetrics(app, process string, period time.Duration) (models.Metrics, error) {
+	var metrics models.Metrics
+
+	q := url.Values{}
+
+	q.Set("period", period.String())
+
+	if process != "" {
+		q.Set("process", process)
+	}
+
+	err := c.Get(fmt.Sprintf("/apps/%s/metrics?%s", app, q.Encode()), &metrics)
+	if err != nil {
+		return nil, err
+	}
+
+	return metrics, nil
+}
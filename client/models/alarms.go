@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+type Alarm struct {
+	Name    string    `json:"name"`
+	Metric  string    `json:"metric"`
+	Process string    `json:"process"`
+	State   string    `json:"state"`
+	Reason  string    `json:"reason"`
+	Updated time.Time `json:"updated"`
+}
+
+type AlarmSetting struct {
+	Metric  string  `json:"metric"`
+	Enabled bool    `json:"enabled"`
+	Alarms  []Alarm `json:"alarms"`
+}
+
+type AlarmSettings []AlarmSetting
+
+type CustomAlarm struct {
+	Id          string    `json:"id"`
+	App         string    `json:"app"`
+	Process     string    `json:"process"`
+	Metric      string    `json:"metric"`
+	Statistic   string    `json:"statistic"`
+	Threshold   float64   `json:"threshold"`
+	Comparison  string    `json:"comparison"`
+	Period      int       `json:"period"`
+	Evaluations int       `json:"evaluations"`
+	Action      string    `json:"action"`
+	State       string    `json:"state"`
+	Reason      string    `json:"reason"`
+	Created     time.Time `json:"created"`
+}
+
+type CustomAlarms []CustomAlarm
@@ -0,0 +1,9 @@
+package models
+
+// AppImport describes an app generated from a service that runs outside of the rack
+type AppImport struct {
+	Name        string      `json:"name"`
+	Manifest    string      `json:"manifest"`
+	Environment Environment `json:"environment"`
+	Formation   Formation   `json:"formation"`
+}
@@ -0,0 +1,9 @@
+package models
+
+type App struct {
+	Name    string `json:"name"`
+	Release string `json:"release"`
+	Status  string `json:"status"`
+}
+
+type Apps []App
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+type AuditEvent struct {
+	Id      string    `json:"id"`
+	Action  string    `json:"action"`
+	App     string    `json:"app"`
+	Method  string    `json:"method"`
+	Path    string    `json:"path"`
+	Status  int       `json:"status"`
+	Summary string    `json:"summary"`
+	User    string    `json:"user"`
+	Created time.Time `json:"created"`
+
+	Authenticated bool `json:"authenticated"`
+}
+
+type AuditEvents []AuditEvent
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// BreakGlass is a request by a user for temporary exec, run and environment access to an
+// immutable app
+type BreakGlass struct {
+	Id       string        `json:"id"`
+	App      string        `json:"app"`
+	User     string        `json:"user"`
+	Reason   string        `json:"reason"`
+	Duration time.Duration `json:"duration"`
+	Status   string        `json:"status"`
+	Reviewer string        `json:"reviewer,omitempty"`
+	Revoker  string        `json:"revoker,omitempty"`
+	Created  time.Time     `json:"created"`
+	Expires  time.Time     `json:"expires"`
+}
+
+type BreakGlasses []BreakGlass
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// BuildSchedule rebuilds and promotes an app from a git url on a cron schedule
+type BuildSchedule struct {
+	Id         string    `json:"id"`
+	App        string    `json:"app"`
+	Schedule   string    `json:"schedule"`
+	URL        string    `json:"url"`
+	Manifest   string    `json:"manifest"`
+	Created    time.Time `json:"created"`
+	LastRun    time.Time `json:"last-run"`
+	LastBuild  string    `json:"last-build"`
+	LastStatus string    `json:"last-status"`
+}
+
+type BuildSchedules []BuildSchedule
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+type Build struct {
+	Id       string `json:"id"`
+	App      string `json:"app"`
+	Logs     string `json:"logs"`
+	Manifest string `json:"manifest"`
+	Release  string `json:"release"`
+	Status   string `json:"status"`
+
+	Description string `json:"description"`
+
+	GitSha string            `json:"git-sha"`
+	Images map[string]string `json:"images"`
+
+	Timings *BuildTimings `json:"timings,omitempty"`
+
+	Architectures []string `json:"architectures,omitempty"`
+
+	Started time.Time `json:"started"`
+	Ended   time.Time `json:"ended"`
+}
+
+type Builds []Build
+
+type BuildTimings struct {
+	Stages []BuildStageTiming `json:"stages"`
+	Steps  []BuildStepTiming  `json:"steps"`
+}
+
+type BuildStageTiming struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+}
+
+type BuildStepTiming struct {
+	Service  string        `json:"service"`
+	Step     string        `json:"step"`
+	Cached   bool          `json:"cached"`
+	Duration time.Duration `json:"duration"`
+}
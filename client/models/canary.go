@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// Canary runs a release next to the active release behind the same balancers
+type Canary struct {
+	App      string    `json:"app"`
+	Release  string    `json:"release"`
+	Percent  int       `json:"percent"`
+	Services []string  `json:"services"`
+	Created  time.Time `json:"created"`
+}
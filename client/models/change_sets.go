@@ -0,0 +1,11 @@
+package models
+
+// StackChange is a resource that a stack update would add, modify or remove
+type StackChange struct {
+	Action      string `json:"action"`
+	Resource    string `json:"resource"`
+	Type        string `json:"type"`
+	Replacement string `json:"replacement,omitempty"`
+}
+
+type StackChanges []StackChange
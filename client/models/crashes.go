@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+type Crash struct {
+	Id        string    `json:"id"`
+	App       string    `json:"app"`
+	Process   string    `json:"process"`
+	Release   string    `json:"release"`
+	Instance  string    `json:"instance"`
+	ExitCode  int       `json:"exit-code"`
+	OOMKilled bool      `json:"oom-killed"`
+	Output    string    `json:"output"`
+	Dump      string    `json:"dump"`
+	DumpError string    `json:"dump-error,omitempty"`
+	Started   time.Time `json:"started"`
+	Crashed   time.Time `json:"crashed"`
+}
+
+type Crashes []Crash
@@ -0,0 +1,13 @@
+package models
+
+// Dependencies maps each app to the apps and resources it depends on
+type Dependencies map[string][]string
+
+// DependencyImpact is an app affected by a change to another app or resource,
+// with the chain of dependencies leading from the app to it
+type DependencyImpact struct {
+	App  string   `json:"app"`
+	Path []string `json:"path"`
+}
+
+type DependencyImpacts []DependencyImpact
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Domain is a custom domain of an app with a Route53 alias record that points at the balancer
+// of one of its processes
+type Domain struct {
+	Domain  string    `json:"domain"`
+	App     string    `json:"app"`
+	Process string    `json:"process"`
+	Target  string    `json:"target"`
+	Status  string    `json:"status"`
+	Created time.Time `json:"created"`
+}
+
+type Domains []Domain
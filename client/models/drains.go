@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Drain forwards the logs of an app to a syslog, HTTP or Kinesis endpoint
+type Drain struct {
+	Id      string    `json:"id"`
+	App     string    `json:"app"`
+	URL     string    `json:"url"`
+	Cursor  int64     `json:"cursor"`
+	Status  string    `json:"status"`
+	Error   string    `json:"error,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+type Drains []Drain
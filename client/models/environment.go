@@ -0,0 +1,3 @@
+package models
+
+type Environment map[string]string
@@ -0,0 +1,21 @@
+package models
+
+type FormationEntry struct {
+	Balancer string `json:"balancer"`
+	Name     string `json:"name"`
+	Count    int    `json:"count"`
+	Memory   int    `json:"memory"`
+	CPU      int    `json:"cpu"`
+	Ports    []int  `json:"ports"`
+
+	Autoscale *FormationAutoscale `json:"autoscale,omitempty"`
+}
+
+// FormationAutoscale is the count range and target CPU percent of an autoscaled process
+type FormationAutoscale struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+	CPU int `json:"cpu"`
+}
+
+type Formation []FormationEntry
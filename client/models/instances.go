@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+type Instance struct {
+	Agent     bool      `json:"agent"`
+	Cpu       float64   `json:"cpu"`
+	Id        string    `json:"id"`
+	Memory    float64   `json:"memory"`
+	PrivateIp string    `json:"private-ip"`
+	Processes int       `json:"processes"`
+	PublicIp  string    `json:"public-ip"`
+	Status    string    `json:"status"`
+	Started   time.Time `json:"started"`
+
+	// Disk is only included when stats are requested
+	Disk *InstanceDisk `json:"disk,omitempty"`
+}
+
+// InstanceDisk is the usage in bytes of the storage docker keeps images and containers on
+type InstanceDisk struct {
+	Used  int64 `json:"used"`
+	Total int64 `json:"total"`
+}
+
+func (d InstanceDisk) PercentUsed() float64 {
+	if d.Total == 0 {
+		return 0
+	}
+
+	return float64(d.Used) / float64(d.Total)
+}
@@ -0,0 +1,10 @@
+package models
+
+// LogScrubRule redacts a field or regular expression from the logs of an app
+type LogScrubRule struct {
+	Name    string `json:"name"`
+	Field   string `json:"field,omitempty"`
+	Pattern string `json:"pattern,omitempty"`
+}
+
+type LogScrubRules []LogScrubRule
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Metric is a series of measurements of a process of an app
+type Metric struct {
+	Process string       `json:"process"`
+	Name    string       `json:"name"`
+	Unit    string       `json:"unit"`
+	Points  MetricPoints `json:"points"`
+}
+
+// MetricPoint is a single measurement of a metric
+type MetricPoint struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// MetricPoints are sorted with the oldest measurement first
+type MetricPoints []MetricPoint
+
+type Metrics []Metric
@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// Monitor periodically requests a URL from the rack and alerts when it stops responding
+type Monitor struct {
+	Id         string        `json:"id"`
+	App        string        `json:"app"`
+	URL        string        `json:"url"`
+	Interval   int           `json:"interval"`
+	MaxLatency int           `json:"max-latency"`
+	Threshold  int           `json:"threshold"`
+	Status     string        `json:"status"`
+	Failures   int           `json:"failures"`
+	Created    time.Time     `json:"created"`
+	Checks     MonitorChecks `json:"checks"`
+}
+
+type Monitors []Monitor
+
+// MonitorCheck is the result of a single request made by a monitor
+type MonitorCheck struct {
+	Time    time.Time `json:"time"`
+	Up      bool      `json:"up"`
+	Code    int       `json:"code"`
+	Latency int       `json:"latency"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// MonitorChecks are sorted with the oldest check first
+type MonitorChecks []MonitorCheck
+
+// Availability returns the percentage of recorded checks that succeeded, or 100 if there are none
+func (cs MonitorChecks) Availability() float64 {
+	if len(cs) == 0 {
+		return 100
+	}
+
+	up := 0
+
+	for _, c := range cs {
+		if c.Up {
+			up++
+		}
+	}
+
+	return float64(up) * 100 / float64(len(cs))
+}
+
+// AverageLatency returns the average latency in milliseconds of the successful checks
+func (cs MonitorChecks) AverageLatency() int {
+	total, count := 0, 0
+
+	for _, c := range cs {
+		if c.Up {
+			total += c.Latency
+			count++
+		}
+	}
+
+	if count == 0 {
+		return 0
+	}
+
+	return total / count
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Notification sends rack events to Slack, a webhook or an SNS topic
+type Notification struct {
+	Id      string    `json:"id"`
+	Url     string    `json:"url"`
+	Type    string    `json:"type"`
+	Target  string    `json:"target"`
+	Events  []string  `json:"events,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+type Notifications []Notification
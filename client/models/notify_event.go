@@ -1,9 +1,9 @@
-package client
+package models
 
 import "time"
 
-//a NotifyEvent is the payload of any webhook services
-//it is serialized to json
+// a NotifyEvent is the payload of any webhook services
+// it is serialized to json
 type NotifyEvent struct {
 	Action    string            `json:"action"`
 	Status    string            `json:"status"`
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Parameters map[string]string
+
+// ParameterSchema describes a stack parameter as it is declared in the stack template
+type ParameterSchema struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Default     string   `json:"default,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Allowed     []string `json:"allowed,omitempty"`
+	Secret      bool     `json:"secret,omitempty"`
+}
+
+type ParameterSchemas []ParameterSchema
+
+// Find returns the schema for the named parameter
+func (ps ParameterSchemas) Find(name string) (*ParameterSchema, bool) {
+	for i := range ps {
+		if ps[i].Name == name {
+			return &ps[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// Validate returns an error if CloudFormation would reject value for this parameter
+func (p ParameterSchema) Validate(value string) error {
+	if len(p.Allowed) > 0 {
+		for _, a := range p.Allowed {
+			if value == a {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("invalid value for %s: %s (allowed: %s)", p.Name, value, strings.Join(p.Allowed, ", "))
+	}
+
+	if p.Type == "Number" {
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("invalid value for %s: %s is not a number", p.Name, value)
+		}
+	}
+
+	return nil
+}
+
+// ParameterChange is a recorded change to a single stack parameter
+type ParameterChange struct {
+	Name    string    `json:"name"`
+	Old     string    `json:"old"`
+	New     string    `json:"new"`
+	User    string    `json:"user"`
+	Created time.Time `json:"created"`
+}
+
+type ParameterChanges []ParameterChange
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Pipeline promotes the builds of an app through a series of racks
+type Pipeline struct {
+	Name    string          `json:"name"`
+	App     string          `json:"app"`
+	Stages  []PipelineStage `json:"stages"`
+	Created time.Time       `json:"created"`
+}
+
+// PipelineStage is a rack a build is promoted on, optionally held until approved
+type PipelineStage struct {
+	Name     string `json:"name"`
+	Rack     string `json:"rack"`
+	Approval bool   `json:"approval"`
+}
+
+type Pipelines []Pipeline
+
+// PipelineRun tracks one build through the stages of a pipeline
+type PipelineRun struct {
+	Id       string             `json:"id"`
+	Pipeline string             `json:"pipeline"`
+	Build    string             `json:"build"`
+	Status   string             `json:"status"`
+	Stages   []PipelineRunStage `json:"stages"`
+	User     string             `json:"user"`
+	Started  time.Time          `json:"started"`
+	Ended    time.Time          `json:"ended"`
+}
+
+// PipelineRunStage is the build and release a run created on the rack of a stage
+type PipelineRunStage struct {
+	Name     string `json:"name"`
+	Rack     string `json:"rack"`
+	Build    string `json:"build"`
+	Release  string `json:"release"`
+	Status   string `json:"status"`
+	Approver string `json:"approver,omitempty"`
+}
+
+type PipelineRuns []PipelineRun
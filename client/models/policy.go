@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// Policy restricts how an app can be changed. An immutable app only changes through releases.
+// A policy without an app applies to every app of the rack.
+type Policy struct {
+	App       string    `json:"app,omitempty"`
+	Immutable bool      `json:"immutable"`
+	Updated   time.Time `json:"updated"`
+	User      string    `json:"user,omitempty"`
+}
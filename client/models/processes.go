@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+type Process struct {
+	Id      string    `json:"id"`
+	App     string    `json:"app"`
+	Command string    `json:"command"`
+	Host    string    `json:"host"`
+	Image   string    `json:"image"`
+	Name    string    `json:"name"`
+	Ports   []string  `json:"ports"`
+	Release string    `json:"release"`
+	Cpu     float64   `json:"cpu"`
+	Memory  float64   `json:"memory"`
+	Started time.Time `json:"started"`
+}
+
+type Processes []Process
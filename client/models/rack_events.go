@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+type RackEvent struct {
+	Action  string            `json:"action"`
+	Type    string            `json:"type"`
+	Status  string            `json:"status"`
+	App     string            `json:"app"`
+	Message string            `json:"message"`
+	Data    map[string]string `json:"data"`
+	Created time.Time         `json:"created"`
+}
+
+type RackEvents []RackEvent
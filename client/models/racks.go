@@ -0,0 +1,12 @@
+package models
+
+type Organization struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type Rack struct {
+	Name         string        `json:"name"`
+	Status       string        `json:"status"`
+	Organization *Organization `json:"organization"`
+}
@@ -0,0 +1,14 @@
+package models
+
+// Mirrors Docker AuthConfiguration
+// https://godoc.org/github.com/fsouza/go-dockerclient#AuthConfiguration
+type Registry struct {
+	Username      string `json:"username,omitempty"`
+	Password      string `json:"password,omitempty"`
+	Email         string `json:"email,omitempty"`
+	ServerAddress string `json:"serveraddress,omitempty"`
+}
+
+// Mirrors Docker AuthConfiguration119
+// https://godoc.org/github.com/fsouza/go-dockerclient#AuthConfigurations119
+type Registries map[string]Registry
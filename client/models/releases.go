@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+type Release struct {
+	Id       string    `json:"id"`
+	App      string    `json:"app"`
+	Build    string    `json:"build"`
+	Env      string    `json:"env"`
+	Manifest string    `json:"manifest"`
+	Created  time.Time `json:"created"`
+
+	// CreatedBy is the user that created the release, if known
+	CreatedBy string `json:"created-by,omitempty"`
+
+	// Overrides are the manifest overrides for the rack applied when the release was promoted
+	Overrides []string `json:"overrides,omitempty"`
+
+	// Processes are the processes a partial promote updated to the release, every process when empty
+	Processes []string `json:"processes,omitempty"`
+}
+
+type Releases []Release
+
+// ReleasePromotion is a recorded deployment of a release
+type ReleasePromotion struct {
+	Release string    `json:"release"`
+	User    string    `json:"user"`
+	Created time.Time `json:"created"`
+
+	// Rack is the rack the release was promoted on and Overrides the manifest overrides for it
+	Rack      string   `json:"rack,omitempty"`
+	Overrides []string `json:"overrides,omitempty"`
+
+	// Processes are the only processes a partial promote updated to the release
+	Processes []string `json:"processes,omitempty"`
+}
+
+type ReleasePromotions []ReleasePromotion
+
+// ReleaseDiff describes what promoting one release in place of another changes
+type ReleaseDiff struct {
+	App  string `json:"app"`
+	From string `json:"from"`
+	To   string `json:"to"`
+
+	Build    *ReleaseDiffBuild  `json:"build,omitempty"`
+	Env      ReleaseDiffEntries `json:"env"`
+	Services ReleaseDiffEntries `json:"services"`
+}
+
+type ReleaseDiffBuild struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type ReleaseDiffEntry struct {
+	Name   string   `json:"name"`
+	Change string   `json:"change"`
+	Fields []string `json:"fields,omitempty"`
+}
+
+type ReleaseDiffEntries []ReleaseDiffEntry
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+type Service struct {
+	Name         string            `json:"name"`
+	Status       string            `json:"status"`
+	StatusReason string            `json:"status-reason"`
+	Type         string            `json:"type"`
+	Exports      map[string]string `json:"exports"`
+	Links        ServiceLinks      `json:"links"`
+	// DEPRECATED: should inject any data in Exports
+	// we only set this on the outgoing response for old clients
+	URL string `json:"url"`
+
+	Outputs    map[string]string `json:"-"`
+	Parameters map[string]string `json:"-"`
+	Tags       map[string]string `json:"-"`
+}
+
+type Services []Service
+
+type ServiceLink struct {
+	App      string    `json:"app"`
+	Variable string    `json:"variable"`
+	Release  string    `json:"release"`
+	User     string    `json:"user"`
+	Created  time.Time `json:"created"`
+}
+
+type ServiceLinks []ServiceLink
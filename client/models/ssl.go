@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type SSL struct {
+	Certificate string    `json:"certificate"`
+	Domain      string    `json:"domain"`
+	Expiration  time.Time `json:"expiration"`
+	Port        int       `json:"port"`
+	Process     string    `json:"process"`
+	Secure      bool      `json:"secure"`
+}
+
+type SSLs []SSL
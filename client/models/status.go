@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// AppStatus is the public status page of an app
+type AppStatus struct {
+	App     string     `json:"app"`
+	Public  bool       `json:"public"`
+	State   string     `json:"state"`
+	Message string     `json:"message"`
+	Updated time.Time  `json:"updated"`
+	Days    StatusDays `json:"days"`
+}
+
+// StatusDay counts the health checks of an app on a single day
+type StatusDay struct {
+	Date   string `json:"date"`
+	Up     int    `json:"up"`
+	Checks int    `json:"checks"`
+}
+
+type StatusDays []StatusDay
+
+// Uptime returns the percentage of health checks that passed, or 100 if there have been none
+func (ds StatusDays) Uptime() float64 {
+	up, checks := 0, 0
+
+	for _, d := range ds {
+		up += d.Up
+		checks += d.Checks
+	}
+
+	if checks == 0 {
+		return 100
+	}
+
+	return float64(up) * 100 / float64(checks)
+}
@@ -0,0 +1,30 @@
+package models
+
+type System struct {
+	Count   int    `json:"count"`
+	Name    string `json:"name"`
+	Region  string `json:"region"`
+	Status  string `json:"status"`
+	Type    string `json:"type"`
+	Version string `json:"version"`
+}
+
+// SystemCheck is the result of a single pre-flight check for a rack update
+type SystemCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+type SystemChecks []SystemCheck
+
+type SystemCapacity struct {
+	ClusterCPU     int64 `json:"cluster-cpu"`
+	ClusterMemory  int64 `json:"cluster-memory"`
+	InstanceCPU    int64 `json:"instance-cpu"`
+	InstanceMemory int64 `json:"instance-memory"`
+	ProcessCount   int64 `json:"process-count"`
+	ProcessCPU     int64 `json:"process-cpu"`
+	ProcessMemory  int64 `json:"process-memory"`
+	ProcessWidth   int64 `json:"process-width"`
+}
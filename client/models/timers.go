@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type Timer struct {
+	Name     string `json:"name"`
+	Process  string `json:"process"`
+	Schedule string `json:"schedule"`
+	Command  string `json:"command"`
+
+	LastRun    time.Time `json:"last-run"`
+	LastStatus string    `json:"last-status"`
+}
+
+type Timers []Timer
+
+type TimerRun struct {
+	Id       string    `json:"id"`
+	Timer    string    `json:"timer"`
+	Status   string    `json:"status"`
+	ExitCode int       `json:"exit-code"`
+	Manual   bool      `json:"manual"`
+	Started  time.Time `json:"started"`
+	Ended    time.Time `json:"ended"`
+}
+
+type TimerRuns []TimerRun
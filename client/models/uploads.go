@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// Upload is a file sent to a rack in chunks
+type Upload struct {
+	Id      string    `json:"id"`
+	Size    int64     `json:"size"`
+	Offset  int64     `json:"offset"`
+	Created time.Time `json:"created"`
+}
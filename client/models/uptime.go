@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+type UptimeReport struct {
+	App       string          `json:"app"`
+	Month     string          `json:"month"`
+	Start     time.Time       `json:"start"`
+	End       time.Time       `json:"end"`
+	Processes []ProcessUptime `json:"processes"`
+}
+
+type ProcessUptime struct {
+	Process      string  `json:"process"`
+	Availability float64 `json:"availability"`
+	Downtime     int     `json:"downtime"`
+	Measured     int     `json:"measured"`
+	Requests     int64   `json:"requests"`
+	Errors       int64   `json:"errors"`
+	SuccessRate  float64 `json:"success-rate"`
+}
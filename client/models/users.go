@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// UserKey is a user that authenticates to the rack with their own key
+type UserKey struct {
+	User    string    `json:"user"`
+	Created time.Time `json:"created"`
+}
+
+type UserKeys []UserKey
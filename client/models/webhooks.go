@@ -0,0 +1,10 @@
+package models
+
+// AppWebhook is what a repository needs to send push webhooks to the rack for an app
+type AppWebhook struct {
+	Repo   string `json:"repo"`
+	Branch string `json:"branch"`
+	Secret string `json:"secret"`
+	GitHub string `json:"github"`
+	GitLab string `json:"gitlab"`
+}
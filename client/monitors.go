@@ -0,0 +1,80 @@
+package client
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/convox/rack/client/models"
+)
+
+// MonitorOptions configure a new monitor. Zero values use the rack defaults.
+type MonitorOptions struct {
+	Interval   time.Duration
+	MaxLatency time.Duration
+	Threshold  int
+}
+
+// GetMonitors returns the monitors of an app
+func (c *Client) GetMonitors(app string) (models.Monitors, error) {
+	var monitors models.Monitors
+
+	err := c.Get(fmt.Sprintf("/apps/%s/monitors", app), &monitors)
+	if err != nil {
+		return nil, err
+	}
+
+	return monitors, nil
+}
+
+// GetMonitor returns a monitor of an app along with its recent checks
+func (c *Client) GetMonitor(app, id string) (*models.Monitor, error) {
+	var monitor models.Monitor
+
+	err := c.Get(fmt.Sprintf("/apps/%s/monitors/%s", app, id), &monitor)
+	if err != nil {
+		return nil, err
+	}
+
+	return &monitor, nil
+}
+
+// CreateMonitor starts checking url from the rack on behalf of app
+func (c *Client) CreateMonitor(app, url string, opts MonitorOptions) (*models.Monitor, error) {
+	var monitor models.Monitor
+
+	params := Params{
+		"url": url,
+	}
+
+	if opts.Interval > 0 {
+		params["interval"] = strconv.Itoa(int(opts.Interval / time.Second))
+	}
+
+	if opts.MaxLatency > 0 {
+		params["max-latency"] = strconv.Itoa(int(opts.MaxLatency / time.Millisecond))
+	}
+
+	if opts.Threshold > 0 {
+		params["threshold"] = strconv.Itoa(opts.Threshold)
+	}
+
+	err := c.Post(fmt.Sprintf("/apps/%s/monitors", app), params, &monitor)
+	if err != nil {
+		return nil, err
+	}
+
+	return &monitor, nil
+}
+
+// DeleteMonitor stops a monitor and discards its history
+func (c *Client) DeleteMonitor(app, id string) (*models.Monitor, error) {
+	var monitor models.Monitor
+
+	err := c.Delete(fmt.Sprintf("/apps/%s/monitors/%s", app, id), &monitor)
+	if err != nil {
+		return nil, err
+	}
+
+	return &monitor, nil
+}
@@ -0,0 +1,48 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/convox/rack/client/models"
+)
+
+// GetNotifications returns the notifications of the rack
+func (c *Client) GetNotifications() (models.Notifications, error) {
+	var notifications models.Notifications
+
+	err := c.Get("/notifications", &notifications)
+	if err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// AddNotification sends rack events to a slack://, webhook:// or sns:// url, optionally limited to
+// some events
+func (c *Client) AddNotification(url string, events []string) (*models.Notification, error) {
+	var n models.Notification
+
+	params := Params{
+		"url": url,
+	}
+
+	if len(events) > 0 {
+		params["events"] = strings.Join(events, ",")
+	}
+
+	err := c.Post("/notifications", params, &n)
+	if err != nil {
+		return nil, err
+	}
+
+	return &n, nil
+}
+
+// RemoveNotification stops sending rack events to a notification
+func (c *Client) RemoveNotification(id string) error {
+	var success interface{}
+
+	return c.Delete(fmt.Sprintf("/notifications/%s", id), &success)
+}
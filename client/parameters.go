@@ -1,11 +1,13 @@
 package client
 
-import "fmt"
+import (
+	"fmt"
 
-type Parameters map[string]string
+	"github.com/convox/rack/client/models"
+)
 
-func (c *Client) ListParameters(app string) (Parameters, error) {
-	var formation Parameters
+func (c *Client) ListParameters(app string) (models.Parameters, error) {
+	var formation models.Parameters
 
 	err := c.Get(fmt.Sprintf("/apps/%s/parameters", app), &formation)
 
@@ -16,7 +18,43 @@ func (c *Client) ListParameters(app string) (Parameters, error) {
 	return formation, nil
 }
 
+// GetParameterSchema returns the type, description and allowed values of each app parameter
+func (c *Client) GetParameterSchema(app string) (models.ParameterSchemas, error) {
+	var schema models.ParameterSchemas
+
+	err := c.Get(fmt.Sprintf("/apps/%s/parameters/schema", app), &schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+// GetParameterHistory returns the recorded parameter changes of an app, latest first
+func (c *Client) GetParameterHistory(app string) (models.ParameterChanges, error) {
+	var history models.ParameterChanges
+
+	err := c.Get(fmt.Sprintf("/apps/%s/parameters/history", app), &history)
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
 func (c *Client) SetParameters(app string, params map[string]string) error {
 	var success interface{}
 	return c.Post(fmt.Sprintf("/apps/%s/parameters", app), params, &success)
 }
+
+// PreviewParameters returns the stack changes that setting params would make without applying them
+func (c *Client) PreviewParameters(app string, params map[string]string) (models.StackChanges, error) {
+	var changes models.StackChanges
+
+	err := c.Post(fmt.Sprintf("/apps/%s/parameters/preview", app), params, &changes)
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
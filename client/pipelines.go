@@ -0,0 +1,101 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/convox/rack/client/models"
+)
+
+// GetPipelines returns the pipelines kept on the rack
+func (c *Client) GetPipelines() (models.Pipelines, error) {
+	var pipelines models.Pipelines
+
+	err := c.Get("/pipelines", &pipelines)
+	if err != nil {
+		return nil, err
+	}
+
+	return pipelines, nil
+}
+
+// CreatePipeline saves a pipeline for an app with stages written as name:rack and the comma
+// separated stages that require approval
+func (c *Client) CreatePipeline(name, app, stages, approval string) (*models.Pipeline, error) {
+	var p models.Pipeline
+
+	params := Params{
+		"name":             name,
+		"app":              app,
+		"stages":           stages,
+		"require-approval": approval,
+	}
+
+	err := c.Post("/pipelines", params, &p)
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// DeletePipeline removes a pipeline and its runs
+func (c *Client) DeletePipeline(name string) error {
+	var success interface{}
+
+	return c.Delete(fmt.Sprintf("/pipelines/%s", name), &success)
+}
+
+// GetPipelineRuns returns the runs of a pipeline, latest first
+func (c *Client) GetPipelineRuns(name string) (models.PipelineRuns, error) {
+	var runs models.PipelineRuns
+
+	err := c.Get(fmt.Sprintf("/pipelines/%s/runs", name), &runs)
+	if err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+// CreatePipelineRun starts tracking a build of the first stage through a pipeline
+func (c *Client) CreatePipelineRun(name, build string) (*models.PipelineRun, error) {
+	var run models.PipelineRun
+
+	err := c.Post(fmt.Sprintf("/pipelines/%s/runs", name), Params{"build": build}, &run)
+	if err != nil {
+		return nil, err
+	}
+
+	return &run, nil
+}
+
+// ApprovePipelineRun lets the stage a run is waiting at start
+func (c *Client) ApprovePipelineRun(name, id string) (*models.PipelineRun, error) {
+	var run models.PipelineRun
+
+	err := c.Post(fmt.Sprintf("/pipelines/%s/runs/%s/approve", name, id), Params{}, &run)
+	if err != nil {
+		return nil, err
+	}
+
+	return &run, nil
+}
+
+// UpdatePipelineStage reports the progress of a stage of a run. The rack refuses to start a
+// stage that requires approval before the run is approved.
+func (c *Client) UpdatePipelineStage(name, id, stage, status, build, release string) (*models.PipelineRun, error) {
+	var run models.PipelineRun
+
+	params := Params{
+		"status":  status,
+		"build":   build,
+		"release": release,
+	}
+
+	err := c.Put(fmt.Sprintf("/pipelines/%s/runs/%s/stages/%s", name, id, stage), params, &run)
+	if err != nil {
+		return nil, err
+	}
+
+	return &run, nil
+}
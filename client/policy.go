@@ -0,0 +1,43 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/convox/rack/client/models"
+)
+
+// GetPolicy returns the policy of an app, or of the rack when app is empty
+func (c *Client) GetPolicy(app string) (*models.Policy, error) {
+	var p models.Policy
+
+	err := c.Get(policyPath(app), &p)
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// SetPolicy changes the policy of an app, or of the rack when app is empty
+func (c *Client) SetPolicy(app string, immutable bool) (*models.Policy, error) {
+	var p models.Policy
+
+	params := Params{
+		"immutable": fmt.Sprintf("%t", immutable),
+	}
+
+	err := c.Post(policyPath(app), params, &p)
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+func policyPath(app string) string {
+	if app == "" {
+		return "/system/policy"
+	}
+
+	return fmt.Sprintf("/apps/%s/policy", app)
+}
@@ -3,32 +3,15 @@ package client
 import (
 	"fmt"
 	"io"
-	"os"
+	"net/url"
 	"strconv"
 	"strings"
-	"time"
 
-	"golang.org/x/crypto/ssh/terminal"
+	"github.com/convox/rack/client/models"
 )
 
-type Process struct {
-	Id      string    `json:"id"`
-	App     string    `json:"app"`
-	Command string    `json:"command"`
-	Host    string    `json:"host"`
-	Image   string    `json:"image"`
-	Name    string    `json:"name"`
-	Ports   []string  `json:"ports"`
-	Release string    `json:"release"`
-	Cpu     float64   `json:"cpu"`
-	Memory  float64   `json:"memory"`
-	Started time.Time `json:"started"`
-}
-
-type Processes []Process
-
-func (c *Client) GetProcesses(app string, stats bool) (Processes, error) {
-	var processes Processes
+func (c *Client) GetProcesses(app string, stats bool) (models.Processes, error) {
+	var processes models.Processes
 
 	err := c.Get(fmt.Sprintf("/apps/%s/processes?stats=%t", app, stats), &processes)
 
@@ -39,8 +22,8 @@ func (c *Client) GetProcesses(app string, stats bool) (Processes, error) {
 	return processes, nil
 }
 
-func (c *Client) GetProcess(app, id string) (*Process, error) {
-	var process Process
+func (c *Client) GetProcess(app, id string) (*models.Process, error) {
+	var process models.Process
 
 	err := c.Get(fmt.Sprintf("/apps/%s/processes/%s", app, id), &process)
 
@@ -78,6 +61,35 @@ func (c *Client) ExecProcessAttached(app, pid, command string, in io.Reader, out
 	return code, nil
 }
 
+// DebugProcessAttached starts a container from image that shares the network and processes of
+// a running process and attaches to it. An empty command runs the default shell of the image.
+func (c *Client) DebugProcessAttached(app, pid, image, command string, in io.Reader, out io.WriteCloser, height, width int) (int, error) {
+	r, w := io.Pipe()
+
+	defer r.Close()
+	defer w.Close()
+
+	ch := make(chan int)
+
+	go copyWithExit(out, r, ch)
+
+	headers := map[string]string{
+		"Command": command,
+		"Image":   image,
+		"Height":  strconv.Itoa(height),
+		"Width":   strconv.Itoa(width),
+	}
+
+	err := c.Stream(fmt.Sprintf("/apps/%s/processes/%s/debug", app, pid), headers, in, w)
+	if err != nil {
+		return 0, err
+	}
+
+	code := <-ch
+
+	return code, nil
+}
+
 func (c *Client) RunProcessAttached(app, process, command, release string, height, width int, in io.Reader, out io.WriteCloser) (int, error) {
 	r, w := io.Pipe()
 
@@ -117,8 +129,8 @@ func (c *Client) RunProcessDetached(app, process, command, release string) error
 	return c.Post(fmt.Sprintf("/apps/%s/processes/%s/run", app, process), params, &success)
 }
 
-func (c *Client) StopProcess(app, id string) (*Process, error) {
-	var process Process
+func (c *Client) StopProcess(app, id string) (*models.Process, error) {
+	var process models.Process
 
 	err := c.Delete(fmt.Sprintf("/apps/%s/processes/%s", app, id), &process)
 
@@ -131,7 +143,6 @@ func (c *Client) StopProcess(app, id string) (*Process, error) {
 
 func copyWithExit(w io.Writer, r io.Reader, ch chan int) {
 	buf := make([]byte, 1024)
-	isTerminalRaw := false
 
 	for {
 		n, err := r.Read(buf)
@@ -145,11 +156,6 @@ func copyWithExit(w io.Writer, r io.Reader, ch chan int) {
 			break
 		}
 
-		if !isTerminalRaw {
-			terminal.MakeRaw(int(os.Stdin.Fd()))
-			isTerminalRaw = true
-		}
-
 		if s := string(buf[0:n]); strings.HasPrefix(s, StatusCodePrefix) {
 			code, _ := strconv.Atoi(strings.TrimSpace(s[37:]))
 			ch <- code
@@ -163,3 +169,31 @@ func copyWithExit(w io.Writer, r io.Reader, ch chan int) {
 		}
 	}
 }
+
+// ProfileProcess records a profile of a running process for duration and writes it to w. It
+// returns the format of the profile, pprof or jfr.
+func (c *Client) ProfileProcess(app, pid, typ, duration string, w io.Writer) (string, error) {
+	params := url.Values{"type": {typ}, "duration": {duration}}
+
+	req, err := c.request("GET", fmt.Sprintf("/apps/%s/processes/%s/profile?%s", app, pid, params.Encode()), nil)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := c.client().Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	defer res.Body.Close()
+
+	if err := responseError(res); err != nil {
+		return "", err
+	}
+
+	if _, err := io.Copy(w, res.Body); err != nil {
+		return "", err
+	}
+
+	return res.Header.Get("Profile-Format"), nil
+}
@@ -0,0 +1,25 @@
+package client
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyWithExit(t *testing.T) {
+	out := &bytes.Buffer{}
+	ch := make(chan int, 1)
+
+	go copyWithExit(out, strings.NewReader(StatusCodePrefix+"3\n"), ch)
+
+	assert.Equal(t, 3, <-ch)
+
+	out.Reset()
+
+	go copyWithExit(out, strings.NewReader("no status"), ch)
+
+	assert.Equal(t, 1, <-ch)
+	assert.Equal(t, "no status", out.String())
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/convox/rack/client/models"
+)
+
+// RackEventOptions narrow the events returned by GetRackEvents. Zero values are not filtered on.
+type RackEventOptions struct {
+	App  string
+	Type string
+
+	// Since is a duration like 24h or a number of days like 7d
+	Since string
+
+	Limit int
+}
+
+// GetRackEvents returns the stored events of the rack, newest first
+func (c *Client) GetRackEvents(opts RackEventOptions) (models.RackEvents, error) {
+	var events models.RackEvents
+
+	q := url.Values{}
+
+	if opts.App != "" {
+		q.Set("app", opts.App)
+	}
+
+	if opts.Type != "" {
+		q.Set("type", opts.Type)
+	}
+
+	if opts.Since != "" {
+		q.Set("since", opts.Since)
+	}
+
+	if opts.Limit > 0 {
+		q.Set("limit", strconv.Itoa(opts.Limit))
+	}
+
+	err := c.Get(fmt.Sprintf("/system/events?%s", q.Encode()), &events)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
@@ -4,20 +4,11 @@ import (
 	"fmt"
 	"io"
 	"time"
-)
-
-type Organization struct {
-	Id   string `json:"id"`
-	Name string `json:"name"`
-}
 
-type Rack struct {
-	Name         string        `json:"name"`
-	Status       string        `json:"status"`
-	Organization *Organization `json:"organization"`
-}
+	"github.com/convox/rack/client/models"
+)
 
-func (c *Client) Racks() (racks []Rack, err error) {
+func (c *Client) Racks() (racks []models.Rack, err error) {
 	err = c.Get("/racks", &racks)
 	return racks, err
 }
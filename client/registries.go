@@ -1,21 +1,12 @@
 package client
 
-import "fmt"
-
-// Mirrors Docker AuthConfiguration
-// https://godoc.org/github.com/fsouza/go-dockerclient#AuthConfiguration
-type Registry struct {
-	Username      string `json:"username,omitempty"`
-	Password      string `json:"password,omitempty"`
-	Email         string `json:"email,omitempty"`
-	ServerAddress string `json:"serveraddress,omitempty"`
-}
+import (
+	"fmt"
 
-// Mirrors Docker AuthConfiguration119
-//https://godoc.org/github.com/fsouza/go-dockerclient#AuthConfigurations119
-type Registries map[string]Registry
+	"github.com/convox/rack/client/models"
+)
 
-func (c *Client) AddRegistry(server, username, password, email string) (*Registry, error) {
+func (c *Client) AddRegistry(server, username, password, email string) (*models.Registry, error) {
 	params := Params{
 		"username":      username,
 		"password":      password,
@@ -23,7 +14,7 @@ func (c *Client) AddRegistry(server, username, password, email string) (*Registr
 		"serveraddress": server,
 	}
 
-	var registry Registry
+	var registry models.Registry
 
 	err := c.Post("/registries", params, &registry)
 
@@ -34,8 +25,8 @@ func (c *Client) AddRegistry(server, username, password, email string) (*Registr
 	return &registry, nil
 }
 
-func (c *Client) RemoveRegistry(server string) (*Registry, error) {
-	var registry Registry
+func (c *Client) RemoveRegistry(server string) (*models.Registry, error) {
+	var registry models.Registry
 
 	err := c.Delete(fmt.Sprintf("/registries?server=%s", server), &registry)
 
@@ -46,8 +37,8 @@ func (c *Client) RemoveRegistry(server string) (*Registry, error) {
 	return &registry, nil
 }
 
-func (c *Client) ListRegistries() (*Registries, error) {
-	registries := Registries{}
+func (c *Client) ListRegistries() (*models.Registries, error) {
+	registries := models.Registries{}
 	err := c.Get("/registries", &registries)
 
 	if err != nil {
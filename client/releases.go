@@ -3,22 +3,14 @@ package client
 import (
 	"fmt"
 	"io"
+	"strings"
 	"time"
-)
-
-type Release struct {
-	Id       string    `json:"id"`
-	App      string    `json:"app"`
-	Build    string    `json:"build"`
-	Env      string    `json:"env"`
-	Manifest string    `json:"manifest"`
-	Created  time.Time `json:"created"`
-}
 
-type Releases []Release
+	"github.com/convox/rack/client/models"
+)
 
-func (c *Client) GetReleases(app string) (Releases, error) {
-	var releases Releases
+func (c *Client) GetReleases(app string) (models.Releases, error) {
+	var releases models.Releases
 
 	err := c.Get(fmt.Sprintf("/apps/%s/releases", app), &releases)
 
@@ -29,8 +21,8 @@ func (c *Client) GetReleases(app string) (Releases, error) {
 	return releases, nil
 }
 
-func (c *Client) GetRelease(app, id string) (*Release, error) {
-	var release Release
+func (c *Client) GetRelease(app, id string) (*models.Release, error) {
+	var release models.Release
 
 	err := c.Get(fmt.Sprintf("/apps/%s/releases/%s", app, id), &release)
 
@@ -41,8 +33,21 @@ func (c *Client) GetRelease(app, id string) (*Release, error) {
 	return &release, nil
 }
 
-func (c *Client) PromoteRelease(app, id string) (*Release, error) {
-	var release Release
+// DiffReleases returns what promoting release to in place of release from would change
+func (c *Client) DiffReleases(app, from, to string) (*models.ReleaseDiff, error) {
+	var diff models.ReleaseDiff
+
+	err := c.Get(fmt.Sprintf("/apps/%s/releases/%s/diff/%s", app, from, to), &diff)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &diff, nil
+}
+
+func (c *Client) PromoteRelease(app, id string) (*models.Release, error) {
+	var release models.Release
 
 	err := c.Post(fmt.Sprintf("/apps/%s/releases/%s/promote", app, id), nil, &release)
 
@@ -53,6 +58,80 @@ func (c *Client) PromoteRelease(app, id string) (*Release, error) {
 	return &release, nil
 }
 
+// PromoteReleaseProcesses promotes a release for only some processes, leaving the other processes
+// on the releases they run
+func (c *Client) PromoteReleaseProcesses(app, id string, processes []string) (*models.Release, error) {
+	var release models.Release
+
+	params := Params{
+		"processes": strings.Join(processes, ","),
+	}
+
+	err := c.Post(fmt.Sprintf("/apps/%s/releases/%s/promote", app, id), params, &release)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &release, nil
+}
+
+// PreviewPromoteRelease returns the stack changes that promoting a release would make without applying them.
+// The promote is limited to processes when any are given.
+func (c *Client) PreviewPromoteRelease(app, id string, processes []string) (models.StackChanges, error) {
+	var changes models.StackChanges
+
+	var params Params
+
+	if len(processes) > 0 {
+		params = Params{"processes": strings.Join(processes, ",")}
+	}
+
+	err := c.Post(fmt.Sprintf("/apps/%s/releases/%s/promote/preview", app, id), params, &changes)
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// PromoteReleaseWait promotes a release and returns once its processes are healthy. A release
+// that does not become healthy within timeout is rolled back and returned as an error. The promote
+// is limited to processes when any are given.
+func (c *Client) PromoteReleaseWait(app, id string, timeout time.Duration, processes []string) (*models.Release, error) {
+	var release models.Release
+
+	params := Params{
+		"wait":    "true",
+		"timeout": timeout.String(),
+	}
+
+	if len(processes) > 0 {
+		params["processes"] = strings.Join(processes, ",")
+	}
+
+	err := c.Post(fmt.Sprintf("/apps/%s/releases/%s/promote", app, id), params, &release)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &release, nil
+}
+
+// GetReleasePromotions returns the recorded deployments of a release, latest first
+func (c *Client) GetReleasePromotions(app, id string) (models.ReleasePromotions, error) {
+	var promotions models.ReleasePromotions
+
+	err := c.Get(fmt.Sprintf("/apps/%s/releases/%s/promotions", app, id), &promotions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return promotions, nil
+}
+
 func (c *Client) StreamReleaseLogs(app, id string, output io.WriteCloser) error {
 	return c.Stream(fmt.Sprintf("/apps/%s/releases/%s/logs", app, id), nil, nil, output)
 }
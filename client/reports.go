@@ -0,0 +1,27 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/convox/rack/client/models"
+)
+
+// GetUptimeReport returns the availability of the balanced processes of an app for a month
+// like 2016-10, or for the current month when month is empty
+func (c *Client) GetUptimeReport(app, month string) (*models.UptimeReport, error) {
+	var report models.UptimeReport
+
+	q := url.Values{}
+
+	if month != "" {
+		q.Set("month", month)
+	}
+
+	err := c.Get(fmt.Sprintf("/apps/%s/reports/uptime?%s", app, q.Encode()), &report)
+	if err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
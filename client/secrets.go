@@ -0,0 +1,44 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GetSecrets returns the names of the secrets of an app
+func (c *Client) GetSecrets(app string) ([]string, error) {
+	var secrets []string
+
+	err := c.Get(fmt.Sprintf("/apps/%s/secrets", app), &secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	return secrets, nil
+}
+
+// GetSecret returns the value of a secret of an app
+func (c *Client) GetSecret(app, name string) (string, error) {
+	var value string
+
+	err := c.Get(fmt.Sprintf("/apps/%s/secrets/%s", app, name), &value)
+	if err != nil {
+		return "", err
+	}
+
+	return value, nil
+}
+
+// SetSecret stores a secret of an app, encrypted with the rack key
+func (c *Client) SetSecret(app, name, value string) error {
+	var success interface{}
+
+	return c.PostBody(fmt.Sprintf("/apps/%s/secrets/%s", app, name), strings.NewReader(value), &success)
+}
+
+// DeleteSecret removes a secret of an app
+func (c *Client) DeleteSecret(app, name string) error {
+	var success interface{}
+
+	return c.Delete(fmt.Sprintf("/apps/%s/secrets/%s", app, name), &success)
+}
@@ -1,26 +1,13 @@
 package client
 
-import "fmt"
-
-type Service struct {
-	Name         string            `json:"name"`
-	Status       string            `json:"status"`
-	StatusReason string            `json:"status-reason"`
-	Type         string            `json:"type"`
-	Exports      map[string]string `json:"exports"`
-	// DEPRECATED: should inject any data in Exports
-	// we only set this on the outgoing response for old clients
-	URL string `json:"url"`
-
-	Outputs    map[string]string `json:"-"`
-	Parameters map[string]string `json:"-"`
-	Tags       map[string]string `json:"-"`
-}
+import (
+	"fmt"
 
-type Services []Service
+	"github.com/convox/rack/client/models"
+)
 
-func (c *Client) GetServices() (Services, error) {
-	var services Services
+func (c *Client) GetServices() (models.Services, error) {
+	var services models.Services
 
 	err := c.Get("/services", &services)
 
@@ -31,10 +18,10 @@ func (c *Client) GetServices() (Services, error) {
 	return services, nil
 }
 
-func (c *Client) CreateService(kind string, options map[string]string) (*Service, error) {
+func (c *Client) CreateService(kind string, options map[string]string) (*models.Service, error) {
 	params := Params(options)
 	params["type"] = kind
-	var service Service
+	var service models.Service
 
 	err := c.Post("/services", params, &service)
 
@@ -45,8 +32,8 @@ func (c *Client) CreateService(kind string, options map[string]string) (*Service
 	return &service, nil
 }
 
-func (c *Client) GetService(name string) (*Service, error) {
-	var service Service
+func (c *Client) GetService(name string) (*models.Service, error) {
+	var service models.Service
 
 	err := c.Get(fmt.Sprintf("/services/%s", name), &service)
 
@@ -57,8 +44,8 @@ func (c *Client) GetService(name string) (*Service, error) {
 	return &service, nil
 }
 
-func (c *Client) DeleteService(name string) (*Service, error) {
-	var service Service
+func (c *Client) DeleteService(name string) (*models.Service, error) {
+	var service models.Service
 
 	err := c.Delete(fmt.Sprintf("/services/%s", name), &service)
 
@@ -69,9 +56,9 @@ func (c *Client) DeleteService(name string) (*Service, error) {
 	return &service, nil
 }
 
-func (c *Client) UpdateService(name string, options map[string]string) (*Service, error) {
+func (c *Client) UpdateService(name string, options map[string]string) (*models.Service, error) {
 	params := Params(options)
-	var service Service
+	var service models.Service
 
 	err := c.Put(fmt.Sprintf("/services/%s", name), params, &service)
 
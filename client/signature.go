@@ -0,0 +1,134 @@
+package client
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// signatureScheme prefixes the Authorization header of a signed request
+	signatureScheme = "Convox-HMAC-SHA256"
+
+	// signatureMaxBody is the largest body that is hashed, larger bodies are sent unsigned
+	signatureMaxBody = 10 * 1024 * 1024
+
+	// unsignedPayload is sent as the body hash of streamed uploads
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+// AuthSchemes asks the rack how clients may authenticate without sending
+// credentials. Racks that do not say only accept basic auth.
+func (c *Client) AuthSchemes() ([]string, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("https://%s/auth", c.Host), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Version", c.Version)
+
+	// older user keys can not sign so the rack answers for the key in use
+	if c.KeyUser != "" {
+		req.Header.Add("Key-User", c.KeyUser)
+	}
+
+	res, err := c.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	res.Body.Close()
+
+	schemes := []string{}
+
+	for _, s := range strings.Split(res.Header.Get("Auth-Schemes"), ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			schemes = append(schemes, s)
+		}
+	}
+
+	if len(schemes) == 0 {
+		schemes = []string{"basic"}
+	}
+
+	return schemes, nil
+}
+
+// payloadHash returns the hex sha256 of a body that is already in memory along
+// with a reader to send in its place. Streamed bodies are not hashed.
+func payloadHash(body io.Reader) (string, io.Reader, error) {
+	switch body.(type) {
+	case nil:
+		return hashBytes(nil), nil, nil
+	case *bytes.Buffer, *bytes.Reader, *strings.Reader:
+		data, err := ioutil.ReadAll(body)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if len(data) > signatureMaxBody {
+			return unsignedPayload, bytes.NewReader(data), nil
+		}
+
+		return hashBytes(data), bytes.NewReader(data), nil
+	default:
+		return unsignedPayload, body, nil
+	}
+}
+
+// sign adds the headers of a request signature in place of the password
+func (c *Client) sign(h http.Header, method, uri, hash string) {
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+
+	key := c.Password
+
+	// the rack keeps a secret derived from a user key to check its signatures, never the key itself
+	if c.KeyUser != "" {
+		key = userKeySigningSecret(c.Password)
+		h.Set("Key-User", c.KeyUser)
+	}
+
+	h.Set("Authorization", fmt.Sprintf("%s Signature=%s", signatureScheme, requestSignature(key, method, uri, ts, hash)))
+	h.Set("Content-Sha256", hash)
+	h.Set("Timestamp", ts)
+}
+
+// authUser is the user name sent with basic authentication
+func (c *Client) authUser() string {
+	if c.KeyUser != "" {
+		return c.KeyUser
+	}
+
+	return "convox"
+}
+
+// userKeySigningSecret must match the secret the rack stores for a user key
+func userKeySigningSecret(key string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte("convox-user-key-signing"))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// requestSignature must match the signature computed by the rack
+func requestSignature(key, method, uri, timestamp, hash string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%s", method, uri, timestamp, hash)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
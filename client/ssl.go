@@ -2,22 +2,12 @@ package client
 
 import (
 	"fmt"
-	"time"
-)
-
-type SSL struct {
-	Certificate string    `json:"certificate"`
-	Domain      string    `json:"domain"`
-	Expiration  time.Time `json:"expiration"`
-	Port        int       `json:"port"`
-	Process     string    `json:"process"`
-	Secure      bool      `json:"secure"`
-}
 
-type SSLs []SSL
+	"github.com/convox/rack/client/models"
+)
 
-func (c *Client) ListSSL(app string) (*SSLs, error) {
-	var ssls SSLs
+func (c *Client) ListSSL(app string) (*models.SSLs, error) {
+	var ssls models.SSLs
 
 	err := c.Get(fmt.Sprintf("/apps/%s/ssl", app), &ssls)
 
@@ -28,12 +18,12 @@ func (c *Client) ListSSL(app string) (*SSLs, error) {
 	return &ssls, nil
 }
 
-func (c *Client) UpdateSSL(app, process, port, id string) (*SSL, error) {
+func (c *Client) UpdateSSL(app, process, port, id string) (*models.SSL, error) {
 	params := Params{
 		"id": id,
 	}
 
-	var ssl SSL
+	var ssl models.SSL
 
 	err := c.Put(fmt.Sprintf("/apps/%s/ssl/%s/%s", app, process, port), params, &ssl)
 
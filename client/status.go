@@ -0,0 +1,40 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/convox/rack/client/models"
+)
+
+// GetAppStatus returns the status page of an app
+func (c *Client) GetAppStatus(app string) (*models.AppStatus, error) {
+	var status models.AppStatus
+
+	err := c.Get(fmt.Sprintf("/apps/%s/status", app), &status)
+	if err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
+
+// SetAppStatus sets the state shown on the status page of an app along with a message explaining it
+func (c *Client) SetAppStatus(app, state, message string) (*models.AppStatus, error) {
+	return c.updateAppStatus(app, Params{"state": state, "message": message})
+}
+
+// SetAppStatusPublic publishes or hides the status page of an app
+func (c *Client) SetAppStatusPublic(app string, public bool) (*models.AppStatus, error) {
+	return c.updateAppStatus(app, Params{"public": fmt.Sprintf("%t", public)})
+}
+
+func (c *Client) updateAppStatus(app string, params Params) (*models.AppStatus, error) {
+	var status models.AppStatus
+
+	err := c.Post(fmt.Sprintf("/apps/%s/status", app), params, &status)
+	if err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
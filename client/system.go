@@ -1,26 +1,15 @@
 package client
 
-import "strconv"
-
-type System struct {
-	Count   int    `json:"count"`
-	Name    string `json:"name"`
-	Region  string `json:"region"`
-	Status  string `json:"status"`
-	Type    string `json:"type"`
-	Version string `json:"version"`
-}
+import (
+	"fmt"
+	"net/url"
+	"strconv"
 
-type SystemCapacity struct {
-	ClusterMemory  int64 `json:"cluster-memory"`
-	InstanceMemory int64 `json:"instance-memory"`
-	ProcessCount   int64 `json:"process-count"`
-	ProcessMemory  int64 `json:"process-memory"`
-	ProcessWidth   int64 `json:"process-width"`
-}
+	"github.com/convox/rack/client/models"
+)
 
-func (c *Client) GetSystem() (*System, error) {
-	var system System
+func (c *Client) GetSystem() (*models.System, error) {
+	var system models.System
 
 	err := c.Get("/system", &system)
 
@@ -48,8 +37,8 @@ func (c *Client) GetSystem() (*System, error) {
 	return &system, nil
 }
 
-func (c *Client) GetSystemCapacity() (*SystemCapacity, error) {
-	var capacity SystemCapacity
+func (c *Client) GetSystemCapacity() (*models.SystemCapacity, error) {
+	var capacity models.SystemCapacity
 
 	err := c.Get("/system/capacity", &capacity)
 
@@ -60,8 +49,32 @@ func (c *Client) GetSystemCapacity() (*SystemCapacity, error) {
 	return &capacity, nil
 }
 
-func (c *Client) GetSystemReleases() (Releases, error) {
-	var releases Releases
+// GetSystemChecks runs the rack's pre-flight checks for updating to version
+func (c *Client) GetSystemChecks(version string) (models.SystemChecks, error) {
+	var checks models.SystemChecks
+
+	err := c.Get(fmt.Sprintf("/system/checks?version=%s", url.QueryEscape(version)), &checks)
+	if err != nil {
+		return nil, err
+	}
+
+	return checks, nil
+}
+
+// GetSystemHealth runs a deep health check of the rack
+func (c *Client) GetSystemHealth() (models.SystemChecks, error) {
+	var checks models.SystemChecks
+
+	err := c.Get("/system/health", &checks)
+	if err != nil {
+		return nil, err
+	}
+
+	return checks, nil
+}
+
+func (c *Client) GetSystemReleases() (models.Releases, error) {
+	var releases models.Releases
 
 	err := c.Get("/system/releases", &releases)
 
@@ -72,8 +85,10 @@ func (c *Client) GetSystemReleases() (Releases, error) {
 	return releases, nil
 }
 
-func (c *Client) UpdateSystem(version string) (*System, error) {
-	var system System
+// UpdateSystem updates the rack to version. Unless force is set the rack refuses
+// updates that fail its pre-flight checks.
+func (c *Client) UpdateSystem(version string, force bool) (*models.System, error) {
+	var system models.System
 
 	err := c.Get("/system", &system)
 
@@ -89,6 +104,10 @@ func (c *Client) UpdateSystem(version string) (*System, error) {
 		"version": version,
 	}
 
+	if force {
+		params["force"] = "true"
+	}
+
 	err = c.Put("/system", params, &system)
 
 	if err != nil {
@@ -98,7 +117,7 @@ func (c *Client) UpdateSystem(version string) (*System, error) {
 	return &system, nil
 }
 
-func (c *Client) UpdateSystemOriginal(version string) (*System, error) {
+func (c *Client) UpdateSystemOriginal(version string) (*models.System, error) {
 	err := c.Post("/system", map[string]string{"version": version}, nil)
 
 	if err != nil {
@@ -108,8 +127,8 @@ func (c *Client) UpdateSystemOriginal(version string) (*System, error) {
 	return c.GetSystem()
 }
 
-func (c *Client) ScaleSystem(count int, typ string) (*System, error) {
-	var system System
+func (c *Client) ScaleSystem(count int, typ string) (*models.System, error) {
+	var system models.System
 
 	params := Params{}
 
@@ -4,13 +4,14 @@ import (
 	"net/url"
 	"testing"
 
+	"github.com/convox/rack/client/models"
 	"github.com/convox/rack/test"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestGetSystem(t *testing.T) {
 	ts := testServer(t,
-		test.Http{Method: "GET", Path: "/system", Code: 200, Response: System{
+		test.Http{Method: "GET", Path: "/system", Code: 200, Response: models.System{
 			Count:   1,
 			Name:    "system",
 			Status:  "running",
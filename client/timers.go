@@ -0,0 +1,43 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/convox/rack/client/models"
+)
+
+// GetTimers returns the timers defined in an app's active release
+func (c *Client) GetTimers(app string) (models.Timers, error) {
+	var timers models.Timers
+
+	err := c.Get(fmt.Sprintf("/apps/%s/timers", app), &timers)
+	if err != nil {
+		return nil, err
+	}
+
+	return timers, nil
+}
+
+// GetTimerRuns returns the run history of a timer, latest first
+func (c *Client) GetTimerRuns(app, name string) (models.TimerRuns, error) {
+	var runs models.TimerRuns
+
+	err := c.Get(fmt.Sprintf("/apps/%s/timers/%s/runs", app, name), &runs)
+	if err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+// RunTimer triggers a timer immediately
+func (c *Client) RunTimer(app, name string) (*models.TimerRun, error) {
+	var run models.TimerRun
+
+	err := c.Post(fmt.Sprintf("/apps/%s/timers/%s/runs", app, name), Params{}, &run)
+	if err != nil {
+		return nil, err
+	}
+
+	return &run, nil
+}
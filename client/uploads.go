@@ -0,0 +1,45 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/convox/rack/client/models"
+)
+
+// CreateUpload starts a chunked upload of size bytes
+func (c *Client) CreateUpload(size int64) (*models.Upload, error) {
+	var upload models.Upload
+
+	params := Params{
+		"size": fmt.Sprintf("%d", size),
+	}
+
+	if err := c.Post("/uploads", params, &upload); err != nil {
+		return nil, err
+	}
+
+	return &upload, nil
+}
+
+// GetUpload returns how much of an upload the rack has received
+func (c *Client) GetUpload(id string) (*models.Upload, error) {
+	var upload models.Upload
+
+	if err := c.Get(fmt.Sprintf("/uploads/%s", id), &upload); err != nil {
+		return nil, err
+	}
+
+	return &upload, nil
+}
+
+// UploadChunk sends the next chunk of an upload starting at offset
+func (c *Client) UploadChunk(id string, offset int64, data []byte) (*models.Upload, error) {
+	var upload models.Upload
+
+	if err := c.PutBody(fmt.Sprintf("/uploads/%s?offset=%d", id, offset), bytes.NewReader(data), &upload); err != nil {
+		return nil, err
+	}
+
+	return &upload, nil
+}
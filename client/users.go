@@ -0,0 +1,40 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/convox/rack/client/models"
+)
+
+// GetUsers returns the users that have a key for the rack
+func (c *Client) GetUsers() (models.UserKeys, error) {
+	var users models.UserKeys
+
+	err := c.Get("/users", &users)
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// CreateUser makes a key for a user, replacing any key the user had, and returns it
+func (c *Client) CreateUser(user string) (string, error) {
+	var res struct {
+		Key string `json:"key"`
+	}
+
+	err := c.Post("/users", Params{"user": user}, &res)
+	if err != nil {
+		return "", err
+	}
+
+	return res.Key, nil
+}
+
+// DeleteUser removes the key of a user
+func (c *Client) DeleteUser(user string) error {
+	var success interface{}
+
+	return c.Delete(fmt.Sprintf("/users/%s", user), &success)
+}
@@ -0,0 +1,19 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/convox/rack/client/models"
+)
+
+// GetAppWebhook returns the secret and urls a repository sends push webhooks for an app with
+func (c *Client) GetAppWebhook(app string) (*models.AppWebhook, error) {
+	var w models.AppWebhook
+
+	err := c.Get(fmt.Sprintf("/apps/%s/webhook", app), &w)
+	if err != nil {
+		return nil, err
+	}
+
+	return &w, nil
+}
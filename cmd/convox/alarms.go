@@ -0,0 +1,282 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/cmd/convox/stdcli"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var alarmMetricFlag = cli.StringFlag{
+	Name:  "metric",
+	Usage: "metric to alarm on: 5xx, cpu or latency",
+}
+
+var customAlarmFlags = []cli.Flag{
+	appFlag,
+	rackFlag,
+	cli.StringFlag{
+		Name:  "metric",
+		Usage: "load balancer or service metric to alarm on, e.g. RequestCount or CPUUtilization",
+	},
+	cli.StringFlag{
+		Name:  "threshold",
+		Usage: "value of the metric that triggers the alarm",
+	},
+	cli.StringFlag{
+		Name:  "comparison",
+		Usage: "alarm when the metric is above or below the threshold (default above)",
+	},
+	cli.StringFlag{
+		Name:  "process",
+		Usage: "process whose metric to watch, required if the app has more than one that reports it",
+	},
+	cli.StringFlag{
+		Name:  "statistic",
+		Usage: "Average, Maximum, Minimum, SampleCount or Sum (default depends on the metric)",
+	},
+	cli.StringFlag{
+		Name:  "period",
+		Usage: "seconds each datapoint covers, a multiple of 60 (default 60)",
+	},
+	cli.StringFlag{
+		Name:  "evaluations",
+		Usage: "consecutive periods past the threshold that trigger the alarm (default 1)",
+	},
+	cli.StringFlag{
+		Name:  "action",
+		Usage: "notify to send to every webhook, or notify:<webhook> to send to one (default notify)",
+	},
+}
+
+func init() {
+	stdcli.RegisterCommand(cli.Command{
+		Name:        "alarms",
+		Description: "manage alarms on an app's metrics",
+		Usage:       "",
+		Action:      cmdAlarms,
+		Flags:       []cli.Flag{appFlag, rackFlag},
+		Subcommands: []cli.Command{
+			{
+				Name:        "enable",
+				Description: "alarm when a metric of the app's processes is outside of its expected range",
+				Usage:       "--metric <5xx|cpu|latency>",
+				Action:      cmdAlarmsEnable,
+				Flags:       []cli.Flag{appFlag, rackFlag, alarmMetricFlag},
+			},
+			{
+				Name:        "disable",
+				Description: "stop alarming on a metric",
+				Usage:       "--metric <5xx|cpu|latency>",
+				Action:      cmdAlarmsDisable,
+				Flags:       []cli.Flag{appFlag, rackFlag, alarmMetricFlag},
+			},
+			{
+				Name:        "create",
+				Description: "create an alarm on a metric of the app with a fixed threshold",
+				Usage:       "--metric <name> --threshold <value> [options]",
+				Action:      cmdAlarmsCreate,
+				Flags:       customAlarmFlags,
+			},
+			{
+				Name:        "update",
+				Description: "change the options of an alarm created with `convox alarms create`",
+				Usage:       "<id> [options]",
+				Action:      cmdAlarmsUpdate,
+				Flags:       customAlarmFlags,
+			},
+			{
+				Name:        "delete",
+				Description: "delete an alarm created with `convox alarms create`",
+				Usage:       "<id>",
+				Action:      cmdAlarmsDelete,
+				Flags:       []cli.Flag{appFlag, rackFlag},
+			},
+		},
+	})
+}
+
+func cmdAlarms(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) > 0 {
+		return stdcli.ExitError(fmt.Errorf("`convox alarms` does not take arguments. Perhaps you meant `convox alarms enable`?"))
+	}
+
+	settings, err := rackClient(c).GetAlarms(app)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	t := stdcli.NewTable("METRIC", "ENABLED", "PROCESS", "STATE")
+
+	for _, s := range settings {
+		if !s.Enabled {
+			t.AddRow(s.Metric, "no", "", "")
+			continue
+		}
+
+		if len(s.Alarms) == 0 {
+			t.AddRow(s.Metric, "yes", "", "")
+			continue
+		}
+
+		for _, a := range s.Alarms {
+			t.AddRow(s.Metric, "yes", a.Process, a.State)
+		}
+	}
+
+	t.Print()
+
+	custom, err := rackClient(c).GetCustomAlarms(app)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(custom) == 0 {
+		return nil
+	}
+
+	fmt.Println()
+
+	t = stdcli.NewTable("ID", "PROCESS", "METRIC", "CONDITION", "PERIOD", "ACTION", "STATE")
+
+	for _, a := range custom {
+		condition := fmt.Sprintf("%s %s %g", a.Statistic, a.Comparison, a.Threshold)
+
+		if a.Evaluations > 1 {
+			condition = fmt.Sprintf("%s for %d periods", condition, a.Evaluations)
+		}
+
+		t.AddRow(a.Id, a.Process, a.Metric, condition, fmt.Sprintf("%ds", a.Period), a.Action, a.State)
+	}
+
+	t.Print()
+
+	return nil
+}
+
+func cmdAlarmsEnable(c *cli.Context) error {
+	return setAlarm(c, true)
+}
+
+func cmdAlarmsDisable(c *cli.Context) error {
+	return setAlarm(c, false)
+}
+
+func setAlarm(c *cli.Context, enabled bool) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	metric := c.String("metric")
+
+	if metric == "" {
+		return stdcli.ExitError(fmt.Errorf("--metric is required"))
+	}
+
+	if enabled {
+		fmt.Printf("Enabling %s alarms for %s... ", metric, app)
+		err = rackClient(c).EnableAlarm(app, metric)
+	} else {
+		fmt.Printf("Disabling %s alarms for %s... ", metric, app)
+		err = rackClient(c).DisableAlarm(app, metric)
+	}
+
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("UPDATING")
+
+	return nil
+}
+
+func customAlarmOptions(c *cli.Context) client.CustomAlarmOptions {
+	return client.CustomAlarmOptions{
+		Action:      c.String("action"),
+		Comparison:  c.String("comparison"),
+		Evaluations: c.String("evaluations"),
+		Metric:      c.String("metric"),
+		Period:      c.String("period"),
+		Process:     c.String("process"),
+		Statistic:   c.String("statistic"),
+		Threshold:   c.String("threshold"),
+	}
+}
+
+func cmdAlarmsCreate(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if c.String("metric") == "" || c.String("threshold") == "" {
+		stdcli.Usage(c, "create")
+		return nil
+	}
+
+	fmt.Printf("Creating alarm on %s... ", c.String("metric"))
+
+	a, err := rackClient(c).CreateCustomAlarm(app, customAlarmOptions(c))
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println(a.Id)
+
+	return nil
+}
+
+func cmdAlarmsUpdate(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "update")
+		return nil
+	}
+
+	id := c.Args()[0]
+
+	fmt.Printf("Updating alarm %s... ", id)
+
+	if _, err := rackClient(c).UpdateCustomAlarm(app, id, customAlarmOptions(c)); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+
+	return nil
+}
+
+func cmdAlarmsDelete(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "delete")
+		return nil
+	}
+
+	id := c.Args()[0]
+
+	fmt.Printf("Deleting alarm %s... ", id)
+
+	if err := rackClient(c).DeleteCustomAlarm(app, id); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+
+	return nil
+}
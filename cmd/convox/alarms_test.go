@@ -0,0 +1,133 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/test"
+)
+
+func TestAlarms(t *testing.T) {
+	ts := testServer(t,
+		test.Http{
+			Method: "GET",
+			Path:   "/apps/foo/alarms",
+			Code:   200,
+			Response: models.AlarmSettings{
+				{Metric: "5xx", Enabled: false},
+				{Metric: "cpu", Enabled: true},
+				{Metric: "latency", Enabled: true, Alarms: []models.Alarm{
+					{Name: "foo-web-latency", Metric: "latency", Process: "web", State: "ALARM"},
+				}},
+			},
+		},
+		test.Http{
+			Method: "GET",
+			Path:   "/apps/foo/alarms/custom",
+			Code:   200,
+			Response: models.CustomAlarms{
+				{Id: "A1234", Process: "web", Metric: "RequestCount", Statistic: "Sum", Comparison: "above", Threshold: 10000, Period: 60, Evaluations: 1, Action: "notify:slack", State: "OK"},
+				{Id: "A5678", Process: "worker", Metric: "CPUUtilization", Statistic: "Average", Comparison: "below", Threshold: 5, Period: 300, Evaluations: 3, Action: "notify", State: "INSUFFICIENT_DATA"},
+			},
+		},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox alarms --app foo",
+			Exit:    0,
+			Stdout:  "METRIC   ENABLED  PROCESS  STATE\n5xx      no\ncpu      yes\nlatency  yes      web      ALARM\n\nID     PROCESS  METRIC          CONDITION                      PERIOD  ACTION        STATE\nA1234  web      RequestCount    Sum above 10000                60s     notify:slack  OK\nA5678  worker   CPUUtilization  Average below 5 for 3 periods  300s    notify        INSUFFICIENT_DATA\n",
+		},
+	)
+}
+
+func TestAlarmsCreate(t *testing.T) {
+	ts := testServer(t,
+		test.Http{
+			Method:   "POST",
+			Path:     "/apps/foo/alarms/custom",
+			Body:     "action=notify%3Aslack&metric=RequestCount&period=60&threshold=10000",
+			Code:     200,
+			Response: models.CustomAlarm{Id: "A1234"},
+		},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox alarms create --app foo --metric RequestCount --threshold 10000 --period 60 --action notify:slack",
+			Exit:    0,
+			Stdout:  "Creating alarm on RequestCount... A1234\n",
+		},
+	)
+}
+
+func TestAlarmsUpdateDelete(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "PUT", Path: "/apps/foo/alarms/custom/A1234", Body: "threshold=20000", Code: 200, Response: models.CustomAlarm{Id: "A1234"}},
+		test.Http{Method: "DELETE", Path: "/apps/foo/alarms/custom/A1234", Code: 200, Response: map[string]bool{"success": true}},
+		test.Http{Method: "DELETE", Path: "/apps/foo/alarms/custom/A5678", Code: 404, Response: map[string]string{"error": "no such alarm: A5678"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox alarms update A1234 --app foo --threshold 20000",
+			Exit:    0,
+			Stdout:  "Updating alarm A1234... OK\n",
+		},
+		test.ExecRun{
+			Command: "convox alarms delete A1234 --app foo",
+			Exit:    0,
+			Stdout:  "Deleting alarm A1234... OK\n",
+		},
+		test.ExecRun{
+			Command: "convox alarms delete A5678 --app foo",
+			Exit:    1,
+			Stdout:  "Deleting alarm A5678... ",
+			Stderr:  "ERROR: no such alarm: A5678\n",
+		},
+	)
+}
+
+func TestAlarmsEnable(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "POST", Path: "/apps/foo/alarms/latency", Code: 200, Response: map[string]bool{"success": true}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox alarms enable --app foo --metric latency",
+			Exit:    0,
+			Stdout:  "Enabling latency alarms for foo... UPDATING\n",
+		},
+		test.ExecRun{
+			Command: "convox alarms enable --app foo",
+			Exit:    1,
+			Stderr:  "ERROR: --metric is required\n",
+		},
+	)
+}
+
+func TestAlarmsDisable(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "DELETE", Path: "/apps/foo/alarms/bogus", Code: 403, Response: map[string]string{"error": "metric must be one of 5xx, cpu, latency"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox alarms disable --app foo --metric bogus",
+			Exit:    1,
+			Stdout:  "Disabling bogus alarms for foo... ",
+			Stderr:  "ERROR: metric must be one of 5xx, cpu, latency\n",
+		},
+	)
+}
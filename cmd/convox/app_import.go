@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/cmd/convox/stdcli"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func cmdAppImport(c *cli.Context) error {
+	from := strings.SplitN(c.String("from-ecs"), "/", 2)
+
+	if len(from) != 2 || from[0] == "" || from[1] == "" || len(c.Args()) > 1 {
+		stdcli.Usage(c, "import")
+		return nil
+	}
+
+	imp, err := rackClient(c).GetAppImportECS(from[0], from[1])
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	app := imp.Name
+
+	if len(c.Args()) > 0 {
+		app = c.Args()[0]
+	}
+
+	fmt.Printf("Creating app %s... ", app)
+
+	if _, err := rackClient(c).CreateApp(app); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("CREATING")
+	fmt.Printf("Waiting for %s... ", app)
+
+	if err := waitForAppRunning(c, app); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+
+	if len(imp.Environment) > 0 {
+		keys := []string{}
+
+		for key := range imp.Environment {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		var env bytes.Buffer
+
+		for _, key := range keys {
+			fmt.Fprintf(&env, "%s=%s\n", key, imp.Environment[key])
+		}
+
+		fmt.Printf("Setting %s... ", strings.Join(keys, ", "))
+
+		if _, _, err := rackClient(c).SetEnvironment(app, &env); err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		fmt.Println("OK")
+	}
+
+	dir, err := ioutil.TempDir("", "import")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "docker-compose.yml"), []byte(imp.Manifest), 0644); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	release, err := executeBuild(c, dir, app, "docker-compose.yml", fmt.Sprintf("imported from ecs %s", c.String("from-ecs")))
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Printf("Promoting %s... ", release)
+
+	if _, err := rackClient(c).PromoteRelease(app, release); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if err := waitForReleasePromotion(c, app, release); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+
+	for _, pf := range imp.Formation {
+		opts := client.FormationOptions{Count: strconv.Itoa(pf.Count)}
+
+		if pf.Memory > 0 {
+			opts.Memory = strconv.Itoa(pf.Memory)
+		}
+
+		if pf.CPU > 0 {
+			opts.CPU = strconv.Itoa(pf.CPU)
+		}
+
+		fmt.Printf("Scaling %s to %d... ", pf.Name, pf.Count)
+
+		if err := rackClient(c).SetFormation(app, pf.Name, opts); err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		if err := waitForAppRunning(c, app); err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		fmt.Println("OK")
+	}
+
+	fmt.Printf("Imported %s, the ECS service is left running until you remove it\n", app)
+
+	return nil
+}
@@ -35,7 +35,20 @@ func init() {
 				Description: "delete an application",
 				Usage:       "<name>",
 				Action:      cmdAppDelete,
-				Flags:       []cli.Flag{rackFlag},
+				Flags:       []cli.Flag{rackFlag, dryRunFlag},
+			},
+			{
+				Name:        "import",
+				Description: "create an app that runs the containers of an existing ECS service",
+				Usage:       "--from-ecs <cluster>/<service> [name]",
+				Action:      cmdAppImport,
+				Flags: []cli.Flag{
+					rackFlag,
+					cli.StringFlag{
+						Name:  "from-ecs",
+						Usage: "ECS service to import as cluster/service",
+					},
+				},
 			},
 			{
 				Name:        "info",
@@ -56,10 +69,55 @@ func init() {
 						Description: "update advanced parameters for an app",
 						Usage:       "NAME=VALUE [NAME=VALUE]",
 						Action:      cmdAppParamsSet,
+						Flags:       []cli.Flag{appFlag, rackFlag, dryRunFlag},
+					},
+					{
+						Name:        "history",
+						Description: "list changes to the parameters of an app",
+						Usage:       "",
+						Action:      cmdAppParamsHistory,
+						Flags:       []cli.Flag{appFlag, rackFlag},
+					},
+				},
+			},
+			{
+				Name:        "policy",
+				Description: "show whether an app can only be changed through releases",
+				Usage:       "",
+				Action:      cmdAppPolicy,
+				Flags:       []cli.Flag{appFlag, rackFlag},
+				Subcommands: []cli.Command{
+					{
+						Name:        "set",
+						Description: "deny exec, run and environment changes on an app with immutable=true",
+						Usage:       "immutable=<true|false>",
+						Action:      cmdAppPolicySet,
 						Flags:       []cli.Flag{appFlag, rackFlag},
 					},
 				},
 			},
+			{
+				Name:        "wait",
+				Description: "wait for an app to finish creating or deploying",
+				Usage:       "[name]",
+				Action:      cmdAppWait,
+				Flags: []cli.Flag{
+					appFlag,
+					rackFlag,
+					cli.DurationFlag{
+						Name:  "timeout",
+						Usage: "give up after a duration, exiting with status 2",
+						Value: 30 * time.Minute,
+					},
+				},
+			},
+			{
+				Name:        "webhook",
+				Description: "show the webhook that builds and promotes an app on pushes to its Repo and Branch parameters",
+				Usage:       "",
+				Action:      cmdAppWebhook,
+				Flags:       []cli.Flag{appFlag, rackFlag},
+			},
 		},
 	})
 }
@@ -133,6 +191,16 @@ func cmdAppDelete(c *cli.Context) error {
 
 	app := c.Args()[0]
 
+	if c.Bool("dry-run") {
+		if _, err := rackClient(c).GetApp(app); err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		dryRunCall("DELETE", fmt.Sprintf("/apps/%s", app), nil)
+		fmt.Printf("Would delete the stack of %s and all of its resources\n", app)
+		return nil
+	}
+
 	fmt.Printf("Deleting %s... ", app)
 
 	_, err := rackClient(c).DeleteApp(app)
@@ -214,6 +282,27 @@ func cmdAppParams(c *cli.Context) error {
 	return nil
 }
 
+func cmdAppParamsHistory(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	history, err := rackClient(c).GetParameterHistory(app)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	t := stdcli.NewTable("WHEN", "NAME", "OLD", "NEW", "USER")
+
+	for _, change := range history {
+		t.AddRow(humanizeTime(change.Created), change.Name, change.Old, change.New, change.User)
+	}
+
+	t.Print()
+	return nil
+}
+
 func cmdAppParamsSet(c *cli.Context) error {
 	_, app, err := stdcli.DirApp(c, ".")
 	if err != nil {
@@ -232,6 +321,14 @@ func cmdAppParamsSet(c *cli.Context) error {
 		params[parts[0]] = parts[1]
 	}
 
+	if c.Bool("dry-run") {
+		if err := paramsDryRun(c, app, params); err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		return nil
+	}
+
 	fmt.Print("Updating parameters... ")
 
 	err = rackClient(c).SetParameters(app, params)
@@ -243,8 +340,33 @@ func cmdAppParamsSet(c *cli.Context) error {
 	return nil
 }
 
+func cmdAppWait(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) > 0 {
+		app = c.Args()[0]
+	}
+
+	fmt.Printf("Waiting for %s... ", app)
+
+	if err := waitForAppRunningTimeout(c, app, c.Duration("timeout")); err != nil {
+		return waitExitError(err)
+	}
+
+	fmt.Println("OK")
+	return nil
+}
+
 func waitForAppRunning(c *cli.Context, app string) error {
-	timeout := time.After(30 * time.Minute)
+	return waitForAppRunningTimeout(c, app, 30*time.Minute)
+}
+
+// waitForAppRunningTimeout waits for an app to settle into running, failing if an update rolled back
+func waitForAppRunningTimeout(c *cli.Context, app string, d time.Duration) error {
+	timeout := time.After(d)
 	tick := time.Tick(5 * time.Second)
 
 	failed := false
@@ -261,7 +383,7 @@ func waitForAppRunning(c *cli.Context, app string) error {
 			case "running":
 				if failed {
 					fmt.Println("DONE")
-					return fmt.Errorf("Update rolled back")
+					return waitError{fmt.Errorf("Update rolled back"), exitWaitFailed}
 				}
 				return nil
 			case "rollback":
@@ -271,7 +393,7 @@ func waitForAppRunning(c *cli.Context, app string) error {
 				}
 			}
 		case <-timeout:
-			return fmt.Errorf("timeout")
+			return waitError{fmt.Errorf("timeout"), exitWaitTimeout}
 		}
 	}
 
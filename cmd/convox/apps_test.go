@@ -4,56 +4,103 @@ import (
 	"testing"
 
 	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/fake"
+	"github.com/convox/rack/client/models"
 	"github.com/convox/rack/test"
 )
 
 func TestApps(t *testing.T) {
-	ts := testServer(t,
-		test.Http{Method: "GET", Path: "/apps", Code: 200, Response: client.Apps{
-			client.App{Name: "sinatra", Status: "running"},
-		}},
-	)
+	fc := fake.New()
+	fc.CreateApp("sinatra")
 
-	defer ts.Close()
-
-	test.Runs(t,
+	fakeRuns(t, fc,
 		test.ExecRun{
 			Command: "convox apps",
 			Exit:    0,
 			Stdout:  "APP      STATUS\nsinatra  running\n",
 		},
+		test.ExecRun{
+			Command: "convox apps sinatra",
+			Exit:    1,
+			Stderr:  "ERROR: `convox apps` does not take arguments. Perhaps you meant `convox apps create`?\n",
+		},
 	)
 }
 
 func TestAppsCreate(t *testing.T) {
+	fc := fake.New()
+
+	fakeRuns(t, fc,
+		test.ExecRun{
+			Command: "convox apps create foobar",
+			Exit:    0,
+			Stdout:  "Creating app foobar... CREATING\n",
+		},
+		test.ExecRun{
+			Command: "convox apps",
+			Exit:    0,
+			Stdout:  "APP     STATUS\nfoobar  running\n",
+		},
+	)
+}
+
+func TestAppsCreateFail(t *testing.T) {
+	fc := fake.New()
+	fc.CreateApp("foobar")
+
+	fakeRuns(t, fc,
+		test.ExecRun{
+			Command: "convox apps create foobar",
+			Exit:    1,
+			Stdout:  "Creating app foobar... ",
+			Stderr:  "ERROR: app already exists: foobar\n",
+		},
+	)
+}
+
+func TestAppsParamsSetDryRun(t *testing.T) {
 	ts := testServer(t,
-		test.Http{Method: "POST", Path: "/apps", Body: "name=foobar", Code: 200, Response: client.App{}},
+		test.Http{Method: "POST", Path: "/apps/foo/parameters/preview", Body: "Internal=Yes", Code: 200, Response: models.StackChanges{
+			{Action: "Modify", Resource: "Balancer", Type: "AWS::ElasticLoadBalancing::LoadBalancer", Replacement: "True"},
+			{Action: "Add", Resource: "BalancerSecurityGroup", Type: "AWS::EC2::SecurityGroup"},
+		}},
+		test.Http{Method: "POST", Path: "/apps/bar/parameters/preview", Body: "Internal=No", Code: 200, Response: models.StackChanges{}},
 	)
 
 	defer ts.Close()
 
 	test.Runs(t,
 		test.ExecRun{
-			Command: "convox apps create foobar",
+			Command: "convox apps params set Internal=Yes --dry-run --app foo",
 			Exit:    0,
-			Stdout:  "Creating app foobar... CREATING\n",
+			Stdout:  "Would POST /apps/foo/parameters\n  Internal=Yes\nACTION  RESOURCE               TYPE                                     REPLACEMENT\nModify  Balancer               AWS::ElasticLoadBalancing::LoadBalancer  True\nAdd     BalancerSecurityGroup  AWS::EC2::SecurityGroup                  -\n",
+		},
+		test.ExecRun{
+			Command: "convox apps params set Internal=No --dry-run --app bar",
+			Exit:    0,
+			Stdout:  "Would POST /apps/bar/parameters\n  Internal=No\nNo infrastructure changes\n",
 		},
 	)
 }
 
-func TestAppsCreateFail(t *testing.T) {
+func TestAppsDeleteDryRun(t *testing.T) {
 	ts := testServer(t,
-		test.Http{Method: "POST", Path: "/apps", Body: "name=foobar", Code: 403, Response: client.Error{Error: "app already exists"}},
+		test.Http{Method: "GET", Path: "/apps/foo", Code: 200, Response: models.App{Name: "foo", Status: "running"}},
+		test.Http{Method: "GET", Path: "/apps/bar", Code: 404, Response: client.Error{Error: "no such app: bar"}},
 	)
 
 	defer ts.Close()
 
 	test.Runs(t,
 		test.ExecRun{
-			Command: "convox apps create foobar",
+			Command: "convox apps delete --dry-run foo",
+			Exit:    0,
+			Stdout:  "Would DELETE /apps/foo\nWould delete the stack of foo and all of its resources\n",
+		},
+		test.ExecRun{
+			Command: "convox apps delete --dry-run bar",
 			Exit:    1,
-			Stdout:  "Creating app foobar... ",
-			Stderr:  "ERROR: app already exists\n",
+			Stderr:  "ERROR: no such app: bar\n",
 		},
 	)
 }
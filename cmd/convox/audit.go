@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/cmd/convox/stdcli"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func init() {
+	stdcli.RegisterCommand(cli.Command{
+		Name:        "audit",
+		Description: "list changes made to the rack and who made them",
+		Usage:       "[--app <app>] [--user <user>] [--action <action>] [--since 24h] [--limit 100]",
+		Action:      cmdAudit,
+		Flags: []cli.Flag{
+			appFlag,
+			rackFlag,
+			cli.StringFlag{
+				Name:  "user",
+				Usage: "only show changes made by this user",
+			},
+			cli.StringFlag{
+				Name:  "action",
+				Usage: "only show this action, e.g. release.promote",
+			},
+			cli.DurationFlag{
+				Name:  "since",
+				Usage: "how far back to look (default 24h)",
+			},
+			cli.IntFlag{
+				Name:  "limit",
+				Usage: "most changes to show (default 100)",
+			},
+		},
+	})
+}
+
+func cmdAudit(c *cli.Context) error {
+	if len(c.Args()) > 0 {
+		return stdcli.ExitError(fmt.Errorf("`convox audit` does not take arguments"))
+	}
+
+	events, err := rackClient(c).GetAudit(client.AuditOptions{
+		Action: c.String("action"),
+		App:    c.String("app"),
+		User:   c.String("user"),
+		Since:  c.Duration("since"),
+		Limit:  c.Int("limit"),
+	})
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	t := stdcli.NewTable("TIME", "USER", "ACTION", "APP", "STATUS", "SUMMARY")
+
+	for _, e := range events {
+		user := e.User
+
+		// without a user key the rack only knows who the client says it is
+		if !e.Authenticated {
+			user += " (client-asserted)"
+		}
+
+		t.AddRow(humanizeTime(e.Created), user, e.Action, e.App, strconv.Itoa(e.Status), e.Summary)
+	}
+
+	t.Print()
+	return nil
+}
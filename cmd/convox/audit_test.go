@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/test"
+)
+
+func TestAudit(t *testing.T) {
+	created := time.Now().UTC().Add(-2 * time.Minute)
+
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/audit", Code: 200, Response: models.AuditEvents{
+			{Action: "release.promote", App: "foo", Status: 200, User: "ddollar", Created: created, Authenticated: true},
+			{Action: "formation.set", App: "foo", Status: 403, Summary: "count=3", User: "unknown", Created: created},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox audit --app foo",
+			Exit:    0,
+			Stdout:  "TIME           USER                       ACTION           APP  STATUS  SUMMARY\n2 minutes ago  ddollar                    release.promote  foo  200\n2 minutes ago  unknown (client-asserted)  formation.set    foo  403     count=3\n",
+		},
+		test.ExecRun{
+			Command: "convox audit foo",
+			Exit:    1,
+			Stderr:  "ERROR: `convox audit` does not take arguments\n",
+		},
+	)
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/convox/rack/cmd/convox/stdcli"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func init() {
+	stdcli.RegisterCommand(cli.Command{
+		Name:        "breakglass",
+		Description: "manage temporary exec, run and environment access to immutable apps",
+		Usage:       "",
+		Action:      cmdBreakGlass,
+		Flags:       []cli.Flag{appFlag, rackFlag},
+		Subcommands: []cli.Command{
+			{
+				Name:        "request",
+				Description: "request temporary access to an immutable app, to be approved by another user",
+				Usage:       "--reason <reason> [--duration 1h]",
+				Action:      cmdBreakGlassRequest,
+				Flags: []cli.Flag{
+					appFlag,
+					rackFlag,
+					cli.DurationFlag{
+						Name:  "duration",
+						Usage: "how long access lasts once approved, at most 8h",
+						Value: 1 * time.Hour,
+					},
+					cli.StringFlag{
+						Name:  "reason",
+						Usage: "why access is needed, such as an incident number",
+					},
+				},
+			},
+			{
+				Name:        "approve",
+				Description: "approve a break glass request of another user",
+				Usage:       "<id>",
+				Action:      cmdBreakGlassApprove,
+				Flags:       []cli.Flag{appFlag, rackFlag},
+			},
+			{
+				Name:        "deny",
+				Description: "deny a break glass request",
+				Usage:       "<id>",
+				Action:      cmdBreakGlassDeny,
+				Flags:       []cli.Flag{appFlag, rackFlag},
+			},
+			{
+				Name:        "revoke",
+				Description: "end break glass access before it expires",
+				Usage:       "<id>",
+				Action:      cmdBreakGlassRevoke,
+				Flags:       []cli.Flag{appFlag, rackFlag},
+			},
+		},
+	})
+}
+
+func cmdBreakGlass(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) > 0 {
+		return stdcli.ExitError(fmt.Errorf("`convox breakglass` does not take arguments. Perhaps you meant `convox breakglass request`?"))
+	}
+
+	grants, err := rackClient(c).GetBreakGlass(app)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	t := stdcli.NewTable("ID", "USER", "STATUS", "DURATION", "EXPIRES", "REASON")
+
+	for _, b := range grants {
+		t.AddRow(b.Id, b.User, b.Status, b.Duration.String(), humanizeTime(b.Expires), b.Reason)
+	}
+
+	t.Print()
+	return nil
+}
+
+func cmdBreakGlassRequest(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) > 0 || c.String("reason") == "" {
+		stdcli.Usage(c, "request")
+		return nil
+	}
+
+	fmt.Printf("Requesting break glass access to %s... ", app)
+
+	b, err := rackClient(c).RequestBreakGlass(app, c.Duration("duration"), c.String("reason"))
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println(b.Id)
+	return nil
+}
+
+func cmdBreakGlassApprove(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "approve")
+		return nil
+	}
+
+	id := c.Args()[0]
+
+	fmt.Printf("Approving %s... ", id)
+
+	b, err := rackClient(c).ApproveBreakGlass(app, id)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Printf("OK, %s has access for %s\n", b.User, b.Duration)
+	return nil
+}
+
+func cmdBreakGlassDeny(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "deny")
+		return nil
+	}
+
+	id := c.Args()[0]
+
+	fmt.Printf("Denying %s... ", id)
+
+	if _, err := rackClient(c).DenyBreakGlass(app, id); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+	return nil
+}
+
+func cmdBreakGlassRevoke(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "revoke")
+		return nil
+	}
+
+	id := c.Args()[0]
+
+	fmt.Printf("Revoking %s... ", id)
+
+	if _, err := rackClient(c).RevokeBreakGlass(app, id); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+	return nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/test"
+)
+
+func TestBreakGlass(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo/breakglass", Code: 200, Response: models.BreakGlasses{
+			{Id: "G1234567890", User: "alice", Status: "pending", Duration: time.Hour, Reason: "incident 1234"},
+			{Id: "G0987654321", User: "bob", Status: "denied", Duration: 30 * time.Minute, Reason: "debugging"},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox breakglass --app foo",
+			Exit:    0,
+			Stdout:  "ID           USER   STATUS   DURATION  EXPIRES  REASON\nG1234567890  alice  pending  1h0m0s             incident 1234\nG0987654321  bob    denied   30m0s              debugging\n",
+		},
+	)
+}
+
+func TestBreakGlassRequest(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "POST", Path: "/apps/foo/breakglass", Body: "duration=1h0m0s&reason=incident+1234", Code: 200, Response: models.BreakGlass{Id: "G1234567890"}},
+		test.Http{Method: "POST", Path: "/apps/bar/breakglass", Body: "duration=30m0s&reason=incident+1234", Code: 403, Response: client.Error{Error: "app is not immutable: bar"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: `convox breakglass request --app foo --duration 1h --reason "incident 1234"`,
+			Exit:    0,
+			Stdout:  "Requesting break glass access to foo... G1234567890\n",
+		},
+		test.ExecRun{
+			Command: `convox breakglass request --app bar --duration 30m --reason "incident 1234"`,
+			Exit:    1,
+			Stdout:  "Requesting break glass access to bar... ",
+			Stderr:  "ERROR: app is not immutable: bar\n",
+		},
+	)
+}
+
+func TestBreakGlassReview(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "POST", Path: "/apps/foo/breakglass/G1234567890/approve", Code: 200, Response: models.BreakGlass{Id: "G1234567890", User: "alice", Status: "approved", Duration: time.Hour}},
+		test.Http{Method: "POST", Path: "/apps/foo/breakglass/G0987654321/approve", Code: 403, Response: client.Error{Error: "break glass request must be approved by another user"}},
+		test.Http{Method: "POST", Path: "/apps/foo/breakglass/G0987654321/deny", Code: 200, Response: models.BreakGlass{Id: "G0987654321", Status: "denied"}},
+		test.Http{Method: "DELETE", Path: "/apps/foo/breakglass/G1234567890", Code: 200, Response: models.BreakGlass{Id: "G1234567890", Status: "revoked"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox breakglass approve G1234567890 --app foo",
+			Exit:    0,
+			Stdout:  "Approving G1234567890... OK, alice has access for 1h0m0s\n",
+		},
+		test.ExecRun{
+			Command: "convox breakglass approve G0987654321 --app foo",
+			Exit:    1,
+			Stdout:  "Approving G0987654321... ",
+			Stderr:  "ERROR: break glass request must be approved by another user\n",
+		},
+		test.ExecRun{
+			Command: "convox breakglass deny G0987654321 --app foo",
+			Exit:    0,
+			Stdout:  "Denying G0987654321... OK\n",
+		},
+		test.ExecRun{
+			Command: "convox breakglass revoke G1234567890 --app foo",
+			Exit:    0,
+			Stdout:  "Revoking G1234567890... OK\n",
+		},
+	)
+}
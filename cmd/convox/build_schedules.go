@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/convox/rack/cmd/convox/stdcli"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func cmdBuildSchedules(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) > 0 {
+		return stdcli.ExitError(fmt.Errorf("`convox builds schedule` does not take arguments. Perhaps you meant `convox builds schedule add`?"))
+	}
+
+	schedules, err := rackClient(c).GetBuildSchedules(app)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	t := stdcli.NewTable("ID", "SCHEDULE", "URL", "LAST RUN", "BUILD", "STATUS")
+
+	for _, s := range schedules {
+		t.AddRow(s.Id, s.Schedule, s.URL, humanizeTime(s.LastRun), s.LastBuild, s.LastStatus)
+	}
+
+	t.Print()
+	return nil
+}
+
+func cmdBuildScheduleAdd(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) != 2 {
+		stdcli.Usage(c, "add")
+		return nil
+	}
+
+	schedule := c.Args()[0]
+	url := c.Args()[1]
+
+	if !remoteSource(url) {
+		return stdcli.ExitError(fmt.Errorf("scheduled builds require a git repository url"))
+	}
+
+	if ref := c.String("ref"); ref != "" {
+		url = strings.SplitN(url, "#", 2)[0] + "#" + ref
+	}
+
+	fmt.Printf("Scheduling builds of %s... ", url)
+
+	s, err := rackClient(c).CreateBuildSchedule(app, schedule, url, c.String("file"))
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println(s.Id)
+	return nil
+}
+
+func cmdBuildScheduleRemove(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "remove")
+		return nil
+	}
+
+	id := c.Args()[0]
+
+	fmt.Printf("Removing schedule %s... ", id)
+
+	if err := rackClient(c).DeleteBuildSchedule(app, id); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/test"
+)
+
+func TestBuildSchedules(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo/builds/schedules", Code: 200, Response: models.BuildSchedules{
+			{Id: "S1234", Schedule: "0 3 * * *", URL: "https://github.com/convox/rack.git#master", LastBuild: "B1234", LastStatus: "complete"},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox builds schedule --app foo",
+			Exit:    0,
+			Stdout:  "ID     SCHEDULE   URL                                        LAST RUN  BUILD  STATUS\nS1234  0 3 * * *  https://github.com/convox/rack.git#master            B1234  complete\n",
+		},
+	)
+}
+
+func TestBuildSchedulesAdd(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "POST", Path: "/apps/foo/builds/schedules", Body: "schedule=0+3+%2A+%2A+%2A&url=https%3A%2F%2Fgithub.com%2Fconvox%2Frack.git%23v1", Code: 200, Response: models.BuildSchedule{Id: "S1234"}},
+		test.Http{Method: "POST", Path: "/apps/bar/builds/schedules", Body: "schedule=nightly&url=https%3A%2F%2Fgithub.com%2Fconvox%2Frack.git", Code: 403, Response: client.Error{Error: "schedule must have five fields: minute hour day-of-month month day-of-week"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: `convox builds schedule add --app foo --ref v1 "0 3 * * *" https://github.com/convox/rack.git#master`,
+			Exit:    0,
+			Stdout:  "Scheduling builds of https://github.com/convox/rack.git#v1... S1234\n",
+		},
+		test.ExecRun{
+			Command: "convox builds schedule add --app bar nightly https://github.com/convox/rack.git",
+			Exit:    1,
+			Stdout:  "Scheduling builds of https://github.com/convox/rack.git... ",
+			Stderr:  "ERROR: schedule must have five fields: minute hour day-of-month month day-of-week\n",
+		},
+		test.ExecRun{
+			Command: "convox builds schedule add --app foo nightly .",
+			Exit:    1,
+			Stderr:  "ERROR: scheduled builds require a git repository url\n",
+		},
+	)
+}
+
+func TestBuildSchedulesRemove(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "DELETE", Path: "/apps/foo/builds/schedules/S1234", Code: 200, Response: map[string]bool{"success": true}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox builds schedule remove --app foo S1234",
+			Exit:    0,
+			Stdout:  "Removing schedule S1234... OK\n",
+		},
+	)
+}
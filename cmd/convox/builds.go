@@ -11,20 +11,26 @@ import (
 	"io/ioutil"
 	"net/url"
 	"os"
-	"path"
+	"os/exec"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	"gopkg.in/urfave/cli.v1"
 
 	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/models"
 	"github.com/convox/rack/cmd/convox/stdcli"
 	"github.com/docker/docker/builder/dockerignore"
 	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/fileutils"
 )
 
+// blobSize is the size from which source files are uploaded to the rack index and left out of build tarballs
+const blobSize = 1024 * 1024
+
 var (
 	buildCreateFlags = []cli.Flag{
 		appFlag,
@@ -47,7 +53,47 @@ var (
 			Value: "",
 			Usage: "description of the build",
 		},
+		cli.StringFlag{
+			Name:  "priority",
+			Value: "",
+			Usage: "position in the build queue: high, normal or low",
+		},
+		cli.IntFlag{
+			Name:  "concurrency",
+			Usage: "number of service images to build at once",
+		},
+		cli.StringFlag{
+			Name:  "strict",
+			Usage: "fail the build on Dockerfile warnings of comma separated rules: unpinned-base, apt-cache, env-secret or all",
+		},
+		cli.StringSliceFlag{
+			Name:  "build-arg",
+			Usage: "build arg passed to the docker build of every service as KEY=VALUE, can be repeated",
+		},
+		cli.StringFlag{
+			Name:  "matrix",
+			Usage: "build an image of every service for each architecture, i.e. arch=amd64,arm64",
+		},
+		cli.BoolFlag{
+			Name:  "watch",
+			Usage: "rebuild incrementally whenever files change",
+		},
+		cli.BoolFlag{
+			Name:  "promote",
+			Usage: "promote each release after it is built",
+		},
+		cli.StringFlag{
+			Name:  "ref",
+			Usage: "branch, tag or commit to build from a git repository",
+		},
+		limitBandwidthFlag,
 	}
+
+	// watchInterval is how often watch mode checks the source for changes
+	watchInterval = 2 * time.Second
+
+	// scpGitSource matches the user@host:path form of ssh git urls, i.e. git@github.com:convox/rack.git
+	scpGitSource = regexp.MustCompile(`^[\w.-]+@[\w.-]+:.`)
 )
 
 func init() {
@@ -86,19 +132,156 @@ func init() {
 					},
 				},
 			},
+			{
+				Name:        "export",
+				Description: "export a build and its images to an archive",
+				Usage:       "<ID> -f <file>",
+				Action:      cmdBuildsExport,
+				Flags: []cli.Flag{
+					appFlag,
+					rackFlag,
+					cli.StringFlag{
+						Name:  "file, f",
+						Usage: "path of the archive to write",
+					},
+				},
+			},
+			{
+				Name:        "import",
+				Description: "import a build from an archive made by builds export",
+				Usage:       "-f <file>",
+				Action:      cmdBuildsImport,
+				Flags: []cli.Flag{
+					appFlag,
+					rackFlag,
+					cli.StringFlag{
+						Name:  "file, f",
+						Usage: "path of the archive to import",
+					},
+					limitBandwidthFlag,
+				},
+			},
+			{
+				Name:        "promote",
+				Description: "promote the release of a build",
+				Usage:       "<ID>",
+				Action:      cmdBuildsPromote,
+				Flags: []cli.Flag{
+					appFlag,
+					rackFlag,
+					cli.BoolFlag{
+						Name:  "wait",
+						Usage: "wait for release to finish promoting before returning",
+					},
+				},
+			},
 			{
 				Name:        "info",
-				Description: "print output for a build",
+				Description: "print information about a build",
 				Usage:       "<ID>",
 				Action:      cmdBuildsInfo,
+				Flags: []cli.Flag{
+					appFlag,
+					rackFlag,
+					cli.BoolFlag{
+						Name:  "logs",
+						Usage: "also print the build output",
+					},
+					cli.BoolFlag{
+						Name:  "timings",
+						Usage: "also print how long each stage and docker build step took",
+					},
+				},
+			},
+			{
+				Name:        "attach",
+				Description: "stream output for an in-progress build and wait for it to finish",
+				Usage:       "<ID>",
+				Action:      cmdBuildsAttach,
 				Flags:       []cli.Flag{appFlag, rackFlag},
 			},
+			{
+				Name:        "wait",
+				Description: "wait for a build to finish",
+				Usage:       "<ID>",
+				Action:      cmdBuildsWait,
+				Flags: []cli.Flag{
+					appFlag,
+					rackFlag,
+					cli.DurationFlag{
+						Name:  "timeout",
+						Usage: "give up after a duration (e.g. 10m), exiting with status 2",
+					},
+				},
+			},
+			{
+				Name:        "cancel",
+				Description: "stop a build that is still in progress",
+				Usage:       "<ID>",
+				Action:      cmdBuildsCancel,
+				Flags:       []cli.Flag{appFlag, rackFlag},
+			},
+			{
+				Name:        "logs",
+				Description: "print output for a build",
+				Usage:       "<ID>",
+				Action:      cmdBuildsLogs,
+				Flags: []cli.Flag{
+					appFlag,
+					rackFlag,
+					cli.BoolFlag{
+						Name:  "follow, f",
+						Usage: "stream output until the build finishes",
+					},
+				},
+			},
 			{
 				Name:        "delete",
 				Description: "Archive a build and its artifacts",
 				Usage:       "<ID>",
 				Action:      cmdBuildsDelete,
+				Flags:       []cli.Flag{appFlag, rackFlag, dryRunFlag},
+			},
+			{
+				Name:        "schedule",
+				Description: "manage scheduled builds of an app",
+				Usage:       "",
+				Action:      cmdBuildSchedules,
 				Flags:       []cli.Flag{appFlag, rackFlag},
+				Subcommands: []cli.Command{
+					{
+						Name:        "add",
+						Description: "build and promote a git repository on a cron schedule (UTC)",
+						Usage:       "[--ref master] [--file docker-compose.yml] <schedule> <url>",
+						Action:      cmdBuildScheduleAdd,
+						Flags: []cli.Flag{
+							appFlag,
+							rackFlag,
+							cli.StringFlag{
+								Name:  "file, f",
+								Usage: "path to an alternate docker compose manifest file",
+							},
+							cli.StringFlag{
+								Name:  "ref",
+								Usage: "branch, tag or commit to build",
+							},
+						},
+					},
+					{
+						Name:        "list",
+						Description: "list scheduled builds",
+						Usage:       "",
+						Action:      cmdBuildSchedules,
+						Flags:       []cli.Flag{appFlag, rackFlag},
+					},
+					{
+						Name:        "remove",
+						Description: "stop a scheduled build",
+						Usage:       "<ID>",
+						Action:      cmdBuildScheduleRemove,
+						Flags:       []cli.Flag{appFlag, rackFlag},
+					},
+				},
 			},
 		},
 	})
@@ -170,12 +353,124 @@ func cmdBuildsCreate(c *cli.Context) error {
 		dir = c.Args()[0]
 	}
 
+	if c.Bool("watch") {
+		return watchBuild(c, dir, app)
+	}
+
 	release, err := executeBuild(c, dir, app, c.String("file"), c.String("description"))
 	if err != nil {
 		return stdcli.ExitError(err)
 	}
 
 	fmt.Printf("Release: %s\n", release)
+
+	if c.Bool("promote") {
+		if err := promoteBuildRelease(c, app, release); err != nil {
+			return stdcli.ExitError(err)
+		}
+	}
+
+	return nil
+}
+
+// watchBuild makes an incremental build of dir every time its files change
+// until interrupted. Failed builds are reported and the next change is awaited.
+func watchBuild(c *cli.Context, dir, app string) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	for {
+		index, err := indexDir(dir)
+		if err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		release, err := executeBuildDirIncremental(c, dir, app, c.String("file"), c.String("description"))
+		if err == nil {
+			fmt.Printf("Release: %s\n", release)
+
+			if c.Bool("promote") {
+				err = promoteBuildRelease(c, app, release)
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %s\n", err)
+		}
+
+		fmt.Println("Watching for changes...")
+
+		for {
+			time.Sleep(watchInterval)
+
+			current, err := indexDir(dir)
+			if err != nil {
+				return stdcli.ExitError(err)
+			}
+
+			if changed := indexChanges(index, current); len(changed) > 0 {
+				fmt.Printf("Changed: %s\n", strings.Join(changed, ", "))
+				break
+			}
+		}
+	}
+}
+
+// indexChanges returns the sorted names of files that were added, removed or modified between two indexes
+func indexChanges(before, after models.Index) []string {
+	files := func(index models.Index) map[string]string {
+		hashes := map[string]string{}
+
+		for hash, item := range index {
+			hashes[item.Name] = hash
+		}
+
+		return hashes
+	}
+
+	b := files(before)
+	a := files(after)
+
+	changed := []string{}
+
+	for name, hash := range a {
+		if b[name] != hash {
+			changed = append(changed, name)
+		}
+	}
+
+	for name := range b {
+		if _, ok := a[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+
+	sort.Strings(changed)
+
+	return changed
+}
+
+// promoteBuildRelease promotes a newly built release and waits for it to finish
+func promoteBuildRelease(c *cli.Context, app, release string) error {
+	if release == "" {
+		return nil
+	}
+
+	fmt.Printf("Promoting %s... ", release)
+
+	if _, err := rackClient(c).PromoteRelease(app, release); err != nil {
+		return err
+	}
+
+	fmt.Println("UPDATING")
+	fmt.Printf("Waiting for stabilization... ")
+
+	if err := waitForReleasePromotion(c, app, release); err != nil {
+		return err
+	}
+
+	fmt.Println("OK")
 	return nil
 }
 
@@ -192,6 +487,15 @@ func cmdBuildsDelete(c *cli.Context) error {
 
 	build := c.Args()[0]
 
+	if c.Bool("dry-run") {
+		if _, err := rackClient(c).GetBuild(app, build); err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		dryRunCall("DELETE", fmt.Sprintf("/apps/%s/builds/%s", app, build), nil)
+		return nil
+	}
+
 	b, err := rackClient(c).DeleteBuild(app, build)
 	if err != nil {
 		return stdcli.ExitError(err)
@@ -219,10 +523,341 @@ func cmdBuildsInfo(c *cli.Context) error {
 		return stdcli.ExitError(err)
 	}
 
+	elapsed := ""
+
+	if !b.Ended.IsZero() {
+		elapsed = stdcli.Duration(b.Started, b.Ended)
+	}
+
+	fmt.Printf("Id           %s\n", b.Id)
+	fmt.Printf("Status       %s\n", b.Status)
+	fmt.Printf("Release      %s\n", b.Release)
+	fmt.Printf("Description  %s\n", b.Description)
+	fmt.Printf("Started      %s\n", humanizeTime(b.Started))
+	fmt.Printf("Elapsed      %s\n", elapsed)
+	fmt.Printf("Git SHA      %s\n", b.GitSha)
+
+	if len(b.Architectures) > 0 {
+		fmt.Printf("Matrix       arch=%s\n", strings.Join(b.Architectures, ","))
+	}
+
+	fmt.Printf("Images       ")
+
+	processes := []string{}
+
+	for process := range b.Images {
+		processes = append(processes, process)
+	}
+
+	sort.Strings(processes)
+
+	images := []string{}
+
+	for _, process := range processes {
+		images = append(images, fmt.Sprintf("%s: %s", process, b.Images[process]))
+	}
+
+	fmt.Println(strings.Join(images, "\n             "))
+
+	fmt.Printf("Manifest     ")
+	fmt.Println(strings.Replace(strings.TrimSpace(b.Manifest), "\n", "\n             ", -1))
+
+	if c.Bool("timings") {
+		fmt.Println()
+		printBuildTimings(b.Timings)
+	}
+
+	if c.Bool("logs") {
+		fmt.Println()
+		fmt.Println(b.Logs)
+	}
+
+	return nil
+}
+
+// printBuildTimings prints the time spent in each stage of a build and in each of
+// its docker build steps, marking the steps that came from the layer cache
+func printBuildTimings(timings *models.BuildTimings) {
+	if timings == nil {
+		fmt.Println("No timings recorded for this build")
+		return
+	}
+
+	t := stdcli.NewTable("STAGE", "DURATION")
+
+	for _, s := range timings.Stages {
+		t.AddRow(s.Name, timingDuration(s.Duration))
+	}
+
+	t.Print()
+
+	if len(timings.Steps) == 0 {
+		return
+	}
+
+	cached := 0
+
+	fmt.Println()
+
+	t = stdcli.NewTable("SERVICE", "STEP", "CACHE", "DURATION")
+
+	for _, s := range timings.Steps {
+		cache := "miss"
+
+		if s.Cached {
+			cache = "hit"
+			cached++
+		}
+
+		step := s.Step
+
+		if len(step) > 50 {
+			step = step[0:47] + "..."
+		}
+
+		t.AddRow(s.Service, step, cache, timingDuration(s.Duration))
+	}
+
+	t.Print()
+
+	fmt.Printf("\n%d of %d steps cached\n", cached, len(timings.Steps))
+}
+
+// timingDuration shows tenths of a second for short durations, which most cached steps are
+func timingDuration(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	}
+
+	return stdcli.Duration(time.Time{}, time.Time{}.Add(d))
+}
+
+func cmdBuildsLogs(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "logs")
+		return nil
+	}
+
+	build := c.Args()[0]
+
+	b, err := rackClient(c).GetBuild(app, build)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	// a build has no end time until the builder reports its result
+	if c.Bool("follow") && b.Ended.IsZero() {
+		if _, err := finishBuild(c, app, b); err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		return nil
+	}
+
 	fmt.Println(b.Logs)
 	return nil
 }
 
+func cmdBuildsAttach(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "attach")
+		return nil
+	}
+
+	b, err := rackClient(c).GetBuild(app, c.Args()[0])
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	var release string
+
+	if b.Ended.IsZero() {
+		release, err = finishBuild(c, app, b)
+	} else {
+		fmt.Println(b.Logs)
+		release, err = waitForBuild(c, app, b.Id, 0)
+	}
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Printf("Release: %s\n", release)
+	return nil
+}
+
+func cmdBuildsWait(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "wait")
+		return nil
+	}
+
+	release, err := waitForBuild(c, app, c.Args()[0], c.Duration("timeout"))
+	if err != nil {
+		return waitExitError(err)
+	}
+
+	fmt.Printf("Release: %s\n", release)
+	return nil
+}
+
+func cmdBuildsCancel(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "cancel")
+		return nil
+	}
+
+	build := c.Args()[0]
+
+	fmt.Printf("Cancelling %s... ", build)
+
+	if _, err := rackClient(c).CancelBuild(app, build); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+	return nil
+}
+
+func cmdBuildsExport(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) != 1 || c.String("file") == "" {
+		stdcli.Usage(c, "export")
+		return nil
+	}
+
+	build := c.Args()[0]
+	file := c.String("file")
+
+	fmt.Printf("Exporting %s to %s... ", build, file)
+
+	f, err := os.Create(file)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	defer f.Close()
+
+	if err := rackClient(c).ExportBuild(app, build, f); err != nil {
+		os.Remove(file)
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+	return nil
+}
+
+func cmdBuildsImport(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) > 0 || c.String("file") == "" {
+		stdcli.Usage(c, "import")
+		return nil
+	}
+
+	f, err := os.Open(c.String("file"))
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	defer f.Close()
+
+	var b *models.Build
+
+	upload, err := uploadFile(c, c.String("file"))
+	if _, ok := err.(client.ErrNotFound); ok {
+		b, err = rackClient(c).ImportBuild(app, f, func(s string) {
+			// Pad string with spaces at the end to clear any text left over from a longer string.
+			fmt.Printf("\rImporting... %s       ", strings.TrimSpace(s))
+		})
+		if err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		fmt.Println()
+	} else if err != nil {
+		return stdcli.ExitError(err)
+	} else {
+		fmt.Printf("Importing... ")
+
+		b, err = rackClient(c).ImportBuildUpload(app, upload)
+		if err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		fmt.Println("OK")
+	}
+
+	fmt.Printf("Build: %s\n", b.Id)
+	fmt.Printf("Release: %s\n", b.Release)
+	return nil
+}
+
+func cmdBuildsPromote(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "promote")
+		return nil
+	}
+
+	build := c.Args()[0]
+
+	r, err := rackClient(c).CreateBuildRelease(app, build)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Printf("Promoting %s... ", r.Id)
+
+	if _, err := rackClient(c).PromoteRelease(app, r.Id); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("UPDATING")
+
+	if c.Bool("wait") {
+		fmt.Printf("Waiting for stabilization... ")
+
+		if err := waitForReleasePromotion(c, app, r.Id); err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		fmt.Println("OK")
+	}
+
+	return nil
+}
+
 func cmdBuildsCopy(c *cli.Context) error {
 	_, app, err := stdcli.DirApp(c, ".")
 	if err != nil {
@@ -270,12 +905,22 @@ func cmdBuildsCopy(c *cli.Context) error {
 }
 
 func executeBuild(c *cli.Context, source, app, manifest, description string) (string, error) {
-	u, _ := url.Parse(source)
+	ref := c.String("ref")
+
+	switch {
+	case remoteSource(source):
+		if ref != "" {
+			source = strings.SplitN(source, "#", 2)[0] + "#" + ref
+		}
 
-	switch u.Scheme {
-	case "http", "https":
 		return executeBuildUrl(c, source, app, manifest, description)
+	case ref != "":
+		return "", fmt.Errorf("--ref requires a git repository to build from")
 	default:
+		if err := runPreBuildHook(source); err != nil {
+			return "", err
+		}
+
 		if c.Bool("incremental") {
 			return executeBuildDirIncremental(c, source, app, manifest, description)
 		} else {
@@ -286,14 +931,76 @@ func executeBuild(c *cli.Context, source, app, manifest, description string) (st
 	return "", fmt.Errorf("unreachable")
 }
 
-func createIndex(dir string) (client.Index, error) {
-	index := client.Index{}
+// preBuildHook is a script in the app directory that is run before a local directory is uploaded,
+// i.e. to precompile assets or generate code that is not checked in
+const preBuildHook = ".convox/hooks/pre-build"
+
+// runPreBuildHook runs the pre-build hook of dir if it has one. Watched builds do not run it as
+// the files it writes would start another build.
+func runPreBuildHook(dir string) error {
+	hook := filepath.Join(dir, preBuildHook)
+
+	if _, err := os.Stat(hook); os.IsNotExist(err) {
+		return nil
+	}
+
+	abs, err := filepath.Abs(hook)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Running pre-build hook...")
+
+	cmd := exec.Command(abs)
+	cmd.Dir = dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("pre-build hook failed: %s", err)
+	}
+
+	return nil
+}
+
+// remoteSource returns true if source is a git repository or url the rack fetches itself
+// rather than a local directory to upload
+func remoteSource(source string) bool {
+	if scpGitSource.MatchString(source) {
+		return true
+	}
+
+	u, err := url.Parse(source)
+	if err != nil {
+		return false
+	}
+
+	switch u.Scheme {
+	case "http", "https", "ssh", "git", "git+ssh":
+		return true
+	}
 
+	return false
+}
+
+func createIndex(dir string) (models.Index, error) {
 	err := warnUnignoredEnv(dir)
 	if err != nil {
 		return nil, err
 	}
 
+	return indexDir(dir)
+}
+
+// indexDir hashes every file in dir that is not excluded by its .dockerignore
+func indexDir(dir string) (models.Index, error) {
+	return indexDirSize(dir, 0)
+}
+
+// indexDirSize hashes the files in dir of at least min bytes that are not excluded by its .dockerignore
+func indexDirSize(dir string, min int64) (models.Index, error) {
+	index := models.Index{}
+
 	ignore, err := readDockerIgnore(dir)
 	if err != nil {
 		return nil, err
@@ -304,7 +1011,7 @@ func createIndex(dir string) (client.Index, error) {
 		return nil, err
 	}
 
-	err = filepath.Walk(resolved, indexWalker(resolved, index, ignore))
+	err = filepath.Walk(resolved, indexWalker(resolved, index, ignore, min))
 	if err != nil {
 		return nil, err
 	}
@@ -312,7 +1019,7 @@ func createIndex(dir string) (client.Index, error) {
 	return index, nil
 }
 
-func indexWalker(root string, index client.Index, ignore []string) filepath.WalkFunc {
+func indexWalker(root string, index models.Index, ignore []string, min int64) filepath.WalkFunc {
 	return func(path string, info os.FileInfo, err error) error {
 		rel, err := filepath.Rel(root, path)
 
@@ -320,7 +1027,7 @@ func indexWalker(root string, index client.Index, ignore []string) filepath.Walk
 			return err
 		}
 
-		if info.IsDir() {
+		if info.IsDir() || info.Size() < min {
 			return nil
 		}
 
@@ -333,6 +1040,9 @@ func indexWalker(root string, index client.Index, ignore []string) filepath.Walk
 			return nil
 		}
 
+		// the rack unpacks builds on linux so index names always use forward slashes
+		rel = filepath.ToSlash(rel)
+
 		data, err := ioutil.ReadFile(path)
 		if err != nil {
 			return err
@@ -341,9 +1051,9 @@ func indexWalker(root string, index client.Index, ignore []string) filepath.Walk
 		sum := sha256.Sum256(data)
 		hash := hex.EncodeToString([]byte(sum[:]))
 
-		index[hash] = client.IndexItem{
+		index[hash] = models.IndexItem{
 			Name:    rel,
-			Mode:    info.Mode(),
+			Mode:    indexMode(info.Mode()),
 			ModTime: info.ModTime(),
 			Size:    len(data),
 		}
@@ -367,10 +1077,15 @@ func readDockerIgnore(dir string) ([]string, error) {
 		return nil, err
 	}
 
+	// patterns are read with forward slashes but matched against paths using the local separator
+	for i := range ignore {
+		ignore[i] = filepath.FromSlash(ignore[i])
+	}
+
 	return ignore, nil
 }
 
-func uploadIndex(c *cli.Context, index client.Index) error {
+func uploadIndex(c *cli.Context, dir string, index models.Index) error {
 	missing, err := rackClient(c).IndexMissing(index)
 	if err != nil {
 		return err
@@ -392,7 +1107,7 @@ func uploadIndex(c *cli.Context, index client.Index) error {
 	tw := tar.NewWriter(gz)
 
 	for _, m := range missing {
-		data, err := ioutil.ReadFile(index[m].Name)
+		data, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(index[m].Name)))
 		if err != nil {
 			return err
 		}
@@ -434,6 +1149,20 @@ func uploadIndex(c *cli.Context, index client.Index) error {
 	return nil
 }
 
+// buildOptions collects the build settings given on the command line
+func buildOptions(c *cli.Context, manifest, description string) client.BuildOptions {
+	return client.BuildOptions{
+		Cache:       !c.Bool("no-cache"),
+		Manifest:    manifest,
+		Description: description,
+		Priority:    c.String("priority"),
+		Concurrency: c.Int("concurrency"),
+		Strict:      c.String("strict"),
+		BuildArgs:   c.StringSlice("build-arg"),
+		Matrix:      c.String("matrix"),
+	}
+}
+
 func executeBuildDirIncremental(c *cli.Context, dir, app, manifest, description string) (string, error) {
 	system, err := rackClient(c).GetSystem()
 	if err != nil {
@@ -445,8 +1174,6 @@ func executeBuildDirIncremental(c *cli.Context, dir, app, manifest, description
 		return executeBuildDir(c, dir, app, manifest, description)
 	}
 
-	cache := !c.Bool("no-cache")
-
 	dir, err = filepath.Abs(dir)
 	if err != nil {
 		return "", err
@@ -461,14 +1188,14 @@ func executeBuildDirIncremental(c *cli.Context, dir, app, manifest, description
 
 	fmt.Println("OK")
 
-	err = uploadIndex(c, index)
+	err = uploadIndex(c, dir, index)
 	if err != nil {
 		return "", err
 	}
 
 	fmt.Printf("Starting build... ")
 
-	build, err := rackClient(c).CreateBuildIndex(app, index, cache, manifest, description)
+	build, err := rackClient(c).CreateBuildIndex(app, index, buildOptions(c, manifest, description))
 	if err != nil {
 		return "", err
 	}
@@ -489,18 +1216,64 @@ func executeBuildDir(c *cli.Context, dir, app, manifest, description string) (st
 		return "", err
 	}
 
-	fmt.Print("Creating tarball... ")
+	blobs, err := sourceBlobs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	if len(blobs) > 0 {
+		if err := uploadIndex(c, dir, blobs); err != nil {
+			return "", err
+		}
+	}
 
-	tar, err := createTarball(dir)
+	tar, err := createTarball(dir, blobs)
 	if err != nil {
 		return "", err
 	}
 
-	fmt.Println("OK")
+	defer tar.Close()
 
-	cache := !c.Bool("no-cache")
+	// the tarball is written out so an interrupted upload can resume from the same bytes
+	tmp, err := ioutil.TempFile("", "source")
+	if err != nil {
+		return "", err
+	}
+
+	defer os.Remove(tmp.Name())
 
-	build, err := rackClient(c).CreateBuildSourceProgress(app, tar, cache, manifest, description, func(s string) {
+	_, err = io.Copy(tmp, tar)
+	tmp.Close()
+	if err != nil {
+		return "", err
+	}
+
+	upload, err := uploadFile(c, tmp.Name())
+	if _, ok := err.(client.ErrNotFound); ok {
+		return executeBuildSource(c, tmp.Name(), app, blobs, manifest, description)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	build, err := rackClient(c).CreateBuildUpload(app, upload, blobs, buildOptions(c, manifest, description))
+	if err != nil {
+		return "", err
+	}
+
+	return finishBuild(c, app, build)
+}
+
+// executeBuildSource streams a source tarball to racks that do not support chunked uploads
+func executeBuildSource(c *cli.Context, file, app string, blobs models.Index, manifest, description string) (string, error) {
+	source, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+
+	defer source.Close()
+
+	build, err := rackClient(c).CreateBuildSourceBlobs(app, source, blobs, buildOptions(c, manifest, description), func(s string) {
 		// Pad string with spaces at the end to clear any text left over from a longer string.
 		fmt.Printf("\rUploading... %s       ", strings.TrimSpace(s))
 	})
@@ -514,9 +1287,7 @@ func executeBuildDir(c *cli.Context, dir, app, manifest, description string) (st
 }
 
 func executeBuildUrl(c *cli.Context, url, app, manifest, description string) (string, error) {
-	cache := !c.Bool("no-cache")
-
-	build, err := rackClient(c).CreateBuildUrl(app, url, cache, manifest, description)
+	build, err := rackClient(c).CreateBuildUrl(app, url, buildOptions(c, manifest, description))
 	if err != nil {
 		return "", err
 	}
@@ -524,7 +1295,27 @@ func executeBuildUrl(c *cli.Context, url, app, manifest, description string) (st
 	return finishBuild(c, app, build)
 }
 
-func createTarball(base string) ([]byte, error) {
+// sourceBlobs indexes the files of a build source that are large enough to be worth
+// keeping in the rack index rather than uploading them with every build. Files with
+// names that would be read as patterns when excluding them from the tarball are left in it.
+func sourceBlobs(dir string) (models.Index, error) {
+	index, err := indexDirSize(dir, blobSize)
+	if err != nil {
+		return nil, err
+	}
+
+	for hash, item := range index {
+		if strings.ContainsAny(item.Name, "*?[\\") {
+			delete(index, hash)
+		}
+	}
+
+	return index, nil
+}
+
+// createTarball returns a gzipped tarball of the build context in base, leaving out the
+// files in blobs. The tarball is generated as it is read so large contexts are never held in memory.
+func createTarball(base string, blobs models.Index) (io.ReadCloser, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
 		return nil, err
@@ -541,21 +1332,14 @@ func createTarball(base string) ([]byte, error) {
 	}
 
 	var includes = []string{"."}
-	var excludes []string
 
-	dockerIgnorePath := path.Join(sym, ".dockerignore")
-	dockerIgnore, err := os.Open(dockerIgnorePath)
+	excludes, err := readDockerIgnore(sym)
 	if err != nil {
-		if !os.IsNotExist(err) {
-			return nil, err
-		}
-		//There is no docker ignore
-		excludes = make([]string, 0)
-	} else {
-		excludes, err = dockerignore.ReadAll(dockerIgnore)
-		if err != nil {
-			return nil, err
-		}
+		return nil, err
+	}
+
+	for _, item := range blobs {
+		excludes = append(excludes, filepath.FromSlash(item.Name))
 	}
 
 	// If .dockerignore mentions .dockerignore or the Dockerfile
@@ -585,20 +1369,16 @@ func createTarball(base string) ([]byte, error) {
 		return nil, err
 	}
 
-	bytes, err := ioutil.ReadAll(out)
-	if err != nil {
-		return nil, err
-	}
-
 	err = os.Chdir(cwd)
 	if err != nil {
+		out.Close()
 		return nil, err
 	}
 
-	return bytes, nil
+	return out, nil
 }
 
-func finishBuild(c *cli.Context, app string, build *client.Build) (string, error) {
+func finishBuild(c *cli.Context, app string, build *models.Build) (string, error) {
 	if build.Id == "" {
 		return "", fmt.Errorf("unable to fetch build id")
 	}
@@ -611,7 +1391,7 @@ func finishBuild(c *cli.Context, app string, build *client.Build) (string, error
 		return "", err
 	}
 
-	release, err := waitForBuild(c, app, build.Id)
+	release, err := waitForBuild(c, app, build.Id, 0)
 	if err != nil {
 		return "", err
 	}
@@ -619,7 +1399,9 @@ func finishBuild(c *cli.Context, app string, build *client.Build) (string, error
 	return release, nil
 }
 
-func waitForBuild(c *cli.Context, app, id string) (string, error) {
+// waitForBuild polls a build until it finishes, giving up after timeout unless it is 0
+func waitForBuild(c *cli.Context, app, id string, timeout time.Duration) (string, error) {
+	start := time.Now()
 
 	for {
 		build, err := rackClient(c).GetBuild(app, id)
@@ -631,11 +1413,17 @@ func waitForBuild(c *cli.Context, app, id string) (string, error) {
 		case "complete":
 			return build.Release, nil
 		case "error":
-			return "", fmt.Errorf("%s build failed", app)
+			return "", waitError{fmt.Errorf("%s build failed", app), exitWaitFailed}
 		case "failed":
-			return "", fmt.Errorf("%s build failed", app)
+			return "", waitError{fmt.Errorf("%s build failed", app), exitWaitFailed}
 		case "timeout":
-			return "", fmt.Errorf("%s build timed out", app)
+			return "", waitError{fmt.Errorf("%s build timed out", app), exitWaitTimeout}
+		case "cancelled":
+			return "", waitError{fmt.Errorf("%s build cancelled", app), exitWaitCancelled}
+		}
+
+		if timeout > 0 && time.Since(start) > timeout {
+			return "", waitError{fmt.Errorf("timeout waiting for build %s", id), exitWaitTimeout}
 		}
 
 		time.Sleep(1 * time.Second)
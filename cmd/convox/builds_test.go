@@ -1,15 +1,31 @@
 package main
 
 import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/fake"
+	"github.com/convox/rack/client/models"
 	"github.com/convox/rack/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBuildsPreventAgainstCreating(t *testing.T) {
 	ts := testServer(t,
-		test.Http{Method: "GET", Path: "/apps/foo", Code: 200, Response: client.App{Name: "foo", Status: "creating"}},
+		test.Http{Method: "GET", Path: "/apps/foo", Code: 200, Response: models.App{Name: "foo", Status: "creating"}},
 	)
 
 	defer ts.Close()
@@ -26,8 +42,8 @@ func TestBuildsPreventAgainstCreating(t *testing.T) {
 
 func TestBuildsCreateReturnsNoBuild(t *testing.T) {
 	ts := testServer(t,
-		test.Http{Method: "GET", Path: "/apps/foo", Code: 200, Response: client.App{Name: "foo", Status: "running"}},
-		test.Http{Method: "POST", Path: "/apps/foo/builds", Body: "cache=true&description=&manifest=docker-compose.yml&repo=https%3A%2F%2Fexample.org", Code: 200, Response: client.Build{}},
+		test.Http{Method: "GET", Path: "/apps/foo", Code: 200, Response: models.App{Name: "foo", Status: "running"}},
+		test.Http{Method: "POST", Path: "/apps/foo/builds", Body: "cache=true&description=&manifest=docker-compose.yml&repo=https%3A%2F%2Fexample.org", Code: 200, Response: models.Build{}},
 	)
 
 	defer ts.Close()
@@ -41,3 +57,544 @@ func TestBuildsCreateReturnsNoBuild(t *testing.T) {
 		},
 	)
 }
+
+func TestBuildsInfo(t *testing.T) {
+	started := time.Now().Add(-2 * time.Hour)
+
+	fc := fake.New()
+	fc.CreateApp("foo")
+	fc.AddBuild("foo", models.Build{
+		Id:          "BABCDEFGHI",
+		Status:      "complete",
+		Release:     "RABCDEFGHI",
+		Description: "first build",
+		GitSha:      "1a2b3c4d",
+		Images: map[string]string{
+			"web":    "registry/foo-web@sha256:2222",
+			"worker": "registry/foo-worker@sha256:3333",
+		},
+		Logs:     "building...",
+		Manifest: "web:\n  image: httpd\n",
+		Started:  started,
+		Ended:    started.Add(90 * time.Second),
+	})
+
+	fakeRuns(t, fc,
+		test.ExecRun{
+			Command: "convox builds info BABCDEFGHI --app foo",
+			Exit:    0,
+			Stdout: `Id           BABCDEFGHI
+Status       complete
+Release      RABCDEFGHI
+Description  first build
+Started      2 hours ago
+Elapsed      1m30s
+Git SHA      1a2b3c4d
+Images       web: registry/foo-web@sha256:2222
+             worker: registry/foo-worker@sha256:3333
+Manifest     web:
+               image: httpd
+`,
+		},
+		test.ExecRun{
+			Command: "convox builds info BBCDEFGHIJ --app foo",
+			Exit:    1,
+			Stderr:  "ERROR: no such build: BBCDEFGHIJ\n",
+		},
+	)
+}
+
+func TestBuildsInfoTimings(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo/builds/BABCDEFGHI", Code: 200, Response: models.Build{
+			Id:       "BABCDEFGHI",
+			Status:   "complete",
+			Manifest: "web:\n  image: httpd\n",
+			Timings: &models.BuildTimings{
+				Stages: []models.BuildStageTiming{
+					{Name: "source", Duration: 2 * time.Second},
+					{Name: "build", Duration: 95 * time.Second},
+				},
+				Steps: []models.BuildStepTiming{
+					{Service: "web", Step: "FROM ruby:2.3", Cached: false, Duration: 100 * time.Millisecond},
+					{Service: "web", Step: "RUN bundle install --jobs 4 --retry 3 --without development test", Cached: true, Duration: 300 * time.Millisecond},
+					{Service: "web", Step: "COPY . /app", Cached: false, Duration: 94 * time.Second},
+				},
+			},
+		}},
+		test.Http{Method: "GET", Path: "/apps/foo/builds/BBCDEFGHIJ", Code: 200, Response: models.Build{
+			Id:     "BBCDEFGHIJ",
+			Status: "complete",
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox builds info BABCDEFGHI --app foo --timings",
+			Exit:    0,
+			OutMatch: `
+STAGE   DURATION
+source  2.0s
+build   1m35s
+
+SERVICE  STEP                                                CACHE  DURATION
+web      FROM ruby:2.3                                       miss   0.1s
+web      RUN bundle install --jobs 4 --retry 3 --without...  hit    0.3s
+web      COPY . /app                                         miss   1m34s
+
+1 of 3 steps cached
+`,
+		},
+		test.ExecRun{
+			Command:  "convox builds info BBCDEFGHIJ --app foo --timings",
+			Exit:     0,
+			OutMatch: "\nNo timings recorded for this build\n",
+		},
+	)
+}
+
+func TestBuildsLogs(t *testing.T) {
+	fc := fake.New()
+	fc.CreateApp("foo")
+	fc.AddBuild("foo", models.Build{
+		Id:     "BABCDEFGHI",
+		Status: "complete",
+		Logs:   "building...",
+		Ended:  time.Now(),
+	})
+
+	fakeRuns(t, fc,
+		test.ExecRun{
+			Command: "convox builds logs BABCDEFGHI --app foo",
+			Exit:    0,
+			Stdout:  "building...\n",
+		},
+	)
+}
+
+func TestBuildsAttachFinished(t *testing.T) {
+	fc := fake.New()
+	fc.CreateApp("foo")
+	fc.AddBuild("foo", models.Build{
+		Id:      "BABCDEFGHI",
+		Status:  "complete",
+		Release: "RABCDEFGHI",
+		Logs:    "building...",
+		Ended:   time.Now(),
+	})
+
+	fakeRuns(t, fc,
+		test.ExecRun{
+			Command: "convox builds attach BABCDEFGHI --app foo",
+			Exit:    0,
+			Stdout:  "building...\nRelease: RABCDEFGHI\n",
+		},
+	)
+}
+
+func TestBuildsWait(t *testing.T) {
+	fc := fake.New()
+	fc.CreateApp("foo")
+	fc.AddBuild("foo", models.Build{
+		Id:      "BABCDEFGHI",
+		Status:  "complete",
+		Release: "RABCDEFGHI",
+		Ended:   time.Now(),
+	})
+
+	fakeRuns(t, fc,
+		test.ExecRun{
+			Command: "convox builds wait BABCDEFGHI --app foo",
+			Exit:    0,
+			Stdout:  "Release: RABCDEFGHI\n",
+		},
+	)
+}
+
+func TestBuildsWaitCancelled(t *testing.T) {
+	fc := fake.New()
+	fc.CreateApp("foo")
+	fc.AddBuild("foo", models.Build{
+		Id:     "BABCDEFGHI",
+		Status: "cancelled",
+		Ended:  time.Now(),
+	})
+
+	fakeRuns(t, fc,
+		test.ExecRun{
+			Command: "convox builds wait BABCDEFGHI --app foo",
+			Exit:    3,
+			Stderr:  "ERROR: foo build cancelled\n",
+		},
+	)
+}
+
+func TestBuildsCancel(t *testing.T) {
+	fc := fake.New()
+	fc.CreateApp("foo")
+	fc.AddBuild("foo", models.Build{Id: "BABCDEFGHI", Status: "running"})
+
+	fakeRuns(t, fc,
+		test.ExecRun{
+			Command: "convox builds cancel BABCDEFGHI --app foo",
+			Exit:    0,
+			Stdout:  "Cancelling BABCDEFGHI... OK\n",
+		},
+		test.ExecRun{
+			Command: "convox builds cancel BABCDEFGHI --app foo",
+			Exit:    1,
+			Stderr:  "ERROR: build has already finished: BABCDEFGHI\n",
+		},
+	)
+
+	b, err := fc.GetBuild("foo", "BABCDEFGHI")
+	require.NoError(t, err)
+	assert.Equal(t, "cancelled", b.Status)
+}
+
+func TestBuildsOutputJSON(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo/builds", Code: 200, Response: models.Builds{
+			{Id: "BABCDEFGHI", Status: "complete", Release: "RBCDEFGHIJ", Description: "first"},
+		}},
+	)
+
+	defer ts.Close()
+
+	expected := "[\n  {\n    \"desc\": \"first\",\n    \"elapsed\": \"\",\n    \"id\": \"BABCDEFGHI\",\n    \"release\": \"RBCDEFGHIJ\",\n    \"started\": \"\",\n    \"status\": \"complete\"\n  }\n]\n"
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox builds --app foo --output json",
+			Exit:    0,
+			Stdout:  expected,
+		},
+		test.ExecRun{
+			Command: "convox --output json builds --app foo",
+			Exit:    0,
+			Stdout:  expected,
+		},
+		test.ExecRun{
+			Command: "convox builds --app foo --output yaml",
+			Exit:    1,
+			Stderr:  "ERROR: unknown output format: yaml\n",
+		},
+	)
+}
+
+func TestBuildsPromote(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "POST", Path: "/apps/foo/builds/BABCDEFGHI/release", Code: 200, Response: models.Release{Id: "RBCDEFGHIJ", Build: "BABCDEFGHI"}},
+		test.Http{Method: "POST", Path: "/apps/foo/releases/RBCDEFGHIJ/promote", Code: 200, Response: models.Release{Id: "RBCDEFGHIJ", Build: "BABCDEFGHI"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox builds promote BABCDEFGHI --app foo",
+			Exit:    0,
+			Stdout:  "Promoting RBCDEFGHIJ... UPDATING\n",
+		},
+	)
+}
+
+func TestBuildsExport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "convox-export")
+	require.Nil(t, err)
+
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "build.tgz")
+
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo/builds/BABCDEFGHI/export", Code: 200, Response: "archive"},
+		test.Http{Method: "GET", Path: "/apps/foo/builds/BRUNNING/export", Code: 403, Response: client.Error{Error: "build is not complete: BRUNNING"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox builds export BABCDEFGHI -f " + file + " --app foo",
+			Exit:    0,
+			Stdout:  "Exporting BABCDEFGHI to " + file + "... OK\n",
+		},
+		test.ExecRun{
+			Command: "convox builds export BRUNNING -f " + file + ".2 --app foo",
+			Exit:    1,
+			Stdout:  "Exporting BRUNNING to " + file + ".2... ",
+			Stderr:  "ERROR: build is not complete: BRUNNING\n",
+		},
+	)
+
+	data, err := ioutil.ReadFile(file)
+	require.Nil(t, err)
+	assert.Equal(t, `"archive"`, string(data))
+
+	_, err = os.Stat(file + ".2")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestCreateIndexIgnore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "convox-index")
+	require.Nil(t, err)
+
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		".dockerignore":   "logs/*.log\n",
+		"Dockerfile":      "FROM scratch\n",
+		"logs/build.log":  "ignored\n",
+		"logs/README":     "kept\n",
+		"src/app/main.go": "package main\n",
+	}
+
+	for name, data := range files {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		require.Nil(t, os.MkdirAll(filepath.Dir(file), 0755))
+		require.Nil(t, ioutil.WriteFile(file, []byte(data), 0644))
+	}
+
+	index, err := createIndex(dir)
+	require.Nil(t, err)
+
+	names := []string{}
+
+	for _, item := range index {
+		names = append(names, item.Name)
+	}
+
+	sort.Strings(names)
+
+	assert.Equal(t, []string{".dockerignore", "Dockerfile", "logs/README", "src/app/main.go"}, names)
+}
+
+func TestSourceBlobs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "convox-blobs")
+	require.Nil(t, err)
+
+	defer os.RemoveAll(dir)
+
+	large := strings.Repeat("x", blobSize)
+
+	files := map[string]string{
+		".dockerignore":    "logs\n",
+		"Dockerfile":       "FROM scratch\n",
+		"logs/big.log":     large,
+		"vendor/model.bin": large,
+		"data/[1].bin":     large + "y",
+	}
+
+	for name, data := range files {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		require.Nil(t, os.MkdirAll(filepath.Dir(file), 0755))
+		require.Nil(t, ioutil.WriteFile(file, []byte(data), 0644))
+	}
+
+	blobs, err := sourceBlobs(dir)
+	require.Nil(t, err)
+
+	names := []string{}
+
+	for _, item := range blobs {
+		names = append(names, item.Name)
+	}
+
+	assert.Equal(t, []string{"vendor/model.bin"}, names)
+
+	tgz, err := createTarball(dir, blobs)
+	require.Nil(t, err)
+
+	defer tgz.Close()
+
+	gz, err := gzip.NewReader(tgz)
+	require.Nil(t, err)
+
+	tr := tar.NewReader(gz)
+	names = []string{}
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.Nil(t, err)
+
+		if header.Typeflag == tar.TypeReg {
+			names = append(names, header.Name)
+		}
+	}
+
+	sort.Strings(names)
+
+	assert.Equal(t, []string{".dockerignore", "Dockerfile", "data/[1].bin"}, names)
+}
+
+func TestIndexChanges(t *testing.T) {
+	before := models.Index{
+		"hash-a": models.IndexItem{Name: "Dockerfile"},
+		"hash-b": models.IndexItem{Name: "main.go"},
+		"hash-c": models.IndexItem{Name: "README"},
+	}
+
+	assert.Equal(t, []string{}, indexChanges(before, before))
+
+	after := models.Index{
+		"hash-a": models.IndexItem{Name: "Dockerfile"},
+		"hash-d": models.IndexItem{Name: "main.go"},
+		"hash-e": models.IndexItem{Name: "util.go"},
+	}
+
+	assert.Equal(t, []string{"README", "main.go", "util.go"}, indexChanges(before, after))
+}
+
+func TestBuildsCreateGitRef(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo", Code: 200, Response: models.App{Name: "foo", Status: "running"}},
+		test.Http{Method: "POST", Path: "/apps/foo/builds", Body: "cache=true&description=&manifest=docker-compose.yml&repo=git%40github.com%3Aconvox%2Frack.git%23v1.0.0", Code: 200, Response: models.Build{}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox build git@github.com:convox/rack.git --app foo --ref v1.0.0",
+			Exit:    1,
+			Stdout:  "",
+			Stderr:  "ERROR: unable to fetch build id\n",
+		},
+	)
+}
+
+func TestBuildsCreateBuildArgs(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo", Code: 200, Response: models.App{Name: "foo", Status: "running"}},
+		test.Http{Method: "POST", Path: "/apps/foo/builds", Body: "build-args=VERSION%3D1.2%0ATOKEN%3Dabc&cache=true&description=&manifest=docker-compose.yml&repo=https%3A%2F%2Fexample.org", Code: 200, Response: models.Build{}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox build https://example.org --app foo --build-arg VERSION=1.2 --build-arg TOKEN=abc",
+			Exit:    1,
+			Stdout:  "",
+			Stderr:  "ERROR: unable to fetch build id\n",
+		},
+	)
+}
+
+func TestBuildsCreateMatrix(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo", Code: 200, Response: models.App{Name: "foo", Status: "running"}},
+		test.Http{Method: "POST", Path: "/apps/foo/builds", Body: "cache=true&description=&manifest=docker-compose.yml&matrix=arch%3Damd64%2Carm64&repo=https%3A%2F%2Fexample.org", Code: 200, Response: models.Build{}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox build https://example.org --app foo --matrix arch=amd64,arm64",
+			Exit:    1,
+			Stdout:  "",
+			Stderr:  "ERROR: unable to fetch build id\n",
+		},
+	)
+}
+
+func TestBuildsCreateRefWithoutRepo(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo", Code: 200, Response: models.App{Name: "foo", Status: "running"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox build . --app foo --ref master",
+			Exit:    1,
+			Stdout:  "",
+			Stderr:  "ERROR: --ref requires a git repository to build from\n",
+		},
+	)
+}
+
+func TestRemoteSource(t *testing.T) {
+	sources := map[string]bool{
+		".":                                      false,
+		"../app":                                 false,
+		"/home/user/app":                         false,
+		"https://example.org/app.tgz":            true,
+		"https://github.com/convox/rack.git#v1":  true,
+		"ssh://git@github.com/convox/rack.git":   true,
+		"git://github.com/convox/rack.git":       true,
+		"git@github.com:convox/rack.git":         true,
+		"deploy@git.example.org:apps/site.git#a": true,
+	}
+
+	for source, remote := range sources {
+		assert.Equal(t, remote, remoteSource(source), source)
+	}
+}
+
+func TestBuildsImportResume(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/uploads/U1", Code: 200, Response: models.Upload{Id: "U1", Size: 5, Offset: 3}},
+		test.Http{Method: "PUT", Path: "/uploads/U1", Body: "de", Code: 200, Response: models.Upload{Id: "U1", Size: 5, Offset: 5}},
+		test.Http{Method: "POST", Path: "/apps/foo/builds/import", Body: "upload=U1", Code: 200, Response: models.Build{Id: "B1", Release: "R1"}},
+	)
+
+	defer ts.Close()
+
+	config, err := ioutil.TempDir("", "convox-uploads")
+	require.Nil(t, err)
+
+	defer os.RemoveAll(config)
+
+	file := filepath.Join(config, "export.tgz")
+	require.Nil(t, ioutil.WriteFile(file, []byte("abcde"), 0644))
+
+	hash := sha256.Sum256([]byte("abcde"))
+	key := fmt.Sprintf("%s/myrack/%s", os.Getenv("CONVOX_HOST"), hex.EncodeToString(hash[:]))
+	require.Nil(t, ioutil.WriteFile(filepath.Join(config, "uploads"), []byte(fmt.Sprintf(`{%q:"U1"}`, key)), 0600))
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox builds import --app foo --file " + file,
+			Env:     map[string]string{"CONVOX_CONFIG": config, "CONVOX_RACK": "myrack"},
+			Exit:    0,
+			Stdout:  "Resuming upload U1 at 3 B\n\rUploading... 3 B / 5 B       \rUploading... 5 B / 5 B       \nImporting... OK\nBuild: B1\nRelease: R1\n",
+		},
+	)
+
+	data, err := ioutil.ReadFile(filepath.Join(config, "uploads"))
+	require.Nil(t, err)
+	assert.Equal(t, "{}", string(data))
+}
+
+func TestBuildsCreatePreBuildHookFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hooks")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	require.Nil(t, os.MkdirAll(filepath.Join(dir, ".convox", "hooks"), 0755))
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, ".convox", "hooks", "pre-build"), []byte("#!/bin/sh\necho generating assets\nexit 3\n"), 0755))
+
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo", Code: 200, Response: models.App{Name: "foo", Status: "running"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: fmt.Sprintf("convox build %s --app foo", dir),
+			Exit:    1,
+			Stdout:  "Running pre-build hook...\ngenerating assets\n",
+			Stderr:  "ERROR: pre-build hook failed: exit status 3\n",
+		},
+	)
+}
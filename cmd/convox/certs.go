@@ -38,9 +38,20 @@ func init() {
 			{
 				Name:        "generate",
 				Description: "generate a certificate",
-				Usage:       "<domain> [domain...]",
+				Usage:       "<domain> [domain...] [--letsencrypt [--challenge dns|http]]",
 				Action:      cmdCertsGenerate,
-				Flags:       []cli.Flag{rackFlag},
+				Flags: []cli.Flag{
+					rackFlag,
+					cli.BoolFlag{
+						Name:  "letsencrypt",
+						Usage: "issue the certificate from Let's Encrypt and renew it automatically",
+					},
+					cli.StringFlag{
+						Name:  "challenge",
+						Value: "dns",
+						Usage: "how to prove control of the domains to Let's Encrypt: dns with a Route53 record, or http served by the rack balancer with dns for custom domains of apps",
+					},
+				},
 			},
 		},
 	})
@@ -77,36 +88,46 @@ func cmdCertsCreate(c *cli.Context) error {
 		return nil
 	}
 
-	pub, err := ioutil.ReadFile(c.Args()[0])
+	pub, key, chain, err := readCertificate(c.Args()[0], c.Args()[1], c.String("chain"))
 	if err != nil {
 		return stdcli.ExitError(err)
 	}
 
-	key, err := ioutil.ReadFile(c.Args()[1])
+	fmt.Printf("Uploading certificate... ")
+
+	cert, err := rackClient(c).CreateCertificate(pub, key, chain)
 	if err != nil {
 		return stdcli.ExitError(err)
 	}
 
+	fmt.Printf("OK, %s\n", cert.Id)
+	return nil
+}
+
+// readCertificate reads a certificate, its key and an optional intermediate chain from files
+func readCertificate(pubFile, keyFile, chainFile string) (string, string, string, error) {
+	pub, err := ioutil.ReadFile(pubFile)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	key, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return "", "", "", err
+	}
+
 	chain := ""
 
-	if chainFile := c.String("chain"); chainFile != "" {
+	if chainFile != "" {
 		data, err := ioutil.ReadFile(chainFile)
 		if err != nil {
-			return stdcli.ExitError(err)
+			return "", "", "", err
 		}
 
 		chain = string(data)
 	}
 
-	fmt.Printf("Uploading certificate... ")
-
-	cert, err := rackClient(c).CreateCertificate(string(pub), string(key), chain)
-	if err != nil {
-		return stdcli.ExitError(err)
-	}
-
-	fmt.Printf("OK, %s\n", cert.Id)
-	return nil
+	return string(pub), string(key), chain, nil
 }
 
 func cmdCertsDelete(c *cli.Context) error {
@@ -132,6 +153,18 @@ func cmdCertsGenerate(c *cli.Context) error {
 		return nil
 	}
 
+	if c.Bool("letsencrypt") {
+		fmt.Printf("Issuing certificate from Let's Encrypt... ")
+
+		cert, err := rackClient(c).GenerateLetsEncryptCertificate(c.Args(), c.String("challenge"))
+		if err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		fmt.Printf("OK, %s\n", cert.Id)
+		return nil
+	}
+
 	fmt.Printf("Requesting certificate... ")
 
 	cert, err := rackClient(c).GenerateCertificate(c.Args())
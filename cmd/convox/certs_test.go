@@ -0,0 +1,96 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCerts(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/certificates", Code: 200, Response: models.Certificates{
+			{Id: "acm-0123456789ab", Domain: "example.org"},
+			{Id: "cert-1234", Domain: "foo.example.org"},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox certs",
+			Exit:    0,
+			Stdout:  "ID                DOMAIN           EXPIRES\nacm-0123456789ab  example.org\ncert-1234         foo.example.org\n",
+		},
+	)
+}
+
+func TestCertsCreate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "convox-certs")
+	require.Nil(t, err)
+
+	defer os.RemoveAll(dir)
+
+	pub := filepath.Join(dir, "cert.pub")
+	key := filepath.Join(dir, "cert.key")
+
+	require.Nil(t, ioutil.WriteFile(pub, []byte("PUB"), 0644))
+	require.Nil(t, ioutil.WriteFile(key, []byte("KEY"), 0600))
+
+	ts := testServer(t,
+		test.Http{Method: "POST", Path: "/certificates", Body: "chain=&private=KEY&public=PUB", Code: 200, Response: models.Certificate{Id: "cert-1234"}},
+		test.Http{Method: "DELETE", Path: "/certificates/cert-1234", Code: 200, Response: map[string]bool{"success": true}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox certs create " + pub + " " + key,
+			Exit:    0,
+			Stdout:  "Uploading certificate... OK, cert-1234\n",
+		},
+		test.ExecRun{
+			Command: "convox certs delete cert-1234",
+			Exit:    0,
+			Stdout:  "Removing certificate... OK\n",
+		},
+	)
+}
+
+func TestCertsGenerate(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "POST", Path: "/certificates/generate", Body: "domains=example.org", Code: 200, Response: models.Certificate{Id: "acm-0123456789ab"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox certs generate example.org",
+			Exit:    0,
+			Stdout:  "Requesting certificate... OK, acm-0123456789ab\n",
+		},
+	)
+}
+
+func TestCertsGenerateLetsEncrypt(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "POST", Path: "/certificates/generate", Body: "challenge=http&domains=example.org%2Cwww.example.org&letsencrypt=true", Code: 200, Response: models.Certificate{Id: "cert-1234"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox certs generate example.org www.example.org --letsencrypt --challenge http",
+			Exit:    0,
+			Stdout:  "Issuing certificate from Let's Encrypt... OK, cert-1234\n",
+		},
+	)
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/cmd/convox/stdcli"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func init() {
+	stdcli.RegisterCommand(cli.Command{
+		Name:        "crashes",
+		Description: "list crashed processes of an app",
+		Usage:       "",
+		Action:      cmdCrashes,
+		Flags:       []cli.Flag{appFlag, rackFlag},
+		Subcommands: []cli.Command{
+			{
+				Name:        "info",
+				Description: "show a crash and the last output of its process",
+				Usage:       "<ID>",
+				Action:      cmdCrashInfo,
+				Flags:       []cli.Flag{appFlag, rackFlag},
+			},
+			{
+				Name:        "dump",
+				Description: "download the core dump or heap snapshot captured with a crash",
+				Usage:       "<ID> --file <crash.tar>",
+				Action:      cmdCrashDump,
+				Flags: []cli.Flag{
+					appFlag,
+					rackFlag,
+					cli.StringFlag{
+						Name:  "file",
+						Usage: "file to write the tar archive of the dump to",
+					},
+				},
+			},
+		},
+	})
+}
+
+func cmdCrashes(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) > 0 {
+		return stdcli.ExitError(fmt.Errorf("`convox crashes` does not take arguments. Perhaps you meant `convox crashes info`?"))
+	}
+
+	crashes, err := rackClient(c).GetCrashes(app)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(crashes) == 0 {
+		fmt.Println("No crashes captured. Add the convox.crash.capture label to a process to capture its crashes.")
+		return nil
+	}
+
+	t := stdcli.NewTable("ID", "PROCESS", "RELEASE", "INSTANCE", "EXIT", "CRASHED", "DUMP")
+
+	for _, cr := range crashes {
+		t.AddRow(cr.Id, cr.Process, cr.Release, cr.Instance, crashExit(cr), humanizeTime(cr.Crashed), crashDump(cr))
+	}
+
+	t.Print()
+	return nil
+}
+
+func cmdCrashInfo(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "info")
+		return nil
+	}
+
+	cr, err := rackClient(c).GetCrash(app, c.Args()[0])
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Printf("Id        %s\n", cr.Id)
+	fmt.Printf("Process   %s\n", cr.Process)
+	fmt.Printf("Release   %s\n", cr.Release)
+	fmt.Printf("Instance  %s\n", cr.Instance)
+	fmt.Printf("Exit      %s\n", crashExit(*cr))
+	fmt.Printf("Started   %s\n", humanizeTime(cr.Started))
+	fmt.Printf("Crashed   %s\n", humanizeTime(cr.Crashed))
+	fmt.Printf("Dump      %s\n", crashDump(*cr))
+
+	if cr.DumpError != "" {
+		fmt.Printf("Error     %s\n", cr.DumpError)
+	}
+
+	if cr.Output != "" {
+		fmt.Printf("\n%s", cr.Output)
+	}
+
+	return nil
+}
+
+func cmdCrashDump(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) != 1 || c.String("file") == "" {
+		stdcli.Usage(c, "dump")
+		return nil
+	}
+
+	id := c.Args()[0]
+	file := c.String("file")
+
+	fmt.Printf("Downloading dump of %s to %s... ", id, file)
+
+	f, err := os.Create(file)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	defer f.Close()
+
+	if err := rackClient(c).DownloadCrashDump(app, id, f); err != nil {
+		os.Remove(file)
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+	return nil
+}
+
+func crashExit(cr models.Crash) string {
+	if cr.OOMKilled {
+		return fmt.Sprintf("%d (out of memory)", cr.ExitCode)
+	}
+
+	return strconv.Itoa(cr.ExitCode)
+}
+
+func crashDump(cr models.Crash) string {
+	if cr.Dump == "" {
+		return "none"
+	}
+
+	return cr.Dump
+}
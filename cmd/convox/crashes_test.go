@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/test"
+)
+
+func TestCrashes(t *testing.T) {
+	crashed := time.Now().UTC().Add(-2 * time.Minute)
+
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo/crashes", Code: 200, Response: models.Crashes{
+			{Id: "0123456789ab", Process: "web", Release: "RABCDEF", Instance: "i-1234", ExitCode: 137, OOMKilled: true, Crashed: crashed},
+			{Id: "ba9876543210", Process: "worker", Release: "RABCDEF", Instance: "i-5678", ExitCode: 2, Dump: "/tmp/core", Crashed: crashed},
+		}},
+		test.Http{Method: "GET", Path: "/apps/bar/crashes", Code: 200, Response: models.Crashes{}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox crashes --app foo",
+			Exit:    0,
+			Stdout:  "ID            PROCESS  RELEASE  INSTANCE  EXIT                 CRASHED        DUMP\n0123456789ab  web      RABCDEF  i-1234    137 (out of memory)  2 minutes ago  none\nba9876543210  worker   RABCDEF  i-5678    2                    2 minutes ago  /tmp/core\n",
+		},
+		test.ExecRun{
+			Command: "convox crashes --app bar",
+			Exit:    0,
+			Stdout:  "No crashes captured. Add the convox.crash.capture label to a process to capture its crashes.\n",
+		},
+	)
+}
+
+func TestCrashesInfo(t *testing.T) {
+	crashed := time.Now().UTC().Add(-2 * time.Minute)
+
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo/crashes/0123456789ab", Code: 200, Response: models.Crash{
+			Id: "0123456789ab", Process: "web", Release: "RABCDEF", Instance: "i-1234", ExitCode: 2, Output: "panic: boom\n", Started: crashed, Crashed: crashed,
+		}},
+		test.Http{Method: "GET", Path: "/apps/foo/crashes/ba9876543210", Code: 404, Response: client.Error{Error: "no such crash: ba9876543210"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox crashes info 0123456789ab --app foo",
+			Exit:    0,
+			Stdout:  "Id        0123456789ab\nProcess   web\nRelease   RABCDEF\nInstance  i-1234\nExit      2\nStarted   2 minutes ago\nCrashed   2 minutes ago\nDump      none\n\npanic: boom\n",
+		},
+		test.ExecRun{
+			Command: "convox crashes info ba9876543210 --app foo",
+			Exit:    1,
+			Stderr:  "ERROR: no such crash: ba9876543210\n",
+		},
+	)
+}
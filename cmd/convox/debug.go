@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/convox/rack/cmd/convox/stdcli"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func init() {
+	stdcli.RegisterCommand(cli.Command{
+		Name:        "debug",
+		Description: "debug running processes",
+		Usage:       "<command>",
+		Action:      cmdDebug,
+		Subcommands: []cli.Command{
+			{
+				Name:        "attach",
+				Description: "start a debugging container that shares the network and processes of a running process",
+				Usage:       "<pid> [command] [--image nicolaka/netshoot]",
+				Action:      cmdDebugAttach,
+				Flags: []cli.Flag{
+					appFlag,
+					rackFlag,
+					cli.StringFlag{
+						Name:  "image",
+						Value: "nicolaka/netshoot",
+						Usage: "image with the debugging tools to run",
+					},
+				},
+			},
+		},
+	})
+}
+
+func cmdDebug(c *cli.Context) error {
+	stdcli.Usage(c, "debug")
+	return nil
+}
+
+func cmdDebugAttach(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) < 1 {
+		stdcli.Usage(c, "attach")
+		return nil
+	}
+
+	pid := c.Args()[0]
+
+	restore, w, h, err := attachTerminal()
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	code, err := rackClient(c).DebugProcessAttached(app, pid, c.String("image"), strings.Join(c.Args()[1:], " "), os.Stdin, os.Stdout, h, w)
+	restore()
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	return cli.NewExitError("", code)
+}
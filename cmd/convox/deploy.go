@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"time"
 
 	"gopkg.in/urfave/cli.v1"
 
@@ -20,6 +21,11 @@ func init() {
 				Name:  "wait",
 				Usage: "wait for release to finish promoting before returning",
 			},
+			cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "with --wait, roll back a promotion that has not finished after a duration",
+				Value: 30 * time.Minute,
+			},
 		),
 	})
 }
@@ -73,8 +79,20 @@ func cmdDeploy(c *cli.Context) error {
 	if c.Bool("wait") {
 		fmt.Printf("Waiting for %s... ", release)
 
-		if err := waitForReleasePromotion(c, app, release); err != nil {
-			return stdcli.ExitError(err)
+		err := waitForAppRunningTimeout(c, app, c.Duration("timeout"))
+
+		// a promotion that never converges is cancelled so cloudformation rolls it back
+		if we, ok := err.(waitError); ok && we.code == exitWaitTimeout {
+			fmt.Println("TIMEOUT")
+
+			if err := rollbackDeploy(c, app, a.Release); err != nil {
+				return stdcli.ExitError(err)
+			}
+
+			return waitExitError(waitError{fmt.Errorf("%s did not finish promoting within %s", release, c.Duration("timeout")), exitWaitFailed})
+		}
+		if err != nil {
+			return waitExitError(err)
 		}
 
 		fmt.Println("OK")
@@ -82,3 +100,32 @@ func cmdDeploy(c *cli.Context) error {
 
 	return nil
 }
+
+// rollbackDeploy cancels the update of an app and waits for it to return to release
+func rollbackDeploy(c *cli.Context, app, release string) error {
+	fmt.Printf("Rolling back to %s... ", release)
+
+	if err := rackClient(c).CancelAppUpdate(app); err != nil {
+		return err
+	}
+
+	timeout := time.After(30 * time.Minute)
+	tick := time.Tick(5 * time.Second)
+
+	for {
+		select {
+		case <-tick:
+			a, err := rackClient(c).GetApp(app)
+			if err != nil {
+				return err
+			}
+
+			if a.Status == "running" {
+				fmt.Println("OK")
+				return nil
+			}
+		case <-timeout:
+			return fmt.Errorf("timeout waiting for %s to roll back", app)
+		}
+	}
+}
@@ -3,13 +3,13 @@ package main
 import (
 	"testing"
 
-	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/models"
 	"github.com/convox/rack/test"
 )
 
 func TestDeployPreventAgainstCreating(t *testing.T) {
 	ts := testServer(t,
-		test.Http{Method: "GET", Path: "/apps/foo", Code: 200, Response: client.App{Name: "foo", Status: "creating"}},
+		test.Http{Method: "GET", Path: "/apps/foo", Code: 200, Response: models.App{Name: "foo", Status: "creating"}},
 	)
 
 	defer ts.Close()
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/cmd/convox/stdcli"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func init() {
+	dotFlag := cli.BoolFlag{
+		Name:  "dot",
+		Usage: "print the graph in graphviz DOT format",
+	}
+
+	stdcli.RegisterCommand(cli.Command{
+		Name:        "deps",
+		Description: "manage dependencies between apps and resources",
+		Usage:       "",
+		Action:      cmdDepsGraph,
+		Flags:       []cli.Flag{rackFlag, dotFlag},
+		Subcommands: []cli.Command{
+			{
+				Name:        "add",
+				Description: "declare that an app depends on other apps or resources",
+				Usage:       "<app> --on <app|resource>[,<app|resource>...]",
+				Action:      cmdDepsAdd,
+				Flags: []cli.Flag{
+					rackFlag,
+					cli.StringFlag{
+						Name:  "on",
+						Usage: "comma-separated apps and resources the app depends on",
+					},
+				},
+			},
+			{
+				Name:        "remove",
+				Description: "remove a dependency of an app",
+				Usage:       "<app> <app|resource>",
+				Action:      cmdDepsRemove,
+				Flags:       []cli.Flag{rackFlag},
+			},
+			{
+				Name:        "graph",
+				Description: "show every declared dependency",
+				Usage:       "",
+				Action:      cmdDepsGraph,
+				Flags:       []cli.Flag{rackFlag, dotFlag},
+			},
+			{
+				Name:        "impact",
+				Description: "show the apps affected by promoting or deleting an app or resource",
+				Usage:       "<app|resource>",
+				Action:      cmdDepsImpact,
+				Flags:       []cli.Flag{rackFlag, dotFlag},
+			},
+		},
+	})
+}
+
+func cmdDepsAdd(c *cli.Context) error {
+	if len(c.Args()) != 1 || c.String("on") == "" {
+		stdcli.Usage(c, "add")
+		return nil
+	}
+
+	app := c.Args()[0]
+
+	fmt.Printf("Adding dependencies of %s... ", app)
+
+	if _, err := rackClient(c).AddDependencies(app, strings.Split(c.String("on"), ",")); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+	return nil
+}
+
+func cmdDepsRemove(c *cli.Context) error {
+	if len(c.Args()) != 2 {
+		stdcli.Usage(c, "remove")
+		return nil
+	}
+
+	app := c.Args()[0]
+	name := c.Args()[1]
+
+	fmt.Printf("Removing dependency of %s on %s... ", app, name)
+
+	if _, err := rackClient(c).RemoveDependency(app, name); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+	return nil
+}
+
+func cmdDepsGraph(c *cli.Context) error {
+	if len(c.Args()) > 0 {
+		return stdcli.ExitError(fmt.Errorf("`convox deps graph` does not take arguments"))
+	}
+
+	deps, err := rackClient(c).GetDependencies()
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	apps := []string{}
+
+	for app := range deps {
+		apps = append(apps, app)
+	}
+
+	sort.Strings(apps)
+
+	if c.Bool("dot") {
+		edges := [][2]string{}
+
+		for _, app := range apps {
+			for _, dep := range deps[app] {
+				edges = append(edges, [2]string{app, dep})
+			}
+		}
+
+		printDot(edges)
+		return nil
+	}
+
+	t := stdcli.NewTable("APP", "DEPENDS ON")
+
+	for _, app := range apps {
+		t.AddRow(app, strings.Join(deps[app], ", "))
+	}
+
+	t.Print()
+	return nil
+}
+
+func cmdDepsImpact(c *cli.Context) error {
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "impact")
+		return nil
+	}
+
+	name := c.Args()[0]
+
+	impacts, err := rackClient(c).GetDependencyImpact(name)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if c.Bool("dot") {
+		printDot(impactEdges(impacts))
+		return nil
+	}
+
+	if len(impacts) == 0 && !stdcli.OutputJSON() {
+		fmt.Printf("No apps depend on %s\n", name)
+		return nil
+	}
+
+	t := stdcli.NewTable("APP", "PATH")
+
+	for _, impact := range impacts {
+		t.AddRow(impact.App, strings.Join(impact.Path, " -> "))
+	}
+
+	t.Print()
+	return nil
+}
+
+// impactEdges returns each dependency along the paths of impacts once
+func impactEdges(impacts models.DependencyImpacts) [][2]string {
+	seen := map[[2]string]bool{}
+	edges := [][2]string{}
+
+	for _, impact := range impacts {
+		for i := 0; i < len(impact.Path)-1; i++ {
+			edge := [2]string{impact.Path[i], impact.Path[i+1]}
+
+			if !seen[edge] {
+				seen[edge] = true
+				edges = append(edges, edge)
+			}
+		}
+	}
+
+	return edges
+}
+
+func printDot(edges [][2]string) {
+	fmt.Println("digraph dependencies {")
+
+	for _, edge := range edges {
+		fmt.Printf("  %q -> %q;\n", edge[0], edge[1])
+	}
+
+	fmt.Println("}")
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/test"
+)
+
+func TestDepsGraph(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/dependencies", Code: 200, Response: models.Dependencies{
+			"web": {"api"},
+			"api": {"postgres-123", "redis-456"},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox deps graph",
+			Exit:    0,
+			Stdout:  "APP  DEPENDS ON\napi  postgres-123, redis-456\nweb  api\n",
+		},
+		test.ExecRun{
+			Command: "convox deps graph --dot",
+			Exit:    0,
+			Stdout:  "digraph dependencies {\n  \"api\" -> \"postgres-123\";\n  \"api\" -> \"redis-456\";\n  \"web\" -> \"api\";\n}\n",
+		},
+	)
+}
+
+func TestDepsImpact(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/dependencies/postgres-123/impact", Code: 200, Response: models.DependencyImpacts{
+			{App: "api", Path: []string{"api", "postgres-123"}},
+			{App: "web", Path: []string{"web", "api", "postgres-123"}},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox deps impact postgres-123",
+			Exit:    0,
+			Stdout:  "APP  PATH\napi  api -> postgres-123\nweb  web -> api -> postgres-123\n",
+		},
+	)
+}
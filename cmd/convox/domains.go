@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/convox/rack/cmd/convox/stdcli"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func init() {
+	stdcli.RegisterCommand(cli.Command{
+		Name:        "domains",
+		Description: "manage custom domains of an app",
+		Usage:       "",
+		Action:      cmdDomains,
+		Flags:       []cli.Flag{appFlag, rackFlag},
+		Subcommands: []cli.Command{
+			{
+				Name:        "add",
+				Description: "point a domain in a route53 hosted zone at the load balancer of an app",
+				Usage:       "<domain> [--process web]",
+				Action:      cmdDomainAdd,
+				Flags: []cli.Flag{
+					appFlag,
+					rackFlag,
+					cli.StringFlag{
+						Name:  "process",
+						Usage: "process whose load balancer the domain points at, required when the app has more than one",
+					},
+				},
+			},
+			{
+				Name:        "remove",
+				Description: "remove the dns record of a domain",
+				Usage:       "<domain>",
+				Action:      cmdDomainRemove,
+				Flags:       []cli.Flag{appFlag, rackFlag},
+			},
+		},
+	})
+}
+
+func cmdDomains(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) > 0 {
+		return stdcli.ExitError(fmt.Errorf("`convox domains` does not take arguments. Perhaps you meant `convox domains add`?"))
+	}
+
+	domains, err := rackClient(c).GetDomains(app)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	t := stdcli.NewTable("DOMAIN", "PROCESS", "TARGET", "STATUS")
+
+	for _, d := range domains {
+		t.AddRow(d.Domain, d.Process, d.Target, d.Status)
+	}
+
+	t.Print()
+	return nil
+}
+
+func cmdDomainAdd(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "add")
+		return nil
+	}
+
+	domain := c.Args()[0]
+
+	fmt.Printf("Adding %s... ", domain)
+
+	d, err := rackClient(c).AddDomain(app, domain, c.String("process"))
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Printf("OK, %s -> %s\n", d.Domain, d.Target)
+	return nil
+}
+
+func cmdDomainRemove(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "remove")
+		return nil
+	}
+
+	domain := c.Args()[0]
+
+	fmt.Printf("Removing %s... ", domain)
+
+	if err := rackClient(c).RemoveDomain(app, domain); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+	return nil
+}
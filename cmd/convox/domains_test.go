@@ -0,0 +1,74 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/test"
+)
+
+func TestDomains(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo/domains", Code: 200, Response: models.Domains{
+			{Domain: "example.org", Process: "web", Target: "foo-web.us-east-1.elb.amazonaws.com", Status: "active"},
+			{Domain: "www.example.org", Process: "web", Target: "foo-web.us-east-1.elb.amazonaws.com", Status: "pending"},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox domains --app foo",
+			Exit:    0,
+			Stdout:  "DOMAIN           PROCESS  TARGET                               STATUS\nexample.org      web      foo-web.us-east-1.elb.amazonaws.com  active\nwww.example.org  web      foo-web.us-east-1.elb.amazonaws.com  pending\n",
+		},
+	)
+}
+
+func TestDomainsAdd(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "POST", Path: "/apps/foo/domains", Body: "domain=example.org&process=web", Code: 200, Response: models.Domain{Domain: "example.org", Target: "foo-web.us-east-1.elb.amazonaws.com"}},
+		test.Http{Method: "POST", Path: "/apps/bar/domains", Body: "domain=example.org", Code: 403, Response: client.Error{Error: "process must be specified, app has load balancers for: admin, web"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox domains add example.org --process web --app foo",
+			Exit:    0,
+			Stdout:  "Adding example.org... OK, example.org -> foo-web.us-east-1.elb.amazonaws.com\n",
+		},
+		test.ExecRun{
+			Command: "convox domains add example.org --app bar",
+			Exit:    1,
+			Stdout:  "Adding example.org... ",
+			Stderr:  "ERROR: process must be specified, app has load balancers for: admin, web\n",
+		},
+	)
+}
+
+func TestDomainsRemove(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "DELETE", Path: "/apps/foo/domains/example.org", Code: 200, Response: map[string]bool{"success": true}},
+		test.Http{Method: "DELETE", Path: "/apps/foo/domains/missing.org", Code: 404, Response: client.Error{Error: "no such domain: missing.org"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox domains remove example.org --app foo",
+			Exit:    0,
+			Stdout:  "Removing example.org... OK\n",
+		},
+		test.ExecRun{
+			Command: "convox domains remove missing.org --app foo",
+			Exit:    1,
+			Stdout:  "Removing missing.org... ",
+			Stderr:  "ERROR: no such domain: missing.org\n",
+		},
+	)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/convox/rack/cmd/convox/stdcli"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func init() {
+	stdcli.RegisterCommand(cli.Command{
+		Name:        "drains",
+		Description: "manage log drains of an app",
+		Usage:       "",
+		Action:      cmdDrains,
+		Flags:       []cli.Flag{appFlag, rackFlag},
+		Subcommands: []cli.Command{
+			{
+				Name:        "add",
+				Description: "forward the logs of an app to a syslog, http or kinesis endpoint",
+				Usage:       "<url>\n\nurl is one of syslog+tls://host:port, syslog+tcp://host:port, syslog+udp://host:port, https://host/path or kinesis://stream",
+				Action:      cmdDrainAdd,
+				Flags:       []cli.Flag{appFlag, rackFlag},
+			},
+			{
+				Name:        "remove",
+				Description: "stop forwarding logs to a drain",
+				Usage:       "<ID>",
+				Action:      cmdDrainRemove,
+				Flags:       []cli.Flag{appFlag, rackFlag},
+			},
+		},
+	})
+}
+
+func cmdDrains(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) > 0 {
+		return stdcli.ExitError(fmt.Errorf("`convox drains` does not take arguments. Perhaps you meant `convox drains add`?"))
+	}
+
+	drains, err := rackClient(c).GetDrains(app)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	t := stdcli.NewTable("ID", "URL", "STATUS", "ERROR")
+
+	for _, d := range drains {
+		t.AddRow(d.Id, d.URL, d.Status, d.Error)
+	}
+
+	t.Print()
+	return nil
+}
+
+func cmdDrainAdd(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "add")
+		return nil
+	}
+
+	url := c.Args()[0]
+
+	fmt.Printf("Adding drain to %s... ", url)
+
+	d, err := rackClient(c).CreateDrain(app, url)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println(d.Id)
+	return nil
+}
+
+func cmdDrainRemove(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "remove")
+		return nil
+	}
+
+	id := c.Args()[0]
+
+	fmt.Printf("Removing drain %s... ", id)
+
+	if _, err := rackClient(c).DeleteDrain(app, id); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+	return nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/test"
+)
+
+func TestDrains(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo/drains", Code: 200, Response: models.Drains{
+			{Id: "D1234", URL: "syslog+tls://logs.papertrailapp.com:12345", Status: "active"},
+			{Id: "D5678", URL: "kinesis://app-logs", Status: "failing", Error: "kinesis rejected 2 records"},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox drains --app foo",
+			Exit:    0,
+			Stdout:  "ID     URL                                        STATUS   ERROR\nD1234  syslog+tls://logs.papertrailapp.com:12345  active\nD5678  kinesis://app-logs                         failing  kinesis rejected 2 records\n",
+		},
+	)
+}
+
+func TestDrainsAdd(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "POST", Path: "/apps/foo/drains", Body: "url=syslog%2Btls%3A%2F%2Flogs.papertrailapp.com%3A12345", Code: 200, Response: models.Drain{Id: "D1234"}},
+		test.Http{Method: "POST", Path: "/apps/bar/drains", Body: "url=ftp%3A%2F%2Fexample.org", Code: 403, Response: client.Error{Error: "url scheme must be one of: http, https, kinesis, syslog, syslog+tcp, syslog+tls, syslog+udp"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox drains add syslog+tls://logs.papertrailapp.com:12345 --app foo",
+			Exit:    0,
+			Stdout:  "Adding drain to syslog+tls://logs.papertrailapp.com:12345... D1234\n",
+		},
+		test.ExecRun{
+			Command: "convox drains add ftp://example.org --app bar",
+			Exit:    1,
+			Stdout:  "Adding drain to ftp://example.org... ",
+			Stderr:  "ERROR: url scheme must be one of: http, https, kinesis, syslog, syslog+tcp, syslog+tls, syslog+udp\n",
+		},
+	)
+}
+
+func TestDrainsRemove(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "DELETE", Path: "/apps/foo/drains/D1234", Code: 200, Response: models.Drain{Id: "D1234"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox drains remove D1234 --app foo",
+			Exit:    0,
+			Stdout:  "Removing drain D1234... OK\n",
+		},
+	)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/cmd/convox/stdcli"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// dryRunCall prints an API call that a --dry-run skipped, followed by its parameters
+func dryRunCall(method, path string, params map[string]string) {
+	fmt.Printf("Would %s %s\n", method, path)
+
+	for _, key := range sortedKeys(params) {
+		fmt.Printf("  %s=%s\n", key, params[key])
+	}
+}
+
+// paramsDryRun prints the call that would set the parameters of an app and the changes it would make
+func paramsDryRun(c *cli.Context, app string, params map[string]string) error {
+	dryRunCall("POST", fmt.Sprintf("/apps/%s/parameters", app), params)
+
+	changes, err := rackClient(c).PreviewParameters(app, params)
+	if err != nil {
+		return err
+	}
+
+	displayStackChanges(changes)
+
+	return nil
+}
+
+// displayStackChanges prints the infrastructure changes a stack update would make
+func displayStackChanges(changes models.StackChanges) {
+	if len(changes) == 0 {
+		fmt.Println("No infrastructure changes")
+		return
+	}
+
+	t := stdcli.NewTable("ACTION", "RESOURCE", "TYPE", "REPLACEMENT")
+
+	for _, c := range changes {
+		t.AddRow(c.Action, c.Resource, c.Type, coalesce(c.Replacement, "-"))
+	}
+
+	t.Print()
+}
+
+// displayEnvChanges prints the keys that replacing the environment before with data would
+// add, change or remove. Values are left out as they often hold credentials.
+func displayEnvChanges(before models.Environment, data string) {
+	after := map[string]string{}
+
+	for _, line := range strings.Split(data, "\n") {
+		if parts := strings.SplitN(line, "=", 2); len(parts) == 2 {
+			if key := strings.TrimSpace(parts[0]); key != "" {
+				after[key] = parts[1]
+			}
+		}
+	}
+
+	t := stdcli.NewTable("CHANGE", "KEY")
+
+	for _, key := range sortedKeys(after) {
+		value, ok := before[key]
+
+		switch {
+		case !ok:
+			t.AddRow("ADD", key)
+		case value != after[key]:
+			t.AddRow("CHANGE", key)
+		}
+	}
+
+	for _, key := range sortedKeys(before) {
+		if _, ok := after[key]; !ok {
+			t.AddRow("REMOVE", key)
+		}
+	}
+
+	if len(t.Rows) == 0 {
+		fmt.Println("No changes to environment")
+		return
+	}
+
+	t.Print()
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := []string{}
+
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
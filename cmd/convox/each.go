@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"github.com/convox/rack/cmd/convox/stdcli"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func init() {
+	stdcli.RegisterCommand(cli.Command{
+		Name:        "each",
+		Description: "run a command against several apps",
+		Usage:       "(--apps <app,app> | --all-apps) [--concurrency 4] -- <command>\n\nExample: convox each --apps web,api,worker -- env set FEATURE_X=on",
+		Action:      cmdEach,
+		Flags: []cli.Flag{
+			rackFlag,
+			cli.StringFlag{
+				Name:  "apps",
+				Usage: "comma separated apps to run the command against",
+			},
+			cli.BoolFlag{
+				Name:  "all-apps",
+				Usage: "run the command against every app of the rack",
+			},
+			cli.IntFlag{
+				Name:  "concurrency",
+				Usage: "how many apps to run the command against at once",
+				Value: 4,
+			},
+		},
+	})
+}
+
+// eachResult is the outcome of running a command against one app
+type eachResult struct {
+	output []byte
+	err    error
+}
+
+func cmdEach(c *cli.Context) error {
+	args := []string(c.Args())
+
+	if len(args) == 0 || (c.String("apps") == "") == !c.Bool("all-apps") {
+		stdcli.Usage(c, "each")
+		return nil
+	}
+
+	for _, arg := range args {
+		if arg == "--app" || arg == "-a" || strings.HasPrefix(arg, "--app=") || strings.HasPrefix(arg, "-a=") {
+			return stdcli.ExitError(fmt.Errorf("choose apps with --apps or --all-apps instead of passing --app to the command"))
+		}
+	}
+
+	if c.Int("concurrency") < 1 {
+		return stdcli.ExitError(fmt.Errorf("concurrency must be at least 1"))
+	}
+
+	apps := []string{}
+
+	if c.Bool("all-apps") {
+		as, err := rackClient(c).GetApps()
+		if err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		for _, a := range as {
+			apps = append(apps, a.Name)
+		}
+	} else {
+		for _, app := range strings.Split(c.String("apps"), ",") {
+			if app = strings.TrimSpace(app); app != "" {
+				apps = append(apps, app)
+			}
+		}
+	}
+
+	if len(apps) == 0 {
+		return stdcli.ExitError(fmt.Errorf("no apps to run the command against"))
+	}
+
+	results := make([]chan eachResult, len(apps))
+	slots := make(chan bool, c.Int("concurrency"))
+
+	for i, app := range apps {
+		results[i] = make(chan eachResult, 1)
+
+		go func(app string, result chan eachResult) {
+			slots <- true
+			defer func() { <-slots }()
+
+			result <- runForApp(c, app, args)
+		}(app, results[i])
+	}
+
+	t := stdcli.NewTable("APP", "RESULT")
+	failed := 0
+
+	// print the output of each app in order as soon as it and the apps before it are done
+	for i, app := range apps {
+		r := <-results[i]
+
+		fmt.Printf("== %s ==\n%s", app, r.output)
+
+		if len(r.output) > 0 && !bytes.HasSuffix(r.output, []byte("\n")) {
+			fmt.Println()
+		}
+
+		if r.err != nil {
+			failed++
+			t.AddRow(app, r.err.Error())
+		} else {
+			t.AddRow(app, "OK")
+		}
+	}
+
+	fmt.Println()
+	t.Print()
+
+	if failed > 0 {
+		return stdcli.ExitError(fmt.Errorf("command failed for %d of %d apps", failed, len(apps)))
+	}
+
+	return nil
+}
+
+// runForApp runs this binary with args and the app set through the environment
+func runForApp(c *cli.Context, app string, args []string) eachResult {
+	cmd := exec.Command(os.Args[0], args...)
+
+	cmd.Env = append(os.Environ(), fmt.Sprintf("CONVOX_APP=%s", app))
+
+	if rack := c.String("rack"); rack != "" {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("CONVOX_RACK=%s", rack))
+	}
+
+	out, err := cmd.CombinedOutput()
+
+	if ee, ok := err.(*exec.ExitError); ok {
+		if status, ok := ee.Sys().(syscall.WaitStatus); ok {
+			err = fmt.Errorf("exit %d", status.ExitStatus())
+		}
+	}
+
+	return eachResult{output: out, err: err}
+}
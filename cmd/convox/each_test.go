@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/test"
+)
+
+func TestEach(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps", Code: 200, Response: models.Apps{{Name: "web"}, {Name: "worker"}}},
+		test.Http{Method: "GET", Path: "/apps/web/environment", Code: 200, Response: models.Environment{}},
+		test.Http{Method: "POST", Path: "/apps/web/environment", Body: "FEATURE_X=on\n", Code: 200, Response: models.Environment{"FEATURE_X": "on"}, Headers: map[string]string{"Release-Id": "R1"}},
+		test.Http{Method: "GET", Path: "/apps/worker/environment", Code: 404, Response: client.Error{Error: "no such app: worker"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox each --apps web,worker -- env set FEATURE_X=on",
+			Exit:    1,
+			Stdout:  "== web ==\nUpdating environment... OK\nTo deploy these changes run `convox releases promote R1`\n== worker ==\nERROR: no such app: worker\n\nAPP     RESULT\nweb     OK\nworker  exit 1\n",
+			Stderr:  "ERROR: command failed for 1 of 2 apps\n",
+		},
+		test.ExecRun{
+			Command: "convox each --all-apps --concurrency 1 -- env set FEATURE_X=on",
+			Exit:    1,
+			Stdout:  "== web ==\nUpdating environment... OK\nTo deploy these changes run `convox releases promote R1`\n== worker ==\nERROR: no such app: worker\n\nAPP     RESULT\nweb     OK\nworker  exit 1\n",
+			Stderr:  "ERROR: command failed for 1 of 2 apps\n",
+		},
+		test.ExecRun{
+			Command: "convox each --apps web -- env set FEATURE_X=on --app api",
+			Exit:    1,
+			Stderr:  "ERROR: choose apps with --apps or --all-apps instead of passing --app to the command\n",
+		},
+	)
+}
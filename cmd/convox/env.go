@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"os/exec"
 	"sort"
 	"strings"
 
+	"github.com/convox/rack/client/models"
 	"github.com/convox/rack/cmd/convox/stdcli"
 	"gopkg.in/urfave/cli.v1"
 )
@@ -39,6 +41,11 @@ func init() {
 						Name:  "promote",
 						Usage: "promote the release after env change",
 					},
+					cli.BoolFlag{
+						Name:  "no-release",
+						Usage: "stage the change without creating a release, see env commit",
+					},
+					dryRunFlag,
 				},
 			},
 			{
@@ -53,6 +60,44 @@ func init() {
 						Name:  "promote",
 						Usage: "promote the release after env change",
 					},
+					cli.BoolFlag{
+						Name:  "no-release",
+						Usage: "stage the change without creating a release, see env commit",
+					},
+					dryRunFlag,
+				},
+			},
+			{
+				Name:        "commit",
+				Description: "create a release with the staged environment changes",
+				Usage:       "",
+				Action:      cmdEnvCommit,
+				Flags: []cli.Flag{
+					appFlag,
+					rackFlag,
+					cli.BoolFlag{
+						Name:  "promote",
+						Usage: "promote the release after env change",
+					},
+				},
+			},
+			{
+				Name:        "edit",
+				Description: "edit all environment variables in $EDITOR",
+				Usage:       "",
+				Action:      cmdEnvEdit,
+				Flags: []cli.Flag{
+					appFlag,
+					rackFlag,
+					cli.BoolFlag{
+						Name:  "promote",
+						Usage: "promote the release after env change",
+					},
+					cli.BoolFlag{
+						Name:  "no-release",
+						Usage: "stage the change without creating a release, see env commit",
+					},
+					dryRunFlag,
 				},
 			},
 		},
@@ -148,78 +193,263 @@ func cmdEnvSet(c *cli.Context) error {
 		}
 
 		data += string(in)
+
+		if len(in) > 0 && !strings.HasSuffix(data, "\n") {
+			data += "\n"
+		}
 	}
 
 	for _, value := range c.Args() {
 		data += fmt.Sprintf("%s\n", value)
 	}
 
+	return envUpdate(c, app, data)
+}
+
+func cmdEnvUnset(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) == 0 {
+		return stdcli.ExitError(errors.New("No variable specified"))
+	}
+
+	if len(c.Args()) > 1 {
+		return stdcli.ExitError(errors.New("Only 1 variable can be unset at a time"))
+	}
+
+	key := c.Args()[0]
+
+	if err := envCheckFlags(c); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if c.Bool("dry-run") {
+		env, err := rackClient(c).GetEnvironment(app)
+		if err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		data := ""
+
+		for k, value := range env {
+			if k != key {
+				data += fmt.Sprintf("%s=%s\n", k, value)
+			}
+		}
+
+		envDryRun(c, "DELETE", fmt.Sprintf("/apps/%s/environment/%s", app, key), env, data)
+		return nil
+	}
+
 	fmt.Print("Updating environment... ")
 
-	_, releaseID, err := rackClient(c).SetEnvironment(app, strings.NewReader(data))
+	if c.Bool("no-release") {
+		if _, err := rackClient(c).StageDeleteEnvironment(app, key); err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		fmt.Println("OK")
+		fmt.Println("To release the staged changes run `convox env commit`")
+
+		return nil
+	}
+
+	_, releaseID, err := rackClient(c).DeleteEnvironment(app, key)
 	if err != nil {
 		return stdcli.ExitError(err)
 	}
 
 	fmt.Println("OK")
 
-	if releaseID != "" {
-		if c.Bool("promote") {
-			fmt.Printf("Promoting %s... ", releaseID)
+	return envPromote(c, app, releaseID)
+}
 
-			_, err = rackClient(c).PromoteRelease(app, releaseID)
-			if err != nil {
-				return stdcli.ExitError(err)
-			}
+func cmdEnvEdit(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) > 0 {
+		return stdcli.ExitError(fmt.Errorf("`convox env edit` does not take arguments"))
+	}
+
+	env, err := rackClient(c).GetEnvironment(app)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	keys := []string{}
+
+	for key := range env {
+		keys = append(keys, key)
+	}
 
-			fmt.Println("OK")
-		} else {
-			fmt.Printf("To deploy these changes run `convox releases promote %s`\n", releaseID)
+	sort.Strings(keys)
+
+	before := ""
+
+	for _, key := range keys {
+		before += fmt.Sprintf("%s=%s\n", key, env[key])
+	}
+
+	file, err := ioutil.TempFile("", "convox-env")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(before); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if err := file.Close(); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	editor := strings.Fields(os.Getenv("EDITOR"))
+
+	if len(editor) == 0 {
+		editor = []string{"vi"}
+	}
+
+	cmd := exec.Command(editor[0], append(editor[1:], file.Name())...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return stdcli.ExitError(fmt.Errorf("editor failed: %s", err))
+	}
+
+	edited, err := ioutil.ReadFile(file.Name())
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	after := ""
+
+	for _, line := range strings.Split(string(edited), "\n") {
+		if trimmed := strings.TrimSpace(line); trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
 		}
+
+		after += line + "\n"
 	}
 
-	return nil
+	if after == before {
+		fmt.Println("No changes to environment")
+		return nil
+	}
+
+	return envUpdate(c, app, after)
 }
 
-func cmdEnvUnset(c *cli.Context) error {
+func cmdEnvCommit(c *cli.Context) error {
 	_, app, err := stdcli.DirApp(c, ".")
 	if err != nil {
 		return stdcli.ExitError(err)
 	}
 
-	if len(c.Args()) == 0 {
-		return stdcli.ExitError(errors.New("No variable specified"))
+	if len(c.Args()) > 0 {
+		return stdcli.ExitError(fmt.Errorf("`convox env commit` does not take arguments"))
 	}
 
-	if len(c.Args()) > 1 {
-		return stdcli.ExitError(errors.New("Only 1 variable can be unset at a time"))
+	fmt.Print("Releasing environment... ")
+
+	release, err := rackClient(c).CommitEnvironment(app)
+	if err != nil {
+		return stdcli.ExitError(err)
 	}
 
-	key := c.Args()[0]
+	fmt.Println("OK")
+
+	return envPromote(c, app, release.Id)
+}
+
+// envUpdate replaces the environment of an app with data, staging the change when asked to
+func envUpdate(c *cli.Context, app, data string) error {
+	if err := envCheckFlags(c); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if c.Bool("dry-run") {
+		env, err := rackClient(c).GetEnvironment(app)
+		if err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		envDryRun(c, "POST", fmt.Sprintf("/apps/%s/environment", app), env, data)
+		return nil
+	}
 
 	fmt.Print("Updating environment... ")
 
-	_, releaseID, err := rackClient(c).DeleteEnvironment(app, key)
+	if c.Bool("no-release") {
+		if _, err := rackClient(c).StageEnvironment(app, strings.NewReader(data)); err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		fmt.Println("OK")
+		fmt.Println("To release the staged changes run `convox env commit`")
+
+		return nil
+	}
+
+	_, releaseID, err := rackClient(c).SetEnvironment(app, strings.NewReader(data))
 	if err != nil {
 		return stdcli.ExitError(err)
 	}
 
 	fmt.Println("OK")
 
-	if releaseID != "" {
-		if c.Bool("promote") {
-			fmt.Printf("Promoting %s... ", releaseID)
+	return envPromote(c, app, releaseID)
+}
+
+// envDryRun prints the call that would replace the environment before with data and what it would change
+func envDryRun(c *cli.Context, method, path string, before models.Environment, data string) {
+	if c.Bool("no-release") {
+		path += "?release=false"
+	}
 
-			_, err = rackClient(c).PromoteRelease(app, releaseID)
-			if err != nil {
-				return stdcli.ExitError(err)
-			}
+	dryRunCall(method, path, nil)
+	displayEnvChanges(before, data)
 
-			fmt.Println("OK")
-		} else {
-			fmt.Printf("To deploy these changes run `convox releases promote %s`\n", releaseID)
-		}
+	if c.Bool("promote") {
+		fmt.Println("Would promote the new release")
+	}
+}
+
+func envCheckFlags(c *cli.Context) error {
+	if c.Bool("no-release") && c.Bool("promote") {
+		return fmt.Errorf("--no-release can not be combined with --promote")
+	}
+
+	return nil
+}
+
+// envPromote promotes the release created by an environment change when asked to
+func envPromote(c *cli.Context, app, releaseID string) error {
+	if releaseID == "" {
+		return nil
+	}
+
+	if !c.Bool("promote") {
+		fmt.Printf("To deploy these changes run `convox releases promote %s`\n", releaseID)
+		return nil
 	}
 
+	fmt.Printf("Promoting %s... ", releaseID)
+
+	if _, err := rackClient(c).PromoteRelease(app, releaseID); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+
 	return nil
 }
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/convox/rack/client/fake"
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvEdit(t *testing.T) {
+	fc := fake.New()
+	fc.CreateApp("foo")
+	fc.SetEnvironment("foo", strings.NewReader("FOO=bar\nBAZ=qux\n"))
+
+	fakeRuns(t, fc,
+		test.ExecRun{
+			Command: "convox env edit --app foo",
+			Env:     map[string]string{"EDITOR": "sed -i s/=bar/=baz/"},
+			Exit:    0,
+			Stdout:  "Updating environment... OK\nTo deploy these changes run `convox releases promote R000000002`\n",
+		},
+		test.ExecRun{
+			Command: "convox env edit --app foo",
+			Env:     map[string]string{"EDITOR": "true"},
+			Exit:    0,
+			Stdout:  "No changes to environment\n",
+		},
+		test.ExecRun{
+			Command: "convox env edit --app foo",
+			Env:     map[string]string{"EDITOR": "false"},
+			Exit:    1,
+			Stderr:  "ERROR: editor failed: exit status 1\n",
+		},
+	)
+
+	env, err := fc.GetEnvironment("foo")
+	require.NoError(t, err)
+	assert.Equal(t, models.Environment{"FOO": "baz", "BAZ": "qux"}, env)
+}
+
+func TestEnvSetPromote(t *testing.T) {
+	fc := fake.New()
+	fc.CreateApp("foo")
+
+	fakeRuns(t, fc,
+		test.ExecRun{
+			Command: "convox env set A=1 B=2 --promote --app foo",
+			Exit:    0,
+			Stdout:  "Updating environment... OK\nPromoting R000000001... OK\n",
+		},
+	)
+
+	a, err := fc.GetApp("foo")
+	require.NoError(t, err)
+	assert.Equal(t, "R000000001", a.Release)
+
+	r, err := fc.GetRelease("foo", "R000000001")
+	require.NoError(t, err)
+	assert.Equal(t, "A=1\nB=2", r.Env)
+}
+
+func TestEnvNoRelease(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo/environment", Code: 200, Response: models.Environment{"A": "1"}},
+		test.Http{Method: "POST", Path: "/apps/foo/environment", Body: "A=1\nB=2\n", Code: 200, Response: models.Environment{"A": "1", "B": "2"}},
+		test.Http{Method: "DELETE", Path: "/apps/foo/environment/A", Code: 200, Response: models.Environment{"B": "2"}},
+		test.Http{Method: "POST", Path: "/apps/foo/environment/commit", Code: 200, Response: models.Release{Id: "R2"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox env set B=2 --no-release --app foo",
+			Exit:    0,
+			Stdout:  "Updating environment... OK\nTo release the staged changes run `convox env commit`\n",
+		},
+		test.ExecRun{
+			Command: "convox env unset A --no-release --app foo",
+			Exit:    0,
+			Stdout:  "Updating environment... OK\nTo release the staged changes run `convox env commit`\n",
+		},
+		test.ExecRun{
+			Command: "convox env set B=2 --no-release --promote --app foo",
+			Exit:    1,
+			Stderr:  "ERROR: --no-release can not be combined with --promote\n",
+		},
+		test.ExecRun{
+			Command: "convox env commit --app foo",
+			Exit:    0,
+			Stdout:  "Releasing environment... OK\nTo deploy these changes run `convox releases promote R2`\n",
+		},
+	)
+}
+
+func TestEnvDryRun(t *testing.T) {
+	fc := fake.New()
+	fc.CreateApp("foo")
+	fc.SetEnvironment("foo", strings.NewReader("A=1\nB=2\n"))
+
+	fakeRuns(t, fc,
+		test.ExecRun{
+			Command: "convox env set B=3 C=4 --dry-run --app foo",
+			Exit:    0,
+			Stdout:  "Would POST /apps/foo/environment\nCHANGE  KEY\nCHANGE  B\nADD     C\n",
+		},
+		test.ExecRun{
+			Command: "convox env set A=1 --dry-run --no-release --app foo",
+			Exit:    0,
+			Stdout:  "Would POST /apps/foo/environment?release=false\nNo changes to environment\n",
+		},
+		test.ExecRun{
+			Command: "convox env unset A --dry-run --promote --app foo",
+			Exit:    0,
+			Stdout:  "Would DELETE /apps/foo/environment/A\nCHANGE  KEY\nREMOVE  A\nWould promote the new release\n",
+		},
+	)
+
+	env, err := fc.GetEnvironment("foo")
+	require.NoError(t, err)
+	assert.Equal(t, models.Environment{"A": "1", "B": "2"}, env)
+}
@@ -21,24 +21,6 @@ func init() {
 }
 
 func cmdExec(c *cli.Context) error {
-	fd := os.Stdin.Fd()
-
-	var h, w int
-
-	if terminal.IsTerminal(int(fd)) {
-		stdinState, err := terminal.GetState(int(fd))
-		if err != nil {
-			return stdcli.ExitError(err)
-		}
-
-		defer terminal.Restore(int(fd), stdinState)
-
-		w, h, err = terminal.GetSize(int(fd))
-		if err != nil {
-			return stdcli.ExitError(err)
-		}
-	}
-
 	_, app, err := stdcli.DirApp(c, ".")
 	if err != nil {
 		return stdcli.ExitError(err)
@@ -50,11 +32,41 @@ func cmdExec(c *cli.Context) error {
 	}
 
 	ps := c.Args()[0]
+	rc := rackClient(c)
 
-	code, err := rackClient(c).ExecProcessAttached(app, ps, strings.Join(c.Args()[1:], " "), os.Stdin, os.Stdout, h, w)
+	restore, w, h, err := attachTerminal()
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	code, err := rc.ExecProcessAttached(app, ps, strings.Join(c.Args()[1:], " "), os.Stdin, os.Stdout, h, w)
+	restore()
 	if err != nil {
 		return stdcli.ExitError(err)
 	}
 
 	return cli.NewExitError("", code)
 }
+
+// attachTerminal puts the terminal on stdin into raw mode so keystrokes go to the remote
+// process as they are typed. It returns a function that restores the terminal along with
+// its width and height, which are zero when stdin is not a terminal.
+func attachTerminal() (func(), int, int, error) {
+	fd := int(os.Stdin.Fd())
+
+	if !terminal.IsTerminal(fd) {
+		return func() {}, 0, 0, nil
+	}
+
+	w, h, err := terminalSize()
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	state, err := terminal.MakeRaw(fd)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return func() { terminal.Restore(fd, state) }, w, h, nil
+}
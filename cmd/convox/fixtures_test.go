@@ -0,0 +1,74 @@
+package main
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/convox/rack/test"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFixtures replays each recorded session in testdata/*.json against a stub rack.
+//
+// To record a session against a live rack, list the commands to run in a fixture file
+// and run the test with the rack's host and password:
+//
+//	CONVOX_RECORD_HOST=rack.example.org CONVOX_RECORD_PASSWORD=secret CONVOX_RECORD_FIXTURE=apps go test -run TestFixtures
+//
+// The file is rewritten with the requests the commands made and the output they printed.
+// Without CONVOX_RECORD_FIXTURE every fixture is recorded again.
+func TestFixtures(t *testing.T) {
+	files, err := filepath.Glob("testdata/*.json")
+	require.Nil(t, err)
+
+	for _, file := range files {
+		testFixture(t, file)
+	}
+}
+
+func testFixture(t *testing.T, file string) {
+	f, err := test.LoadFixture(file)
+	require.Nil(t, err)
+
+	if host := os.Getenv("CONVOX_RECORD_HOST"); host != "" {
+		name := strings.TrimSuffix(filepath.Base(file), ".json")
+
+		if only := os.Getenv("CONVOX_RECORD_FIXTURE"); only == "" || only == name {
+			recordFixture(t, f, file, host)
+		}
+
+		return
+	}
+
+	ts := testServer(t, f.Stubs()...)
+	defer ts.Close()
+
+	for _, run := range f.ExecRuns() {
+		run.Test(t)
+	}
+}
+
+func recordFixture(t *testing.T, f *test.Fixture, file, host string) {
+	rec := test.NewRecorder(host)
+
+	ts := httptest.NewTLSServer(rec)
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	require.Nil(t, err)
+
+	os.Setenv("CONVOX_HOST", u.Host)
+	os.Setenv("CONVOX_PASSWORD", os.Getenv("CONVOX_RECORD_PASSWORD"))
+
+	for i, run := range f.ExecRuns() {
+		f.Runs[i].Stdout, f.Runs[i].Stderr, f.Runs[i].Exit = run.Output()
+	}
+
+	f.Requests = rec.Requests
+
+	require.Nil(t, f.Save(file))
+}
@@ -11,3 +11,13 @@ var rackFlag = cli.StringFlag{
 	Name:  "rack",
 	Usage: "Rack name.",
 }
+
+var limitBandwidthFlag = cli.StringFlag{
+	Name:  "limit-bandwidth",
+	Usage: "most data to upload per second, like 5MB/s. Defaults to CONVOX_LIMIT_BANDWIDTH or ~/.convox/limit-bandwidth.",
+}
+
+var dryRunFlag = cli.BoolFlag{
+	Name:  "dry-run",
+	Usage: "print the API calls and infrastructure changes the command would make without making them",
+}
@@ -1,12 +1,37 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"time"
 
+	"github.com/convox/rack/cmd/convox/stdcli"
 	"github.com/dustin/go-humanize"
+	"gopkg.in/urfave/cli.v1"
 )
 
+// exit codes for the wait commands so scripts can tell why a wait ended
+const (
+	exitWaitFailed    = 1
+	exitWaitTimeout   = 2
+	exitWaitCancelled = 3
+)
+
+// waitError ends a wait with a specific exit code
+type waitError struct {
+	error
+	code int
+}
+
+// waitExitError is like stdcli.ExitError but keeps the exit code of a waitError
+func waitExitError(err error) error {
+	if we, ok := err.(waitError); ok {
+		return cli.NewExitError(fmt.Sprintf("ERROR: %s", we.Error()), we.code)
+	}
+
+	return stdcli.ExitError(err)
+}
+
 func exists(filename string) bool {
 	if _, err := os.Stat(filename); os.IsNotExist(err) {
 		return false
@@ -18,6 +43,8 @@ func exists(filename string) bool {
 func humanizeTime(t time.Time) string {
 	if t.IsZero() {
 		return ""
+	} else if stdcli.OutputJSON() {
+		return t.UTC().Format(time.RFC3339)
 	} else {
 		return humanize.Time(t)
 	}
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/convox/rack/cmd/convox/stdcli"
+	"gopkg.in/urfave/cli.v1"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// herokuAddonServices maps heroku addons to the rack services that replace them
+var herokuAddonServices = map[string]string{
+	"cleardb":           "mysql",
+	"heroku-postgresql": "postgres",
+	"heroku-redis":      "redis",
+	"jawsdb":            "mysql",
+	"memcachier":        "memcached",
+	"papertrail":        "syslog",
+	"rediscloud":        "redis",
+	"redistogo":         "redis",
+}
+
+// herokuDynoMemory is the memory in MB of each heroku dyno size
+var herokuDynoMemory = map[string]int{
+	"free":          512,
+	"hobby":         512,
+	"standard-1x":   512,
+	"standard-2x":   1024,
+	"performance-m": 2560,
+	"performance-l": 14336,
+	"private-s":     1024,
+	"private-m":     2560,
+	"private-l":     14336,
+}
+
+type herokuFormation struct {
+	Type     string `json:"type"`
+	Command  string `json:"command"`
+	Quantity int    `json:"quantity"`
+	Size     string `json:"size"`
+}
+
+type herokuAddon struct {
+	Name       string   `json:"name"`
+	ConfigVars []string `json:"config_vars"`
+
+	AddonService struct {
+		Name string `json:"name"`
+	} `json:"addon_service"`
+}
+
+func init() {
+	stdcli.RegisterCommand(cli.Command{
+		Name:        "import",
+		Description: "migrate an app from another platform",
+		Usage:       "",
+		Subcommands: []cli.Command{
+			{
+				Name:        "heroku",
+				Description: "create an app from the config vars, processes and addons of a heroku app",
+				Usage:       "<heroku app> [--app name]",
+				Action:      cmdImportHeroku,
+				Flags: []cli.Flag{
+					appFlag,
+					rackFlag,
+				},
+			},
+		},
+	})
+}
+
+func cmdImportHeroku(c *cli.Context) error {
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "heroku")
+		return nil
+	}
+
+	source := c.Args()[0]
+
+	app := c.String("app")
+	if app == "" {
+		app = source
+	}
+
+	token, err := herokuToken()
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Printf("Reading heroku app %s... ", source)
+
+	env := map[string]string{}
+	formation := []herokuFormation{}
+	addons := []herokuAddon{}
+
+	if err := herokuGet(token, fmt.Sprintf("/apps/%s/config-vars", source), &env); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if err := herokuGet(token, fmt.Sprintf("/apps/%s/formation", source), &formation); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if err := herokuGet(token, fmt.Sprintf("/apps/%s/addons", source), &addons); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+
+	manifest, err := herokuManifest(formation)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if exists("docker-compose.yml") {
+		fmt.Println("Skipping docker-compose.yml, it already exists")
+	} else {
+		fmt.Print("Writing docker-compose.yml... ")
+
+		if err := ioutil.WriteFile(filepath.Join(".", "docker-compose.yml"), manifest, 0644); err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		fmt.Println("OK")
+	}
+
+	fmt.Printf("Creating app %s... ", app)
+
+	if _, err := rackClient(c).CreateApp(app); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("CREATING")
+	fmt.Printf("Waiting for %s... ", app)
+
+	if err := waitForAppRunning(c, app); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+
+	if len(env) > 0 {
+		keys := []string{}
+
+		for key := range env {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		var data bytes.Buffer
+
+		for _, key := range keys {
+			fmt.Fprintf(&data, "%s=%s\n", key, env[key])
+		}
+
+		fmt.Printf("Setting %s... ", strings.Join(keys, ", "))
+
+		if _, _, err := rackClient(c).SetEnvironment(app, &data); err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		fmt.Println("OK")
+	}
+
+	fmt.Println()
+	fmt.Println(herokuNextSteps(app, formation, addons))
+
+	return nil
+}
+
+// herokuManifest suggests a manifest that builds the current directory once for every process type
+func herokuManifest(formation []herokuFormation) ([]byte, error) {
+	sort.Sort(herokuFormations(formation))
+
+	services := yaml.MapSlice{}
+
+	for _, f := range formation {
+		s := yaml.MapSlice{
+			{Key: "build", Value: "."},
+			{Key: "command", Value: f.Command},
+		}
+
+		// heroku routes web traffic to the port in $PORT
+		if f.Type == "web" {
+			s = append(s,
+				yaml.MapItem{Key: "environment", Value: []string{"PORT=5000"}},
+				yaml.MapItem{Key: "ports", Value: []string{"80:5000", "443:5000"}},
+			)
+		}
+
+		services = append(services, yaml.MapItem{Key: herokuProcessName(f.Type), Value: s})
+	}
+
+	return yaml.Marshal(yaml.MapSlice{
+		{Key: "version", Value: "2"},
+		{Key: "services", Value: services},
+	})
+}
+
+// herokuNextSteps describes how to replace the addons and scale of a heroku app
+func herokuNextSteps(app string, formation []herokuFormation, addons []herokuAddon) string {
+	lines := []string{}
+
+	if len(addons) > 0 {
+		lines = append(lines, "Addons (their config vars still point at heroku until replaced):")
+
+		for _, a := range addons {
+			vars := strings.Join(a.ConfigVars, ", ")
+
+			if service, ok := herokuAddonServices[a.AddonService.Name]; ok {
+				lines = append(lines, fmt.Sprintf("  %s: convox services create %s --name=%s-%s && convox services link %s-%s --app %s  # replaces %s", a.Name, service, app, service, app, service, app, vars))
+			} else {
+				lines = append(lines, fmt.Sprintf("  %s: no rack equivalent for %s, keep %s", a.Name, a.AddonService.Name, vars))
+			}
+		}
+
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, "Deploy with `convox deploy --app "+app+" --wait`, then scale to match heroku:")
+
+	sort.Sort(herokuFormations(formation))
+
+	for _, f := range formation {
+		scale := fmt.Sprintf("  convox scale %s --app %s --count %d", herokuProcessName(f.Type), app, f.Quantity)
+
+		if memory, ok := herokuDynoMemory[strings.ToLower(f.Size)]; ok {
+			scale += fmt.Sprintf(" --memory %d", memory)
+		}
+
+		lines = append(lines, scale)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func herokuProcessName(t string) string {
+	return strings.Replace(strings.ToLower(t), "_", "-", -1)
+}
+
+// herokuGet reads a resource from the heroku platform api
+func herokuGet(token, path string, v interface{}) error {
+	host := os.Getenv("HEROKU_API_URL")
+	if host == "" {
+		host = "https://api.heroku.com"
+	}
+
+	req, err := http.NewRequest("GET", host+path, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/vnd.heroku+json; version=3")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode/100 != 2 {
+		var e struct {
+			Message string `json:"message"`
+		}
+
+		if json.Unmarshal(data, &e) == nil && e.Message != "" {
+			return fmt.Errorf("heroku: %s", e.Message)
+		}
+
+		return fmt.Errorf("heroku: response status %d", res.StatusCode)
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// herokuToken returns HEROKU_API_KEY or the password the heroku cli saved in ~/.netrc
+func herokuToken() (string, error) {
+	if token := os.Getenv("HEROKU_API_KEY"); token != "" {
+		return token, nil
+	}
+
+	f, err := os.Open(filepath.Join(os.Getenv("HOME"), ".netrc"))
+	if err != nil {
+		return "", fmt.Errorf("set HEROKU_API_KEY or log in with `heroku login`")
+	}
+
+	defer f.Close()
+
+	words := []string{}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Split(bufio.ScanWords)
+
+	for scanner.Scan() {
+		words = append(words, scanner.Text())
+	}
+
+	machine := ""
+
+	for i := 0; i < len(words)-1; i++ {
+		switch words[i] {
+		case "machine":
+			machine = words[i+1]
+		case "password":
+			if machine == "api.heroku.com" {
+				return words[i+1], nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("set HEROKU_API_KEY or log in with `heroku login`")
+}
+
+type herokuFormations []herokuFormation
+
+func (fs herokuFormations) Len() int           { return len(fs) }
+func (fs herokuFormations) Less(i, j int) bool { return fs[i].Type < fs[j].Type }
+func (fs herokuFormations) Swap(i, j int)      { fs[i], fs[j] = fs[j], fs[i] }
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/convox/rack/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHerokuManifest(t *testing.T) {
+	data, err := herokuManifest([]herokuFormation{
+		{Type: "web", Command: "bundle exec puma -C config/puma.rb", Quantity: 2, Size: "Standard-2X"},
+		{Type: "low_worker", Command: "bundle exec sidekiq", Quantity: 1, Size: "standard-1x"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `version: "2"
+services:
+  low-worker:
+    build: .
+    command: bundle exec sidekiq
+  web:
+    build: .
+    command: bundle exec puma -C config/puma.rb
+    environment:
+    - PORT=5000
+    ports:
+    - 80:5000
+    - 443:5000
+`, string(data))
+}
+
+func TestHerokuNextSteps(t *testing.T) {
+	addons := []herokuAddon{
+		{Name: "postgresql-fluffy-123", ConfigVars: []string{"DATABASE_URL"}},
+		{Name: "sendgrid-shiny-456", ConfigVars: []string{"SENDGRID_USERNAME", "SENDGRID_PASSWORD"}},
+	}
+
+	addons[0].AddonService.Name = "heroku-postgresql"
+	addons[1].AddonService.Name = "sendgrid"
+
+	steps := herokuNextSteps("shop", []herokuFormation{
+		{Type: "web", Quantity: 2, Size: "Standard-2X"},
+		{Type: "worker", Quantity: 1, Size: "custom"},
+	}, addons)
+
+	assert.Equal(t, `Addons (their config vars still point at heroku until replaced):
+  postgresql-fluffy-123: convox services create postgres --name=shop-postgres && convox services link shop-postgres --app shop  # replaces DATABASE_URL
+  sendgrid-shiny-456: no rack equivalent for sendgrid, keep SENDGRID_USERNAME, SENDGRID_PASSWORD
+
+Deploy with `+"`convox deploy --app shop --wait`"+`, then scale to match heroku:
+  convox scale web --app shop --count 2 --memory 1024
+  convox scale worker --app shop --count 1`, steps)
+}
+
+func TestImportHerokuMissingApp(t *testing.T) {
+	heroku := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		w.WriteHeader(404)
+		w.Write([]byte(`{"id":"not_found","message":"Couldn't find that app."}`))
+	}))
+
+	defer heroku.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox import heroku shop",
+			Env:     map[string]string{"HEROKU_API_KEY": "secret", "HEROKU_API_URL": heroku.URL},
+			Exit:    1,
+			Stdout:  "Reading heroku app shop... ",
+			Stderr:  "ERROR: heroku: Couldn't find that app.\n",
+		},
+	)
+}
@@ -318,7 +318,11 @@ func cmdInstall(c *cli.Context) error {
 			&cloudformation.Parameter{ParameterKey: aws.String("SubnetPrivate2CIDR"), ParameterValue: aws.String(subnetPrivate2CIDR)},
 			&cloudformation.Parameter{ParameterKey: aws.String("VPCCIDR"), ParameterValue: aws.String(vpcCIDR)},
 		},
-		StackName:   aws.String(stackName),
+		StackName: aws.String(stackName),
+		Tags: []*cloudformation.Tag{
+			&cloudformation.Tag{Key: aws.String("System"), Value: aws.String("convox")},
+			&cloudformation.Tag{Key: aws.String("Type"), Value: aws.String("rack")},
+		},
 		TemplateURL: aws.String(furl),
 	}
 
@@ -2,6 +2,8 @@ package main
 
 import (
 	"fmt"
+	"io"
+	"net"
 	"os"
 	"strconv"
 	"strings"
@@ -27,10 +29,30 @@ func init() {
 				Action:      cmdInstancesKeyroll,
 				Flags:       []cli.Flag{rackFlag},
 			},
+			{
+				Name:        "list",
+				Description: "list your Convox rack's instances",
+				Usage:       "",
+				Action:      cmdInstancesList,
+				Flags:       []cli.Flag{rackFlag},
+			},
+			{
+				Name:        "ps",
+				Description: "list the processes of every app running on an instance",
+				Usage:       "<id>",
+				Action:      cmdInstancesPs,
+				Flags: []cli.Flag{
+					rackFlag,
+					cli.BoolFlag{
+						Name:  "stats",
+						Usage: "display process cpu/memory stats",
+					},
+				},
+			},
 			{
 				Name:            "ssh",
 				Description:     "establish secure shell with EC2 instance",
-				Usage:           "<id> [command]",
+				Usage:           "[-A] <id> [command]",
 				Action:          cmdInstancesSSH,
 				Flags:           []cli.Flag{rackFlag},
 				SkipFlagParsing: true,
@@ -80,6 +102,38 @@ func cmdInstancesList(c *cli.Context) error {
 	return nil
 }
 
+func cmdInstancesPs(c *cli.Context) error {
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "ps")
+		return nil
+	}
+
+	ps, err := rackClient(c).GetInstanceProcesses(c.Args()[0], c.Bool("stats"))
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if c.Bool("stats") {
+		t := stdcli.NewTable("ID", "APP", "NAME", "RELEASE", "CPU %", "MEM %", "STARTED", "COMMAND")
+
+		for _, p := range ps {
+			t.AddRow(p.Id, p.App, p.Name, p.Release, fmt.Sprintf("%0.2f%%", p.Cpu), fmt.Sprintf("%0.2f%%", p.Memory*100), humanizeTime(p.Started), p.Command)
+		}
+
+		t.Print()
+		return nil
+	}
+
+	t := stdcli.NewTable("ID", "APP", "NAME", "RELEASE", "STARTED", "COMMAND")
+
+	for _, p := range ps {
+		t.AddRow(p.Id, p.App, p.Name, p.Release, humanizeTime(p.Started), p.Command)
+	}
+
+	t.Print()
+	return nil
+}
+
 func cmdInstancesKeyroll(c *cli.Context) error {
 	err := rackClient(c).InstanceKeyroll()
 	if err != nil {
@@ -108,15 +162,37 @@ func cmdInstancesTerminate(c *cli.Context) error {
 }
 
 func cmdInstancesSSH(c *cli.Context) error {
-	if len(c.Args()) < 1 {
+	args := c.Args()
+	forward := false
+
+	// flags are not parsed so that they can be passed to the command
+	if len(args) > 0 && args[0] == "-A" {
+		forward = true
+		args = args[1:]
+	}
+
+	if len(args) < 1 {
 		stdcli.Usage(c, "ssh")
 		return nil
 	}
 
-	id := c.Args()[0]
-	cmd := strings.Join(c.Args()[1:], " ")
+	id := args[0]
+	cmd := strings.Join(args[1:], " ")
+
+	var agent func() (io.ReadWriteCloser, error)
+
+	if forward {
+		sock := os.Getenv("SSH_AUTH_SOCK")
+		if sock == "" {
+			return stdcli.ExitError(fmt.Errorf("SSH_AUTH_SOCK is not set, start an ssh agent to use -A"))
+		}
+
+		agent = func() (io.ReadWriteCloser, error) {
+			return net.Dial("unix", sock)
+		}
+	}
 
-	code, err := sshWithRestore(c, id, cmd)
+	code, err := sshWithRestore(c, id, cmd, agent)
 	if err != nil {
 		return stdcli.ExitError(err)
 	}
@@ -124,7 +200,7 @@ func cmdInstancesSSH(c *cli.Context) error {
 	return cli.NewExitError("", code)
 }
 
-func sshWithRestore(c *cli.Context, id, cmd string) (int, error) {
+func sshWithRestore(c *cli.Context, id, cmd string, agent func() (io.ReadWriteCloser, error)) (int, error) {
 	fd := os.Stdin.Fd()
 	isTerm := terminal.IsTerminal(int(fd))
 	var h, w int
@@ -135,7 +211,7 @@ func sshWithRestore(c *cli.Context, id, cmd string) (int, error) {
 			return -1, err
 		}
 
-		h, w, err = terminal.GetSize(int(fd))
+		h, w, err = terminalSize()
 		if err != nil {
 			return -1, err
 		}
@@ -143,5 +219,5 @@ func sshWithRestore(c *cli.Context, id, cmd string) (int, error) {
 		defer terminal.Restore(int(fd), stdinState)
 	}
 
-	return rackClient(c).SSHInstance(id, cmd, h, w, isTerm, os.Stdin, os.Stdout)
+	return rackClient(c).SSHInstance(id, cmd, h, w, isTerm, agent, os.Stdin, os.Stdout)
 }
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/test"
+)
+
+func TestInstancesPs(t *testing.T) {
+	started := time.Now().UTC().Add(-2 * time.Minute)
+
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/instances/i-1234/processes", Code: 200, Response: models.Processes{
+			{Id: "abc123", App: "api", Name: "web", Release: "RABCDEFGHI", Started: started, Command: "bin/web"},
+			{Id: "def456", Name: "ecs-agent", Started: started, Command: "/agent"},
+		}},
+		test.Http{Method: "GET", Path: "/instances/i-5678/processes", Code: 404, Response: client.Error{Error: "no such instance: i-5678"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox instances ps i-1234",
+			Exit:    0,
+			Stdout:  "ID      APP  NAME       RELEASE     STARTED        COMMAND\nabc123  api  web        RABCDEFGHI  2 minutes ago  bin/web\ndef456       ecs-agent              2 minutes ago  /agent\n",
+		},
+		test.ExecRun{
+			Command: "convox instances ps i-5678",
+			Exit:    1,
+			Stderr:  "ERROR: no such instance: i-5678\n",
+		},
+	)
+}
+
+func TestInstancesList(t *testing.T) {
+	started := time.Now().UTC().Add(-2 * time.Minute)
+
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/instances", Code: 200, Response: []models.Instance{
+			{Id: "i-1234", Agent: true, Status: "active", Started: started, Processes: 3, Cpu: 0.25, Memory: 0.5},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox instances list",
+			Exit:    0,
+			Stdout:  "ID      AGENT  STATUS  STARTED        PS  CPU     MEM\ni-1234  on     active  2 minutes ago  3   25.00%  50.00%\n",
+		},
+	)
+}
+
+func TestInstancesTerminate(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "DELETE", Path: "/instances/i-1234", Code: 200, Response: map[string]bool{"success": true}},
+		test.Http{Method: "DELETE", Path: "/instances/i-5678", Code: 404, Response: client.Error{Error: "no such instance: i-5678"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox instances terminate i-1234",
+			Exit:    0,
+			Stdout:  "Successfully sent terminate to instance \"i-1234\"\n",
+		},
+		test.ExecRun{
+			Command: "convox instances terminate i-5678",
+			Exit:    1,
+			Stderr:  "ERROR: no such instance: i-5678\n",
+		},
+	)
+}
+
+func TestInstancesSSHAgentWithoutSocket(t *testing.T) {
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox instances ssh -A i-1234",
+			Env:     map[string]string{"SSH_AUTH_SOCK": ""},
+			Exit:    1,
+			Stderr:  "ERROR: SSH_AUTH_SOCK is not set, start an ssh agent to use -A\n",
+		},
+	)
+}
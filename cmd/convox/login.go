@@ -1,6 +1,7 @@
 package main
 
 import (
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -20,6 +21,21 @@ var ConfigRoot string
 
 type ConfigAuth map[string]string
 
+// ConfigHost is how the cli connects to a rack
+type ConfigHost struct {
+	// CA is a PEM bundle of the authorities that sign the certificate of the rack
+	CA string `json:"ca,omitempty"`
+
+	// Pin is the hash of a public key in the certificate chain, see client.SPKIPin
+	Pin string `json:"pin,omitempty"`
+
+	// Sign is set for racks that accept signed requests in place of the password
+	Sign bool `json:"sign,omitempty"`
+
+	// User is the user the saved password is a key of, empty for the rack password
+	User string `json:"user,omitempty"`
+}
+
 func init() {
 	stdcli.RegisterCommand(cli.Command{
 		Name:        "login",
@@ -31,6 +47,18 @@ func init() {
 				Name:  "password, p",
 				Usage: "Console API key or Rack password. If not specified, prompt.",
 			},
+			cli.StringFlag{
+				Name:  "user, u",
+				Usage: "log in with the key of this user instead of the Rack password",
+			},
+			cli.StringFlag{
+				Name:  "ca",
+				Usage: "PEM file of the certificate authority that signed the rack's certificate",
+			},
+			cli.StringFlag{
+				Name:  "pin",
+				Usage: "require a public key in the rack's certificate chain, like sha256//<base64>",
+			},
 		},
 	})
 
@@ -79,6 +107,28 @@ func cmdLogin(c *cli.Context) error {
 		host = u.Host
 	}
 
+	hc, err := getHostConfig(host)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if ca := c.String("ca"); ca != "" {
+		data, err := ioutil.ReadFile(ca)
+		if err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		hc.CA = string(data)
+	}
+
+	if pin := c.String("pin"); pin != "" {
+		hc.Pin = pin
+	}
+
+	if c.IsSet("user") {
+		hc.User = c.String("user")
+	}
+
 	password := os.Getenv("CONVOX_PASSWORD")
 
 	if password == "" {
@@ -87,21 +137,21 @@ func cmdLogin(c *cli.Context) error {
 
 	if password != "" {
 		// password flag
-		err = testLogin(host, password, c.App.Version)
+		err = testLogin(host, password, c.App.Version, &hc)
 	} else {
 		// first try current login
 		password, err = getLogin(host)
-		err = testLogin(host, password, c.App.Version)
+		err = testLogin(host, password, c.App.Version, &hc)
 
 		// then prompt for password
 		if err != nil {
 			password = promptForPassword()
-			err = testLogin(host, password, c.App.Version)
+			err = testLogin(host, password, c.App.Version, &hc)
 		}
 	}
 
 	if err != nil {
-		if strings.Contains(err.Error(), "401") {
+		if _, ok := err.(client.ErrUnauthorized); ok {
 			return stdcli.ExitError(fmt.Errorf("invalid login"))
 		} else {
 			return stdcli.ExitError(err)
@@ -113,6 +163,11 @@ func cmdLogin(c *cli.Context) error {
 		return stdcli.ExitError(err)
 	}
 
+	err = setHostConfig(host, hc)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
 	err = switchHost(host)
 	if err != nil {
 		return stdcli.ExitError(err)
@@ -235,6 +290,100 @@ func removeLogin(host string) error {
 	return ioutil.WriteFile(config, data, 0600)
 }
 
+// getHostConfig returns the connection settings saved for host
+func getHostConfig(host string) (ConfigHost, error) {
+	hcs, err := readHostConfigs()
+	if err != nil {
+		return ConfigHost{}, err
+	}
+
+	return hcs[host], nil
+}
+
+// setHostConfig saves the connection settings of host, forgetting them if hc is empty
+func setHostConfig(host string, hc ConfigHost) error {
+	hcs, err := readHostConfigs()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := hcs[host]; !ok && hc == (ConfigHost{}) {
+		return nil
+	}
+
+	if hc == (ConfigHost{}) {
+		delete(hcs, host)
+	} else {
+		hcs[host] = hc
+	}
+
+	data, err := json.MarshalIndent(hcs, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(ConfigRoot, 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(ConfigRoot, "hosts"), data, 0600)
+}
+
+// readHostConfigs returns the connection settings of every host. Earlier versions kept the ca and
+// pin of each host in a tls file with the same format, which is moved to hosts the first time.
+func readHostConfigs() (map[string]ConfigHost, error) {
+	config := filepath.Join(ConfigRoot, "hosts")
+	legacy := filepath.Join(ConfigRoot, "tls")
+
+	if _, err := os.Stat(config); os.IsNotExist(err) {
+		if _, err := os.Stat(legacy); err == nil {
+			if err := os.Rename(legacy, config); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	data, _ := ioutil.ReadFile(config)
+	if data == nil {
+		data = []byte("{}")
+	}
+
+	var hcs map[string]ConfigHost
+
+	if err := json.Unmarshal(data, &hcs); err != nil {
+		return nil, err
+	}
+
+	if hcs == nil {
+		hcs = map[string]ConfigHost{}
+	}
+
+	return hcs, nil
+}
+
+// configureClient makes cl connect to its rack as hc describes
+func configureClient(cl *client.Client, hc ConfigHost) error {
+	if hc.CA != "" {
+		pool := x509.NewCertPool()
+
+		if !pool.AppendCertsFromPEM([]byte(hc.CA)) {
+			return fmt.Errorf("no certificates found in ca for %s", cl.Host)
+		}
+
+		cl.CA = pool
+	}
+
+	if hc.Pin != "" && !strings.HasPrefix(hc.Pin, "sha256//") {
+		return fmt.Errorf("pin must look like sha256//<base64>")
+	}
+
+	cl.Pin = hc.Pin
+	cl.Sign = hc.Sign
+	cl.KeyUser = hc.User
+
+	return nil
+}
+
 func switchHost(host string) error {
 	return ioutil.WriteFile(filepath.Join(ConfigRoot, "host"), []byte(host), 0600)
 }
@@ -336,19 +485,56 @@ func currentId() (string, error) {
 	return strings.TrimSpace(string(data)), nil
 }
 
+// currentUser returns a name for whoever is running the cli. The id is
+// the console login email when there is one, otherwise use the local user.
+func currentUser() string {
+	if id, err := currentId(); err == nil && strings.Contains(id, "@") {
+		return id
+	}
+
+	for _, name := range []string{"USER", "USERNAME"} {
+		if user := os.Getenv(name); user != "" {
+			return user
+		}
+	}
+
+	return ""
+}
+
 func updateId(id string) error {
 	config := filepath.Join(ConfigRoot, "id")
 
 	return ioutil.WriteFile(config, []byte(id), 0600)
 }
 
-func testLogin(host, password, version string) (err error) {
+// testLogin checks password against the rack at host and records in hc
+// whether the rack accepts signed requests
+func testLogin(host, password, version string, hc *ConfigHost) (err error) {
 	cl := client.New(host, password, version)
 
 	if cl == nil {
 		return
 	}
 
+	if err = configureClient(cl, *hc); err != nil {
+		return
+	}
+
+	schemes, err := cl.AuthSchemes()
+	if err != nil {
+		return
+	}
+
+	hc.Sign = false
+
+	for _, s := range schemes {
+		if s == "signature" {
+			hc.Sign = true
+		}
+	}
+
+	cl.Sign = hc.Sign
+
 	_, err = cl.GetApps()
 
 	if err != nil {
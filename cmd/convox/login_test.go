@@ -1,12 +1,21 @@
 package main
 
 import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/models"
 	"github.com/convox/rack/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestInvalidLogin(t *testing.T) {
@@ -39,7 +48,8 @@ func TestLogin(t *testing.T) {
 	temp, _ := ioutil.TempDir("", "convox-test")
 
 	ts := testServer(t,
-		test.Http{Method: "GET", Path: "/apps", Code: 200, Response: client.Apps{}},
+		test.Http{Method: "GET", Path: "/apps", Code: 200, Response: models.Apps{}},
+		test.Http{Method: "GET", Path: "/auth", Code: 401, Response: "invalid authorization"},
 	)
 
 	defer ts.Close()
@@ -53,3 +63,81 @@ func TestLogin(t *testing.T) {
 		},
 	)
 }
+
+func TestLoginCA(t *testing.T) {
+	temp, _ := ioutil.TempDir("", "convox-test")
+
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps", Code: 200, Response: models.Apps{}},
+		test.Http{Method: "GET", Path: "/auth", Code: 401, Response: "invalid authorization"},
+	)
+
+	defer ts.Close()
+
+	cert, err := x509.ParseCertificate(ts.TLS.Certificates[0].Certificate[0])
+	require.Nil(t, err)
+
+	ca := filepath.Join(temp, "ca.pem")
+	other := filepath.Join(temp, "other.pem")
+
+	require.Nil(t, ioutil.WriteFile(ca, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), 0600))
+	require.Nil(t, ioutil.WriteFile(other, []byte("not a certificate"), 0600))
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: fmt.Sprintf("convox login --password foobar --ca %s --pin %s %s", ca, client.SPKIPin(cert), ts.URL),
+			Env:     map[string]string{"CONVOX_CONFIG": temp},
+			Exit:    0,
+			Stdout:  "Logged in successfully.\n",
+		},
+		test.ExecRun{
+			Command: fmt.Sprintf("convox login --password foobar --ca %s %s", other, ts.URL),
+			Env:     map[string]string{"CONVOX_CONFIG": temp},
+			Exit:    1,
+			Stderr:  "ERROR: no certificates found in ca",
+		},
+		test.ExecRun{
+			Command: fmt.Sprintf("convox login --password foobar --pin sha256//AAAA %s", ts.URL),
+			Env:     map[string]string{"CONVOX_CONFIG": temp},
+			Exit:    1,
+			Stderr:  "does not match pin sha256//AAAA",
+		},
+	)
+
+	data, err := ioutil.ReadFile(filepath.Join(temp, "hosts"))
+	require.Nil(t, err)
+
+	var hcs map[string]ConfigHost
+
+	require.Nil(t, json.Unmarshal(data, &hcs))
+
+	u, _ := url.Parse(ts.URL)
+
+	assert.Equal(t, client.SPKIPin(cert), hcs[u.Host].Pin)
+	assert.Contains(t, hcs[u.Host].CA, "BEGIN CERTIFICATE")
+}
+
+func TestHostConfigMigratesTLS(t *testing.T) {
+	temp, _ := ioutil.TempDir("", "convox-test")
+	defer os.RemoveAll(temp)
+
+	root := ConfigRoot
+	ConfigRoot = temp
+	defer func() { ConfigRoot = root }()
+
+	require.Nil(t, ioutil.WriteFile(filepath.Join(temp, "tls"), []byte(`{"rack.example.org":{"pin":"sha256//AAAA"}}`), 0600))
+
+	hc, err := getHostConfig("rack.example.org")
+	require.Nil(t, err)
+	assert.Equal(t, "sha256//AAAA", hc.Pin)
+
+	_, err = os.Stat(filepath.Join(temp, "tls"))
+	assert.True(t, os.IsNotExist(err))
+
+	hc.Sign = true
+	require.Nil(t, setHostConfig("rack.example.org", hc))
+
+	hc, err = getHostConfig("rack.example.org")
+	require.Nil(t, err)
+	assert.Equal(t, ConfigHost{Pin: "sha256//AAAA", Sign: true}, hc)
+}
@@ -1,8 +1,12 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/convox/rack/cmd/convox/stdcli"
@@ -22,6 +26,10 @@ func init() {
 				Name:  "filter",
 				Usage: "filter the logs by a given token",
 			},
+			cli.StringFlag{
+				Name:  "process, p",
+				Usage: "only show the logs of this process",
+			},
 			cli.BoolTFlag{
 				Name:  "follow",
 				Usage: "keep streaming new log output (default)",
@@ -32,6 +40,42 @@ func init() {
 				Value: 2 * time.Minute,
 			},
 		},
+		Subcommands: []cli.Command{
+			{
+				Name:        "scrub",
+				Description: "list the rules that redact personal data from the logs of an app",
+				Usage:       "",
+				Action:      cmdLogsScrub,
+				Flags:       []cli.Flag{appFlag, rackFlag},
+				Subcommands: []cli.Command{
+					{
+						Name:        "add",
+						Description: "redact a field or pattern from logs before they are stored",
+						Usage:       "<name> (--field <name> | --pattern <regexp>)",
+						Action:      cmdLogsScrubAdd,
+						Flags: []cli.Flag{
+							appFlag,
+							rackFlag,
+							cli.StringFlag{
+								Name:  "field",
+								Usage: "redact the value of a key=value or json field with this name",
+							},
+							cli.StringFlag{
+								Name:  "pattern",
+								Usage: "redact text matching this regular expression",
+							},
+						},
+					},
+					{
+						Name:        "remove",
+						Description: "stop redacting logs with a rule",
+						Usage:       "<name>",
+						Action:      cmdLogsScrubRemove,
+						Flags:       []cli.Flag{appFlag, rackFlag},
+					},
+				},
+			},
+		},
 	})
 }
 
@@ -45,9 +89,194 @@ func cmdLogsStream(c *cli.Context) error {
 		return stdcli.ExitError(fmt.Errorf("`convox logs` does not take arguments. Perhaps you meant `convox logs`?"))
 	}
 
-	err = rackClient(c).StreamAppLogs(app, c.String("filter"), c.BoolT("follow"), c.Duration("since"), os.Stdout)
+	process := c.String("process")
+
+	if process != "" {
+		if err := validateProcessId(c, app, process); err != nil {
+			return stdcli.ExitError(err)
+		}
+	}
+
+	err = rackClient(c).StreamAppLogs(app, process, c.String("filter"), c.BoolT("follow"), c.Duration("since"), logsOutput(app))
 	if err != nil {
 		return stdcli.ExitError(err)
 	}
 	return nil
 }
+
+// logsOutput returns where to stream the logs of app, which is stdout as JSON objects with --output json
+func logsOutput(app string) io.WriteCloser {
+	if !stdcli.OutputJSON() {
+		return os.Stdout
+	}
+
+	return &logsJSONWriter{app: app, w: os.Stdout}
+}
+
+// logLine is a line of log output as printed by --output json
+type logLine struct {
+	Timestamp string `json:"timestamp,omitempty"`
+	App       string `json:"app,omitempty"`
+	Process   string `json:"process,omitempty"`
+	Container string `json:"container,omitempty"`
+	Message   string `json:"message"`
+}
+
+// parseLogLine splits a line of the form "time stream message" into its fields. App streams are
+// named process:release/container while ECS names rack streams prefix/process/container.
+// Lines that do not start with a timestamp are kept whole as the message.
+func parseLogLine(app, line string) logLine {
+	parts := strings.SplitN(line, " ", 3)
+
+	if len(parts) < 3 {
+		return logLine{Message: line}
+	}
+
+	if _, err := time.Parse(time.RFC3339, parts[0]); err != nil {
+		return logLine{Message: line}
+	}
+
+	l := logLine{Timestamp: parts[0], App: app, Message: parts[2]}
+
+	stream := parts[1]
+
+	if i := strings.Index(stream, ":"); i >= 0 {
+		l.Process = stream[:i]
+		stream = stream[i+1:]
+	}
+
+	segments := strings.Split(stream, "/")
+
+	if len(segments) > 1 {
+		l.Container = segments[len(segments)-1]
+
+		if l.Process == "" {
+			l.Process = segments[len(segments)-2]
+		}
+	} else if l.Process == "" {
+		l.Process = stream
+	}
+
+	return l
+}
+
+// logsJSONWriter writes each complete line of log output as a JSON object
+type logsJSONWriter struct {
+	app     string
+	w       io.WriteCloser
+	partial []byte
+}
+
+func (lw *logsJSONWriter) Write(p []byte) (int, error) {
+	data := append(lw.partial, p...)
+
+	i := bytes.LastIndexByte(data, '\n')
+	if i < 0 {
+		lw.partial = data
+		return len(p), nil
+	}
+
+	lw.partial = append([]byte{}, data[i+1:]...)
+
+	for _, line := range strings.Split(string(data[:i]), "\n") {
+		if err := lw.writeLine(line); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+func (lw *logsJSONWriter) Close() error {
+	if len(lw.partial) > 0 {
+		if err := lw.writeLine(string(lw.partial)); err != nil {
+			return err
+		}
+
+		lw.partial = nil
+	}
+
+	return lw.w.Close()
+}
+
+func (lw *logsJSONWriter) writeLine(line string) error {
+	data, err := json.Marshal(parseLogLine(lw.app, strings.TrimSuffix(line, "\r")))
+	if err != nil {
+		return err
+	}
+
+	_, err = lw.w.Write(append(data, '\n'))
+
+	return err
+}
+
+func cmdLogsScrub(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) > 0 {
+		return stdcli.ExitError(fmt.Errorf("`convox logs scrub` does not take arguments. Perhaps you meant `convox logs scrub add`?"))
+	}
+
+	rules, err := rackClient(c).GetLogScrubRules(app)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	t := stdcli.NewTable("NAME", "FIELD", "PATTERN")
+
+	for _, r := range rules {
+		t.AddRow(r.Name, r.Field, r.Pattern)
+	}
+
+	t.Print()
+	return nil
+}
+
+func cmdLogsScrubAdd(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) != 1 || (c.String("field") == "") == (c.String("pattern") == "") {
+		stdcli.Usage(c, "add")
+		return nil
+	}
+
+	name := c.Args()[0]
+
+	fmt.Printf("Adding log scrub rule %s... ", name)
+
+	if _, err := rackClient(c).CreateLogScrubRule(app, name, c.String("field"), c.String("pattern")); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+	return nil
+}
+
+func cmdLogsScrubRemove(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "remove")
+		return nil
+	}
+
+	name := c.Args()[0]
+
+	fmt.Printf("Removing log scrub rule %s... ", name)
+
+	if err := rackClient(c).DeleteLogScrubRule(app, name); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+	return nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogsUnknownProcess(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo/formation", Code: 200, Response: models.Formation{
+			{Name: "web", Count: 2},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox logs --app foo --process worker --since 1h --filter ERROR",
+			Exit:    1,
+			Stderr:  "ERROR: Unknown process name: worker\n",
+		},
+	)
+}
+
+func TestLogsScrub(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo/logs/scrub", Code: 200, Response: models.LogScrubRules{
+			{Name: "email", Field: "email"},
+			{Name: "cards", Pattern: `\d{4}-\d{4}-\d{4}-\d{4}`},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox logs scrub --app foo",
+			Exit:    0,
+			Stdout:  "NAME   FIELD  PATTERN\nemail  email\ncards         \\d{4}-\\d{4}-\\d{4}-\\d{4}\n",
+		},
+	)
+}
+
+func TestLogsScrubAdd(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "POST", Path: "/apps/foo/logs/scrub", Body: "field=email&name=email", Code: 200, Response: models.LogScrubRule{Name: "email", Field: "email"}},
+		test.Http{Method: "POST", Path: "/apps/bar/logs/scrub", Body: "name=cards&pattern=%5B", Code: 403, Response: client.Error{Error: "invalid pattern: error parsing regexp: missing closing ]: `[`"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox logs scrub add email --field email --app foo",
+			Exit:    0,
+			Stdout:  "Adding log scrub rule email... OK\n",
+		},
+		test.ExecRun{
+			Command: "convox logs scrub add cards --pattern [ --app bar",
+			Exit:    1,
+			Stdout:  "Adding log scrub rule cards... ",
+			Stderr:  "ERROR: invalid pattern: error parsing regexp: missing closing ]: `[`\n",
+		},
+	)
+}
+
+func TestLogsScrubRemove(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "DELETE", Path: "/apps/foo/logs/scrub/email", Code: 200, Response: map[string]bool{"success": true}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox logs scrub remove email --app foo",
+			Exit:    0,
+			Stdout:  "Removing log scrub rule email... OK\n",
+		},
+	)
+}
+
+func TestParseLogLine(t *testing.T) {
+	lines := map[string]logLine{
+		"2016-10-01T12:00:00Z web:RABCDEFGHI/0123456789ab GET / 200": {
+			Timestamp: "2016-10-01T12:00:00Z", App: "foo", Process: "web", Container: "0123456789ab", Message: "GET / 200",
+		},
+		"2016-10-01T12:00:00Z convox/web/0123456789ab ns=api at=start": {
+			Timestamp: "2016-10-01T12:00:00Z", App: "foo", Process: "web", Container: "0123456789ab", Message: "ns=api at=start",
+		},
+		"2016-10-01T12:00:00Z build starting": {
+			Timestamp: "2016-10-01T12:00:00Z", App: "foo", Process: "build", Message: "starting",
+		},
+		"connection lost, reconnecting": {
+			Message: "connection lost, reconnecting",
+		},
+	}
+
+	for line, expected := range lines {
+		assert.Equal(t, expected, parseLogLine("foo", line), line)
+	}
+}
+
+func TestLogsJSONWriter(t *testing.T) {
+	out := &bufferCloser{}
+	w := &logsJSONWriter{app: "foo", w: out}
+
+	w.Write([]byte("2016-10-01T12:00:00Z web:RA/1 first\n2016-10-01T12:00:01Z web:RA/1 sec"))
+	w.Write([]byte("ond\n2016-10-01T12:00:02Z web:RA/1 last"))
+	w.Close()
+
+	assert.Equal(t, `{"timestamp":"2016-10-01T12:00:00Z","app":"foo","process":"web","container":"1","message":"first"}
+{"timestamp":"2016-10-01T12:00:01Z","app":"foo","process":"web","container":"1","message":"second"}
+{"timestamp":"2016-10-01T12:00:02Z","app":"foo","process":"web","container":"1","message":"last"}
+`, out.String())
+	assert.True(t, out.closed)
+}
+
+type bufferCloser struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (b *bufferCloser) Close() error {
+	b.closed = true
+	return nil
+}
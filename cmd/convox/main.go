@@ -5,6 +5,8 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"gopkg.in/urfave/cli.v1"
@@ -64,7 +66,64 @@ func currentRack(c *cli.Context) string {
 	return coalesce(c.String("rack"), os.Getenv("CONVOX_RACK"), stdcli.ReadSetting("rack"), strings.TrimSpace(string(cr)))
 }
 
-func rackClient(c *cli.Context) *client.Client {
+// bandwidthRate matches upper cased rates like 5MB/S, 512K or 100000
+var bandwidthRate = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*([KMG]?)B?(?:/S)?$`)
+
+// parseBandwidth returns the bytes per second of a rate like 5MB/s
+func parseBandwidth(rate string) (int64, error) {
+	m := bandwidthRate.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(rate)))
+	if m == nil {
+		return 0, fmt.Errorf("invalid bandwidth limit: %s, use a rate like 5MB/s", rate)
+	}
+
+	n, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, err
+	}
+
+	switch m[2] {
+	case "K":
+		n *= 1024
+	case "M":
+		n *= 1024 * 1024
+	case "G":
+		n *= 1024 * 1024 * 1024
+	}
+
+	if n < 1 {
+		return 0, fmt.Errorf("invalid bandwidth limit: %s, use a rate like 5MB/s", rate)
+	}
+
+	return int64(n), nil
+}
+
+// currentBandwidthLimit returns the bytes per second uploads are limited to, 0 for no limit
+func currentBandwidthLimit(c *cli.Context) (int64, error) {
+	cb, err := ioutil.ReadFile(filepath.Join(ConfigRoot, "limit-bandwidth"))
+	if err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+
+	rate := coalesce(c.String("limit-bandwidth"), os.Getenv("CONVOX_LIMIT_BANDWIDTH"), stdcli.ReadSetting("limit-bandwidth"), strings.TrimSpace(string(cb)))
+
+	if rate == "" {
+		return 0, nil
+	}
+
+	return parseBandwidth(rate)
+}
+
+// newRackClient connects a command to a rack. Tests replace it to run commands against client/fake.
+var newRackClient = func(c *cli.Context, rack string) client.Interface {
+	return liveRackClient(c, rack)
+}
+
+// rackClient returns the client a command talks to the current rack with
+func rackClient(c *cli.Context) client.Interface {
+	return newRackClient(c, currentRack(c))
+}
+
+func liveRackClient(c *cli.Context, rack string) client.Interface {
 	host, password, err := currentLogin()
 	if err != nil {
 		stdcli.Error(err)
@@ -73,7 +132,27 @@ func rackClient(c *cli.Context) *client.Client {
 
 	cl := client.New(host, password, c.App.Version)
 
-	cl.Rack = currentRack(c)
+	hc, err := getHostConfig(host)
+	if err != nil {
+		stdcli.Error(err)
+		return nil
+	}
+
+	if err := configureClient(cl, hc); err != nil {
+		stdcli.Error(err)
+		return nil
+	}
+
+	cl.Rack = rack
+	cl.User = currentUser()
+
+	limit, err := currentBandwidthLimit(c)
+	if err != nil {
+		stdcli.Error(err)
+		return nil
+	}
+
+	cl.BandwidthLimit = limit
 
 	return cl
 }
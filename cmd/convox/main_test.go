@@ -1,17 +1,26 @@
 package main
 
 import (
+	"bytes"
+	"fmt"
+	"io"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/cmd/convox/stdcli"
 	"github.com/convox/rack/test"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/urfave/cli.v1"
 )
 
 func testServer(t *testing.T, stubs ...test.Http) *httptest.Server {
-	stubs = append(stubs, test.Http{Method: "GET", Path: "/system", Code: 200, Response: client.System{
+	stubs = append(stubs, test.Http{Method: "GET", Path: "/system", Code: 200, Response: models.System{
 		Version: "latest",
 	}})
 
@@ -24,3 +33,156 @@ func testServer(t *testing.T, stubs ...test.Http) *httptest.Server {
 
 	return server
 }
+
+var (
+	fakeApp     *cli.App
+	fakeAppOnce sync.Once
+)
+
+// fakeExit stops a command run by fakeRuns where the convox binary would exit
+type fakeExit int
+
+// fakeRuns runs each command inside the test against rc instead of a rack, checking its
+// exit code and output the way test.Runs checks the convox binary
+func fakeRuns(t *testing.T, rc client.Interface, runs ...test.ExecRun) {
+	// stdcli.New adds the output flag to the shared commands so only build the app once
+	fakeAppOnce.Do(func() {
+		fakeApp = stdcli.New()
+	})
+
+	prev := newRackClient
+
+	newRackClient = func(c *cli.Context, rack string) client.Interface {
+		return rc
+	}
+
+	defer func() { newRackClient = prev }()
+
+	for _, run := range runs {
+		stdout, stderr, code := fakeRun(run)
+
+		assert.Equal(t, run.Exit, code, fmt.Sprintf("%s: exit code should be equal", run.Command))
+		if run.Stdout != "" {
+			assert.Equal(t, run.Stdout, stdout, fmt.Sprintf("%s: stdout should be equal", run.Command))
+		}
+		if run.OutMatch != "" {
+			assert.Contains(t, stdout, run.OutMatch, fmt.Sprintf("%s: stdout %q should contain %q", run.Command, stdout, run.OutMatch))
+		}
+		if run.Stderr != "" {
+			assert.Contains(t, stderr, run.Stderr, fmt.Sprintf("%s: stderr %q should contain %q", run.Command, stderr, run.Stderr))
+		}
+	}
+}
+
+func fakeRun(run test.ExecRun) (string, string, int) {
+	for k, v := range run.Env {
+		if old, ok := os.LookupEnv(k); ok {
+			defer os.Setenv(k, old)
+		} else {
+			defer os.Unsetenv(k)
+		}
+
+		os.Setenv(k, v)
+	}
+
+	stdout := capture(&os.Stdout)
+	stderr := capture(&os.Stderr)
+
+	exiter, osExiter, errWriter := stdcli.Exiter, cli.OsExiter, cli.ErrWriter
+
+	exit := func(code int) { panic(fakeExit(code)) }
+
+	stdcli.Exiter, cli.OsExiter, cli.ErrWriter = exit, exit, os.Stderr
+
+	code := func() (code int) {
+		defer func() {
+			if r := recover(); r != nil {
+				c, ok := r.(fakeExit)
+				if !ok {
+					panic(r)
+				}
+				code = int(c)
+			}
+		}()
+
+		if err := fakeApp.Run(append([]string{"convox"}, strings.Fields(run.Command)[1:]...)); err != nil {
+			return 1
+		}
+
+		return 0
+	}()
+
+	stdcli.Exiter, cli.OsExiter, cli.ErrWriter = exiter, osExiter, errWriter
+	stdcli.OutputFormat = "table"
+
+	return stdout(), stderr(), code
+}
+
+// capture points f at a pipe until the returned func restores it and returns what was written
+func capture(f **os.File) func() string {
+	buf := &bytes.Buffer{}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		panic(err)
+	}
+
+	orig := *f
+	*f = w
+
+	done := make(chan struct{})
+
+	go func() {
+		io.Copy(buf, r)
+		close(done)
+	}()
+
+	return func() string {
+		*f = orig
+		w.Close()
+		<-done
+		r.Close()
+		return buf.String()
+	}
+}
+
+func TestParseBandwidth(t *testing.T) {
+	rates := map[string]int64{
+		"5MB/s":  5 * 1024 * 1024,
+		"512k":   512 * 1024,
+		"1.5M":   1536 * 1024,
+		"100000": 100000,
+		"2 GB/s": 2 * 1024 * 1024 * 1024,
+	}
+
+	for rate, n := range rates {
+		limit, err := parseBandwidth(rate)
+		assert.NoError(t, err, rate)
+		assert.Equal(t, n, limit, rate)
+	}
+
+	for _, rate := range []string{"fast", "5Mbps", "0", "-1MB/s"} {
+		_, err := parseBandwidth(rate)
+		assert.EqualError(t, err, "invalid bandwidth limit: "+rate+", use a rate like 5MB/s")
+	}
+}
+
+func TestBandwidthLimitInvalid(t *testing.T) {
+	ts := testServer(t)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox build --app foo --limit-bandwidth fast",
+			Exit:    1,
+			Stderr:  "ERROR: invalid bandwidth limit: fast, use a rate like 5MB/s\n",
+		},
+		test.ExecRun{
+			Command: "convox build --app foo",
+			Env:     map[string]string{"CONVOX_LIMIT_BANDWIDTH": "slow"},
+			Exit:    1,
+			Stderr:  "ERROR: invalid bandwidth limit: slow, use a rate like 5MB/s\n",
+		},
+	)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/cmd/convox/stdcli"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// sparkBars are the characters of a sparkline from the lowest value to the highest
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+func init() {
+	stdcli.RegisterCommand(cli.Command{
+		Name:        "metrics",
+		Description: "show cpu, memory and request metrics of an app",
+		Usage:       "[--period 1h] [--process <name>]",
+		Action:      cmdMetrics,
+		Flags: []cli.Flag{
+			appFlag,
+			rackFlag,
+			cli.DurationFlag{
+				Name:  "period",
+				Usage: "how far back to show metrics for",
+				Value: time.Hour,
+			},
+			cli.StringFlag{
+				Name:  "process, p",
+				Usage: "only show the metrics of this process",
+			},
+		},
+	})
+}
+
+func cmdMetrics(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) > 0 {
+		return stdcli.ExitError(fmt.Errorf("`convox metrics` does not take arguments. Perhaps you meant `convox metrics --process %s`?", c.Args()[0]))
+	}
+
+	metrics, err := rackClient(c).GetAppMetrics(app, c.String("process"), c.Duration("period"))
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	t := stdcli.NewTable("PROCESS", "METRIC", "LATEST", "MIN", "MAX", "TREND")
+
+	for _, m := range metrics {
+		if len(m.Points) == 0 {
+			t.AddRow(m.Process, m.Name, "-", "-", "-", "")
+			continue
+		}
+
+		min, max := math.Inf(1), math.Inf(-1)
+
+		for _, p := range m.Points {
+			min = math.Min(min, p.Value)
+			max = math.Max(max, p.Value)
+		}
+
+		latest := m.Points[len(m.Points)-1].Value
+
+		t.AddRow(m.Process, m.Name, metricValue(m.Unit, latest), metricValue(m.Unit, min), metricValue(m.Unit, max), sparkline(m.Points))
+	}
+
+	t.Print()
+	return nil
+}
+
+// metricValue formats a measurement in its unit
+func metricValue(unit string, value float64) string {
+	switch unit {
+	case "percent":
+		return fmt.Sprintf("%0.1f%%", value)
+	case "milliseconds":
+		return fmt.Sprintf("%0.0fms", value)
+	}
+
+	return fmt.Sprintf("%0.0f", value)
+}
+
+// sparkline draws points as bars scaled between their lowest and highest value
+func sparkline(points models.MetricPoints) string {
+	if len(points) == 0 {
+		return ""
+	}
+
+	min, max := points[0].Value, points[0].Value
+
+	for _, p := range points {
+		min = math.Min(min, p.Value)
+		max = math.Max(max, p.Value)
+	}
+
+	line := make([]rune, len(points))
+
+	for i, p := range points {
+		bar := 0
+
+		if max > min {
+			bar = int(math.Floor((p.Value-min)/(max-min)*float64(len(sparkBars)-1) + 0.5))
+		}
+
+		line[i] = sparkBars[bar]
+	}
+
+	return string(line)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	t0 := time.Date(2016, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	points := func(values ...float64) models.MetricPoints {
+		ps := models.MetricPoints{}
+
+		for i, v := range values {
+			ps = append(ps, models.MetricPoint{Time: t0.Add(time.Duration(i) * time.Minute), Value: v})
+		}
+
+		return ps
+	}
+
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo/metrics", Code: 200, Response: models.Metrics{
+			{Process: "web", Name: "cpu", Unit: "percent", Points: points(10, 40, 25)},
+			{Process: "web", Name: "requests", Unit: "count", Points: points(120, 80, 300)},
+			{Process: "web", Name: "latency", Unit: "milliseconds", Points: points(42.4, 42.4)},
+			{Process: "web", Name: "5xx", Unit: "percent", Points: points()},
+		}},
+		test.Http{Method: "GET", Path: "/apps/bar/metrics", Code: 404, Response: client.Error{Error: "no such process: worker"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox metrics --app foo --period 3m",
+			Exit:    0,
+			Stdout:  "PROCESS  METRIC    LATEST  MIN    MAX    TREND\nweb      cpu       25.0%   10.0%  40.0%  ▁█▅\nweb      requests  300     80     300    ▂▁█\nweb      latency   42ms    42ms   42ms   ▁▁\nweb      5xx       -       -      -\n",
+		},
+		test.ExecRun{
+			Command: "convox metrics --app bar --process worker",
+			Exit:    1,
+			Stderr:  "ERROR: no such process: worker\n",
+		},
+	)
+}
+
+func TestSparkline(t *testing.T) {
+	ps := models.MetricPoints{}
+
+	for i := 0; i < 8; i++ {
+		ps = append(ps, models.MetricPoint{Value: float64(i * 10)})
+	}
+
+	assert.Equal(t, "▁▂▃▄▅▆▇█", sparkline(ps))
+	assert.Equal(t, "", sparkline(models.MetricPoints{}))
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/cmd/convox/stdcli"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func init() {
+	stdcli.RegisterCommand(cli.Command{
+		Name:        "monitors",
+		Description: "manage health check monitors of an app",
+		Usage:       "",
+		Action:      cmdMonitors,
+		Flags:       []cli.Flag{appFlag, rackFlag},
+		Subcommands: []cli.Command{
+			{
+				Name:        "create",
+				Description: "check a url from the rack and alert when it fails",
+				Usage:       "--url <url> [--interval 30s] [--threshold 3] [--max-latency 2s]",
+				Action:      cmdMonitorCreate,
+				Flags: []cli.Flag{
+					appFlag,
+					rackFlag,
+					cli.StringFlag{
+						Name:  "url",
+						Usage: "url to request",
+					},
+					cli.DurationFlag{
+						Name:  "interval",
+						Usage: "time between checks (default 30s)",
+					},
+					cli.IntFlag{
+						Name:  "threshold",
+						Usage: "consecutive failed checks before alerting (default 3)",
+					},
+					cli.DurationFlag{
+						Name:  "max-latency",
+						Usage: "count responses slower than this as failures",
+					},
+				},
+			},
+			{
+				Name:        "info",
+				Description: "show a monitor and its recent checks",
+				Usage:       "<ID>",
+				Action:      cmdMonitorInfo,
+				Flags:       []cli.Flag{appFlag, rackFlag},
+			},
+			{
+				Name:        "delete",
+				Description: "stop a monitor and discard its history",
+				Usage:       "<ID>",
+				Action:      cmdMonitorDelete,
+				Flags:       []cli.Flag{appFlag, rackFlag},
+			},
+		},
+	})
+}
+
+func cmdMonitors(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) > 0 {
+		return stdcli.ExitError(fmt.Errorf("`convox monitors` does not take arguments. Perhaps you meant `convox monitors create`?"))
+	}
+
+	monitors, err := rackClient(c).GetMonitors(app)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	t := stdcli.NewTable("ID", "URL", "INTERVAL", "STATUS", "AVAILABILITY", "LATENCY")
+
+	for _, m := range monitors {
+		t.AddRow(m.Id, m.URL, fmt.Sprintf("%ds", m.Interval), m.Status, fmt.Sprintf("%0.2f%%", m.Checks.Availability()), fmt.Sprintf("%dms", m.Checks.AverageLatency()))
+	}
+
+	t.Print()
+	return nil
+}
+
+func cmdMonitorCreate(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if c.String("url") == "" {
+		stdcli.Usage(c, "create")
+		return nil
+	}
+
+	fmt.Printf("Creating monitor for %s... ", c.String("url"))
+
+	m, err := rackClient(c).CreateMonitor(app, c.String("url"), client.MonitorOptions{
+		Interval:   c.Duration("interval"),
+		MaxLatency: c.Duration("max-latency"),
+		Threshold:  c.Int("threshold"),
+	})
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println(m.Id)
+	return nil
+}
+
+func cmdMonitorInfo(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "info")
+		return nil
+	}
+
+	m, err := rackClient(c).GetMonitor(app, c.Args()[0])
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	maxLatency := "none"
+
+	if m.MaxLatency > 0 {
+		maxLatency = fmt.Sprintf("%dms", m.MaxLatency)
+	}
+
+	fmt.Printf("Id            %s\n", m.Id)
+	fmt.Printf("URL           %s\n", m.URL)
+	fmt.Printf("Interval      %ds\n", m.Interval)
+	fmt.Printf("Threshold     %d\n", m.Threshold)
+	fmt.Printf("Max Latency   %s\n", maxLatency)
+	fmt.Printf("Status        %s\n", m.Status)
+	fmt.Printf("Availability  %0.2f%%\n", m.Checks.Availability())
+	fmt.Printf("Latency       %dms\n", m.Checks.AverageLatency())
+
+	if len(m.Checks) == 0 {
+		return nil
+	}
+
+	fmt.Println()
+
+	t := stdcli.NewTable("TIME", "RESULT", "CODE", "LATENCY", "ERROR")
+
+	// show the most recent checks first
+	for i := len(m.Checks) - 1; i >= 0 && i >= len(m.Checks)-10; i-- {
+		check := m.Checks[i]
+
+		result := "down"
+
+		if check.Up {
+			result = "up"
+		}
+
+		t.AddRow(humanizeTime(check.Time), result, strconv.Itoa(check.Code), fmt.Sprintf("%dms", check.Latency), check.Error)
+	}
+
+	t.Print()
+	return nil
+}
+
+func cmdMonitorDelete(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "delete")
+		return nil
+	}
+
+	id := c.Args()[0]
+
+	fmt.Printf("Deleting monitor %s... ", id)
+
+	if _, err := rackClient(c).DeleteMonitor(app, id); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+	return nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/test"
+)
+
+func TestMonitors(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo/monitors", Code: 200, Response: models.Monitors{
+			{Id: "M1234", URL: "https://example.com/healthz", Interval: 30, Status: "up", Checks: models.MonitorChecks{
+				{Up: true, Latency: 120},
+				{Up: true, Latency: 80},
+				{Up: false, Error: "response status 502"},
+				{Up: true, Latency: 100},
+			}},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox monitors --app foo",
+			Exit:    0,
+			Stdout:  "ID     URL                          INTERVAL  STATUS  AVAILABILITY  LATENCY\nM1234  https://example.com/healthz  30s       up      75.00%        100ms\n",
+		},
+	)
+}
+
+func TestMonitorsCreate(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "POST", Path: "/apps/foo/monitors", Body: "interval=30&max-latency=2000&url=https%3A%2F%2Fexample.com%2Fhealthz", Code: 200, Response: models.Monitor{Id: "M1234"}},
+		test.Http{Method: "POST", Path: "/apps/bar/monitors", Body: "url=example.com", Code: 403, Response: client.Error{Error: "url must start with http:// or https://"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox monitors create --app foo --url https://example.com/healthz --interval 30s --max-latency 2s",
+			Exit:    0,
+			Stdout:  "Creating monitor for https://example.com/healthz... M1234\n",
+		},
+		test.ExecRun{
+			Command: "convox monitors create --app bar --url example.com",
+			Exit:    1,
+			Stdout:  "Creating monitor for example.com... ",
+			Stderr:  "ERROR: url must start with http:// or https://\n",
+		},
+	)
+}
+
+func TestMonitorsInfo(t *testing.T) {
+	checked := time.Now().UTC().Add(-2 * time.Minute)
+
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo/monitors/M1234", Code: 200, Response: models.Monitor{
+			Id: "M1234", URL: "https://example.com/healthz", Interval: 30, Threshold: 3, Status: "down", Checks: models.MonitorChecks{
+				{Time: checked, Up: false, Code: 502, Latency: 40, Error: "response status 502"},
+			},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox monitors info M1234 --app foo",
+			Exit:    0,
+			Stdout:  "Id            M1234\nURL           https://example.com/healthz\nInterval      30s\nThreshold     3\nMax Latency   none\nStatus        down\nAvailability  0.00%\nLatency       0ms\n\nTIME           RESULT  CODE  LATENCY  ERROR\n2 minutes ago  down    502   40ms     response status 502\n",
+		},
+	)
+}
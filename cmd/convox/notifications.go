@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/convox/rack/cmd/convox/stdcli"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func init() {
+	stdcli.RegisterCommand(cli.Command{
+		Name:        "notifications",
+		Description: "manage notifications of rack events",
+		Usage:       "",
+		Action:      cmdNotifications,
+		Flags:       []cli.Flag{rackFlag},
+		Subcommands: []cli.Command{
+			{
+				Name:        "add",
+				Description: "send rack events to slack, a webhook or an sns topic",
+				Usage:       "<slack://hooks.slack.com/services/...|webhook://https://...|sns://arn:aws:sns:...> [--events build,release:promote]",
+				Action:      cmdNotificationAdd,
+				Flags: []cli.Flag{
+					rackFlag,
+					cli.StringFlag{
+						Name:  "events",
+						Usage: "comma separated events or kinds of events to send, all events by default",
+					},
+				},
+			},
+			{
+				Name:        "remove",
+				Description: "stop sending rack events to a notification",
+				Usage:       "<id>",
+				Action:      cmdNotificationRemove,
+				Flags:       []cli.Flag{rackFlag},
+			},
+		},
+	})
+}
+
+func cmdNotifications(c *cli.Context) error {
+	if len(c.Args()) > 0 {
+		return stdcli.ExitError(fmt.Errorf("`convox notifications` does not take arguments. Perhaps you meant `convox notifications add`?"))
+	}
+
+	notifications, err := rackClient(c).GetNotifications()
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	t := stdcli.NewTable("ID", "TYPE", "TARGET", "EVENTS")
+
+	for _, n := range notifications {
+		events := "all"
+
+		if len(n.Events) > 0 {
+			events = strings.Join(n.Events, ",")
+		}
+
+		t.AddRow(n.Id, n.Type, n.Target, events)
+	}
+
+	t.Print()
+	return nil
+}
+
+func cmdNotificationAdd(c *cli.Context) error {
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "add")
+		return nil
+	}
+
+	events := []string{}
+
+	if e := c.String("events"); e != "" {
+		events = strings.Split(e, ",")
+	}
+
+	fmt.Print("Adding notification... ")
+
+	n, err := rackClient(c).AddNotification(c.Args()[0], events)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Printf("OK, %s\n", n.Id)
+	return nil
+}
+
+func cmdNotificationRemove(c *cli.Context) error {
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "remove")
+		return nil
+	}
+
+	id := c.Args()[0]
+
+	fmt.Printf("Removing %s... ", id)
+
+	if err := rackClient(c).RemoveNotification(id); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+	return nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/test"
+)
+
+func TestNotifications(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/notifications", Code: 200, Response: models.Notifications{
+			{Id: "N1234567890", Type: "slack", Target: "https://hooks.slack.com/services/T000/B000/XXXX"},
+			{Id: "N0987654321", Type: "sns", Target: "arn:aws:sns:us-east-1:123456789012:deploys", Events: []string{"build", "release:promote"}},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox notifications",
+			Exit:    0,
+			Stdout:  "ID           TYPE   TARGET                                           EVENTS\nN1234567890  slack  https://hooks.slack.com/services/T000/B000/XXXX  all\nN0987654321  sns    arn:aws:sns:us-east-1:123456789012:deploys       build,release:promote\n",
+		},
+	)
+}
+
+func TestNotificationsAdd(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "POST", Path: "/notifications", Body: "events=build%2Crelease%3Apromote&url=slack%3A%2F%2Fhooks.slack.com%2Fservices%2FT000%2FB000%2FXXXX", Code: 200, Response: models.Notification{Id: "N1234567890"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox notifications add slack://hooks.slack.com/services/T000/B000/XXXX --events build,release:promote",
+			Exit:    0,
+			Stdout:  "Adding notification... OK, N1234567890\n",
+		},
+	)
+}
+
+func TestNotificationsAddInvalid(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "POST", Path: "/notifications", Body: "url=sns%3A%2F%2Fdeploys", Code: 403, Response: client.Error{Error: "sns notification must be a topic arn: deploys"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox notifications add sns://deploys",
+			Exit:    1,
+			Stdout:  "Adding notification... ",
+			Stderr:  "ERROR: sns notification must be a topic arn: deploys\n",
+		},
+	)
+}
+
+func TestNotificationsRemove(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "DELETE", Path: "/notifications/N1234567890", Code: 200, Response: map[string]bool{"success": true}},
+		test.Http{Method: "DELETE", Path: "/notifications/N0000000000", Code: 404, Response: client.Error{Error: "no such notification: N0000000000"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox notifications remove N1234567890",
+			Exit:    0,
+			Stdout:  "Removing N1234567890... OK\n",
+		},
+		test.ExecRun{
+			Command: "convox notifications remove N0000000000",
+			Exit:    1,
+			Stdout:  "Removing N0000000000... ",
+			Stderr:  "ERROR: no such notification: N0000000000\n",
+		},
+	)
+}
@@ -0,0 +1,386 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/cmd/convox/stdcli"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Pipelines span racks, they are kept on the rack they are created on (--rack) which tracks their
+// runs and decides whether a stage may start. The cli promotes each stage with its own
+// credentials for the rack of the stage.
+
+func init() {
+	stdcli.RegisterCommand(cli.Command{
+		Name:        "pipelines",
+		Description: "promote builds of an app through a series of racks",
+		Usage:       "",
+		Action:      cmdPipelines,
+		Flags:       []cli.Flag{rackFlag},
+		Subcommands: []cli.Command{
+			{
+				Name:        "create",
+				Description: "create a pipeline",
+				Usage:       "--stages <stage:rack,...> [--require-approval <stage,...>] <name>",
+				Action:      cmdPipelineCreate,
+				Flags: []cli.Flag{
+					appFlag,
+					rackFlag,
+					cli.StringFlag{
+						Name:  "stages",
+						Usage: "comma separated stages in the order builds are promoted, i.e. staging:org/staging,production:org/production",
+					},
+					cli.StringFlag{
+						Name:  "require-approval",
+						Usage: "comma separated stages that wait for `convox pipelines approve` before promoting",
+					},
+				},
+			},
+			{
+				Name:        "delete",
+				Description: "delete a pipeline",
+				Usage:       "<name>",
+				Action:      cmdPipelineDelete,
+				Flags:       []cli.Flag{rackFlag},
+			},
+			{
+				Name:        "run",
+				Description: "promote a build of the first stage through the pipeline",
+				Usage:       "<name> <build id>",
+				Action:      cmdPipelineRun,
+				Flags:       []cli.Flag{rackFlag},
+			},
+			{
+				Name:        "approve",
+				Description: "approve a run that is waiting for approval and continue it",
+				Usage:       "<name> <run id>",
+				Action:      cmdPipelineApprove,
+				Flags:       []cli.Flag{rackFlag},
+			},
+			{
+				Name:        "runs",
+				Description: "list the runs of a pipeline",
+				Usage:       "<name>",
+				Action:      cmdPipelineRuns,
+				Flags:       []cli.Flag{rackFlag},
+			},
+		},
+	})
+}
+
+func cmdPipelines(c *cli.Context) error {
+	if len(c.Args()) > 0 {
+		return stdcli.ExitError(fmt.Errorf("`convox pipelines` does not take arguments. Perhaps you meant `convox pipelines runs`?"))
+	}
+
+	pipelines, err := rackClient(c).GetPipelines()
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	t := stdcli.NewTable("NAME", "APP", "STAGES")
+
+	for _, p := range pipelines {
+		stages := []string{}
+
+		for _, s := range p.Stages {
+			stage := fmt.Sprintf("%s:%s", s.Name, s.Rack)
+
+			if s.Approval {
+				stage += "*"
+			}
+
+			stages = append(stages, stage)
+		}
+
+		t.AddRow(p.Name, p.App, strings.Join(stages, " -> "))
+	}
+
+	t.Print()
+	return nil
+}
+
+func cmdPipelineCreate(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) != 1 || c.String("stages") == "" {
+		stdcli.Usage(c, "create")
+		return nil
+	}
+
+	name := c.Args()[0]
+
+	fmt.Printf("Creating pipeline %s... ", name)
+
+	if _, err := rackClient(c).CreatePipeline(name, app, c.String("stages"), c.String("require-approval")); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+	return nil
+}
+
+func cmdPipelineDelete(c *cli.Context) error {
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "delete")
+		return nil
+	}
+
+	name := c.Args()[0]
+
+	fmt.Printf("Deleting pipeline %s... ", name)
+
+	if err := rackClient(c).DeletePipeline(name); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+	return nil
+}
+
+func cmdPipelineRun(c *cli.Context) error {
+	if len(c.Args()) != 2 {
+		stdcli.Usage(c, "run")
+		return nil
+	}
+
+	p, err := pipelineGet(c, c.Args()[0])
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	run, err := rackClient(c).CreatePipelineRun(p.Name, c.Args()[1])
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Printf("Run: %s\n", run.Id)
+
+	if err := advancePipelineRun(c, p, run); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	return nil
+}
+
+func cmdPipelineApprove(c *cli.Context) error {
+	if len(c.Args()) != 2 {
+		stdcli.Usage(c, "approve")
+		return nil
+	}
+
+	p, err := pipelineGet(c, c.Args()[0])
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	run, err := rackClient(c).ApprovePipelineRun(p.Name, c.Args()[1])
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if err := advancePipelineRun(c, p, run); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	return nil
+}
+
+func cmdPipelineRuns(c *cli.Context) error {
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "runs")
+		return nil
+	}
+
+	runs, err := rackClient(c).GetPipelineRuns(c.Args()[0])
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	t := stdcli.NewTable("ID", "BUILD", "STAGE", "STATUS", "STARTED")
+
+	for _, r := range runs {
+		t.AddRow(r.Id, r.Build, pipelineCurrentStage(r), r.Status, humanizeTime(r.Started))
+	}
+
+	t.Print()
+	return nil
+}
+
+// pipelineGet returns a pipeline kept on the rack
+func pipelineGet(c *cli.Context, name string) (*models.Pipeline, error) {
+	pipelines, err := rackClient(c).GetPipelines()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range pipelines {
+		if p.Name == name {
+			return &p, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no such pipeline: %s", name)
+}
+
+// advancePipelineRun promotes a run through its remaining stages, reporting each stage to the
+// rack of the pipeline, and stops at a stage the rack holds for approval
+func advancePipelineRun(c *cli.Context, p *models.Pipeline, run *models.PipelineRun) error {
+	rc := rackClient(c)
+
+	for i := range run.Stages {
+		if run.Stages[i].Status == "complete" {
+			continue
+		}
+
+		name := run.Stages[i].Name
+
+		next, err := rc.UpdatePipelineStage(p.Name, run.Id, name, "running", "", "")
+		if err != nil && strings.HasSuffix(err.Error(), "requires approval") {
+			fmt.Printf("Stage %s requires approval, run `convox pipelines approve %s %s` to continue\n", name, p.Name, run.Id)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		run = next
+
+		if err := runPipelineStage(c, p.App, run, i); err != nil {
+			if _, uerr := rc.UpdatePipelineStage(p.Name, run.Id, name, "failed", "", ""); uerr != nil {
+				return uerr
+			}
+
+			return err
+		}
+
+		rs := run.Stages[i]
+
+		next, err = rc.UpdatePipelineStage(p.Name, run.Id, name, "complete", rs.Build, rs.Release)
+		if err != nil {
+			return err
+		}
+
+		run = next
+	}
+
+	return nil
+}
+
+// runPipelineStage copies the build of the previous stage to the rack of a stage and promotes it
+func runPipelineStage(c *cli.Context, app string, run *models.PipelineRun, i int) error {
+	rs := &run.Stages[i]
+	rc := pipelineClient(c, rs.Rack)
+
+	fmt.Printf("Stage %s (%s)\n", rs.Name, rs.Rack)
+
+	if i == 0 {
+		r, err := rc.CreateBuildRelease(app, rs.Build)
+		if err != nil {
+			return err
+		}
+
+		rs.Release = r.Id
+	} else {
+		prev := run.Stages[i-1]
+
+		fmt.Printf("Copying %s from %s... ", prev.Build, prev.Rack)
+
+		tmp, err := ioutil.TempFile("", "convox-pipeline")
+		if err != nil {
+			return err
+		}
+
+		defer os.Remove(tmp.Name())
+		defer tmp.Close()
+
+		if err := pipelineClient(c, prev.Rack).ExportBuild(app, prev.Build, tmp); err != nil {
+			return err
+		}
+
+		if _, err := tmp.Seek(0, 0); err != nil {
+			return err
+		}
+
+		b, err := rc.ImportBuild(app, tmp, func(s string) {})
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(b.Id)
+
+		rs.Build = b.Id
+		rs.Release = b.Release
+	}
+
+	fmt.Printf("Promoting %s... ", rs.Release)
+
+	if _, err := rc.PromoteRelease(app, rs.Release); err != nil {
+		return err
+	}
+
+	if err := waitForPipelinePromotion(rc, app, rs.Release); err != nil {
+		return err
+	}
+
+	fmt.Println("OK")
+	return nil
+}
+
+// waitForPipelinePromotion waits for a release to be running on the rack of a client
+func waitForPipelinePromotion(rc client.Interface, app, release string) error {
+	timeout := time.After(30 * time.Minute)
+	tick := time.Tick(5 * time.Second)
+
+	for {
+		select {
+		case <-tick:
+			a, err := rc.GetApp(app)
+			if err != nil {
+				return err
+			}
+
+			if a.Status != "running" {
+				continue
+			}
+
+			if a.Release != release {
+				return fmt.Errorf("%s was rolled back", release)
+			}
+
+			return nil
+		case <-timeout:
+			return fmt.Errorf("timeout waiting for %s to promote", release)
+		}
+	}
+}
+
+// pipelineClient returns a client for the rack of a stage
+func pipelineClient(c *cli.Context, rack string) client.Interface {
+	return newRackClient(c, rack)
+}
+
+// pipelineCurrentStage is the stage a run is on or stopped at
+func pipelineCurrentStage(r models.PipelineRun) string {
+	for _, s := range r.Stages {
+		if s.Status != "complete" {
+			return s.Name
+		}
+	}
+
+	if len(r.Stages) > 0 {
+		return r.Stages[len(r.Stages)-1].Name
+	}
+
+	return ""
+}
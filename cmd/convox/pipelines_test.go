@@ -0,0 +1,157 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/test"
+)
+
+func TestPipelinesCreate(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "POST", Path: "/pipelines", Body: "app=foo&name=web&require-approval=production&stages=staging%3Aorg%2Fstaging%2Cproduction%3Aorg%2Fproduction", Code: 200, Response: models.Pipeline{Name: "web"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox pipelines create --app foo --stages staging:org/staging,production:org/production --require-approval production web",
+			Exit:    0,
+			Stdout:  "Creating pipeline web... OK\n",
+		},
+	)
+}
+
+func TestPipelinesCreateInvalid(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "POST", Path: "/pipelines", Body: "app=foo&name=api&require-approval=production&stages=staging%3Aorg%2Fstaging", Code: 403, Response: client.Error{Error: "no such stage: production"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox pipelines create --app foo --stages staging:org/staging --require-approval production api",
+			Exit:    1,
+			Stdout:  "Creating pipeline api... ",
+			Stderr:  "ERROR: no such stage: production\n",
+		},
+	)
+}
+
+func TestPipelinesList(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/pipelines", Code: 200, Response: models.Pipelines{
+			{
+				Name: "web",
+				App:  "foo",
+				Stages: []models.PipelineStage{
+					{Name: "staging", Rack: "org/staging"},
+					{Name: "production", Rack: "org/production", Approval: true},
+				},
+			},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox pipelines",
+			Exit:    0,
+			Stdout:  "NAME  APP  STAGES\nweb   foo  staging:org/staging -> production:org/production*\n",
+		},
+	)
+}
+
+func TestPipelinesDelete(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "DELETE", Path: "/pipelines/web", Code: 404, Response: client.Error{Error: "no such pipeline: web"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox pipelines delete web",
+			Exit:    1,
+			Stdout:  "Deleting pipeline web... ",
+			Stderr:  "ERROR: no such pipeline: web\n",
+		},
+	)
+}
+
+func TestPipelinesRunRequiresApproval(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/pipelines", Code: 200, Response: models.Pipelines{
+			{
+				Name:   "web",
+				App:    "foo",
+				Stages: []models.PipelineStage{{Name: "production", Rack: "org/production", Approval: true}},
+			},
+		}},
+		test.Http{Method: "POST", Path: "/pipelines/web/runs", Body: "build=B1", Code: 200, Response: models.PipelineRun{
+			Id:     "P1",
+			Status: "pending",
+			Stages: []models.PipelineRunStage{{Name: "production", Rack: "org/production", Build: "B1", Status: "pending"}},
+		}},
+		test.Http{Method: "PUT", Path: "/pipelines/web/runs/P1/stages/production", Body: "build=&release=&status=running", Code: 403, Response: client.Error{Error: "stage production requires approval"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox pipelines run web B1",
+			Exit:    0,
+			Stdout:  "Run: P1\nStage production requires approval, run `convox pipelines approve web P1` to continue\n",
+		},
+	)
+}
+
+func TestPipelinesApproveMissingRun(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/pipelines", Code: 200, Response: models.Pipelines{{Name: "web", App: "foo"}}},
+		test.Http{Method: "POST", Path: "/pipelines/web/runs/P123/approve", Code: 404, Response: client.Error{Error: "no such run: P123"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox pipelines approve web P123",
+			Exit:    1,
+			Stderr:  "ERROR: no such run: P123\n",
+		},
+	)
+}
+
+func TestPipelinesRuns(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/pipelines/web/runs", Code: 200, Response: models.PipelineRuns{
+			{
+				Id:      "P1",
+				Build:   "B1",
+				Status:  "awaiting-approval",
+				Started: time.Now().Add(-2 * time.Minute),
+				Stages: []models.PipelineRunStage{
+					{Name: "staging", Status: "complete"},
+					{Name: "production", Status: "awaiting-approval"},
+				},
+			},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox pipelines runs web",
+			Exit:    0,
+			Stdout:  "ID  BUILD  STAGE       STATUS             STARTED\nP1  B1     production  awaiting-approval  2 minutes ago\n",
+		},
+	)
+}
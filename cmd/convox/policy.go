@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/convox/rack/cmd/convox/stdcli"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func cmdAppPolicy(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	p, err := rackClient(c).GetPolicy(app)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	rack, err := rackClient(c).GetPolicy("")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	immutable := fmt.Sprintf("%t", p.Immutable)
+
+	if rack.Immutable && !p.Immutable {
+		immutable = "true (rack policy)"
+	}
+
+	fmt.Printf("Immutable  %s\n", immutable)
+
+	if !p.Updated.IsZero() {
+		fmt.Printf("Updated    %s by %s\n", humanizeTime(p.Updated), p.User)
+	}
+
+	return nil
+}
+
+func cmdAppPolicySet(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	return policySet(c, app)
+}
+
+func cmdRackPolicy(c *cli.Context) error {
+	p, err := rackClient(c).GetPolicy("")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Printf("Immutable  %t\n", p.Immutable)
+
+	if !p.Updated.IsZero() {
+		fmt.Printf("Updated    %s by %s\n", humanizeTime(p.Updated), p.User)
+	}
+
+	return nil
+}
+
+func cmdRackPolicySet(c *cli.Context) error {
+	return policySet(c, "")
+}
+
+// policySet changes the policy of an app, or of the rack when app is empty, from immutable=true
+// style arguments
+func policySet(c *cli.Context, app string) error {
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "set")
+		return nil
+	}
+
+	parts := strings.SplitN(c.Args()[0], "=", 2)
+
+	if len(parts) != 2 || parts[0] != "immutable" {
+		return stdcli.ExitError(fmt.Errorf("invalid argument: %s", c.Args()[0]))
+	}
+
+	immutable, err := strconv.ParseBool(parts[1])
+	if err != nil {
+		return stdcli.ExitError(fmt.Errorf("immutable must be true or false"))
+	}
+
+	fmt.Print("Updating policy... ")
+
+	if _, err := rackClient(c).SetPolicy(app, immutable); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+	return nil
+}
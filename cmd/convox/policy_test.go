@@ -0,0 +1,81 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/test"
+)
+
+func TestAppPolicy(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo/policy", Code: 200, Response: models.Policy{App: "foo", Immutable: true, Updated: time.Now().Add(-49 * time.Hour), User: "ops@example.org"}},
+		test.Http{Method: "GET", Path: "/system/policy", Code: 200, Response: models.Policy{}},
+		test.Http{Method: "GET", Path: "/apps/bar/policy", Code: 200, Response: models.Policy{App: "bar"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox apps policy --app foo",
+			Exit:    0,
+			Stdout:  "Immutable  true\nUpdated    2 days ago by ops@example.org\n",
+		},
+		test.ExecRun{
+			Command: "convox apps policy --app bar",
+			Exit:    0,
+			Stdout:  "Immutable  false\n",
+		},
+	)
+}
+
+func TestAppPolicyRack(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo/policy", Code: 200, Response: models.Policy{App: "foo"}},
+		test.Http{Method: "GET", Path: "/system/policy", Code: 200, Response: models.Policy{Immutable: true}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox apps policy --app foo",
+			Exit:    0,
+			Stdout:  "Immutable  true (rack policy)\n",
+		},
+	)
+}
+
+func TestPolicySet(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "POST", Path: "/apps/foo/policy", Body: "immutable=true", Code: 200, Response: models.Policy{App: "foo", Immutable: true}},
+		test.Http{Method: "POST", Path: "/system/policy", Body: "immutable=false", Code: 200, Response: models.Policy{}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox apps policy set immutable=true --app foo",
+			Exit:    0,
+			Stdout:  "Updating policy... OK\n",
+		},
+		test.ExecRun{
+			Command: "convox rack policy set immutable=false",
+			Exit:    0,
+			Stdout:  "Updating policy... OK\n",
+		},
+		test.ExecRun{
+			Command: "convox rack policy set immutable=maybe",
+			Exit:    1,
+			Stderr:  "ERROR: immutable must be true or false\n",
+		},
+		test.ExecRun{
+			Command: "convox rack policy set exec=false",
+			Exit:    1,
+			Stderr:  "ERROR: invalid argument: exec=false\n",
+		},
+	)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/convox/rack/cmd/convox/stdcli"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func init() {
+	stdcli.RegisterCommand(cli.Command{
+		Name:        "profile",
+		Description: "record a cpu, memory or other profile of a running process",
+		Usage:       "<pid> [--type cpu] [--duration 30s] [--file <path>]",
+		Action:      cmdProfile,
+		Flags: []cli.Flag{
+			appFlag,
+			rackFlag,
+			cli.StringFlag{
+				Name:  "type",
+				Value: "cpu",
+				Usage: "type of profile: cpu, heap, allocs, block, goroutine, mutex or trace",
+			},
+			cli.StringFlag{
+				Name:  "duration",
+				Value: "30s",
+				Usage: "how long to record the profile for",
+			},
+			cli.StringFlag{
+				Name:  "file",
+				Usage: "file to write the profile to, defaults to <pid>-<type>.<format>",
+			},
+		},
+	})
+}
+
+func cmdProfile(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "profile")
+		return nil
+	}
+
+	pid := c.Args()[0]
+	typ := c.String("type")
+
+	fmt.Printf("Recording %s profile of %s for %s... ", typ, pid, c.String("duration"))
+
+	var profile bytes.Buffer
+
+	format, err := rackClient(c).ProfileProcess(app, pid, typ, c.String("duration"), &profile)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+
+	file := c.String("file")
+
+	if file == "" {
+		file = fmt.Sprintf("%s-%s.%s", pid, typ, format)
+	}
+
+	if err := ioutil.WriteFile(file, profile.Bytes(), 0644); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Printf("Wrote %s\n", file)
+
+	return nil
+}
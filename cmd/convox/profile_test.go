@@ -0,0 +1,47 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "convox-profile")
+	require.Nil(t, err)
+
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "heap.pprof")
+
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo/processes/p1/profile", Code: 200, Response: "profile", Headers: map[string]string{"Profile-Format": "pprof"}},
+		test.Http{Method: "GET", Path: "/apps/foo/processes/p2/profile", Code: 403, Response: client.Error{Error: "process worker does not serve profiles, set the convox.profile.port label"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox profile p1 --type heap --duration 10s --file " + file + " --app foo",
+			Exit:    0,
+			Stdout:  "Recording heap profile of p1 for 10s... OK\nWrote " + file + "\n",
+		},
+		test.ExecRun{
+			Command: "convox profile p2 --app foo",
+			Exit:    1,
+			Stdout:  "Recording cpu profile of p2 for 30s... ",
+			Stderr:  "ERROR: process worker does not serve profiles, set the convox.profile.port label\n",
+		},
+	)
+
+	data, err := ioutil.ReadFile(file)
+	require.Nil(t, err)
+	assert.Equal(t, `"profile"`, string(data))
+}
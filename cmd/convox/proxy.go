@@ -72,7 +72,7 @@ func cmdProxy(c *cli.Context) error {
 	select {}
 }
 
-func proxy(localhost string, localport int, remotehost string, remoteport int, client *client.Client) {
+func proxy(localhost string, localport int, remotehost string, remoteport int, client client.Interface) {
 	fmt.Printf("proxying %s:%d to %s:%d\n", localhost, localport, remotehost, remoteport)
 
 	listener, err := net.Listen("tcp4", fmt.Sprintf("%s:%d", localhost, localport))
@@ -3,7 +3,7 @@ package main
 import (
 	"fmt"
 
-	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/models"
 	"github.com/convox/rack/cmd/convox/stdcli"
 	"gopkg.in/urfave/cli.v1"
 )
@@ -67,7 +67,7 @@ func cmdPs(c *cli.Context) error {
 	return nil
 }
 
-func displayProcesses(ps []client.Process) {
+func displayProcesses(ps []models.Process) {
 	t := stdcli.NewTable("ID", "NAME", "RELEASE", "STARTED", "COMMAND")
 
 	for _, p := range ps {
@@ -77,7 +77,7 @@ func displayProcesses(ps []client.Process) {
 	t.Print()
 }
 
-func displayProcessesStats(ps []client.Process, fm client.Formation) {
+func displayProcessesStats(ps []models.Process, fm models.Formation) {
 	t := stdcli.NewTable("ID", "NAME", "RELEASE", "CPU %", "MEM", "MEM %", "STARTED", "COMMAND")
 
 	for _, p := range ps {
@@ -145,7 +145,7 @@ func cmdPsStop(c *cli.Context) error {
 	return nil
 }
 
-func prettyId(p client.Process) string {
+func prettyId(p models.Process) string {
 	if p.Id == "pending" {
 		return "[PENDING]"
 	}
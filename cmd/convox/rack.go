@@ -2,13 +2,15 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/models"
 	"github.com/convox/rack/cmd/convox/stdcli"
 	"github.com/convox/version"
+	"github.com/dustin/go-humanize"
 	"gopkg.in/urfave/cli.v1"
 )
 
@@ -20,6 +22,38 @@ func init() {
 		Action:      cmdRack,
 		Flags:       []cli.Flag{rackFlag},
 		Subcommands: []cli.Command{
+			{
+				Name:        "events",
+				Description: "list what has happened to the rack and its apps",
+				Usage:       "[--since 24h] [--type scaling] [--app <app>] [--limit 100]",
+				Action:      cmdRackEvents,
+				Flags: []cli.Flag{
+					rackFlag,
+					cli.StringFlag{
+						Name:  "app",
+						Usage: "only show events of this app",
+					},
+					cli.StringFlag{
+						Name:  "type",
+						Usage: "only show events of this type: alarm, api, app, crash, error, instance, rack, release, scaling, secret or stack",
+					},
+					cli.StringFlag{
+						Name:  "since",
+						Usage: "how far back to look, e.g. 2h or 7d (default 24h)",
+					},
+					cli.IntFlag{
+						Name:  "limit",
+						Usage: "most events to show (default 100)",
+					},
+				},
+			},
+			{
+				Name:        "health",
+				Description: "check the health of the rack subsystems",
+				Usage:       "",
+				Action:      cmdRackHealth,
+				Flags:       []cli.Flag{rackFlag},
+			},
 			{
 				Name:        "logs",
 				Description: "stream the rack logs",
@@ -54,6 +88,29 @@ func init() {
 						Description: "update advanced rack parameters",
 						Usage:       "NAME=VALUE [NAME=VALUE]",
 						Action:      cmdRackParamsSet,
+						Flags:       []cli.Flag{rackFlag, dryRunFlag},
+					},
+					{
+						Name:        "history",
+						Description: "list changes to the parameters of the rack",
+						Usage:       "",
+						Action:      cmdRackParamsHistory,
+						Flags:       []cli.Flag{rackFlag},
+					},
+				},
+			},
+			{
+				Name:        "policy",
+				Description: "show whether every app of the rack can only be changed through releases",
+				Usage:       "",
+				Action:      cmdRackPolicy,
+				Flags:       []cli.Flag{rackFlag},
+				Subcommands: []cli.Command{
+					{
+						Name:        "set",
+						Description: "deny exec, run and environment changes on every app with immutable=true",
+						Usage:       "immutable=<true|false>",
+						Action:      cmdRackPolicySet,
 						Flags:       []cli.Flag{rackFlag},
 					},
 				},
@@ -93,7 +150,13 @@ func init() {
 				Description: "update rack to the given version",
 				Usage:       "[version]",
 				Action:      cmdRackUpdate,
-				Flags:       []cli.Flag{rackFlag},
+				Flags: []cli.Flag{
+					rackFlag,
+					cli.BoolFlag{
+						Name:  "force",
+						Usage: "update even if pre-flight checks fail",
+					},
+				},
 			},
 			{
 				Name:        "releases",
@@ -136,8 +199,83 @@ func cmdRack(c *cli.Context) error {
 	return nil
 }
 
+func cmdRackHealth(c *cli.Context) error {
+	if len(c.Args()) > 0 {
+		return stdcli.ExitError(fmt.Errorf("`convox rack health` does not take arguments"))
+	}
+
+	checks, err := rackClient(c).GetSystemHealth()
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	failed := []string{}
+
+	t := stdcli.NewTable("CHECK", "STATUS", "MESSAGE")
+
+	for _, check := range checks {
+		t.AddRow(check.Name, check.Status, check.Message)
+
+		if check.Status == "fail" {
+			failed = append(failed, check.Name)
+		}
+	}
+
+	t.Print()
+
+	if len(failed) > 0 {
+		return stdcli.ExitError(fmt.Errorf("rack health checks failed: %s", strings.Join(failed, ", ")))
+	}
+
+	return nil
+}
+
+func cmdRackEvents(c *cli.Context) error {
+	if len(c.Args()) > 0 {
+		return stdcli.ExitError(fmt.Errorf("`convox rack events` does not take arguments"))
+	}
+
+	events, err := rackClient(c).GetRackEvents(client.RackEventOptions{
+		App:   c.String("app"),
+		Type:  c.String("type"),
+		Since: c.String("since"),
+		Limit: c.Int("limit"),
+	})
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	t := stdcli.NewTable("TIME", "TYPE", "ACTION", "APP", "STATUS", "MESSAGE")
+
+	for _, e := range events {
+		t.AddRow(humanizeTime(e.Created), e.Type, e.Action, e.App, e.Status, rackEventMessage(e))
+	}
+
+	t.Print()
+	return nil
+}
+
+// rackEventMessage describes events that have no message by the rest of their data
+func rackEventMessage(e models.RackEvent) string {
+	if e.Message != "" {
+		return e.Message
+	}
+
+	params := []string{}
+
+	for _, key := range sortedKeys(e.Data) {
+		switch key {
+		case "app", "rack":
+		default:
+			params = append(params, fmt.Sprintf("%s=%s", key, e.Data[key]))
+		}
+	}
+
+	return strings.Join(params, " ")
+}
+
 func cmdRackLogs(c *cli.Context) error {
-	err := rackClient(c).StreamRackLogs(c.String("filter"), c.BoolT("follow"), c.Duration("since"), os.Stdout)
+	err := rackClient(c).StreamRackLogs(c.String("filter"), c.BoolT("follow"), c.Duration("since"), logsOutput(currentRack(c)))
 	if err != nil {
 		return stdcli.ExitError(err)
 	}
@@ -156,6 +294,11 @@ func cmdRackParams(c *cli.Context) error {
 		return stdcli.ExitError(err)
 	}
 
+	schema, err := rackParameterSchema(c, system.Name)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
 	keys := []string{}
 
 	for key, _ := range params {
@@ -164,10 +307,52 @@ func cmdRackParams(c *cli.Context) error {
 
 	sort.Strings(keys)
 
-	t := stdcli.NewTable("NAME", "VALUE")
+	if schema == nil {
+		t := stdcli.NewTable("NAME", "VALUE")
+
+		for _, key := range keys {
+			t.AddRow(key, params[key])
+		}
+
+		t.Print()
+		return nil
+	}
+
+	t := stdcli.NewTable("NAME", "VALUE", "DESCRIPTION")
 
 	for _, key := range keys {
-		t.AddRow(key, params[key])
+		description := ""
+
+		if p, ok := schema.Find(key); ok {
+			description = p.Description
+
+			if len(p.Allowed) > 0 {
+				description = strings.TrimSpace(fmt.Sprintf("%s (%s)", description, strings.Join(p.Allowed, "|")))
+			}
+		}
+
+		t.AddRow(key, params[key], description)
+	}
+
+	t.Print()
+	return nil
+}
+
+func cmdRackParamsHistory(c *cli.Context) error {
+	system, err := rackClient(c).GetSystem()
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	history, err := rackClient(c).GetParameterHistory(system.Name)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	t := stdcli.NewTable("WHEN", "NAME", "OLD", "NEW", "USER")
+
+	for _, change := range history {
+		t.AddRow(humanizeTime(change.Created), change.Name, change.Old, change.New, change.User)
 	}
 
 	t.Print()
@@ -192,6 +377,41 @@ func cmdRackParamsSet(c *cli.Context) error {
 		params[parts[0]] = parts[1]
 	}
 
+	schema, err := rackParameterSchema(c, system.Name)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	// check values here rather than let a bad one fail the stack update
+	if schema != nil {
+		keys := []string{}
+
+		for key := range params {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			p, ok := schema.Find(key)
+			if !ok {
+				return stdcli.ExitError(fmt.Errorf("unknown parameter: %s", key))
+			}
+
+			if err := p.Validate(params[key]); err != nil {
+				return stdcli.ExitError(err)
+			}
+		}
+	}
+
+	if c.Bool("dry-run") {
+		if err := paramsDryRun(c, system.Name, params); err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		return nil
+	}
+
 	fmt.Print("Updating parameters... ")
 
 	err = rackClient(c).SetParameters(system.Name, params)
@@ -203,6 +423,19 @@ func cmdRackParamsSet(c *cli.Context) error {
 	return nil
 }
 
+// rackParameterSchema returns the rack parameter schema, or nil for racks too old to report one
+func rackParameterSchema(c *cli.Context, rack string) (models.ParameterSchemas, error) {
+	schema, err := rackClient(c).GetParameterSchema(rack)
+	if _, ok := err.(client.ErrNotFound); ok {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return schema, nil
+}
+
 func cmdRackPs(c *cli.Context) error {
 	system, err := rackClient(c).GetSystem()
 	if err != nil {
@@ -221,6 +454,26 @@ func cmdRackPs(c *cli.Context) error {
 		}
 
 		displayProcessesStats(ps, fm)
+
+		instances, err := rackClient(c).GetInstancesStats()
+		if err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		fmt.Println()
+
+		t := stdcli.NewTable("INSTANCE", "DISK USED", "DISK TOTAL", "DISK %")
+
+		for _, i := range instances {
+			if i.Disk == nil {
+				t.AddRow(i.Id, "unknown", "unknown", "unknown")
+				continue
+			}
+
+			t.AddRow(i.Id, humanize.Bytes(uint64(i.Disk.Used)), humanize.Bytes(uint64(i.Disk.Total)), fmt.Sprintf("%0.2f%%", i.Disk.PercentUsed()*100))
+		}
+
+		t.Print()
 		return nil
 	}
 
@@ -246,7 +499,39 @@ func cmdRackUpdate(c *cli.Context) error {
 		return stdcli.ExitError(err)
 	}
 
-	system, err := rackClient(c).UpdateSystem(version.Version)
+	checks, err := rackClient(c).GetSystemChecks(version.Version)
+
+	_, notFound := err.(client.ErrNotFound)
+
+	switch {
+	case notFound:
+		// racks older than the checks endpoint can only be updated blind
+	case err != nil:
+		return stdcli.ExitError(err)
+	case len(checks) == 0:
+		// nothing to report
+	default:
+		failed := false
+
+		t := stdcli.NewTable("CHECK", "STATUS", "MESSAGE")
+
+		for _, check := range checks {
+			t.AddRow(check.Name, check.Status, check.Message)
+
+			if check.Status == "fail" {
+				failed = true
+			}
+		}
+
+		t.Print()
+		fmt.Println()
+
+		if failed && !c.Bool("force") {
+			return stdcli.ExitError(fmt.Errorf("pre-flight checks failed, use --force to update anyway"))
+		}
+	}
+
+	system, err := rackClient(c).UpdateSystem(version.Version, c.Bool("force"))
 	if err != nil {
 		return stdcli.ExitError(err)
 	}
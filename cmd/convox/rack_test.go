@@ -1,15 +1,41 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/models"
 	"github.com/convox/rack/test"
 	"github.com/convox/version"
 	"github.com/stretchr/testify/require"
 )
 
+func TestRackHealth(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/system/health", Code: 200, Response: models.SystemChecks{
+			{Name: "dynamodb", Status: "pass"},
+			{Name: "certificate", Status: "fail", Message: "expires soon"},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox rack health",
+			Exit:    1,
+			Stdout:  "CHECK        STATUS  MESSAGE\ndynamodb     pass\ncertificate  fail    expires soon\n",
+			Stderr:  "ERROR: rack health checks failed: certificate\n",
+		},
+	)
+}
+
 func TestRackUpdateStable(t *testing.T) {
 	versions, err := version.All()
 	require.Nil(t, err)
@@ -18,7 +44,8 @@ func TestRackUpdateStable(t *testing.T) {
 	require.Nil(t, err)
 
 	ts := testServer(t,
-		test.Http{Method: "PUT", Body: fmt.Sprintf("version=%s", stable.Version), Path: "/system", Code: 200, Response: client.System{
+		test.Http{Method: "GET", Path: "/system/checks", Code: 200, Response: models.SystemChecks{}},
+		test.Http{Method: "PUT", Body: fmt.Sprintf("version=%s", stable.Version), Path: "/system", Code: 200, Response: models.System{
 			Name:    "mysystem",
 			Version: "ver",
 			Count:   1,
@@ -39,7 +66,8 @@ func TestRackUpdateStable(t *testing.T) {
 
 func TestRackUpdateSpecified(t *testing.T) {
 	ts := testServer(t,
-		test.Http{Method: "PUT", Body: "version=20150909014908", Path: "/system", Code: 200, Response: client.System{
+		test.Http{Method: "GET", Path: "/system/checks", Code: 200, Response: models.SystemChecks{}},
+		test.Http{Method: "PUT", Body: "version=20150909014908", Path: "/system", Code: 200, Response: models.System{
 			Name:    "mysystem",
 			Version: "ver",
 			Count:   1,
@@ -57,3 +85,155 @@ func TestRackUpdateSpecified(t *testing.T) {
 		},
 	)
 }
+
+func TestRackUpdateChecksFailed(t *testing.T) {
+	versions := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(version.Versions{{Version: "20150909014908", Published: true}})
+	}))
+
+	defer versions.Close()
+
+	url := version.URL
+	version.URL = versions.URL
+	defer func() { version.URL = url }()
+
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/system/checks", Code: 200, Response: models.SystemChecks{
+			{Name: "parameters", Status: "pass"},
+			{Name: "ami", Status: "fail", Message: "custom ami is pinned"},
+		}},
+	)
+
+	defer ts.Close()
+
+	fakeRuns(t, client.New(strings.TrimPrefix(ts.URL, "https://"), "test", "test"),
+		test.ExecRun{
+			Command: "convox rack update 20150909014908",
+			Exit:    1,
+			Stdout:  "CHECK       STATUS  MESSAGE\nparameters  pass\nami         fail    custom ami is pinned\n\n",
+			Stderr:  "ERROR: pre-flight checks failed, use --force to update anyway\n",
+		},
+	)
+}
+
+func TestRackParams(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/system", Code: 200, Response: models.System{Name: "convox"}},
+		test.Http{Method: "GET", Path: "/apps/convox/parameters", Code: 200, Response: models.Parameters{"Internal": "No", "Key": "****"}},
+		test.Http{Method: "GET", Path: "/apps/convox/parameters/schema", Code: 200, Response: models.ParameterSchemas{
+			{Name: "Internal", Type: "String", Description: "Only allow access from inside the VPC", Allowed: []string{"Yes", "No"}},
+			{Name: "Key", Type: "String", Secret: true},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox rack params",
+			Exit:    0,
+			Stdout:  "NAME      VALUE  DESCRIPTION\nInternal  No     Only allow access from inside the VPC (Yes|No)\nKey       ****\n",
+		},
+	)
+}
+
+func TestRackParamsSetInvalid(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/system", Code: 200, Response: models.System{Name: "convox"}},
+		test.Http{Method: "GET", Path: "/apps/convox/parameters/schema", Code: 200, Response: models.ParameterSchemas{
+			{Name: "Internal", Type: "String", Allowed: []string{"Yes", "No"}},
+			{Name: "SwapSize", Type: "Number"},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox rack params set Internal=maybe",
+			Exit:    1,
+			Stderr:  "ERROR: invalid value for Internal: maybe (allowed: Yes, No)\n",
+		},
+		test.ExecRun{
+			Command: "convox rack params set SwapSize=big",
+			Exit:    1,
+			Stderr:  "ERROR: invalid value for SwapSize: big is not a number\n",
+		},
+		test.ExecRun{
+			Command: "convox rack params set Nonexistent=1",
+			Exit:    1,
+			Stderr:  "ERROR: unknown parameter: Nonexistent\n",
+		},
+	)
+}
+
+func TestRackParamsHistory(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/system", Code: 200, Response: models.System{Name: "convox"}},
+		test.Http{Method: "GET", Path: "/apps/convox/parameters/history", Code: 200, Response: models.ParameterChanges{
+			{Name: "Autoscale", Old: "Yes", New: "No", User: "ops@example.org", Created: time.Now().Add(-49 * time.Hour)},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox rack params history",
+			Exit:    0,
+			Stdout:  "WHEN        NAME       OLD  NEW  USER\n2 days ago  Autoscale  Yes  No   ops@example.org\n",
+		},
+	)
+}
+
+func TestRackPsStats(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/system", Code: 200, Response: models.System{Name: "convox", Version: "latest"}},
+		test.Http{Method: "GET", Path: "/apps/convox/processes", Code: 200, Response: models.Processes{
+			{Id: "abc123", Name: "web", Release: "RABCDEFGHI", Cpu: 1.5, Memory: 0.25, Command: "api/bin/web"},
+		}},
+		test.Http{Method: "GET", Path: "/apps/convox/formation", Code: 200, Response: models.Formation{
+			{Name: "web", Count: 1, Memory: 256},
+		}},
+		test.Http{Method: "GET", Path: "/instances", Code: 200, Response: []models.Instance{
+			{Id: "i-1234", Disk: &models.InstanceDisk{Used: 9000000000, Total: 10000000000}},
+			{Id: "i-5678"},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox rack ps --stats",
+			Exit:    0,
+			Stdout:  "ID      NAME  RELEASE     CPU %  MEM           MEM %   STARTED  COMMAND\nabc123  web   RABCDEFGHI  1.50%  64.0MB/256MB  25.00%           api/bin/web\n\nINSTANCE  DISK USED  DISK TOTAL  DISK %\ni-1234    9.0GB      10GB        90.00%\ni-5678    unknown    unknown     unknown\n",
+		},
+	)
+}
+
+func TestRackEvents(t *testing.T) {
+	created := time.Now().UTC().Add(-2 * time.Minute)
+
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/system/events", Code: 200, Response: models.RackEvents{
+			{Action: "release:scale", Type: "scaling", Status: "success", App: "foo", Data: map[string]string{"app": "foo", "count": "4", "process": "web"}, Created: created},
+			{Action: "rack:scale", Type: "scaling", Status: "success", Message: "rack autoscaled", Created: created},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox rack events --since 7d --type scaling",
+			Exit:    0,
+			Stdout:  "TIME           TYPE     ACTION         APP  STATUS   MESSAGE\n2 minutes ago  scaling  release:scale  foo  success  count=4 process=web\n2 minutes ago  scaling  rack:scale          success  rack autoscaled\n",
+		},
+		test.ExecRun{
+			Command: "convox rack events foo",
+			Exit:    1,
+			Stderr:  "ERROR: `convox rack events` does not take arguments\n",
+		},
+	)
+}
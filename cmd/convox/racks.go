@@ -2,7 +2,15 @@ package main
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
+	"strings"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/convox/rack/client"
 	"github.com/convox/rack/cmd/convox/stdcli"
 	"gopkg.in/urfave/cli.v1"
 )
@@ -13,6 +21,20 @@ func init() {
 		Description: "list your Convox racks",
 		Usage:       "",
 		Action:      cmdRacks,
+		Subcommands: []cli.Command{
+			{
+				Name:        "adopt",
+				Description: "log in to a rack that was installed from another machine using AWS credentials",
+				Usage:       "[--stack-name convox] <region> [credentials.csv]",
+				Action:      cmdRacksAdopt,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "stack-name",
+						Usage: "name of the rack stack, required if the region has more than one rack",
+					},
+				},
+			},
+		},
 	})
 }
 
@@ -37,3 +59,194 @@ func cmdRacks(c *cli.Context) error {
 	t.Print()
 	return nil
 }
+
+func cmdRacksAdopt(c *cli.Context) error {
+	if len(c.Args()) != 1 && len(c.Args()) != 2 {
+		stdcli.Usage(c, "adopt")
+		return nil
+	}
+
+	region := c.Args()[0]
+
+	credentialsFile := ""
+	if len(c.Args()) == 2 {
+		credentialsFile = c.Args()[1]
+	}
+
+	creds, err := readCredentials(credentialsFile)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+	if creds == nil {
+		return stdcli.ExitError(fmt.Errorf("error reading credentials"))
+	}
+
+	CF := cloudformation.New(session.New(), awsConfig(region, creds))
+	ECS := ecs.New(session.New(), awsConfig(region, creds))
+
+	stacks := []*cloudformation.Stack{}
+
+	err = CF.DescribeStacksPages(&cloudformation.DescribeStacksInput{}, func(res *cloudformation.DescribeStacksOutput, last bool) bool {
+		stacks = append(stacks, res.Stacks...)
+		return true
+	})
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	stack, err := adoptableRack(stacks, c.String("stack-name"))
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Printf("Adopting %s... ", *stack.StackName)
+
+	host := stackOutput(stack, "Dashboard")
+
+	password, err := rackPassword(CF, ECS, *stack.StackName)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	hc, err := getHostConfig(host)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if err := testLogin(host, password, c.App.Version, &hc); err != nil {
+		return stdcli.ExitError(fmt.Errorf("could not log in to %s: %s", host, err))
+	}
+
+	if err := addLogin(host, password); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if err := setHostConfig(host, hc); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if err := switchHost(host); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+	fmt.Printf("Logged in to %s, try `convox apps`\n", host)
+
+	return nil
+}
+
+// rackVersion matches the timestamp versions of released racks
+var rackVersion = regexp.MustCompile(`^\d{14}$`)
+
+// adoptableRack returns the rack stack named name, or the only rack stack if name is empty.
+// Racks are tagged Type=rack when installed, older racks are recognized by their template.
+func adoptableRack(stacks []*cloudformation.Stack, name string) (*cloudformation.Stack, error) {
+	racks := []*cloudformation.Stack{}
+
+	for _, s := range stacks {
+		tags := map[string]string{}
+
+		for _, t := range s.Tags {
+			tags[*t.Key] = *t.Value
+		}
+
+		if tags["Type"] == "rack" || (stackOutput(s, "Dashboard") != "" && stackParameter(s, "Password") != "" && stackParameter(s, "Version") != "") {
+			racks = append(racks, s)
+		}
+	}
+
+	var rack *cloudformation.Stack
+
+	switch {
+	case name != "":
+		for _, r := range racks {
+			if *r.StackName == name {
+				rack = r
+			}
+		}
+
+		if rack == nil {
+			return nil, fmt.Errorf("no rack named %s found", name)
+		}
+	case len(racks) == 0:
+		return nil, fmt.Errorf("no racks found")
+	case len(racks) > 1:
+		names := []string{}
+
+		for _, r := range racks {
+			names = append(names, *r.StackName)
+		}
+
+		sort.Strings(names)
+
+		return nil, fmt.Errorf("found racks %s, choose one with --stack-name", strings.Join(names, ", "))
+	default:
+		rack = racks[0]
+	}
+
+	if status := *rack.StackStatus; !strings.HasSuffix(status, "_COMPLETE") || strings.HasPrefix(status, "DELETE") || status == "ROLLBACK_COMPLETE" {
+		return nil, fmt.Errorf("rack %s can not be adopted while it is %s", *rack.StackName, status)
+	}
+
+	if stackOutput(rack, "Dashboard") == "" {
+		return nil, fmt.Errorf("rack %s has no Dashboard output, is it a convox rack?", *rack.StackName)
+	}
+
+	if v := stackParameter(rack, "Version"); rackVersion.MatchString(v) && v < client.MinimumServerVersion {
+		return nil, fmt.Errorf("rack %s is version %s, update it to at least %s with the account that installed it", *rack.StackName, v, client.MinimumServerVersion)
+	}
+
+	return rack, nil
+}
+
+// rackPassword reads the password of a rack from the environment of its api task definition
+func rackPassword(CF *cloudformation.CloudFormation, ECS *ecs.ECS, stack string) (string, error) {
+	res, err := CF.DescribeStackResource(&cloudformation.DescribeStackResourceInput{
+		LogicalResourceId: aws.String("RackWebTasks"),
+		StackName:         aws.String(stack),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if res.StackResourceDetail == nil || res.StackResourceDetail.PhysicalResourceId == nil {
+		return "", fmt.Errorf("could not find the api tasks of %s", stack)
+	}
+
+	tres, err := ECS.DescribeTaskDefinition(&ecs.DescribeTaskDefinitionInput{
+		TaskDefinition: res.StackResourceDetail.PhysicalResourceId,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	for _, cd := range tres.TaskDefinition.ContainerDefinitions {
+		for _, kv := range cd.Environment {
+			if *kv.Name == "PASSWORD" && kv.Value != nil {
+				return *kv.Value, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("could not find the password of %s", stack)
+}
+
+func stackOutput(s *cloudformation.Stack, key string) string {
+	for _, o := range s.Outputs {
+		if *o.OutputKey == key && o.OutputValue != nil {
+			return *o.OutputValue
+		}
+	}
+
+	return ""
+}
+
+func stackParameter(s *cloudformation.Stack, key string) string {
+	for _, p := range s.Parameters {
+		if *p.ParameterKey == key && p.ParameterValue != nil {
+			return *p.ParameterValue
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/stretchr/testify/assert"
+)
+
+func rackStack(name, status, version string, tags map[string]string) *cloudformation.Stack {
+	s := &cloudformation.Stack{
+		StackName:   aws.String(name),
+		StackStatus: aws.String(status),
+		Outputs: []*cloudformation.Output{
+			{OutputKey: aws.String("Dashboard"), OutputValue: aws.String(name + ".example.org")},
+		},
+		Parameters: []*cloudformation.Parameter{
+			{ParameterKey: aws.String("Password"), ParameterValue: aws.String("****")},
+			{ParameterKey: aws.String("Version"), ParameterValue: aws.String(version)},
+		},
+	}
+
+	for k, v := range tags {
+		s.Tags = append(s.Tags, &cloudformation.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+
+	return s
+}
+
+func TestAdoptableRack(t *testing.T) {
+	app := &cloudformation.Stack{
+		StackName:   aws.String("convox-web"),
+		StackStatus: aws.String("UPDATE_COMPLETE"),
+		Tags:        []*cloudformation.Tag{{Key: aws.String("Type"), Value: aws.String("app")}},
+	}
+
+	s, err := adoptableRack([]*cloudformation.Stack{app, rackStack("convox", "UPDATE_COMPLETE", "20161102160040", nil)}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "convox", *s.StackName)
+
+	racks := []*cloudformation.Stack{
+		app,
+		rackStack("staging", "UPDATE_ROLLBACK_COMPLETE", "dev", map[string]string{"Type": "rack"}),
+		rackStack("production", "UPDATE_IN_PROGRESS", "20161102160040", nil),
+		rackStack("legacy", "CREATE_COMPLETE", "20150901000000", nil),
+	}
+
+	_, err = adoptableRack(racks, "")
+	assert.EqualError(t, err, "found racks legacy, production, staging, choose one with --stack-name")
+
+	s, err = adoptableRack(racks, "staging")
+	assert.NoError(t, err)
+	assert.Equal(t, "staging.example.org", stackOutput(s, "Dashboard"))
+
+	_, err = adoptableRack(racks, "production")
+	assert.EqualError(t, err, "rack production can not be adopted while it is UPDATE_IN_PROGRESS")
+
+	_, err = adoptableRack(racks, "legacy")
+	assert.EqualError(t, err, "rack legacy is version 20150901000000, update it to at least 20151023042141 with the account that installed it")
+
+	_, err = adoptableRack(racks, "convox-web")
+	assert.EqualError(t, err, "no rack named convox-web found")
+
+	_, err = adoptableRack([]*cloudformation.Stack{app}, "")
+	assert.EqualError(t, err, "no racks found")
+}
@@ -2,10 +2,18 @@ package main
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 
 	"gopkg.in/urfave/cli.v1"
+	yaml "gopkg.in/yaml.v2"
 
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/models"
 	"github.com/convox/rack/cmd/convox/stdcli"
 )
 
@@ -24,11 +32,49 @@ func init() {
 				Action:      cmdReleaseInfo,
 				Flags:       []cli.Flag{appFlag, rackFlag},
 			},
+			{
+				Name:        "diff",
+				Description: "show what promoting a release in place of another would change",
+				Usage:       "<from release id> <to release id>",
+				Action:      cmdReleaseDiff,
+				Flags:       []cli.Flag{appFlag, rackFlag},
+			},
 			{
 				Name:        "promote",
 				Description: "promote a release",
 				Usage:       "<release id>",
 				Action:      cmdReleasePromote,
+				Flags: []cli.Flag{
+					appFlag,
+					rackFlag,
+					cli.BoolFlag{
+						Name:  "wait",
+						Usage: "wait for release to finish promoting before returning",
+					},
+					cli.StringFlag{
+						Name:  "canary",
+						Usage: "run the release next to the active release with a percentage of its traffic, i.e. 10%",
+					},
+					cli.BoolFlag{
+						Name:  "follow",
+						Usage: "stream the stack events of the promotion until it has finished",
+					},
+					cli.DurationFlag{
+						Name:  "health-timeout",
+						Usage: "have the rack wait for the new processes to become healthy and roll back after a duration",
+					},
+					cli.StringFlag{
+						Name:  "processes",
+						Usage: "only update these comma separated processes, leaving the others on the release they run",
+					},
+					dryRunFlag,
+				},
+			},
+			{
+				Name:        "finalize",
+				Description: "promote the release of a canary to all traffic",
+				Usage:       "",
+				Action:      cmdReleaseFinalize,
 				Flags: []cli.Flag{
 					appFlag,
 					rackFlag,
@@ -38,6 +84,13 @@ func init() {
 					},
 				},
 			},
+			{
+				Name:        "abort",
+				Description: "stop a canary and return all traffic to the active release",
+				Usage:       "",
+				Action:      cmdReleaseAbort,
+				Flags:       []cli.Flag{appFlag, rackFlag},
+			},
 		},
 	})
 }
@@ -101,12 +154,305 @@ func cmdReleaseInfo(c *cli.Context) error {
 		return stdcli.ExitError(err)
 	}
 
-	fmt.Printf("Id       %s\n", r.Id)
-	fmt.Printf("Build    %s\n", r.Build)
-	fmt.Printf("Created  %s\n", r.Created)
-	fmt.Printf("Env      ")
+	releases, err := rackClient(c).GetReleases(app)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	promotions, err := rackClient(c).GetReleasePromotions(app, r.Id)
+	if _, ok := err.(client.ErrNotFound); ok {
+		promotions = nil
+	} else if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	creator := r.CreatedBy
+
+	if creator == "" {
+		creator = "unknown"
+	}
+
+	fmt.Printf("Id        %s\n", r.Id)
+	fmt.Printf("Build     %s\n", r.Build)
+	fmt.Printf("Created   %s\n", r.Created)
+	fmt.Printf("Creator   %s\n", creator)
+	fmt.Printf("Env       %s\n", indentLines(maskEnv(r.Env), 10))
+
+	if prev := previousRelease(releases, *r); prev != nil {
+		changes, err := releaseChanges(*prev, *r)
+		if err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		if len(changes) == 0 {
+			changes = []string{"none"}
+		}
+
+		fmt.Printf("Changes   since %s\n", prev.Id)
+		fmt.Printf("          %s\n", indentLines(strings.Join(changes, "\n"), 10))
+	}
+
+	fmt.Printf("Manifest  %s\n", indentLines(strings.TrimRight(r.Manifest, "\n"), 10))
+
+	deployments := []string{}
+
+	for _, p := range promotions {
+		deployment := fmt.Sprintf("%s by %s", humanizeTime(p.Created), p.User)
+
+		if p.Rack != "" {
+			deployment += fmt.Sprintf(" on %s", p.Rack)
+		}
+
+		if len(p.Processes) > 0 {
+			deployment += fmt.Sprintf("\n  processes %s", strings.Join(p.Processes, ", "))
+		}
+
+		for _, o := range p.Overrides {
+			deployment += fmt.Sprintf("\n  override %s", o)
+		}
+
+		deployments = append(deployments, deployment)
+	}
+
+	if len(deployments) == 0 {
+		deployments = []string{"never"}
+	}
+
+	fmt.Printf("Promoted  %s\n", indentLines(strings.Join(deployments, "\n"), 10))
+
+	return nil
+}
+
+// indentLines indents every line of s after the first by n spaces
+func indentLines(s string, n int) string {
+	return strings.Replace(s, "\n", "\n"+strings.Repeat(" ", n), -1)
+}
+
+// maskEnv replaces every value in a KEY=VALUE environment with asterisks
+func maskEnv(env string) string {
+	lines := []string{}
+
+	for _, line := range strings.Split(env, "\n") {
+		if parts := strings.SplitN(line, "=", 2); len(parts) == 2 {
+			lines = append(lines, fmt.Sprintf("%s=****", parts[0]))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// previousRelease returns the release created most recently before r
+func previousRelease(releases models.Releases, r models.Release) *models.Release {
+	var prev *models.Release
+
+	for i := range releases {
+		if !releases[i].Created.Before(r.Created) {
+			continue
+		}
+
+		if prev == nil || releases[i].Created.After(prev.Created) {
+			prev = &releases[i]
+		}
+	}
+
+	return prev
+}
+
+// releaseChanges describes how the services and environment of r differ from prev
+func releaseChanges(prev, r models.Release) ([]string, error) {
+	changes := []string{}
+
+	if prev.Build != r.Build {
+		changes = append(changes, fmt.Sprintf("build: %s -> %s", prev.Build, r.Build))
+	}
+
+	before, err := manifestServices(prev.Manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := manifestServices(r.Manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range unionKeys(before, after) {
+		b, inBefore := before[name].(map[interface{}]interface{})
+		a, inAfter := after[name].(map[interface{}]interface{})
+
+		switch {
+		case !inBefore:
+			changes = append(changes, fmt.Sprintf("%s: added", name))
+		case !inAfter:
+			changes = append(changes, fmt.Sprintf("%s: removed", name))
+		default:
+			sb := stringKeys(b)
+			sa := stringKeys(a)
+
+			fields := []string{}
+
+			for _, field := range unionKeys(sb, sa) {
+				if !reflect.DeepEqual(sb[field], sa[field]) {
+					fields = append(fields, field)
+				}
+			}
+
+			if len(fields) > 0 {
+				changes = append(changes, fmt.Sprintf("%s: %s changed", name, strings.Join(fields, ", ")))
+			}
+		}
+	}
+
+	penv := parseEnv(prev.Env)
+	renv := parseEnv(r.Env)
+
+	envChanges := []string{}
+
+	for _, key := range unionKeys(stringMap(penv), stringMap(renv)) {
+		pv, inBefore := penv[key]
+		rv, inAfter := renv[key]
+
+		switch {
+		case !inBefore:
+			envChanges = append(envChanges, fmt.Sprintf("+%s", key))
+		case !inAfter:
+			envChanges = append(envChanges, fmt.Sprintf("-%s", key))
+		case pv != rv:
+			envChanges = append(envChanges, fmt.Sprintf("~%s", key))
+		}
+	}
+
+	if len(envChanges) > 0 {
+		changes = append(changes, fmt.Sprintf("env: %s", strings.Join(envChanges, " ")))
+	}
+
+	return changes, nil
+}
+
+// manifestServices returns the raw service definitions of a version 1 or 2 manifest
+func manifestServices(data string) (map[string]interface{}, error) {
+	var m map[string]interface{}
+
+	if err := yaml.Unmarshal([]byte(data), &m); err != nil {
+		return nil, fmt.Errorf("error loading manifest: %s", err)
+	}
+
+	if _, ok := m["version"]; !ok {
+		return m, nil
+	}
+
+	services := map[string]interface{}{}
+
+	if ss, ok := m["services"].(map[interface{}]interface{}); ok {
+		for name, s := range ss {
+			services[fmt.Sprintf("%v", name)] = s
+		}
+	}
+
+	return services, nil
+}
+
+func parseEnv(env string) map[string]string {
+	vars := map[string]string{}
+
+	for _, line := range strings.Split(env, "\n") {
+		if parts := strings.SplitN(line, "=", 2); len(parts) == 2 {
+			vars[parts[0]] = parts[1]
+		}
+	}
+
+	return vars
+}
+
+func stringKeys(m map[interface{}]interface{}) map[string]interface{} {
+	keys := map[string]interface{}{}
+
+	for k, v := range m {
+		keys[fmt.Sprintf("%v", k)] = v
+	}
+
+	return keys
+}
+
+func stringMap(m map[string]string) map[string]interface{} {
+	keys := map[string]interface{}{}
+
+	for k, v := range m {
+		keys[k] = v
+	}
+
+	return keys
+}
+
+// unionKeys returns the sorted keys present in either map
+func unionKeys(a, b map[string]interface{}) []string {
+	seen := map[string]bool{}
+	keys := []string{}
+
+	for _, m := range []map[string]interface{}{a, b} {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func cmdReleaseDiff(c *cli.Context) error {
+	if len(c.Args()) != 2 {
+		stdcli.Usage(c, "releases diff")
+		return nil
+	}
+
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	d, err := rackClient(c).DiffReleases(app, c.Args()[0], c.Args()[1])
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if d.Build == nil && len(d.Services) == 0 && len(d.Env) == 0 {
+		fmt.Printf("No changes between %s and %s\n", d.From, d.To)
+		return nil
+	}
+
+	if d.Build != nil {
+		fmt.Printf("Build     %s -> %s\n", d.Build.From, d.Build.To)
+	}
+
+	if len(d.Services) > 0 {
+		services := []string{}
+
+		for _, s := range d.Services {
+			if s.Change == "changed" {
+				services = append(services, fmt.Sprintf("%s: %s changed", s.Name, strings.Join(s.Fields, ", ")))
+			} else {
+				services = append(services, fmt.Sprintf("%s: %s", s.Name, s.Change))
+			}
+		}
+
+		fmt.Printf("Services  %s\n", indentLines(strings.Join(services, "\n"), 10))
+	}
+
+	if len(d.Env) > 0 {
+		env := []string{}
+		marks := map[string]string{"added": "+", "removed": "-", "changed": "~"}
+
+		for _, e := range d.Env {
+			env = append(env, marks[e.Change]+e.Name)
+		}
+
+		fmt.Printf("Env       %s\n", indentLines(strings.Join(env, "\n"), 10))
+	}
 
-	fmt.Println(strings.Replace(r.Env, "\n", "\n         ", -1))
 	return nil
 }
 
@@ -123,15 +469,97 @@ func cmdReleasePromote(c *cli.Context) error {
 		return stdcli.ExitError(err)
 	}
 
-	fmt.Printf("Promoting %s... ", release)
+	processes := []string{}
+
+	for _, p := range strings.Split(c.String("processes"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			processes = append(processes, p)
+		}
+	}
+
+	if canary := c.String("canary"); canary != "" {
+		if len(processes) > 0 {
+			return stdcli.ExitError(fmt.Errorf("--processes can not be used with --canary"))
+		}
+
+		percent, err := strconv.Atoi(strings.TrimSuffix(canary, "%"))
+		if err != nil {
+			return stdcli.ExitError(fmt.Errorf("canary must be a percentage, i.e. 10%%"))
+		}
+
+		if c.Bool("dry-run") {
+			dryRunCall("POST", fmt.Sprintf("/apps/%s/releases/%s/canary", app, release), map[string]string{"percent": strconv.Itoa(percent)})
+			return nil
+		}
+
+		fmt.Printf("Starting canary of %s with %d%% of traffic... ", release, percent)
+
+		if _, err := rackClient(c).CreateCanary(app, release, percent); err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		fmt.Println("OK")
+		fmt.Printf("Run `convox releases finalize --app %s` to promote %s or `convox releases abort --app %s` to stop it\n", app, release, app)
+		return nil
+	}
+
+	if c.Bool("dry-run") {
+		var params map[string]string
+
+		if len(processes) > 0 {
+			params = map[string]string{"processes": strings.Join(processes, ",")}
+		}
+
+		dryRunCall("POST", fmt.Sprintf("/apps/%s/releases/%s/promote", app, release), params)
+
+		changes, err := rackClient(c).PreviewPromoteRelease(app, release, processes)
+		if err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		displayStackChanges(changes)
+		return nil
+	}
+
+	if len(processes) > 0 {
+		fmt.Printf("Promoting %s for %s... ", release, strings.Join(processes, ", "))
+	} else {
+		fmt.Printf("Promoting %s... ", release)
+	}
 
-	_, err = rackClient(c).PromoteRelease(app, release)
+	if timeout := c.Duration("health-timeout"); timeout > 0 {
+		if _, err := rackClient(c).PromoteReleaseWait(app, release, timeout, processes); err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		// racks without the health gate return before the promotion has finished
+		if err := waitForReleasePromotion(c, app, release); err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		fmt.Println("OK")
+		return nil
+	}
+
+	if len(processes) > 0 {
+		_, err = rackClient(c).PromoteReleaseProcesses(app, release, processes)
+	} else {
+		_, err = rackClient(c).PromoteRelease(app, release)
+	}
 	if err != nil {
 		return stdcli.ExitError(err)
 	}
 
 	fmt.Println("UPDATING")
 
+	if c.Bool("follow") {
+		if err := followReleasePromotion(c, app, release); err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		return nil
+	}
+
 	if c.Bool("wait") {
 		fmt.Printf("Waiting for stabilization... ")
 
@@ -148,3 +576,94 @@ func cmdReleasePromote(c *cli.Context) error {
 func waitForReleasePromotion(c *cli.Context, app, release string) error {
 	return waitForAppRunning(c, app)
 }
+
+// followReleasePromotion prints the stack events of a promotion and fails if it was rolled back
+func followReleasePromotion(c *cli.Context, app, release string) error {
+	reader, writer := io.Pipe()
+	done := make(chan bool)
+
+	go func() {
+		io.Copy(os.Stdout, reader)
+		done <- true
+	}()
+
+	if err := rackClient(c).StreamAppEvents(app, writer); err != nil {
+		return err
+	}
+
+	<-done
+
+	a, err := rackClient(c).GetApp(app)
+	if err != nil {
+		return err
+	}
+
+	if a.Release != release {
+		return fmt.Errorf("%s was rolled back", release)
+	}
+
+	return nil
+}
+
+func cmdReleaseFinalize(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) > 0 {
+		stdcli.Usage(c, "finalize")
+		return nil
+	}
+
+	canary, err := rackClient(c).GetCanary(app)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Printf("Promoting %s... ", canary.Release)
+
+	if _, err := rackClient(c).FinalizeCanary(app); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("UPDATING")
+
+	if c.Bool("wait") {
+		fmt.Printf("Waiting for stabilization... ")
+
+		if err := waitForReleasePromotion(c, app, canary.Release); err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		fmt.Println("OK")
+	}
+
+	return nil
+}
+
+func cmdReleaseAbort(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) > 0 {
+		stdcli.Usage(c, "abort")
+		return nil
+	}
+
+	canary, err := rackClient(c).GetCanary(app)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Printf("Stopping canary of %s... ", canary.Release)
+
+	if err := rackClient(c).AbortCanary(app); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+	return nil
+}
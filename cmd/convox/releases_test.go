@@ -0,0 +1,254 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/test"
+)
+
+func TestReleaseInfo(t *testing.T) {
+	created := time.Date(2016, 10, 2, 12, 0, 0, 0, time.UTC)
+
+	release := models.Release{
+		Id:        "R2",
+		App:       "foo",
+		Build:     "B2",
+		Env:       "DATABASE_URL=postgres://secret\nPORT=3000",
+		Manifest:  "web:\n  image: web\n  command: bin/web\nworker:\n  image: worker\n",
+		Created:   created,
+		CreatedBy: "ops@example.org",
+	}
+
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo/releases/R2", Code: 200, Response: release},
+		test.Http{Method: "GET", Path: "/apps/foo/releases", Code: 200, Response: models.Releases{
+			release,
+			{Id: "R1", App: "foo", Build: "B1", Env: "PORT=5000\nDEBUG=1", Manifest: "web:\n  image: web\n  command: bin/start\n", Created: created.Add(-time.Hour)},
+		}},
+		test.Http{Method: "GET", Path: "/apps/foo/releases/R2/promotions", Code: 200, Response: models.ReleasePromotions{
+			{Release: "R2", User: "ops@example.org", Created: time.Now().Add(-49 * time.Hour)},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox releases info R2 --app foo",
+			Exit:    0,
+			Stdout: `Id        R2
+Build     B2
+Created   2016-10-02 12:00:00 +0000 UTC
+Creator   ops@example.org
+Env       DATABASE_URL=****
+          PORT=****
+Changes   since R1
+          build: B1 -> B2
+          web: command changed
+          worker: added
+          env: +DATABASE_URL -DEBUG ~PORT
+Manifest  web:
+            image: web
+            command: bin/web
+          worker:
+            image: worker
+Promoted  2 days ago by ops@example.org
+`,
+		},
+	)
+}
+
+func TestReleaseInfoOverrides(t *testing.T) {
+	created := time.Date(2016, 10, 2, 12, 0, 0, 0, time.UTC)
+
+	release := models.Release{
+		Id:       "R1",
+		App:      "foo",
+		Build:    "B1",
+		Manifest: "web:\n  image: web\n",
+		Created:  created,
+	}
+
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo/releases/R1", Code: 200, Response: release},
+		test.Http{Method: "GET", Path: "/apps/foo/releases", Code: 200, Response: models.Releases{release}},
+		test.Http{Method: "GET", Path: "/apps/foo/releases/R1/promotions", Code: 200, Response: models.ReleasePromotions{
+			{Release: "R1", User: "ops@example.org", Created: time.Now().Add(-49 * time.Hour), Rack: "production", Overrides: []string{"web command=bin/web --workers 8", "web scale.count=4"}},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox releases info R1 --app foo",
+			Exit:    0,
+			Stdout: `Id        R1
+Build     B1
+Created   2016-10-02 12:00:00 +0000 UTC
+Creator   unknown
+Env       
+Manifest  web:
+            image: web
+Promoted  2 days ago by ops@example.org on production
+            override web command=bin/web --workers 8
+            override web scale.count=4
+`,
+		},
+	)
+}
+
+func TestReleaseDiff(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo/releases/R1/diff/R2", Code: 200, Response: models.ReleaseDiff{
+			App:   "foo",
+			From:  "R1",
+			To:    "R2",
+			Build: &models.ReleaseDiffBuild{From: "B1", To: "B2"},
+			Services: models.ReleaseDiffEntries{
+				{Name: "web", Change: "changed", Fields: []string{"command", "ports"}},
+				{Name: "worker", Change: "added"},
+			},
+			Env: models.ReleaseDiffEntries{
+				{Name: "DATABASE_URL", Change: "added"},
+				{Name: "DEBUG", Change: "removed"},
+				{Name: "PORT", Change: "changed"},
+			},
+		}},
+		test.Http{Method: "GET", Path: "/apps/foo/releases/R2/diff/R3", Code: 200, Response: models.ReleaseDiff{App: "foo", From: "R2", To: "R3"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox releases diff R1 R2 --app foo",
+			Exit:    0,
+			Stdout:  "Build     B1 -> B2\nServices  web: command, ports changed\n          worker: added\nEnv       +DATABASE_URL\n          -DEBUG\n          ~PORT\n",
+		},
+		test.ExecRun{
+			Command: "convox releases diff R2 R3 --app foo",
+			Exit:    0,
+			Stdout:  "No changes between R2 and R3\n",
+		},
+	)
+}
+
+func TestReleasePromoteCanary(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "POST", Path: "/apps/foo/releases/R2/canary", Body: "percent=10", Code: 200, Response: models.Canary{App: "foo", Release: "R2", Percent: 10}},
+		test.Http{Method: "POST", Path: "/apps/bar/releases/R2/canary", Body: "percent=10", Code: 403, Response: client.Error{Error: "app already has a canary of R1, finalize or abort it first"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox releases promote --app foo --canary 10% R2",
+			Exit:    0,
+			Stdout:  "Starting canary of R2 with 10% of traffic... OK\nRun `convox releases finalize --app foo` to promote R2 or `convox releases abort --app foo` to stop it\n",
+		},
+		test.ExecRun{
+			Command: "convox releases promote --app bar --canary 10 R2",
+			Exit:    1,
+			Stdout:  "Starting canary of R2 with 10% of traffic... ",
+			Stderr:  "ERROR: app already has a canary of R1, finalize or abort it first\n",
+		},
+		test.ExecRun{
+			Command: "convox releases promote --app foo --canary some R2",
+			Exit:    1,
+			Stderr:  "ERROR: canary must be a percentage, i.e. 10%\n",
+		},
+	)
+}
+
+func TestReleasePromoteDryRun(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "POST", Path: "/apps/foo/releases/R2/promote/preview", Code: 200, Response: models.StackChanges{
+			{Action: "Modify", Resource: "ServiceWeb", Type: "AWS::ECS::Service", Replacement: "False"},
+		}},
+		test.Http{Method: "POST", Path: "/apps/foo/releases/R3/promote/preview", Code: 404, Response: client.Error{Error: "no such release: R3"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox releases promote --app foo --dry-run R2",
+			Exit:    0,
+			Stdout:  "Would POST /apps/foo/releases/R2/promote\nACTION  RESOURCE    TYPE               REPLACEMENT\nModify  ServiceWeb  AWS::ECS::Service  False\n",
+		},
+		test.ExecRun{
+			Command: "convox releases promote --app foo --dry-run R3",
+			Exit:    1,
+			Stdout:  "Would POST /apps/foo/releases/R3/promote\n",
+			Stderr:  "ERROR: no such release: R3\n",
+		},
+		test.ExecRun{
+			Command: "convox releases promote --app foo --canary 10% --dry-run R2",
+			Exit:    0,
+			Stdout:  "Would POST /apps/foo/releases/R2/canary\n  percent=10\n",
+		},
+	)
+}
+
+func TestReleasePromoteProcesses(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "POST", Path: "/apps/foo/releases/R2/promote", Body: "processes=worker", Code: 200, Response: models.Release{Id: "R2", App: "foo", Processes: []string{"worker"}}},
+		test.Http{Method: "POST", Path: "/apps/foo/releases/R2/promote/preview", Body: "processes=worker%2Cweb", Code: 200, Response: models.StackChanges{
+			{Action: "Modify", Resource: "WEBECSTaskDefinition", Type: "Custom::ECSTaskDefinition", Replacement: "False"},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox releases promote --app foo --processes worker R2",
+			Exit:    0,
+			Stdout:  "Promoting R2 for worker... UPDATING\n",
+		},
+		test.ExecRun{
+			Command: "convox releases promote --app foo --processes worker,web --dry-run R2",
+			Exit:    0,
+			Stdout:  "Would POST /apps/foo/releases/R2/promote\n  processes=worker,web\nACTION  RESOURCE              TYPE                       REPLACEMENT\nModify  WEBECSTaskDefinition  Custom::ECSTaskDefinition  False\n",
+		},
+		test.ExecRun{
+			Command: "convox releases promote --app foo --processes worker --canary 10% R2",
+			Exit:    1,
+			Stderr:  "ERROR: --processes can not be used with --canary\n",
+		},
+	)
+}
+
+func TestReleaseFinalizeAbort(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo/canary", Code: 200, Response: models.Canary{App: "foo", Release: "R2", Percent: 10}},
+		test.Http{Method: "POST", Path: "/apps/foo/canary/finalize", Code: 200, Response: models.Release{Id: "R2"}},
+		test.Http{Method: "DELETE", Path: "/apps/foo/canary", Code: 200, Response: map[string]bool{"success": true}},
+		test.Http{Method: "GET", Path: "/apps/bar/canary", Code: 404, Response: client.Error{Error: "no canary for app: bar"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox releases finalize --app foo",
+			Exit:    0,
+			Stdout:  "Promoting R2... UPDATING\n",
+		},
+		test.ExecRun{
+			Command: "convox releases abort --app foo",
+			Exit:    0,
+			Stdout:  "Stopping canary of R2... OK\n",
+		},
+		test.ExecRun{
+			Command: "convox releases abort --app bar",
+			Exit:    1,
+			Stderr:  "ERROR: no canary for app: bar\n",
+		},
+	)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/cmd/convox/stdcli"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func init() {
+	stdcli.RegisterCommand(cli.Command{
+		Name:        "reports",
+		Description: "generate reports about an app",
+		Usage:       "<report>",
+		Action:      cmdReports,
+		Subcommands: []cli.Command{
+			{
+				Name:        "uptime",
+				Description: "report the availability of an app over a month",
+				Usage:       "[--month 2016-10] [--csv <file>]",
+				Action:      cmdReportsUptime,
+				Flags: []cli.Flag{
+					appFlag,
+					rackFlag,
+					cli.StringFlag{
+						Name:  "month",
+						Usage: "month to report on, e.g. 2016-10 (default this month)",
+					},
+					cli.StringFlag{
+						Name:  "csv",
+						Usage: "also write the report as CSV to this file, or use - to print only the CSV",
+					},
+				},
+			},
+		},
+	})
+}
+
+func cmdReports(c *cli.Context) error {
+	stdcli.Usage(c, "reports")
+	return nil
+}
+
+func cmdReportsUptime(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) > 0 {
+		return stdcli.ExitError(fmt.Errorf("`convox reports uptime` does not take arguments"))
+	}
+
+	report, err := rackClient(c).GetUptimeReport(app, c.String("month"))
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if file := c.String("csv"); file == "-" {
+		return writeUptimeCSV(os.Stdout, report)
+	} else if file != "" {
+		f, err := os.Create(file)
+		if err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		defer f.Close()
+
+		if err := writeUptimeCSV(f, report); err != nil {
+			return stdcli.ExitError(err)
+		}
+	}
+
+	fmt.Printf("Uptime of %s from %s to %s\n\n", report.App, report.Start.Format("2006-01-02 15:04"), report.End.Format("2006-01-02 15:04"))
+
+	if len(report.Processes) == 0 {
+		fmt.Println("No processes behind a load balancer")
+		return nil
+	}
+
+	t := stdcli.NewTable("PROCESS", "AVAILABILITY", "DOWNTIME", "REQUESTS", "5XX", "SUCCESS")
+
+	for _, p := range report.Processes {
+		t.AddRow(p.Process, fmt.Sprintf("%.3f%%", p.Availability), fmt.Sprintf("%d min", p.Downtime), strconv.FormatInt(p.Requests, 10), strconv.FormatInt(p.Errors, 10), fmt.Sprintf("%.3f%%", p.SuccessRate))
+	}
+
+	t.Print()
+	return nil
+}
+
+func writeUptimeCSV(w io.Writer, report *models.UptimeReport) error {
+	cw := csv.NewWriter(w)
+
+	cw.Write([]string{"app", "month", "process", "availability", "downtime_minutes", "measured_minutes", "requests", "errors", "success_rate"})
+
+	for _, p := range report.Processes {
+		cw.Write([]string{
+			report.App,
+			report.Month,
+			p.Process,
+			strconv.FormatFloat(p.Availability, 'f', 3, 64),
+			strconv.Itoa(p.Downtime),
+			strconv.Itoa(p.Measured),
+			strconv.FormatInt(p.Requests, 10),
+			strconv.FormatInt(p.Errors, 10),
+			strconv.FormatFloat(p.SuccessRate, 'f', 3, 64),
+		})
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/test"
+)
+
+func TestReportsUptime(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/myapp/reports/uptime", Code: 200, Response: models.UptimeReport{
+			App:   "myapp",
+			Month: "2016-09",
+			Start: time.Date(2016, 9, 1, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2016, 10, 1, 0, 0, 0, 0, time.UTC),
+			Processes: []models.ProcessUptime{
+				{Process: "web", Availability: 99.95, Downtime: 20, Measured: 43200, Requests: 120000, Errors: 30, SuccessRate: 99.975},
+			},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox reports uptime --app myapp --month 2016-09",
+			Exit:    0,
+			Stdout:  "Uptime of myapp from 2016-09-01 00:00 to 2016-10-01 00:00\n\nPROCESS  AVAILABILITY  DOWNTIME  REQUESTS  5XX  SUCCESS\nweb      99.950%       20 min    120000    30   99.975%\n",
+		},
+		test.ExecRun{
+			Command: "convox reports uptime --app myapp --month 2016-09 --csv -",
+			Exit:    0,
+			Stdout:  "app,month,process,availability,downtime_minutes,measured_minutes,requests,errors,success_rate\nmyapp,2016-09,web,99.950,20,43200,120000,30,99.975\n",
+		},
+	)
+}
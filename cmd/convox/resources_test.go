@@ -0,0 +1,103 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/test"
+)
+
+func TestResources(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/services", Code: 200, Response: models.Services{
+			{Name: "maindb", Type: "postgres", Status: "running"},
+			{Name: "cache", Type: "redis", Status: "creating"},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox resources",
+			Exit:    0,
+			Stdout:  "NAME    TYPE      STATUS\nmaindb  postgres  running\ncache   redis     creating\n",
+		},
+	)
+}
+
+func TestResourcesLink(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "POST", Path: "/services/maindb/links", Body: "app=foo", Code: 200, Response: models.Service{Name: "maindb"}, Headers: map[string]string{"Release-Id": "R1"}},
+		test.Http{Method: "POST", Path: "/services/papertrail/links", Body: "app=foo", Code: 200, Response: models.Service{Name: "papertrail"}},
+		test.Http{Method: "DELETE", Path: "/services/cache/links/foo", Code: 403, Response: client.Error{Error: "Service cache is not linked to app foo"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox resources link maindb --app foo",
+			Exit:    0,
+			Stdout:  "Linked maindb to foo\nTo deploy these changes run `convox releases promote R1`\n",
+		},
+		test.ExecRun{
+			Command: "convox resources link papertrail --app foo",
+			Exit:    0,
+			Stdout:  "Linked papertrail to foo\n",
+		},
+		test.ExecRun{
+			Command: "convox resources unlink cache --app foo",
+			Exit:    1,
+			Stderr:  "ERROR: Service cache is not linked to app foo\n",
+		},
+	)
+}
+
+func TestResourcesLinkPromote(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "POST", Path: "/services/maindb/links", Body: "app=foo", Code: 200, Response: models.Service{Name: "maindb"}, Headers: map[string]string{"Release-Id": "R1"}},
+		test.Http{Method: "POST", Path: "/apps/foo/releases/R1/promote", Code: 200, Response: models.Release{Id: "R1"}},
+		test.Http{Method: "DELETE", Path: "/services/maindb/links/foo", Code: 200, Response: models.Service{Name: "maindb"}, Headers: map[string]string{"Release-Id": "R2"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox resources link maindb --app foo --promote",
+			Exit:    0,
+			Stdout:  "Linked maindb to foo\nPromoting R1... OK\n",
+		},
+		test.ExecRun{
+			Command: "convox resources unlink maindb --app foo",
+			Exit:    0,
+			Stdout:  "Unlinked maindb from foo\nTo deploy these changes run `convox releases promote R2`\n",
+		},
+	)
+}
+
+func TestResourcesInfoLinks(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/services/maindb", Code: 200, Response: models.Service{
+			Name:   "maindb",
+			Status: "running",
+			URL:    "postgres://db",
+			Links: models.ServiceLinks{
+				{App: "api", Variable: "MAINDB_URL"},
+				{App: "web", Variable: "MAINDB_URL"},
+			},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox resources info maindb",
+			Exit:    0,
+			Stdout:  "Name    maindb\nStatus  running\nURL     postgres://db\nApps\n  api: MAINDB_URL\n  web: MAINDB_URL\n",
+		},
+	)
+}
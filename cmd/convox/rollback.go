@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/cmd/convox/stdcli"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func init() {
+	stdcli.RegisterCommand(cli.Command{
+		Name:        "rollback",
+		Description: "promote the previously promoted release of an app",
+		Usage:       "[release id]",
+		Action:      cmdRollback,
+		Flags: []cli.Flag{
+			appFlag,
+			rackFlag,
+			cli.BoolFlag{
+				Name:  "wait",
+				Usage: "wait for release to finish promoting before returning",
+			},
+		},
+	})
+}
+
+func cmdRollback(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) > 1 {
+		stdcli.Usage(c, "rollback")
+		return nil
+	}
+
+	a, err := rackClient(c).GetApp(app)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	releases, err := rackClient(c).GetReleases(app)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	var current *models.Release
+
+	for i := range releases {
+		if releases[i].Id == a.Release {
+			current = &releases[i]
+		}
+	}
+
+	if current == nil {
+		return stdcli.ExitError(fmt.Errorf("%s has no active release to roll back", app))
+	}
+
+	var target *models.Release
+
+	if len(c.Args()) == 1 {
+		target, err = rackClient(c).GetRelease(app, c.Args()[0])
+		if err != nil {
+			return stdcli.ExitError(err)
+		}
+	} else {
+		target, err = rollbackTarget(rackClient(c), app, releases, *current)
+		if err != nil {
+			return stdcli.ExitError(err)
+		}
+	}
+
+	if target.Id == current.Id {
+		return stdcli.ExitError(fmt.Errorf("%s is already the active release", target.Id))
+	}
+
+	// a release whose build has been cleaned up can not start its processes
+	b, err := rackClient(c).GetBuild(app, target.Build)
+	if _, ok := err.(client.ErrNotFound); ok {
+		return stdcli.ExitError(fmt.Errorf("build %s of release %s no longer exists", target.Build, target.Id))
+	}
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+	if b.Status != "complete" {
+		return stdcli.ExitError(fmt.Errorf("build %s of release %s is %s", target.Build, target.Id, b.Status))
+	}
+
+	changes, err := releaseChanges(*current, *target)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(changes) == 0 {
+		changes = []string{"none"}
+	}
+
+	fmt.Printf("Rolling back %s from %s to %s\n", app, current.Id, target.Id)
+	fmt.Printf("Changes   %s\n", indentLines(strings.Join(changes, "\n"), 10))
+
+	fmt.Printf("Promoting %s... ", target.Id)
+
+	if _, err := rackClient(c).PromoteRelease(app, target.Id); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("UPDATING")
+
+	if c.Bool("wait") {
+		fmt.Printf("Waiting for stabilization... ")
+
+		if err := waitForReleasePromotion(c, app, target.Id); err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		fmt.Println("OK")
+	}
+
+	return nil
+}
+
+// rollbackTarget returns the most recent release before current that was promoted.
+// Racks that do not record promotions fall back to the release created before current.
+func rollbackTarget(rc client.Interface, app string, releases models.Releases, current models.Release) (*models.Release, error) {
+	r := current
+
+	for {
+		prev := previousRelease(releases, r)
+		if prev == nil {
+			return nil, fmt.Errorf("no earlier promoted release of %s to roll back to", app)
+		}
+
+		promotions, err := rc.GetReleasePromotions(app, prev.Id)
+		if _, ok := err.(client.ErrNotFound); ok {
+			return previousRelease(releases, current), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(promotions) > 0 {
+			return prev, nil
+		}
+
+		r = *prev
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/test"
+)
+
+func TestRollback(t *testing.T) {
+	created := time.Date(2016, 10, 2, 12, 0, 0, 0, time.UTC)
+
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo", Code: 200, Response: models.App{Name: "foo", Release: "R3"}},
+		test.Http{Method: "GET", Path: "/apps/foo/releases", Code: 200, Response: models.Releases{
+			{Id: "R3", App: "foo", Build: "B2", Env: "PORT=3000\nDEBUG=1", Manifest: "web:\n  image: web\n", Created: created},
+			{Id: "R2", App: "foo", Build: "B2", Env: "PORT=3000", Manifest: "web:\n  image: web\n", Created: created.Add(-time.Hour)},
+			{Id: "R1", App: "foo", Build: "B1", Env: "PORT=3000", Manifest: "web:\n  image: web\n", Created: created.Add(-2 * time.Hour)},
+		}},
+		test.Http{Method: "GET", Path: "/apps/foo/releases/R2/promotions", Code: 200, Response: models.ReleasePromotions{}},
+		test.Http{Method: "GET", Path: "/apps/foo/releases/R1/promotions", Code: 200, Response: models.ReleasePromotions{
+			{Release: "R1", User: "ops@example.org", Created: created.Add(-2 * time.Hour)},
+		}},
+		test.Http{Method: "GET", Path: "/apps/foo/builds/B1", Code: 200, Response: models.Build{Id: "B1", Status: "complete"}},
+		test.Http{Method: "POST", Path: "/apps/foo/releases/R1/promote", Code: 200, Response: models.Release{Id: "R1"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox rollback --app foo",
+			Exit:    0,
+			Stdout:  "Rolling back foo from R3 to R1\nChanges   build: B2 -> B1\n          env: -DEBUG\nPromoting R1... UPDATING\n",
+		},
+	)
+}
+
+func TestRollbackMissingBuild(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo", Code: 200, Response: models.App{Name: "foo", Release: "R2"}},
+		test.Http{Method: "GET", Path: "/apps/foo/releases", Code: 200, Response: models.Releases{
+			{Id: "R2", App: "foo", Build: "B2"},
+		}},
+		test.Http{Method: "GET", Path: "/apps/foo/releases/R1", Code: 200, Response: models.Release{Id: "R1", App: "foo", Build: "B1"}},
+		test.Http{Method: "GET", Path: "/apps/foo/builds/B1", Code: 404, Response: client.Error{Error: "no such build: B1"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox rollback R1 --app foo",
+			Exit:    1,
+			Stderr:  "ERROR: build B1 of release R1 no longer exists\n",
+		},
+	)
+}
@@ -5,8 +5,6 @@ import (
 	"os"
 	"strings"
 
-	"golang.org/x/crypto/ssh/terminal"
-
 	"github.com/convox/rack/cmd/convox/stdcli"
 	"gopkg.in/urfave/cli.v1"
 )
@@ -103,25 +101,16 @@ func cmdRunDetached(c *cli.Context) error {
 }
 
 func runAttached(c *cli.Context, app, ps, args, release string) (int, error) {
-	fd := os.Stdin.Fd()
-
-	var w, h int
-
-	if terminal.IsTerminal(int(fd)) {
-		stdinState, err := terminal.GetState(int(fd))
-		if err != nil {
-			return -1, err
-		}
-
-		defer terminal.Restore(int(fd), stdinState)
+	rc := rackClient(c)
 
-		w, h, err = terminal.GetSize(int(fd))
-		if err != nil {
-			return -1, err
-		}
+	restore, w, h, err := attachTerminal()
+	if err != nil {
+		return -1, err
 	}
 
-	code, err := rackClient(c).RunProcessAttached(app, ps, args, release, h, w, os.Stdin, os.Stdout)
+	defer restore()
+
+	code, err := rc.RunProcessAttached(app, ps, args, release, h, w, os.Stdin, os.Stdout)
 	if err != nil {
 		return -1, err
 	}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/test"
+)
+
+func TestRunDetached(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/myapp/formation", Code: 200, Response: models.Formation{
+			{Name: "web", Count: 2},
+		}},
+		test.Http{Method: "POST", Path: "/apps/myapp/processes/web/run", Body: "command=bin%2Fmigrate+--all&release=R1234", Code: 200, Response: map[string]string{"success": "true"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox run --detach --release R1234 --app myapp web bin/migrate --all",
+			Exit:    0,
+			Stdout:  "Running `bin/migrate --all` on web... OK\n",
+		},
+		test.ExecRun{
+			Command: "convox run --detach --app myapp worker bin/migrate",
+			Exit:    1,
+			Stderr:  "ERROR: Unknown process name: worker\n",
+		},
+	)
+}
@@ -2,8 +2,10 @@ package main
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/models"
 	"github.com/convox/rack/cmd/convox/stdcli"
 	"gopkg.in/urfave/cli.v1"
 )
@@ -12,7 +14,7 @@ func init() {
 	stdcli.RegisterCommand(cli.Command{
 		Name:        "scale",
 		Description: "scale an app's processes",
-		Usage:       "<process> [--count=2] [--memory=256] [--cpu=256]",
+		Usage:       "<process> [--count=2] [--memory=256] [--cpu=256] [--and-grow] [--autoscale --min=2 --max=10 --cpu=70]",
 		Action:      cmdScale,
 		Flags: []cli.Flag{
 			appFlag,
@@ -27,7 +29,27 @@ func init() {
 			},
 			cli.IntFlag{
 				Name:  "cpu",
-				Usage: "CPU units available to specified process type.",
+				Usage: "CPU units available to specified process type, or with --autoscale the CPU percent to add processes above.",
+			},
+			cli.BoolFlag{
+				Name:  "autoscale",
+				Usage: "keep the count between --min and --max based on CPU utilization, --autoscale=false returns to a fixed count",
+			},
+			cli.IntFlag{
+				Name:  "min",
+				Usage: "fewest processes to run when autoscaling",
+			},
+			cli.IntFlag{
+				Name:  "max",
+				Usage: "most processes to run when autoscaling",
+			},
+			cli.BoolFlag{
+				Name:  "and-grow",
+				Usage: "add rack instances if the change does not fit on the current ones",
+			},
+			cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "preview the change and its placement impact without applying it",
 			},
 			cli.BoolFlag{
 				Name:  "wait",
@@ -49,7 +71,27 @@ func cmdScale(c *cli.Context) error {
 		opts.Count = c.String("count")
 	}
 
-	if c.IsSet("cpu") {
+	if c.IsSet("autoscale") {
+		opts.Autoscale = fmt.Sprintf("%t", c.Bool("autoscale"))
+	}
+
+	if (c.IsSet("min") || c.IsSet("max")) && opts.Autoscale != "true" {
+		return stdcli.ExitError(fmt.Errorf("--min and --max require --autoscale"))
+	}
+
+	if opts.Autoscale == "true" {
+		if c.IsSet("min") {
+			opts.AutoscaleMin = c.String("min")
+		}
+
+		if c.IsSet("max") {
+			opts.AutoscaleMax = c.String("max")
+		}
+
+		if c.IsSet("cpu") {
+			opts.AutoscaleCPU = c.String("cpu")
+		}
+	} else if c.IsSet("cpu") {
 		opts.CPU = c.String("cpu")
 	}
 
@@ -57,17 +99,21 @@ func cmdScale(c *cli.Context) error {
 		opts.Memory = c.String("memory")
 	}
 
+	opts.Grow = c.Bool("and-grow")
+
+	changed := opts.Count != "" || opts.CPU != "" || opts.Memory != "" || opts.Autoscale != ""
+
 	// validate single process type argument
 	switch len(c.Args()) {
 	case 0:
-		if opts.Memory != "" || opts.CPU != "" || opts.Count != "" {
+		if changed {
 			return stdcli.ExitError(fmt.Errorf("missing process name"))
 		}
 
 		displayFormation(c, app)
 		return nil
 	case 1:
-		if opts.Count == "" && opts.CPU == "" && opts.Memory == "" {
+		if !changed {
 			displayFormation(c, app)
 			return nil
 		}
@@ -79,6 +125,15 @@ func cmdScale(c *cli.Context) error {
 
 	process := c.Args()[0]
 
+	if err := previewFormation(c, app, process, opts); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if c.Bool("dry-run") {
+		dryRunCall("POST", fmt.Sprintf("/apps/%s/formation/%s", app, process), opts.Params())
+		return nil
+	}
+
 	err = rackClient(c).SetFormation(app, process, opts)
 	if err != nil {
 		return stdcli.ExitError(err)
@@ -126,12 +181,138 @@ func displayFormation(c *cli.Context, app string) error {
 		}
 	}
 
-	t := stdcli.NewTable("NAME", "DESIRED", "RUNNING", "CPU", "MEMORY")
+	autoscaled := false
 
 	for _, f := range formation {
-		t.AddRow(f.Name, fmt.Sprintf("%d", f.Count), fmt.Sprintf("%d", running[f.Name]), fmt.Sprintf("%d", f.CPU), fmt.Sprintf("%d", f.Memory))
+		if f.Autoscale != nil {
+			autoscaled = true
+		}
+	}
+
+	headers := []string{"NAME", "DESIRED", "RUNNING", "CPU", "MEMORY"}
+
+	// only show autoscaling to apps that use it
+	if autoscaled {
+		headers = append(headers, "AUTOSCALE")
+	}
+
+	t := stdcli.NewTable(headers...)
+
+	for _, f := range formation {
+		row := []string{f.Name, fmt.Sprintf("%d", f.Count), fmt.Sprintf("%d", running[f.Name]), fmt.Sprintf("%d", f.CPU), fmt.Sprintf("%d", f.Memory)}
+
+		if autoscaled {
+			as := ""
+
+			if f.Autoscale != nil {
+				as = fmt.Sprintf("%d-%d at %d%% cpu", f.Autoscale.Min, f.Autoscale.Max, f.Autoscale.CPU)
+			}
+
+			row = append(row, as)
+		}
+
+		t.AddRow(row...)
 	}
 
 	t.Print()
 	return nil
 }
+
+// previewFormation validates a formation change against the rack's instance size
+// and, under --dry-run or when it exceeds the cluster, shows how it changes the reserved cpu and memory
+func previewFormation(c *cli.Context, app, process string, opts client.FormationOptions) error {
+	formation, err := rackClient(c).ListFormation(app)
+	if err != nil {
+		return err
+	}
+
+	var current *models.FormationEntry
+
+	for i := range formation {
+		if formation[i].Name == process {
+			current = &formation[i]
+			break
+		}
+	}
+
+	if current == nil {
+		return fmt.Errorf("no such process: %s", process)
+	}
+
+	capacity, err := rackClient(c).GetSystemCapacity()
+	if err != nil {
+		return err
+	}
+
+	next := *current
+
+	for _, o := range []struct {
+		name  string
+		value string
+		field *int
+	}{
+		{"count", opts.Count, &next.Count},
+		{"cpu", opts.CPU, &next.CPU},
+		{"memory", opts.Memory, &next.Memory},
+	} {
+		if o.value == "" {
+			continue
+		}
+
+		n, err := strconv.Atoi(o.value)
+		if err != nil {
+			return fmt.Errorf("%s must be numeric", o.name)
+		}
+
+		*o.field = n
+	}
+
+	if capacity.InstanceCPU > 0 && int64(next.CPU) > capacity.InstanceCPU {
+		return fmt.Errorf("requested cpu %d greater than instance size %d", next.CPU, capacity.InstanceCPU)
+	}
+
+	if capacity.InstanceMemory > 0 && int64(next.Memory) > capacity.InstanceMemory {
+		return fmt.Errorf("requested memory %d greater than instance size %d", next.Memory, capacity.InstanceMemory)
+	}
+
+	cpu := capacity.ProcessCPU - reserved(current.Count, current.CPU) + reserved(next.Count, next.CPU)
+	memory := capacity.ProcessMemory - reserved(current.Count, current.Memory) + reserved(next.Count, next.Memory)
+
+	// like the rack only warn about changes that need more than the process already holds
+	exceeds := (cpu > capacity.ClusterCPU && reserved(next.Count, next.CPU) > reserved(current.Count, current.CPU)) ||
+		(memory > capacity.ClusterMemory && reserved(next.Count, next.Memory) > reserved(current.Count, current.Memory))
+
+	// only show the preview when asked for or when the change needs attention
+	if !c.Bool("dry-run") && !exceeds {
+		return nil
+	}
+
+	t := stdcli.NewTable("RESOURCE", "CURRENT", "NEW")
+	t.AddRow("count", fmt.Sprintf("%d", current.Count), fmt.Sprintf("%d", next.Count))
+	t.AddRow("cpu", fmt.Sprintf("%d", current.CPU), fmt.Sprintf("%d", next.CPU))
+	t.AddRow("memory", fmt.Sprintf("%d", current.Memory), fmt.Sprintf("%d", next.Memory))
+	t.AddRow("cluster cpu", fmt.Sprintf("%d/%d", capacity.ProcessCPU, capacity.ClusterCPU), fmt.Sprintf("%d/%d", cpu, capacity.ClusterCPU))
+	t.AddRow("cluster memory", fmt.Sprintf("%d/%d", capacity.ProcessMemory, capacity.ClusterMemory), fmt.Sprintf("%d/%d", memory, capacity.ClusterMemory))
+	t.Print()
+
+	if exceeds {
+		if opts.Grow {
+			fmt.Println("this exceeds the current cluster capacity, rack instances will be added to make room")
+		} else {
+			fmt.Println("WARNING: this exceeds the current cluster capacity, use --and-grow to add rack instances")
+		}
+	}
+
+	fmt.Println()
+
+	return nil
+}
+
+// reserved returns the resources held by count processes, treating negative counts as none running
+func reserved(count, size int) int64 {
+	if count < 0 {
+		return 0
+	}
+
+	return int64(count * size)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/test"
+)
+
+func TestScaleDryRun(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/myapp/formation", Code: 200, Response: models.Formation{
+			{Name: "web", Count: 2, CPU: 256, Memory: 512},
+		}},
+		test.Http{Method: "GET", Path: "/system/capacity", Code: 200, Response: models.SystemCapacity{
+			ClusterCPU:     2048,
+			ClusterMemory:  4096,
+			InstanceCPU:    1024,
+			InstanceMemory: 2048,
+			ProcessCPU:     512,
+			ProcessMemory:  1024,
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox scale web --count 4 --memory 1024 --cpu 512 --dry-run --app myapp",
+			Exit:    0,
+			Stdout:  "RESOURCE        CURRENT    NEW\ncount           2          4\ncpu             256        512\nmemory          512        1024\ncluster cpu     512/2048   2048/2048\ncluster memory  1024/4096  4096/4096\n\nWould POST /apps/myapp/formation/web\n  count=4\n  cpu=512\n  memory=1024\n",
+		},
+		test.ExecRun{
+			Command: "convox scale web --count 8 --memory 1024 --dry-run --app myapp",
+			Exit:    0,
+			Stdout:  "RESOURCE        CURRENT    NEW\ncount           2          8\ncpu             256        256\nmemory          512        1024\ncluster cpu     512/2048   2048/2048\ncluster memory  1024/4096  8192/4096\nWARNING: this exceeds the current cluster capacity, use --and-grow to add rack instances\n\nWould POST /apps/myapp/formation/web\n  count=8\n  memory=1024\n",
+		},
+		test.ExecRun{
+			Command: "convox scale web --memory 4096 --dry-run --app myapp",
+			Exit:    1,
+			Stderr:  "ERROR: requested memory 4096 greater than instance size 2048\n",
+		},
+	)
+}
+
+func TestScaleAutoscale(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/myapp/formation", Code: 200, Response: models.Formation{
+			{Name: "web", Count: 2, CPU: 256, Memory: 512, Autoscale: &models.FormationAutoscale{Min: 2, Max: 10, CPU: 70}},
+			{Name: "worker", Count: 1, CPU: 0, Memory: 256},
+		}},
+		test.Http{Method: "GET", Path: "/system/capacity", Code: 200, Response: models.SystemCapacity{
+			ClusterCPU:     2048,
+			ClusterMemory:  4096,
+			InstanceCPU:    1024,
+			InstanceMemory: 2048,
+			ProcessCPU:     512,
+			ProcessMemory:  1024,
+		}},
+		test.Http{Method: "GET", Path: "/apps/myapp/processes", Code: 200, Response: models.Processes{}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox scale web --autoscale --min 2 --max 10 --cpu 70 --dry-run --app myapp",
+			Exit:    0,
+			Stdout:  "RESOURCE        CURRENT    NEW\ncount           2          2\ncpu             256        256\nmemory          512        512\ncluster cpu     512/2048   512/2048\ncluster memory  1024/4096  1024/4096\n\nWould POST /apps/myapp/formation/web\n  autoscale=true\n  autoscale-cpu=70\n  autoscale-max=10\n  autoscale-min=2\n",
+		},
+		test.ExecRun{
+			Command: "convox scale web --max 10 --app myapp",
+			Exit:    1,
+			Stderr:  "ERROR: --min and --max require --autoscale\n",
+		},
+		test.ExecRun{
+			Command: "convox scale --app myapp",
+			Exit:    0,
+			Stdout:  "NAME    DESIRED  RUNNING  CPU  MEMORY  AUTOSCALE\nweb     2        0        256  512     2-10 at 70% cpu\nworker  1        0        0    256\n",
+		},
+	)
+}
+
+func TestScale(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/myapp/formation", Code: 200, Response: models.Formation{
+			{Name: "web", Count: 2, CPU: 256, Memory: 512},
+		}},
+		test.Http{Method: "GET", Path: "/system/capacity", Code: 200, Response: models.SystemCapacity{
+			ClusterCPU:     2048,
+			ClusterMemory:  4096,
+			InstanceCPU:    1024,
+			InstanceMemory: 2048,
+			ProcessCPU:     512,
+			ProcessMemory:  1024,
+		}},
+		test.Http{Method: "POST", Path: "/apps/myapp/formation/web", Body: "count=3&cpu=256&memory=512", Code: 200, Response: map[string]bool{"success": true}},
+		test.Http{Method: "GET", Path: "/apps/myapp/processes", Code: 200, Response: models.Processes{}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox scale web --count 3 --memory 512 --cpu 256 --app myapp",
+			Exit:    0,
+			Stdout:  "NAME  DESIRED  RUNNING  CPU  MEMORY\nweb   2        0        256  512\n",
+		},
+		test.ExecRun{
+			Command: "convox scale --count 3 --app myapp",
+			Exit:    1,
+			Stderr:  "ERROR: missing process name\n",
+		},
+	)
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/convox/rack/cmd/convox/stdcli"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func init() {
+	stdcli.RegisterCommand(cli.Command{
+		Name:        "secrets",
+		Description: "manage an app's secrets",
+		Usage:       "",
+		Action:      cmdSecrets,
+		Flags:       []cli.Flag{appFlag, rackFlag},
+		Subcommands: []cli.Command{
+			{
+				Name:        "get",
+				Description: "print the value of a secret",
+				Usage:       "<NAME>",
+				Action:      cmdSecretGet,
+				Flags:       []cli.Flag{appFlag, rackFlag},
+			},
+			{
+				Name:        "set",
+				Description: "set a secret, reading the value from stdin if it is not given",
+				Usage:       "<NAME> [VALUE]",
+				Action:      cmdSecretSet,
+				Flags:       []cli.Flag{appFlag, rackFlag},
+			},
+			{
+				Name:        "rm",
+				Description: "remove a secret",
+				Usage:       "<NAME>",
+				Action:      cmdSecretRemove,
+				Flags:       []cli.Flag{appFlag, rackFlag},
+			},
+		},
+	})
+}
+
+func cmdSecrets(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) > 0 {
+		return stdcli.ExitError(fmt.Errorf("`convox secrets` does not take arguments. Perhaps you meant `convox secrets set`?"))
+	}
+
+	secrets, err := rackClient(c).GetSecrets(app)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	t := stdcli.NewTable("NAME", "REFERENCE")
+
+	for _, name := range secrets {
+		t.AddRow(name, fmt.Sprintf("${secret:%s}", name))
+	}
+
+	t.Print()
+
+	return nil
+}
+
+func cmdSecretGet(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "get")
+		return nil
+	}
+
+	value, err := rackClient(c).GetSecret(app, c.Args()[0])
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println(value)
+
+	return nil
+}
+
+func cmdSecretSet(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) < 1 || len(c.Args()) > 2 {
+		stdcli.Usage(c, "set")
+		return nil
+	}
+
+	name := c.Args()[0]
+	value := ""
+
+	if len(c.Args()) == 2 {
+		value = c.Args()[1]
+	} else {
+		// reading from stdin keeps the value out of shell history
+		stat, err := os.Stdin.Stat()
+		if err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		if (stat.Mode() & os.ModeCharDevice) != 0 {
+			return stdcli.ExitError(fmt.Errorf("no value given, pass one as an argument or on stdin"))
+		}
+
+		data, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		value = strings.TrimSuffix(string(data), "\n")
+	}
+
+	fmt.Printf("Setting secret %s... ", name)
+
+	if err := rackClient(c).SetSecret(app, name, value); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+	fmt.Printf("Refer to it in the environment as ${secret:%s}, it is read when a release is promoted\n", name)
+
+	return nil
+}
+
+func cmdSecretRemove(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "rm")
+		return nil
+	}
+
+	name := c.Args()[0]
+
+	fmt.Printf("Removing secret %s... ", name)
+
+	if err := rackClient(c).DeleteSecret(app, name); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+
+	return nil
+}
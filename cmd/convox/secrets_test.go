@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/convox/rack/test"
+)
+
+func TestSecrets(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo/secrets", Code: 200, Response: []string{"api-token", "db-password"}},
+		test.Http{Method: "GET", Path: "/apps/foo/secrets/db-password", Code: 200, Response: "hunter2"},
+		test.Http{Method: "POST", Path: "/apps/foo/secrets/db-password", Body: "hunter3", Code: 200, Response: map[string]bool{"success": true}},
+		test.Http{Method: "DELETE", Path: "/apps/foo/secrets/api-token", Code: 200, Response: map[string]bool{"success": true}},
+		test.Http{Method: "DELETE", Path: "/apps/foo/secrets/missing", Code: 404, Response: map[string]string{"error": "no such secret: missing"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox secrets --app foo",
+			Exit:    0,
+			Stdout:  "NAME         REFERENCE\napi-token    ${secret:api-token}\ndb-password  ${secret:db-password}\n",
+		},
+		test.ExecRun{
+			Command: "convox secrets get db-password --app foo",
+			Exit:    0,
+			Stdout:  "hunter2\n",
+		},
+		test.ExecRun{
+			Command: "convox secrets set --app foo db-password",
+			Stdin:   "hunter3\n",
+			Exit:    0,
+			Stdout:  "Setting secret db-password... OK\nRefer to it in the environment as ${secret:db-password}, it is read when a release is promoted\n",
+		},
+		test.ExecRun{
+			Command: "convox secrets rm --app foo api-token",
+			Exit:    0,
+			Stdout:  "Removing secret api-token... OK\n",
+		},
+		test.ExecRun{
+			Command: "convox secrets rm --app foo missing",
+			Exit:    1,
+			Stderr:  "ERROR: no such secret: missing\n",
+		},
+	)
+}
@@ -65,7 +65,7 @@ func init() {
 		usage += fmt.Sprintf("\n  %-10s  %s", t.name, t.args)
 	}
 
-	stdcli.RegisterCommand(cli.Command{
+	services := cli.Command{
 		Name:        "services",
 		Description: "manage services",
 		Usage:       "",
@@ -107,14 +107,28 @@ func init() {
 				Description: "create a link between a service and an app.",
 				Usage:       "<name>",
 				Action:      cmdLinkCreate,
-				Flags:       []cli.Flag{appFlag, rackFlag},
+				Flags: []cli.Flag{
+					appFlag,
+					rackFlag,
+					cli.BoolFlag{
+						Name:  "promote",
+						Usage: "promote the release with the new environment",
+					},
+				},
 			},
 			{
 				Name:        "unlink",
 				Description: "delete a link between a service and an app.",
 				Usage:       "<name>",
 				Action:      cmdLinkDelete,
-				Flags:       []cli.Flag{appFlag, rackFlag},
+				Flags: []cli.Flag{
+					appFlag,
+					rackFlag,
+					cli.BoolFlag{
+						Name:  "promote",
+						Usage: "promote the release with the changed environment",
+					},
+				},
 			},
 			{
 				Name:        "url",
@@ -138,7 +152,17 @@ func init() {
 				},
 			},
 		},
-	})
+	}
+
+	stdcli.RegisterCommand(services)
+
+	// resources are the same stacks under the name they are known by in newer racks
+	resources := services
+	resources.Name = "resources"
+	resources.Description = "manage resources such as databases, caches, buckets and queues"
+	resources.Subcommands = append([]cli.Command{}, services.Subcommands...)
+
+	stdcli.RegisterCommand(resources)
 }
 
 func cmdServices(c *cli.Context) error {
@@ -310,6 +334,14 @@ func cmdServiceInfo(c *cli.Context) error {
 		fmt.Printf("URL     %s\n", service.URL)
 	}
 
+	if len(service.Links) > 0 {
+		fmt.Printf("Apps\n")
+
+		for _, link := range service.Links {
+			fmt.Printf("  %s: %s\n", link.App, link.Variable)
+		}
+	}
+
 	return nil
 }
 
@@ -352,13 +384,14 @@ func cmdLinkCreate(c *cli.Context) error {
 
 	name := c.Args()[0]
 
-	_, err = rackClient(c).CreateLink(app, name)
+	_, releaseID, err := rackClient(c).CreateLink(app, name)
 	if err != nil {
 		return stdcli.ExitError(err)
 	}
 
 	fmt.Printf("Linked %s to %s\n", name, app)
-	return nil
+
+	return envPromote(c, app, releaseID)
 }
 
 func cmdLinkDelete(c *cli.Context) error {
@@ -374,13 +407,14 @@ func cmdLinkDelete(c *cli.Context) error {
 
 	name := c.Args()[0]
 
-	_, err = rackClient(c).DeleteLink(app, name)
+	_, releaseID, err := rackClient(c).DeleteLink(app, name)
 	if err != nil {
 		return stdcli.ExitError(err)
 	}
 
 	fmt.Printf("Unlinked %s from %s\n", name, app)
-	return nil
+
+	return envPromote(c, app, releaseID)
 }
 
 func cmdServiceProxy(c *cli.Context) error {
@@ -20,15 +20,15 @@ func init() {
 		Subcommands: []cli.Command{
 			{
 				Name:        "update",
-				Description: "upload a replacement ssl certificate",
-				Usage:       "<process:port> <certificate>",
+				Description: "attach a certificate to a load balancer listener, uploading it first when given a key",
+				Usage:       "<process:port> <certificate> | <process:port> <cert.pub> <cert.key> [--chain <chain.pem>]",
 				Action:      cmdSSLUpdate,
 				Flags: []cli.Flag{
 					appFlag,
 					rackFlag,
 					cli.StringFlag{
 						Name:  "chain",
-						Usage: "Intermediate certificate chain.",
+						Usage: "intermediate certificate chain of an uploaded certificate",
 					},
 				},
 			},
@@ -72,7 +72,7 @@ func cmdSSLUpdate(c *cli.Context) error {
 		return stdcli.ExitError(err)
 	}
 
-	if len(c.Args()) < 2 {
+	if len(c.Args()) < 2 || len(c.Args()) > 3 {
 		stdcli.Usage(c, "update")
 		return nil
 	}
@@ -85,9 +85,31 @@ func cmdSSLUpdate(c *cli.Context) error {
 		return stdcli.ExitError(fmt.Errorf("target must be process:port"))
 	}
 
+	id := c.Args()[1]
+
+	if len(c.Args()) == 3 {
+		pub, key, chain, err := readCertificate(c.Args()[1], c.Args()[2], c.String("chain"))
+		if err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		fmt.Printf("Uploading certificate... ")
+
+		cert, err := rackClient(c).CreateCertificate(pub, key, chain)
+		if err != nil {
+			return stdcli.ExitError(err)
+		}
+
+		fmt.Printf("OK, %s\n", cert.Id)
+
+		id = cert.Id
+	} else if c.String("chain") != "" {
+		return stdcli.ExitError(fmt.Errorf("--chain can only be used when uploading a certificate and key"))
+	}
+
 	fmt.Printf("Updating certificate... ")
 
-	_, err = rackClient(c).UpdateSSL(app, parts[0], parts[1], c.Args()[1])
+	_, err = rackClient(c).UpdateSSL(app, parts[0], parts[1], id)
 	if err != nil {
 		return stdcli.ExitError(err)
 	}
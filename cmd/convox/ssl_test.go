@@ -0,0 +1,94 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSL(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo/ssl", Code: 200, Response: models.SSLs{
+			{Process: "web", Port: 443, Certificate: "acm-0123456789ab", Domain: "example.org"},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox ssl --app foo",
+			Exit:    0,
+			Stdout:  "TARGET   CERTIFICATE       DOMAIN       EXPIRES\nweb:443  acm-0123456789ab  example.org\n",
+		},
+	)
+}
+
+func TestSSLUpdate(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "PUT", Path: "/apps/foo/ssl/web/443", Body: "id=acm-0123456789ab", Code: 200, Response: models.SSL{Process: "web", Port: 443}},
+		test.Http{Method: "PUT", Path: "/apps/foo/ssl/web/444", Body: "id=cert-none", Code: 404, Response: client.Error{Error: "no such certificate: cert-none"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox ssl update web:443 acm-0123456789ab --app foo",
+			Exit:    0,
+			Stdout:  "Updating certificate... OK\n",
+		},
+		test.ExecRun{
+			Command: "convox ssl update web:444 cert-none --app foo",
+			Exit:    1,
+			Stdout:  "Updating certificate... ",
+			Stderr:  "ERROR: no such certificate: cert-none\n",
+		},
+		test.ExecRun{
+			Command: "convox ssl update web acm-0123456789ab --app foo",
+			Exit:    1,
+			Stderr:  "ERROR: target must be process:port\n",
+		},
+		test.ExecRun{
+			Command: "convox ssl update web:443 acm-0123456789ab --chain chain.pem --app foo",
+			Exit:    1,
+			Stderr:  "ERROR: --chain can only be used when uploading a certificate and key\n",
+		},
+	)
+}
+
+func TestSSLUpdateUpload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "convox-ssl")
+	require.Nil(t, err)
+
+	defer os.RemoveAll(dir)
+
+	pub := filepath.Join(dir, "cert.pub")
+	key := filepath.Join(dir, "cert.key")
+	chain := filepath.Join(dir, "chain.pem")
+
+	require.Nil(t, ioutil.WriteFile(pub, []byte("PUB"), 0644))
+	require.Nil(t, ioutil.WriteFile(key, []byte("KEY"), 0600))
+	require.Nil(t, ioutil.WriteFile(chain, []byte("CHAIN"), 0644))
+
+	ts := testServer(t,
+		test.Http{Method: "POST", Path: "/certificates", Body: "chain=CHAIN&private=KEY&public=PUB", Code: 200, Response: models.Certificate{Id: "cert-1234"}},
+		test.Http{Method: "PUT", Path: "/apps/foo/ssl/web/443", Body: "id=cert-1234", Code: 200, Response: models.SSL{Process: "web", Port: 443}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox ssl update web:443 " + pub + " " + key + " --chain " + chain + " --app foo",
+			Exit:    0,
+			Stdout:  "Uploading certificate... OK, cert-1234\nUpdating certificate... OK\n",
+		},
+	)
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/cmd/convox/stdcli"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func init() {
+	stdcli.RegisterCommand(cli.Command{
+		Name:        "status",
+		Description: "manage an app's public status page",
+		Usage:       "",
+		Action:      cmdStatus,
+		Flags:       []cli.Flag{appFlag, rackFlag},
+		Subcommands: []cli.Command{
+			{
+				Name:        "set",
+				Description: "set the state shown on the status page",
+				Usage:       "<operational|degraded|outage> [--message \"...\"]",
+				Action:      cmdStatusSet,
+				Flags: []cli.Flag{
+					appFlag,
+					rackFlag,
+					cli.StringFlag{
+						Name:  "message",
+						Usage: "explanation shown with the state",
+					},
+				},
+			},
+			{
+				Name:        "enable",
+				Description: "publish the status page and start recording uptime",
+				Usage:       "",
+				Action:      cmdStatusEnable,
+				Flags:       []cli.Flag{appFlag, rackFlag},
+			},
+			{
+				Name:        "disable",
+				Description: "hide the status page and stop recording uptime",
+				Usage:       "",
+				Action:      cmdStatusDisable,
+				Flags:       []cli.Flag{appFlag, rackFlag},
+			},
+		},
+	})
+}
+
+func cmdStatus(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) > 0 {
+		return stdcli.ExitError(fmt.Errorf("`convox status` does not take arguments. Perhaps you meant `convox status set`?"))
+	}
+
+	s, err := rackClient(c).GetAppStatus(app)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Printf("App      %s\n", s.App)
+	fmt.Printf("State    %s\n", s.State)
+	fmt.Printf("Message  %s\n", s.Message)
+	fmt.Printf("Uptime   %.2f%% (%d days)\n", s.Days.Uptime(), len(s.Days))
+	fmt.Printf("Page     %s\n", statusPageURL(s))
+
+	return nil
+}
+
+func cmdStatusSet(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "set")
+		return nil
+	}
+
+	state := c.Args()[0]
+
+	fmt.Printf("Setting status of %s to %s... ", app, state)
+
+	if _, err := rackClient(c).SetAppStatus(app, state, c.String("message")); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+	return nil
+}
+
+func cmdStatusEnable(c *cli.Context) error {
+	return setStatusPublic(c, true)
+}
+
+func cmdStatusDisable(c *cli.Context) error {
+	return setStatusPublic(c, false)
+}
+
+func setStatusPublic(c *cli.Context, public bool) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if public {
+		fmt.Printf("Publishing status page of %s... ", app)
+	} else {
+		fmt.Printf("Hiding status page of %s... ", app)
+	}
+
+	s, err := rackClient(c).SetAppStatusPublic(app, public)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+
+	if public {
+		fmt.Println(statusPageURL(s))
+	}
+
+	return nil
+}
+
+func statusPageURL(s *models.AppStatus) string {
+	if !s.Public {
+		return "disabled"
+	}
+
+	host, _, err := currentLogin()
+	if err != nil {
+		return fmt.Sprintf("/status/%s", s.App)
+	}
+
+	return fmt.Sprintf("https://%s/status/%s", host, s.App)
+}
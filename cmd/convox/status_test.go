@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/test"
+)
+
+func TestStatus(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo/status", Code: 200, Response: models.AppStatus{
+			App:     "foo",
+			State:   "degraded",
+			Message: "elevated error rates",
+			Days:    models.StatusDays{{Date: "2016-10-01", Up: 287, Checks: 288}, {Date: "2016-10-02", Up: 288, Checks: 288}},
+		}},
+		test.Http{Method: "POST", Path: "/apps/foo/status", Body: "message=elevated+error+rates&state=degraded", Code: 200, Response: models.AppStatus{App: "foo", State: "degraded"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox status --app foo",
+			Exit:    0,
+			Stdout:  "App      foo\nState    degraded\nMessage  elevated error rates\nUptime   99.83% (2 days)\nPage     disabled\n",
+		},
+		test.ExecRun{
+			Command: `convox status set degraded --message "elevated error rates" --app foo`,
+			Exit:    0,
+			Stdout:  "Setting status of foo to degraded... OK\n",
+		},
+	)
+}
@@ -0,0 +1,68 @@
+package stdcli
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+// OutputFormat is the format tables and logs are printed in, chosen with the global --output flag
+var OutputFormat = "table"
+
+// OutputFlag selects the OutputFormat. It is accepted before or after any command.
+var OutputFlag = cli.StringFlag{
+	Name:  "output",
+	Usage: "output format for tables and logs: table or json",
+}
+
+var regexpNonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+
+// OutputJSON returns true if commands should print machine-readable JSON instead of tables
+func OutputJSON() bool {
+	return OutputFormat == "json"
+}
+
+func setOutputFormat(c *cli.Context) error {
+	switch format := c.String("output"); format {
+	case "":
+	case "table", "json":
+		OutputFormat = format
+	default:
+		return ExitError(fmt.Errorf("unknown output format: %s", format))
+	}
+
+	return nil
+}
+
+// withOutputFlag adds OutputFlag to every command and subcommand
+func withOutputFlag(cmds []cli.Command) []cli.Command {
+	for i := range cmds {
+		before := cmds[i].Before
+
+		cmds[i].Flags = append(cmds[i].Flags, OutputFlag)
+		cmds[i].Before = func(c *cli.Context) error {
+			if err := setOutputFormat(c); err != nil {
+				return err
+			}
+
+			if before != nil {
+				return before(c)
+			}
+
+			return nil
+		}
+
+		cmds[i].Subcommands = withOutputFlag(cmds[i].Subcommands)
+	}
+
+	return cmds
+}
+
+// jsonKey turns a table header like "LAST RUN" or "CPU %" into a key like "last_run" or "cpu_percent"
+func jsonKey(header string) string {
+	key := strings.ToLower(strings.Replace(header, "%", "percent", -1))
+
+	return strings.Trim(regexpNonAlphanumeric.ReplaceAllString(key, "_"), "_")
+}
@@ -5,7 +5,6 @@ import (
 	"io/ioutil"
 	"os"
 	"os/exec"
-	"path"
 	"path/filepath"
 	"strings"
 	"time"
@@ -83,7 +82,9 @@ func New() *cli.App {
 	app.EnableBashCompletion = true
 
 	app.Name = Binary
-	app.Commands = Commands
+	app.Commands = withOutputFlag(Commands)
+	app.Flags = append(app.Flags, OutputFlag)
+	app.Before = setOutputFormat
 
 	app.CommandNotFound = func(c *cli.Context, cmd string) {
 		fmt.Fprintf(os.Stderr, "No such command \"%s\". Try `%s help`\n", cmd, Binary)
@@ -101,7 +102,7 @@ func Debug() bool {
 }
 
 // If user specifies the app's name from command line, then use it;
-// if not, try to read the app name from .convox/app
+// if not, use $CONVOX_APP or try to read the app name from .convox/app
 // otherwise use the current working directory's name
 func DirApp(c *cli.Context, wd string) (string, string, error) {
 	abs, err := filepath.Abs(wd)
@@ -112,12 +113,16 @@ func DirApp(c *cli.Context, wd string) (string, string, error) {
 
 	app := c.String("app")
 
+	if app == "" {
+		app = os.Getenv("CONVOX_APP")
+	}
+
 	if app == "" {
 		app = ReadSetting("app")
 	}
 
 	if app == "" {
-		app = path.Base(abs)
+		app = filepath.Base(abs)
 	}
 
 	app = strings.ToLower(app)
@@ -1,6 +1,7 @@
 package stdcli
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -23,7 +24,13 @@ func (t *Table) AddRow(values ...string) {
 	t.Rows = append(t.Rows, values)
 }
 
+// Print writes the table, or a JSON array with an object per row when the output format is json
 func (t *Table) Print() {
+	if OutputJSON() {
+		t.printJSON()
+		return
+	}
+
 	t.printValues(t.Headers)
 
 	for _, row := range t.Rows {
@@ -62,6 +69,25 @@ func (t *Table) printValues(values []string) {
 	fmt.Fprint(t.Output, line)
 }
 
+func (t *Table) printJSON() {
+	rows := make([]map[string]string, len(t.Rows))
+
+	for i, row := range t.Rows {
+		rows[i] = map[string]string{}
+
+		for j, header := range t.Headers {
+			if j < len(row) {
+				rows[i][jsonKey(header)] = row[j]
+			}
+		}
+	}
+
+	// a map of strings always marshals
+	data, _ := json.MarshalIndent(rows, "", "  ")
+
+	fmt.Fprintln(t.Output, string(data))
+}
+
 func interfaceSlice(ss []string) []interface{} {
 	is := make([]interface{}, len(ss))
 
@@ -27,3 +27,18 @@ func TestTableOutput(t *testing.T) {
 	assert.Equal(t, "bar foo baz  foo", lines[2])
 	assert.Equal(t, "", lines[3])
 }
+
+func TestTableOutputJSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	stdcli.OutputFormat = "json"
+	defer func() { stdcli.OutputFormat = "table" }()
+
+	tb := stdcli.NewTable("ID", "LAST RUN", "CPU %")
+	tb.Output = buf
+
+	tb.AddRow("foo", "2016-10-01T12:00:00Z", "1.50%")
+	tb.Print()
+
+	assert.Equal(t, "[\n  {\n    \"cpu_percent\": \"1.50%\",\n    \"id\": \"foo\",\n    \"last_run\": \"2016-10-01T12:00:00Z\"\n  }\n]\n", buf.String())
+}
@@ -0,0 +1,19 @@
+// +build !windows
+
+package main
+
+import (
+	"os"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// terminalSize returns the width and height of the terminal attached to stdin
+func terminalSize() (int, int, error) {
+	return terminal.GetSize(int(os.Stdin.Fd()))
+}
+
+// indexMode returns the mode to record for a file in a build index
+func indexMode(mode os.FileMode) os.FileMode {
+	return mode
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"os"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// terminalSize returns the width and height of the console.
+// Windows only reports the size on the output handle.
+func terminalSize() (int, int, error) {
+	return terminal.GetSize(int(os.Stdout.Fd()))
+}
+
+// indexMode returns the mode to record for a file in a build index.
+// Windows has no executable bit so mark files executable like docker does for build contexts.
+func indexMode(mode os.FileMode) os.FileMode {
+	return (mode &^ os.ModePerm) | (mode.Perm() & 0755) | 0111
+}
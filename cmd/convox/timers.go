@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/convox/rack/cmd/convox/stdcli"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func init() {
+	stdcli.RegisterCommand(cli.Command{
+		Name:        "timers",
+		Description: "list an app's scheduled timers",
+		Usage:       "",
+		Action:      cmdTimers,
+		Flags:       []cli.Flag{appFlag, rackFlag},
+		Subcommands: []cli.Command{
+			{
+				Name:        "runs",
+				Description: "show the run history of a timer",
+				Usage:       "<name>",
+				Action:      cmdTimerRuns,
+				Flags:       []cli.Flag{appFlag, rackFlag},
+			},
+			{
+				Name:        "run",
+				Description: "run a timer now",
+				Usage:       "<name>",
+				Action:      cmdTimerRun,
+				Flags:       []cli.Flag{appFlag, rackFlag},
+			},
+		},
+	})
+}
+
+func cmdTimers(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) > 0 {
+		return stdcli.ExitError(fmt.Errorf("`convox timers` does not take arguments. Perhaps you meant `convox timers run`?"))
+	}
+
+	if c.Bool("help") {
+		stdcli.Usage(c, "")
+		return nil
+	}
+
+	timers, err := rackClient(c).GetTimers(app)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	t := stdcli.NewTable("NAME", "PROCESS", "SCHEDULE", "LAST RUN", "LAST STATUS", "COMMAND")
+
+	for _, timer := range timers {
+		t.AddRow(timer.Name, timer.Process, timer.Schedule, humanizeTime(timer.LastRun), timer.LastStatus, timer.Command)
+	}
+
+	t.Print()
+	return nil
+}
+
+func cmdTimerRuns(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "runs")
+		return nil
+	}
+
+	runs, err := rackClient(c).GetTimerRuns(app, c.Args()[0])
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	t := stdcli.NewTable("ID", "STATUS", "EXIT", "STARTED", "ELAPSED", "TRIGGER")
+
+	for _, r := range runs {
+		exit := ""
+		elapsed := ""
+
+		if !r.Ended.IsZero() {
+			exit = fmt.Sprintf("%d", r.ExitCode)
+			elapsed = stdcli.Duration(r.Started, r.Ended)
+		}
+
+		trigger := "schedule"
+
+		if r.Manual {
+			trigger = "manual"
+		}
+
+		t.AddRow(r.Id, r.Status, exit, humanizeTime(r.Started), elapsed, trigger)
+	}
+
+	t.Print()
+	return nil
+}
+
+func cmdTimerRun(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "run")
+		return nil
+	}
+
+	timer := c.Args()[0]
+
+	fmt.Printf("Running timer %s... ", timer)
+
+	run, err := rackClient(c).RunTimer(app, timer)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Printf("OK, %s\n", run.Id)
+	return nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/test"
+)
+
+func TestTimers(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo/timers", Code: 200, Response: models.Timers{
+			models.Timer{Name: "nightly", Process: "worker", Schedule: "0 3 * * ?", Command: "bin/nightly", LastStatus: "failed"},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox timers --app foo",
+			Exit:    0,
+			Stdout:  "NAME     PROCESS  SCHEDULE   LAST RUN  LAST STATUS  COMMAND\nnightly  worker   0 3 * * ?            failed       bin/nightly\n",
+		},
+	)
+}
+
+func TestTimerRun(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "POST", Path: "/apps/foo/timers/nightly/runs", Code: 200, Response: models.TimerRun{Id: "1234", Timer: "nightly", Status: "running"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox timers run nightly --app foo",
+			Exit:    0,
+			Stdout:  "Running timer nightly... OK, 1234\n",
+		},
+	)
+}
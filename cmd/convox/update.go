@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"net/http"
 	"os/exec"
+	"runtime"
+	"strings"
 
 	"github.com/convox/rack/cmd/convox/stdcli"
 	"github.com/equinox-io/equinox"
@@ -54,6 +56,8 @@ func cmdUpdate(c *cli.Context) error {
 		CurrentVersion: Version,
 		Channel:        "stable",
 		HTTPClient:     client,
+		OS:             runtime.GOOS,
+		Arch:           updateArch(),
 	}
 	if err := opts.SetPublicKeyPEM(publicKey); err != nil {
 		return stdcli.ExitError(err)
@@ -92,6 +96,7 @@ func updateClient() (*http.Client, error) {
 
 	client := &http.Client{
 		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
 			TLSClientConfig: &tls.Config{RootCAs: pool},
 		},
 	}
@@ -99,6 +104,42 @@ func updateClient() (*http.Client, error) {
 	return client, nil
 }
 
+// updateArch returns the architecture of the machine rather than of the running binary
+// so an amd64 build running under emulation updates itself to a native build
+func updateArch() string {
+	switch runtime.GOOS {
+	case "darwin":
+		// rosetta sets this for translated processes
+		if out, err := exec.Command("sysctl", "-n", "sysctl.proc_translated").Output(); err == nil && strings.TrimSpace(string(out)) == "1" {
+			return "arm64"
+		}
+	case "linux":
+		if out, err := exec.Command("uname", "-m").Output(); err == nil {
+			if arch := machineArch(strings.TrimSpace(string(out))); arch != "" {
+				return arch
+			}
+		}
+	}
+
+	return runtime.GOARCH
+}
+
+// machineArch converts a uname machine name to a go architecture
+func machineArch(machine string) string {
+	switch machine {
+	case "aarch64", "arm64":
+		return "arm64"
+	case "armv6l", "armv7l":
+		return "arm"
+	case "i386", "i686":
+		return "386"
+	case "x86_64", "amd64":
+		return "amd64"
+	}
+
+	return ""
+}
+
 func updateProxy() error {
 	cmd := exec.Command("docker", "pull", "convox/proxy")
 	return cmd.Run()
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMachineArch(t *testing.T) {
+	assert.Equal(t, "arm64", machineArch("aarch64"))
+	assert.Equal(t, "arm64", machineArch("arm64"))
+	assert.Equal(t, "arm", machineArch("armv7l"))
+	assert.Equal(t, "386", machineArch("i686"))
+	assert.Equal(t, "amd64", machineArch("x86_64"))
+	assert.Equal(t, "", machineArch("s390x"))
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cheggaaa/pb"
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/models"
+	"gopkg.in/urfave/cli.v1"
+)
+
+const (
+	uploadChunkSize = 5 * 1024 * 1024
+	uploadRetries   = 3
+)
+
+// uploadRetryWait is how long to wait before retrying a chunk that failed to send
+var uploadRetryWait = 2 * time.Second
+
+// uploadState maps a rack and the hash of a file to the upload sending it, so that an
+// interrupted upload is resumed by running the same command again
+type uploadState map[string]string
+
+func uploadStateFile() string {
+	return filepath.Join(ConfigRoot, "uploads")
+}
+
+func loadUploadState() (uploadState, error) {
+	state := uploadState{}
+
+	data, err := ioutil.ReadFile(uploadStateFile())
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+func (s uploadState) save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(ConfigRoot, 0700); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(uploadStateFile(), data, 0600)
+}
+
+// uploadFile sends a file to the rack in chunks and returns the id of the completed upload.
+// Racks that do not support chunked uploads return client.ErrNotFound.
+func uploadFile(c *cli.Context, file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+
+	defer f.Close()
+
+	hash := sha256.New()
+
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+
+	host, err := currentHost()
+	if err != nil {
+		return "", err
+	}
+
+	key := fmt.Sprintf("%s/%s/%s", host, currentRack(c), hex.EncodeToString(hash.Sum(nil)))
+
+	state, err := loadUploadState()
+	if err != nil {
+		return "", err
+	}
+
+	rc := rackClient(c)
+
+	var upload *models.Upload
+
+	if id, ok := state[key]; ok {
+		upload, err = rc.GetUpload(id)
+		if _, ok := err.(client.ErrNotFound); ok {
+			upload = nil
+		} else if err != nil {
+			return "", err
+		}
+	}
+
+	if upload == nil {
+		stat, err := f.Stat()
+		if err != nil {
+			return "", err
+		}
+
+		upload, err = rc.CreateUpload(stat.Size())
+		if err != nil {
+			return "", err
+		}
+
+		state[key] = upload.Id
+
+		if err := state.save(); err != nil {
+			return "", err
+		}
+	} else if upload.Offset > 0 {
+		fmt.Printf("Resuming upload %s at %s\n", upload.Id, pb.Format(upload.Offset).To(pb.U_BYTES))
+	}
+
+	buf := make([]byte, uploadChunkSize)
+	failures := 0
+
+	for upload.Offset < upload.Size {
+		// Pad string with spaces at the end to clear any text left over from a longer string.
+		fmt.Printf("\rUploading... %s / %s       ", pb.Format(upload.Offset).To(pb.U_BYTES), pb.Format(upload.Size).To(pb.U_BYTES))
+
+		n, err := f.ReadAt(buf, upload.Offset)
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+
+		u, err := rc.UploadChunk(upload.Id, upload.Offset, buf[:n])
+		if err != nil {
+			failures++
+
+			if failures > uploadRetries {
+				fmt.Println()
+				return "", fmt.Errorf("%s: run the command again to resume the upload", err)
+			}
+
+			time.Sleep(uploadRetryWait)
+
+			// the chunk may have arrived even though its response did not
+			if u, err := rc.GetUpload(upload.Id); err == nil {
+				upload = u
+			}
+
+			continue
+		}
+
+		failures = 0
+		upload = u
+	}
+
+	fmt.Printf("\rUploading... %s / %s       \n", pb.Format(upload.Size).To(pb.U_BYTES), pb.Format(upload.Size).To(pb.U_BYTES))
+
+	delete(state, key)
+
+	if err := state.save(); err != nil {
+		return "", err
+	}
+
+	return upload.Id, nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/convox/rack/cmd/convox/stdcli"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func init() {
+	stdcli.RegisterCommand(cli.Command{
+		Name:        "users",
+		Description: "manage the keys users authenticate to the rack with",
+		Usage:       "",
+		Action:      cmdUsers,
+		Flags:       []cli.Flag{rackFlag},
+		Subcommands: []cli.Command{
+			{
+				Name:        "create",
+				Description: "create a key for a user, replacing any key they had",
+				Usage:       "<user>",
+				Action:      cmdUsersCreate,
+				Flags:       []cli.Flag{rackFlag},
+			},
+			{
+				Name:        "delete",
+				Description: "delete the key of a user",
+				Usage:       "<user>",
+				Action:      cmdUsersDelete,
+				Flags:       []cli.Flag{rackFlag},
+			},
+		},
+	})
+}
+
+func cmdUsers(c *cli.Context) error {
+	if len(c.Args()) > 0 {
+		return stdcli.ExitError(fmt.Errorf("`convox users` does not take arguments. Perhaps you meant `convox users create`?"))
+	}
+
+	users, err := rackClient(c).GetUsers()
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	t := stdcli.NewTable("USER", "CREATED")
+
+	for _, u := range users {
+		t.AddRow(u.User, humanizeTime(u.Created))
+	}
+
+	t.Print()
+	return nil
+}
+
+func cmdUsersCreate(c *cli.Context) error {
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "create")
+		return nil
+	}
+
+	user := c.Args()[0]
+
+	key, err := rackClient(c).CreateUser(user)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Printf("Key for %s: %s\n", user, key)
+	fmt.Printf("The key is not shown again. %s logs in with `convox login <host> --user %s --password <key>`\n", user, user)
+	return nil
+}
+
+func cmdUsersDelete(c *cli.Context) error {
+	if len(c.Args()) != 1 {
+		stdcli.Usage(c, "delete")
+		return nil
+	}
+
+	user := c.Args()[0]
+
+	fmt.Printf("Deleting key of %s... ", user)
+
+	if err := rackClient(c).DeleteUser(user); err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	fmt.Println("OK")
+	return nil
+}
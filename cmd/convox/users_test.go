@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/test"
+)
+
+func TestUsers(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/users", Code: 200, Response: models.UserKeys{{User: "alice"}, {User: "bob"}}},
+		test.Http{Method: "POST", Path: "/users", Body: "user=alice", Code: 200, Response: map[string]string{"user": "alice", "key": "0123abcd"}},
+		test.Http{Method: "DELETE", Path: "/users/bob", Code: 200, Response: map[string]bool{"success": true}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox users",
+			Exit:    0,
+			Stdout:  "USER   CREATED\nalice\nbob\n",
+		},
+		test.ExecRun{
+			Command: "convox users create alice",
+			Exit:    0,
+			Stdout:  "Key for alice: 0123abcd\nThe key is not shown again. alice logs in with `convox login <host> --user alice --password <key>`\n",
+		},
+		test.ExecRun{
+			Command: "convox users delete bob",
+			Exit:    0,
+			Stdout:  "Deleting key of bob... OK\n",
+		},
+	)
+}
+
+func TestUsersCreateInvalid(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "POST", Path: "/users", Body: "user=convox", Code: 403, Response: client.Error{Error: "invalid user name: convox"}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox users create convox",
+			Exit:    1,
+			Stderr:  "ERROR: invalid user name: convox\n",
+		},
+	)
+}
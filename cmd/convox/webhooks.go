@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/convox/rack/cmd/convox/stdcli"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func cmdAppWebhook(c *cli.Context) error {
+	_, app, err := stdcli.DirApp(c, ".")
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	w, err := rackClient(c).GetAppWebhook(app)
+	if err != nil {
+		return stdcli.ExitError(err)
+	}
+
+	if w.Repo == "" {
+		fmt.Println("Repo    (not set, use `convox apps params set Repo=<url> Branch=<branch>`)")
+	} else {
+		fmt.Printf("Repo    %s\n", w.Repo)
+	}
+
+	fmt.Printf("Branch  %s\n", w.Branch)
+	fmt.Printf("GitHub  %s\n", w.GitHub)
+	fmt.Printf("GitLab  %s\n", w.GitLab)
+	fmt.Printf("Secret  %s\n", w.Secret)
+
+	return nil
+}
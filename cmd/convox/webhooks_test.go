@@ -0,0 +1,41 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/convox/rack/client/models"
+	"github.com/convox/rack/test"
+)
+
+func TestAppWebhook(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/apps/foo/webhook", Code: 200, Response: models.AppWebhook{
+			Repo:   "https://github.com/example/foo.git",
+			Branch: "master",
+			Secret: "ABCDEFGHIJ",
+			GitHub: "https://rack.example.org/webhooks/github",
+			GitLab: "https://rack.example.org/webhooks/gitlab",
+		}},
+		test.Http{Method: "GET", Path: "/apps/bar/webhook", Code: 200, Response: models.AppWebhook{
+			Branch: "master",
+			Secret: "KLMNOPQRST",
+			GitHub: "https://rack.example.org/webhooks/github",
+			GitLab: "https://rack.example.org/webhooks/gitlab",
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox apps webhook --app foo",
+			Exit:    0,
+			Stdout:  "Repo    https://github.com/example/foo.git\nBranch  master\nGitHub  https://rack.example.org/webhooks/github\nGitLab  https://rack.example.org/webhooks/gitlab\nSecret  ABCDEFGHIJ\n",
+		},
+		test.ExecRun{
+			Command: "convox apps webhook --app bar",
+			Exit:    0,
+			Stdout:  "Repo    (not set, use `convox apps params set Repo=<url> Branch=<branch>`)\nBranch  master\nGitHub  https://rack.example.org/webhooks/github\nGitLab  https://rack.example.org/webhooks/gitlab\nSecret  KLMNOPQRST\n",
+		},
+	)
+}
@@ -0,0 +1,118 @@
+// entrypoint is added to every image a rack builds. It replaces environment values of the
+// form ${secret:NAME} with the decrypted secrets of the app and then runs the original
+// entrypoint and command of the image. Secrets are read with the task role of the process
+// so their values only ever exist inside the running container.
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"syscall"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/convox/rack/api/crypt"
+)
+
+// secretReference matches an environment value that refers to a secret
+var secretReference = regexp.MustCompile(`^\$\{secret:([A-Za-z0-9_.-]+)\}$`)
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintf(os.Stderr, "usage: entrypoint <command> [args...]\n")
+		os.Exit(1)
+	}
+
+	env, err := resolveSecrets(os.Environ())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %s\n", err)
+		os.Exit(1)
+	}
+
+	path, err := exec.LookPath(os.Args[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %s\n", err)
+		os.Exit(1)
+	}
+
+	if err := syscall.Exec(path, os.Args[1:], env); err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// resolveSecrets returns env with every secret reference replaced by the value of the secret
+func resolveSecrets(env []string) ([]string, error) {
+	resolved := make([]string, len(env))
+	secrets := map[string]string{}
+
+	for i, kv := range env {
+		resolved[i] = kv
+
+		parts := strings.SplitN(kv, "=", 2)
+
+		if len(parts) != 2 {
+			continue
+		}
+
+		m := secretReference.FindStringSubmatch(parts[1])
+		if m == nil {
+			continue
+		}
+
+		value, ok := secrets[m[1]]
+
+		if !ok {
+			v, err := fetchSecret(m[1])
+			if err != nil {
+				return nil, err
+			}
+
+			value = v
+			secrets[m[1]] = v
+		}
+
+		resolved[i] = fmt.Sprintf("%s=%s", parts[0], value)
+	}
+
+	return resolved, nil
+}
+
+// fetchSecret reads and decrypts a secret from the settings bucket of the app
+func fetchSecret(name string) (string, error) {
+	bucket := os.Getenv("SECRETS_BUCKET")
+
+	if bucket == "" {
+		return "", fmt.Errorf("can not resolve secret %s without SECRETS_BUCKET", name)
+	}
+
+	region := os.Getenv("AWS_REGION")
+
+	res, err := s3.New(session.New(), &aws.Config{Region: aws.String(region)}).GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(fmt.Sprintf("secrets/%s", name)),
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not fetch secret %s: %s", name, err)
+	}
+
+	defer res.Body.Close()
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+
+	// the encrypted data key names the kms key it was made with
+	dec, err := crypt.New(region, "", "").Decrypt("", data)
+	if err != nil {
+		return "", fmt.Errorf("could not decrypt secret %s: %s", name, err)
+	}
+
+	return string(dec), nil
+}
@@ -0,0 +1,94 @@
+package manifest
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// AutoscaleMetric is a metric-based scaling policy for a process, configured with labels like:
+//
+//	convox.autoscale.metric: AWS/SQS ApproximateNumberOfMessagesVisible QueueName=jobs
+//	convox.autoscale.target: 100
+//	convox.autoscale.min: 1
+//	convox.autoscale.max: 10
+type AutoscaleMetric struct {
+	Namespace  string
+	Name       string
+	Dimensions map[string]string
+	Statistic  string
+
+	Target float64
+	Min    int
+	Max    int
+}
+
+// Autoscale returns the metric-based autoscaling policy for the service, or nil if none is configured
+func (s Service) Autoscale() (*AutoscaleMetric, error) {
+	metric, ok := s.Labels["convox.autoscale.metric"]
+	if !ok {
+		return nil, nil
+	}
+
+	parts := strings.Fields(metric)
+
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("convox.autoscale.metric is invalid for %s, must be in the format: <namespace> <metric> [Dimension=value...]", s.Name)
+	}
+
+	am := &AutoscaleMetric{
+		Namespace:  parts[0],
+		Name:       parts[1],
+		Dimensions: map[string]string{},
+		Statistic:  s.LabelDefault("convox.autoscale.statistic", "Average"),
+	}
+
+	for _, d := range parts[2:] {
+		kv := strings.SplitN(d, "=", 2)
+
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("convox.autoscale.metric dimension is invalid for %s: %s", s.Name, d)
+		}
+
+		am.Dimensions[kv[0]] = kv[1]
+	}
+
+	switch am.Statistic {
+	case "Average", "Maximum", "Minimum", "Sum", "SampleCount":
+	default:
+		return nil, fmt.Errorf("convox.autoscale.statistic is invalid for %s: %s", s.Name, am.Statistic)
+	}
+
+	target, err := strconv.ParseFloat(s.Labels["convox.autoscale.target"], 64)
+	if err != nil || target <= 0 {
+		return nil, fmt.Errorf("convox.autoscale.target is invalid for %s, must be a number greater than 0", s.Name)
+	}
+
+	am.Target = target
+
+	if am.Min, err = strconv.Atoi(s.LabelDefault("convox.autoscale.min", "1")); err != nil || am.Min < 0 {
+		return nil, fmt.Errorf("convox.autoscale.min is invalid for %s, must be a number 0 or greater", s.Name)
+	}
+
+	if am.Max, err = strconv.Atoi(s.LabelDefault("convox.autoscale.max", "10")); err != nil || am.Max < am.Min {
+		return nil, fmt.Errorf("convox.autoscale.max is invalid for %s, must be a number no less than convox.autoscale.min", s.Name)
+	}
+
+	return am, nil
+}
+
+// Desired returns the number of processes needed to keep the metric at or below its target per process
+func (am AutoscaleMetric) Desired(value float64) int {
+	desired := int(math.Ceil(value / am.Target))
+
+	if desired < am.Min {
+		desired = am.Min
+	}
+
+	if desired > am.Max {
+		desired = am.Max
+	}
+
+	return desired
+}
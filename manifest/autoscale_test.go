@@ -0,0 +1,79 @@
+package manifest_test
+
+import (
+	"testing"
+
+	"github.com/convox/rack/manifest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoscaleNone(t *testing.T) {
+	s := manifest.Service{Name: "worker"}
+
+	am, err := s.Autoscale()
+
+	assert.Nil(t, err)
+	assert.Nil(t, am)
+}
+
+func TestAutoscaleMetric(t *testing.T) {
+	s := manifest.Service{
+		Name: "worker",
+		Labels: manifest.Labels{
+			"convox.autoscale.metric": "AWS/SQS ApproximateNumberOfMessagesVisible QueueName=jobs",
+			"convox.autoscale.target": "100",
+			"convox.autoscale.max":    "5",
+		},
+	}
+
+	am, err := s.Autoscale()
+
+	if assert.Nil(t, err) && assert.NotNil(t, am) {
+		assert.Equal(t, "AWS/SQS", am.Namespace)
+		assert.Equal(t, "ApproximateNumberOfMessagesVisible", am.Name)
+		assert.Equal(t, map[string]string{"QueueName": "jobs"}, am.Dimensions)
+		assert.Equal(t, "Average", am.Statistic)
+		assert.Equal(t, 100.0, am.Target)
+		assert.Equal(t, 1, am.Min)
+		assert.Equal(t, 5, am.Max)
+
+		assert.Equal(t, 1, am.Desired(0))
+		assert.Equal(t, 1, am.Desired(100))
+		assert.Equal(t, 3, am.Desired(250))
+		assert.Equal(t, 5, am.Desired(10000))
+	}
+}
+
+func TestAutoscaleInvalid(t *testing.T) {
+	tests := map[string]manifest.Labels{
+		"convox.autoscale.metric is invalid for worker, must be in the format: <namespace> <metric> [Dimension=value...]": {
+			"convox.autoscale.metric": "AWS/SQS",
+			"convox.autoscale.target": "100",
+		},
+		"convox.autoscale.metric dimension is invalid for worker: jobs": {
+			"convox.autoscale.metric": "AWS/SQS ApproximateNumberOfMessagesVisible jobs",
+			"convox.autoscale.target": "100",
+		},
+		"convox.autoscale.target is invalid for worker, must be a number greater than 0": {
+			"convox.autoscale.metric": "AWS/SQS ApproximateNumberOfMessagesVisible",
+		},
+		"convox.autoscale.max is invalid for worker, must be a number no less than convox.autoscale.min": {
+			"convox.autoscale.metric": "AWS/SQS ApproximateNumberOfMessagesVisible",
+			"convox.autoscale.target": "100",
+			"convox.autoscale.min":    "4",
+			"convox.autoscale.max":    "2",
+		},
+	}
+
+	for message, labels := range tests {
+		s := manifest.Service{Name: "worker", Labels: labels}
+
+		am, err := s.Autoscale()
+
+		assert.Nil(t, am)
+
+		if assert.NotNil(t, err) {
+			assert.Equal(t, message, err.Error())
+		}
+	}
+}
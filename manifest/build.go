@@ -1,19 +1,65 @@
 package manifest
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 )
 
+// BuildOptions control how the images of a manifest are built
+type BuildOptions struct {
+	// Cache allows docker to reuse layers from earlier builds
+	Cache bool
+
+	// Concurrency is the number of images to build at once, defaulting to one
+	Concurrency int
+
+	// Timings collects how long each docker build step took, if set
+	Timings *BuildTimings
+
+	// Args are passed to the docker build of every service as build args, replacing the build
+	// args of the same name in the manifest
+	Args map[string]string
+
+	// Platform is the platform the images are built and pulled for, i.e. linux/arm64, empty for
+	// the platform of the builder
+	Platform string
+}
+
+// ParseBuildArgs returns the build args in a list of KEY=VALUE pairs
+func ParseBuildArgs(list []string) (map[string]string, error) {
+	args := map[string]string{}
+
+	for _, arg := range list {
+		parts := strings.SplitN(arg, "=", 2)
+
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("build arg must be KEY=VALUE: %s", arg)
+		}
+
+		args[strings.TrimSpace(parts[0])] = parts[1]
+	}
+
+	return args, nil
+}
+
 func (m *Manifest) Build(dir, appName string, s Stream, cache bool) error {
+	return m.BuildWithOptions(dir, appName, s, BuildOptions{Cache: cache})
+}
+
+// BuildWithOptions builds the images of all services, running up to opts.Concurrency
+// docker builds at once. A service whose Dockerfile starts FROM the image of another
+// service in the manifest waits for that image to be built first.
+func (m *Manifest) BuildWithOptions(dir, appName string, s Stream, opts BuildOptions) error {
 	pulls := map[string][]string{}
 	builds := []Service{}
 
 	for _, service := range m.runOrder() {
-		dockerFile := service.Build.Dockerfile
-		if dockerFile == "" {
-			dockerFile = service.Dockerfile
-		}
 		if service.Image != "" {
 			pulls[service.Image] = append(pulls[service.Image], service.Tag(appName))
 		} else {
@@ -21,35 +67,8 @@ func (m *Manifest) Build(dir, appName string, s Stream, cache bool) error {
 		}
 	}
 
-	buildCache := map[string]string{}
-
-	for _, service := range builds {
-		if bc, ok := buildCache[service.Build.Hash()]; ok {
-			if err := DefaultRunner.Run(s, Docker("tag", bc, service.Tag(appName))); err != nil {
-				return fmt.Errorf("build error: %s", err)
-			}
-			continue
-		}
-
-		args := []string{"build"}
-
-		if !cache {
-			args = append(args, "--no-cache")
-		}
-
-		context := coalesce(service.Build.Context, ".")
-		dockerFile := coalesce(service.Dockerfile, "Dockerfile")
-		dockerFile = coalesce(service.Build.Dockerfile, dockerFile)
-
-		args = append(args, "-f", fmt.Sprintf("%s/%s", context, dockerFile))
-		args = append(args, "-t", service.Tag(appName))
-		args = append(args, context)
-
-		if err := DefaultRunner.Run(s, Docker(args...)); err != nil {
-			return fmt.Errorf("build error: %s", err)
-		}
-
-		buildCache[service.Build.Hash()] = service.Tag(appName)
+	if err := buildServices(dir, appName, s, builds, opts); err != nil {
+		return err
 	}
 
 	for image, tags := range pulls {
@@ -60,10 +79,15 @@ func (m *Manifest) Build(dir, appName string, s Stream, cache bool) error {
 			return err
 		}
 
+		if opts.Platform != "" {
+			args = append(args, "--platform", opts.Platform)
+		}
+
 		args = append(args, image)
 
-		if !cache || len(output) == 0 {
-			if err := DefaultRunner.Run(s, Docker("pull", image)); err != nil {
+		// a cached image may be for another platform
+		if !opts.Cache || len(output) == 0 || opts.Platform != "" {
+			if err := DefaultRunner.Run(s, Docker(args...)); err != nil {
 				return fmt.Errorf("build error: %s", err)
 			}
 		}
@@ -77,6 +101,233 @@ func (m *Manifest) Build(dir, appName string, s Stream, cache bool) error {
 	return nil
 }
 
+// buildStep is the build of one service, or a tag of the identical build of an earlier one
+type buildStep struct {
+	service Service
+	source  string
+	deps    []string
+}
+
+type buildResult struct {
+	tag string
+	err error
+}
+
+func buildServices(dir, appName string, s Stream, services []Service, opts BuildOptions) error {
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	tags := map[string]bool{}
+
+	for _, service := range services {
+		tags[service.Tag(appName)] = true
+	}
+
+	pending := []buildStep{}
+	sources := map[string]string{}
+
+	for _, service := range services {
+		step := buildStep{service: service}
+
+		if source, ok := sources[service.Build.Hash()]; ok {
+			step.source = source
+			step.deps = []string{source}
+		} else {
+			sources[service.Build.Hash()] = service.Tag(appName)
+
+			for _, image := range dockerfileImages(dir, service) {
+				if tags[image] && image != service.Tag(appName) {
+					step.deps = append(step.deps, image)
+				}
+			}
+		}
+
+		pending = append(pending, step)
+	}
+
+	built := map[string]bool{}
+	results := make(chan buildResult)
+	running := 0
+
+	var failure error
+
+	for len(pending) > 0 || running > 0 {
+		// start steps in manifest order as their dependencies finish so a concurrency of one is sequential
+		for i := 0; i < len(pending) && running < concurrency && failure == nil; {
+			step := pending[i]
+
+			if !stepReady(step, built) {
+				i++
+				continue
+			}
+
+			pending = append(pending[:i], pending[i+1:]...)
+			running++
+
+			go func(step buildStep) {
+				results <- buildResult{tag: step.service.Tag(appName), err: runBuildStep(s, appName, step, opts)}
+			}(step)
+		}
+
+		if running == 0 {
+			if failure != nil {
+				return failure
+			}
+
+			names := []string{}
+
+			for _, step := range pending {
+				names = append(names, step.service.Name)
+			}
+
+			return fmt.Errorf("build error: circular image dependency between %s", strings.Join(names, ", "))
+		}
+
+		r := <-results
+		running--
+
+		if r.err != nil && failure == nil {
+			failure = r.err
+		}
+
+		built[r.tag] = true
+	}
+
+	return failure
+}
+
+func stepReady(step buildStep, built map[string]bool) bool {
+	for _, dep := range step.deps {
+		if !built[dep] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func runBuildStep(s Stream, appName string, step buildStep, opts BuildOptions) error {
+	service := step.service
+
+	if step.source != "" {
+		if err := DefaultRunner.Run(s, Docker("tag", step.source, service.Tag(appName))); err != nil {
+			return fmt.Errorf("build error: %s", err)
+		}
+
+		return nil
+	}
+
+	args := []string{"build"}
+
+	if !opts.Cache {
+		args = append(args, "--no-cache")
+	}
+
+	if opts.Platform != "" {
+		args = append(args, "--platform", opts.Platform)
+	}
+
+	args = append(args, proxyBuildArgs()...)
+	args = append(args, serviceBuildArgs(service, opts.Args)...)
+	args = append(args, "-f", serviceDockerfile(service))
+	args = append(args, "-t", service.Tag(appName))
+	args = append(args, coalesce(service.Build.Context, "."))
+
+	if opts.Timings != nil {
+		st := &stepTimer{service: service.Name}
+		s = timeSteps(s, st)
+		defer func() { opts.Timings.add(st.finish(time.Now())) }()
+	}
+
+	if err := DefaultRunner.Run(s, Docker(args...)); err != nil {
+		return fmt.Errorf("build error: %s", err)
+	}
+
+	return nil
+}
+
+// proxyEnv are the proxy settings docker passes to builds without an ARG in the Dockerfile
+var proxyEnv = []string{"HTTP_PROXY", "http_proxy", "HTTPS_PROXY", "https_proxy", "NO_PROXY", "no_proxy"}
+
+// proxyBuildArgs lets builds reach the network through the same proxy as the builder
+func proxyBuildArgs() []string {
+	args := []string{}
+
+	for _, name := range proxyEnv {
+		if value := os.Getenv(name); value != "" {
+			args = append(args, "--build-arg", fmt.Sprintf("%s=%s", name, value))
+		}
+	}
+
+	return args
+}
+
+// serviceBuildArgs returns the build args of a service from the manifest and the build, in order
+func serviceBuildArgs(service Service, extra map[string]string) []string {
+	merged := map[string]string{}
+
+	for key, value := range service.Build.Args {
+		merged[key] = value
+	}
+
+	for key, value := range extra {
+		merged[key] = value
+	}
+
+	keys := []string{}
+
+	for key := range merged {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	args := []string{}
+
+	for _, key := range keys {
+		args = append(args, "--build-arg", fmt.Sprintf("%s=%s", key, merged[key]))
+	}
+
+	return args
+}
+
+func serviceDockerfile(service Service) string {
+	context := coalesce(service.Build.Context, ".")
+	dockerFile := coalesce(service.Dockerfile, "Dockerfile")
+	dockerFile = coalesce(service.Build.Dockerfile, dockerFile)
+
+	return fmt.Sprintf("%s/%s", context, dockerFile)
+}
+
+// dockerfileImages returns the images named in the FROM lines of a service's Dockerfile
+func dockerfileImages(dir string, service Service) []string {
+	data, err := ioutil.ReadFile(filepath.Join(dir, serviceDockerfile(service)))
+	if err != nil {
+		return nil
+	}
+
+	images := []string{}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+
+		if len(fields) >= 2 && strings.ToUpper(fields[0]) == "FROM" {
+			image := fields[1]
+
+			// compare without a tag, the services are tagged without one
+			if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+				image = image[:i]
+			}
+
+			images = append(images, image)
+		}
+	}
+
+	return images
+}
+
 func pushSync(s Stream, local, remote string) error {
 	err := run(s, Docker("tag", local, remote))
 	if err != nil {
@@ -99,11 +350,7 @@ func (m *Manifest) Push(stream Stream, app, registry, tag string, flatten string
 
 	for _, s := range m.runOrder() {
 		local := fmt.Sprintf("%s/%s", app, s.Name)
-		remote := fmt.Sprintf("%s/%s-%s:%s", registry, app, s.Name, tag)
-
-		if flatten != "" {
-			remote = fmt.Sprintf("%s/%s:%s", registry, flatten, fmt.Sprintf("%s.%s", s.Name, tag))
-		}
+		remote := remoteTag(s, app, registry, tag, flatten)
 
 		for i := 1; i <= pushRetryLimit; i++ {
 			if err := DefaultRunner.Run(stream, Docker("tag", local, remote)); err != nil {
@@ -122,3 +369,136 @@ func (m *Manifest) Push(stream Stream, app, registry, tag string, flatten string
 
 	return nil
 }
+
+// EntrypointPath is where WrapEntrypoints puts the entrypoint binary in the image of a service
+const EntrypointPath = "/convox/entrypoint"
+
+// WrapEntrypoints adds the binary at path to the image of each service and runs it ahead of
+// the entrypoint and command the image already has, so it can prepare the environment of
+// every process the image runs. Images that are already wrapped are left alone. The binary
+// must be built for platform, empty for the platform of the builder.
+func (m *Manifest) WrapEntrypoints(app, binary, platform string, stream Stream) error {
+	for _, s := range m.runOrder() {
+		image := fmt.Sprintf("%s/%s", app, s.Name)
+
+		data, err := DefaultRunner.CombinedOutput(Docker("inspect", "--format", "{{json .Config}}", image))
+		if err != nil {
+			return fmt.Errorf("could not inspect %s: %s", image, err)
+		}
+
+		var config struct {
+			Entrypoint []string
+			Cmd        []string
+		}
+
+		if err := json.Unmarshal(data, &config); err != nil {
+			return fmt.Errorf("could not inspect %s: %s", image, err)
+		}
+
+		if len(config.Entrypoint) > 0 && config.Entrypoint[0] == EntrypointPath {
+			continue
+		}
+
+		if err := wrapEntrypoint(image, binary, platform, config.Entrypoint, config.Cmd, stream); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// wrapEntrypoint rebuilds image with binary in front of its entrypoint. Setting an entrypoint
+// clears the command of the base image so it is set again.
+func wrapEntrypoint(image, binary, platform string, entrypoint, cmd []string, stream Stream) error {
+	dir, err := ioutil.TempDir("", "entrypoint")
+	if err != nil {
+		return err
+	}
+
+	defer os.RemoveAll(dir)
+
+	bin, err := ioutil.ReadFile(binary)
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "entrypoint"), bin, 0755); err != nil {
+		return err
+	}
+
+	ep, err := json.Marshal(append([]string{EntrypointPath}, entrypoint...))
+	if err != nil {
+		return err
+	}
+
+	dockerfile := fmt.Sprintf("FROM %s\nCOPY entrypoint %s\nENTRYPOINT %s\n", image, EntrypointPath, ep)
+
+	if cmd != nil {
+		data, err := json.Marshal(cmd)
+		if err != nil {
+			return err
+		}
+
+		dockerfile += fmt.Sprintf("CMD %s\n", data)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "Dockerfile"), []byte(dockerfile), 0644); err != nil {
+		return err
+	}
+
+	args := []string{"build", "-t", image}
+
+	if platform != "" {
+		args = append(args, "--platform", platform)
+	}
+
+	if err := DefaultRunner.Run(stream, Docker(append(args, dir)...)); err != nil {
+		return fmt.Errorf("could not add entrypoint to %s: %s", image, err)
+	}
+
+	return nil
+}
+
+// Digests returns the pushed image reference, including its content digest, for each service
+func (m *Manifest) Digests(app, registry, tag string, flatten string) (map[string]string, error) {
+	if tag == "" {
+		tag = "latest"
+	}
+
+	digests := map[string]string{}
+
+	for _, s := range m.runOrder() {
+		remote := remoteTag(s, app, registry, tag, flatten)
+		repo := remote[:strings.LastIndex(remote, ":")]
+
+		data, err := DefaultRunner.CombinedOutput(Docker("inspect", "--format", "{{json .RepoDigests}}", remote))
+		if err != nil {
+			return nil, fmt.Errorf("could not inspect %s: %s", remote, err)
+		}
+
+		var rds []string
+
+		if err := json.Unmarshal(data, &rds); err != nil {
+			return nil, fmt.Errorf("could not inspect %s: %s", remote, err)
+		}
+
+		digests[s.Name] = remote
+
+		for _, rd := range rds {
+			if strings.HasPrefix(rd, repo+"@") {
+				digests[s.Name] = rd
+				break
+			}
+		}
+	}
+
+	return digests, nil
+}
+
+func remoteTag(s Service, app, registry, tag, flatten string) string {
+	if flatten != "" {
+		return fmt.Sprintf("%s/%s:%s", registry, flatten, fmt.Sprintf("%s.%s", s.Name, tag))
+	}
+
+	return fmt.Sprintf("%s/%s-%s:%s", registry, app, s.Name, tag)
+}
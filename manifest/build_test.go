@@ -2,8 +2,11 @@ package manifest_test
 
 import (
 	"fmt"
+	"io/ioutil"
 	"math"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"testing"
 
 	"github.com/convox/rack/manifest"
@@ -100,6 +103,103 @@ func TestBuildWithCache(t *testing.T) {
 	assert.Equal(t, te.Commands[1].Args, cmd2)
 }
 
+func TestWrapEntrypoints(t *testing.T) {
+	output := manifest.NewOutput()
+	str := output.Stream("build")
+	dr := manifest.DefaultRunner
+	te := NewTestExecer()
+	te.CannedResponses = []ExecResponse{
+		ExecResponse{Output: []byte(`{"Entrypoint":["/docker-entrypoint.sh"],"Cmd":["postgres"]}`)},
+		ExecResponse{Output: []byte(`{"Entrypoint":["/convox/entrypoint"],"Cmd":["bin/web"]}`)},
+	}
+
+	manifest.DefaultRunner = te
+	defer func() { manifest.DefaultRunner = dr }()
+
+	binary, err := ioutil.TempFile("", "entrypoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	binary.Close()
+	defer os.Remove(binary.Name())
+
+	m, err := manifestFixture("full-v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = m.WrapEntrypoints("web", binary.Name(), "", str)
+
+	if assert.Nil(t, err) && assert.Equal(t, 1, len(te.Commands)) {
+		assert.Equal(t, []string{"docker", "build", "-t", "web/database"}, te.Commands[0].Args[0:4])
+	}
+}
+
+func TestBuildPlatform(t *testing.T) {
+	output := manifest.NewOutput()
+	str := output.Stream("build")
+	dr := manifest.DefaultRunner
+	te := NewTestExecer()
+	te.CannedResponses = []ExecResponse{
+		ExecResponse{
+			Output: []byte("dockeid"),
+			Error:  nil,
+		},
+	}
+
+	manifest.DefaultRunner = te
+	defer func() { manifest.DefaultRunner = dr }()
+
+	m, err := manifestFixture("full-v1")
+	if err != nil {
+		t.Error(err)
+	}
+
+	// an image in the cache may be for another platform so it is always pulled
+	err = m.BuildWithOptions(".", "web", str, manifest.BuildOptions{Cache: true, Platform: "linux/arm64"})
+	assert.Nil(t, err)
+
+	te.AssertCommands(t, TestCommands{
+		[]string{"docker", "build", "--platform", "linux/arm64", "-f", "./Dockerfile.dev", "-t", "web/web", "."},
+		[]string{"docker", "pull", "--platform", "linux/arm64", "convox/postgres"},
+		[]string{"docker", "tag", "convox/postgres", "web/database"},
+	})
+}
+
+func TestBuildProxy(t *testing.T) {
+	defer os.Setenv("HTTP_PROXY", os.Getenv("HTTP_PROXY"))
+	defer os.Setenv("NO_PROXY", os.Getenv("NO_PROXY"))
+
+	os.Setenv("HTTP_PROXY", "http://proxy.example.com:3128")
+	os.Setenv("NO_PROXY", "169.254.169.254")
+
+	output := manifest.NewOutput()
+	str := output.Stream("build")
+	dr := manifest.DefaultRunner
+	te := NewTestExecer()
+	te.CannedResponses = []ExecResponse{
+		ExecResponse{
+			Output: []byte("dockerid"),
+			Error:  nil,
+		},
+	}
+
+	manifest.DefaultRunner = te
+	defer func() { manifest.DefaultRunner = dr }()
+
+	m, err := manifestFixture("full-v1")
+	if err != nil {
+		t.Error(err)
+	}
+
+	err = m.Build(".", "web", str, true)
+
+	cmd1 := []string{"docker", "build", "--build-arg", "HTTP_PROXY=http://proxy.example.com:3128", "--build-arg", "NO_PROXY=169.254.169.254", "-f", "./Dockerfile.dev", "-t", "web/web", "."}
+
+	assert.Equal(t, len(te.Commands), 2)
+	assert.Equal(t, te.Commands[0].Args, cmd1)
+}
+
 func TestBuildCacheNoImage(t *testing.T) {
 	output := manifest.NewOutput()
 	str := output.Stream("build")
@@ -238,10 +338,10 @@ func TestBuildRepeatComplex(t *testing.T) {
 
 	te.AssertCommands(t, TestCommands{
 		[]string{"docker", "build", "--no-cache", "-f", "./Dockerfile", "-t", "web/first", "."},
-		[]string{"docker", "build", "--no-cache", "-f", "./Dockerfile", "-t", "web/monitor", "."},
-		[]string{"docker", "build", "--no-cache", "-f", "./other/Dockerfile", "-t", "web/othera", "./other"},
-		[]string{"docker", "build", "--no-cache", "-f", "./Dockerfile.other", "-t", "web/otherb", "."},
-		[]string{"docker", "build", "--no-cache", "-f", "./Dockerfile", "-t", "web/otherc", "."},
+		[]string{"docker", "build", "--no-cache", "--build-arg", "foo=bar", "-f", "./Dockerfile", "-t", "web/monitor", "."},
+		[]string{"docker", "build", "--no-cache", "--build-arg", "foo=bar", "-f", "./other/Dockerfile", "-t", "web/othera", "./other"},
+		[]string{"docker", "build", "--no-cache", "--build-arg", "foo=bar", "-f", "./Dockerfile.other", "-t", "web/otherb", "."},
+		[]string{"docker", "build", "--no-cache", "--build-arg", "foo=other", "-f", "./Dockerfile", "-t", "web/otherc", "."},
 		[]string{"docker", "build", "--no-cache", "-f", "./Dockerfile", "-t", "web/otherd", "."},
 		[]string{"docker", "tag", "web/first", "web/othere"},
 		[]string{"docker", "build", "--no-cache", "-f", "./Dockerfile.otherf", "-t", "web/otherf", "."},
@@ -250,6 +350,70 @@ func TestBuildRepeatComplex(t *testing.T) {
 	})
 }
 
+func TestBuildArgs(t *testing.T) {
+	output := manifest.NewOutput()
+	str := output.Stream("build")
+	dr := manifest.DefaultRunner
+	te := NewTestExecer()
+	manifest.DefaultRunner = te
+	defer func() { manifest.DefaultRunner = dr }()
+
+	m, err := manifestFixture("build-args")
+	if err != nil {
+		t.Error(err)
+	}
+
+	args, err := manifest.ParseBuildArgs([]string{"VERSION=2.4", "TOKEN=a=b"})
+	assert.Nil(t, err)
+
+	err = m.BuildWithOptions(".", "web", str, manifest.BuildOptions{Args: args})
+	assert.Nil(t, err)
+
+	te.AssertCommands(t, TestCommands{
+		[]string{"docker", "build", "--no-cache", "--build-arg", "RUBY=2.3", "--build-arg", "TOKEN=a=b", "--build-arg", "VERSION=2.4", "-f", "./Dockerfile", "-t", "web/web", "."},
+		[]string{"docker", "build", "--no-cache", "--build-arg", "TOKEN=a=b", "--build-arg", "VERSION=2.4", "--build-arg", "WORKERS=4", "-f", "./Dockerfile.worker", "-t", "web/worker", "."},
+	})
+
+	_, err = manifest.ParseBuildArgs([]string{"VERSION"})
+	assert.EqualError(t, err, "build arg must be KEY=VALUE: VERSION")
+}
+
+func TestBuildDependencyOrder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "manifest")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"docker-compose.yml": "api:\n  build:\n    context: .\n    dockerfile: Dockerfile.api\nworker:\n  build: .\nzbase:\n  build:\n    context: .\n    dockerfile: Dockerfile.base\n",
+		"Dockerfile":         "FROM web/zbase:latest\n",
+		"Dockerfile.api":     "FROM web/zbase\nRUN make\n",
+		"Dockerfile.base":    "FROM ubuntu:16.04\n",
+	}
+
+	for name, data := range files {
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0644))
+	}
+
+	output := manifest.NewOutput()
+	str := output.Stream("build")
+	dr := manifest.DefaultRunner
+	te := NewTestExecer()
+	manifest.DefaultRunner = te
+	defer func() { manifest.DefaultRunner = dr }()
+
+	m, err := manifest.LoadFile(filepath.Join(dir, "docker-compose.yml"))
+	assert.Nil(t, err)
+
+	err = m.BuildWithOptions(dir, "web", str, manifest.BuildOptions{Concurrency: 1})
+	assert.Nil(t, err)
+
+	te.AssertCommands(t, TestCommands{
+		[]string{"docker", "build", "--no-cache", "-f", "./Dockerfile.base", "-t", "web/zbase", "."},
+		[]string{"docker", "build", "--no-cache", "-f", "./Dockerfile.api", "-t", "web/api", "."},
+		[]string{"docker", "build", "--no-cache", "-f", "./Dockerfile", "-t", "web/worker", "."},
+	})
+}
+
 func TestDoubleDockerfile(t *testing.T) {
 	m, err := manifestFixture("double-dockerfile")
 
@@ -282,3 +446,163 @@ func TestPush(t *testing.T) {
 	assert.Equal(t, te.Commands[2].Args, cmd3)
 	assert.Equal(t, te.Commands[3].Args, cmd4)
 }
+
+func TestDigests(t *testing.T) {
+	dr := manifest.DefaultRunner
+	te := NewTestExecer()
+	te.CannedResponses = []ExecResponse{
+		ExecResponse{Output: []byte(`["registry/flatten@sha256:1111"]`)},
+		ExecResponse{Output: []byte(`["other/web@sha256:3333","registry/flatten@sha256:2222"]`)},
+	}
+	manifest.DefaultRunner = te
+	defer func() { manifest.DefaultRunner = dr }()
+
+	m, err := manifestFixture("full-v1")
+	if err != nil {
+		t.Error(err)
+	}
+
+	digests, err := m.Digests("app", "registry", "tag", "flatten")
+
+	if assert.Nil(t, err) {
+		assert.Equal(t, map[string]string{
+			"database": "registry/flatten@sha256:1111",
+			"web":      "registry/flatten@sha256:2222",
+		}, digests)
+	}
+}
+
+func TestBuildHooks(t *testing.T) {
+	output := manifest.NewOutput()
+	str := output.Stream("build")
+	dr := manifest.DefaultRunner
+	te := NewTestExecer()
+
+	manifest.DefaultRunner = te
+	defer func() { manifest.DefaultRunner = dr }()
+
+	m, err := manifestFixture("build-hooks")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Nil(t, m.RunBuildHook("src", "pre", str))
+	assert.Nil(t, m.RunBuildHook("src", "post", str))
+
+	te.AssertCommands(t, TestCommands{
+		[]string{"sh", "-c", "./scripts/generate.sh"},
+		[]string{"sh", "-c", "./scripts/smoke-test.sh"},
+	})
+
+	assert.Equal(t, "src", te.Commands[0].Dir)
+
+	assert.EqualError(t, m.RunBuildHook("src", "during", str), "unknown build hook: during")
+
+	m, err = manifestFixture("full-v2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Nil(t, m.RunBuildHook("src", "pre", str))
+	assert.Equal(t, 2, len(te.Commands))
+}
+
+func TestBuildTests(t *testing.T) {
+	output := manifest.NewOutput()
+	str := output.Stream("build")
+	dr := manifest.DefaultRunner
+	te := NewTestExecer()
+
+	manifest.DefaultRunner = te
+	defer func() { manifest.DefaultRunner = dr }()
+
+	m, err := manifestFixture("build-hooks")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Nil(t, m.RunBuildTests("app", str))
+
+	te.AssertCommands(t, TestCommands{
+		[]string{"docker", "run", "--rm", "app/web", "sh", "-c", "bin/smoke-test --port 3000"},
+		[]string{"docker", "run", "--rm", "app/worker", "sh", "-c", "bin/check"},
+	})
+
+	m.BuildConfig.Hooks.Test["admin"] = "bin/check"
+
+	assert.EqualError(t, m.RunBuildTests("app", str), "build test for unknown service: admin")
+
+	m, err = manifestFixture("full-v2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Nil(t, m.RunBuildTests("app", str))
+	assert.Equal(t, 2, len(te.Commands))
+}
+
+// outputExecer prints docker build output for every build it runs
+type outputExecer struct {
+	*TestExecer
+	Output []string
+}
+
+func (p *outputExecer) Run(s manifest.Stream, cmd *exec.Cmd) error {
+	p.TestExecer.Run(s, cmd)
+
+	if cmd.Args[1] == "build" {
+		for _, line := range p.Output {
+			s <- line
+		}
+	}
+
+	return nil
+}
+
+func TestBuildTimings(t *testing.T) {
+	output := manifest.NewOutput()
+	str := output.Stream("build")
+	dr := manifest.DefaultRunner
+	te := &outputExecer{
+		TestExecer: NewTestExecer(),
+		Output: []string{
+			"Sending build context to Docker daemon 2.048 kB",
+			"Step 1/3 : FROM ruby:2.3",
+			" ---> 1f2a3b4c5d6e",
+			"Step 2/3 : COPY Gemfile /app/Gemfile",
+			" ---> Using cache",
+			" ---> 2a3b4c5d6e7f",
+			"Step 3/3 : RUN bundle install",
+			" ---> Running in 3b4c5d6e7f8a",
+			" ---> 4c5d6e7f8a9b",
+			"Successfully built 4c5d6e7f8a9b",
+		},
+	}
+	manifest.DefaultRunner = te
+	defer func() { manifest.DefaultRunner = dr }()
+
+	m, err := manifestFixture("repeat-simple")
+	if err != nil {
+		t.Error(err)
+	}
+
+	timings := &manifest.BuildTimings{}
+
+	err = m.BuildWithOptions(".", "web", str, manifest.BuildOptions{Cache: true, Timings: timings})
+	assert.Nil(t, err)
+
+	steps := []string{}
+
+	for _, s := range timings.Steps {
+		steps = append(steps, fmt.Sprintf("%s %s %t", s.Service, s.Step, s.Cached))
+	}
+
+	assert.Equal(t, []string{
+		"monitor FROM ruby:2.3 false",
+		"monitor COPY Gemfile /app/Gemfile true",
+		"monitor RUN bundle install false",
+		"other FROM ruby:2.3 false",
+		"other COPY Gemfile /app/Gemfile true",
+		"other RUN bundle install false",
+	}, steps)
+}
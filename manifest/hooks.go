@@ -0,0 +1,83 @@
+package manifest
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+)
+
+// BuildConfig is the top level build section of a version 2 manifest
+type BuildConfig struct {
+	Hooks BuildHooks `yaml:"hooks,omitempty"`
+}
+
+// BuildHooks are shell commands the build service runs in the app source,
+// i.e. to generate code or compile assets before the images are built.
+// Test commands run in a container of the image built for their service,
+// i.e. to smoke test it before it is pushed.
+type BuildHooks struct {
+	Pre  string            `yaml:"pre,omitempty"`
+	Post string            `yaml:"post,omitempty"`
+	Test map[string]string `yaml:"test,omitempty"`
+}
+
+// RunBuildHook runs the "pre" or "post" build hook in dir and writes its output to s.
+// Manifests without the hook do nothing.
+func (m *Manifest) RunBuildHook(dir, hook string, s Stream) error {
+	if m.BuildConfig == nil {
+		return nil
+	}
+
+	var command string
+
+	switch hook {
+	case "pre":
+		command = m.BuildConfig.Hooks.Pre
+	case "post":
+		command = m.BuildConfig.Hooks.Post
+	default:
+		return fmt.Errorf("unknown build hook: %s", hook)
+	}
+
+	if command == "" {
+		return nil
+	}
+
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Dir = dir
+
+	if err := DefaultRunner.Run(s, cmd); err != nil {
+		return fmt.Errorf("%s-build hook error: %s", hook, err)
+	}
+
+	return nil
+}
+
+// RunBuildTests runs the test command of each service in a container of its built image and
+// writes their output to s. A command that fails fails the build.
+func (m *Manifest) RunBuildTests(appName string, s Stream) error {
+	if m.BuildConfig == nil {
+		return nil
+	}
+
+	names := []string{}
+
+	for name := range m.BuildConfig.Hooks.Test {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		service, ok := m.Services[name]
+		if !ok {
+			return fmt.Errorf("build test for unknown service: %s", name)
+		}
+
+		if err := DefaultRunner.Run(s, Docker("run", "--rm", service.Tag(appName), "sh", "-c", m.BuildConfig.Hooks.Test[name])); err != nil {
+			return fmt.Errorf("build test of %s failed: %s", name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,211 @@
+package manifest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LintRules are the Dockerfile checks run before every build
+var LintRules = map[string]string{
+	"unpinned-base": "base image should be pinned to a tag other than latest",
+	"apt-cache":     "apt-get install should be followed by rm -rf /var/lib/apt/lists/* in the same RUN",
+	"env-secret":    "secrets set with ENV are baked into the image, set them with `convox env set` instead",
+}
+
+// LintWarning is a Dockerfile line that breaks a lint rule
+type LintWarning struct {
+	Service string
+	File    string
+	Line    int
+	Rule    string
+}
+
+func (w LintWarning) String() string {
+	return fmt.Sprintf("%s %s:%d %s: %s", w.Service, w.File, w.Line, w.Rule, LintRules[w.Rule])
+}
+
+// ParseLintRules returns the rules named in a comma separated list, where all selects every rule
+func ParseLintRules(list string) (map[string]bool, error) {
+	rules := map[string]bool{}
+
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+
+		switch {
+		case name == "":
+		case name == "all":
+			for rule := range LintRules {
+				rules[rule] = true
+			}
+		case LintRules[name] != "":
+			rules[name] = true
+		default:
+			return nil, fmt.Errorf("unknown lint rule: %s", name)
+		}
+	}
+
+	return rules, nil
+}
+
+// Lint checks the Dockerfiles of the services the manifest builds
+func (m *Manifest) Lint(dir, appName string) ([]LintWarning, error) {
+	// services can be built FROM the images of other services which are never pinned
+	local := map[string]bool{}
+
+	for _, s := range m.Services {
+		local[s.Tag(appName)] = true
+	}
+
+	names := []string{}
+
+	for name, s := range m.Services {
+		if s.Image == "" {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	warnings := []LintWarning{}
+
+	for _, name := range names {
+		s := m.Services[name]
+		file := serviceDockerfile(s)
+
+		data, err := ioutil.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, w := range LintDockerfile(string(data), local) {
+			w.Service = name
+			w.File = strings.TrimPrefix(file, "./")
+			warnings = append(warnings, w)
+		}
+	}
+
+	return warnings, nil
+}
+
+// LintDockerfile returns the lines of a Dockerfile that break a lint rule. Images in local
+// are built by the manifest and are not expected to be pinned.
+func LintDockerfile(data string, local map[string]bool) []LintWarning {
+	warnings := []LintWarning{}
+
+	for _, in := range dockerfileInstructions(data) {
+		fields := strings.Fields(in.text)
+
+		if len(fields) < 2 {
+			continue
+		}
+
+		switch strings.ToUpper(fields[0]) {
+		case "FROM":
+			if unpinnedImage(fields[1], local) {
+				warnings = append(warnings, LintWarning{Line: in.line, Rule: "unpinned-base"})
+			}
+		case "RUN":
+			if strings.Contains(in.text, "apt-get install") && !strings.Contains(in.text, "/var/lib/apt/lists") {
+				warnings = append(warnings, LintWarning{Line: in.line, Rule: "apt-cache"})
+			}
+		case "ENV":
+			for _, key := range envKeys(fields[1:]) {
+				if secretName(key) {
+					warnings = append(warnings, LintWarning{Line: in.line, Rule: "env-secret"})
+					break
+				}
+			}
+		}
+	}
+
+	return warnings
+}
+
+type dockerfileInstruction struct {
+	line int
+	text string
+}
+
+// dockerfileInstructions joins continued lines and drops comments
+func dockerfileInstructions(data string) []dockerfileInstruction {
+	instructions := []dockerfileInstruction{}
+
+	var current *dockerfileInstruction
+
+	for i, line := range strings.Split(data, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if current == nil {
+			if trimmed == "" {
+				continue
+			}
+
+			current = &dockerfileInstruction{line: i + 1}
+		}
+
+		if strings.HasSuffix(trimmed, "\\") {
+			current.text += strings.TrimSuffix(trimmed, "\\") + " "
+			continue
+		}
+
+		current.text += trimmed
+		instructions = append(instructions, *current)
+		current = nil
+	}
+
+	if current != nil {
+		instructions = append(instructions, *current)
+	}
+
+	return instructions
+}
+
+func unpinnedImage(image string, local map[string]bool) bool {
+	if image == "scratch" || local[image] || strings.Contains(image, "@") {
+		return false
+	}
+
+	i := strings.LastIndex(image, ":")
+
+	if i <= strings.LastIndex(image, "/") {
+		return true
+	}
+
+	return image[i+1:] == "latest"
+}
+
+// envKeys returns the names set by the arguments of an ENV instruction in either form
+func envKeys(args []string) []string {
+	if !strings.Contains(args[0], "=") {
+		return []string{args[0]}
+	}
+
+	keys := []string{}
+
+	for _, arg := range args {
+		if i := strings.Index(arg, "="); i > 0 {
+			keys = append(keys, arg[:i])
+		}
+	}
+
+	return keys
+}
+
+func secretName(key string) bool {
+	key = strings.ToUpper(key)
+
+	for _, s := range []string{"PASSWORD", "SECRET", "TOKEN"} {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+
+	return key == "KEY" || strings.HasSuffix(key, "_KEY")
+}
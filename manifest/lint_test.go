@@ -0,0 +1,87 @@
+package manifest_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/convox/rack/manifest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintDockerfile(t *testing.T) {
+	dockerfile := `# build the web image
+FROM ubuntu
+RUN apt-get update && \
+    apt-get install -y curl
+RUN apt-get update && apt-get install -y git && rm -rf /var/lib/apt/lists/*
+ENV PORT=3000 AWS_SECRET_ACCESS_KEY=abc
+ENV DATABASE_PASSWORD hunter2
+ENV PATH /app/bin:$PATH
+FROM golang:1.7
+FROM alpine:latest
+FROM myapp/web
+`
+
+	warnings := manifest.LintDockerfile(dockerfile, map[string]bool{"myapp/web": true})
+
+	found := []string{}
+
+	for _, w := range warnings {
+		found = append(found, w.String())
+	}
+
+	assert.Equal(t, []string{
+		" :2 unpinned-base: base image should be pinned to a tag other than latest",
+		" :3 apt-cache: apt-get install should be followed by rm -rf /var/lib/apt/lists/* in the same RUN",
+		" :6 env-secret: secrets set with ENV are baked into the image, set them with `convox env set` instead",
+		" :7 env-secret: secrets set with ENV are baked into the image, set them with `convox env set` instead",
+		" :10 unpinned-base: base image should be pinned to a tag other than latest",
+	}, found)
+}
+
+func TestManifestLint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lint")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "worker"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM ruby:2.3\n"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "worker", "Dockerfile.worker"), []byte("FROM myapp/web\nENV API_TOKEN=abc\n"), 0644))
+
+	m, err := manifest.Load([]byte(`version: "2"
+services:
+  web:
+    build: .
+  worker:
+    build:
+      context: worker
+      dockerfile: Dockerfile.worker
+  redis:
+    image: redis
+`))
+	require.NoError(t, err)
+
+	warnings, err := m.Lint(dir, "myapp")
+	require.NoError(t, err)
+
+	if assert.Len(t, warnings, 1) {
+		assert.Equal(t, "worker worker/Dockerfile.worker:2 env-secret: secrets set with ENV are baked into the image, set them with `convox env set` instead", warnings[0].String())
+	}
+}
+
+func TestParseLintRules(t *testing.T) {
+	rules, err := manifest.ParseLintRules("apt-cache, env-secret")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"apt-cache": true, "env-secret": true}, rules)
+
+	rules, err = manifest.ParseLintRules("all")
+	require.NoError(t, err)
+	assert.Len(t, rules, 3)
+
+	_, err = manifest.ParseLintRules("apt-cache,latest")
+	assert.EqualError(t, err, "unknown lint rule: latest")
+}
@@ -23,6 +23,8 @@ type Manifest struct {
 	Version  string             `yaml:"version"`
 	Networks Networks           `yaml:"networks,omitempty"`
 	Services map[string]Service `yaml:"services"`
+
+	BuildConfig *BuildConfig `yaml:"build,omitempty"`
 }
 
 // Load a Manifest from raw data
@@ -132,6 +134,10 @@ func (m Manifest) Validate() error {
 			}
 		}
 
+		if _, err := entry.Autoscale(); err != nil {
+			return err
+		}
+
 		for _, l := range entry.Links {
 			ls, ok := m.Services[l]
 			if !ok {
@@ -0,0 +1,101 @@
+package manifest
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OverridePrefix starts the labels that change a service on a single rack, such as
+// convox.override.production.command or convox.override.production.convox.health.path
+const OverridePrefix = "convox.override."
+
+// Override is a change to a service that only applies when it is promoted on one rack
+type Override struct {
+	Service string
+	Key     string
+	Value   string
+}
+
+func (o Override) String() string {
+	return fmt.Sprintf("%s %s=%s", o.Service, o.Key, o.Value)
+}
+
+// Overrides are sorted by service and key
+type Overrides []Override
+
+func (ovs Overrides) Len() int      { return len(ovs) }
+func (ovs Overrides) Swap(i, j int) { ovs[i], ovs[j] = ovs[j], ovs[i] }
+
+func (ovs Overrides) Less(i, j int) bool {
+	if ovs[i].Service != ovs[j].Service {
+		return ovs[i].Service < ovs[j].Service
+	}
+
+	return ovs[i].Key < ovs[j].Key
+}
+
+// ApplyOverrides changes the services of a manifest with their overrides for environment and
+// returns the overrides it applied. A command override replaces the command of the service and
+// any key other than scale.count, scale.cpu and scale.memory sets the label of the same name.
+// Scale overrides are left to the caller as they are not part of the manifest. The override
+// labels of every environment are removed so that a change to one rack's overrides does not
+// change the services of another.
+func (m *Manifest) ApplyOverrides(environment string) (Overrides, error) {
+	overrides := Overrides{}
+
+	for name, s := range m.Services {
+		labels := Labels{}
+
+		for label, value := range s.Labels {
+			if !strings.HasPrefix(label, OverridePrefix) {
+				labels[label] = value
+				continue
+			}
+
+			parts := strings.SplitN(strings.TrimPrefix(label, OverridePrefix), ".", 2)
+
+			if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				return nil, fmt.Errorf("invalid override label for %s: %s", name, label)
+			}
+
+			if parts[0] != environment {
+				continue
+			}
+
+			overrides = append(overrides, Override{Service: name, Key: parts[1], Value: value})
+		}
+
+		for _, o := range overrides {
+			if o.Service != name {
+				continue
+			}
+
+			switch o.Key {
+			case "command":
+				s.Command = Command{String: o.Value}
+			case "scale.count", "scale.cpu", "scale.memory":
+				if _, err := strconv.Atoi(o.Value); err != nil {
+					return nil, fmt.Errorf("%s override for %s must be numeric: %s", o.Key, name, o.Value)
+				}
+			default:
+				if strings.HasPrefix(o.Key, "scale.") {
+					return nil, fmt.Errorf("unknown scale override for %s: %s", name, o.Key)
+				}
+
+				labels[o.Key] = o.Value
+			}
+		}
+
+		if s.Labels != nil {
+			s.Labels = labels
+		}
+
+		m.Services[name] = s
+	}
+
+	sort.Sort(overrides)
+
+	return overrides, nil
+}
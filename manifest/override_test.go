@@ -0,0 +1,63 @@
+package manifest_test
+
+import (
+	"testing"
+
+	"github.com/convox/rack/manifest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyOverrides(t *testing.T) {
+	m, err := manifestFixture("overrides")
+
+	if assert.Nil(t, err) {
+		overrides, err := m.ApplyOverrides("production")
+
+		if assert.Nil(t, err) {
+			assert.Equal(t, manifest.Overrides{
+				{Service: "web", Key: "command", Value: "bin/web --workers 8"},
+				{Service: "web", Key: "convox.health.path", Value: "/health"},
+				{Service: "web", Key: "scale.count", Value: "4"},
+			}, overrides)
+
+			assert.Equal(t, "web command=bin/web --workers 8", overrides[0].String())
+
+			assert.Equal(t, "bin/web --workers 8", m.Services["web"].Command.String)
+			assert.Equal(t, manifest.Labels{"convox.health.path": "/health"}, m.Services["web"].Labels)
+			assert.Equal(t, "bin/work", m.Services["worker"].Command.String)
+		}
+	}
+}
+
+func TestApplyOverridesOtherEnvironment(t *testing.T) {
+	m, err := manifestFixture("overrides")
+
+	if assert.Nil(t, err) {
+		overrides, err := m.ApplyOverrides("development")
+
+		if assert.Nil(t, err) {
+			assert.Equal(t, manifest.Overrides{}, overrides)
+			assert.Equal(t, "bin/web", m.Services["web"].Command.String)
+			assert.Equal(t, manifest.Labels{"convox.health.path": "/check"}, m.Services["web"].Labels)
+		}
+	}
+}
+
+func TestApplyOverridesInvalid(t *testing.T) {
+	m := manifest.Manifest{Services: map[string]manifest.Service{
+		"web": {Labels: manifest.Labels{"convox.override.production.scale.count": "many"}},
+	}}
+
+	_, err := m.ApplyOverrides("production")
+	assert.EqualError(t, err, "scale.count override for web must be numeric: many")
+
+	m.Services["web"] = manifest.Service{Labels: manifest.Labels{"convox.override.production.scale.size": "2"}}
+
+	_, err = m.ApplyOverrides("production")
+	assert.EqualError(t, err, "unknown scale override for web: scale.size")
+
+	m.Services["web"] = manifest.Service{Labels: manifest.Labels{"convox.override.production": "bin/web"}}
+
+	_, err = m.ApplyOverrides("production")
+	assert.EqualError(t, err, "invalid override label for web: convox.override.production")
+}
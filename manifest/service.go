@@ -41,6 +41,14 @@ type Service struct {
 
 	Primary bool `yaml:"-"`
 
+	// Release keeps the process on a release other than the one being promoted and
+	// ReleaseEnvironment is the url of the environment of that release
+	Release            string `yaml:"-"`
+	ReleaseEnvironment string `yaml:"-"`
+
+	// Architecture places the process on instances of a cpu architecture, empty for any
+	Architecture string `yaml:"-"`
+
 	randoms map[string]int
 }
 
@@ -214,6 +222,37 @@ func (s Service) DeploymentMaximum() string {
 	return s.LabelDefault("convox.deployment.maximum", "200")
 }
 
+// CrashCapture returns true if the output of containers of the service that crash should be kept
+func (s Service) CrashCapture() bool {
+	return s.Labels["convox.crash.capture"] == "true" || s.CrashDump() != ""
+}
+
+// CrashDump returns the path of a core dump or heap snapshot in the container to keep along with a crash
+func (s Service) CrashDump() string {
+	return s.Labels["convox.crash.dump"]
+}
+
+// ProfilePort returns the port in the containers of the service that serves profiles
+func (s Service) ProfilePort() string {
+	return s.Labels["convox.profile.port"]
+}
+
+// ProfileFormat returns the format of the profiles the service serves, pprof for Go or jfr for the JVM
+func (s Service) ProfileFormat() string {
+	return s.LabelDefault("convox.profile.format", "pprof")
+}
+
+// ProfilePath returns the path the service serves profiles under
+func (s Service) ProfilePath() string {
+	def := "/debug/pprof"
+
+	if s.ProfileFormat() == "jfr" {
+		def = "/jfr"
+	}
+
+	return s.LabelDefault("convox.profile.path", def)
+}
+
 // NetworkName returns custom network name from the networks, defined in compose file.
 // REturns empty string, if no custom network is defined.
 // We pick the last one, as we currently support only single one.
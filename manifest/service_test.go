@@ -66,3 +66,30 @@ func TestDefaultNetworkName(t *testing.T) {
 
 	assert.Equal(t, s.NetworkName(), "")
 }
+
+func TestCrashCapture(t *testing.T) {
+	s := manifest.Service{Labels: manifest.Labels{}}
+	assert.False(t, s.CrashCapture())
+	assert.Equal(t, "", s.CrashDump())
+
+	s = manifest.Service{Labels: manifest.Labels{"convox.crash.capture": "true"}}
+	assert.True(t, s.CrashCapture())
+
+	s = manifest.Service{Labels: manifest.Labels{"convox.crash.dump": "/tmp/core"}}
+	assert.True(t, s.CrashCapture())
+	assert.Equal(t, "/tmp/core", s.CrashDump())
+}
+
+func TestProfile(t *testing.T) {
+	s := manifest.Service{Labels: manifest.Labels{}}
+	assert.Equal(t, "", s.ProfilePort())
+	assert.Equal(t, "pprof", s.ProfileFormat())
+	assert.Equal(t, "/debug/pprof", s.ProfilePath())
+
+	s = manifest.Service{Labels: manifest.Labels{"convox.profile.port": "9999", "convox.profile.format": "jfr"}}
+	assert.Equal(t, "9999", s.ProfilePort())
+	assert.Equal(t, "/jfr", s.ProfilePath())
+
+	s = manifest.Service{Labels: manifest.Labels{"convox.profile.port": "6060", "convox.profile.path": "/pprof"}}
+	assert.Equal(t, "/pprof", s.ProfilePath())
+}
@@ -0,0 +1,94 @@
+package manifest
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// dockerStep matches the line docker prints as it starts each step of a build
+var dockerStep = regexp.MustCompile(`^Step \d+(/\d+)? : (.*)$`)
+
+// BuildStepTiming is how long one step of the docker build of a service took
+type BuildStepTiming struct {
+	Service  string
+	Step     string
+	Cached   bool
+	Duration time.Duration
+}
+
+// BuildTimings collects the steps of the docker builds of a manifest as they finish
+type BuildTimings struct {
+	Steps []BuildStepTiming
+
+	lock sync.Mutex
+}
+
+func (bt *BuildTimings) add(steps []BuildStepTiming) {
+	bt.lock.Lock()
+	defer bt.lock.Unlock()
+
+	bt.Steps = append(bt.Steps, steps...)
+}
+
+// stepTimer follows the output of a docker build, timing each step from its start
+// to the start of the next one
+type stepTimer struct {
+	service string
+	steps   []BuildStepTiming
+	started time.Time
+	done    bool
+	lock    sync.Mutex
+}
+
+// timeSteps returns a stream that forwards the output of a docker build to s through st
+func timeSteps(s Stream, st *stepTimer) Stream {
+	out := make(Stream)
+
+	go func() {
+		for line := range out {
+			st.line(line, time.Now())
+			s <- line
+		}
+	}()
+
+	return out
+}
+
+func (st *stepTimer) line(text string, now time.Time) {
+	st.lock.Lock()
+	defer st.lock.Unlock()
+
+	if st.done {
+		return
+	}
+
+	if m := dockerStep.FindStringSubmatch(strings.TrimSpace(text)); m != nil {
+		st.end(now)
+		st.steps = append(st.steps, BuildStepTiming{Service: st.service, Step: m[2]})
+		st.started = now
+		return
+	}
+
+	if len(st.steps) > 0 && strings.Contains(text, "---> Using cache") {
+		st.steps[len(st.steps)-1].Cached = true
+	}
+}
+
+// finish times the last step and returns every step seen
+func (st *stepTimer) finish(now time.Time) []BuildStepTiming {
+	st.lock.Lock()
+	defer st.lock.Unlock()
+
+	st.end(now)
+	st.done = true
+
+	return st.steps
+}
+
+func (st *stepTimer) end(now time.Time) {
+	if len(st.steps) > 0 {
+		st.steps[len(st.steps)-1].Duration = now.Sub(st.started)
+	}
+}
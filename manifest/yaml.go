@@ -2,6 +2,7 @@ package manifest
 
 import (
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 )
@@ -53,12 +54,28 @@ func (b *Build) UnmarshalYAML(unmarshal func(interface{}) error) error {
 				b.Dockerfile = mapValue.(string)
 			case "args":
 				args := map[string]string{}
-				for key, value := range mapValue.(map[interface{}]interface{}) {
-					if ks, ok := key.(string); ok {
-						if vs, ok := value.(string); ok {
-							args[ks] = vs
+				switch t := mapValue.(type) {
+				case map[interface{}]interface{}:
+					for key, value := range t {
+						if ks, ok := key.(string); ok && value != nil {
+							args[ks] = fmt.Sprintf("%v", value)
 						}
 					}
+				case []interface{}:
+					// the list form takes KEY=VALUE or a KEY whose value comes from the environment
+					for _, item := range t {
+						if is, ok := item.(string); ok {
+							parts := strings.SplitN(is, "=", 2)
+							if len(parts) == 2 {
+								args[parts[0]] = parts[1]
+							} else {
+								args[parts[0]] = os.Getenv(parts[0])
+							}
+						}
+					}
+				case nil:
+				default:
+					return fmt.Errorf("Failed to unmarshal build args: %#v", mapValue)
 				}
 				b.Args = args
 			default:
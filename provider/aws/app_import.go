@@ -0,0 +1,173 @@
+package aws
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/convox/rack/api/structs"
+	"github.com/convox/rack/manifest"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// importNameInvalid matches the characters that can not be used in app and service names
+var importNameInvalid = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// AppImportECS describes an app that runs the containers of an existing ECS service with
+// the same images, commands, ports, environment and scale
+func (p *AWSProvider) AppImportECS(cluster, service string) (*structs.AppImport, error) {
+	res, err := p.ecs().DescribeServices(&ecs.DescribeServicesInput{
+		Cluster:  aws.String(cluster),
+		Services: []*string{aws.String(service)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.Services) != 1 || *res.Services[0].Status == "INACTIVE" {
+		return nil, ErrorNotFound(fmt.Sprintf("no such ecs service: %s/%s", cluster, service))
+	}
+
+	s := res.Services[0]
+
+	td, err := p.describeTaskDefinition(*s.TaskDefinition)
+	if err != nil {
+		return nil, err
+	}
+
+	return ecsAppImport(s, td)
+}
+
+func ecsAppImport(s *ecs.Service, td *ecs.TaskDefinition) (*structs.AppImport, error) {
+	imp := &structs.AppImport{
+		Name:        importName(*s.ServiceName),
+		Environment: structs.Environment{},
+		Formation:   structs.Formation{},
+	}
+
+	balanced := map[string]int64{}
+
+	for _, lb := range s.LoadBalancers {
+		if lb.ContainerName != nil && lb.ContainerPort != nil {
+			balanced[*lb.ContainerName] = *lb.ContainerPort
+		}
+	}
+
+	// variables every container agrees on become the app environment,
+	// the rest are set on the services that use them
+	values := map[string]map[string]bool{}
+	defined := map[string]int{}
+
+	for _, cd := range td.ContainerDefinitions {
+		for _, kv := range cd.Environment {
+			if values[*kv.Name] == nil {
+				values[*kv.Name] = map[string]bool{}
+			}
+			values[*kv.Name][aws.StringValue(kv.Value)] = true
+			defined[*kv.Name]++
+		}
+	}
+
+	for key, vs := range values {
+		if len(vs) == 1 && defined[key] == len(td.ContainerDefinitions) {
+			for v := range vs {
+				imp.Environment[key] = v
+			}
+		}
+	}
+
+	services := map[string]map[string]interface{}{}
+
+	for _, cd := range td.ContainerDefinitions {
+		name := importName(*cd.Name)
+
+		ms := map[string]interface{}{
+			"image": aws.StringValue(cd.Image),
+		}
+
+		if len(cd.Command) > 0 {
+			ms["command"] = aws.StringValueSlice(cd.Command)
+		}
+
+		if len(cd.EntryPoint) > 0 {
+			ms["entrypoint"] = strings.Join(aws.StringValueSlice(cd.EntryPoint), " ")
+		}
+
+		env := map[string]string{}
+
+		for _, kv := range cd.Environment {
+			if _, ok := imp.Environment[*kv.Name]; !ok {
+				env[*kv.Name] = aws.StringValue(kv.Value)
+			}
+		}
+
+		if len(env) > 0 {
+			ms["environment"] = env
+		}
+
+		links := []string{}
+
+		for _, link := range cd.Links {
+			links = append(links, importName(strings.SplitN(*link, ":", 2)[0]))
+		}
+
+		if len(links) > 0 {
+			sort.Strings(links)
+			ms["links"] = links
+		}
+
+		ports := manifest.Ports{}
+
+		for _, pm := range cd.PortMappings {
+			port := manifest.Port{Balancer: int(*pm.ContainerPort), Container: int(*pm.ContainerPort)}
+
+			if balanced[*cd.Name] == *pm.ContainerPort {
+				port.Balancer = 80
+				port.Public = true
+			}
+
+			ports = append(ports, port)
+		}
+
+		if len(ports) > 0 {
+			ms["ports"] = ports
+		}
+
+		services[name] = ms
+
+		imp.Formation = append(imp.Formation, structs.ProcessFormation{
+			Name:   name,
+			Count:  int(aws.Int64Value(s.DesiredCount)),
+			CPU:    int(aws.Int64Value(cd.Cpu)),
+			Memory: int(aws.Int64Value(cd.Memory)),
+		})
+	}
+
+	m := yaml.MapSlice{
+		{Key: "version", Value: "2"},
+		{Key: "services", Value: services},
+	}
+
+	data, err := yaml.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	imp.Manifest = string(data)
+
+	return imp, nil
+}
+
+// importName turns an ECS name into a name the rack accepts
+func importName(name string) string {
+	name = strings.Trim(importNameInvalid.ReplaceAllString(strings.ToLower(name), "-"), "-")
+
+	if len(name) > 30 {
+		name = strings.TrimRight(name[0:30], "-")
+	}
+
+	return name
+}
@@ -0,0 +1,140 @@
+package aws_test
+
+import (
+	"testing"
+
+	"github.com/convox/rack/api/awsutil"
+	"github.com/convox/rack/api/structs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppImportECS(t *testing.T) {
+	provider := StubAwsProvider(
+		cycleImportDescribeServices,
+		cycleImportDescribeTaskDefinition,
+	)
+	defer provider.Close()
+
+	imp, err := provider.AppImportECS("legacy", "Billing_API")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "billing-api", imp.Name)
+	assert.Equal(t, structs.Environment{"RAILS_ENV": "production"}, imp.Environment)
+	assert.Equal(t, structs.Formation{
+		{Name: "web", Count: 3, CPU: 256, Memory: 512},
+		{Name: "sidekiq", Count: 3, Memory: 1024},
+	}, imp.Formation)
+	assert.Equal(t, `version: "2"
+services:
+  sidekiq:
+    command:
+    - bundle
+    - exec
+    - sidekiq
+    environment:
+      QUEUE: default
+    image: example/billing:v12
+  web:
+    entrypoint: bin/entry
+    environment:
+      PORT: "3000"
+    image: example/billing:v12
+    links:
+    - sidekiq
+    ports:
+    - 80:3000
+    - "9090"
+`, imp.Manifest)
+}
+
+func TestAppImportECSMissing(t *testing.T) {
+	provider := StubAwsProvider(
+		cycleImportDescribeServicesMissing,
+	)
+	defer provider.Close()
+
+	_, err := provider.AppImportECS("legacy", "Billing_API")
+
+	assert.EqualError(t, err, "no such ecs service: legacy/Billing_API")
+}
+
+var cycleImportDescribeServices = awsutil.Cycle{
+	Request: awsutil.Request{
+		RequestURI: "/",
+		Operation:  "AmazonEC2ContainerServiceV20141113.DescribeServices",
+		Body:       `{"cluster":"legacy","services":["Billing_API"]}`,
+	},
+	Response: awsutil.Response{
+		StatusCode: 200,
+		Body: `{
+			"services": [
+				{
+					"status": "ACTIVE",
+					"serviceName": "Billing_API",
+					"desiredCount": 3,
+					"taskDefinition": "arn:aws:ecs:us-east-1:901416387788:task-definition/billing:7",
+					"loadBalancers": [
+						{ "containerName": "web", "containerPort": 3000, "loadBalancerName": "billing" }
+					]
+				}
+			],
+			"failures": []
+		}`,
+	},
+}
+
+var cycleImportDescribeServicesMissing = awsutil.Cycle{
+	Request: awsutil.Request{
+		RequestURI: "/",
+		Operation:  "AmazonEC2ContainerServiceV20141113.DescribeServices",
+		Body:       `{"cluster":"legacy","services":["Billing_API"]}`,
+	},
+	Response: awsutil.Response{
+		StatusCode: 200,
+		Body:       `{"services":[],"failures":[{"arn":"arn:aws:ecs:us-east-1:901416387788:service/Billing_API","reason":"MISSING"}]}`,
+	},
+}
+
+var cycleImportDescribeTaskDefinition = awsutil.Cycle{
+	Request: awsutil.Request{
+		RequestURI: "/",
+		Operation:  "AmazonEC2ContainerServiceV20141113.DescribeTaskDefinition",
+		Body:       `{"taskDefinition":"arn:aws:ecs:us-east-1:901416387788:task-definition/billing:7"}`,
+	},
+	Response: awsutil.Response{
+		StatusCode: 200,
+		Body: `{
+			"taskDefinition": {
+				"family": "billing",
+				"containerDefinitions": [
+					{
+						"name": "web",
+						"cpu": 256,
+						"memory": 512,
+						"image": "example/billing:v12",
+						"entryPoint": ["bin/entry"],
+						"links": ["sidekiq:worker"],
+						"environment": [
+							{"name": "RAILS_ENV", "value": "production"},
+							{"name": "PORT", "value": "3000"}
+						],
+						"portMappings": [
+							{"hostPort": 0, "containerPort": 3000},
+							{"hostPort": 9090, "containerPort": 9090}
+						]
+					},
+					{
+						"name": "sidekiq",
+						"memory": 1024,
+						"image": "example/billing:v12",
+						"command": ["bundle", "exec", "sidekiq"],
+						"environment": [
+							{"name": "RAILS_ENV", "value": "production"},
+							{"name": "QUEUE", "value": "default"}
+						]
+					}
+				]
+			}
+		}`,
+	},
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/acm"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/cloudwatch"
 	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
@@ -36,11 +37,16 @@ type AWSProvider struct {
 	Secret   string
 	Token    string
 
+	BuildConcurrency  int
+	BuildHttpProxy    string
+	BuildImage        string
+	BuildNoProxy      string
 	Cluster           string
 	Development       bool
 	DockerImageAPI    string
 	DynamoBuilds      string
 	DynamoReleases    string
+	EncryptionKey     string
 	NotificationHost  string
 	NotificationTopic string
 	Password          string
@@ -63,11 +69,16 @@ func NewProviderFromEnv() *AWSProvider {
 		Access:            os.Getenv("AWS_ACCESS"),
 		Secret:            os.Getenv("AWS_SECRET"),
 		Token:             os.Getenv("AWS_TOKEN"),
+		BuildConcurrency:  buildConcurrency(),
+		BuildHttpProxy:    os.Getenv("BUILD_HTTP_PROXY"),
+		BuildImage:        os.Getenv("BUILD_IMAGE"),
+		BuildNoProxy:      os.Getenv("BUILD_NO_PROXY"),
 		Cluster:           os.Getenv("CLUSTER"),
 		Development:       os.Getenv("DEVELOPMENT") == "true",
 		DockerImageAPI:    os.Getenv("DOCKER_IMAGE_API"),
 		DynamoBuilds:      os.Getenv("DYNAMO_BUILDS"),
 		DynamoReleases:    os.Getenv("DYNAMO_RELEASES"),
+		EncryptionKey:     os.Getenv("ENCRYPTION_KEY"),
 		NotificationHost:  os.Getenv("NOTIFICATION_HOST"),
 		NotificationTopic: os.Getenv("NOTIFICATION_TOPIC"),
 		Password:          os.Getenv("PASSWORD"),
@@ -107,6 +118,10 @@ func (p *AWSProvider) acm() *acm.ACM {
 	return acm.New(session.New(), p.config())
 }
 
+func (p *AWSProvider) autoscaling() *autoscaling.AutoScaling {
+	return autoscaling.New(session.New(), p.config())
+}
+
 func (p *AWSProvider) cloudformation() *cloudformation.CloudFormation {
 	return cloudformation.New(session.New(), p.config())
 }
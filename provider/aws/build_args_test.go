@@ -0,0 +1,26 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/convox/rack/api/structs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildArgsImage(t *testing.T) {
+	a := &structs.App{Name: "httpd"}
+	b := &structs.Build{Id: "B12345"}
+
+	p := &AWSProvider{DockerImageAPI: "convox/api:20170101000000"}
+
+	args := p.buildArgs(a, b, "tgz")
+
+	assert.Equal(t, []string{"convox/api:20170101000000", "build", "tgz"}, args[len(args)-3:])
+
+	// a rack can run builds in its own image
+	p.BuildImage = "example/builder:1"
+
+	args = p.buildArgs(a, b, "tgz")
+
+	assert.Equal(t, []string{"example/builder:1", "build", "tgz"}, args[len(args)-3:])
+}
@@ -0,0 +1,333 @@
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/convox/rack/api/helpers"
+	"github.com/convox/rack/api/structs"
+)
+
+// buildQueue counts the builds running on this rack host.
+// Providers are created per request so the counter is shared across the package.
+// Builds waiting for a slot are kept in the builds table with a status of queued
+// so they survive an api restart and can be picked up by any rack host.
+var buildQueue = &queue{}
+
+type queue struct {
+	sync.Mutex
+
+	running int
+}
+
+// buildJob is everything needed to start a queued build later, possibly on another rack host
+type buildJob struct {
+	Url         string   `json:"url"`
+	Manifest    string   `json:"manifest"`
+	Cache       bool     `json:"cache"`
+	Concurrency int      `json:"concurrency"`
+	Strict      string   `json:"strict"`
+	BuildArgs   []string `json:"build-args"`
+
+	spooled bool
+}
+
+// buildConcurrency is the number of builds allowed to run at once on this rack host, 0 for no limit
+func buildConcurrency() int {
+	n, err := strconv.Atoi(os.Getenv("BUILD_CONCURRENCY"))
+	if err != nil || n < 0 {
+		return 0
+	}
+
+	return n
+}
+
+// acquire reserves a slot for a build if fewer than limit builds are running and returns true.
+// A limit of 0 means no limit.
+func (q *queue) acquire(limit int) bool {
+	q.Lock()
+	defer q.Unlock()
+
+	if limit <= 0 || q.running < limit {
+		q.running++
+		return true
+	}
+
+	return false
+}
+
+// done releases the slot held by a finished build
+func (q *queue) done() {
+	q.Lock()
+	defer q.Unlock()
+
+	q.running--
+}
+
+// stats returns the number of builds running on this rack host
+func (q *queue) stats() int {
+	q.Lock()
+	defer q.Unlock()
+
+	return q.running
+}
+
+// BuildDequeue starts queued builds while this rack host has free build slots
+func (p *AWSProvider) BuildDequeue() error {
+	// without a limit builds never wait
+	if p.BuildConcurrency <= 0 {
+		return nil
+	}
+
+	for buildQueue.acquire(p.BuildConcurrency) {
+		started, err := p.buildDequeueNext()
+		if err != nil || !started {
+			buildQueue.done()
+			return err
+		}
+	}
+
+	return nil
+}
+
+// buildDequeueNext claims the oldest build of the highest priority and starts it,
+// returning false if there was nothing left to claim
+func (p *AWSProvider) buildDequeueNext() (bool, error) {
+	queued, err := p.buildsQueued()
+	if err != nil {
+		return false, err
+	}
+
+	for _, b := range queued {
+		claimed, err := p.buildClaim(&b, "created")
+		if err != nil {
+			return false, err
+		}
+
+		// another rack host got to it first
+		if !claimed {
+			continue
+		}
+
+		if err := p.buildStartQueued(&b); err != nil {
+			b.Status = "failed"
+			b.Reason = err.Error()
+			b.Ended = time.Now()
+
+			if err := p.BuildSave(&b); err != nil {
+				helpers.Error(nil, err) // send internal error to rollbar
+			}
+
+			return false, err
+		}
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// buildsQueued lists the builds waiting for a slot, in the order they should start
+func (p *AWSProvider) buildsQueued() (structs.Builds, error) {
+	req := &dynamodb.ScanInput{
+		ConsistentRead:   aws.Bool(true),
+		FilterExpression: aws.String("#status = :queued"),
+		ExpressionAttributeNames: map[string]*string{
+			"#status": aws.String("status"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":queued": &dynamodb.AttributeValue{S: aws.String("queued")},
+		},
+		TableName: aws.String(p.DynamoBuilds),
+	}
+
+	builds := structs.Builds{}
+
+	err := p.dynamodb().ScanPages(req, func(res *dynamodb.ScanOutput, last bool) bool {
+		for _, item := range res.Items {
+			builds = append(builds, *p.buildFromItem(item))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Sort(queuedBuilds(builds))
+
+	return builds, nil
+}
+
+// queuedBuilds sorts builds by priority, then oldest first
+type queuedBuilds structs.Builds
+
+func (qb queuedBuilds) Len() int      { return len(qb) }
+func (qb queuedBuilds) Swap(i, j int) { qb[i], qb[j] = qb[j], qb[i] }
+
+func (qb queuedBuilds) Less(i, j int) bool {
+	ri, rj := buildPriorityRank(qb[i].Priority), buildPriorityRank(qb[j].Priority)
+
+	if ri != rj {
+		return ri < rj
+	}
+
+	return qb[i].Started.Before(qb[j].Started)
+}
+
+// buildPriorityRank is the position of a priority in BuildPriorities, builds without one rank as normal
+func buildPriorityRank(priority string) int {
+	for i, p := range structs.BuildPriorities {
+		if p == priority {
+			return i
+		}
+	}
+
+	return buildPriorityRank("normal")
+}
+
+// buildClaim moves a queued build to status, returning false if it is no longer queued
+func (p *AWSProvider) buildClaim(b *structs.Build, status string) (bool, error) {
+	_, err := p.dynamodb().UpdateItem(&dynamodb.UpdateItemInput{
+		ConditionExpression: aws.String("#status = :queued"),
+		ExpressionAttributeNames: map[string]*string{
+			"#status": aws.String("status"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":queued": &dynamodb.AttributeValue{S: aws.String("queued")},
+			":status": &dynamodb.AttributeValue{S: aws.String(status)},
+		},
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": &dynamodb.AttributeValue{S: aws.String(b.Id)},
+		},
+		TableName:        aws.String(p.DynamoBuilds),
+		UpdateExpression: aws.String("SET #status = :status"),
+	})
+
+	if awsError(err) == "ConditionalCheckFailedException" {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	b.Status = status
+
+	return true, nil
+}
+
+// buildEnqueue saves the job and source of a build to the app settings bucket and marks it queued
+func (p *AWSProvider) buildEnqueue(a *structs.App, b *structs.Build, job buildJob, stdin io.Reader) error {
+	if stdin != nil {
+		if err := p.buildSpoolSource(a, b, stdin); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	if err := p.s3Put(a.Outputs["Settings"], fmt.Sprintf("builds/%s.job", b.Id), data, false); err != nil {
+		return err
+	}
+
+	b.Status = "queued"
+
+	return p.BuildSave(b)
+}
+
+// buildSpoolSource streams a build source to the app settings bucket
+func (p *AWSProvider) buildSpoolSource(a *structs.App, b *structs.Build, r io.Reader) error {
+	return p.s3PutSpooled(a.Outputs["Settings"], fmt.Sprintf("builds/%s.tgz", b.Id), r)
+}
+
+// s3PutSpooled streams r to an object through a temporary file so it is never held in memory
+func (p *AWSProvider) s3PutSpooled(bucket, key string, r io.Reader) error {
+	fd, err := ioutil.TempFile("", "spool")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(fd.Name())
+	defer fd.Close()
+
+	size, err := io.Copy(fd, r)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fd.Seek(0, 0); err != nil {
+		return err
+	}
+
+	req := &s3.PutObjectInput{
+		Body:          fd,
+		Bucket:        aws.String(bucket),
+		ContentLength: aws.Int64(size),
+		Key:           aws.String(key),
+	}
+
+	p.s3Encryption(req, false)
+
+	_, err = p.s3().PutObject(req)
+
+	return err
+}
+
+// buildStartQueued starts a claimed build from the job and source saved when it was queued
+func (p *AWSProvider) buildStartQueued(b *structs.Build) error {
+	a, err := p.AppGet(b.App)
+	if err != nil {
+		return err
+	}
+
+	data, err := p.s3Get(a.Outputs["Settings"], fmt.Sprintf("builds/%s.job", b.Id))
+	if err != nil {
+		return err
+	}
+
+	job := buildJob{spooled: true}
+
+	if err := json.Unmarshal(data, &job); err != nil {
+		return err
+	}
+
+	var stdin io.Reader
+
+	if job.Url == "-" {
+		res, err := p.s3().GetObject(&s3.GetObjectInput{
+			Bucket: aws.String(a.Outputs["Settings"]),
+			Key:    aws.String(fmt.Sprintf("builds/%s.tgz", b.Id)),
+		})
+		if err != nil {
+			return err
+		}
+
+		stdin = res.Body
+	}
+
+	if err := p.BuildSave(b); err != nil {
+		return err
+	}
+
+	return p.buildStart(a, b, job, stdin)
+}
+
+// buildUnspool removes the job and source saved for a queued build
+func (p *AWSProvider) buildUnspool(a *structs.App, b *structs.Build) {
+	for _, key := range []string{fmt.Sprintf("builds/%s.job", b.Id), fmt.Sprintf("builds/%s.tgz", b.Id)} {
+		if err := p.s3Delete(a.Outputs["Settings"], key); err != nil && awsError(err) != "NoSuchKey" {
+			helpers.Error(nil, err) // send internal error to rollbar
+		}
+	}
+}
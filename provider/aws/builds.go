@@ -1,9 +1,12 @@
 package aws
 
 import (
+	"archive/tar"
 	"bufio"
 	"bytes"
+	"compress/gzip"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -12,6 +15,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -26,8 +30,47 @@ import (
 	"github.com/convox/rack/manifest"
 )
 
+// buildLogsInterval is how often the output of a running build is saved
+var buildLogsInterval = 5 * time.Second
+
+// buildCancelInterval is how often the host running a build checks whether it was cancelled
+var buildCancelInterval = 5 * time.Second
+
 var regexpECR = regexp.MustCompile(`(\d+)\.dkr\.ecr\.([^.]+)\.amazonaws\.com\/([^:]+):([^ ]+)`)
 
+// BuildCancel marks a build as cancelled. A running build container is stopped by the host
+// running it, which may not be the one handling this request, once it sees the cancel.
+func (p *AWSProvider) BuildCancel(app, id string) (*structs.Build, error) {
+	b, err := p.BuildGet(app, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// a build still waiting in the queue has no container to stop
+	claimed, err := p.buildClaim(b, "cancelled")
+	if err != nil {
+		return nil, err
+	}
+
+	if claimed {
+		a, err := p.AppGet(app)
+		if err != nil {
+			return nil, err
+		}
+
+		p.buildUnspool(a, b)
+	}
+
+	b.Status = "cancelled"
+	b.Ended = time.Now()
+
+	if err := p.BuildSave(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
 func (p *AWSProvider) BuildCopy(srcApp, id, destApp string) (*structs.Build, error) {
 	srcA, err := p.AppGet(srcApp)
 	if err != nil {
@@ -85,10 +128,14 @@ func (p *AWSProvider) BuildCopy(srcApp, id, destApp string) (*structs.Build, err
 	}
 
 	// Build .tgz in context of destApp
-	return p.BuildCreateTar(destA.Name, bytes.NewReader(tgz), "docker-compose.yml", fmt.Sprintf("Copy of %s %s", srcA.Name, srcB.Id), false)
+	return p.BuildCreateTar(destA.Name, bytes.NewReader(tgz), structs.BuildOptions{
+		Description: fmt.Sprintf("Copy of %s %s", srcA.Name, srcB.Id),
+		Manifest:    "docker-compose.yml",
+		Priority:    "normal",
+	})
 }
 
-func (p *AWSProvider) BuildCreateIndex(app string, index structs.Index, manifest, description string, cache bool) (*structs.Build, error) {
+func (p *AWSProvider) BuildCreateIndex(app string, index structs.Index, opts structs.BuildOptions) (*structs.Build, error) {
 	dir, err := ioutil.TempDir("", "source")
 	if err != nil {
 		return nil, err
@@ -109,31 +156,26 @@ func (p *AWSProvider) BuildCreateIndex(app string, index structs.Index, manifest
 		return nil, err
 	}
 
-	return p.BuildCreateTar(app, bytes.NewReader(tgz), manifest, description, cache)
+	return p.BuildCreateTar(app, bytes.NewReader(tgz), opts)
 }
 
-func (p *AWSProvider) BuildCreateRepo(app, url, manifest, description string, cache bool) (*structs.Build, error) {
+func (p *AWSProvider) BuildCreateRepo(app, url string, opts structs.BuildOptions) (*structs.Build, error) {
 	a, err := p.AppGet(app)
 	if err != nil {
 		return nil, err
 	}
 
 	b := structs.NewBuild(app)
-	b.Description = description
+	b.Architectures = opts.Architectures
+	b.Description = opts.Description
+	b.Priority = opts.Priority
 
 	err = p.BuildSave(b)
 	if err != nil {
 		return nil, err
 	}
 
-	args := p.buildArgs(a, b, url)
-
-	env, err := p.buildEnv(a, b, manifest, cache)
-	if err != nil {
-		return b, err
-	}
-
-	err = p.buildRun(a, b, args, env, nil)
+	err = p.buildRun(a, b, buildJob{Url: url, Manifest: opts.Manifest, Cache: opts.Cache, Concurrency: opts.Concurrency, Strict: opts.Strict, BuildArgs: opts.BuildArgs}, nil)
 
 	// build create is now complete or failed
 	p.EventSend(&structs.Event{
@@ -147,30 +189,23 @@ func (p *AWSProvider) BuildCreateRepo(app, url, manifest, description string, ca
 	return b, err
 }
 
-func (p *AWSProvider) BuildCreateTar(app string, src io.Reader, manifest, description string, cache bool) (*structs.Build, error) {
+func (p *AWSProvider) BuildCreateTar(app string, src io.Reader, opts structs.BuildOptions) (*structs.Build, error) {
 	a, err := p.AppGet(app)
 	if err != nil {
 		return nil, err
 	}
 
 	b := structs.NewBuild(app)
-	b.Description = description
+	b.Architectures = opts.Architectures
+	b.Description = opts.Description
+	b.Priority = opts.Priority
 
 	err = p.BuildSave(b)
 	if err != nil {
 		return nil, err
 	}
 
-	// TODO: save the tarball in s3?
-
-	args := p.buildArgs(a, b, "-")
-
-	env, err := p.buildEnv(a, b, manifest, cache)
-	if err != nil {
-		return b, err
-	}
-
-	err = p.buildRun(a, b, args, env, src)
+	err = p.buildRun(a, b, buildJob{Url: "-", Manifest: opts.Manifest, Cache: opts.Cache, Concurrency: opts.Concurrency, Strict: opts.Strict, BuildArgs: opts.BuildArgs}, src)
 
 	p.EventSend(&structs.Event{
 		Action: "build:create",
@@ -212,6 +247,176 @@ func (p *AWSProvider) BuildDelete(app, id string) (*structs.Build, error) {
 	return b, err
 }
 
+// buildArchive describes the build stored in an exported archive along with
+// the tag each of its images had on the rack it was exported from
+type buildArchive struct {
+	Build  structs.Build     `json:"build"`
+	Images map[string]string `json:"images"`
+}
+
+// BuildExport writes a gzipped tarball containing the manifest and images of a
+// completed build to w. The archive can be loaded on another rack with BuildImport.
+func (p *AWSProvider) BuildExport(app, id string, w io.Writer) error {
+	a, err := p.AppGet(app)
+	if err != nil {
+		return err
+	}
+
+	b, err := p.BuildGet(app, id)
+	if err != nil {
+		return err
+	}
+
+	if b.Status != "complete" {
+		return fmt.Errorf("build is not complete: %s", id)
+	}
+
+	var m manifest.Manifest
+
+	if err := yaml.Unmarshal([]byte(b.Manifest), &m); err != nil {
+		return err
+	}
+
+	archive := buildArchive{Build: *b, Images: map[string]string{}}
+	archive.Build.Logs = ""
+
+	services := []string{}
+
+	for name := range m.Services {
+		archive.Images[name] = p.registryTag(a, name, b.Id)
+		services = append(services, name)
+	}
+
+	sort.Strings(services)
+
+	data, err := json.Marshal(archive)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	header := &tar.Header{
+		Name:    "build.json",
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	if _, err := tw.Write(data); err != nil {
+		return err
+	}
+
+	for _, name := range services {
+		if err := exportImage(tw, name, archive.Images[name]); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return gz.Close()
+}
+
+// BuildImport creates a completed build and its release from an archive written by BuildExport,
+// pushing its images to the registry of app
+func (p *AWSProvider) BuildImport(app string, r io.Reader) (*structs.Build, error) {
+	a, err := p.AppGet(app)
+	if err != nil {
+		return nil, err
+	}
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	tr := tar.NewReader(gz)
+
+	b := structs.NewBuild(app)
+	b.Images = map[string]string{}
+	b.Started = time.Now()
+
+	var archive *buildArchive
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case header.Name == "build.json":
+			archive = &buildArchive{}
+
+			if err := json.NewDecoder(tr).Decode(archive); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(header.Name, "images/"):
+			if archive == nil {
+				return nil, fmt.Errorf("invalid build archive: images before build.json")
+			}
+
+			name := strings.TrimSuffix(strings.TrimPrefix(header.Name, "images/"), ".tar")
+
+			image, ok := archive.Images[name]
+			if !ok {
+				return nil, fmt.Errorf("invalid build archive: unknown image %s", name)
+			}
+
+			tag := p.registryTag(a, name, b.Id)
+
+			if err := importImage(tr, image, tag); err != nil {
+				return nil, err
+			}
+
+			b.Images[name] = tag
+		}
+	}
+
+	if archive == nil {
+		return nil, fmt.Errorf("invalid build archive: no build.json")
+	}
+
+	for name := range archive.Images {
+		if _, ok := b.Images[name]; !ok {
+			return nil, fmt.Errorf("invalid build archive: missing image %s", name)
+		}
+	}
+
+	b.Manifest = archive.Build.Manifest
+	b.GitSha = archive.Build.GitSha
+	b.Description = fmt.Sprintf("Import of %s %s", archive.Build.App, archive.Build.Id)
+	b.Status = "complete"
+	b.Ended = time.Now()
+
+	if err := p.BuildSave(b); err != nil {
+		return nil, err
+	}
+
+	_, err = p.BuildRelease(b)
+
+	p.EventSend(&structs.Event{
+		Action: "build:import",
+		Data: map[string]string{
+			"app": b.App,
+			"id":  b.Id,
+		},
+	}, err)
+
+	return b, err
+}
+
 func (p *AWSProvider) BuildGet(app, id string) (*structs.Build, error) {
 	req := &dynamodb.GetItemInput{
 		ConsistentRead: aws.Bool(true),
@@ -299,6 +504,170 @@ func (p *AWSProvider) BuildList(app string, limit int64) (structs.Builds, error)
 	return builds, nil
 }
 
+// BuildPrune archives the builds of an app beyond the newest retain, keeping the builds in keep
+// and the build of the active release, then deletes registry images that no remaining build
+// references. Returns the archived builds.
+func (p *AWSProvider) BuildPrune(app string, retain int, keep []string) (structs.Builds, error) {
+	a, err := p.AppGet(app)
+	if err != nil {
+		return nil, err
+	}
+
+	builds := structs.Builds{}
+
+	err = p.dynamodb().QueryPages(&dynamodb.QueryInput{
+		KeyConditionExpression: aws.String("app = :app"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":app": &dynamodb.AttributeValue{S: aws.String(a.Name)},
+		},
+		IndexName: aws.String("app.created"),
+		TableName: aws.String(p.DynamoBuilds),
+	}, func(res *dynamodb.QueryOutput, last bool) bool {
+		for _, item := range res.Items {
+			builds = append(builds, *p.buildFromItem(item))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if a.Release != "" {
+		r, err := p.ReleaseGet(a.Name, a.Release)
+		if err != nil {
+			return nil, err
+		}
+
+		keep = append(keep, r.Build)
+	}
+
+	expired := builds.Expired(retain, keep)
+	pruned := structs.Builds{}
+
+	for i := range expired {
+		b := &expired[i]
+
+		if err := p.buildArchive(a, b); err != nil {
+			return pruned, err
+		}
+
+		pruned = append(pruned, *b)
+	}
+
+	remaining := map[string]bool{}
+
+	for _, b := range builds {
+		if b.Status != "archived" {
+			remaining[b.Id] = true
+		}
+	}
+
+	for _, b := range pruned {
+		delete(remaining, b.Id)
+	}
+
+	return pruned, p.deleteUnreferencedImages(a, remaining)
+}
+
+// buildArchiveKey is where the export of an archived build is kept in the settings bucket of its app
+func buildArchiveKey(id string) string {
+	return fmt.Sprintf("builds/archive/%s.tgz", id)
+}
+
+// buildArchive saves an export of a complete build to the app settings bucket, where it can be
+// loaded again with BuildImport, then marks the build archived and deletes its images. The build
+// record and logs are kept.
+func (p *AWSProvider) buildArchive(a *structs.App, b *structs.Build) error {
+	if b.Status == "complete" {
+		r, w := io.Pipe()
+
+		go func() {
+			w.CloseWithError(p.BuildExport(a.Name, b.Id, w))
+		}()
+
+		err := p.s3PutSpooled(a.Outputs["Settings"], buildArchiveKey(b.Id), r)
+		r.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	b.Status = "archived"
+
+	if err := p.BuildSave(b); err != nil {
+		return err
+	}
+
+	return p.deleteImages(a, b)
+}
+
+// registryImageTag matches the <service>.<build> tags that builds push to an app registry
+var registryImageTag = regexp.MustCompile(`^[^.]+\.(B[A-Z]+)$`)
+
+// deleteUnreferencedImages removes untagged images and images of builds that no longer exist
+// from the registry of an app. Tags that were not pushed by a build are left alone.
+func (p *AWSProvider) deleteUnreferencedImages(a *structs.App, builds map[string]bool) error {
+	registryId := a.Outputs["RegistryId"]
+	repository := a.Outputs["RegistryRepository"]
+
+	// apps on the rack registry do not have a repository of their own
+	if registryId == "" || repository == "" {
+		return nil
+	}
+
+	unreferenced := []*ecr.ImageIdentifier{}
+
+	req := &ecr.ListImagesInput{
+		RegistryId:     aws.String(registryId),
+		RepositoryName: aws.String(repository),
+	}
+
+	for {
+		res, err := p.ecr().ListImages(req)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range res.ImageIds {
+			if id.ImageTag == nil {
+				unreferenced = append(unreferenced, &ecr.ImageIdentifier{ImageDigest: id.ImageDigest})
+				continue
+			}
+
+			if m := registryImageTag.FindStringSubmatch(*id.ImageTag); m != nil && !builds[m[1]] {
+				unreferenced = append(unreferenced, &ecr.ImageIdentifier{ImageTag: id.ImageTag})
+			}
+		}
+
+		if res.NextToken == nil {
+			break
+		}
+
+		req.NextToken = res.NextToken
+	}
+
+	// BatchDeleteImage accepts at most 100 images per call
+	for len(unreferenced) > 0 {
+		n := len(unreferenced)
+		if n > 100 {
+			n = 100
+		}
+
+		_, err := p.ecr().BatchDeleteImage(&ecr.BatchDeleteImageInput{
+			ImageIds:       unreferenced[0:n],
+			RegistryId:     aws.String(registryId),
+			RepositoryName: aws.String(repository),
+		})
+		if err != nil {
+			return err
+		}
+
+		unreferenced = unreferenced[n:]
+	}
+
+	return nil
+}
+
 func (p *AWSProvider) BuildRelease(b *structs.Build) (*structs.Release, error) {
 	releases, err := p.ReleaseList(b.App, 20)
 	if err != nil {
@@ -314,6 +683,7 @@ func (p *AWSProvider) BuildRelease(b *structs.Build) (*structs.Release, error) {
 
 	r.Id = newId
 	r.Created = time.Time{}
+	r.CreatedBy = ""
 	r.Build = b.Id
 	r.Manifest = b.Manifest
 
@@ -381,18 +751,42 @@ func (p *AWSProvider) BuildSave(b *structs.Build) error {
 		req.Item["release"] = &dynamodb.AttributeValue{S: aws.String(b.Release)}
 	}
 
+	if b.Priority != "" {
+		req.Item["priority"] = &dynamodb.AttributeValue{S: aws.String(b.Priority)}
+	}
+
+	if b.GitSha != "" {
+		req.Item["git-sha"] = &dynamodb.AttributeValue{S: aws.String(b.GitSha)}
+	}
+
+	if len(b.Architectures) > 0 {
+		req.Item["architectures"] = &dynamodb.AttributeValue{S: aws.String(strings.Join(b.Architectures, ","))}
+	}
+
+	if len(b.Images) > 0 {
+		data, err := json.Marshal(b.Images)
+		if err != nil {
+			return err
+		}
+
+		req.Item["images"] = &dynamodb.AttributeValue{S: aws.String(string(data))}
+	}
+
+	if b.Timings != nil {
+		data, err := json.Marshal(b.Timings)
+		if err != nil {
+			return err
+		}
+
+		req.Item["timings"] = &dynamodb.AttributeValue{S: aws.String(string(data))}
+	}
+
 	if !b.Ended.IsZero() {
 		req.Item["ended"] = &dynamodb.AttributeValue{S: aws.String(b.Ended.Format(sortableTime))}
 	}
 
 	if b.Logs != "" {
-		_, err := p.s3().PutObject(&s3.PutObjectInput{
-			Body:          bytes.NewReader([]byte(b.Logs)),
-			Bucket:        aws.String(a.Outputs["Settings"]),
-			ContentLength: aws.Int64(int64(len(b.Logs))),
-			Key:           aws.String(fmt.Sprintf("builds/%s.log", b.Id)),
-		})
-		if err != nil {
+		if err := p.buildLogsSave(a, b.Id, b.Logs); err != nil {
 			return err
 		}
 	}
@@ -402,6 +796,11 @@ func (p *AWSProvider) BuildSave(b *structs.Build) error {
 	return err
 }
 
+// buildLogsSave stores the output of a build in the settings bucket of its app
+func (p *AWSProvider) buildLogsSave(a *structs.App, id, logs string) error {
+	return p.s3Put(a.Outputs["Settings"], fmt.Sprintf("builds/%s.log", id), []byte(logs), false)
+}
+
 func (p *AWSProvider) buildArgs(a *structs.App, b *structs.Build, source string) []string {
 	return []string{
 		"run",
@@ -420,13 +819,33 @@ func (p *AWSProvider) buildArgs(a *structs.App, b *structs.Build, source string)
 		"-e", "MANIFEST_PATH",
 		"-e", "REPOSITORY",
 		"-e", "NO_CACHE",
-		p.DockerImageAPI,
+		"-e", "BUILD_CONCURRENCY",
+		"-e", "BUILD_STRICT",
+		"-e", "BUILD_ARGS",
+		"-e", "BUILD_ARCHITECTURES",
+		"-e", "HTTP_PROXY",
+		"-e", "HTTPS_PROXY",
+		"-e", "NO_PROXY",
+		"-e", "http_proxy",
+		"-e", "https_proxy",
+		"-e", "no_proxy",
+		p.buildImage(),
 		"build",
 		source,
 	}
 }
 
-func (p *AWSProvider) buildEnv(a *structs.App, b *structs.Build, manifest_path string, cache bool) ([]string, error) {
+// buildImage returns the image builds run in, which racks can replace with one
+// that has extra certificates or tooling installed
+func (p *AWSProvider) buildImage() string {
+	if p.BuildImage != "" {
+		return p.BuildImage
+	}
+
+	return p.DockerImageAPI
+}
+
+func (p *AWSProvider) buildEnv(a *structs.App, b *structs.Build, manifest_path string, cache bool, concurrency int, strict string, buildArgs []string) ([]string, error) {
 	// self-hosted registry auth
 	email := "user@convox.com"
 	username := "convox"
@@ -499,28 +918,119 @@ func (p *AWSProvider) buildEnv(a *structs.App, b *structs.Build, manifest_path s
 		env = append(env, "NO_CACHE=true")
 	}
 
+	if concurrency > 0 {
+		env = append(env, fmt.Sprintf("BUILD_CONCURRENCY=%d", concurrency))
+	}
+
+	if strict != "" {
+		env = append(env, fmt.Sprintf("BUILD_STRICT=%s", strict))
+	}
+
+	if len(buildArgs) > 0 {
+		env = append(env, fmt.Sprintf("BUILD_ARGS=%s", strings.Join(buildArgs, "\n")))
+	}
+
+	if len(b.Architectures) > 0 {
+		env = append(env, fmt.Sprintf("BUILD_ARCHITECTURES=%s", strings.Join(b.Architectures, ",")))
+	}
+
+	env = append(env, p.buildProxyEnv(host)...)
+
 	return env, nil
 }
 
+// buildProxyEnv sends builds through the rack's egress proxy, except for
+// callbacks to the rack and pushes to its registry
+func (p *AWSProvider) buildProxyEnv(host string) []string {
+	if p.BuildHttpProxy == "" {
+		return []string{}
+	}
+
+	noProxy := []string{}
+
+	for _, h := range []string{host, strings.Split(p.RegistryHost, ":")[0], p.BuildNoProxy} {
+		if h != "" {
+			noProxy = append(noProxy, h)
+		}
+	}
+
+	env := []string{}
+
+	for _, name := range []string{"HTTP_PROXY", "HTTPS_PROXY", "http_proxy", "https_proxy"} {
+		env = append(env, fmt.Sprintf("%s=%s", name, p.BuildHttpProxy))
+	}
+
+	for _, name := range []string{"NO_PROXY", "no_proxy"} {
+		env = append(env, fmt.Sprintf("%s=%s", name, strings.Join(noProxy, ",")))
+	}
+
+	return env
+}
+
 // buildFromItem populates a Build struct from a DynamoDB Item
 func (p *AWSProvider) buildFromItem(item map[string]*dynamodb.AttributeValue) *structs.Build {
 	id := coalesce(item["id"], "")
 	started, _ := time.Parse(sortableTime, coalesce(item["created"], ""))
 	ended, _ := time.Parse(sortableTime, coalesce(item["ended"], ""))
 
+	var images map[string]string
+
+	if data := coalesce(item["images"], ""); data != "" {
+		json.Unmarshal([]byte(data), &images)
+	}
+
+	var timings *structs.BuildTimings
+
+	if data := coalesce(item["timings"], ""); data != "" {
+		json.Unmarshal([]byte(data), &timings)
+	}
+
+	var archs []string
+
+	if data := coalesce(item["architectures"], ""); data != "" {
+		archs = strings.Split(data, ",")
+	}
+
 	return &structs.Build{
-		Id:          id,
-		App:         coalesce(item["app"], ""),
-		Description: coalesce(item["description"], ""),
-		Manifest:    coalesce(item["manifest"], ""),
-		Release:     coalesce(item["release"], ""),
-		Status:      coalesce(item["status"], ""),
-		Started:     started,
-		Ended:       ended,
+		Id:            id,
+		App:           coalesce(item["app"], ""),
+		Architectures: archs,
+		Description:   coalesce(item["description"], ""),
+		GitSha:        coalesce(item["git-sha"], ""),
+		Images:        images,
+		Timings:       timings,
+		Manifest:      coalesce(item["manifest"], ""),
+		Priority:      coalesce(item["priority"], ""),
+		Release:       coalesce(item["release"], ""),
+		Status:        coalesce(item["status"], ""),
+		Started:       started,
+		Ended:         ended,
+	}
+}
+
+// buildRun starts a build if this rack host has a free build slot, otherwise it is queued
+// until a slot frees up here or on another rack host
+func (p *AWSProvider) buildRun(a *structs.App, b *structs.Build, job buildJob, stdin io.Reader) error {
+	if !buildQueue.acquire(p.BuildConcurrency) {
+		return p.buildEnqueue(a, b, job, stdin)
+	}
+
+	if err := p.buildStart(a, b, job, stdin); err != nil {
+		buildQueue.done()
+		return err
 	}
+
+	return nil
 }
 
-func (p *AWSProvider) buildRun(a *structs.App, b *structs.Build, args []string, env []string, stdin io.Reader) error {
+func (p *AWSProvider) buildStart(a *structs.App, b *structs.Build, job buildJob, stdin io.Reader) error {
+	args := p.buildArgs(a, b, job.Url)
+
+	env, err := p.buildEnv(a, b, job.Manifest, job.Cache, job.Concurrency, job.Strict, job.BuildArgs)
+	if err != nil {
+		return err
+	}
+
 	cmd := exec.Command("docker", args...)
 	cmd.Env = env
 	cmd.Stdin = stdin
@@ -539,19 +1049,53 @@ func (p *AWSProvider) buildRun(a *structs.App, b *structs.Build, args []string,
 		return err
 	}
 
-	go p.buildWait(a, b, cmd, stdout)
+	go p.buildWait(a, b, job, cmd, stdout)
 
 	return nil
 }
 
-func (p *AWSProvider) buildWait(a *structs.App, b *structs.Build, cmd *exec.Cmd, stdout io.ReadCloser) {
+func (p *AWSProvider) buildWait(a *structs.App, b *structs.Build, job buildJob, cmd *exec.Cmd, stdout io.ReadCloser) {
+	defer func() {
+		buildQueue.done()
+
+		if job.spooled {
+			p.buildUnspool(a, b)
+		}
+
+		// hand the free slot to the next queued build
+		if err := p.BuildDequeue(); err != nil {
+			helpers.Error(nil, err) // send internal error to rollbar
+		}
+	}()
+
+	// a cancel can be handled by any rack host so watch for it here where the container runs
+	stop := make(chan struct{})
+	defer close(stop)
+
+	go p.buildCancelWatch(b, stop)
+
+	// redact build output before it is stored
+	scrubber, err := p.logScrubber(a)
+	if err != nil {
+		helpers.Error(nil, err) // send internal error to rollbar
+		scrubber = &structs.LogScrubber{}
+	}
 
 	// scan all output
 	scanner := bufio.NewScanner(stdout)
 	out := ""
+	saved := time.Now()
 	for scanner.Scan() {
-		text := scanner.Text()
+		text := scrubber.Scrub(scanner.Text())
 		out += text + "\n"
+
+		// save output as it arrives so it can be followed from another host or after a restart
+		if time.Since(saved) > buildLogsInterval {
+			if err := p.buildLogsSave(a, b.Id, out); err != nil {
+				helpers.Error(nil, err) // send internal error to rollbar
+			}
+			saved = time.Now()
+		}
 	}
 	if err := scanner.Err(); err != nil {
 		helpers.Error(nil, err) // send internal error to rollbar
@@ -588,13 +1132,13 @@ func (p *AWSProvider) buildWait(a *structs.App, b *structs.Build, cmd *exec.Cmd,
 	}
 
 	// reload build item to get data from BuildUpdate callback
-	b, err := p.BuildGet(b.App, b.Id)
+	b, err = p.BuildGet(b.App, b.Id)
 	if err != nil {
 		helpers.Error(nil, err) // send internal error to rollbar
 		return
 	}
 
-	if cmdStatus != "" { // Careful not to override the status set by BuildUpdate
+	if cmdStatus != "" && b.Status != "cancelled" { // Careful not to override the status set by BuildUpdate or BuildCancel
 		b.Status = cmdStatus
 	}
 
@@ -607,6 +1151,36 @@ func (p *AWSProvider) buildWait(a *structs.App, b *structs.Build, cmd *exec.Cmd,
 	}
 }
 
+// buildCancelWatch kills the container of a build on this host once a cancel is recorded for it
+// in the builds table, until done is closed
+func (p *AWSProvider) buildCancelWatch(b *structs.Build, done chan struct{}) {
+	tick := time.NewTicker(buildCancelInterval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-tick.C:
+			cb, err := p.BuildGet(b.App, b.Id)
+			if err != nil {
+				helpers.Error(nil, err) // send internal error to rollbar
+				continue
+			}
+
+			if cb.Status != "cancelled" {
+				continue
+			}
+
+			if out, err := exec.Command("docker", "kill", fmt.Sprintf("build-%s", b.Id)).CombinedOutput(); err != nil {
+				helpers.Error(nil, fmt.Errorf("could not cancel build %s: %s", b.Id, strings.TrimSpace(string(out))))
+			}
+
+			return
+		}
+	}
+}
+
 func createTarball(base string) ([]byte, error) {
 	cwd, err := os.Getwd()
 
@@ -683,6 +1257,11 @@ func (p *AWSProvider) deleteImages(a *structs.App, b *structs.Build) error {
 
 	for name, _ := range m.Services {
 		urls = append(urls, p.registryTag(a, name, b.Id))
+
+		// the images of each architecture of a matrix build
+		for _, arch := range b.Architectures {
+			urls = append(urls, p.registryTag(a, name, fmt.Sprintf("%s-%s", b.Id, arch)))
+		}
 	}
 
 	imageIds := []*ecr.ImageIdentifier{}
@@ -711,6 +1290,65 @@ func (p *AWSProvider) deleteImages(a *structs.App, b *structs.Build) error {
 	return err
 }
 
+// exportImage adds the image saved by docker to tw as images/<name>.tar
+func exportImage(tw *tar.Writer, name, image string) error {
+	if out, err := exec.Command("docker", "pull", image).CombinedOutput(); err != nil {
+		return fmt.Errorf("could not pull %s: %s", image, strings.TrimSpace(string(out)))
+	}
+
+	f, err := ioutil.TempFile("", "image")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if out, err := exec.Command("docker", "save", "-o", f.Name(), image).CombinedOutput(); err != nil {
+		return fmt.Errorf("could not save %s: %s", image, strings.TrimSpace(string(out)))
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	header := &tar.Header{
+		Name:    fmt.Sprintf("images/%s.tar", name),
+		Mode:    0644,
+		Size:    stat.Size(),
+		ModTime: time.Now(),
+	}
+
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(tw, f)
+
+	return err
+}
+
+// importImage loads an image saved as image from r and pushes it as tag
+func importImage(r io.Reader, image, tag string) error {
+	cmd := exec.Command("docker", "load")
+	cmd.Stdin = r
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("could not load %s: %s", image, strings.TrimSpace(string(out)))
+	}
+
+	if out, err := exec.Command("docker", "tag", image, tag).CombinedOutput(); err != nil {
+		return fmt.Errorf("could not tag %s: %s", image, strings.TrimSpace(string(out)))
+	}
+
+	if out, err := exec.Command("docker", "push", tag).CombinedOutput(); err != nil {
+		return fmt.Errorf("could not push %s: %s", tag, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
 func (p *AWSProvider) registryTag(a *structs.App, serviceName, buildID string) string {
 	tag := fmt.Sprintf("%s/%s-%s:%s", p.RegistryHost, a.Name, serviceName, buildID)
 
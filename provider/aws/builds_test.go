@@ -40,6 +40,38 @@ func TestBuildGet(t *testing.T) {
 	}, b)
 }
 
+func TestBuildDequeueClaimedElsewhere(t *testing.T) {
+	provider := StubAwsProvider(
+		buildsQueuedScanCycle,
+		buildQueuedClaimConflictCycle,
+	)
+	defer provider.Close()
+
+	provider.BuildConcurrency = 1
+
+	err := provider.BuildDequeue()
+
+	assert.Nil(t, err)
+}
+
+func TestBuildCancelRunning(t *testing.T) {
+	provider := StubAwsProvider(
+		buildRunningGetItemCycle,
+		buildRunningClaimConflictCycle,
+
+		describeStacksCycle,
+		buildRunningCancelPutItemCycle,
+	)
+	defer provider.Close()
+
+	// the container is killed by the host running it, not the one handling the cancel
+	b, err := provider.BuildCancel("httpd", "BRUNNINGBUILD")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "cancelled", b.Status)
+	assert.False(t, b.Ended.IsZero())
+}
+
 func TestBuildDelete(t *testing.T) {
 	provider := StubAwsProvider(
 		build2GetItemCycle,
@@ -283,6 +315,66 @@ var buildsQueryCycle = awsutil.Cycle{
 	},
 }
 
+var buildsQueuedScanCycle = awsutil.Cycle{
+	Request: awsutil.Request{
+		RequestURI: "/",
+		Operation:  "DynamoDB_20120810.Scan",
+		Body:       `{"ConsistentRead":true,"ExpressionAttributeNames":{"#status":"status"},"ExpressionAttributeValues":{":queued":{"S":"queued"}},"FilterExpression":"#status = :queued","TableName":"convox-builds"}`,
+	},
+	Response: awsutil.Response{
+		StatusCode: 200,
+		Body:       `{"Count":1,"Items":[{"id":{"S":"BQUEUEDBUILD"},"app":{"S":"httpd"},"created":{"S":"20160404.143416.178278576"},"status":{"S":"queued"}}],"ScannedCount":1}`,
+	},
+}
+
+var buildQueuedClaimConflictCycle = awsutil.Cycle{
+	Request: awsutil.Request{
+		RequestURI: "/",
+		Operation:  "DynamoDB_20120810.UpdateItem",
+		Body:       `{"ConditionExpression":"#status = :queued","ExpressionAttributeNames":{"#status":"status"},"ExpressionAttributeValues":{":queued":{"S":"queued"},":status":{"S":"created"}},"Key":{"id":{"S":"BQUEUEDBUILD"}},"TableName":"convox-builds","UpdateExpression":"SET #status = :status"}`,
+	},
+	Response: awsutil.Response{
+		StatusCode: 400,
+		Body:       `{"__type":"com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException","message":"The conditional request failed"}`,
+	},
+}
+
+var buildRunningGetItemCycle = awsutil.Cycle{
+	Request: awsutil.Request{
+		RequestURI: "/",
+		Operation:  "DynamoDB_20120810.GetItem",
+		Body:       `{"ConsistentRead":true,"Key":{"id":{"S":"BRUNNINGBUILD"}},"TableName":"convox-builds"}`,
+	},
+	Response: awsutil.Response{
+		StatusCode: 200,
+		Body:       `{"Item":{"id":{"S":"BRUNNINGBUILD"},"app":{"S":"httpd"},"created":{"S":"20160404.143416.178278576"},"status":{"S":"running"}}}`,
+	},
+}
+
+var buildRunningClaimConflictCycle = awsutil.Cycle{
+	Request: awsutil.Request{
+		RequestURI: "/",
+		Operation:  "DynamoDB_20120810.UpdateItem",
+		Body:       `{"ConditionExpression":"#status = :queued","ExpressionAttributeNames":{"#status":"status"},"ExpressionAttributeValues":{":queued":{"S":"queued"},":status":{"S":"cancelled"}},"Key":{"id":{"S":"BRUNNINGBUILD"}},"TableName":"convox-builds","UpdateExpression":"SET #status = :status"}`,
+	},
+	Response: awsutil.Response{
+		StatusCode: 400,
+		Body:       `{"__type":"com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException","message":"The conditional request failed"}`,
+	},
+}
+
+var buildRunningCancelPutItemCycle = awsutil.Cycle{
+	Request: awsutil.Request{
+		RequestURI: "/",
+		Operation:  "DynamoDB_20120810.PutItem",
+		Body:       `/"id":{"S":"BRUNNINGBUILD"},"status":{"S":"cancelled"}/`,
+	},
+	Response: awsutil.Response{
+		StatusCode: 200,
+		Body:       `{}`,
+	},
+}
+
 var build1GetItemCycle = awsutil.Cycle{
 	Request: awsutil.Request{
 		RequestURI: "/",
@@ -108,6 +108,40 @@ func (p *AWSProvider) FormationSave(app string, pf *structs.ProcessFormation) er
 		return fmt.Errorf("requested memory %d greater than instance size %d", pf.Memory, capacity.InstanceMemory)
 	}
 
+	if as := pf.Autoscale; as != nil {
+		if as.Min < 1 {
+			return fmt.Errorf("autoscale minimum %d must be 1 or greater", as.Min)
+		}
+
+		if as.Max < as.Min {
+			return fmt.Errorf("autoscale maximum %d must not be less than the minimum %d", as.Max, as.Min)
+		}
+
+		if as.CPU < 1 || as.CPU > 100 {
+			return fmt.Errorf("autoscale cpu %d must be a percentage between 1 and 100", as.CPU)
+		}
+
+		// start from a count the scaling policies would keep anyway
+		switch {
+		case pf.Count < as.Min:
+			pf.Count = as.Min
+		case pf.Count > as.Max:
+			pf.Count = as.Max
+		}
+	}
+
+	if _, ok := a.Parameters[fmt.Sprintf("%sAutoscale", upperName(pf.Name))]; ok {
+		if pf.Autoscale != nil {
+			if err := p.formationMetricAutoscale(a, pf.Name); err != nil {
+				return err
+			}
+		}
+
+		params[fmt.Sprintf("%sAutoscale", upperName(pf.Name))] = formationAutoscale(pf.Autoscale)
+	} else if pf.Autoscale != nil {
+		return fmt.Errorf("promote a new release of %s to enable autoscaling", app)
+	}
+
 	if _, ok := a.Parameters[fmt.Sprintf("%sFormation", upperName(pf.Name))]; ok {
 		params[fmt.Sprintf("%sFormation", upperName(pf.Name))] = fmt.Sprintf("%d,%d,%d", pf.Count, pf.CPU, pf.Memory)
 	} else {
@@ -119,8 +153,12 @@ func (p *AWSProvider) FormationSave(app string, pf *structs.ProcessFormation) er
 	p.EventSend(&structs.Event{
 		Action: "release:scale",
 		Data: map[string]string{
-			"app": a.Name,
-			"id":  a.Release,
+			"app":     a.Name,
+			"id":      a.Release,
+			"process": pf.Name,
+			"count":   fmt.Sprintf("%d", pf.Count),
+			"cpu":     fmt.Sprintf("%d", pf.CPU),
+			"memory":  fmt.Sprintf("%d", pf.Memory),
 		},
 	}, nil)
 
@@ -140,6 +178,73 @@ func (p *AWSProvider) FormationSave(app string, pf *structs.ProcessFormation) er
 	return err
 }
 
+// formationMetricAutoscale returns an error if the manifest of the current release scales a
+// process on a metric, as the rack would fight the cpu scaling policies over its count
+func (p *AWSProvider) formationMetricAutoscale(a *structs.App, process string) error {
+	if a.Release == "" {
+		return nil
+	}
+
+	release, err := p.ReleaseGet(a.Name, a.Release)
+	if err != nil {
+		return err
+	}
+
+	m, err := manifest.Load([]byte(release.Manifest))
+	if err != nil {
+		return err
+	}
+
+	if s, ok := m.Services[process]; ok {
+		if _, ok := s.Labels["convox.autoscale.metric"]; ok {
+			return fmt.Errorf("%s is autoscaled by the convox.autoscale.metric label, remove it to autoscale on cpu", process)
+		}
+	}
+
+	return nil
+}
+
+// formationAutoscale returns the Autoscale parameter of a process. Processes scale in once the
+// CPU utilization drops below half of the target so a removed process does not push the rest
+// straight back above it.
+func formationAutoscale(as *structs.ProcessAutoscale) string {
+	if as == nil {
+		return "0,0,0,0"
+	}
+
+	return fmt.Sprintf("%d,%d,%d,%d", as.Min, as.Max, as.CPU, as.CPU/2)
+}
+
+func parseFormationAutoscale(app *structs.App, process string) (*structs.ProcessAutoscale, error) {
+	param, ok := app.Parameters[fmt.Sprintf("%sAutoscale", upperName(process))]
+	if !ok {
+		return nil, nil
+	}
+
+	parts := strings.Split(param, ",")
+
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("%s autoscale settings not in Min,Max,CpuHigh,CpuLow format", process)
+	}
+
+	values := make([]int, len(parts))
+
+	for i, part := range parts {
+		v, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("%s autoscale settings not numeric", process)
+		}
+
+		values[i] = v
+	}
+
+	if values[1] == 0 {
+		return nil, nil
+	}
+
+	return &structs.ProcessAutoscale{Min: values[0], Max: values[1], CPU: values[2]}, nil
+}
+
 func parseFormationParameters(app *structs.App, process string) (count, cpu, memory int, err error) {
 	if _, ok := app.Parameters[fmt.Sprintf("%sFormation", upperName(process))]; ok {
 		return parseFormationCombined(app, process)
@@ -201,6 +306,11 @@ func processFormation(a *structs.App, s manifest.Service) (*structs.ProcessForma
 		return nil, err
 	}
 
+	autoscale, err := parseFormationAutoscale(a, s.Name)
+	if err != nil {
+		return nil, err
+	}
+
 	re := regexp.MustCompile(fmt.Sprintf(`%sPort(\d+)Host`, upperName(s.Name)))
 
 	ports := []int{}
@@ -215,12 +325,13 @@ func processFormation(a *structs.App, s manifest.Service) (*structs.ProcessForma
 	}
 
 	pf := &structs.ProcessFormation{
-		Balancer: coalesceString(a.Outputs[fmt.Sprintf("Balancer%sHost", upperName(s.Name))], a.Outputs["BalancerHost"]),
-		Name:     s.Name,
-		Count:    count,
-		Memory:   memory,
-		CPU:      cpu,
-		Ports:    ports,
+		Balancer:  coalesceString(a.Outputs[fmt.Sprintf("Balancer%sHost", upperName(s.Name))], a.Outputs["BalancerHost"]),
+		Name:      s.Name,
+		Count:     count,
+		Memory:    memory,
+		CPU:       cpu,
+		Ports:     ports,
+		Autoscale: autoscale,
 	}
 
 	return pf, nil
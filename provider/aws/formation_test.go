@@ -2,6 +2,7 @@ package aws_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/convox/rack/api/awsutil"
@@ -339,6 +340,78 @@ func TestFormationSaveMemoryTooLarge(t *testing.T) {
 	assert.Equal(t, fmt.Errorf("requested memory 20000 greater than instance size 2004"), err)
 }
 
+func TestFormationSaveAutoscaleBadRange(t *testing.T) {
+	provider := StubAwsProvider(
+		cycleFormationDescribeStacks,
+		cycleCapacityListContainerInstances,
+		cycleCapacityDescribeContainerInstances,
+		cycleCapacityListServices,
+		cycleCapacityDescribeServices,
+		cycleCapacityDescribeTaskDefinition,
+		cycleCapacityDescribeTaskDefinition,
+	)
+	defer provider.Close()
+
+	pf := &structs.ProcessFormation{
+		Name:      "web",
+		Count:     1,
+		Autoscale: &structs.ProcessAutoscale{Min: 4, Max: 2, CPU: 70},
+	}
+
+	err := provider.FormationSave("httpd", pf)
+
+	assert.Equal(t, fmt.Errorf("autoscale maximum 2 must not be less than the minimum 4"), err)
+}
+
+func TestFormationSaveAutoscaleUnsupported(t *testing.T) {
+	provider := StubAwsProvider(
+		cycleFormationDescribeStacks,
+		cycleCapacityListContainerInstances,
+		cycleCapacityDescribeContainerInstances,
+		cycleCapacityListServices,
+		cycleCapacityDescribeServices,
+		cycleCapacityDescribeTaskDefinition,
+		cycleCapacityDescribeTaskDefinition,
+	)
+	defer provider.Close()
+
+	pf := &structs.ProcessFormation{
+		Name:      "web",
+		Count:     1,
+		Autoscale: &structs.ProcessAutoscale{Min: 2, Max: 10, CPU: 70},
+	}
+
+	err := provider.FormationSave("httpd", pf)
+
+	assert.Equal(t, fmt.Errorf("promote a new release of httpd to enable autoscaling"), err)
+	assert.Equal(t, 2, pf.Count)
+}
+
+func TestFormationSaveAutoscaleMetricConflict(t *testing.T) {
+	provider := StubAwsProvider(
+		cycleFormationDescribeStacksAutoscale,
+		cycleCapacityListContainerInstances,
+		cycleCapacityDescribeContainerInstances,
+		cycleCapacityListServices,
+		cycleCapacityDescribeServices,
+		cycleCapacityDescribeTaskDefinition,
+		cycleCapacityDescribeTaskDefinition,
+		cycleFormationDescribeStacksAutoscale,
+		cycleReleaseGetItemAutoscaleMetric,
+	)
+	defer provider.Close()
+
+	pf := &structs.ProcessFormation{
+		Name:      "web",
+		Count:     1,
+		Autoscale: &structs.ProcessAutoscale{Min: 2, Max: 10, CPU: 70},
+	}
+
+	err := provider.FormationSave("httpd", pf)
+
+	assert.Equal(t, fmt.Errorf("web is autoscaled by the convox.autoscale.metric label, remove it to autoscale on cpu"), err)
+}
+
 var cycleFormationDescribeStacks = awsutil.Cycle{
 	awsutil.Request{"/", "", `Action=DescribeStacks&StackName=convox-httpd&Version=2010-05-15`},
 	awsutil.Response{
@@ -813,7 +886,7 @@ func cycleDescribeStacksNotFound(name string) awsutil.Cycle {
 var cycleNotificationPublish = awsutil.Cycle{
 	Request: awsutil.Request{
 		RequestURI: "/",
-		Body:       `Action=Publish&Message=%7B%22action%22%3A%22release%3Ascale%22%2C%22status%22%3A%22success%22%2C%22data%22%3A%7B%22app%22%3A%22httpd%22%2C%22id%22%3A%22RVFETUHHKKD%22%7D%2C%22timestamp%22%3A%220001-01-01T00%3A00%3A00Z%22%7D&Subject=release%3Ascale&TargetArn=&Version=2010-03-31`,
+		Body:       `Action=Publish&Message=%7B%22action%22%3A%22release%3Ascale%22%2C%22status%22%3A%22success%22%2C%22data%22%3A%7B%22app%22%3A%22httpd%22%2C%22count%22%3A%221%22%2C%22cpu%22%3A%22256%22%2C%22id%22%3A%22RVFETUHHKKD%22%2C%22memory%22%3A%22512%22%2C%22process%22%3A%22web%22%7D%2C%22timestamp%22%3A%220001-01-01T00%3A00%3A00Z%22%7D&Subject=release%3Ascale&TargetArn=&Version=2010-03-31`,
 	},
 	Response: awsutil.Response{
 		StatusCode: 200,
@@ -849,3 +922,24 @@ var cycleFormationUpdateStack = awsutil.Cycle{
 		`,
 	},
 }
+
+// cycleFormationDescribeStacksAutoscale is an app stack from a release that supports autoscaling
+var cycleFormationDescribeStacksAutoscale = awsutil.Cycle{
+	Request: cycleFormationDescribeStacks.Request,
+	Response: awsutil.Response{
+		StatusCode: 200,
+		Body:       strings.Replace(cycleFormationDescribeStacks.Response.Body, "<Parameters>", "<Parameters><member><ParameterKey>WebAutoscale</ParameterKey><ParameterValue>0,0,0,0</ParameterValue></member>", 1),
+	},
+}
+
+var cycleReleaseGetItemAutoscaleMetric = awsutil.Cycle{
+	Request: awsutil.Request{
+		RequestURI: "/",
+		Operation:  "DynamoDB_20120810.GetItem",
+		Body:       `{"ConsistentRead":true,"Key":{"id":{"S":"RVFETUHHKKD"}},"TableName":"convox-releases"}`,
+	},
+	Response: awsutil.Response{
+		StatusCode: 200,
+		Body:       `{"Item":{"id":{"S":"RVFETUHHKKD"},"build":{"S":"BHINCLZYYVN"},"app":{"S":"httpd"},"manifest":{"S":"web:\n  image: httpd\n  labels:\n    - convox.autoscale.metric=AWS/SQS ApproximateNumberOfMessagesVisible QueueName=jobs\n    - convox.autoscale.target=100\n"},"env":{"S":"foo=bar"},"created":{"S":"20160404.143542.627770380"}}}`,
+	},
+}
@@ -476,11 +476,25 @@ func (p *AWSProvider) s3Put(bucket, key string, data []byte, public bool) error
 		req.ACL = aws.String("public-read")
 	}
 
+	p.s3Encryption(req, public)
+
 	_, err := p.s3().PutObject(req)
 
 	return err
 }
 
+// s3Encryption encrypts an object at rest with the rack encryption key. Public objects
+// are fetched anonymously by url, which kms does not allow, so they use an s3 managed key.
+func (p *AWSProvider) s3Encryption(req *s3.PutObjectInput, public bool) {
+	if public || p.EncryptionKey == "" {
+		req.ServerSideEncryption = aws.String(s3.ServerSideEncryptionAes256)
+		return
+	}
+
+	req.ServerSideEncryption = aws.String(s3.ServerSideEncryptionAwsKms)
+	req.SSEKMSKeyId = aws.String(p.EncryptionKey)
+}
+
 // updateStack updates a stack
 //   template is url to a template or empty string to reuse previous
 //   changes is a list of parameter changes to make (does not need to include every param)
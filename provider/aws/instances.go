@@ -1,18 +1,72 @@
 package aws
 
 import (
+	"encoding/json"
 	"fmt"
+	"math/rand"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/aws/aws-sdk-go/service/cloudwatch"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/convox/rack/api/crypt"
 	"github.com/convox/rack/api/structs"
 )
 
+// InstanceKeyroll generates a new ec2 keypair for the rack instances and keeps its private
+// key in the rack settings so that `convox instances ssh` can use it. The instances are
+// replaced with ones that accept the new key as the rack stack updates.
+func (p *AWSProvider) InstanceKeyroll() error {
+	name := fmt.Sprintf("%s-keypair-%d", p.Rack, (rand.Intn(8999) + 1000))
+
+	keypair, err := p.ec2().CreateKeyPair(&ec2.CreateKeyPairInput{
+		KeyName: aws.String(name),
+	})
+	if err != nil {
+		return err
+	}
+
+	settings, err := p.rackSettings()
+	if err != nil {
+		return err
+	}
+
+	settings["InstancePEM"] = *keypair.KeyMaterial
+
+	if err := p.rackSettingsSave(settings); err != nil {
+		return err
+	}
+
+	return p.updateStack(p.Rack, "", map[string]string{"Key": name})
+}
+
+// InstanceTerminate terminates an instance of the rack. Its autoscaling group launches a
+// replacement so the capacity of the rack stays the same.
+func (p *AWSProvider) InstanceTerminate(id string) error {
+	res, err := p.autoscaling().DescribeAutoScalingInstances(&autoscaling.DescribeAutoScalingInstancesInput{
+		InstanceIds: []*string{aws.String(id)},
+	})
+	if err != nil {
+		return err
+	}
+
+	// only instances in one of the rack's own autoscaling groups can be terminated
+	if len(res.AutoScalingInstances) != 1 || !strings.HasPrefix(*res.AutoScalingInstances[0].AutoScalingGroupName, p.Rack+"-") {
+		return fmt.Errorf("no such instance: %s", id)
+	}
+
+	_, err = p.autoscaling().TerminateInstanceInAutoScalingGroup(&autoscaling.TerminateInstanceInAutoScalingGroupInput{
+		InstanceId:                     aws.String(id),
+		ShouldDecrementDesiredCapacity: aws.Bool(false),
+	})
+
+	return err
+}
+
 func (p *AWSProvider) InstanceList() (structs.Instances, error) {
 	ecsRes, err := p.describeContainerInstances()
 
@@ -166,3 +220,44 @@ func (p *AWSProvider) describeContainerInstances() (*ecs.DescribeContainerInstan
 		ContainerInstances: instances,
 	}, nil
 }
+
+// rackSettings returns the settings the rack keeps encrypted in its settings bucket
+func (p *AWSProvider) rackSettings() (map[string]string, error) {
+	settings := map[string]string{}
+
+	data, err := p.s3Get(p.SettingsBucket, "env")
+	if awsError, ok := err.(awserr.RequestFailure); ok && awsError.StatusCode() == 404 {
+		return settings, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if p.EncryptionKey != "" {
+		if d, err := crypt.New(p.Region, p.Access, p.Secret).Decrypt(p.EncryptionKey, data); err == nil {
+			data = d
+		}
+	}
+
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+func (p *AWSProvider) rackSettingsSave(settings map[string]string) error {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+
+	if p.EncryptionKey != "" {
+		data, err = crypt.New(p.Region, p.Access, p.Secret).Encrypt(p.EncryptionKey, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	return p.s3Put(p.SettingsBucket, "env", data, false)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/convox/rack/api/awsutil"
 	"github.com/convox/rack/api/structs"
+	"github.com/convox/rack/test"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -61,6 +62,60 @@ func TestInstancesList(t *testing.T) {
 	}, is)
 }
 
+func TestInstanceTerminate(t *testing.T) {
+	provider := StubAwsProvider(
+		cycleInstanceDescribeAutoScaling("i-4a5513f4", "convox-Instances-1NCWX9EC0JOV4"),
+		test.DeleteInstanceCycle("i-4a5513f4"),
+	)
+	defer provider.Close()
+
+	err := provider.InstanceTerminate("i-4a5513f4")
+
+	assert.NoError(t, err)
+}
+
+func TestInstanceTerminateOtherGroup(t *testing.T) {
+	provider := StubAwsProvider(
+		cycleInstanceDescribeAutoScaling("i-4a5513f4", "other-Instances-1NCWX9EC0JOV4"),
+	)
+	defer provider.Close()
+
+	err := provider.InstanceTerminate("i-4a5513f4")
+
+	assert.EqualError(t, err, "no such instance: i-4a5513f4")
+}
+
+func cycleInstanceDescribeAutoScaling(id, group string) awsutil.Cycle {
+	return awsutil.Cycle{
+		Request: awsutil.Request{
+			RequestURI: "/",
+			Body:       `Action=DescribeAutoScalingInstances&InstanceIds.member.1=` + id + `&Version=2011-01-01`,
+		},
+		Response: awsutil.Response{
+			StatusCode: 200,
+			Body: `
+				<DescribeAutoScalingInstancesResponse xmlns="http://autoscaling.amazonaws.com/doc/2011-01-01/">
+					<DescribeAutoScalingInstancesResult>
+						<AutoScalingInstances>
+							<member>
+								<AutoScalingGroupName>` + group + `</AutoScalingGroupName>
+								<AvailabilityZone>us-east-1a</AvailabilityZone>
+								<HealthStatus>HEALTHY</HealthStatus>
+								<InstanceId>` + id + `</InstanceId>
+								<LaunchConfigurationName>convox-LaunchConfiguration-1X8AV0ZJ3WO2B</LaunchConfigurationName>
+								<LifecycleState>InService</LifecycleState>
+							</member>
+						</AutoScalingInstances>
+					</DescribeAutoScalingInstancesResult>
+					<ResponseMetadata>
+						<RequestId>9f2a5b4e-9bd8-11e6-9f33-a24fc0d9ad94</RequestId>
+					</ResponseMetadata>
+				</DescribeAutoScalingInstancesResponse>
+			`,
+		},
+	}
+}
+
 func listContainerInstancesCycle(clusterName string) awsutil.Cycle {
 	return awsutil.Cycle{
 		awsutil.Request{"/", "AmazonEC2ContainerServiceV20141113.ListContainerInstances",
@@ -99,6 +99,11 @@ func ECSTaskDefinitionCreate(req Request) (string, map[string]string, error) {
 		Family: aws.String(req.ResourceProperties["Name"].(string)),
 	}
 
+	// the task role lets the entrypoint of the image read the secrets of the app
+	if role, ok := req.ResourceProperties["TaskRole"].(string); ok && role != "" {
+		r.TaskRoleArn = aws.String(role)
+	}
+
 	// get environment from S3 URL
 	// 'Environment' is a CloudFormation Template Property that references 'Environment' CF Parameter with S3 URL
 	// S3 body may be encrypted with KMS key
@@ -204,6 +209,12 @@ func ECSTaskDefinitionCreate(req Request) (string, map[string]string, error) {
 			})
 		}
 
+		// ${secret:NAME} values are resolved by the entrypoint of the image
+		r.ContainerDefinitions[i].Environment, err = secretsEnvironment(req, r.ContainerDefinitions[i].Environment)
+		if err != nil {
+			return "invalid", nil, err
+		}
+
 		// set Release value in Task environment
 		if release, ok := req.ResourceProperties["Release"].(string); ok {
 			r.ContainerDefinitions[i].Environment = append(r.ContainerDefinitions[i].Environment, &ecs.KeyValuePair{
@@ -281,6 +292,32 @@ func ECSTaskDefinitionCreate(req Request) (string, map[string]string, error) {
 	return *res.TaskDefinition.TaskDefinitionArn, nil, nil
 }
 
+// secretsEnvironment tells the entrypoint of the image where to find the secrets an environment
+// refers to. The references are left in place and resolved with the task role when the container
+// starts, so secret values never appear in the task definition.
+func secretsEnvironment(req Request, env []*ecs.KeyValuePair) ([]*ecs.KeyValuePair, error) {
+	refs := false
+
+	for _, kv := range env {
+		if models.SecretReference.MatchString(*kv.Value) {
+			refs = true
+			break
+		}
+	}
+
+	if !refs {
+		return env, nil
+	}
+
+	bucket, _ := req.ResourceProperties["Settings"].(string)
+
+	if bucket == "" {
+		return nil, fmt.Errorf("can not refer to secrets without a settings bucket")
+	}
+
+	return append(env, &ecs.KeyValuePair{Name: aws.String("SECRETS_BUCKET"), Value: aws.String(bucket)}), nil
+}
+
 func ECSTaskDefinitionDelete(req Request) (string, map[string]string, error) {
 	// We have observed a race condition quickly deregistering then re-registering
 	// Task Definitions, where the Register fails. We work around this by not
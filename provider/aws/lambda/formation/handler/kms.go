@@ -5,6 +5,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/kms"
 )
 
@@ -39,15 +40,30 @@ func KMSKeyCreate(req Request) (string, map[string]string, error) {
 		return "invalid", nil, err
 	}
 
+	if err := kmsKeyRotation(req, *res.KeyMetadata.Arn); err != nil {
+		return *res.KeyMetadata.Arn, nil, err
+	}
+
 	return *res.KeyMetadata.Arn, nil, nil
 }
 
 func KMSKeyUpdate(req Request) (string, map[string]string, error) {
-	return req.PhysicalResourceId, nil, nil
+	return req.PhysicalResourceId, nil, kmsKeyRotation(req, req.PhysicalResourceId)
 }
 
 func KMSKeyDelete(req Request) (string, map[string]string, error) {
-	_, err := KMS(req).DisableKey(&kms.DisableKeyInput{
+	// a key that is removed during an update, i.e. by a CustomEncryptionKey parameter,
+	// still decrypts everything stored before so only disable it with the stack
+	deleting, err := stackDeleting(req)
+	if err != nil {
+		return req.PhysicalResourceId, nil, err
+	}
+
+	if !deleting {
+		return req.PhysicalResourceId, nil, nil
+	}
+
+	_, err = KMS(req).DisableKey(&kms.DisableKeyInput{
 		KeyId: aws.String(req.PhysicalResourceId),
 	})
 
@@ -70,3 +86,32 @@ func KMSKeyDelete(req Request) (string, map[string]string, error) {
 
 	return req.PhysicalResourceId, nil, err
 }
+
+// kmsKeyRotation has KMS rotate the key material every year when the KeyRotation property is set.
+// Older material is kept so data encrypted before a rotation can still be decrypted.
+func kmsKeyRotation(req Request, key string) error {
+	if rotation, ok := req.ResourceProperties["KeyRotation"].(string); !ok || rotation != "true" {
+		return nil
+	}
+
+	_, err := KMS(req).EnableKeyRotation(&kms.EnableKeyRotationInput{
+		KeyId: aws.String(key),
+	})
+
+	return err
+}
+
+func stackDeleting(req Request) (bool, error) {
+	res, err := CloudFormation(req).DescribeStacks(&cloudformation.DescribeStacksInput{
+		StackName: aws.String(req.StackId),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if len(res.Stacks) != 1 {
+		return false, fmt.Errorf("could not find stack: %s", req.StackId)
+	}
+
+	return *res.Stacks[0].StackStatus == cloudformation.StackStatusDeleteInProgress, nil
+}
@@ -0,0 +1,69 @@
+package aws
+
+import (
+	"encoding/json"
+
+	"github.com/convox/rack/api/structs"
+)
+
+// logScrubKey is where the log scrub rules of an app are kept in its settings bucket
+const logScrubKey = "logs/scrub.json"
+
+// LogScrubList returns the rules used to redact the logs of an app
+func (p *AWSProvider) LogScrubList(app string) (structs.LogScrubRules, error) {
+	a, err := p.AppGet(app)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.logScrubRules(a)
+}
+
+// LogScrubSave replaces the rules used to redact the logs of an app
+func (p *AWSProvider) LogScrubSave(app string, rules structs.LogScrubRules) error {
+	a, err := p.AppGet(app)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range rules {
+		if err := r.Validate(); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(rules)
+	if err != nil {
+		return err
+	}
+
+	return p.s3Put(a.Outputs["Settings"], logScrubKey, data, false)
+}
+
+func (p *AWSProvider) logScrubRules(a *structs.App) (structs.LogScrubRules, error) {
+	rules := structs.LogScrubRules{}
+
+	data, err := p.s3Get(a.Outputs["Settings"], logScrubKey)
+	if awsError(err) == "NoSuchKey" {
+		return rules, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// logScrubber returns a scrubber for the rules of an app
+func (p *AWSProvider) logScrubber(a *structs.App) (*structs.LogScrubber, error) {
+	rules, err := p.logScrubRules(a)
+	if err != nil {
+		return nil, err
+	}
+
+	return structs.NewLogScrubber(rules)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -18,7 +19,14 @@ func (p *AWSProvider) LogStream(app string, w io.Writer, opts structs.LogStreamO
 		return err
 	}
 
-	return p.subscribeLogs(w, a.Outputs["LogGroup"], opts)
+	// app containers send their output straight to cloudwatch so apply the
+	// scrub rules as logs leave the rack
+	scrubber, err := p.logScrubber(a)
+	if err != nil {
+		return err
+	}
+
+	return p.subscribeLogs(scrubber.Writer(w), a.Outputs["LogGroup"], opts)
 }
 
 func (p *AWSProvider) subscribeLogs(w io.Writer, group string, opts structs.LogStreamOptions) error {
@@ -35,7 +43,7 @@ func (p *AWSProvider) subscribeLogs(w io.Writer, group string, opts structs.LogS
 	start := time.Now().Add(-since).UnixNano() / int64(time.Millisecond)
 
 	for {
-		s, err := p.fetchLogs(w, group, opts.Filter, start)
+		s, err := p.fetchLogs(w, group, opts, start)
 		if err != nil {
 			return err
 		}
@@ -51,7 +59,7 @@ func (p *AWSProvider) subscribeLogs(w io.Writer, group string, opts structs.LogS
 }
 
 // fetch logs until we run out of NextTokens, writing them the whole way
-func (p *AWSProvider) fetchLogs(w io.Writer, group, filter string, start int64) (int64, error) {
+func (p *AWSProvider) fetchLogs(w io.Writer, group string, opts structs.LogStreamOptions, start int64) (int64, error) {
 	log := Logger.At("fetchLogs").Namespace("start=%d", start).Start()
 
 	req := &cloudwatchlogs.FilterLogEventsInput{
@@ -60,8 +68,8 @@ func (p *AWSProvider) fetchLogs(w io.Writer, group, filter string, start int64)
 		StartTime:    aws.Int64(start),
 	}
 
-	if filter != "" {
-		req.FilterPattern = aws.String(filter)
+	if opts.Filter != "" {
+		req.FilterPattern = aws.String(opts.Filter)
 	}
 
 	for {
@@ -77,7 +85,7 @@ func (p *AWSProvider) fetchLogs(w io.Writer, group, filter string, start int64)
 			return 0, err
 		}
 
-		latest, err := p.writeLogEvents(w, res.Events)
+		latest, err := p.writeLogEvents(w, res.Events, opts.Process)
 		if err != nil {
 			log.Error(err)
 			return 0, err
@@ -98,7 +106,9 @@ func (p *AWSProvider) fetchLogs(w io.Writer, group, filter string, start int64)
 	return start, nil
 }
 
-func (p *AWSProvider) writeLogEvents(w io.Writer, events []*cloudwatchlogs.FilteredLogEvent) (int64, error) {
+// writeLogEvents writes events labelled with the stream they came from, which is named
+// process:release/container. When process is set events from other processes are skipped.
+func (p *AWSProvider) writeLogEvents(w io.Writer, events []*cloudwatchlogs.FilteredLogEvent, process string) (int64, error) {
 	if len(events) == 0 {
 		return 0, nil
 	}
@@ -115,10 +125,16 @@ func (p *AWSProvider) writeLogEvents(w io.Writer, events []*cloudwatchlogs.Filte
 			latest = *e.Timestamp
 		}
 
+		stream := aws.StringValue(e.LogStreamName)
+
+		if process != "" && !strings.HasPrefix(stream, process+":") {
+			continue
+		}
+
 		sec := *e.Timestamp / 1000
 		nsec := *e.Timestamp - (sec * 1000)
 		t := time.Unix(sec, nsec)
-		line := fmt.Sprintf("%s %s\n", t.Format(time.RFC3339), *e.Message)
+		line := fmt.Sprintf("%s %s %s\n", t.Format(time.RFC3339), stream, *e.Message)
 
 		if _, err := w.Write([]byte(line)); err != nil {
 			log.Error(err)
@@ -0,0 +1,67 @@
+package aws_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/convox/rack/api/awsutil"
+	"github.com/convox/rack/api/structs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSystemLogsProcess(t *testing.T) {
+	provider := StubAwsProvider(
+		cycleLogsDescribeStacks,
+		cycleLogsFilterLogEvents,
+	)
+	defer provider.Close()
+
+	buf := &bytes.Buffer{}
+
+	err := provider.SystemLogs(buf, structs.LogStreamOptions{Process: "web", Follow: false})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "2016-04-04T14:35:42Z web:RVFETUHHKKD/4b7bd7dbbc3b started\n2016-04-04T14:35:44Z web:RVFETUHHKKD/9a1c07aa7f2d ERROR timeout\n", buf.String())
+}
+
+var cycleLogsDescribeStacks = awsutil.Cycle{
+	Request: awsutil.Request{
+		RequestURI: "/",
+		Body:       `Action=DescribeStacks&StackName=convox&Version=2010-05-15`,
+	},
+	Response: awsutil.Response{
+		StatusCode: 200,
+		Body: `<DescribeStacksResponse xmlns="http://cloudformation.amazonaws.com/doc/2010-05-15/">
+			<DescribeStacksResult>
+				<Stacks>
+					<member>
+						<Outputs>
+							<member>
+								<OutputKey>LogGroup</OutputKey>
+								<OutputValue>convox-LogGroup-L4V203L35WRM</OutputValue>
+							</member>
+						</Outputs>
+						<StackName>convox</StackName>
+						<StackStatus>UPDATE_COMPLETE</StackStatus>
+					</member>
+				</Stacks>
+			</DescribeStacksResult>
+		</DescribeStacksResponse>`,
+	},
+}
+
+var cycleLogsFilterLogEvents = awsutil.Cycle{
+	Request: awsutil.Request{
+		RequestURI: "/",
+		Operation:  "Logs_20140328.FilterLogEvents",
+		Body:       `/^{"interleaved":true,"logGroupName":"convox-LogGroup-L4V203L35WRM","startTime":\d+}$/`,
+	},
+	Response: awsutil.Response{
+		StatusCode: 200,
+		Body: `{"events":[
+			{"logStreamName":"web:RVFETUHHKKD/9a1c07aa7f2d","timestamp":1459780544000,"message":"ERROR timeout"},
+			{"logStreamName":"worker:RVFETUHHKKD/0c5e3d9b8a71","timestamp":1459780543000,"message":"working"},
+			{"logStreamName":"web:RVFETUHHKKD/4b7bd7dbbc3b","timestamp":1459780542000,"message":"started"}
+		]}`,
+	},
+}
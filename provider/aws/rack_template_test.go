@@ -0,0 +1,316 @@
+package aws_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rackTemplate loads the formation template racks are installed and updated with
+func rackTemplate(t *testing.T) map[string]interface{} {
+	data, err := ioutil.ReadFile("dist/rack.json")
+	require.NoError(t, err)
+
+	var tmpl map[string]interface{}
+
+	require.NoError(t, json.Unmarshal(data, &tmpl))
+
+	return tmpl
+}
+
+// templateValue returns the part of a template at a path like Resources/RackWeb/Properties/Cluster,
+// where numbers index into lists
+func templateValue(t *testing.T, tmpl map[string]interface{}, path string) interface{} {
+	var v interface{} = tmpl
+
+	for _, key := range strings.Split(path, "/") {
+		switch vv := v.(type) {
+		case map[string]interface{}:
+			next, ok := vv[key]
+			require.True(t, ok, "%s: no %s", path, key)
+			v = next
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			require.NoError(t, err, path)
+			require.True(t, i < len(vv), "%s: no %s", path, key)
+			v = vv[i]
+		default:
+			t.Fatalf("%s: can not find %s in %v", path, key, v)
+		}
+	}
+
+	return v
+}
+
+// templateJSON returns the compact json of a part of a template
+func templateJSON(t *testing.T, tmpl map[string]interface{}, path string) string {
+	data, err := json.Marshal(templateValue(t, tmpl, path))
+	require.NoError(t, err)
+
+	return string(data)
+}
+
+// templateRender evaluates a part of a template the way CloudFormation would for a stack with
+// params, filling in defaults for the rest. Attributes of resources render as Resource.Attribute
+// and resources as their logical id.
+func templateRender(t *testing.T, tmpl map[string]interface{}, path string, params map[string]string) string {
+	values := map[string]string{}
+
+	for name, p := range tmpl["Parameters"].(map[string]interface{}) {
+		if def, ok := p.(map[string]interface{})["Default"]; ok {
+			values[name] = fmt.Sprintf("%v", def)
+		}
+	}
+
+	for k, v := range params {
+		values[k] = v
+	}
+
+	values["AWS::Region"] = "us-east-1"
+	values["AWS::StackName"] = "convox"
+
+	conditions := tmpl["Conditions"].(map[string]interface{})
+
+	var render func(v interface{}) interface{}
+	var condition func(name string) bool
+
+	condition = func(name string) bool {
+		c, ok := conditions[name]
+		require.True(t, ok, "no condition %s", name)
+		return render(c).(bool)
+	}
+
+	render = func(v interface{}) interface{} {
+		switch vv := v.(type) {
+		case map[string]interface{}:
+			for fn, args := range vv {
+				switch fn {
+				case "Ref":
+					if value, ok := values[args.(string)]; ok {
+						return value
+					}
+					return args.(string)
+				case "Condition":
+					return condition(args.(string))
+				case "Fn::Base64":
+					return render(args)
+				case "Fn::GetAtt":
+					return fmt.Sprintf("%s.%s", args.([]interface{})[0], args.([]interface{})[1])
+				case "Fn::FindInMap":
+					return "map"
+				case "Fn::Equals":
+					return fmt.Sprintf("%v", render(args.([]interface{})[0])) == fmt.Sprintf("%v", render(args.([]interface{})[1]))
+				case "Fn::Not":
+					return !render(args.([]interface{})[0]).(bool)
+				case "Fn::And", "Fn::Or":
+					all, any := true, false
+					for _, c := range args.([]interface{}) {
+						b := render(c).(bool)
+						all, any = all && b, any || b
+					}
+					return (fn == "Fn::And" && all) || (fn == "Fn::Or" && any)
+				case "Fn::If":
+					if condition(args.([]interface{})[0].(string)) {
+						return render(args.([]interface{})[1])
+					}
+					return render(args.([]interface{})[2])
+				case "Fn::Join":
+					parts := []string{}
+					for _, part := range args.([]interface{})[1].([]interface{}) {
+						if r := render(part); r != "AWS::NoValue" {
+							parts = append(parts, fmt.Sprintf("%v", r))
+						}
+					}
+					return strings.Join(parts, args.([]interface{})[0].(string))
+				}
+			}
+			t.Fatalf("%s: can not render %v", path, vv)
+		case []interface{}:
+			list := []interface{}{}
+			for _, item := range vv {
+				list = append(list, render(item))
+			}
+			return list
+		}
+
+		return v
+	}
+
+	return fmt.Sprintf("%v", render(templateValue(t, tmpl, path)))
+}
+
+func TestRackTemplateReferences(t *testing.T) {
+	tmpl := rackTemplate(t)
+
+	params := tmpl["Parameters"].(map[string]interface{})
+	conditions := tmpl["Conditions"].(map[string]interface{})
+	resources := tmpl["Resources"].(map[string]interface{})
+
+	var walk func(path string, v interface{})
+
+	walk = func(path string, v interface{}) {
+		switch vv := v.(type) {
+		case map[string]interface{}:
+			for k, child := range vv {
+				switch k {
+				case "Ref":
+					if name, ok := child.(string); ok && !strings.HasPrefix(name, "AWS::") {
+						_, param := params[name]
+						_, resource := resources[name]
+						assert.True(t, param || resource, "%s: Ref to unknown %s", path, name)
+					}
+				case "Fn::GetAtt":
+					name := child.([]interface{})[0].(string)
+					assert.Contains(t, resources, name, "%s: GetAtt of unknown resource", path)
+				case "Fn::If":
+					name := child.([]interface{})[0].(string)
+					assert.Contains(t, conditions, name, "%s: If on unknown condition", path)
+				case "Condition":
+					if name, ok := child.(string); ok {
+						assert.Contains(t, conditions, name, "%s: unknown condition", path)
+					}
+				}
+
+				walk(path+"/"+k, child)
+			}
+		case []interface{}:
+			for i, child := range vv {
+				walk(path+"/"+strconv.Itoa(i), child)
+			}
+		}
+	}
+
+	walk("Conditions", conditions)
+	walk("Resources", resources)
+	walk("Outputs", tmpl["Outputs"])
+}
+
+func TestRackTemplateDedicatedControlPlane(t *testing.T) {
+	tmpl := rackTemplate(t)
+
+	assert.Equal(t, "No", templateValue(t, tmpl, "Parameters/DedicatedControlPlane/Default"))
+
+	for _, r := range []string{"ControlCluster", "ControlLaunchConfiguration", "ControlInstances"} {
+		assert.Equal(t, "DedicatedControlPlane", templateValue(t, tmpl, "Resources/"+r+"/Condition"), r)
+	}
+
+	assert.Equal(t, `{"Ref":"ControlInstanceType"}`, templateJSON(t, tmpl, "Resources/ControlLaunchConfiguration/Properties/InstanceType"))
+	assert.Contains(t, templateJSON(t, tmpl, "Resources/ControlLaunchConfiguration/Properties/UserData"), `"  - echo ECS_CLUSTER=",{"Ref":"ControlCluster"}`)
+
+	// the rack runs in the control cluster and tells the api where to look for its own processes
+	for _, r := range []string{"RackWeb", "RackMonitor"} {
+		assert.Equal(t, `{"Fn::If":["DedicatedControlPlane",{"Ref":"ControlCluster"},{"Ref":"Cluster"}]}`, templateJSON(t, tmpl, "Resources/"+r+"/Properties/Cluster"), r)
+		assert.Equal(t, `{"Fn::If":["DedicatedControlPlane",{"Ref":"ControlCluster"},""]}`, templateJSON(t, tmpl, "Resources/"+r+"Tasks/Properties/Tasks/0/Environment/CONTROL_CLUSTER"), r)
+	}
+
+	// apps stay on the app instances
+	assert.Contains(t, templateJSON(t, tmpl, "Resources/LaunchConfiguration/Properties/UserData"), `"  - echo ECS_CLUSTER=",{"Ref":"Cluster"}`)
+}
+
+func TestRackTemplateRegistryCache(t *testing.T) {
+	tmpl := rackTemplate(t)
+
+	assert.Equal(t, "No", templateValue(t, tmpl, "Parameters/RegistryCache/Default"))
+
+	for _, r := range []string{"RackCache", "RackCacheTasks", "RegistryCacheBalancer", "RegistryCacheBalancerSecurityGroup"} {
+		assert.Equal(t, "RegistryCache", templateValue(t, tmpl, "Resources/"+r+"/Condition"), r)
+	}
+
+	// the cache proxies docker hub into the registry bucket
+	env := templateValue(t, tmpl, "Resources/RackCacheTasks/Properties/Tasks/0/Environment").(map[string]interface{})
+	assert.Equal(t, "https://registry-1.docker.io", env["REGISTRY_PROXY_REMOTEURL"])
+	assert.Equal(t, "/cache", env["REGISTRY_STORAGE_S3_ROOTDIRECTORY"])
+	assert.Equal(t, "4102:5000", templateValue(t, tmpl, "Resources/RackCacheTasks/Properties/Tasks/0/PortMappings/0"))
+
+	// it is only reachable from inside the vpc
+	assert.Equal(t, "internal", templateValue(t, tmpl, "Resources/RegistryCacheBalancer/Properties/Scheme"))
+	assert.Equal(t, `[{"CidrIp":{"Ref":"VPCCIDR"},"FromPort":"5001","IpProtocol":"tcp","ToPort":"5001"}]`, templateJSON(t, tmpl, "Resources/RegistryCacheBalancerSecurityGroup/Properties/SecurityGroupIngress"))
+	assert.Equal(t, "4102", templateValue(t, tmpl, "Resources/RegistryCacheBalancer/Properties/Listeners/0/InstancePort"))
+	assert.Equal(t, `{"Ref":"RegistryCacheBalancer"}`, templateJSON(t, tmpl, "Resources/RackCache/Properties/LoadBalancers/0/LoadBalancerName"))
+
+	// every instance pulls through the cache when it is enabled
+	for _, r := range []string{"LaunchConfiguration", "ControlLaunchConfiguration"} {
+		assert.Contains(t, templateJSON(t, tmpl, "Resources/"+r+"/Properties/UserData"), `{"Fn::If":["RegistryCache",{"Fn::Join":["",["  - echo 'OPTIONS=\"${OPTIONS} --registry-mirror=http://",{"Fn::GetAtt":["RegistryCacheBalancer","DNSName"]},":5001`, r)
+	}
+}
+
+func TestRackTemplateVpcEndpoints(t *testing.T) {
+	tmpl := rackTemplate(t)
+
+	assert.Equal(t, "No", templateValue(t, tmpl, "Parameters/VpcGatewayEndpoints/Default"))
+	assert.Equal(t, "No", templateValue(t, tmpl, "Parameters/VpcInterfaceEndpoints/Default"))
+
+	// gateway endpoints attach to the route tables of the vpc the rack created
+	for service, r := range map[string]string{"s3": "VpcEndpointS3", "dynamodb": "VpcEndpointDynamoDB"} {
+		assert.Equal(t, "VpcGatewayEndpoints", templateValue(t, tmpl, "Resources/"+r+"/Condition"), r)
+		assert.Equal(t, `{"Fn::Join":[".",["com.amazonaws",{"Ref":"AWS::Region"},"`+service+`"]]}`, templateJSON(t, tmpl, "Resources/"+r+"/Properties/ServiceName"), r)
+		assert.Contains(t, templateJSON(t, tmpl, "Resources/"+r+"/Properties/RouteTableIds"), `{"Fn::If":["BlankExistingVpc",{"Ref":"Routes"},{"Ref":"AWS::NoValue"}]}`, r)
+		assert.Contains(t, templateJSON(t, tmpl, "Resources/"+r+"/Properties/RouteTableIds"), `{"Fn::If":["Private",{"Ref":"RouteTablePrivate0"},{"Ref":"AWS::NoValue"}]}`, r)
+	}
+
+	// an existing vpc has route tables the rack does not know about unless its instances are private
+	assert.Contains(t, templateJSON(t, tmpl, "Conditions/VpcGatewayEndpoints"), `{"Fn::Or":[{"Condition":"BlankExistingVpc"},{"Condition":"Private"}]}`)
+
+	// interface endpoints resolve the service names to private addresses inside the vpc
+	for service, r := range map[string]string{"ecr.api": "VpcEndpointEcrApi", "ecr.dkr": "VpcEndpointEcrDkr", "logs": "VpcEndpointLogs"} {
+		assert.Equal(t, "VpcInterfaceEndpoints", templateValue(t, tmpl, "Resources/"+r+"/Condition"), r)
+		assert.Equal(t, "Interface", templateValue(t, tmpl, "Resources/"+r+"/Properties/VpcEndpointType"), r)
+		assert.Equal(t, true, templateValue(t, tmpl, "Resources/"+r+"/Properties/PrivateDnsEnabled"), r)
+		assert.Equal(t, `{"Fn::Join":[".",["com.amazonaws",{"Ref":"AWS::Region"},"`+service+`"]]}`, templateJSON(t, tmpl, "Resources/"+r+"/Properties/ServiceName"), r)
+		assert.Equal(t, `[{"Ref":"VpcEndpointSecurityGroup"}]`, templateJSON(t, tmpl, "Resources/"+r+"/Properties/SecurityGroupIds"), r)
+	}
+
+	assert.Equal(t, `[{"CidrIp":{"Ref":"VPCCIDR"},"FromPort":"443","IpProtocol":"tcp","ToPort":"443"}]`, templateJSON(t, tmpl, "Resources/VpcEndpointSecurityGroup/Properties/SecurityGroupIngress"))
+}
+
+func TestRackTemplateInstanceOptions(t *testing.T) {
+	tmpl := rackTemplate(t)
+
+	for _, r := range []string{"LaunchConfiguration", "ControlLaunchConfiguration"} {
+		path := "Resources/" + r + "/Properties/UserData"
+
+		// the defaults keep the open file limit and leave docker and the kernel alone
+		data := templateRender(t, tmpl, path, map[string]string{"DedicatedControlPlane": "Yes"})
+
+		assert.Contains(t, data, "  - echo 'OPTIONS=\"--default-ulimit nofile=1024000:1024000\"' >> /etc/sysconfig/docker\n", r)
+		assert.NotContains(t, data, "--max-concurrent-downloads", r)
+		assert.NotContains(t, data, "net.core.somaxconn", r)
+		assert.NotContains(t, data, "nf_conntrack", r)
+		assert.Contains(t, data, "  - touch /etc/sysctl.d/99-convox.conf && sysctl -p /etc/sysctl.d/99-convox.conf\n", r)
+
+		data = templateRender(t, tmpl, path, map[string]string{
+			"DedicatedControlPlane":        "Yes",
+			"DockerMaxConcurrentDownloads": "10",
+			"DockerOptions":                "--storage-opt dm.loopdatasize=200G",
+			"InstanceConntrackMax":         "262144",
+			"InstanceNofileLimit":          "65536",
+			"InstanceSomaxconn":            "4096",
+		})
+
+		assert.Contains(t, data, "  - echo 'OPTIONS=\"--default-ulimit nofile=65536:65536\"' >> /etc/sysconfig/docker\n", r)
+		assert.Contains(t, data, "  - echo 'OPTIONS=\"${OPTIONS} --max-concurrent-downloads=10\"' >> /etc/sysconfig/docker\n", r)
+		assert.Contains(t, data, "  - echo 'OPTIONS=\"${OPTIONS} --storage-opt dm.loopdatasize=200G\"' >> /etc/sysconfig/docker\n", r)
+		assert.Contains(t, data, "  - echo 'net.core.somaxconn = 4096' >> /etc/sysctl.d/99-convox.conf\n", r)
+		assert.Contains(t, data, "  - modprobe nf_conntrack\n  - echo 'net.netfilter.nf_conntrack_max = 262144' >> /etc/sysctl.d/99-convox.conf\n", r)
+
+		// sysctls are written before they are loaded
+		assert.True(t, strings.Index(data, "net.core.somaxconn") < strings.Index(data, "sysctl -p"), r)
+	}
+}
+
+func TestRackTemplateBuildImage(t *testing.T) {
+	tmpl := rackTemplate(t)
+
+	for _, r := range []string{"RackWebTasks", "RackMonitorTasks"} {
+		path := "Resources/" + r + "/Properties/Tasks/0/Environment/BUILD_IMAGE"
+
+		assert.Equal(t, "convox/api:20170101000000", templateRender(t, tmpl, path, map[string]string{"Version": "20170101000000"}), r)
+		assert.Equal(t, "example/builder:1", templateRender(t, tmpl, path, map[string]string{"Version": "20170101000000", "BuildImage": "example/builder:1"}), r)
+	}
+}
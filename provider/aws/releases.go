@@ -1,17 +1,30 @@
 package aws
 
 import (
-	"bytes"
 	"fmt"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
-	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/convox/rack/api/crypt"
 	"github.com/convox/rack/api/structs"
 )
 
+// ReleaseDiff returns what promoting release to in place of release from would change
+func (p *AWSProvider) ReleaseDiff(app, from, to string) (*structs.ReleaseDiff, error) {
+	fr, err := p.ReleaseGet(app, from)
+	if err != nil {
+		return nil, err
+	}
+
+	tr, err := p.ReleaseGet(app, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return structs.DiffReleases(*fr, *tr)
+}
+
 // ReleaseGet returns a release
 func (p *AWSProvider) ReleaseGet(app, id string) (*structs.Release, error) {
 	if id == "" {
@@ -41,6 +54,11 @@ func (p *AWSProvider) ReleaseGet(app, id string) (*structs.Release, error) {
 
 	release := releaseFromItem(res.Item)
 
+	release.Env, err = p.releaseEnv(res.Item)
+	if err != nil {
+		return nil, err
+	}
+
 	return release, nil
 }
 
@@ -75,6 +93,11 @@ func (p *AWSProvider) ReleaseList(app string, limit int64) (structs.Releases, er
 
 	for i, item := range res.Items {
 		releases[i] = *releaseFromItem(item)
+
+		releases[i].Env, err = p.releaseEnv(item)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return releases, nil
@@ -116,13 +139,26 @@ func (p *AWSProvider) ReleaseSave(r *structs.Release, bucket, key string) error
 	}
 
 	if r.Env != "" {
-		req.Item["env"] = &dynamodb.AttributeValue{S: aws.String(r.Env)}
+		av, err := p.releaseEnvItem(r.Env)
+		if err != nil {
+			return err
+		}
+
+		if av.B != nil {
+			req.Item["env-encrypted"] = av
+		} else {
+			req.Item["env"] = av
+		}
 	}
 
 	if r.Manifest != "" {
 		req.Item["manifest"] = &dynamodb.AttributeValue{S: aws.String(r.Manifest)}
 	}
 
+	if r.CreatedBy != "" {
+		req.Item["created-by"] = &dynamodb.AttributeValue{S: aws.String(r.CreatedBy)}
+	}
+
 	var err error
 	env := []byte(r.Env)
 
@@ -135,13 +171,7 @@ func (p *AWSProvider) ReleaseSave(r *structs.Release, bucket, key string) error
 		}
 	}
 
-	_, err = p.s3().PutObject(&s3.PutObjectInput{
-		ACL:           aws.String("public-read"),
-		Body:          bytes.NewReader(env),
-		Bucket:        aws.String(bucket),
-		ContentLength: aws.Int64(int64(len(env))),
-		Key:           aws.String(fmt.Sprintf("releases/%s/env", r.Id)),
-	})
+	err = p.s3Put(bucket, fmt.Sprintf("releases/%s/env", r.Id), env, true)
 	if err != nil {
 		return err
 	}
@@ -160,11 +190,44 @@ func releaseFromItem(item map[string]*dynamodb.AttributeValue) *structs.Release
 		Env:      coalesce(item["env"], ""),
 		Manifest: coalesce(item["manifest"], ""),
 		Created:  created,
+
+		CreatedBy: coalesce(item["created-by"], ""),
 	}
 
 	return release
 }
 
+// releaseEnvItem encrypts a release environment with the rack key so it is not
+// kept in plain text in the releases table. Racks without a key store it as is.
+func (p *AWSProvider) releaseEnvItem(env string) (*dynamodb.AttributeValue, error) {
+	if p.EncryptionKey == "" {
+		return &dynamodb.AttributeValue{S: aws.String(env)}, nil
+	}
+
+	data, err := crypt.New(p.Region, p.Access, p.Secret).Encrypt(p.EncryptionKey, []byte(env))
+	if err != nil {
+		return nil, err
+	}
+
+	return &dynamodb.AttributeValue{B: data}, nil
+}
+
+// releaseEnv returns the environment of a release item, decrypting it if it was saved encrypted
+func (p *AWSProvider) releaseEnv(item map[string]*dynamodb.AttributeValue) (string, error) {
+	av := item["env-encrypted"]
+
+	if av == nil || av.B == nil {
+		return coalesce(item["env"], ""), nil
+	}
+
+	data, err := crypt.New(p.Region, p.Access, p.Secret).Decrypt(p.EncryptionKey, av.B)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
 // ReleaseDelete will delete all releases that belong to app and buildID
 // This could includes the active release which implies this should be called with caution.
 func (p *AWSProvider) ReleaseDelete(app, buildID string) error {
@@ -74,3 +74,43 @@ var release1GetItemCycle = awsutil.Cycle{
 		Body:       `{"Item":{"id":{"S":"RVFETUHHKKD"},"build":{"S":"BHINCLZYYVN"},"app":{"S":"httpd"},"manifest":{"S":"web:\n  image: httpd\n  ports:\n  - 80:80\n"},"env":{"S":"foo=bar"},"created":{"S":"20160404.143542.627770380"}}}`,
 	},
 }
+
+func TestReleaseDiff(t *testing.T) {
+	provider := StubAwsProvider(
+		describeStacksCycle,
+		release2GetItemCycle,
+		describeStacksCycle,
+		release1GetItemCycle,
+	)
+	defer provider.Close()
+
+	d, err := provider.ReleaseDiff("httpd", "RFVZFLKVTYO", "RVFETUHHKKD")
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, &structs.ReleaseDiff{
+		App:   "httpd",
+		From:  "RFVZFLKVTYO",
+		To:    "RVFETUHHKKD",
+		Build: &structs.ReleaseDiffBuild{From: "BNOARQMVHUO", To: "BHINCLZYYVN"},
+		Env: structs.ReleaseDiffEntries{
+			{Name: "debug", Change: "removed"},
+			{Name: "foo", Change: "changed"},
+		},
+		Services: structs.ReleaseDiffEntries{
+			{Name: "web", Change: "changed", Fields: []string{"image", "ports"}},
+			{Name: "worker", Change: "removed"},
+		},
+	}, d)
+}
+
+var release2GetItemCycle = awsutil.Cycle{
+	Request: awsutil.Request{
+		RequestURI: "/",
+		Operation:  "DynamoDB_20120810.GetItem",
+		Body:       `{"ConsistentRead":true,"Key":{"id":{"S":"RFVZFLKVTYO"}},"TableName":"convox-releases"}`,
+	},
+	Response: awsutil.Response{
+		StatusCode: 200,
+		Body:       `{"Item":{"id":{"S":"RFVZFLKVTYO"},"build":{"S":"BNOARQMVHUO"},"app":{"S":"httpd"},"manifest":{"S":"web:\n  image: nginx\n  ports:\n  - 8080:80\nworker:\n  image: httpd\n"},"env":{"S":"debug=1\nfoo=baz"},"created":{"S":"20160403.184639.166694813"}}}`,
+	},
+}
@@ -1,18 +1,242 @@
 package aws
 
 import (
+	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os/exec"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/convox/rack/api/structs"
 )
 
+// statefulResources are replaced with downtime or data loss if they are removed from the rack template
+var statefulResources = map[string]bool{
+	"AWS::DynamoDB::Table":                    true,
+	"AWS::EC2::Subnet":                        true,
+	"AWS::EC2::VPC":                           true,
+	"AWS::ECS::Cluster":                       true,
+	"AWS::EFS::FileSystem":                    true,
+	"AWS::ElasticLoadBalancing::LoadBalancer": true,
+	"AWS::KMS::Key":                           true,
+	"AWS::S3::Bucket":                         true,
+}
+
+type rackTemplate struct {
+	Mappings struct {
+		RegionConfig map[string]map[string]string
+	}
+	Parameters map[string]struct {
+		Default interface{}
+	}
+	Resources map[string]struct {
+		Type string
+	}
+}
+
+// SystemCheck runs pre-flight checks for updating the rack to version
+func (p *AWSProvider) SystemCheck(version string) (structs.SystemChecks, error) {
+	stack, err := p.describeStack(p.Rack)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := p.cloudformation().GetTemplate(&cloudformation.GetTemplateInput{
+		StackName: aws.String(p.Rack),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var current rackTemplate
+
+	if err := json.Unmarshal([]byte(*res.TemplateBody), &current); err != nil {
+		return nil, err
+	}
+
+	next, err := fetchRackTemplate(version)
+	if err != nil {
+		return nil, err
+	}
+
+	params := stackParameters(stack)
+
+	checks := structs.SystemChecks{
+		checkTemplate(current, *next),
+		checkParameters(params, *next),
+	}
+
+	ami, err := p.checkAmi(params["Ami"], *next)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(checks, ami), nil
+}
+
+func fetchRackTemplate(version string) (*rackTemplate, error) {
+	res, err := http.Get(fmt.Sprintf("https://convox.s3.amazonaws.com/release/%s/formation.json", version))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return nil, fmt.Errorf("no such version: %s", version)
+	}
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var t rackTemplate
+
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// checkTemplate fails if the update would remove or change the type of a resource apps depend on
+func checkTemplate(current, next rackTemplate) structs.SystemCheck {
+	replaced := []string{}
+	added := 0
+	removed := 0
+
+	for name, r := range current.Resources {
+		nr, ok := next.Resources[name]
+
+		switch {
+		case !ok:
+			removed++
+		case nr.Type != r.Type:
+		default:
+			continue
+		}
+
+		if statefulResources[r.Type] {
+			replaced = append(replaced, name)
+		}
+	}
+
+	for name := range next.Resources {
+		if _, ok := current.Resources[name]; !ok {
+			added++
+		}
+	}
+
+	if len(replaced) > 0 {
+		sort.Strings(replaced)
+
+		return structs.SystemCheck{
+			Name:    "template",
+			Status:  "fail",
+			Message: fmt.Sprintf("would replace resources used by running apps: %s", strings.Join(replaced, ", ")),
+		}
+	}
+
+	return structs.SystemCheck{
+		Name:    "template",
+		Status:  "pass",
+		Message: fmt.Sprintf("%d resources added, %d removed", added, removed),
+	}
+}
+
+// checkParameters warns about parameters the new version drops and fails on new parameters without a default
+func checkParameters(params map[string]string, next rackTemplate) structs.SystemCheck {
+	deprecated := []string{}
+	required := []string{}
+
+	for name := range params {
+		if _, ok := next.Parameters[name]; !ok {
+			deprecated = append(deprecated, name)
+		}
+	}
+
+	for name, np := range next.Parameters {
+		if _, ok := params[name]; !ok && np.Default == nil {
+			required = append(required, name)
+		}
+	}
+
+	sort.Strings(deprecated)
+	sort.Strings(required)
+
+	switch {
+	case len(required) > 0:
+		return structs.SystemCheck{
+			Name:    "parameters",
+			Status:  "fail",
+			Message: fmt.Sprintf("new parameters have no default: %s", strings.Join(required, ", ")),
+		}
+	case len(deprecated) > 0:
+		return structs.SystemCheck{
+			Name:    "parameters",
+			Status:  "warn",
+			Message: fmt.Sprintf("deprecated parameters will be dropped: %s", strings.Join(deprecated, ", ")),
+		}
+	}
+
+	return structs.SystemCheck{Name: "parameters", Status: "pass"}
+}
+
+// checkAmi makes sure a pinned custom AMI still exists and points out when it differs from the version's default
+func (p *AWSProvider) checkAmi(ami string, next rackTemplate) (structs.SystemCheck, error) {
+	def := next.Mappings.RegionConfig[p.Region]["Ami"]
+
+	if ami == "" {
+		if def == "" {
+			return structs.SystemCheck{
+				Name:    "ami",
+				Status:  "fail",
+				Message: fmt.Sprintf("no default ami for region %s", p.Region),
+			}, nil
+		}
+
+		return structs.SystemCheck{Name: "ami", Status: "pass", Message: def}, nil
+	}
+
+	res, err := p.ec2().DescribeImages(&ec2.DescribeImagesInput{
+		ImageIds: []*string{aws.String(ami)},
+	})
+	if awsError(err) == "InvalidAMIID.NotFound" || (err == nil && len(res.Images) == 0) {
+		return structs.SystemCheck{
+			Name:    "ami",
+			Status:  "fail",
+			Message: fmt.Sprintf("custom ami not found: %s", ami),
+		}, nil
+	}
+	if err != nil {
+		return structs.SystemCheck{}, err
+	}
+
+	if ami != def {
+		return structs.SystemCheck{
+			Name:    "ami",
+			Status:  "warn",
+			Message: fmt.Sprintf("custom ami %s is pinned, this version defaults to %s", ami, def),
+		}, nil
+	}
+
+	return structs.SystemCheck{Name: "ami", Status: "pass", Message: ami}, nil
+}
+
 func (p *AWSProvider) SystemGet() (*structs.System, error) {
 	res, err := p.describeStacks(&cloudformation.DescribeStacksInput{
 		StackName: aws.String(p.Rack),
@@ -88,6 +312,11 @@ func (p *AWSProvider) SystemReleases() (structs.Releases, error) {
 
 	for i, item := range res.Items {
 		releases[i] = *releaseFromItem(item)
+
+		releases[i].Env, err = p.releaseEnv(item)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return releases, nil
@@ -157,3 +386,130 @@ func (p *AWSProvider) SystemSave(system structs.System) error {
 
 	return err
 }
+
+// SystemHealth checks that the rack can reach each of the services it depends on
+func (p *AWSProvider) SystemHealth() (structs.SystemChecks, error) {
+	checks := structs.SystemChecks{
+		p.healthCloudFormation(),
+		p.healthDynamoDB(),
+		p.healthECS(),
+		p.healthS3(),
+		p.healthBuilds(),
+		p.healthCertificate(),
+	}
+
+	return checks, nil
+}
+
+func healthResult(name string, err error, message string) structs.SystemCheck {
+	if err != nil {
+		return structs.SystemCheck{Name: name, Status: "fail", Message: err.Error()}
+	}
+
+	return structs.SystemCheck{Name: name, Status: "pass", Message: message}
+}
+
+func (p *AWSProvider) healthCloudFormation() structs.SystemCheck {
+	res, err := p.cloudformation().DescribeStacks(&cloudformation.DescribeStacksInput{
+		StackName: aws.String(p.Rack),
+	})
+	if err != nil {
+		return healthResult("cloudformation", err, "")
+	}
+	if len(res.Stacks) != 1 {
+		return healthResult("cloudformation", fmt.Errorf("could not load stack: %s", p.Rack), "")
+	}
+
+	return healthResult("cloudformation", nil, *res.Stacks[0].StackStatus)
+}
+
+func (p *AWSProvider) healthDynamoDB() structs.SystemCheck {
+	for _, table := range []string{p.DynamoBuilds, p.DynamoReleases} {
+		res, err := p.dynamodb().DescribeTable(&dynamodb.DescribeTableInput{
+			TableName: aws.String(table),
+		})
+		if err != nil {
+			return healthResult("dynamodb", err, "")
+		}
+
+		if status := *res.Table.TableStatus; status != "ACTIVE" && status != "UPDATING" {
+			return healthResult("dynamodb", fmt.Errorf("table %s is %s", table, strings.ToLower(status)), "")
+		}
+	}
+
+	return healthResult("dynamodb", nil, "builds and releases tables active")
+}
+
+func (p *AWSProvider) healthECS() structs.SystemCheck {
+	res, err := p.ecs().DescribeClusters(&ecs.DescribeClustersInput{
+		Clusters: []*string{aws.String(p.Cluster)},
+	})
+	if err != nil {
+		return healthResult("ecs", err, "")
+	}
+	if len(res.Clusters) != 1 {
+		return healthResult("ecs", fmt.Errorf("could not find cluster: %s", p.Cluster), "")
+	}
+
+	c := res.Clusters[0]
+
+	if *c.RegisteredContainerInstancesCount == 0 {
+		return healthResult("ecs", fmt.Errorf("no instances registered in cluster"), "")
+	}
+
+	return healthResult("ecs", nil, fmt.Sprintf("%d instances, %d tasks running", *c.RegisteredContainerInstancesCount, *c.RunningTasksCount))
+}
+
+func (p *AWSProvider) healthS3() structs.SystemCheck {
+	_, err := p.s3().HeadBucket(&s3.HeadBucketInput{
+		Bucket: aws.String(p.SettingsBucket),
+	})
+
+	return healthResult("s3", err, "settings bucket reachable")
+}
+
+// healthBuilds checks that this api process can run build containers
+func (p *AWSProvider) healthBuilds() structs.SystemCheck {
+	if out, err := exec.Command("docker", "version", "--format", "{{.Server.Version}}").CombinedOutput(); err != nil {
+		return healthResult("builds", fmt.Errorf("docker unavailable: %s", strings.TrimSpace(string(out))), "")
+	}
+
+	queued, err := p.buildsQueued()
+	if err != nil {
+		return healthResult("builds", err, "")
+	}
+
+	return healthResult("builds", nil, fmt.Sprintf("%d running, %d queued", buildQueue.stats(), len(queued)))
+}
+
+// healthCertificate checks the expiry of the certificate served by the rack api
+func (p *AWSProvider) healthCertificate() structs.SystemCheck {
+	if p.NotificationHost == "" {
+		return structs.SystemCheck{Name: "certificate", Status: "warn", Message: "rack hostname unknown"}
+	}
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 5 * time.Second}, "tcp", fmt.Sprintf("%s:443", p.NotificationHost), &tls.Config{
+		InsecureSkipVerify: true,
+	})
+	if err != nil {
+		return healthResult("certificate", err, "")
+	}
+	defer conn.Close()
+
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return healthResult("certificate", fmt.Errorf("no certificate presented"), "")
+	}
+
+	expires := certs[0].NotAfter
+	message := fmt.Sprintf("expires %s", expires.Format("2006-01-02"))
+
+	switch left := expires.Sub(time.Now()); {
+	case left < 7*24*time.Hour:
+		return structs.SystemCheck{Name: "certificate", Status: "fail", Message: message}
+	case left < 30*24*time.Hour:
+		return structs.SystemCheck{Name: "certificate", Status: "warn", Message: message}
+	}
+
+	return structs.SystemCheck{Name: "certificate", Status: "pass", Message: message}
+}
@@ -10,15 +10,21 @@ import (
 type Provider interface {
 	AppGet(name string) (*structs.App, error)
 	AppDelete(name string) error
+	AppImportECS(cluster, service string) (*structs.AppImport, error)
 
+	BuildCancel(app, id string) (*structs.Build, error)
 	BuildCopy(srcApp, id, destApp string) (*structs.Build, error)
-	BuildCreateIndex(app string, index structs.Index, manifest, description string, cache bool) (*structs.Build, error)
-	BuildCreateRepo(app, url, manifest, description string, cache bool) (*structs.Build, error)
-	BuildCreateTar(app string, src io.Reader, manifest, description string, cache bool) (*structs.Build, error)
+	BuildCreateIndex(app string, index structs.Index, opts structs.BuildOptions) (*structs.Build, error)
+	BuildCreateRepo(app, url string, opts structs.BuildOptions) (*structs.Build, error)
+	BuildCreateTar(app string, src io.Reader, opts structs.BuildOptions) (*structs.Build, error)
 	BuildDelete(app, id string) (*structs.Build, error)
+	BuildDequeue() error
+	BuildExport(app, id string, w io.Writer) error
 	BuildGet(app, id string) (*structs.Build, error)
+	BuildImport(app string, r io.Reader) (*structs.Build, error)
 	BuildLogs(app, id string) (string, error)
 	BuildList(app string, limit int64) (structs.Builds, error)
+	BuildPrune(app string, retain int, keep []string) (structs.Builds, error)
 	BuildRelease(*structs.Build) (*structs.Release, error)
 	BuildSave(*structs.Build) error
 
@@ -41,11 +47,16 @@ type Provider interface {
 	IndexDownload(*structs.Index, string) error
 	IndexUpload(string, []byte) error
 
+	InstanceKeyroll() error
 	InstanceList() (structs.Instances, error)
+	InstanceTerminate(id string) error
 
+	LogScrubList(app string) (structs.LogScrubRules, error)
+	LogScrubSave(app string, rules structs.LogScrubRules) error
 	LogStream(app string, w io.Writer, opts structs.LogStreamOptions) error
 
 	ReleaseDelete(app, buildID string) error
+	ReleaseDiff(app, from, to string) (*structs.ReleaseDiff, error)
 	ReleaseGet(app, id string) (*structs.Release, error)
 	ReleaseList(app string, limit int64) (structs.Releases, error)
 	ReleasePromote(app, id string) (*structs.Release, error)
@@ -60,7 +71,9 @@ type Provider interface {
 	ServiceUnlink(name, app, process string) (*structs.Service, error)
 	ServiceUpdate(name string, params map[string]string) (*structs.Service, error)
 
+	SystemCheck(version string) (structs.SystemChecks, error)
 	SystemGet() (*structs.System, error)
+	SystemHealth() (structs.SystemChecks, error)
 	SystemReleases() (structs.Releases, error)
 	SystemSave(system structs.System) error
 }
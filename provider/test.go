@@ -11,6 +11,7 @@ import (
 type TestProvider struct {
 	mock.Mock
 	App          structs.App
+	AppImport    structs.AppImport
 	Build        structs.Build
 	Builds       structs.Builds
 	Capacity     structs.Capacity
@@ -36,6 +37,18 @@ func (p *TestProvider) AppDelete(name string) error {
 	return nil
 }
 
+// AppImportECS describes an App equivalent to an ECS service
+func (p *TestProvider) AppImportECS(cluster, service string) (*structs.AppImport, error) {
+	p.Called(cluster, service)
+	return &p.AppImport, nil
+}
+
+// BuildCancel cancels a running Build
+func (p *TestProvider) BuildCancel(app, id string) (*structs.Build, error) {
+	p.Called(app, id)
+	return &p.Build, nil
+}
+
 // BuildCopy copies an App
 func (p *TestProvider) BuildCopy(srcApp, id, destApp string) (*structs.Build, error) {
 	p.Called(srcApp, id, destApp)
@@ -43,20 +56,20 @@ func (p *TestProvider) BuildCopy(srcApp, id, destApp string) (*structs.Build, er
 }
 
 // BuildCreateIndex creates a Build from an Index
-func (p *TestProvider) BuildCreateIndex(app string, index structs.Index, manifest, description string, cache bool) (*structs.Build, error) {
-	p.Called(app, index, manifest, description, cache)
+func (p *TestProvider) BuildCreateIndex(app string, index structs.Index, opts structs.BuildOptions) (*structs.Build, error) {
+	p.Called(app, index, opts)
 	return &p.Build, nil
 }
 
 // BuildCreateRepo creates a Build from a repository URL
-func (p *TestProvider) BuildCreateRepo(app, url, manifest, description string, cache bool) (*structs.Build, error) {
-	p.Called(app, url, manifest, description, cache)
+func (p *TestProvider) BuildCreateRepo(app, url string, opts structs.BuildOptions) (*structs.Build, error) {
+	p.Called(app, url, opts)
 	return &p.Build, nil
 }
 
 // BuildCreateTar creates a Build from a tarball
-func (p *TestProvider) BuildCreateTar(app string, src io.Reader, manifest, description string, cache bool) (*structs.Build, error) {
-	p.Called(app, src, manifest, description, cache)
+func (p *TestProvider) BuildCreateTar(app string, src io.Reader, opts structs.BuildOptions) (*structs.Build, error) {
+	p.Called(app, src, opts)
 	return &p.Build, nil
 }
 
@@ -66,16 +79,34 @@ func (p *TestProvider) BuildDelete(app, id string) (*structs.Build, error) {
 	return &p.Build, nil
 }
 
+// BuildDequeue starts queued Builds
+func (p *TestProvider) BuildDequeue() error {
+	p.Called()
+	return nil
+}
+
+// BuildExport exports a Build
+func (p *TestProvider) BuildExport(app, id string, w io.Writer) error {
+	p.Called(app, id, w)
+	return nil
+}
+
 // BuildGet gets a Build
 func (p *TestProvider) BuildGet(app, id string) (*structs.Build, error) {
 	p.Called(app, id)
 	return &p.Build, nil
 }
 
+// BuildImport imports a Build
+func (p *TestProvider) BuildImport(app string, r io.Reader) (*structs.Build, error) {
+	p.Called(app, r)
+	return &p.Build, nil
+}
+
 // BuildLogs gets a Build's logs
 func (p *TestProvider) BuildLogs(app, id string) (string, error) {
 	p.Called(app, id)
-	return "", nil
+	return p.Build.Logs, nil
 }
 
 // BuildList lists the Builds
@@ -84,6 +115,12 @@ func (p *TestProvider) BuildList(app string, limit int64) (structs.Builds, error
 	return p.Builds, nil
 }
 
+// BuildPrune prunes the Builds beyond the newest retain
+func (p *TestProvider) BuildPrune(app string, retain int, keep []string) (structs.Builds, error) {
+	p.Called(app, retain, keep)
+	return p.Builds, nil
+}
+
 // BuildRelease gets the Release for a Build
 func (p *TestProvider) BuildRelease(b *structs.Build) (*structs.Release, error) {
 	p.Called(b)
@@ -189,12 +226,41 @@ func (p *TestProvider) IndexUpload(hash string, data []byte) error {
 	return nil
 }
 
+// InstanceKeyroll replaces the keypair of the rack instances
+func (p *TestProvider) InstanceKeyroll() error {
+	args := p.Called()
+	return args.Error(0)
+}
+
 // InstanceList lists the Instances
 func (p *TestProvider) InstanceList() (structs.Instances, error) {
 	p.Called()
 	return p.Instances, nil
 }
 
+// InstanceTerminate terminates an Instance
+func (p *TestProvider) InstanceTerminate(id string) error {
+	args := p.Called(id)
+	return args.Error(0)
+}
+
+// LogScrubList lists the log scrub rules of an App
+func (p *TestProvider) LogScrubList(app string) (structs.LogScrubRules, error) {
+	args := p.Called(app)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(structs.LogScrubRules), args.Error(1)
+}
+
+// LogScrubSave saves the log scrub rules of an App
+func (p *TestProvider) LogScrubSave(app string, rules structs.LogScrubRules) error {
+	args := p.Called(app, rules)
+	return args.Error(0)
+}
+
 // LogStream streams the Logs
 func (p *TestProvider) LogStream(app string, w io.Writer, opts structs.LogStreamOptions) error {
 	p.Called(app, w, opts)
@@ -207,6 +273,17 @@ func (p *TestProvider) ReleaseDelete(app, buildID string) error {
 	return nil
 }
 
+// ReleaseDiff compares two Releases
+func (p *TestProvider) ReleaseDiff(app, from, to string) (*structs.ReleaseDiff, error) {
+	args := p.Called(app, from, to)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*structs.ReleaseDiff), args.Error(1)
+}
+
 // ReleaseGet gets a Release
 func (p *TestProvider) ReleaseGet(app, id string) (*structs.Release, error) {
 	p.Called(app, id)
@@ -278,6 +355,17 @@ func (p *TestProvider) ServiceUpdate(name string, params map[string]string) (*st
 	return &p.Service, nil
 }
 
+// SystemCheck runs pre-flight checks for a System update
+func (p *TestProvider) SystemCheck(version string) (structs.SystemChecks, error) {
+	args := p.Called(version)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(structs.SystemChecks), args.Error(1)
+}
+
 // SystemGet gets the System
 func (p *TestProvider) SystemGet() (*structs.System, error) {
 	args := p.Called()
@@ -289,6 +377,17 @@ func (p *TestProvider) SystemGet() (*structs.System, error) {
 	return args.Get(0).(*structs.System), args.Error(1)
 }
 
+// SystemHealth checks the health of the System
+func (p *TestProvider) SystemHealth() (structs.SystemChecks, error) {
+	args := p.Called()
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(structs.SystemChecks), args.Error(1)
+}
+
 // SystemLogs streams logs from the System
 func (p *TestProvider) SystemLogs(w io.Writer, opts structs.LogStreamOptions) error {
 	args := p.Called(w, opts)
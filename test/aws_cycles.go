@@ -77,6 +77,40 @@ func GetAppTemplateCycle(stackName string) awsutil.Cycle {
 	}
 }
 
+func GetTemplateSummaryCycle(stackName string) awsutil.Cycle {
+	return awsutil.Cycle{
+		awsutil.Request{"/", "", `Action=GetTemplateSummary&StackName=` + stackName + `&Version=2010-05-15`},
+		awsutil.Response{200, `
+<GetTemplateSummaryResult>
+	<Parameters>
+		<member>
+			<ParameterKey>Internal</ParameterKey>
+			<ParameterType>String</ParameterType>
+			<DefaultValue>No</DefaultValue>
+			<Description>Only allow access to this app from inside the VPC</Description>
+			<ParameterConstraints>
+				<AllowedValues>
+					<member>Yes</member>
+					<member>No</member>
+				</AllowedValues>
+			</ParameterConstraints>
+		</member>
+		<member>
+			<ParameterKey>Cluster</ParameterKey>
+			<ParameterType>String</ParameterType>
+			<ParameterConstraints/>
+		</member>
+		<member>
+			<ParameterKey>Key</ParameterKey>
+			<ParameterType>String</ParameterType>
+			<NoEcho>true</NoEcho>
+			<ParameterConstraints/>
+		</member>
+	</Parameters>
+</GetTemplateSummaryResult>`},
+	}
+}
+
 // returns the stack you asked for with a status
 func DescribeAppStatusStackCycle(stackName string, status string) awsutil.Cycle {
 	return awsutil.Cycle{
@@ -133,6 +167,13 @@ func DeleteInstanceCycle(instance string) awsutil.Cycle {
 	}
 }
 
+func CancelUpdateStackCycle(stackName string) awsutil.Cycle {
+	return awsutil.Cycle{
+		awsutil.Request{"/", "", `Action=CancelUpdateStack&StackName=` + stackName + `&Version=2010-05-15`},
+		awsutil.Response{200, ""},
+	}
+}
+
 func DeleteStackCycle(stackName string) awsutil.Cycle {
 	return awsutil.Cycle{
 		awsutil.Request{"/", "", `Action=DeleteStack&StackName=` + stackName + `&Version=2010-05-15`},
@@ -191,6 +232,39 @@ func GetItemAppReleaseCycle(appName string) awsutil.Cycle {
 	}
 }
 
+// take or renew a lock in the convox-locks table, failing the condition if another owner holds it
+func AcquireLockCycle(name, owner string, held bool) awsutil.Cycle {
+	return lockCycle("PutItem", `/"ConditionExpression":"attribute_not_exists\(#name\) OR #owner = :owner OR #expires < :now",.*":owner":\{"S":"`+owner+`"\}\},"Item":\{"expires":\{"N":"\d+"\},"name":\{"S":"`+name+`"\},"owner":\{"S":"`+owner+`"\}\},"TableName":"convox-locks"/`, held)
+}
+
+// give up a lock in the convox-locks table, failing the condition if another owner holds it
+func ReleaseLockCycle(name, owner string, held bool) awsutil.Cycle {
+	return lockCycle("DeleteItem", `/"ConditionExpression":"#owner = :owner",.*":owner":\{"S":"`+owner+`"\}\},"Key":\{"name":\{"S":"`+name+`"\}\},"TableName":"convox-locks"/`, held)
+}
+
+func lockCycle(operation, body string, held bool) awsutil.Cycle {
+	c := awsutil.Cycle{
+		Request: awsutil.Request{
+			RequestURI: "/",
+			Operation:  "DynamoDB_20120810." + operation,
+			Body:       body,
+		},
+		Response: awsutil.Response{
+			StatusCode: 200,
+			Body:       `{}`,
+		},
+	}
+
+	if held {
+		c.Response = awsutil.Response{
+			StatusCode: 400,
+			Body:       `{"__type":"com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException","message":"The conditional request failed"}`,
+		}
+	}
+
+	return c
+}
+
 func UpdateAppStackCycle(stackName, count, memory string) awsutil.Cycle {
 	const countPreviousValue string = "1"
 	const memoryPreviousValue string = "256"
@@ -46,6 +46,12 @@ func (er ExecRun) Test(t *testing.T) {
 	}
 }
 
+// Output runs the command and returns what it printed along with its exit code
+func (er ExecRun) Output() (string, string, int) {
+	stdout, stderr, code, _ := er.exec()
+	return stdout, stderr, code
+}
+
 func (er ExecRun) exec() (string, string, int, error) {
 	cmd := exec.Command("sh", "-c", er.Command)
 
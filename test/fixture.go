@@ -0,0 +1,164 @@
+package test
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+)
+
+// Fixture is a recorded CLI session: the commands that were run, what they printed,
+// and the API requests they made along with the responses they got
+type Fixture struct {
+	Runs     []FixtureRun     `json:"runs"`
+	Requests []FixtureRequest `json:"requests"`
+}
+
+// FixtureRun is a single command and its expected output
+type FixtureRun struct {
+	Command string `json:"command"`
+	Stdin   string `json:"stdin,omitempty"`
+	Exit    int    `json:"exit"`
+	Stdout  string `json:"stdout"`
+	Stderr  string `json:"stderr,omitempty"`
+}
+
+// FixtureRequest is an API request and the response the rack gave to it
+type FixtureRequest struct {
+	Method   string          `json:"method"`
+	Path     string          `json:"path"`
+	Body     string          `json:"body,omitempty"`
+	Code     int             `json:"code"`
+	Response json.RawMessage `json:"response"`
+}
+
+// LoadFixture reads a fixture from a JSON file
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var f Fixture
+
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+// Save writes the fixture to a JSON file
+func (f *Fixture) Save(path string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Stubs returns the recorded requests as stubs for Server
+func (f *Fixture) Stubs() []Http {
+	stubs := make([]Http, len(f.Requests))
+
+	for i, r := range f.Requests {
+		stubs[i] = Http{Method: r.Method, Path: r.Path, Body: r.Body, Code: r.Code, Response: r.Response}
+	}
+
+	return stubs
+}
+
+// ExecRuns returns the recorded commands as runs for Runs
+func (f *Fixture) ExecRuns() []ExecRun {
+	runs := make([]ExecRun, len(f.Runs))
+
+	for i, r := range f.Runs {
+		runs[i] = ExecRun{Command: r.Command, Stdin: r.Stdin, Exit: r.Exit, Stdout: r.Stdout, Stderr: r.Stderr}
+	}
+
+	return runs
+}
+
+// Recorder proxies requests to a live rack and keeps each exchange for a Fixture.
+// Streaming responses are passed through as they arrive and recorded whole once they end.
+type Recorder struct {
+	sync.Mutex
+
+	Requests []FixtureRequest
+
+	proxy *httputil.ReverseProxy
+}
+
+// NewRecorder returns a Recorder that forwards to the rack API at host
+func NewRecorder(host string) *Recorder {
+	rec := &Recorder{}
+
+	rec.proxy = httputil.NewSingleHostReverseProxy(&url.URL{Scheme: "https", Host: host})
+	rec.proxy.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+
+	return rec
+}
+
+func (rec *Recorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(503)
+		w.Write(serverError(err.Error()))
+		return
+	}
+
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	r.Host = ""
+
+	cw := &captureWriter{ResponseWriter: w, code: 200}
+
+	rec.proxy.ServeHTTP(cw, r)
+
+	response := cw.body.Bytes()
+
+	var v interface{}
+
+	if err := json.Unmarshal(response, &v); err != nil {
+		response, _ = json.Marshal(string(response))
+	}
+
+	rec.Lock()
+	defer rec.Unlock()
+
+	rec.Requests = append(rec.Requests, FixtureRequest{
+		Method:   r.Method,
+		Path:     r.URL.RequestURI(),
+		Body:     string(body),
+		Code:     cw.code,
+		Response: json.RawMessage(bytes.TrimSpace(response)),
+	})
+}
+
+type captureWriter struct {
+	http.ResponseWriter
+
+	body bytes.Buffer
+	code int
+}
+
+func (cw *captureWriter) Write(data []byte) (int, error) {
+	cw.body.Write(data)
+	return cw.ResponseWriter.Write(data)
+}
+
+// Flush passes streaming responses through to the client as they arrive
+func (cw *captureWriter) Flush() {
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (cw *captureWriter) WriteHeader(code int) {
+	cw.code = code
+	cw.ResponseWriter.WriteHeader(code)
+}
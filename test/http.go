@@ -16,6 +16,9 @@ type Http struct {
 	Code     int
 	Body     string
 	Response interface{}
+
+	// Headers are added to the response
+	Headers map[string]string
 }
 
 var HandlerFunc http.HandlerFunc
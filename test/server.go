@@ -17,7 +17,8 @@ func Server(t *testing.T, stubs ...Http) *httptest.Server {
 		found := false
 
 		for _, stub := range stubs {
-			if stub.Method == r.Method && stub.Path == r.URL.Path {
+			// recorded fixtures keep the query string in the path
+			if stub.Method == r.Method && (stub.Path == r.URL.Path || stub.Path == r.URL.RequestURI()) {
 				data, err := json.Marshal(stub.Response)
 
 				if err != nil {
@@ -34,6 +35,10 @@ func Server(t *testing.T, stubs ...Http) *httptest.Server {
 
 				assert.Equal(t, string(rb), stub.Body)
 
+				for key, value := range stub.Headers {
+					w.Header().Set(key, value)
+				}
+
 				w.WriteHeader(stub.Code)
 				w.Write(data)
 